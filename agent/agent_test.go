@@ -0,0 +1,245 @@
+package agent
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func startTestAgent(t *testing.T, token string, runTerragrunt func(opts *options.TerragruntOptions) error) *Client {
+	return startTestAgentWithTLS(t, token, nil, nil, runTerragrunt)
+}
+
+func startTestAgentWithTLS(t *testing.T, token string, serverTLS *tls.Config, clientTLS *tls.Config, runTerragrunt func(opts *options.TerragruntOptions) error) *Client {
+	rootDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "app"), os.ModePerm))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.RunTerragrunt = runTerragrunt
+
+	listener := bufconn.Listen(1024 * 1024)
+	server := NewServer(opts, token, serverTLS)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	client, err := Dial([]string{"bufnet"}, token, clientTLS, grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return listener.DialContext(ctx)
+	}))
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+
+	return client
+}
+
+func TestRunModuleStreamsOutput(t *testing.T) {
+	t.Parallel()
+
+	client := startTestAgent(t, "", func(opts *options.TerragruntOptions) error {
+		fmt.Fprintf(opts.Writer, "%s in %s\n", strings.Join(opts.TerraformCliArgs, " "), filepath.Base(opts.WorkingDir))
+		fmt.Fprintln(opts.ErrWriter, "some warning")
+		return nil
+	})
+
+	var stdout, stderr bytes.Buffer
+	err := client.RunModule(context.Background(), "app", []string{"apply", "-auto-approve"}, &stdout, &stderr)
+	require.NoError(t, err)
+
+	assert.Equal(t, "apply -auto-approve in app\n", stdout.String())
+	assert.Equal(t, "some warning\n", stderr.String())
+}
+
+func TestRunModuleReturnsRemoteError(t *testing.T) {
+	t.Parallel()
+
+	client := startTestAgent(t, "", func(opts *options.TerragruntOptions) error {
+		return fmt.Errorf("apply failed")
+	})
+
+	var stdout, stderr bytes.Buffer
+	err := client.RunModule(context.Background(), "app", []string{"apply"}, &stdout, &stderr)
+	require.Error(t, err)
+
+	remoteErr, isRemoteErr := errors.Unwrap(err).(RemoteModuleError)
+	require.True(t, isRemoteErr, "Expected a RemoteModuleError, but got: %v", err)
+	assert.Equal(t, "app", remoteErr.ModulePath)
+	assert.Equal(t, "apply failed", remoteErr.Message)
+	assert.Equal(t, 1, remoteErr.ExitCode)
+}
+
+func TestRunModuleRejectsPathsOutsideRoot(t *testing.T) {
+	t.Parallel()
+
+	ran := false
+	client := startTestAgent(t, "", func(opts *options.TerragruntOptions) error {
+		ran = true
+		return nil
+	})
+
+	for _, modulePath := range []string{"../outside", "/etc", "missing"} {
+		var stdout, stderr bytes.Buffer
+		err := client.RunModule(context.Background(), modulePath, []string{"apply"}, &stdout, &stderr)
+		assert.Error(t, err, modulePath)
+	}
+	assert.False(t, ran)
+}
+
+func TestRunModuleRequiresToken(t *testing.T) {
+	t.Parallel()
+
+	client := startTestAgent(t, "secret", func(opts *options.TerragruntOptions) error { return nil })
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, client.RunModule(context.Background(), "app", []string{"plan"}, &stdout, &stderr))
+
+	client.token = "wrong"
+	err := client.RunModule(context.Background(), "app", []string{"plan"}, &stdout, &stderr)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid or missing agent token")
+}
+
+func TestRunModuleOverMutualTLS(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	caCert, caKey := writeTestCertificate(t, dir, "ca", nil, nil)
+	writeTestCertificate(t, dir, "agent", caCert, caKey)
+	writeTestCertificate(t, dir, "scheduler", caCert, caKey)
+
+	serverTLS, err := ServerTLSConfig(filepath.Join(dir, "agent.crt"), filepath.Join(dir, "agent.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+	clientTLS, err := ClientTLSConfig(filepath.Join(dir, "scheduler.crt"), filepath.Join(dir, "scheduler.key"), filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+
+	client := startTestAgentWithTLS(t, "secret", serverTLS, clientTLS, func(opts *options.TerragruntOptions) error {
+		fmt.Fprintln(opts.Writer, "applied")
+		return nil
+	})
+
+	var stdout, stderr bytes.Buffer
+	require.NoError(t, client.RunModule(context.Background(), "app", []string{"apply"}, &stdout, &stderr))
+	assert.Equal(t, "applied\n", stdout.String())
+
+	// A scheduler without a client certificate signed by the CA is rejected.
+	clientTLS, err = ClientTLSConfig("", "", filepath.Join(dir, "ca.crt"))
+	require.NoError(t, err)
+	client = startTestAgentWithTLS(t, "secret", serverTLS, clientTLS, func(opts *options.TerragruntOptions) error { return nil })
+	require.Error(t, client.RunModule(context.Background(), "app", []string{"apply"}, &stdout, &stderr))
+}
+
+func TestCheckTokenTransport(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		address   string
+		token     string
+		tlsConfig *tls.Config
+		expectErr bool
+	}{
+		{"127.0.0.1:7070", "secret", nil, false},
+		{"localhost:7070", "secret", nil, false},
+		{"[::1]:7070", "secret", nil, false},
+		{"agent-1:7070", "", nil, false},
+		{"agent-1:7070", "secret", &tls.Config{}, false},
+		{"agent-1:7070", "secret", nil, true},
+		{":7070", "secret", nil, true},
+		{"0.0.0.0:7070", "secret", nil, true},
+	}
+
+	for _, testCase := range testCases {
+		err := CheckTokenTransport(testCase.address, testCase.token, testCase.tlsConfig)
+		if testCase.expectErr {
+			assert.Error(t, err, testCase.address)
+		} else {
+			assert.NoError(t, err, testCase.address)
+		}
+	}
+}
+
+func TestCheckServerAuthentication(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		address   string
+		token     string
+		tlsConfig *tls.Config
+		expectErr bool
+	}{
+		{"127.0.0.1:7070", "", nil, false},
+		{"localhost:7070", "", nil, false},
+		{"agent-1:7070", "secret", nil, false},
+		{"agent-1:7070", "", &tls.Config{ClientCAs: x509.NewCertPool()}, false},
+		{"agent-1:7070", "", &tls.Config{}, true},
+		{"agent-1:7070", "", nil, true},
+		{":7070", "", nil, true},
+		{"0.0.0.0:7070", "", nil, true},
+	}
+
+	for _, testCase := range testCases {
+		err := CheckServerAuthentication(testCase.address, testCase.token, testCase.tlsConfig)
+		if testCase.expectErr {
+			assert.Error(t, err, testCase.address)
+		} else {
+			assert.NoError(t, err, testCase.address)
+		}
+	}
+}
+
+// writeTestCertificate writes the certificate and key of the given name to dir, signed by the given CA, or self-signed
+// as a CA if there is none. The certificates are valid for bufnet, the address the agent is dialed at over bufconn.
+func writeTestCertificate(t *testing.T, dir string, name string, caCert *x509.Certificate, caKey *ecdsa.PrivateKey) (*x509.Certificate, *ecdsa.PrivateKey) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		DNSNames:     []string{"bufnet"},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+	parent, signer := template, key
+	if caCert == nil {
+		template.IsCA = true
+		template.BasicConstraintsValid = true
+		template.KeyUsage |= x509.KeyUsageCertSign
+	} else {
+		parent, signer = caCert, caKey
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".crt"), pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, name+".key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+
+	cert, err := x509.ParseCertificate(der)
+	require.NoError(t, err)
+	return cert, key
+}
@@ -0,0 +1,101 @@
+package agent
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"sync/atomic"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// Client dispatches modules to a pool of agents. Modules are spread over the agents in a round-robin fashion; the
+// ordering between modules is enforced by the run-all scheduler, which only dispatches a module once all of its
+// dependencies finished.
+type Client struct {
+	addresses []string
+	conns     []*grpc.ClientConn
+	token     string
+	next      uint32
+}
+
+// Dial connects to the agents at the given addresses, over TLS with the given config, or in plaintext if it is nil. The
+// connections are established lazily, on the first request.
+func Dial(addresses []string, token string, tlsConfig *tls.Config, dialOpts ...grpc.DialOption) (*Client, error) {
+	if len(addresses) == 0 {
+		return nil, errors.WithStackTrace(NoAgentsConfigured{})
+	}
+
+	transportCreds := insecure.NewCredentials()
+	if tlsConfig != nil {
+		transportCreds = credentials.NewTLS(tlsConfig)
+	}
+	dialOpts = append([]grpc.DialOption{grpc.WithTransportCredentials(transportCreds)}, dialOpts...)
+
+	client := &Client{addresses: addresses, token: token}
+	for _, address := range addresses {
+		conn, err := grpc.Dial(address, dialOpts...)
+		if err != nil {
+			client.Close()
+			return nil, errors.WithStackTrace(err)
+		}
+		client.conns = append(client.conns, conn)
+	}
+	return client, nil
+}
+
+// Close closes the connections to all the agents.
+func (client *Client) Close() error {
+	var closeErr error
+	for _, conn := range client.conns {
+		if err := conn.Close(); err != nil && closeErr == nil {
+			closeErr = errors.WithStackTrace(err)
+		}
+	}
+	return closeErr
+}
+
+// RunModule runs the given terraform args in the module at modulePath, relative to the root directory of the agent,
+// and copies the output of the module to the given writers. It returns once the module finished running.
+func (client *Client) RunModule(ctx context.Context, modulePath string, terraformCliArgs []string, stdout io.Writer, stderr io.Writer) error {
+	index := int(atomic.AddUint32(&client.next, 1)-1) % len(client.conns)
+	address := client.addresses[index]
+
+	if client.token != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, tokenMetadataKey, client.token)
+	}
+
+	stream, err := newRunModuleStream(ctx, client.conns[index], &RunModuleRequest{ModulePath: modulePath, TerraformCliArgs: terraformCliArgs})
+	if err != nil {
+		return errors.WithStackTrace(AgentRequestFailed{Agent: address, ModulePath: modulePath, Err: err})
+	}
+
+	for {
+		event := &RunModuleEvent{}
+		if err := stream.RecvMsg(event); err != nil {
+			if err == io.EOF {
+				err = io.ErrUnexpectedEOF
+			}
+			return errors.WithStackTrace(AgentRequestFailed{Agent: address, ModulePath: modulePath, Err: err})
+		}
+
+		if event.Done {
+			if event.Error != "" {
+				return errors.WithStackTrace(RemoteModuleError{Agent: address, ModulePath: modulePath, Message: event.Error, ExitCode: event.ExitCode})
+			}
+			return nil
+		}
+
+		writer := stdout
+		if event.Stream == StreamStderr {
+			writer = stderr
+		}
+		if _, err := writer.Write(event.Data); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+}
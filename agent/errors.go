@@ -0,0 +1,78 @@
+package agent
+
+import "fmt"
+
+// Custom error types
+
+type NoAgentsConfigured struct{}
+
+func (err NoAgentsConfigured) Error() string {
+	return "No agent addresses were configured."
+}
+
+type ModulePathOutsideRoot struct {
+	ModulePath string
+	RootDir    string
+}
+
+func (err ModulePathOutsideRoot) Error() string {
+	return fmt.Sprintf("Module path %s is outside of the agent root directory %s.", err.ModulePath, err.RootDir)
+}
+
+type ModuleNotFound struct {
+	ModulePath string
+	RootDir    string
+}
+
+func (err ModuleNotFound) Error() string {
+	return fmt.Sprintf("Module %s does not exist in the agent root directory %s.", err.ModulePath, err.RootDir)
+}
+
+type AgentRequestFailed struct {
+	Agent      string
+	ModulePath string
+	Err        error
+}
+
+func (err AgentRequestFailed) Error() string {
+	return fmt.Sprintf("Failed to run module %s on agent %s: %v", err.ModulePath, err.Agent, err.Err)
+}
+
+type RemoteModuleError struct {
+	Agent      string
+	ModulePath string
+	Message    string
+	ExitCode   int
+}
+
+func (err RemoteModuleError) Error() string {
+	return fmt.Sprintf("Module %s failed on agent %s: %s", err.ModulePath, err.Agent, err.Message)
+}
+
+func (err RemoteModuleError) ExitStatus() (int, error) {
+	return err.ExitCode, nil
+}
+
+type MissingTLSCertificate struct{}
+
+func (err MissingTLSCertificate) Error() string {
+	return "A TLS certificate and key are required to verify the client certificates with a CA."
+}
+
+type InvalidTLSCA string
+
+func (caFile InvalidTLSCA) Error() string {
+	return fmt.Sprintf("No PEM encoded certificate was found in the CA file %s.", string(caFile))
+}
+
+type TokenWithoutTLS string
+
+func (address TokenWithoutTLS) Error() string {
+	return fmt.Sprintf("Refusing to send the agent token in plaintext to %s, which is not a loopback address. Configure TLS with --terragrunt-agent-tls-cert and --terragrunt-agent-tls-key on the agent, and --terragrunt-agent-tls-ca on the scheduler.", string(address))
+}
+
+type UnauthenticatedAgent string
+
+func (address UnauthenticatedAgent) Error() string {
+	return fmt.Sprintf("Refusing to listen on %s, which is not a loopback address, without authenticating the schedulers. Set --terragrunt-agent-token, or verify the client certificates with --terragrunt-agent-tls-ca.", string(address))
+}
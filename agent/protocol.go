@@ -0,0 +1,84 @@
+package agent
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+const (
+	serviceName         = "terragrunt.agent.v1.Agent"
+	runModuleMethod     = "RunModule"
+	runModuleFullMethod = "/" + serviceName + "/" + runModuleMethod
+
+	// tokenMetadataKey is the gRPC metadata key that carries the shared token used to authenticate the scheduler.
+	tokenMetadataKey = "x-terragrunt-agent-token"
+
+	StreamStdout = "stdout"
+	StreamStderr = "stderr"
+)
+
+// RunModuleRequest is a work item sent by the scheduler to an agent: run terragrunt with the given terraform args in
+// the module at the given path.
+type RunModuleRequest struct {
+	// ModulePath is the path of the module relative to the root directory of the agent.
+	ModulePath string `json:"module_path"`
+
+	// TerraformCliArgs are the args to run terraform with, e.g. ["apply", "-input=false", "-auto-approve"].
+	TerraformCliArgs []string `json:"terraform_cli_args"`
+}
+
+// RunModuleEvent is streamed back by the agent while it runs a module. Events carry either a chunk of output, or the
+// final status of the run when Done is set.
+type RunModuleEvent struct {
+	Stream string `json:"stream,omitempty"`
+	Data   []byte `json:"data,omitempty"`
+
+	Done     bool   `json:"done,omitempty"`
+	Error    string `json:"error,omitempty"`
+	ExitCode int    `json:"exit_code,omitempty"`
+}
+
+// agentService is the interface implemented by the agent server. It only exists to describe the service to gRPC.
+type agentService interface {
+	RunModule(request *RunModuleRequest, stream grpc.ServerStream) error
+}
+
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: serviceName,
+	HandlerType: (*agentService)(nil),
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    runModuleMethod,
+			Handler:       runModuleHandler,
+			ServerStreams: true,
+		},
+	},
+}
+
+func runModuleHandler(srv interface{}, stream grpc.ServerStream) error {
+	request := &RunModuleRequest{}
+	if err := stream.RecvMsg(request); err != nil {
+		return err
+	}
+	return srv.(agentService).RunModule(request, stream)
+}
+
+var runModuleStreamDesc = &grpc.StreamDesc{
+	StreamName:    runModuleMethod,
+	ServerStreams: true,
+}
+
+func newRunModuleStream(ctx context.Context, conn *grpc.ClientConn, request *RunModuleRequest) (grpc.ClientStream, error) {
+	stream, err := conn.NewStream(ctx, runModuleStreamDesc, runModuleFullMethod, grpc.CallContentSubtype(codecName))
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.SendMsg(request); err != nil {
+		return nil, err
+	}
+	if err := stream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
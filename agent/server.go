@@ -0,0 +1,167 @@
+package agent
+
+import (
+	"crypto/subtle"
+	"crypto/tls"
+	"net"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Server runs the modules dispatched by a run-all scheduler. Every module is resolved relative to the root directory
+// of the agent, which is expected to be a checkout of the same code as the scheduler's working directory.
+type Server struct {
+	opts       *options.TerragruntOptions
+	rootDir    string
+	token      string
+	grpcServer *grpc.Server
+}
+
+// NewServer creates an agent server that runs modules in the working directory of the given options. If token is not
+// empty, the scheduler has to present the same token with every request. The server serves over TLS with the given
+// config, or in plaintext if it is nil.
+func NewServer(opts *options.TerragruntOptions, token string, tlsConfig *tls.Config) *Server {
+	server := &Server{
+		opts:    opts,
+		rootDir: opts.WorkingDir,
+		token:   token,
+	}
+	var serverOpts []grpc.ServerOption
+	if tlsConfig != nil {
+		serverOpts = append(serverOpts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	server.grpcServer = grpc.NewServer(serverOpts...)
+	server.grpcServer.RegisterService(&serviceDesc, server)
+	return server
+}
+
+// Serve accepts connections on the given listener until Stop is called.
+func (server *Server) Serve(listener net.Listener) error {
+	server.opts.Logger.Infof("Terragrunt agent listening on %s, running modules in %s", listener.Addr(), server.rootDir)
+	return errors.WithStackTrace(server.grpcServer.Serve(listener))
+}
+
+// Stop waits for the running modules to finish and stops the server.
+func (server *Server) Stop() {
+	server.grpcServer.GracefulStop()
+}
+
+// RunModule runs a single module and streams its output and final status back to the scheduler.
+func (server *Server) RunModule(request *RunModuleRequest, stream grpc.ServerStream) error {
+	if err := server.authenticate(stream); err != nil {
+		return err
+	}
+
+	modulePath, err := server.resolveModulePath(request.ModulePath)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	moduleOpts := server.opts.Clone(config.GetDefaultConfigPath(modulePath))
+	if err := server.setDownloadDir(moduleOpts); err != nil {
+		return status.Error(codes.Internal, err.Error())
+	}
+	moduleOpts.TerraformCliArgs = request.TerraformCliArgs
+	moduleOpts.TerraformCommand = util.FirstArg(request.TerraformCliArgs)
+	moduleOpts.OriginalTerraformCommand = moduleOpts.TerraformCommand
+
+	sender := &eventSender{stream: stream}
+	moduleOpts.Writer = &streamWriter{sender: sender, stream: StreamStdout}
+	moduleOpts.ErrWriter = &streamWriter{sender: sender, stream: StreamStderr}
+
+	server.opts.Logger.Infof("Running %s in module %s", moduleOpts.TerraformCommand, modulePath)
+
+	done := &RunModuleEvent{Done: true}
+	if runErr := moduleOpts.RunTerragrunt(moduleOpts); runErr != nil {
+		server.opts.Logger.Errorf("Module %s finished with an error: %v", modulePath, runErr)
+		done.Error = runErr.Error()
+		done.ExitCode = 1
+		if exitCode, err := shell.GetExitCode(runErr); err == nil {
+			done.ExitCode = exitCode
+		}
+	}
+	return sender.send(done)
+}
+
+// setDownloadDir uses the default download dir of the module, unless a custom download dir was set for the agent.
+func (server *Server) setDownloadDir(moduleOpts *options.TerragruntOptions) error {
+	_, rootDownloadDir, err := options.DefaultWorkingAndDownloadDirs(server.opts.TerragruntConfigPath)
+	if err != nil {
+		return err
+	}
+	if server.opts.DownloadDir != rootDownloadDir {
+		return nil
+	}
+	_, moduleOpts.DownloadDir, err = options.DefaultWorkingAndDownloadDirs(moduleOpts.TerragruntConfigPath)
+	return err
+}
+
+func (server *Server) authenticate(stream grpc.ServerStream) error {
+	if server.token == "" {
+		return nil
+	}
+	md, _ := metadata.FromIncomingContext(stream.Context())
+	for _, token := range md.Get(tokenMetadataKey) {
+		if subtle.ConstantTimeCompare([]byte(token), []byte(server.token)) == 1 {
+			return nil
+		}
+	}
+	return status.Error(codes.Unauthenticated, "invalid or missing agent token")
+}
+
+// resolveModulePath converts the module path of a request to an absolute path, making sure that the scheduler can't
+// make the agent run code outside of its root directory.
+func (server *Server) resolveModulePath(relativePath string) (string, error) {
+	if filepath.IsAbs(relativePath) {
+		return "", errors.WithStackTrace(ModulePathOutsideRoot{ModulePath: relativePath, RootDir: server.rootDir})
+	}
+	modulePath := filepath.Join(server.rootDir, relativePath)
+	relToRoot, err := filepath.Rel(server.rootDir, modulePath)
+	if err != nil || relToRoot == ".." || strings.HasPrefix(relToRoot, ".."+string(filepath.Separator)) {
+		return "", errors.WithStackTrace(ModulePathOutsideRoot{ModulePath: relativePath, RootDir: server.rootDir})
+	}
+	if !util.IsDir(modulePath) {
+		return "", errors.WithStackTrace(ModuleNotFound{ModulePath: relativePath, RootDir: server.rootDir})
+	}
+	return filepath.ToSlash(modulePath), nil
+}
+
+// eventSender serializes the events sent on a stream, as gRPC streams don't support concurrent sends and terraform
+// writes to stdout and stderr concurrently.
+type eventSender struct {
+	mu     sync.Mutex
+	stream grpc.ServerStream
+}
+
+func (sender *eventSender) send(event *RunModuleEvent) error {
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
+	return sender.stream.SendMsg(event)
+}
+
+type streamWriter struct {
+	sender *eventSender
+	stream string
+}
+
+func (writer *streamWriter) Write(p []byte) (int, error) {
+	data := make([]byte, len(p))
+	copy(data, p)
+	if err := writer.sender.send(&RunModuleEvent{Stream: writer.stream, Data: data}); err != nil {
+		return 0, errors.WithStackTrace(err)
+	}
+	return len(p), nil
+}
@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"os"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// ServerTLSConfig returns the TLS config of an agent serving the given certificate and key. If caFile is set, the
+// agent also requires the schedulers to present a client certificate signed by that CA. It returns nil if no
+// certificate is set, in which case the agent serves in plaintext.
+func ServerTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" {
+		if caFile != "" {
+			return nil, errors.WithStackTrace(MissingTLSCertificate{})
+		}
+		return nil, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// ClientTLSConfig returns the TLS config of a scheduler connecting to the agents. The certificates of the agents are
+// verified against caFile if set, or against the CAs of the system otherwise, and the given certificate and key, if
+// set, are presented to the agents that require a client certificate. It returns nil if none of the files is set, in
+// which case the scheduler connects in plaintext.
+func ClientTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	if certFile == "" && keyFile == "" && caFile == "" {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	if caFile != "" {
+		pool, err := loadCertPool(caFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// CheckTokenTransport refuses to send or accept the given token in plaintext over the network: a token is only
+// allowed without TLS if the address is a loopback address.
+func CheckTokenTransport(address string, token string, tlsConfig *tls.Config) error {
+	if token == "" || tlsConfig != nil || isLoopbackAddress(address) {
+		return nil
+	}
+	return errors.WithStackTrace(TokenWithoutTLS(address))
+}
+
+// CheckServerAuthentication refuses to serve unauthenticated clients over the network: an agent listening on an
+// address that is not a loopback address must either require a token or verify the client certificates with a CA.
+func CheckServerAuthentication(address string, token string, tlsConfig *tls.Config) error {
+	if token != "" || (tlsConfig != nil && tlsConfig.ClientCAs != nil) || isLoopbackAddress(address) {
+		return nil
+	}
+	return errors.WithStackTrace(UnauthenticatedAgent(address))
+}
+
+// isLoopbackAddress returns true if the host of the given address only resolves to the loopback interface. An address
+// without a host, such as :7070, listens on all the interfaces.
+func isLoopbackAddress(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		host = address
+	}
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func loadCertPool(caFile string) (*x509.CertPool, error) {
+	pem, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(pem) {
+		return nil, errors.WithStackTrace(InvalidTLSCA(caFile))
+	}
+	return pool, nil
+}
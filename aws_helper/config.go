@@ -30,6 +30,9 @@ type AwsSessionConfig struct {
 	DisableComputeChecksums bool
 	ExternalID              string
 	SessionName             string
+	// Whether RoleArn is assumed with the credentials of the IAM role of terragrunt, as for the assume_role of
+	// remote_state, instead of replacing it
+	AssumeRoleWithIAMRole bool
 }
 
 // addUserAgent - Add terragrunt version to the user agent for AWS API calls.
@@ -83,8 +86,29 @@ func CreateAwsSessionFromConfig(config *AwsSessionConfig, terragruntOptions *opt
 
 	sess.Handlers.Build.PushFrontNamed(addUserAgent)
 
-	// Merge the config based IAMRole options into the original one, as the config has higher precedence than CLI.
+	credentialOptFn := func(p *stscreds.AssumeRoleProvider) {
+		if config.ExternalID != "" {
+			p.ExternalID = aws.String(config.ExternalID)
+		}
+	}
+
 	iamRoleOptions := terragruntOptions.IAMRoleOptions
+
+	// The role of the assume_role of remote_state is assumed with the credentials of the IAM role, the same way
+	// Terraform assumes it with the credentials of the IAM role terragrunt passes to it.
+	if config.AssumeRoleWithIAMRole && config.RoleArn != "" && config.RoleArn != iamRoleOptions.RoleARN {
+		if iamRoleOptions.RoleARN != "" {
+			sess.Config.Credentials = getSTSCredentialsFromIAMRoleOptions(sess, iamRoleOptions, terragruntOptions)
+		}
+		configRoleOptions := options.IAMRoleOptions{
+			RoleARN:               config.RoleArn,
+			AssumeRoleSessionName: config.SessionName,
+		}
+		sess.Config.Credentials = getSTSCredentialsFromIAMRoleOptions(sess.Copy(), configRoleOptions, terragruntOptions, credentialOptFn)
+		return sess, nil
+	}
+
+	// Merge the config based IAMRole options into the original one, as the config has higher precedence than CLI.
 	if config.RoleArn != "" {
 		iamRoleOptions = options.MergeIAMRoleOptions(
 			iamRoleOptions,
@@ -95,19 +119,21 @@ func CreateAwsSessionFromConfig(config *AwsSessionConfig, terragruntOptions *opt
 		)
 	}
 
-	credentialOptFn := func(p *stscreds.AssumeRoleProvider) {
-		if config.ExternalID != "" {
-			p.ExternalID = aws.String(config.ExternalID)
-		}
-	}
-
 	if iamRoleOptions.RoleARN != "" {
-		sess.Config.Credentials = getSTSCredentialsFromIAMRoleOptions(sess, iamRoleOptions, credentialOptFn)
+		sess.Config.Credentials = getSTSCredentialsFromIAMRoleOptions(sess, iamRoleOptions, terragruntOptions, credentialOptFn)
 	}
 	return sess, nil
 }
 
-func getSTSCredentialsFromIAMRoleOptions(sess *session.Session, iamRoleOptions options.IAMRoleOptions, optFns ...func(*stscreds.AssumeRoleProvider)) *credentials.Credentials {
+func getSTSCredentialsFromIAMRoleOptions(sess *session.Session, iamRoleOptions options.IAMRoleOptions, terragruntOptions *options.TerragruntOptions, optFns ...func(*stscreds.AssumeRoleProvider)) *credentials.Credentials {
+	if iamRoleOptions.WebIdentityToken != "" {
+		return getWebIdentityCredentials(sess, iamRoleOptions)
+	}
+	// The roles that require MFA are assumed once for all the sessions, so that the user is only prompted once.
+	if iamRoleOptions.MFASerial != "" {
+		return credentials.NewCredentials(&mfaSessionProvider{sess: sess, optFns: optFns, iamRoleOpts: iamRoleOptions, terragruntOptions: terragruntOptions})
+	}
+
 	optFns = append(optFns, func(p *stscreds.AssumeRoleProvider) {
 		if iamRoleOptions.AssumeRoleDuration > 0 {
 			p.Duration = time.Second * time.Duration(iamRoleOptions.AssumeRoleDuration)
@@ -140,7 +166,7 @@ func CreateAwsSession(config *AwsSessionConfig, terragruntOptions *options.Terra
 		sess.Handlers.Build.PushFrontNamed(addUserAgent)
 		if terragruntOptions.IAMRoleOptions.RoleARN != "" {
 			terragruntOptions.Logger.Debugf("Assuming role %s", terragruntOptions.IAMRoleOptions.RoleARN)
-			sess.Config.Credentials = getSTSCredentialsFromIAMRoleOptions(sess, terragruntOptions.IAMRoleOptions)
+			sess.Config.Credentials = getSTSCredentialsFromIAMRoleOptions(sess, terragruntOptions.IAMRoleOptions, terragruntOptions)
 		}
 	} else {
 		sess, err = CreateAwsSessionFromConfig(config, terragruntOptions)
@@ -163,6 +189,11 @@ func CreateAwsSession(config *AwsSessionConfig, terragruntOptions *options.Terra
 
 // Make API calls to AWS to assume the IAM role specified and return the temporary AWS credentials to use that role
 func AssumeIamRole(iamRoleOpts options.IAMRoleOptions) (*sts.Credentials, error) {
+	return assumeIamRole(iamRoleOpts, "")
+}
+
+// assumeIamRole assumes the given IAM role, with the given code of the MFA device of the role if not empty.
+func assumeIamRole(iamRoleOpts options.IAMRoleOptions, mfaTokenCode string) (*sts.Credentials, error) {
 	sessionOptions := session.Options{SharedConfigState: session.SharedConfigEnable}
 	sess, err := session.NewSessionWithOptions(sessionOptions)
 	if err != nil {
@@ -171,35 +202,64 @@ func AssumeIamRole(iamRoleOpts options.IAMRoleOptions) (*sts.Credentials, error)
 
 	sess.Handlers.Build.PushFrontNamed(addUserAgent)
 
-	_, err = sess.Config.Credentials.Get()
-	if err != nil {
-		return nil, errors.WithStackTraceAndPrefix(err, "Error finding AWS credentials (did you set the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables?)")
+	// The roles assumed with web identity federation don't require AWS credentials, only the token.
+	if iamRoleOpts.WebIdentityToken != "" {
+		return assumeIamRoleWithWebIdentity(sess, iamRoleOpts)
 	}
 
-	stsClient := sts.New(sess)
+	return assumeIamRoleWithSession(sess, iamRoleOpts, mfaTokenCode)
+}
 
-	sessionName := options.GetDefaultIAMAssumeRoleSessionName()
-	if iamRoleOpts.AssumeRoleSessionName != "" {
-		sessionName = iamRoleOpts.AssumeRoleSessionName
+// assumeIamRoleWithSession assumes the given IAM role with the credentials of the given session, such as the session
+// of a profile, and with the given code of the MFA device of the role if not empty. The given functions customize the
+// request the same way as for the credentials of stscreds.
+func assumeIamRoleWithSession(sess *session.Session, iamRoleOpts options.IAMRoleOptions, mfaTokenCode string, optFns ...func(*stscreds.AssumeRoleProvider)) (*sts.Credentials, error) {
+	_, err := sess.Config.Credentials.Get()
+	if err != nil {
+		return nil, errors.WithStackTraceAndPrefix(err, "Error finding AWS credentials (did you set the AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables?)")
 	}
 
-	sessionDurationSeconds := int64(options.DefaultIAMAssumeRoleDuration)
-	if iamRoleOpts.AssumeRoleDuration != 0 {
-		sessionDurationSeconds = iamRoleOpts.AssumeRoleDuration
+	provider := &stscreds.AssumeRoleProvider{
+		Client:          sts.New(sess),
+		RoleARN:         iamRoleOpts.RoleARN,
+		RoleSessionName: iamRoleSessionName(iamRoleOpts),
+		Duration:        time.Second * time.Duration(iamRoleDuration(iamRoleOpts)),
 	}
-
-	input := sts.AssumeRoleInput{
-		RoleArn:         aws.String(iamRoleOpts.RoleARN),
-		RoleSessionName: aws.String(sessionName),
-		DurationSeconds: aws.Int64(sessionDurationSeconds),
+	if mfaTokenCode != "" {
+		provider.SerialNumber = aws.String(iamRoleOpts.MFASerial)
+		provider.TokenCode = aws.String(mfaTokenCode)
+	}
+	for _, optFn := range optFns {
+		optFn(provider)
 	}
 
-	output, err := stsClient.AssumeRole(&input)
+	value, err := provider.Retrieve()
 	if err != nil {
 		return nil, errors.WithStackTrace(err)
 	}
 
-	return output.Credentials, nil
+	return &sts.Credentials{
+		AccessKeyId:     aws.String(value.AccessKeyID),
+		SecretAccessKey: aws.String(value.SecretAccessKey),
+		SessionToken:    aws.String(value.SessionToken),
+		Expiration:      aws.Time(provider.ExpiresAt()),
+	}, nil
+}
+
+// iamRoleSessionName returns the name of the STS session the given role is assumed with.
+func iamRoleSessionName(iamRoleOpts options.IAMRoleOptions) string {
+	if iamRoleOpts.AssumeRoleSessionName != "" {
+		return iamRoleOpts.AssumeRoleSessionName
+	}
+	return options.GetDefaultIAMAssumeRoleSessionName()
+}
+
+// iamRoleDuration returns the duration, in seconds, of the STS session the given role is assumed with.
+func iamRoleDuration(iamRoleOpts options.IAMRoleOptions) int64 {
+	if iamRoleOpts.AssumeRoleDuration != 0 {
+		return iamRoleOpts.AssumeRoleDuration
+	}
+	return int64(options.DefaultIAMAssumeRoleDuration)
 }
 
 // Return the AWS caller identity associated with the current set of credentials
@@ -277,7 +337,13 @@ func AssumeRoleAndUpdateEnvIfNecessary(terragruntOptions *options.TerragruntOpti
 	}
 
 	terragruntOptions.Logger.Debugf("Assuming IAM role %s with a session duration of %d seconds.", iamRoleOpts.RoleARN, iamRoleOpts.AssumeRoleDuration)
-	creds, err := AssumeIamRole(iamRoleOpts)
+	var creds *sts.Credentials
+	var err error
+	if iamRoleOpts.MFASerial != "" && iamRoleOpts.WebIdentityToken == "" {
+		creds, err = AssumeIamRoleWithMFA(iamRoleOpts, terragruntOptions)
+	} else {
+		creds, err = AssumeIamRole(iamRoleOpts)
+	}
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,242 @@
+package aws_helper
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// The sessions of the roles assumed with MFA are not reused when they expire within this window, so that they don't
+// expire while terraform runs.
+const mfaSessionExpiryWindow = 5 * time.Minute
+
+// The context the key the cached sessions are encrypted with is derived from the secret of the user with.
+const mfaSessionCacheKeyContext = "terragrunt mfa session cache"
+
+var (
+	// mfaSessions are the sessions of the roles assumed with MFA by this process, by mfaSessionKey, so that the user is
+	// prompted for the MFA code once for all the units of a run-all.
+	mfaSessions = map[string]*sts.Credentials{}
+	// mfaSessionsLock is held while a role is assumed with MFA, so that the units of a run-all running in parallel wait
+	// for the single prompt rather than prompting concurrently.
+	mfaSessionsLock sync.Mutex
+)
+
+// AssumeIamRoleWithMFA assumes the IAM role specified with the MFA device of the given options, and returns the
+// temporary AWS credentials to use that role. The user is prompted for the MFA code only if there is no valid session
+// of the role cached in memory, or in the cache folder of the user, which is shared by the terragrunt processes. The
+// sessions are only cached on disk when the user sets a secret to encrypt them with, since a key stored next to the
+// sessions would not protect them. The role is assumed with the default AWS credentials.
+func AssumeIamRoleWithMFA(iamRoleOpts options.IAMRoleOptions, terragruntOptions *options.TerragruntOptions) (*sts.Credentials, error) {
+	return assumeIamRoleWithMFA(nil, iamRoleOpts, terragruntOptions, mfaSessionCacheDir())
+}
+
+// assumeIamRoleWithMFA assumes the given role with the credentials of the given session, or with the default AWS
+// credentials if it is nil. The given functions customize the request, as with stscreds.
+func assumeIamRoleWithMFA(sess *session.Session, iamRoleOpts options.IAMRoleOptions, terragruntOptions *options.TerragruntOptions, cacheDir string, optFns ...func(*stscreds.AssumeRoleProvider)) (*sts.Credentials, error) {
+	mfaSessionsLock.Lock()
+	defer mfaSessionsLock.Unlock()
+
+	key := mfaSessionKey(iamRoleOpts)
+	if creds, found := mfaSessions[key]; found && isValidMFASession(creds) {
+		return creds, nil
+	}
+
+	secret := terragruntOptions.MFASessionCacheKey
+
+	creds, err := readMFASessionCache(cacheDir, secret, key)
+	if err != nil {
+		terragruntOptions.Logger.Debugf("Could not read the cached session of the IAM role %s: %v", iamRoleOpts.RoleARN, err)
+	}
+	if creds != nil && isValidMFASession(creds) {
+		terragruntOptions.Logger.Debugf("Using the cached session of the IAM role %s, which expires at %s", iamRoleOpts.RoleARN, aws.TimeValue(creds.Expiration))
+		mfaSessions[key] = creds
+		return creds, nil
+	}
+
+	if terragruntOptions.NonInteractive {
+		return nil, errors.WithStackTrace(MFACodeRequired{RoleARN: iamRoleOpts.RoleARN, MFASerial: iamRoleOpts.MFASerial})
+	}
+
+	prompt := fmt.Sprintf("Enter the MFA code of %s to assume the IAM role %s: ", iamRoleOpts.MFASerial, iamRoleOpts.RoleARN)
+	tokenCode, err := shell.PromptUserForInput(prompt, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if sess == nil {
+		creds, err = assumeIamRole(iamRoleOpts, tokenCode)
+	} else {
+		creds, err = assumeIamRoleWithSession(sess, iamRoleOpts, tokenCode, optFns...)
+	}
+	if err != nil {
+		return nil, err
+	}
+	mfaSessions[key] = creds
+
+	if secret == "" {
+		terragruntOptions.Logger.Debugf("Not caching the session of the IAM role %s on disk, as no MFA cache key is set", iamRoleOpts.RoleARN)
+	} else if err := writeMFASessionCache(cacheDir, secret, key, creds); err != nil {
+		terragruntOptions.Logger.Warnf("Failed to cache the session of the IAM role %s in %s: %v", iamRoleOpts.RoleARN, cacheDir, err)
+	}
+
+	return creds, nil
+}
+
+// mfaSessionProvider provides the credentials of the IAM role assumed with MFA to the AWS sessions terragrunt uses,
+// from the same cache as the credentials passed to terraform. The role is assumed with the credentials of the session
+// the provider is created for, such as the session of the profile of a remote_state block.
+type mfaSessionProvider struct {
+	credentials.Expiry
+
+	sess              *session.Session
+	optFns            []func(*stscreds.AssumeRoleProvider)
+	iamRoleOpts       options.IAMRoleOptions
+	terragruntOptions *options.TerragruntOptions
+}
+
+func (provider *mfaSessionProvider) Retrieve() (credentials.Value, error) {
+	creds, err := assumeIamRoleWithMFA(provider.sess, provider.iamRoleOpts, provider.terragruntOptions, mfaSessionCacheDir(), provider.optFns...)
+	if err != nil {
+		return credentials.Value{}, err
+	}
+	provider.SetExpiration(aws.TimeValue(creds.Expiration), mfaSessionExpiryWindow)
+
+	return credentials.Value{
+		AccessKeyID:     aws.StringValue(creds.AccessKeyId),
+		SecretAccessKey: aws.StringValue(creds.SecretAccessKey),
+		SessionToken:    aws.StringValue(creds.SessionToken),
+		ProviderName:    "TerragruntMFASessionProvider",
+	}, nil
+}
+
+// mfaSessionKey returns the key of the sessions of the given role, which are specific to the MFA device, the session
+// name and the duration the role is assumed with.
+func mfaSessionKey(iamRoleOpts options.IAMRoleOptions) string {
+	hash := sha256.Sum256([]byte(fmt.Sprintf("%s\n%s\n%s\n%d", iamRoleOpts.RoleARN, iamRoleOpts.MFASerial, iamRoleOpts.AssumeRoleSessionName, iamRoleOpts.AssumeRoleDuration)))
+	return hex.EncodeToString(hash[:])
+}
+
+func isValidMFASession(creds *sts.Credentials) bool {
+	return creds.Expiration != nil && time.Until(*creds.Expiration) > mfaSessionExpiryWindow
+}
+
+// mfaSessionCacheDir returns the folder where the sessions of the roles assumed with MFA are cached, in the cache folder
+// of the user.
+func mfaSessionCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "terragrunt", "mfa-sessions")
+}
+
+// readMFASessionCache returns the session cached with the given key in the given folder, or nil if there is none or
+// there is no secret to decrypt it with.
+func readMFASessionCache(cacheDir string, secret string, key string) (*sts.Credentials, error) {
+	if secret == "" {
+		return nil, nil
+	}
+
+	encrypted, err := os.ReadFile(filepath.Join(cacheDir, key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	gcm, err := mfaSessionCacheCipher(secret)
+	if err != nil {
+		return nil, err
+	}
+	if len(encrypted) < gcm.NonceSize() {
+		return nil, errors.WithStackTrace(InvalidMFASessionCache(key))
+	}
+	nonce, ciphertext := encrypted[:gcm.NonceSize()], encrypted[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return nil, errors.WithStackTrace(InvalidMFASessionCache(key))
+	}
+
+	var creds sts.Credentials
+	if err := json.Unmarshal(plaintext, &creds); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return &creds, nil
+}
+
+// writeMFASessionCache encrypts the given session with the given secret, and writes it to a file that is only readable
+// by the current user.
+func writeMFASessionCache(cacheDir string, secret string, key string, creds *sts.Credentials) error {
+	plaintext, err := json.Marshal(creds)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	gcm, err := mfaSessionCacheCipher(secret)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	encrypted := gcm.Seal(nonce, nonce, plaintext, []byte(key))
+	return errors.WithStackTrace(os.WriteFile(filepath.Join(cacheDir, key), encrypted, 0600))
+}
+
+// mfaSessionCacheCipher returns the cipher the cached sessions are encrypted with, whose key is derived from the given
+// secret of the user, which is never written to disk.
+func mfaSessionCacheCipher(secret string) (cipher.AEAD, error) {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(mfaSessionCacheKeyContext))
+
+	block, err := aes.NewCipher(mac.Sum(nil))
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	return gcm, errors.WithStackTrace(err)
+}
+
+// Custom error types
+
+type MFACodeRequired struct {
+	RoleARN   string
+	MFASerial string
+}
+
+func (err MFACodeRequired) Error() string {
+	return fmt.Sprintf("Assuming the IAM role %s requires the MFA code of %s, which can't be prompted for with --terragrunt-non-interactive. Run terragrunt interactively once to cache the session.", err.RoleARN, err.MFASerial)
+}
+
+type InvalidMFASessionCache string
+
+func (key InvalidMFASessionCache) Error() string {
+	return fmt.Sprintf("The cached session %s could not be decrypted.", string(key))
+}
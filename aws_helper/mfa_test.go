@@ -0,0 +1,145 @@
+package aws_helper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMFASessionCache(t *testing.T) {
+	t.Parallel()
+
+	cacheDir := t.TempDir()
+	key := mfaSessionKey(options.IAMRoleOptions{RoleARN: "arn:aws:iam::111111111111:role/cache", MFASerial: "arn:aws:iam::111111111111:mfa/user"})
+
+	creds, err := readMFASessionCache(cacheDir, "cache-secret", key)
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+
+	expiration := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	require.NoError(t, writeMFASessionCache(cacheDir, "cache-secret", key, &sts.Credentials{
+		AccessKeyId:     aws.String("access-key"),
+		SecretAccessKey: aws.String("secret-key"),
+		SessionToken:    aws.String("session-token"),
+		Expiration:      aws.Time(expiration),
+	}))
+
+	encrypted, err := os.ReadFile(filepath.Join(cacheDir, key))
+	require.NoError(t, err)
+	assert.NotContains(t, string(encrypted), "secret-key")
+
+	creds, err = readMFASessionCache(cacheDir, "cache-secret", key)
+	require.NoError(t, err)
+	assert.Equal(t, "secret-key", aws.StringValue(creds.SecretAccessKey))
+	assert.Equal(t, expiration, aws.TimeValue(creds.Expiration))
+
+	// The sessions encrypted with another secret are ignored.
+	_, err = readMFASessionCache(cacheDir, "other-secret", key)
+	require.Error(t, err)
+	assert.IsType(t, InvalidMFASessionCache(""), errors.Unwrap(err))
+
+	// Nothing but the sessions is written to the cache folder.
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, key, entries[0].Name())
+
+	// The sessions are not read from disk without a secret.
+	creds, err = readMFASessionCache(cacheDir, "", key)
+	require.NoError(t, err)
+	assert.Nil(t, creds)
+}
+
+func TestAssumeIamRoleWithMFAUsesCachedSession(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+	// The user can't be prompted, so the session must come from the caches.
+	terragruntOptions.NonInteractive = true
+	terragruntOptions.MFASessionCacheKey = "cache-secret"
+
+	cacheDir := t.TempDir()
+	iamRoleOpts := options.IAMRoleOptions{RoleARN: "arn:aws:iam::111111111111:role/cached", MFASerial: "arn:aws:iam::111111111111:mfa/user"}
+
+	_, err = assumeIamRoleWithMFA(nil, iamRoleOpts, terragruntOptions, cacheDir)
+	require.Error(t, err)
+	assert.IsType(t, MFACodeRequired{}, errors.Unwrap(err))
+
+	// A session about to expire is not reused.
+	key := mfaSessionKey(iamRoleOpts)
+	require.NoError(t, writeMFASessionCache(cacheDir, "cache-secret", key, &sts.Credentials{
+		AccessKeyId: aws.String("expiring"),
+		Expiration:  aws.Time(time.Now().Add(time.Minute)),
+	}))
+	_, err = assumeIamRoleWithMFA(nil, iamRoleOpts, terragruntOptions, cacheDir)
+	require.Error(t, err)
+
+	require.NoError(t, writeMFASessionCache(cacheDir, "cache-secret", key, &sts.Credentials{
+		AccessKeyId: aws.String("cached"),
+		Expiration:  aws.Time(time.Now().Add(time.Hour)),
+	}))
+	creds, err := assumeIamRoleWithMFA(nil, iamRoleOpts, terragruntOptions, cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", aws.StringValue(creds.AccessKeyId))
+
+	// The session is then reused from memory by the other units of the run.
+	require.NoError(t, os.Remove(filepath.Join(cacheDir, key)))
+	creds, err = assumeIamRoleWithMFA(nil, iamRoleOpts, terragruntOptions, cacheDir)
+	require.NoError(t, err)
+	assert.Equal(t, "cached", aws.StringValue(creds.AccessKeyId))
+}
+
+func TestAssumeIamRoleWithMFAUsesCallerSession(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRole", r.Form.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::111111111111:mfa/user", r.Form.Get("SerialNumber"))
+		assert.Equal(t, "123456", r.Form.Get("TokenCode"))
+		assert.Equal(t, "external-id", r.Form.Get("ExternalId"))
+		// The request is signed with the credentials of the session of the caller, such as those of a profile.
+		assert.Contains(t, r.Header.Get("Authorization"), "Credential=profile-access-key/")
+		fmt.Fprint(w, `<AssumeRoleResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleResult>
+    <Credentials>
+      <AccessKeyId>role-access-key</AccessKeyId>
+      <SecretAccessKey>secret-key</SecretAccessKey>
+      <SessionToken>session-token</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleResult>
+</AssumeRoleResponse>`)
+	}))
+	defer server.Close()
+
+	sess, err := session.NewSession(&aws.Config{
+		Region:      aws.String("us-east-1"),
+		Endpoint:    aws.String(server.URL),
+		Credentials: credentials.NewStaticCredentials("profile-access-key", "profile-secret-key", ""),
+	})
+	require.NoError(t, err)
+
+	iamRoleOpts := options.IAMRoleOptions{RoleARN: "arn:aws:iam::111111111111:role/profile", MFASerial: "arn:aws:iam::111111111111:mfa/user"}
+	externalID := func(p *stscreds.AssumeRoleProvider) { p.ExternalID = aws.String("external-id") }
+
+	creds, err := assumeIamRoleWithSession(sess, iamRoleOpts, "123456", externalID)
+	require.NoError(t, err)
+	assert.Equal(t, "role-access-key", aws.StringValue(creds.AccessKeyId))
+	assert.Equal(t, time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC), aws.TimeValue(creds.Expiration).UTC())
+}
@@ -0,0 +1,72 @@
+package aws_helper
+
+import (
+	"bytes"
+	"os"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/credentials/stscreds"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// webIdentityToken fetches the OIDC token to assume a role with web identity federation, which is either the path of a
+// file holding the token, such as the token file of a CI job, or the token itself. The file is read every time the
+// token is fetched, so that the token is picked up when the CI system refreshes it.
+type webIdentityToken string
+
+func (token webIdentityToken) FetchToken(ctx credentials.Context) ([]byte, error) {
+	if !util.FileExists(string(token)) {
+		return []byte(token), nil
+	}
+
+	contents, err := os.ReadFile(string(token))
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return bytes.TrimSpace(contents), nil
+}
+
+// getWebIdentityCredentials returns the credentials of the role of the given options, assumed with its web identity
+// token, which are refreshed with the token when they expire.
+func getWebIdentityCredentials(sess *session.Session, iamRoleOptions options.IAMRoleOptions) *credentials.Credentials {
+	provider := stscreds.NewWebIdentityRoleProviderWithOptions(
+		sts.New(sess),
+		iamRoleOptions.RoleARN,
+		iamRoleSessionName(iamRoleOptions),
+		webIdentityToken(iamRoleOptions.WebIdentityToken),
+		func(p *stscreds.WebIdentityRoleProvider) {
+			p.Duration = time.Second * time.Duration(iamRoleDuration(iamRoleOptions))
+		},
+	)
+	return credentials.NewCredentials(provider)
+}
+
+// assumeIamRoleWithWebIdentity assumes the role of the given options with its web identity token, which requires no AWS
+// credentials, and returns the temporary AWS credentials to use that role.
+func assumeIamRoleWithWebIdentity(sess *session.Session, iamRoleOptions options.IAMRoleOptions) (*sts.Credentials, error) {
+	token, err := webIdentityToken(iamRoleOptions.WebIdentityToken).FetchToken(aws.BackgroundContext())
+	if err != nil {
+		return nil, err
+	}
+
+	input := sts.AssumeRoleWithWebIdentityInput{
+		RoleArn:          aws.String(iamRoleOptions.RoleARN),
+		RoleSessionName:  aws.String(iamRoleSessionName(iamRoleOptions)),
+		DurationSeconds:  aws.Int64(iamRoleDuration(iamRoleOptions)),
+		WebIdentityToken: aws.String(string(token)),
+	}
+
+	output, err := sts.New(sess).AssumeRoleWithWebIdentity(&input)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	return output.Credentials, nil
+}
@@ -0,0 +1,75 @@
+package aws_helper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebIdentityTokenFromFileOrValue(t *testing.T) {
+	t.Parallel()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("token-from-file\n"), 0600))
+
+	token, err := webIdentityToken(tokenFile).FetchToken(aws.BackgroundContext())
+	require.NoError(t, err)
+	assert.Equal(t, "token-from-file", string(token))
+
+	token, err = webIdentityToken("eyJhbGciOiJSUzI1NiJ9.token").FetchToken(aws.BackgroundContext())
+	require.NoError(t, err)
+	assert.Equal(t, "eyJhbGciOiJSUzI1NiJ9.token", string(token))
+}
+
+func TestGetWebIdentityCredentials(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "AssumeRoleWithWebIdentity", r.Form.Get("Action"))
+		assert.Equal(t, "arn:aws:iam::111111111111:role/ci", r.Form.Get("RoleArn"))
+		assert.Equal(t, "ci-job", r.Form.Get("RoleSessionName"))
+		assert.Equal(t, "900", r.Form.Get("DurationSeconds"))
+		// The request is not signed, as it is authenticated by the token.
+		assert.Empty(t, r.Header.Get("Authorization"))
+		fmt.Fprintf(w, `<AssumeRoleWithWebIdentityResponse xmlns="https://sts.amazonaws.com/doc/2011-06-15/">
+  <AssumeRoleWithWebIdentityResult>
+    <Credentials>
+      <AccessKeyId>access-key-for-%s</AccessKeyId>
+      <SecretAccessKey>secret-key</SecretAccessKey>
+      <SessionToken>session-token</SessionToken>
+      <Expiration>2100-01-01T00:00:00Z</Expiration>
+    </Credentials>
+  </AssumeRoleWithWebIdentityResult>
+</AssumeRoleWithWebIdentityResponse>`, r.Form.Get("WebIdentityToken"))
+	}))
+	defer server.Close()
+
+	sess, err := session.NewSession(&aws.Config{Region: aws.String("us-east-1"), Endpoint: aws.String(server.URL)})
+	require.NoError(t, err)
+
+	iamRoleOpts := options.IAMRoleOptions{
+		RoleARN:               "arn:aws:iam::111111111111:role/ci",
+		AssumeRoleSessionName: "ci-job",
+		AssumeRoleDuration:    900,
+		WebIdentityToken:      "oidc-token",
+	}
+
+	creds, err := getWebIdentityCredentials(sess, iamRoleOpts).Get()
+	require.NoError(t, err)
+	assert.Equal(t, "access-key-for-oidc-token", creds.AccessKeyID)
+	assert.Equal(t, "session-token", creds.SessionToken)
+
+	stsCreds, err := assumeIamRoleWithWebIdentity(sess, iamRoleOpts)
+	require.NoError(t, err)
+	assert.Equal(t, "access-key-for-oidc-token", aws.StringValue(stsCreds.AccessKeyId))
+}
@@ -18,12 +18,28 @@ import (
 
 	"github.com/gruntwork-io/go-commons/env"
 	"github.com/gruntwork-io/terragrunt/cli/commands"
+	"github.com/gruntwork-io/terragrunt/cli/commands/agent"
 	awsproviderpatch "github.com/gruntwork-io/terragrunt/cli/commands/aws-provider-patch"
+	"github.com/gruntwork-io/terragrunt/cli/commands/backend"
+	"github.com/gruntwork-io/terragrunt/cli/commands/completion"
+	"github.com/gruntwork-io/terragrunt/cli/commands/cost"
+	"github.com/gruntwork-io/terragrunt/cli/commands/dependency"
+	"github.com/gruntwork-io/terragrunt/cli/commands/docs"
+	"github.com/gruntwork-io/terragrunt/cli/commands/eval"
+	"github.com/gruntwork-io/terragrunt/cli/commands/exec"
+	"github.com/gruntwork-io/terragrunt/cli/commands/graph"
 	graphdependencies "github.com/gruntwork-io/terragrunt/cli/commands/graph-dependencies"
 	"github.com/gruntwork-io/terragrunt/cli/commands/hclfmt"
+	"github.com/gruntwork-io/terragrunt/cli/commands/hclvalidate"
+	importgen "github.com/gruntwork-io/terragrunt/cli/commands/import-gen"
+	"github.com/gruntwork-io/terragrunt/cli/commands/list"
 	outputmodulegroups "github.com/gruntwork-io/terragrunt/cli/commands/output-module-groups"
+	"github.com/gruntwork-io/terragrunt/cli/commands/render"
 	renderjson "github.com/gruntwork-io/terragrunt/cli/commands/render-json"
+	"github.com/gruntwork-io/terragrunt/cli/commands/run"
 	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/cli/commands/stack"
+	"github.com/gruntwork-io/terragrunt/cli/commands/state"
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	terragruntinfo "github.com/gruntwork-io/terragrunt/cli/commands/terragrunt-info"
 	validateinputs "github.com/gruntwork-io/terragrunt/cli/commands/validate-inputs"
@@ -67,14 +83,30 @@ func NewApp(writer io.Writer, errWriter io.Writer) *cli.App {
 // This set of commands is also used in unit tests
 func terragruntCommands(opts *options.TerragruntOptions) cli.Commands {
 	cmds := cli.Commands{
+		run.NewCommand(opts),                // run
 		runall.NewCommand(opts),             // run-all
+		graph.NewCommand(opts),              // graph
+		agent.NewCommand(opts),              // agent
 		terragruntinfo.NewCommand(opts),     // terragrunt-info
 		validateinputs.NewCommand(opts),     // validate-inputs
 		graphdependencies.NewCommand(opts),  // graph-dependencies
 		hclfmt.NewCommand(opts),             // hclfmt
+		eval.NewCommand(opts),               // eval
+		exec.NewCommand(opts),               // exec
+		completion.NewCommand(opts),         // completion
+		cost.NewCommand(opts),               // cost
+		docs.NewCommand(opts),               // docs
+		importgen.NewCommand(opts),          // import-gen
+		list.NewCommand(opts),               // list
+		hclvalidate.NewCommand(opts),        // hclvalidate
 		renderjson.NewCommand(opts),         // render-json
+		render.NewCommand(opts),             // render
 		awsproviderpatch.NewCommand(opts),   // aws-provider-patch
 		outputmodulegroups.NewCommand(opts), // output-module-groups
+		stack.NewCommand(opts),              // stack
+		state.NewCommand(opts),              // state
+		backend.NewCommand(opts),            // backend
+		dependency.NewCommand(opts),         // dependency
 	}
 
 	sort.Sort(cmds)
@@ -112,12 +144,18 @@ func initialSetup(opts *options.TerragruntOptions) func(ctx *cli.Context) error
 
 		// --- Args
 		// convert the rest flags (intended for terraform) to one dash, e.g. `--input=true` to `-input=true`
-		args := ctx.Args().Normalize(cli.SingleDashFlag).Slice()
+		cliArgs := ctx.Args()
+		// `--` separates the flags of the run command from the terraform command and its args, e.g. `run --all -- plan`.
+		// The args are normalized to single dashes by then, so the separator is `-`.
+		if ctx.Command.Name == run.CommandName && cliArgs.First() == "-" {
+			cliArgs = cliArgs.Tail()
+		}
+		args := cliArgs.Normalize(cli.SingleDashFlag).Slice()
 		cmdName := ctx.Command.Name
 
 		switch cmdName {
-		case terraform.CommandName, runall.CommandName:
-			cmdName = ctx.Args().CommandName()
+		case terraform.CommandName, runall.CommandName, run.CommandName, graph.CommandName:
+			cmdName = cliArgs.CommandName()
 		default:
 			args = append([]string{ctx.Command.Name}, args...)
 		}
@@ -131,6 +169,13 @@ func initialSetup(opts *options.TerragruntOptions) func(ctx *cli.Context) error
 		if opts.DisableLogColors {
 			util.DisableLogColors()
 		}
+		switch opts.LogFormat {
+		case options.LogFormatText:
+		case options.LogFormatJSONStream:
+			util.EnableJSONLogFormat()
+		default:
+			return errors.WithStackTrace(InvalidLogFormat(opts.LogFormat))
+		}
 		opts.LogLevel = util.ParseLogLevel(opts.LogLevelStr)
 		opts.Logger = util.CreateLogEntry("", opts.LogLevel)
 		opts.Logger.Logger.SetOutput(ctx.App.ErrWriter)
@@ -159,7 +204,7 @@ func initialSetup(opts *options.TerragruntOptions) func(ctx *cli.Context) error
 		// --- Terragrunt ConfigPath
 		if opts.TerragruntConfigPath == "" {
 			opts.TerragruntConfigPath = config.GetDefaultConfigPath(opts.WorkingDir)
-		} else if !filepath.IsAbs(opts.TerragruntConfigPath) && ctx.Command.Name == terraform.CommandName {
+		} else if !filepath.IsAbs(opts.TerragruntConfigPath) && (ctx.Command.Name == terraform.CommandName || ctx.Command.Name == run.CommandName || ctx.Command.Name == graph.CommandName) {
 			opts.TerragruntConfigPath = util.JoinPath(opts.WorkingDir, opts.TerragruntConfigPath)
 		}
 
@@ -175,6 +220,12 @@ func initialSetup(opts *options.TerragruntOptions) func(ctx *cli.Context) error
 			return err
 		}
 
+		// The variable files are relative to the dir terragrunt is run in, and not to the modules of a run-all.
+		opts.TerragruntVarFiles, err = util.CanonicalPaths(opts.TerragruntVarFiles, opts.WorkingDir)
+		if err != nil {
+			return err
+		}
+
 		// --- Terragrunt Version
 		terragruntVersion, err := hashicorpversion.NewVersion(ctx.App.Version)
 		if err != nil {
@@ -223,3 +274,11 @@ func osExiter(exitCode int) {
 	// Do nothing. We just need to override this function, as the default value calls os.Exit, which
 	// kills the app (or any automated test) dead in its tracks.
 }
+
+// Custom error types
+
+type InvalidLogFormat string
+
+func (format InvalidLogFormat) Error() string {
+	return fmt.Sprintf("Invalid log format %q. Supported formats: %s, %s", string(format), options.LogFormatText, options.LogFormatJSONStream)
+}
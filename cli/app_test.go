@@ -13,6 +13,7 @@ import (
 	"github.com/gruntwork-io/terragrunt/cli/commands"
 	awsproviderpatch "github.com/gruntwork-io/terragrunt/cli/commands/aws-provider-patch"
 	"github.com/gruntwork-io/terragrunt/cli/commands/hclfmt"
+	"github.com/gruntwork-io/terragrunt/cli/commands/run"
 	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	"github.com/gruntwork-io/terragrunt/config"
@@ -268,6 +269,8 @@ func TestFilterTerragruntArgs(t *testing.T) {
 		{[]string{"foo", doubleDashed(commands.FlagNameTerragruntNonInteractive), "-bar", doubleDashed(commands.FlagNameTerragruntWorkingDir), "/some/path", "--baz", doubleDashed(commands.FlagNameTerragruntConfig), fmt.Sprintf("/some/path/%s", config.DefaultTerragruntConfigPath)}, []string{"foo", "-bar", "-baz"}},
 		{[]string{CommandNameApplyAll, "foo", "bar"}, []string{terraform.CommandNameApply, "foo", "bar"}},
 		{[]string{CommandNameDestroyAll, "foo", "-foo", "--bar"}, []string{terraform.CommandNameDestroy, "foo", "-foo", "-bar"}},
+		{[]string{run.CommandName, "--" + run.FlagNameAll, "--", "plan", "-out=plan.tfplan"}, []string{terraform.CommandNamePlan, "-out=plan.tfplan"}},
+		{[]string{run.CommandName, "--", "apply", "--auto-approve"}, []string{terraform.CommandNameApply, "-auto-approve"}},
 	}
 
 	for _, testCase := range testCases {
@@ -308,6 +311,17 @@ func TestParseMultiStringArg(t *testing.T) {
 	}
 }
 
+func TestParseVarFileArgRelativeToWorkingDir(t *testing.T) {
+	t.Parallel()
+
+	workingDir := t.TempDir()
+	opts := options.NewTerragruntOptions()
+	opts.WorkingDir = workingDir
+	actualOptions, err := runAppTest([]string{CommandNameApplyAll, doubleDashed(commands.FlagNameTerragruntVarFile), "vars/prod.tfvars", doubleDashed(commands.FlagNameTerragruntVarFile), "/etc/common.tfvars"}, opts)
+	require.NoError(t, err)
+	assert.Equal(t, []string{filepath.ToSlash(filepath.Join(workingDir, "vars", "prod.tfvars")), "/etc/common.tfvars"}, actualOptions.TerragruntVarFiles)
+}
+
 func TestParseMutliStringKeyValueArg(t *testing.T) {
 	t.Parallel()
 
@@ -476,7 +490,7 @@ func TestAutocomplete(t *testing.T) {
 	}{
 		{
 			"",
-			[]string{"aws-provider-patch", "graph-dependencies", "hclfmt", "output-module-groups", "render-json", "run-all", "terragrunt-info", "validate-inputs"},
+			[]string{"aws-provider-patch", "backend", "completion", "cost", "dependency", "docs", "eval", "exec", "graph", "graph-dependencies", "hclfmt", "hclvalidate", "import-gen", "list", "find", "output-module-groups", "render", "render-json", "run", "run-all", "stack", "state", "terragrunt-info", "validate-inputs"},
 		},
 		{
 			"--versio",
@@ -488,7 +502,11 @@ func TestAutocomplete(t *testing.T) {
 		},
 		{
 			"run-all ren",
-			[]string{"render-json"},
+			[]string{"render", "render-json"},
+		},
+		{
+			"run ren",
+			[]string{"render", "render-json"},
 		},
 	}
 
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/agent"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const defaultListenAddress = "127.0.0.1:7070"
+
+func Run(opts *options.TerragruntOptions) error {
+	address := opts.AgentListenAddress
+	if address == "" {
+		address = defaultListenAddress
+	}
+
+	tlsConfig, err := agent.ServerTLSConfig(opts.AgentTLSCert, opts.AgentTLSKey, opts.AgentTLSCA)
+	if err != nil {
+		return err
+	}
+	if err := agent.CheckServerAuthentication(address, opts.AgentToken, tlsConfig); err != nil {
+		return err
+	}
+	if err := agent.CheckTokenTransport(address, opts.AgentToken, tlsConfig); err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	server := agent.NewServer(opts, opts.AgentToken, tlsConfig)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	defer func() {
+		signal.Stop(signals)
+		close(signals)
+	}()
+	go func() {
+		if _, ok := <-signals; ok {
+			opts.Logger.Infof("Stopping the agent once the running modules finish")
+			server.Stop()
+		}
+	}()
+
+	return server.Serve(listener)
+}
@@ -0,0 +1,77 @@
+package agent
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "agent"
+
+	FlagNameTerragruntAgentListen  = "terragrunt-agent-listen"
+	FlagNameTerragruntAgentToken   = "terragrunt-agent-token"
+	FlagNameTerragruntAgentTLSCert = "terragrunt-agent-tls-cert"
+	FlagNameTerragruntAgentTLSKey  = "terragrunt-agent-tls-key"
+	FlagNameTerragruntAgentTLSCA   = "terragrunt-agent-tls-ca"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	flags := cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntAgentListen,
+			Destination: &opts.AgentListenAddress,
+			EnvVar:      "TERRAGRUNT_AGENT_LISTEN",
+			Usage:       "The address the agent listens on for work items from a run-all scheduler. Default is 127.0.0.1:7070.",
+		},
+		NewTokenFlag(opts),
+	}
+	return append(flags, NewTLSFlags(opts)...)
+}
+
+// NewTokenFlag creates the flag for the token shared by the agents and the run-all scheduler.
+func NewTokenFlag(opts *options.TerragruntOptions) cli.Flag {
+	return &cli.GenericFlag[string]{
+		Name:        FlagNameTerragruntAgentToken,
+		Destination: &opts.AgentToken,
+		EnvVar:      "TERRAGRUNT_AGENT_TOKEN",
+		Usage:       "A token shared between the run-all scheduler and the agents to authenticate the scheduler.",
+	}
+}
+
+// NewTLSFlags creates the flags of the TLS connection between the agents and the run-all scheduler.
+func NewTLSFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntAgentTLSCert,
+			Destination: &opts.AgentTLSCert,
+			EnvVar:      "TERRAGRUNT_AGENT_TLS_CERT",
+			Usage:       "The PEM encoded TLS certificate the agent serves with, or the client certificate the run-all scheduler presents to the agents.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntAgentTLSKey,
+			Destination: &opts.AgentTLSKey,
+			EnvVar:      "TERRAGRUNT_AGENT_TLS_KEY",
+			Usage:       "The PEM encoded private key of the certificate of --terragrunt-agent-tls-cert.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntAgentTLSCA,
+			Destination: &opts.AgentTLSCA,
+			EnvVar:      "TERRAGRUNT_AGENT_TLS_CA",
+			Usage:       "The PEM encoded CA the run-all scheduler verifies the agents with, or the agent verifies the client certificates of the scheduler with.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Run as an agent that executes the modules dispatched by a remote run-all scheduler.",
+		Description: "The agent serves work items sent by 'terragrunt run-all --terragrunt-agents' over gRPC. Module paths are resolved relative to the working directory of the agent, which should be a checkout of the same code as the scheduler.",
+		Flags:       NewFlags(opts).Sort(),
+		Action: func(ctx *cli.Context) error {
+			opts.RunTerragrunt = terraform.Run
+			return Run(opts.OptionsFromContext(ctx))
+		},
+	}
+}
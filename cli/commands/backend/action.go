@@ -0,0 +1,175 @@
+// `backend check` compares the live resources of the backends of the modules in the working dir, such as the S3 bucket
+// and the DynamoDB lock table, with the configuration terragrunt would create them with, and prints the drift as json.
+// Each backend is checked once, however many modules store their state in it, and nothing is modified.
+
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+// The settings of the backend config that don't change the resources of the backend, and differ for each module.
+var moduleSpecificConfigs = []string{"key", "prefix"}
+
+// CheckResult is the result of `backend check`, which is printed as json.
+type CheckResult struct {
+	Drifted  bool            `json:"drifted"`
+	Backends []BackendResult `json:"backends"`
+}
+
+// BackendResult is the drift of a backend, along with the modules that store their state in it.
+type BackendResult struct {
+	Backend string `json:"backend"`
+	Bucket  string `json:"bucket,omitempty"`
+	// Whether terragrunt can check the resources of this type of backend. The drift of the other backends is empty.
+	Supported bool                  `json:"supported"`
+	Modules   []string              `json:"modules"`
+	Drift     []remote.BackendDrift `json:"drift"`
+}
+
+// backendUsage is a backend along with the modules that store their state in it.
+type backendUsage struct {
+	remoteState *remote.RemoteState
+	modules     []string
+}
+
+func RunCheck(opts *options.TerragruntOptions) error {
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	usages, err := findBackendUsages(opts, stack.Modules)
+	if err != nil {
+		return err
+	}
+
+	result := CheckResult{Backends: []BackendResult{}}
+	driftCount := 0
+	for _, usage := range usages {
+		backendResult, err := checkBackend(opts, usage)
+		if err != nil {
+			return err
+		}
+		driftCount += len(backendResult.Drift)
+		result.Backends = append(result.Backends, backendResult)
+	}
+	result.Drifted = driftCount > 0
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := fmt.Fprintf(opts.Writer, "%s\n", resultJSON); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if result.Drifted {
+		return errors.WithStackTrace(BackendsDrifted{DriftCount: driftCount, BackendCount: len(result.Backends)})
+	}
+	return nil
+}
+
+// checkBackend returns the drift of the resources of the given backend.
+func checkBackend(opts *options.TerragruntOptions, usage *backendUsage) (BackendResult, error) {
+	remoteState := usage.remoteState
+
+	backendResult := BackendResult{
+		Backend: remoteState.Backend,
+		Modules: usage.modules,
+		Drift:   []remote.BackendDrift{},
+	}
+	if bucket, ok := remoteState.Config["bucket"].(string); ok {
+		backendResult.Bucket = bucket
+	}
+
+	if remoteState.Backend != "s3" {
+		opts.Logger.Warnf("Terragrunt can not check the resources of the %s backend used by %s", remoteState.Backend, strings.Join(usage.modules, ", "))
+		return backendResult, nil
+	}
+
+	opts.Logger.Debugf("Checking the resources of the backend %s", remoteState)
+	drift, err := remote.CheckS3BackendDrift(remoteState.Config, opts)
+	if err != nil {
+		return backendResult, err
+	}
+
+	backendResult.Supported = true
+	backendResult.Drift = append(backendResult.Drift, drift...)
+	return backendResult, nil
+}
+
+// findBackendUsages returns the backends of the given modules, sorted by backend, which are the remote_state configs
+// without the settings that are specific to each module. The modules without remote_state are skipped.
+func findBackendUsages(opts *options.TerragruntOptions, modules []*configstack.TerraformModule) ([]*backendUsage, error) {
+	usages := map[string]*backendUsage{}
+
+	for _, module := range modules {
+		configPath := module.TerragruntOptions.TerragruntConfigPath
+
+		cfg, err := config.PartialParseConfigFile(configPath, module.TerragruntOptions, nil, []config.PartialDecodeSectionType{config.RemoteStateBlock})
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.RemoteState == nil {
+			opts.Logger.Debugf("Module %s does not have a remote_state block", module.Path)
+			continue
+		}
+
+		backendConfig := map[string]interface{}{}
+		for name, value := range cfg.RemoteState.Config {
+			backendConfig[name] = value
+		}
+		for _, name := range moduleSpecificConfigs {
+			delete(backendConfig, name)
+		}
+
+		backendConfigJSON, err := json.Marshal(backendConfig)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		key := cfg.RemoteState.Backend + string(backendConfigJSON)
+
+		usage, ok := usages[key]
+		if !ok {
+			usage = &backendUsage{remoteState: cfg.RemoteState}
+			usages[key] = usage
+		}
+		usage.modules = append(usage.modules, relPath(opts, module.Path))
+	}
+
+	keys := []string{}
+	for key := range usages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sortedUsages := []*backendUsage{}
+	for _, key := range keys {
+		sortedUsages = append(sortedUsages, usages[key])
+	}
+
+	return sortedUsages, nil
+}
+
+// relPath returns the given path relative to the working dir, so that the result doesn't depend on where the working
+// dir is checked out. The paths outside of the working dir are kept as is.
+func relPath(opts *options.TerragruntOptions, path string) string {
+	rel, err := filepath.Rel(opts.WorkingDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,86 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+func TestFindBackendUsages(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+
+	configs := map[string]string{
+		"vpc": `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "vpc/terraform.tfstate"
+    region = "us-east-1"
+  }
+}
+`,
+		"app": `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "app/terraform.tfstate"
+    region = "us-east-1"
+  }
+}
+`,
+		"other": `
+remote_state {
+  backend = "gcs"
+  config = {
+    bucket = "my-gcs-state"
+    prefix = "other"
+  }
+}
+`,
+		"local": `
+inputs = {}
+`,
+	}
+
+	modules := []*configstack.TerraformModule{}
+	for _, name := range []string{"app", "local", "other", "vpc"} {
+		moduleDir := filepath.Join(rootDir, name)
+		require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+
+		configPath := filepath.Join(moduleDir, "terragrunt.hcl")
+		require.NoError(t, os.WriteFile(configPath, []byte(configs[name]), 0644))
+
+		opts, err := options.NewTerragruntOptionsForTest(configPath)
+		require.NoError(t, err)
+		modules = append(modules, &configstack.TerraformModule{Path: moduleDir, TerragruntOptions: opts})
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = rootDir
+
+	usages, err := findBackendUsages(opts, modules)
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+
+	assert.Equal(t, "gcs", usages[0].remoteState.Backend)
+	assert.Equal(t, []string{"other"}, usages[0].modules)
+
+	assert.Equal(t, "s3", usages[1].remoteState.Backend)
+	assert.Equal(t, []string{"app", "vpc"}, usages[1].modules)
+
+	backendResult, err := checkBackend(opts, usages[0])
+	require.NoError(t, err)
+	assert.Equal(t, BackendResult{Backend: "gcs", Bucket: "my-gcs-state", Modules: []string{"other"}, Drift: []remote.BackendDrift{}}, backendResult)
+}
@@ -0,0 +1,118 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// RunBootstrap creates the resources of the backends of the module in the working dir, or of all the modules in the
+// working dir with --all, such as the S3 bucket and the DynamoDB lock table, so that they don't have to be created by
+// the first run of the modules.
+func RunBootstrap(opts *options.TerragruntOptions) error {
+	usages, err := findTargetBackendUsages(opts)
+	if err != nil {
+		return err
+	}
+
+	if len(usages) == 0 {
+		opts.Logger.Infof("None of the modules in %s have a remote_state block", opts.WorkingDir)
+		return nil
+	}
+
+	// Creating the resources is the point of the command, so the user isn't prompted for it, and the resources are
+	// created even if creating them is not allowed when running the modules.
+	bootstrapOpts := opts.Clone(opts.TerragruntConfigPath)
+	bootstrapOpts.NonInteractive = true
+	bootstrapOpts.FailIfBucketCreationRequired = false
+
+	for _, usage := range usages {
+		if err := usage.remoteState.Validate(); err != nil {
+			return err
+		}
+
+		opts.Logger.Infof("Bootstrapping the %s used by %s", describeBackend(usage), strings.Join(usage.modules, ", "))
+		if err := usage.remoteState.Initialize(bootstrapOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RunDelete deletes the resources of the backends of the module in the working dir, or of all the modules in the
+// working dir with --all, along with all the states they hold, once the user confirms it.
+func RunDelete(opts *options.TerragruntOptions) error {
+	usages, err := findTargetBackendUsages(opts)
+	if err != nil {
+		return err
+	}
+
+	deletableUsages := []*backendUsage{}
+	descriptions := []string{}
+	for _, usage := range usages {
+		if !usage.remoteState.CanDelete() {
+			opts.Logger.Warnf("Terragrunt can not delete the resources of the %s backend used by %s", usage.remoteState.Backend, strings.Join(usage.modules, ", "))
+			continue
+		}
+		deletableUsages = append(deletableUsages, usage)
+		descriptions = append(descriptions, fmt.Sprintf("- The %s used by %s\n", describeBackend(usage), strings.Join(usage.modules, ", ")))
+	}
+
+	if len(deletableUsages) == 0 {
+		opts.Logger.Infof("There are no backend resources to delete in %s", opts.WorkingDir)
+		return nil
+	}
+
+	prompt := fmt.Sprintf("WARNING: The following backend resources will be deleted, along with all the states stored in them, including the states of the modules that are not listed:\n%sAre you sure you want to delete them? There is no undo!", strings.Join(descriptions, ""))
+	shouldDelete, err := shell.PromptUserForYesNo(prompt, opts)
+	if err != nil {
+		return err
+	}
+	if !shouldDelete {
+		return nil
+	}
+
+	for _, usage := range deletableUsages {
+		if err := usage.remoteState.Delete(opts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findTargetBackendUsages returns the backends of the module in the working dir, or of all the modules in the working
+// dir if --all is set.
+func findTargetBackendUsages(opts *options.TerragruntOptions) ([]*backendUsage, error) {
+	if !opts.BackendAll {
+		module := &configstack.TerraformModule{Path: opts.WorkingDir, TerragruntOptions: opts}
+		return findBackendUsages(opts, []*configstack.TerraformModule{module})
+	}
+
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return nil, err
+	}
+	return findBackendUsages(opts, stack.Modules)
+}
+
+// describeBackend returns a description of the resources of the given backend, such as "S3 bucket my-state", for the
+// logs and the prompts.
+func describeBackend(usage *backendUsage) string {
+	config := usage.remoteState.Config
+	switch usage.remoteState.Backend {
+	case "s3":
+		if lockTable, _, err := remote.GetS3Locking(config); err == nil && lockTable != nil {
+			return fmt.Sprintf("S3 bucket %v and DynamoDB lock table %s", config["bucket"], lockTable.Name)
+		}
+		return fmt.Sprintf("S3 bucket %v", config["bucket"])
+	case "gcs":
+		return fmt.Sprintf("GCS bucket %v", config["bucket"])
+	case "azurerm":
+		return fmt.Sprintf("Azure storage container %v of storage account %v", config["container_name"], config["storage_account_name"])
+	}
+	return fmt.Sprintf("%s backend", usage.remoteState.Backend)
+}
@@ -0,0 +1,78 @@
+package backend
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestFindTargetBackendUsages(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	for _, name := range []string{"app", "vpc"} {
+		moduleDir := filepath.Join(rootDir, name)
+		require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(""), 0644))
+		require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "terragrunt.hcl"), []byte(`
+remote_state {
+  backend = "s3"
+  config = {
+    bucket         = "my-state"
+    key            = "${path_relative_to_include()}/terraform.tfstate"
+    region         = "us-east-1"
+    dynamodb_table = "my-locks"
+  }
+}
+`), 0644))
+	}
+
+	testCases := []struct {
+		all        bool
+		workingDir string
+		expected   []string
+	}{
+		{false, filepath.Join(rootDir, "app"), []string{"."}},
+		{true, rootDir, []string{"app", "vpc"}},
+	}
+
+	for _, testCase := range testCases {
+		opts, err := options.NewTerragruntOptionsForTest(filepath.Join(testCase.workingDir, "terragrunt.hcl"))
+		require.NoError(t, err)
+		opts.WorkingDir = testCase.workingDir
+		opts.BackendAll = testCase.all
+
+		usages, err := findTargetBackendUsages(opts)
+		require.NoError(t, err)
+		require.Len(t, usages, 1)
+		assert.Equal(t, testCase.expected, usages[0].modules)
+		assert.Equal(t, "S3 bucket my-state and DynamoDB lock table my-locks", describeBackend(usages[0]))
+	}
+}
+
+func TestRunDeleteSkipsUnsupportedBackends(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	configPath := filepath.Join(moduleDir, "terragrunt.hcl")
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+remote_state {
+  backend = "local"
+  config = {
+    path = "terraform.tfstate"
+  }
+}
+`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	opts.WorkingDir = moduleDir
+
+	// The user would be prompted if there was a backend to delete.
+	require.NoError(t, RunDelete(opts))
+}
@@ -0,0 +1,63 @@
+package backend
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName          = "backend"
+	CommandNameCheck     = "check"
+	CommandNameBootstrap = "bootstrap"
+	CommandNameDelete    = "delete"
+
+	FlagNameAll = "all"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Inspect the resources of the backends of the modules, such as the state bucket and the lock table, with `backend check`, create them with `backend bootstrap`, or delete them with `backend delete`.",
+		Subcommands: cli.Commands{newCheckCommand(opts), newBootstrapCommand(opts), newDeleteCommand(opts)},
+		Action:      func(ctx *cli.Context) error { return errors.WithStackTrace(MissingSubcommand{}) },
+	}
+}
+
+func newCheckCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameCheck,
+		Usage:       "Compare the backend resources of the modules in the current directory tree with the configuration Terragrunt would create them with, and report the drift as json.",
+		Description: "Nothing is created or modified. Only the S3 backend is checked: the encryption, versioning, policies, public access blocking, access logging and object lock of the bucket, and the existence and encryption of the DynamoDB lock table.",
+		Action:      func(ctx *cli.Context) error { return RunCheck(opts.OptionsFromContext(ctx)) },
+	}
+}
+
+func newAllFlag(opts *options.TerragruntOptions) cli.Flag {
+	return &cli.BoolFlag{
+		Name:        FlagNameAll,
+		Destination: &opts.BackendAll,
+		Usage:       "Handle the backends of all the modules in the current directory tree, rather than the backend of the module in the working directory.",
+	}
+}
+
+func newBootstrapCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameBootstrap,
+		Usage:       "Create the resources of the backend of the module, such as the state bucket and the lock table, without running the module.",
+		Description: "The resources are created and updated as when running the module, following the remote_state config, but without prompting, and even if --terragrunt-fail-on-state-bucket-creation is set. Each backend is bootstrapped once, however many modules store their state in it.",
+		Flags:       cli.Flags{newAllFlag(opts)},
+		Action:      func(ctx *cli.Context) error { return RunBootstrap(opts.OptionsFromContext(ctx)) },
+	}
+}
+
+func newDeleteCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameDelete,
+		Usage:       "Delete the resources of the backend of the module, such as the state bucket and the lock table, along with all the states stored in them.",
+		Description: "Only the s3, gcs and azurerm backends are supported. For the s3 backend, the lock table is deleted first, and nothing is deleted if it still holds a lock. For the azurerm backend, only the container is deleted.",
+		Flags:       cli.Flags{newAllFlag(opts)},
+		Action:      func(ctx *cli.Context) error { return RunDelete(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -0,0 +1,20 @@
+package backend
+
+import (
+	"fmt"
+)
+
+type MissingSubcommand struct{}
+
+func (err MissingSubcommand) Error() string {
+	return fmt.Sprintf("Missing %s subcommand, e.g. `terragrunt %s %s` or `terragrunt %s %s`.", CommandName, CommandName, CommandNameCheck, CommandName, CommandNameBootstrap)
+}
+
+type BackendsDrifted struct {
+	DriftCount   int
+	BackendCount int
+}
+
+func (err BackendsDrifted) Error() string {
+	return fmt.Sprintf("Found %d settings that drifted in %d backends.", err.DriftCount, err.BackendCount)
+}
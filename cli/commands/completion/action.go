@@ -0,0 +1,90 @@
+// `completion` command prints the script that enables the shell completion of terragrunt for the given shell. The
+// scripts only register terragrunt as the completer of its own command line, so the completions are generated by the
+// CLI app from its commands and flags, with the values of some flags completed from the units in the working dir.
+
+package completion
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The shells the completion scripts are available for.
+const (
+	ShellBash = "bash"
+	ShellZsh  = "zsh"
+	ShellFish = "fish"
+)
+
+// Shells are the shells the completion scripts are available for.
+var Shells = []string{ShellBash, ShellZsh, ShellFish}
+
+// The scripts are formatted with the name of the binary. Bash and zsh, through its bash compatibility, run the binary
+// with COMP_LINE and COMP_POINT set to the command line and the position of the cursor, and fish sets COMP_LINE to the
+// command line up to the cursor.
+const (
+	bashScript = `# terragrunt completion for bash, enabled with: source <(%[1]s completion bash)
+complete -o default -C %[1]s %[1]s
+`
+
+	zshScript = `# terragrunt completion for zsh, enabled with: source <(%[1]s completion zsh)
+autoload -U +X bashcompinit && bashcompinit
+complete -o default -C %[1]s %[1]s
+`
+
+	fishScript = `# terragrunt completion for fish, enabled with: %[1]s completion fish | source
+function __complete_%[1]s
+    set -lx COMP_LINE (commandline -cp)
+    test -z (commandline -ct)
+    and set COMP_LINE "$COMP_LINE "
+    %[1]s
+end
+complete -f -c %[1]s -a "(__complete_%[1]s)"
+`
+)
+
+// Run prints the completion script of the given shell for the binary with the given name.
+func Run(opts *options.TerragruntOptions, shell, name string) error {
+	script, err := Script(shell, name)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprint(opts.Writer, script); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// Script returns the completion script of the given shell for the binary with the given name.
+func Script(shell, name string) (string, error) {
+	switch shell {
+	case ShellBash:
+		return fmt.Sprintf(bashScript, name), nil
+	case ShellZsh:
+		return fmt.Sprintf(zshScript, name), nil
+	case ShellFish:
+		return fmt.Sprintf(fishScript, name), nil
+	case "":
+		return "", errors.WithStackTrace(MissingShell{})
+	}
+	return "", errors.WithStackTrace(UnsupportedShell(shell))
+}
+
+// Custom error types
+
+type MissingShell struct{}
+
+func (err MissingShell) Error() string {
+	return fmt.Sprintf("Missing the shell to print the completion script for. The supported shells are %s.", strings.Join(Shells, ", "))
+}
+
+type UnsupportedShell string
+
+func (err UnsupportedShell) Error() string {
+	return fmt.Sprintf("Unsupported shell %s. The supported shells are %s.", string(err), strings.Join(Shells, ", "))
+}
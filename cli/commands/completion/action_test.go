@@ -0,0 +1,53 @@
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScript(t *testing.T) {
+	t.Parallel()
+
+	script, err := Script(ShellBash, "terragrunt")
+	require.NoError(t, err)
+	assert.Contains(t, script, "complete -o default -C terragrunt terragrunt\n")
+
+	script, err = Script(ShellZsh, "terragrunt")
+	require.NoError(t, err)
+	assert.Contains(t, script, "bashcompinit")
+
+	script, err = Script(ShellFish, "tg")
+	require.NoError(t, err)
+	assert.Contains(t, script, `complete -f -c tg -a "(__complete_tg)"`)
+
+	_, err = Script("tcsh", "terragrunt")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported shell tcsh")
+}
+
+func TestCompleteUnitPathsAndTags(t *testing.T) {
+	t.Parallel()
+
+	stackDir := t.TempDir()
+	writeConfig := func(path string, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+	writeConfig(filepath.Join(stackDir, "network", "vpc", config.DefaultTerragruntConfigPath), `tags = ["network", "prod"]`)
+	writeConfig(filepath.Join(stackDir, "app", config.DefaultTerragruntConfigPath), `tags = ["app", "prod"]`)
+	writeConfig(filepath.Join(stackDir, "broken", config.DefaultTerragruntConfigPath), `tags = [`)
+	writeConfig(filepath.Join(stackDir, "app", ".terragrunt-cache", "abc", config.DefaultTerragruntConfigPath), `tags = ["cached"]`)
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = stackDir
+
+	assert.Equal(t, []string{"app", "broken", "network/vpc"}, UnitPaths(opts)(nil))
+	assert.Equal(t, []string{"app", "network", "prod"}, Tags(opts)(nil))
+}
@@ -0,0 +1,21 @@
+package completion
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "completion"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Print the script that enables the shell completion of terragrunt for the given shell: bash, zsh or fish.",
+		Description: "The script completes the commands and flags of terragrunt, as well as the paths of the units and the values of their tags in the current directory tree.",
+		Action: func(ctx *cli.Context) error {
+			return Run(opts.OptionsFromContext(ctx), ctx.Args().First(), ctx.App.Name)
+		},
+	}
+}
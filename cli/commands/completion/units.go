@@ -0,0 +1,85 @@
+package completion
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// UnitPaths returns the func completing the paths of the units in the working dir, relative to it, for the flags that
+// take the path of a unit.
+func UnitPaths(opts *options.TerragruntOptions) cli.FlagCompleteFunc {
+	return func(ctx *cli.Context) []string {
+		workingDir, configFiles := findConfigFiles(opts)
+
+		paths := []string{}
+		for _, configFile := range configFiles {
+			path, err := filepath.Rel(workingDir, filepath.Dir(configFile))
+			if err != nil || path == "." {
+				continue
+			}
+			paths = append(paths, filepath.ToSlash(path))
+		}
+		sort.Strings(paths)
+		return paths
+	}
+}
+
+// Tags returns the func completing the tags of the units in the working dir, for the flags that filter the units on
+// their tags. The tags are read with a partial parse of the configs, and the configs that can't be parsed are skipped.
+func Tags(opts *options.TerragruntOptions) cli.FlagCompleteFunc {
+	return func(ctx *cli.Context) []string {
+		_, configFiles := findConfigFiles(opts)
+
+		tags := []string{}
+		for _, configFile := range configFiles {
+			configOpts := opts.Clone(configFile)
+			configOpts.WorkingDir = filepath.Dir(configFile)
+
+			cfg, err := config.PartialParseConfigFile(configFile, configOpts, nil, []config.PartialDecodeSectionType{config.TerragruntTags})
+			if err != nil {
+				continue
+			}
+			for _, tag := range cfg.Tags {
+				if !util.ListContainsElement(tags, tag) {
+					tags = append(tags, tag)
+				}
+			}
+		}
+		sort.Strings(tags)
+		return tags
+	}
+}
+
+// findConfigFiles returns the working dir and the terragrunt configs in it. The options are not initialized when
+// completing, so the working dir is the one of --terragrunt-working-dir, if it precedes the completed flag, or the
+// current dir. Errors are ignored, as nothing is completed then.
+func findConfigFiles(opts *options.TerragruntOptions) (string, []string) {
+	workingDir := opts.WorkingDir
+	if workingDir == "" {
+		currentDir, err := os.Getwd()
+		if err != nil {
+			return "", nil
+		}
+		workingDir = currentDir
+	}
+
+	// The download dir, which the configs are not searched in, defaults to the one of the working dir, as when the
+	// options are initialized.
+	findOpts := opts.Clone(opts.TerragruntConfigPath)
+	findOpts.WorkingDir = workingDir
+	if findOpts.DownloadDir == "" {
+		findOpts.DownloadDir = util.JoinPath(workingDir, util.TerragruntCacheDir)
+	}
+
+	configFiles, err := config.FindConfigFilesInPath(workingDir, findOpts)
+	if err != nil {
+		return workingDir, nil
+	}
+	return workingDir, configFiles
+}
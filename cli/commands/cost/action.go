@@ -0,0 +1,100 @@
+// `cost` command estimates the monthly cost of the plan of the unit in the working dir: it plans the unit, converts the
+// plan to JSON with `terraform show -json`, and feeds it to the cost engine of the options, then writes a report of the
+// cost of the unit. With run-all, the reports of every unit are aggregated into a single report with the total cost.
+
+package cost
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	costestimate "github.com/gruntwork-io/terragrunt/cost"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+const (
+	// The plan and its JSON representation are written to the working directory of terraform, and removed once the
+	// cost is estimated.
+	costPlanFile     = "terragrunt-cost.tfplan"
+	costPlanJSONFile = "terragrunt-cost.tfplan.json"
+
+	terraformCommandPlan = "plan"
+)
+
+func Run(opts *options.TerragruntOptions) error {
+	if err := costestimate.ValidateFormat(opts.CostFormat); err != nil {
+		return err
+	}
+	engine, err := costestimate.NewEngine(opts)
+	if err != nil {
+		return err
+	}
+
+	// The unit is planned as with `terragrunt plan`, so that the extra_arguments of plan apply, but the output of the
+	// plan goes to stderr, so that stdout only has the report.
+	planOpts := opts.Clone(opts.TerragruntConfigPath)
+	planOpts.Writer = opts.ErrWriter
+	planOpts.TerraformCommand = terraformCommandPlan
+	planOpts.OriginalTerraformCommand = terraformCommandPlan
+	planOpts.TerraformCliArgs = []string{terraformCommandPlan, "-input=false", "-out=" + costPlanFile}
+
+	var estimate *costestimate.Estimate
+	target := terraform.NewTarget(terraform.TargetPointInitCommand, func(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+		unitEstimate, err := estimatePlanCost(opts, engine)
+		estimate = unitEstimate
+		return err
+	})
+	if err := terraform.RunWithTarget(planOpts, target); err != nil {
+		return err
+	}
+
+	// The callback doesn't run for the units skipped with skip = true or excluded from plan, whose cost is left out of
+	// the report.
+	if estimate == nil {
+		opts.Logger.Debugf("Leaving %s out of the cost report, as it was not planned", opts.TerragruntConfigPath)
+		return costestimate.NewReport(nil).Write(opts.Writer, opts.CostFormat)
+	}
+
+	unitPath, err := filepath.Rel(opts.WorkingDir, filepath.Dir(opts.TerragruntConfigPath))
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	report := costestimate.NewReport([]costestimate.UnitCost{{Path: filepath.ToSlash(unitPath), MonthlyCost: estimate.MonthlyCost, Currency: estimate.Currency}})
+	return report.Write(opts.Writer, opts.CostFormat)
+}
+
+// estimatePlanCost plans the unit in the working directory of the given options, whose args are the plan command, and
+// estimates the cost of the plan with the given engine.
+func estimatePlanCost(opts *options.TerragruntOptions, engine costestimate.Engine) (*costestimate.Estimate, error) {
+	planFile := filepath.Join(opts.WorkingDir, costPlanFile)
+	planJSONFile := filepath.Join(opts.WorkingDir, costPlanJSONFile)
+	defer removeCostFiles(opts, planFile, planJSONFile)
+
+	if err := shell.RunTerraformCommand(opts, opts.TerraformCliArgs...); err != nil {
+		return nil, err
+	}
+
+	out, err := shell.RunShellCommandWithOutput(opts, "", true, false, opts.TerraformPath, "show", "-json", costPlanFile)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(planJSONFile, []byte(out.Stdout), 0600); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	opts.Logger.Debugf("Estimating the cost of the plan %s", planJSONFile)
+	return engine.Estimate(opts, planJSONFile)
+}
+
+func removeCostFiles(opts *options.TerragruntOptions, files ...string) {
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			opts.Logger.Debugf("Failed to remove cost file %s: %v", file, err)
+		}
+	}
+}
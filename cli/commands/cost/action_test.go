@@ -0,0 +1,41 @@
+package cost
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	costestimate "github.com/gruntwork-io/terragrunt/cost"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestRunSkippedUnit(t *testing.T) {
+	t.Parallel()
+
+	unitDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, config.DefaultTerragruntConfigPath), []byte("skip = true\n"), 0644))
+
+	// The version of terraform is checked before the unit is skipped, so a stub stands in for it.
+	terraformPath := filepath.Join(unitDir, "terraform")
+	require.NoError(t, os.WriteFile(terraformPath, []byte("#!/bin/sh\necho 'Terraform v1.5.7'\n"), 0755))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(unitDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformPath = terraformPath
+	opts.CostFormat = costestimate.FormatJSON
+	var out bytes.Buffer
+	opts.Writer = &out
+
+	require.NoError(t, Run(opts))
+
+	var report costestimate.Report
+	require.NoError(t, json.Unmarshal(out.Bytes(), &report))
+	assert.Empty(t, report.Units)
+	assert.Zero(t, report.TotalMonthlyCost)
+}
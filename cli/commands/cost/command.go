@@ -0,0 +1,50 @@
+package cost
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "cost"
+
+	FlagNameTerragruntCostEngine = "terragrunt-cost-engine"
+	FlagNameTerragruntCostAPIURL = "terragrunt-cost-api-url"
+	FlagNameTerragruntCostFormat = "terragrunt-cost-format"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntCostEngine,
+			Aliases:     []string{"cost-engine"},
+			Destination: &opts.CostEngine,
+			EnvVar:      "TERRAGRUNT_COST_ENGINE",
+			Usage:       "The engine estimating the cost of the plans: infracost, to run the infracost CLI, or infracost-api, to post the plans to the API given with --terragrunt-cost-api-url. Defaults to infracost.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntCostAPIURL,
+			Aliases:     []string{"cost-api-url"},
+			Destination: &opts.CostAPIURL,
+			EnvVar:      "TERRAGRUNT_COST_API_URL",
+			Usage:       "The URL of the API the infracost-api cost engine posts the plans to.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntCostFormat,
+			Aliases:     []string{"cost-format"},
+			Destination: &opts.CostFormat,
+			EnvVar:      "TERRAGRUNT_COST_FORMAT",
+			Usage:       "The format of the cost report: text, json or html. Defaults to text.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Estimate the monthly cost of the plan of the unit, or of every unit with run-all cost.",
+		Description: "The plan of each unit is estimated by a cost engine, infracost by default, and the cost of every unit and the total cost are reported.",
+		Flags:       NewFlags(opts).Sort(),
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -0,0 +1,372 @@
+// `dependency gen-mocks` generates the mock_outputs of a dependency block from the output blocks of the module of the
+// dependency. Each output is mocked with a placeholder value of the type inferred from its value expression, and the
+// existing mocks of the outputs that are still in the module are kept, so that the mocks can be kept in sync with the
+// module by running the command again.
+
+package dependency
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	tr "github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const mockOutputsAttributeName = "mock_outputs"
+
+// The types of the values returned by the terraform functions that are commonly used in output values. The outputs set
+// to any other function call are mocked as strings.
+var functionTypes = map[string]cty.Type{
+	"tostring":     cty.String,
+	"format":       cty.String,
+	"join":         cty.String,
+	"lower":        cty.String,
+	"upper":        cty.String,
+	"trimspace":    cty.String,
+	"replace":      cty.String,
+	"jsonencode":   cty.String,
+	"yamlencode":   cty.String,
+	"base64encode": cty.String,
+	"tonumber":     cty.Number,
+	"length":       cty.Number,
+	"max":          cty.Number,
+	"min":          cty.Number,
+	"tobool":       cty.Bool,
+	"can":          cty.Bool,
+	"contains":     cty.Bool,
+	"alltrue":      cty.Bool,
+	"anytrue":      cty.Bool,
+	"tolist":       cty.List(cty.String),
+	"toset":        cty.List(cty.String),
+	"concat":       cty.List(cty.String),
+	"flatten":      cty.List(cty.String),
+	"compact":      cty.List(cty.String),
+	"distinct":     cty.List(cty.String),
+	"sort":         cty.List(cty.String),
+	"keys":         cty.List(cty.String),
+	"values":       cty.List(cty.String),
+	"split":        cty.List(cty.String),
+	"tomap":        cty.Map(cty.String),
+	"merge":        cty.Map(cty.String),
+	"zipmap":       cty.Map(cty.String),
+}
+
+// The suffixes of the names of the resource attributes that are lists, such as subnet_ids. The outputs set to any
+// other resource attribute are mocked as strings.
+var listAttributeSuffixes = []string{"ids", "arns", "names", "cidrs"}
+
+// RunGenMocks prints the mock_outputs of the given dependency block of the config, or writes them to the block when
+// the --write flag is set.
+func RunGenMocks(opts *options.TerragruntOptions, dependencyName string) error {
+	if dependencyName == "" {
+		return errors.WithStackTrace(MissingDependencyName{})
+	}
+
+	cfg, err := config.PartialParseConfigFile(opts.TerragruntConfigPath, opts, nil, []config.PartialDecodeSectionType{config.DependencyBlock})
+	if err != nil {
+		return err
+	}
+
+	var dependency *config.Dependency
+	for i := range cfg.TerragruntDependencies {
+		if cfg.TerragruntDependencies[i].Name == dependencyName {
+			dependency = &cfg.TerragruntDependencies[i]
+		}
+	}
+	if dependency == nil {
+		return errors.WithStackTrace(DependencyNotFound{Name: dependencyName, ConfigPath: opts.TerragruntConfigPath})
+	}
+
+	targetConfig, err := config.GetDependencyConfigPath(*dependency, opts.TerragruntConfigPath, opts)
+	if err != nil {
+		return err
+	}
+
+	modulePath, err := dependencyModulePath(opts, targetConfig)
+	if err != nil {
+		return err
+	}
+
+	outputTypes, err := moduleOutputTypes(modulePath)
+	if err != nil {
+		return err
+	}
+
+	mockOutputs := generateMockOutputs(opts, outputTypes, dependency.MockOutputs)
+
+	if opts.DependencyGenMocksWrite {
+		return writeMockOutputs(opts, dependencyName, mockOutputs)
+	}
+
+	file := hclwrite.NewEmptyFile()
+	file.Body().SetAttributeValue(mockOutputsAttributeName, mockOutputs)
+	if _, err := opts.Writer.Write(hclwrite.Format(file.Bytes())); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// dependencyModulePath returns the path of the terraform module of the given dependency config. The modules with a
+// remote source must have been downloaded by running terragrunt in the dependency.
+func dependencyModulePath(opts *options.TerragruntOptions, targetConfig string) (string, error) {
+	targetOpts := opts.Clone(targetConfig)
+	targetDir := filepath.Dir(targetConfig)
+
+	targetCfg, err := config.PartialParseConfigFile(targetConfig, targetOpts, nil, []config.PartialDecodeSectionType{config.TerraformSource})
+	if err != nil {
+		return "", err
+	}
+
+	source, err := config.GetTerragruntSourceForModule(opts.Source, targetDir, targetCfg)
+	if err != nil {
+		return "", err
+	}
+	if source == "" {
+		if targetCfg.Terraform == nil || targetCfg.Terraform.Source == nil || *targetCfg.Terraform.Source == "" {
+			return targetDir, nil
+		}
+		source = *targetCfg.Terraform.Source
+	}
+
+	_, downloadDir, err := options.DefaultWorkingAndDownloadDirs(targetConfig)
+	if err != nil {
+		return "", err
+	}
+
+	terraformSource, err := tr.NewSource(source, downloadDir, targetDir, opts.Logger)
+	if err != nil {
+		return "", err
+	}
+
+	// The local sources are read directly, as the downloaded copy may be outdated.
+	if tr.IsLocalSource(terraformSource.CanonicalSourceURL) {
+		modulePath, err := filepath.Rel(terraformSource.DownloadDir, terraformSource.WorkingDir)
+		if err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		return util.JoinPath(terraformSource.CanonicalSourceURL.Path, modulePath), nil
+	}
+
+	if !util.IsDir(terraformSource.WorkingDir) {
+		return "", errors.WithStackTrace(ModuleNotDownloaded{ConfigPath: targetConfig, Source: source})
+	}
+
+	return terraformSource.WorkingDir, nil
+}
+
+// moduleOutputTypes returns the types of the outputs of the given module, inferred from their value expressions. The
+// outputs whose type can't be inferred, such as the ones defined in json files, are typed as strings.
+func moduleOutputTypes(modulePath string) (map[string]cty.Type, error) {
+	module, diags := tfconfig.LoadModule(modulePath)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+
+	outputTypes := map[string]cty.Type{}
+	parser := hclparse.NewParser()
+
+	for name, output := range module.Outputs {
+		outputTypes[name] = cty.String
+
+		if !strings.HasSuffix(output.Pos.Filename, ".tf") {
+			continue
+		}
+
+		file, diags := parser.ParseHCLFile(output.Pos.Filename)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+
+		for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+			if block.Type != "output" || len(block.Labels) != 1 || block.Labels[0] != name {
+				continue
+			}
+			if value, ok := block.Body.Attributes["value"]; ok {
+				outputTypes[name] = expressionType(value.Expr)
+			}
+		}
+	}
+
+	return outputTypes, nil
+}
+
+// expressionType returns the type the given expression likely evaluates to, without evaluating it, as the resources
+// and variables it references are not known. It defaults to a string, which is the type of most resource attributes.
+func expressionType(expr hclsyntax.Expression) cty.Type {
+	switch expr := expr.(type) {
+	case *hclsyntax.LiteralValueExpr:
+		if expr.Val.Type().IsPrimitiveType() {
+			return expr.Val.Type()
+		}
+	case *hclsyntax.TemplateWrapExpr:
+		return expressionType(expr.Wrapped)
+	case *hclsyntax.ParenthesesExpr:
+		return expressionType(expr.Expression)
+	case *hclsyntax.ConditionalExpr:
+		return expressionType(expr.TrueResult)
+	case *hclsyntax.BinaryOpExpr:
+		return expr.Op.Type
+	case *hclsyntax.UnaryOpExpr:
+		return expr.Op.Type
+	case *hclsyntax.SplatExpr:
+		return cty.List(cty.String)
+	case *hclsyntax.TupleConsExpr:
+		if len(expr.Exprs) > 0 {
+			return cty.List(expressionType(expr.Exprs[0]))
+		}
+		return cty.List(cty.String)
+	case *hclsyntax.ForExpr:
+		if expr.KeyExpr != nil {
+			return cty.Map(expressionType(expr.ValExpr))
+		}
+		return cty.List(expressionType(expr.ValExpr))
+	case *hclsyntax.ObjectConsExpr:
+		return objectConsType(expr)
+	case *hclsyntax.FunctionCallExpr:
+		if functionType, ok := functionTypes[expr.Name]; ok {
+			return functionType
+		}
+		// These functions return one of their arguments, e.g. try(aws_instance.main.id, "").
+		if (expr.Name == "try" || expr.Name == "coalesce" || expr.Name == "one") && len(expr.Args) > 0 {
+			return expressionType(expr.Args[0])
+		}
+	case *hclsyntax.ScopeTraversalExpr:
+		return traversalType(expr.Traversal)
+	case *hclsyntax.RelativeTraversalExpr:
+		return traversalType(expr.Traversal)
+	}
+
+	return cty.String
+}
+
+// objectConsType returns the object type of the given object expression, or a map of strings if its keys are not
+// static.
+func objectConsType(expr *hclsyntax.ObjectConsExpr) cty.Type {
+	attributeTypes := map[string]cty.Type{}
+	for _, item := range expr.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.Type() != cty.String || !key.IsKnown() || key.IsNull() {
+			return cty.Map(cty.String)
+		}
+		attributeTypes[key.AsString()] = expressionType(item.ValueExpr)
+	}
+	return cty.Object(attributeTypes)
+}
+
+// traversalType returns the type of the resource attribute or variable referenced by the given traversal, guessed from
+// its name.
+func traversalType(traversal hcl.Traversal) cty.Type {
+	for i := len(traversal) - 1; i >= 0; i-- {
+		attribute, ok := traversal[i].(hcl.TraverseAttr)
+		if !ok {
+			continue
+		}
+		for _, suffix := range listAttributeSuffixes {
+			if strings.HasSuffix(attribute.Name, suffix) {
+				return cty.List(cty.String)
+			}
+		}
+		break
+	}
+	return cty.String
+}
+
+// generateMockOutputs returns the mock outputs of the given outputs, keeping the existing mocks of the outputs that are
+// still in the module.
+func generateMockOutputs(opts *options.TerragruntOptions, outputTypes map[string]cty.Type, existingMocks *cty.Value) cty.Value {
+	mocks := map[string]cty.Value{}
+	for name, outputType := range outputTypes {
+		mocks[name] = placeholderValue(name, outputType)
+	}
+
+	if existingMocks != nil && existingMocks.IsWhollyKnown() && !existingMocks.IsNull() && existingMocks.CanIterateElements() {
+		for it := existingMocks.ElementIterator(); it.Next(); {
+			key, value := it.Element()
+			name := key.AsString()
+			if _, isOutput := outputTypes[name]; !isOutput {
+				opts.Logger.Infof("Removing the mock of the output %s, which is not in the module of the dependency anymore", name)
+				continue
+			}
+			mocks[name] = value
+		}
+	}
+
+	return cty.ObjectVal(mocks)
+}
+
+// placeholderValue returns the placeholder value of the output with the given name and type.
+func placeholderValue(name string, valueType cty.Type) cty.Value {
+	switch {
+	case valueType == cty.Number:
+		return cty.NumberIntVal(0)
+	case valueType == cty.Bool:
+		return cty.False
+	case valueType.IsListType():
+		return cty.TupleVal([]cty.Value{placeholderValue(name, valueType.ElementType())})
+	case valueType.IsMapType():
+		return cty.EmptyObjectVal
+	case valueType.IsObjectType():
+		attributes := map[string]cty.Value{}
+		for attributeName, attributeType := range valueType.AttributeTypes() {
+			attributes[attributeName] = placeholderValue(attributeName, attributeType)
+		}
+		return cty.ObjectVal(attributes)
+	default:
+		return cty.StringVal(fmt.Sprintf("mock-%s", name))
+	}
+}
+
+// writeMockOutputs sets the mock_outputs of the given dependency block of the config, keeping the rest of the config
+// as is.
+func writeMockOutputs(opts *options.TerragruntOptions, dependencyName string, mockOutputs cty.Value) error {
+	configPath := opts.TerragruntConfigPath
+
+	stat, err := os.Stat(configPath)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	file, diags := hclwrite.ParseConfig(contents, configPath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return errors.WithStackTrace(diags)
+	}
+
+	var dependencyBlock *hclwrite.Block
+	for _, block := range file.Body().Blocks() {
+		if block.Type() == config.MetadataDependency && len(block.Labels()) == 1 && block.Labels()[0] == dependencyName {
+			dependencyBlock = block
+		}
+	}
+	if dependencyBlock == nil {
+		return errors.WithStackTrace(DependencyBlockNotInConfig{Name: dependencyName, ConfigPath: configPath})
+	}
+
+	dependencyBlock.Body().SetAttributeValue(mockOutputsAttributeName, mockOutputs)
+
+	if err := os.WriteFile(configPath, hclwrite.Format(file.Bytes()), stat.Mode()); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	opts.Logger.Infof("Wrote the mock outputs of the dependency %s to %s", dependencyName, configPath)
+
+	return nil
+}
@@ -0,0 +1,156 @@
+package dependency
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const testModule = `
+output "vpc_id" {
+  value = aws_vpc.main.id
+}
+
+output "subnet_ids" {
+  value = aws_subnet.private[*].id
+}
+
+output "az_count" {
+  value = length(var.azs)
+}
+
+output "endpoint" {
+  value = {
+    host = aws_lb.main.dns_name
+    port = 443
+  }
+}
+`
+
+// writeTestConfigs writes a vpc module, along with a vpc config using it and an app config depending on the vpc config,
+// and returns the options of the app config.
+func writeTestConfigs(t *testing.T, appConfig string) *options.TerragruntOptions {
+	t.Helper()
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"modules/vpc/main.tf":     testModule,
+		"live/vpc/terragrunt.hcl": `terraform { source = "../../modules/vpc" }`,
+		"live/app/terragrunt.hcl": appConfig,
+	}
+	for path, contents := range files {
+		path = filepath.Join(rootDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "live", "app", "terragrunt.hcl"))
+	require.NoError(t, err)
+	return opts
+}
+
+func TestRunGenMocksPrintsMockOutputs(t *testing.T) {
+	t.Parallel()
+
+	opts := writeTestConfigs(t, `
+dependency "vpc" {
+  config_path = "../vpc"
+
+  mock_outputs = {
+    vpc_id  = "vpc-1234"
+    removed = "foo"
+  }
+}
+`)
+	var stdout bytes.Buffer
+	opts.Writer = &stdout
+
+	require.NoError(t, RunGenMocks(opts, "vpc"))
+
+	expected := `mock_outputs = {
+  az_count = 0
+  endpoint = {
+    host = "mock-host"
+    port = 0
+  }
+  subnet_ids = ["mock-subnet_ids"]
+  vpc_id     = "vpc-1234"
+}
+`
+	assert.Equal(t, expected, stdout.String())
+}
+
+func TestRunGenMocksWritesMockOutputs(t *testing.T) {
+	t.Parallel()
+
+	opts := writeTestConfigs(t, `
+dependency "vpc" {
+  config_path = "../vpc"
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`)
+	opts.DependencyGenMocksWrite = true
+
+	require.NoError(t, RunGenMocks(opts, "vpc"))
+
+	contents, err := os.ReadFile(opts.TerragruntConfigPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), `vpc_id     = "mock-vpc_id"`)
+	assert.Contains(t, string(contents), `vpc_id = dependency.vpc.outputs.vpc_id`)
+}
+
+func TestRunGenMocksUnknownDependency(t *testing.T) {
+	t.Parallel()
+
+	opts := writeTestConfigs(t, `
+dependency "vpc" {
+  config_path = "../vpc"
+}
+`)
+
+	err := RunGenMocks(opts, "mysql")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Found no dependency block named mysql")
+}
+
+func TestExpressionType(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		expr     string
+		expected cty.Type
+	}{
+		{`aws_vpc.main.id`, cty.String},
+		{`aws_vpc.main.cidr_block`, cty.String},
+		{`module.vpc.private_subnet_ids`, cty.List(cty.String)},
+		{`"${aws_vpc.main.id}"`, cty.String},
+		{`"arn:${aws_vpc.main.id}"`, cty.String},
+		{`42`, cty.Number},
+		{`true`, cty.Bool},
+		{`var.count > 1`, cty.Bool},
+		{`[for s in aws_subnet.main : s.id]`, cty.List(cty.String)},
+		{`{for s in aws_subnet.main : s.id => s.cidr_block}`, cty.Map(cty.String)},
+		{`merge(var.tags, {})`, cty.Map(cty.String)},
+		{`try(length(var.azs), 0)`, cty.Number},
+		{`var.enabled ? 1 : 0`, cty.Number},
+		{`{ (var.key) = "value" }`, cty.Map(cty.String)},
+	}
+
+	for _, testCase := range testCases {
+		expr, diags := hclsyntax.ParseExpression([]byte(testCase.expr), "test.tf", hcl.InitialPos)
+		require.False(t, diags.HasErrors(), diags.Error())
+		assert.True(t, testCase.expected.Equals(expressionType(expr)), "%s: expected %s, got %s", testCase.expr, testCase.expected.FriendlyName(), expressionType(expr).FriendlyName())
+	}
+}
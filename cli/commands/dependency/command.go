@@ -0,0 +1,57 @@
+package dependency
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName         = "dependency"
+	CommandNameGenMocks = "gen-mocks"
+	CommandNameDiscover = "discover"
+
+	FlagNameWrite = "write"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Manage the dependency blocks of the config, such as generating their mock outputs with `dependency gen-mocks`.",
+		Subcommands: cli.Commands{newGenMocksCommand(opts), newDiscoverCommand(opts)},
+		Action:      func(ctx *cli.Context) error { return errors.WithStackTrace(MissingSubcommand{}) },
+	}
+}
+
+func newGenMocksCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameGenMocks,
+		Usage:       "Generate the mock_outputs of a dependency block from the output blocks of the module of the dependency, e.g. `terragrunt dependency gen-mocks vpc`.",
+		Description: "Each output is mocked with a placeholder value of the type inferred from its value expression. The existing mock outputs of the outputs that are still in the module are kept, so that running the command again keeps the mocks in sync with the module. The module must be a local source, or have been downloaded by running terragrunt in the dependency.",
+		Flags: cli.Flags{
+			&cli.BoolFlag{
+				Name:        FlagNameWrite,
+				Destination: &opts.DependencyGenMocksWrite,
+				Usage:       "Write the mock_outputs to the dependency block of the config, rather than printing them.",
+			},
+		},
+		Action: func(ctx *cli.Context) error { return RunGenMocks(opts.OptionsFromContext(ctx), ctx.Args().First()) },
+	}
+}
+
+func newDiscoverCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameDiscover,
+		Usage:       "Find the modules whose state is read by the terraform_remote_state data sources of the module, but that are not declared in the dependencies block of the config.",
+		Description: "The modules are searched for in the git repository of the working dir, and matched by the location of the state set in their remote_state block. The data sources whose config references variables or other values that are only known when running terraform are skipped.",
+		Flags: cli.Flags{
+			&cli.BoolFlag{
+				Name:        FlagNameWrite,
+				Destination: &opts.DependencyDiscoverWrite,
+				Usage:       "Add the discovered dependencies to the dependencies block of the config, rather than printing them.",
+			},
+		},
+		Action: func(ctx *cli.Context) error { return RunDiscover(opts.OptionsFromContext(ctx)) },
+	}
+}
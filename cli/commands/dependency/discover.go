@@ -0,0 +1,177 @@
+// `dependency discover` finds the modules whose state is read by the terraform_remote_state data sources of the module
+// of the config, but that are not declared as its dependencies, so that the run order of run-all stays accurate. The
+// discovered dependencies are printed as a dependencies block, or added to the dependencies block of the config.
+
+package dependency
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const (
+	dependenciesBlockName = "dependencies"
+	pathsAttributeName    = "paths"
+)
+
+// RunDiscover prints the dependencies block declaring the modules whose state is read by the terraform_remote_state
+// data sources of the module, or adds them to the dependencies block of the config when the --write flag is set.
+func RunDiscover(opts *options.TerragruntOptions) error {
+	cfg, err := config.PartialParseConfigFile(opts.TerragruntConfigPath, opts, nil, []config.PartialDecodeSectionType{config.DependenciesBlock, config.DependencyBlock})
+	if err != nil {
+		return err
+	}
+
+	modulePath, err := dependencyModulePath(opts, opts.TerragruntConfigPath)
+	if err != nil {
+		return err
+	}
+
+	undeclared, err := configstack.FindUndeclaredRemoteStateDependencies(opts, modulePath, cfg)
+	if err != nil {
+		return err
+	}
+	if len(undeclared) == 0 {
+		opts.Logger.Infof("The modules whose state is read by the terraform_remote_state data sources of %s are all declared as dependencies", opts.TerragruntConfigPath)
+		return nil
+	}
+
+	configDir := filepath.Dir(opts.TerragruntConfigPath)
+	paths := []string{}
+	for _, dependency := range undeclared {
+		path, err := filepath.Rel(configDir, dependency.ModulePath)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		path = filepath.ToSlash(path)
+		opts.Logger.Infof("%s reads the state of %s at %s, which is not declared as a dependency", dependency.DataSource, path, dependency.Location)
+
+		if !containsPath(paths, path) {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+
+	if opts.DependencyDiscoverWrite {
+		return writeDependenciesPaths(opts, paths)
+	}
+
+	file := hclwrite.NewEmptyFile()
+	file.Body().AppendNewBlock(dependenciesBlockName, nil).Body().SetAttributeValue(pathsAttributeName, stringListValue(paths))
+	if _, err := opts.Writer.Write(hclwrite.Format(file.Bytes())); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// writeDependenciesPaths adds the given paths to the dependencies block of the config, which is created if there is
+// none, keeping the rest of the config as is.
+func writeDependenciesPaths(opts *options.TerragruntOptions, paths []string) error {
+	configPath := opts.TerragruntConfigPath
+
+	stat, err := os.Stat(configPath)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	file, diags := hclwrite.ParseConfig(contents, configPath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return errors.WithStackTrace(diags)
+	}
+
+	dependenciesBlock := file.Body().FirstMatchingBlock(dependenciesBlockName, nil)
+	if dependenciesBlock == nil {
+		file.Body().AppendNewline()
+		dependenciesBlock = file.Body().AppendNewBlock(dependenciesBlockName, nil)
+	}
+
+	existingPaths := []string{}
+	if attr := dependenciesBlock.Body().GetAttribute(pathsAttributeName); attr != nil {
+		existingPaths, err = staticStringList(attr.Expr().BuildTokens(nil).Bytes())
+		if err != nil {
+			return errors.WithStackTrace(DependenciesPathsNotStatic{ConfigPath: configPath})
+		}
+	}
+	for _, path := range paths {
+		if !containsPath(existingPaths, path) {
+			existingPaths = append(existingPaths, path)
+		}
+	}
+
+	dependenciesBlock.Body().SetAttributeValue(pathsAttributeName, stringListValue(existingPaths))
+
+	if err := os.WriteFile(configPath, hclwrite.Format(file.Bytes()), stat.Mode()); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	opts.Logger.Infof("Added %v to the dependencies block of %s", paths, configPath)
+
+	return nil
+}
+
+// staticStringList returns the strings of the given list expression, which must not reference anything.
+func staticStringList(expression []byte) ([]string, error) {
+	expr, diags := hclsyntax.ParseExpression(expression, "", hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+
+	list := []string{}
+	if value.IsNull() {
+		return list, nil
+	}
+	if !value.CanIterateElements() {
+		return nil, fmt.Errorf("not a list of strings")
+	}
+	for _, element := range value.AsValueSlice() {
+		if element.Type() != cty.String || element.IsNull() {
+			return nil, fmt.Errorf("not a list of strings")
+		}
+		list = append(list, element.AsString())
+	}
+	return list, nil
+}
+
+// stringListValue returns the given strings as a list value, which is written as ["a", "b"].
+func stringListValue(list []string) cty.Value {
+	if len(list) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	values := []cty.Value{}
+	for _, str := range list {
+		values = append(values, cty.StringVal(str))
+	}
+	return cty.ListVal(values)
+}
+
+// containsPath returns true if the given paths contain the given path, ignoring the trailing slashes.
+func containsPath(paths []string, path string) bool {
+	for _, existing := range paths {
+		if filepath.Clean(existing) == filepath.Clean(path) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,136 @@
+package dependency
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const testRemoteStateConfig = `
+remote_state {
+  backend = "gcs"
+  config = {
+    bucket = "my-state"
+    prefix = "%s"
+  }
+}
+`
+
+const testRemoteStateModule = `
+data "terraform_remote_state" "vpc" {
+  backend = "gcs"
+  config = {
+    bucket = "my-state"
+    prefix = "vpc"
+  }
+}
+
+data "terraform_remote_state" "mysql" {
+  backend = "gcs"
+  config = {
+    bucket = "my-state"
+    prefix = "mysql"
+  }
+}
+`
+
+// writeDiscoverTestConfigs writes a vpc, a mysql and an app module reading the state of both, along with the given
+// config of the app, and returns the options of the app config.
+func writeDiscoverTestConfigs(t *testing.T, appConfig string) *options.TerragruntOptions {
+	t.Helper()
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"vpc/terragrunt.hcl":   fmt.Sprintf(testRemoteStateConfig, "vpc"),
+		"mysql/terragrunt.hcl": fmt.Sprintf(testRemoteStateConfig, "mysql"),
+		"app/main.tf":          testRemoteStateModule,
+		"app/terragrunt.hcl":   appConfig,
+	}
+	for path, contents := range files {
+		path = filepath.Join(rootDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "app", "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = filepath.Join(rootDir, "app")
+	return opts
+}
+
+func TestRunDiscoverPrintsDependencies(t *testing.T) {
+	t.Parallel()
+
+	opts := writeDiscoverTestConfigs(t, `
+dependency "mysql" {
+  config_path = "../mysql"
+}
+`)
+
+	var out bytes.Buffer
+	opts.Writer = &out
+	require.NoError(t, RunDiscover(opts))
+
+	assert.Equal(t, "dependencies {\n  paths = [\"../vpc\"]\n}\n", out.String())
+}
+
+func TestRunDiscoverWritesDependencies(t *testing.T) {
+	t.Parallel()
+
+	opts := writeDiscoverTestConfigs(t, `inputs = {
+  name = "app"
+}
+
+dependencies {
+  paths = ["../dns"]
+}
+`)
+	opts.DependencyDiscoverWrite = true
+	require.NoError(t, RunDiscover(opts))
+
+	contents, err := os.ReadFile(opts.TerragruntConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, `inputs = {
+  name = "app"
+}
+
+dependencies {
+  paths = ["../dns", "../mysql", "../vpc"]
+}
+`, string(contents))
+
+	// Running again finds nothing to add.
+	require.NoError(t, RunDiscover(opts))
+	unchanged, err := os.ReadFile(opts.TerragruntConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, string(contents), string(unchanged))
+}
+
+func TestRunDiscoverCreatesDependenciesBlock(t *testing.T) {
+	t.Parallel()
+
+	opts := writeDiscoverTestConfigs(t, `inputs = {
+  name = "app"
+}
+`)
+	opts.DependencyDiscoverWrite = true
+	require.NoError(t, RunDiscover(opts))
+
+	contents, err := os.ReadFile(opts.TerragruntConfigPath)
+	require.NoError(t, err)
+	assert.Equal(t, `inputs = {
+  name = "app"
+}
+
+dependencies {
+  paths = ["../mysql", "../vpc"]
+}
+`, string(contents))
+}
@@ -0,0 +1,52 @@
+package dependency
+
+import (
+	"fmt"
+)
+
+type MissingSubcommand struct{}
+
+func (err MissingSubcommand) Error() string {
+	return fmt.Sprintf("Missing %s subcommand, e.g. `terragrunt %s %s vpc`.", CommandName, CommandName, CommandNameGenMocks)
+}
+
+type MissingDependencyName struct{}
+
+func (err MissingDependencyName) Error() string {
+	return fmt.Sprintf("Missing the name of the dependency block, e.g. `terragrunt %s %s vpc`.", CommandName, CommandNameGenMocks)
+}
+
+type DependencyNotFound struct {
+	Name       string
+	ConfigPath string
+}
+
+func (err DependencyNotFound) Error() string {
+	return fmt.Sprintf("Found no dependency block named %s in %s.", err.Name, err.ConfigPath)
+}
+
+type DependencyBlockNotInConfig struct {
+	Name       string
+	ConfigPath string
+}
+
+func (err DependencyBlockNotInConfig) Error() string {
+	return fmt.Sprintf("The dependency block %s is not defined in %s, but in a config it includes. Add the mock_outputs to the included config instead, or run without --%s to print them.", err.Name, err.ConfigPath, FlagNameWrite)
+}
+
+type ModuleNotDownloaded struct {
+	ConfigPath string
+	Source     string
+}
+
+func (err ModuleNotDownloaded) Error() string {
+	return fmt.Sprintf("The module %s of %s has not been downloaded. Run `terragrunt init` in the directory of %s first.", err.Source, err.ConfigPath, err.ConfigPath)
+}
+
+type DependenciesPathsNotStatic struct {
+	ConfigPath string
+}
+
+func (err DependenciesPathsNotStatic) Error() string {
+	return fmt.Sprintf("The paths of the dependencies block of %s can not be evaluated without running terragrunt. Add the discovered dependencies to it manually, or run without --%s to print them.", err.ConfigPath, FlagNameWrite)
+}
@@ -0,0 +1,277 @@
+// `docs` command generates Markdown documentation for every unit of the stack in the working dir: a page per unit, with
+// its terraform source and version, its backend, its dependencies, and its inputs with their values and the files they
+// are set in, along with an index of the units and a diagram of their dependencies.
+
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// DefaultOutDir is the directory the documentation is written to when --terragrunt-docs-out is not passed.
+	DefaultOutDir = "terragrunt-docs"
+
+	indexFile = "index.md"
+	unitFile  = "README.md"
+)
+
+// unitDoc is what is documented about a unit. The paths are relative to the working dir.
+type unitDoc struct {
+	Path          string
+	Source        string
+	SourceVersion string
+	Backend       string
+	// The names of the settings of the backend, whose values, such as credentials, are not documented.
+	BackendConfigNames []string
+	Dependencies       []string
+	Inputs             []inputDoc
+	// The error reading the full config of the unit, e.g. because the outputs of a dependency are not available, in
+	// which case only its source and dependencies are documented.
+	ReadError string
+}
+
+// inputDoc is an input of a unit, with its value as JSON and the file it is set in, relative to the unit.
+type inputDoc struct {
+	Name   string
+	Value  string
+	Source string
+}
+
+func Run(opts *options.TerragruntOptions) error {
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	outDir := opts.DocsOutDir
+	if outDir == "" {
+		outDir = DefaultOutDir
+	}
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(opts.WorkingDir, outDir)
+	}
+
+	docs := []*unitDoc{}
+	for _, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+		docs = append(docs, newUnitDoc(opts, module))
+	}
+	sort.Slice(docs, func(i, j int) bool { return docs[i].Path < docs[j].Path })
+
+	for _, doc := range docs {
+		if err := writeFile(filepath.Join(outDir, filepath.FromSlash(doc.Path), unitFile), doc.markdown()); err != nil {
+			return err
+		}
+	}
+	if err := writeFile(filepath.Join(outDir, indexFile), indexMarkdown(docs)); err != nil {
+		return err
+	}
+
+	opts.Logger.Infof("Wrote the documentation of %d units to %s", len(docs), outDir)
+	return nil
+}
+
+// newUnitDoc returns the documentation of the given module of the stack. The full config of the module is read to
+// document its inputs and backend, which resolves the outputs of its dependencies, as other commands do.
+func newUnitDoc(opts *options.TerragruntOptions, module *configstack.TerraformModule) *unitDoc {
+	doc := &unitDoc{Path: util.GetPathRelativeToIfInside(module.Path, opts.WorkingDir), Dependencies: []string{}, Inputs: []inputDoc{}}
+	for _, dependency := range module.Dependencies {
+		doc.Dependencies = append(doc.Dependencies, util.GetPathRelativeToIfInside(dependency.Path, opts.WorkingDir))
+	}
+	sort.Strings(doc.Dependencies)
+
+	if module.Config.Terraform != nil && module.Config.Terraform.Source != nil {
+		doc.Source = *module.Config.Terraform.Source
+		doc.SourceVersion = sourceVersion(doc.Source)
+	}
+
+	cfg, err := config.ReadTerragruntConfig(module.TerragruntOptions)
+	if err != nil {
+		opts.Logger.Warnf("Documenting unit %s without its inputs and backend, as its config could not be read: %v", module.Path, err)
+		doc.ReadError = err.Error()
+		return doc
+	}
+
+	if cfg.RemoteState != nil {
+		doc.Backend = cfg.RemoteState.Backend
+		for name := range cfg.RemoteState.Config {
+			doc.BackendConfigNames = append(doc.BackendConfigNames, name)
+		}
+		sort.Strings(doc.BackendConfigNames)
+	}
+
+	for name, value := range cfg.Inputs {
+		valueJSON, err := json.Marshal(value)
+		if err != nil {
+			valueJSON = []byte(fmt.Sprintf("%v", value))
+		}
+		input := inputDoc{Name: name, Value: util.RedactSecrets(string(valueJSON))}
+		if metadata, found := cfg.GetMapFieldMetadata(config.MetadataInputs, name); found {
+			if sourceFile, err := filepath.Rel(module.Path, metadata[config.FoundInFile]); err == nil {
+				input.Source = filepath.ToSlash(sourceFile)
+			}
+		}
+		doc.Inputs = append(doc.Inputs, input)
+	}
+	sort.Slice(doc.Inputs, func(i, j int) bool { return doc.Inputs[i].Name < doc.Inputs[j].Name })
+	return doc
+}
+
+// markdown returns the page of the unit.
+func (doc *unitDoc) markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# %s\n\n", doc.Path)
+
+	switch {
+	case doc.Source == "":
+		sb.WriteString("**Source**: the terraform code in the directory of the unit\n\n")
+	case doc.SourceVersion == "":
+		fmt.Fprintf(&sb, "**Source**: `%s`\n\n", doc.Source)
+	default:
+		fmt.Fprintf(&sb, "**Source**: `%s` (version `%s`)\n\n", doc.Source, doc.SourceVersion)
+	}
+
+	if doc.ReadError != "" {
+		fmt.Fprintf(&sb, "> The config of the unit could not be read, so its inputs and backend are not documented: %s\n\n", markdownCell(doc.ReadError))
+	}
+
+	sb.WriteString("## Dependencies\n\n")
+	if len(doc.Dependencies) == 0 {
+		sb.WriteString("None.\n\n")
+	}
+	for _, dependency := range doc.Dependencies {
+		if isOutside(dependency) {
+			fmt.Fprintf(&sb, "- %s\n", dependency)
+			continue
+		}
+		// The page of the dependency is in the folder of the dependency, relative to the folder of this page.
+		link, err := filepath.Rel(filepath.FromSlash(doc.Path), filepath.FromSlash(filepath.Join(dependency, unitFile)))
+		if err != nil {
+			link = filepath.Join(dependency, unitFile)
+		}
+		fmt.Fprintf(&sb, "- [%s](%s)\n", dependency, filepath.ToSlash(link))
+	}
+	if len(doc.Dependencies) > 0 {
+		sb.WriteString("\n")
+	}
+
+	if doc.ReadError != "" {
+		return sb.String()
+	}
+
+	sb.WriteString("## Backend\n\n")
+	if doc.Backend == "" {
+		sb.WriteString("None.\n\n")
+	} else {
+		fmt.Fprintf(&sb, "**Type**: `%s`\n\n", doc.Backend)
+		if len(doc.BackendConfigNames) > 0 {
+			sb.WriteString("**Settings**:")
+			for i, name := range doc.BackendConfigNames {
+				if i > 0 {
+					sb.WriteString(",")
+				}
+				fmt.Fprintf(&sb, " `%s`", name)
+			}
+			sb.WriteString("\n\n")
+		}
+	}
+
+	sb.WriteString("## Inputs\n\n")
+	if len(doc.Inputs) == 0 {
+		sb.WriteString("None.\n")
+		return sb.String()
+	}
+	sb.WriteString("| Name | Value | Set in |\n| --- | --- | --- |\n")
+	for _, input := range doc.Inputs {
+		fmt.Fprintf(&sb, "| `%s` | `%s` | %s |\n", input.Name, markdownCell(input.Value), markdownCell(input.Source))
+	}
+	return sb.String()
+}
+
+// indexMarkdown returns the index of the units, with a Mermaid diagram of their dependencies, where each unit points
+// to the units it depends on.
+func indexMarkdown(docs []*unitDoc) string {
+	var sb strings.Builder
+	sb.WriteString("# Units\n\n")
+	sb.WriteString("| Unit | Source | Version |\n| --- | --- | --- |\n")
+	for _, doc := range docs {
+		source := "local"
+		if doc.Source != "" {
+			source = "`" + markdownCell(doc.Source) + "`"
+		}
+		fmt.Fprintf(&sb, "| [%s](%s) | %s | %s |\n", doc.Path, filepath.ToSlash(filepath.Join(doc.Path, unitFile)), source, doc.SourceVersion)
+	}
+
+	sb.WriteString("\n## Dependencies\n\n```mermaid\ngraph TD\n")
+	ids := map[string]string{}
+	nodeID := func(path string) string {
+		if id, found := ids[path]; found {
+			return id
+		}
+		ids[path] = fmt.Sprintf("unit%d", len(ids))
+		fmt.Fprintf(&sb, "  %s[\"%s\"]\n", ids[path], strings.ReplaceAll(path, `"`, "#quot;"))
+		return ids[path]
+	}
+	for _, doc := range docs {
+		nodeID(doc.Path)
+	}
+	for _, doc := range docs {
+		for _, dependency := range doc.Dependencies {
+			fmt.Fprintf(&sb, "  %s --> %s\n", nodeID(doc.Path), nodeID(dependency))
+		}
+	}
+	sb.WriteString("```\n")
+	return sb.String()
+}
+
+// sourceVersion returns the version the given terraform source points to: the ref of a git source, or the version of a
+// registry source, if any.
+func sourceVersion(source string) string {
+	_, query, found := strings.Cut(source, "?")
+	if !found {
+		return ""
+	}
+	values, err := url.ParseQuery(query)
+	if err != nil {
+		return ""
+	}
+	if ref := values.Get("ref"); ref != "" {
+		return ref
+	}
+	return values.Get("version")
+}
+
+func isOutside(path string) bool {
+	return filepath.IsAbs(filepath.FromSlash(path))
+}
+
+// markdownCell escapes the given text to be written in a cell of a Markdown table.
+func markdownCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+}
+
+func writeFile(path string, contents string) error {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(path, []byte(contents), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
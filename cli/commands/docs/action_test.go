@@ -0,0 +1,107 @@
+package docs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSourceVersion(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		source   string
+		expected string
+	}{
+		{"git::https://example.com/modules.git//vpc?ref=v1.2.0", "v1.2.0"},
+		{"tfr:///terraform-aws-modules/vpc/aws?version=5.0.0", "5.0.0"},
+		{"github.com/org/modules//vpc?depth=1&ref=main", "main"},
+		{"../modules/vpc", ""},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, sourceVersion(testCase.source), testCase.source)
+	}
+}
+
+func TestRunWritesUnitDocs(t *testing.T) {
+	t.Parallel()
+
+	stackDir := t.TempDir()
+	writeConfig := func(path string, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+	writeConfig(filepath.Join(stackDir, "root.hcl"), `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "${path_relative_to_include()}/terraform.tfstate"
+  }
+}
+
+inputs = {
+  region = "us-east-1"
+}
+`)
+	writeConfig(filepath.Join(stackDir, "vpc", config.DefaultTerragruntConfigPath), `
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+terraform {
+  source = "git::https://example.com/modules.git//vpc?ref=v1.2.0"
+}
+`)
+	writeConfig(filepath.Join(stackDir, "app", config.DefaultTerragruntConfigPath), `
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+dependency "vpc" {
+  config_path  = "../vpc"
+  skip_outputs = true
+  mock_outputs = {
+    vpc_id = "vpc-mock"
+  }
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`)
+	writeConfig(filepath.Join(stackDir, "app", "main.tf"), `variable "vpc_id" {}`)
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = stackDir
+	opts.DocsOutDir = "out"
+
+	require.NoError(t, Run(opts))
+
+	appDoc, err := os.ReadFile(filepath.Join(stackDir, "out", "app", unitFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(appDoc), "**Source**: the terraform code in the directory of the unit")
+	assert.Contains(t, string(appDoc), "- [vpc](../vpc/README.md)")
+	assert.Contains(t, string(appDoc), "**Type**: `s3`")
+	assert.Contains(t, string(appDoc), "**Settings**: `bucket`, `key`")
+	assert.NotContains(t, string(appDoc), "my-state")
+	assert.Contains(t, string(appDoc), "| `region` | `\"us-east-1\"` | ../root.hcl |")
+	assert.Contains(t, string(appDoc), "| `vpc_id` | `\"vpc-mock\"` | terragrunt.hcl |")
+
+	vpcDoc, err := os.ReadFile(filepath.Join(stackDir, "out", "vpc", unitFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(vpcDoc), "**Source**: `git::https://example.com/modules.git//vpc?ref=v1.2.0` (version `v1.2.0`)")
+
+	index, err := os.ReadFile(filepath.Join(stackDir, "out", indexFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(index), "| [app](app/README.md) | local |  |")
+	assert.Contains(t, string(index), "| [vpc](vpc/README.md) | `git::https://example.com/modules.git//vpc?ref=v1.2.0` | v1.2.0 |")
+	assert.Contains(t, string(index), "unit0[\"app\"]\n  unit1[\"vpc\"]\n  unit0 --> unit1\n")
+}
@@ -0,0 +1,34 @@
+package docs
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "docs"
+
+	FlagNameTerragruntDocsOut = "terragrunt-docs-out"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDocsOut,
+			Aliases:     []string{"docs-out"},
+			Destination: &opts.DocsOutDir,
+			EnvVar:      "TERRAGRUNT_DOCS_OUT",
+			Usage:       "The directory to write the documentation of the units to, relative to the working directory. Defaults to " + DefaultOutDir + ".",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Generate Markdown documentation for every unit in the current directory tree, with an index and a dependency diagram.",
+		Description: "The documentation of each unit lists its terraform source and version, its backend, its dependencies, and its inputs with their values and the files they are set in.",
+		Flags:       NewFlags(opts).Sort(),
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -0,0 +1,85 @@
+// `eval` command evaluates HCL expressions in the same context as the attributes of the terragrunt config in the
+// working dir, so that they can reference its locals, includes and dependency outputs, and prints their values. When no
+// expression is given, it starts an interactive console that evaluates the expressions read line by line.
+
+package eval
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const (
+	consolePrompt = "> "
+	consoleExit   = "exit"
+)
+
+func Run(opts *options.TerragruntOptions, expressions []string, in io.Reader) error {
+	opts.Logger.Debugf("Parsing the terragrunt config %s to evaluate expressions in its context", opts.TerragruntConfigPath)
+	evalContext, err := config.ParseConfigEvalContext(opts.TerragruntConfigPath, opts)
+	if err != nil {
+		return err
+	}
+
+	if len(expressions) == 0 {
+		return runConsole(opts, evalContext, in)
+	}
+
+	for _, expression := range expressions {
+		if err := evaluateAndPrint(opts, evalContext, expression); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runConsole evaluates the expressions read line by line from the given reader until it is exhausted or `exit` is read.
+// The errors of the expressions are printed instead of being returned, so that the console keeps going after a typo.
+func runConsole(opts *options.TerragruntOptions, evalContext *hcl.EvalContext, in io.Reader) error {
+	scanner := bufio.NewScanner(in)
+	for {
+		// The prompt is written to ErrWriter, as are the prompts of the other commands, so that stdout only has the
+		// values.
+		if _, err := fmt.Fprint(opts.ErrWriter, consolePrompt); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		if !scanner.Scan() {
+			break
+		}
+
+		expression := strings.TrimSpace(scanner.Text())
+		if expression == "" {
+			continue
+		}
+		if expression == consoleExit {
+			return nil
+		}
+
+		if err := evaluateAndPrint(opts, evalContext, expression); err != nil {
+			if _, err := fmt.Fprintln(opts.ErrWriter, err); err != nil {
+				return errors.WithStackTrace(err)
+			}
+		}
+	}
+	return errors.WithStackTrace(scanner.Err())
+}
+
+func evaluateAndPrint(opts *options.TerragruntOptions, evalContext *hcl.EvalContext, expression string) error {
+	value, err := config.EvaluateExpression(expression, evalContext)
+	if err != nil {
+		return err
+	}
+
+	if _, err := fmt.Fprintln(opts.Writer, config.FormatExpressionValue(value)); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package eval
+
+import (
+	"bytes"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func newEvalOptions(t *testing.T) (*options.TerragruntOptions, *bytes.Buffer, *bytes.Buffer) {
+	t.Helper()
+
+	configPath, err := filepath.Abs("../../../test/fixture-eval/app/terragrunt.hcl")
+	require.NoError(t, err)
+
+	tgOptions, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+
+	var stdout, stderr bytes.Buffer
+	tgOptions.Writer = &stdout
+	tgOptions.ErrWriter = &stderr
+
+	return tgOptions, &stdout, &stderr
+}
+
+func TestEval(t *testing.T) {
+	t.Parallel()
+
+	tgOptions, stdout, _ := newEvalOptions(t)
+
+	err := Run(tgOptions, []string{
+		"local.region",
+		"include.root.locals.env",
+		"dependency.vpc.outputs.vpc_id",
+		`"${local.name}-${dependency.vpc.outputs.vpc_id}"`,
+		"{ name = local.name }",
+	}, strings.NewReader(""))
+	require.NoError(t, err)
+
+	expected := `"us-east-1"
+"dev"
+"vpc-1234"
+"app-dev-vpc-1234"
+{
+  name = "app-dev"
+}
+`
+	assert.Equal(t, expected, stdout.String())
+}
+
+func TestEvalInvalidExpression(t *testing.T) {
+	t.Parallel()
+
+	tgOptions, stdout, _ := newEvalOptions(t)
+
+	err := Run(tgOptions, []string{"local.region", "local.missing"}, strings.NewReader(""))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported attribute")
+	assert.Equal(t, "\"us-east-1\"\n", stdout.String())
+}
+
+func TestEvalConsole(t *testing.T) {
+	t.Parallel()
+
+	tgOptions, stdout, stderr := newEvalOptions(t)
+
+	in := strings.NewReader("local.region\n\nlocal.missing\nupper(local.name)\nexit\nlocal.name\n")
+	require.NoError(t, Run(tgOptions, nil, in))
+
+	// The console keeps going after an error, and stops at exit.
+	assert.Equal(t, "\"us-east-1\"\n\"APP-DEV\"\n", stdout.String())
+	assert.Contains(t, stderr.String(), "Unsupported attribute")
+	assert.Equal(t, 5, strings.Count(stderr.String(), consolePrompt))
+}
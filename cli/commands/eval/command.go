@@ -0,0 +1,21 @@
+package eval
+
+import (
+	"os"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "eval"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Evaluate expressions in the context of the terragrunt config, e.g. `terragrunt eval 'local.region'`. Starts an interactive console if no expression is given.",
+		Description: "The expressions can reference the locals, include, dependency and feature values of the config, which is useful for debugging them without running terraform.",
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx), ctx.Args().Slice(), os.Stdin) },
+	}
+}
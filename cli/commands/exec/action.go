@@ -0,0 +1,92 @@
+package exec
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// The prefix of the environment variables holding the outputs of the dependencies of the unit as JSON, followed by the
+// name of the dependency block, e.g. TERRAGRUNT_DEPENDENCY_vpc.
+const EnvNameDependencyPrefix = "TERRAGRUNT_DEPENDENCY_"
+
+// Run resolves the config of the unit, downloads its source and generates its files as for a terraform command, then
+// runs the given command in the working directory of terraform instead.
+func Run(opts *options.TerragruntOptions, args []string) error {
+	if len(args) == 0 {
+		return errors.WithStackTrace(MissingCommand{})
+	}
+
+	target := terraform.NewTarget(terraform.TargetPointGenerateConfig, func(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+		return runExec(opts, cfg, args)
+	})
+	return terraform.RunWithTarget(opts, target)
+}
+
+// runExec runs the given command in the working directory of the given options, with the environment terraform would
+// run with, which includes the credentials of the IAM role of the unit and its inputs as TF_VAR_ variables, along with
+// the outputs of its dependencies.
+func runExec(opts *options.TerragruntOptions, cfg *config.TerragruntConfig, args []string) error {
+	execOpts := opts.Clone(opts.TerragruntConfigPath)
+	execOpts.WorkingDir = opts.WorkingDir
+
+	if err := terraform.SetTerragruntInputsAsEnvVars(execOpts, cfg); err != nil {
+		return err
+	}
+	dependencyEnv, err := dependencyOutputsAsEnvVars(opts, cfg)
+	if err != nil {
+		return err
+	}
+	for name, value := range dependencyEnv {
+		execOpts.Env[name] = value
+	}
+
+	opts.Logger.Debugf("Running %v in %s", args, execOpts.WorkingDir)
+	return shell.RunShellCommand(execOpts, args[0], args[1:]...)
+}
+
+// dependencyOutputsAsEnvVars returns the outputs of each dependency of the unit as JSON, in an environment variable
+// named after the dependency block.
+func dependencyOutputsAsEnvVars(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) (map[string]string, error) {
+	outputs, err := cfg.DependencyOutputs(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	env := map[string]string{}
+	for name, value := range outputs {
+		outputsJSON, err := ctyjson.SimpleJSONValue{Value: value}.MarshalJSON()
+		if err != nil {
+			return nil, errors.WithStackTrace(DependencyOutputsEncodingError{Name: name, Err: err})
+		}
+		env[EnvNameDependencyPrefix+name] = string(outputsJSON)
+	}
+	return env, nil
+}
+
+// Custom error types
+
+type MissingCommand struct{}
+
+func (err MissingCommand) Error() string {
+	return "Missing the command to run (Example: terragrunt exec -- tflint)"
+}
+
+type DependencyOutputsEncodingError struct {
+	Name string
+	Err  error
+}
+
+func (err DependencyOutputsEncodingError) Error() string {
+	return fmt.Sprintf("Could not encode the outputs of dependency %s as JSON: %v", err.Name, err.Err)
+}
+
+func (err DependencyOutputsEncodingError) Unwrap() error {
+	return err.Err
+}
@@ -0,0 +1,67 @@
+package exec
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const execTestConfig = `
+dependency "vpc" {
+  config_path  = "../vpc"
+  skip_outputs = true
+
+  mock_outputs = {
+    vpc_id = "vpc-123"
+  }
+}
+
+inputs = {
+  name   = "app"
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`
+
+func TestRunExec(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	unitDir := filepath.Join(rootDir, "app")
+	require.NoError(t, os.MkdirAll(unitDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "vpc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "vpc", config.DefaultTerragruntConfigPath), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, config.DefaultTerragruntConfigPath), []byte(execTestConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, "main.tf"), []byte(""), 0644))
+
+	// The version of terraform is checked before the command runs, so a stub stands in for it.
+	terraformPath := filepath.Join(rootDir, "terraform")
+	require.NoError(t, os.WriteFile(terraformPath, []byte("#!/bin/sh\necho 'Terraform v1.5.7'\n"), 0755))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(unitDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformPath = terraformPath
+	opts.TerraformCommand = CommandName
+	opts.TerraformCliArgs = []string{CommandName}
+
+	require.NoError(t, Run(opts, []string{"sh", "-c", `printf '%s\n%s\n%s' "$TF_VAR_name" "$TF_VAR_vpc_id" "$TERRAGRUNT_DEPENDENCY_vpc" > env.txt`}))
+
+	env, err := os.ReadFile(filepath.Join(unitDir, "env.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "app\nvpc-123\n{\"vpc_id\":\"vpc-123\"}", string(env))
+}
+
+func TestRunExecMissingCommand(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	err = Run(opts, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Missing the command to run")
+}
@@ -0,0 +1,34 @@
+package exec
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "exec"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:      CommandName,
+		Usage:     "Run a command in the working directory of the unit, with its inputs, the outputs of its dependencies and its IAM role in the environment.",
+		UsageText: "terragrunt exec -- <command> [args]",
+		Action: func(ctx *cli.Context) error {
+			return Run(opts.OptionsFromContext(ctx), commandArgs(ctx))
+		},
+	}
+}
+
+// commandArgs returns the command to run and its args: the args after `--` as they were given, so that the flags of
+// the command are not taken for flags of terragrunt nor normalized to single dashes, or else the args of the exec
+// command.
+func commandArgs(ctx *cli.Context) []string {
+	rawArgs := ctx.RawArgs().Slice()
+	for i, arg := range rawArgs {
+		if arg == "--" {
+			return rawArgs[i+1:]
+		}
+	}
+	return ctx.Args().Slice()
+}
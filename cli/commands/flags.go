@@ -1,6 +1,7 @@
 package commands
 
 import (
+	"github.com/gruntwork-io/terragrunt/cli/commands/completion"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/cli"
 	"github.com/gruntwork-io/terragrunt/shell"
@@ -22,8 +23,20 @@ const (
 	FlagNameTerragruntIAMRole                        = "terragrunt-iam-role"
 	FlagNameTerragruntIAMAssumeRoleDuration          = "terragrunt-iam-assume-role-duration"
 	FlagNameTerragruntIAMAssumeRoleSessionName       = "terragrunt-iam-assume-role-session-name"
+	FlagNameTerragruntIAMMFASerial                   = "terragrunt-iam-mfa-serial"
+	FlagNameTerragruntIAMMFACacheKey                 = "terragrunt-iam-mfa-cache-key"
+	FlagNameTerragruntIAMWebIdentityToken            = "terragrunt-iam-web-identity-token"
 	FlagNameTerragruntIgnoreDependencyErrors         = "terragrunt-ignore-dependency-errors"
 	FlagNameTerragruntIgnoreDependencyOrder          = "terragrunt-ignore-dependency-order"
+	FlagNameTerragruntContinueOnError                = "terragrunt-continue-on-error"
+	FlagNameTerragruntRunSummaryJSON                 = "terragrunt-run-summary-json"
+	FlagNameTerragruntTimeout                        = "terragrunt-timeout"
+	FlagNameTerragruntSkipUnchanged                  = "terragrunt-skip-unchanged"
+	FlagNameTerragruntEnv                            = "terragrunt-env"
+	FlagNameFeature                                  = "feature"
+	FlagNameTerragruntVar                            = "terragrunt-var"
+	FlagNameTerragruntVarFile                        = "terragrunt-var-file"
+	FlagNameTerragruntStrictConfig                   = "terragrunt-strict-config"
 	FlagNameTerragruntIgnoreExternalDependencies     = "terragrunt-ignore-external-dependencies"
 	FlagNameTerragruntIncludeExternalDependencies    = "terragrunt-include-external-dependencies"
 	FlagNameTerragruntExcludeDir                     = "terragrunt-exclude-dir"
@@ -32,10 +45,19 @@ const (
 	FlagNameTerragruntParallelism                    = "terragrunt-parallelism"
 	FlagNameTerragruntDebug                          = "terragrunt-debug"
 	FlagNameTerragruntLogLevel                       = "terragrunt-log-level"
+	FlagNameTerragruntLogFormat                      = "terragrunt-log-format"
 	FlagNameTerragruntNoColor                        = "terragrunt-no-color"
 	FlagNameTerragruntModulesThatInclude             = "terragrunt-modules-that-include"
+	FlagNameTerragruntChangedSince                   = "terragrunt-changed-since"
 	FlagNameTerragruntFetchDependencyOutputFromState = "terragrunt-fetch-dependency-output-from-state"
+	FlagNameTerragruntDependencyFetchParallelism     = "terragrunt-dependency-fetch-parallelism"
+	FlagNameTerragruntDependencyOutputCacheDir       = "terragrunt-dependency-output-cache-dir"
+	FlagNameTerragruntDependencyOutputCacheTTL       = "terragrunt-dependency-output-cache-ttl"
+	FlagNameTerragruntCheckRemoteStateDependencies   = "terragrunt-check-remote-state-dependencies"
+	FlagNameTerragruntStrictMockOutputs              = "terragrunt-strict-mock-outputs"
+	FlagNameTerragruntBreakCycleHints                = "terragrunt-break-cycle-hints"
 	FlagNameTerragruntUsePartialParseConfigCache     = "terragrunt-use-partial-parse-config-cache"
+	FlagNameTerragruntPersistentParseConfigCache     = "terragrunt-persistent-parse-config-cache"
 	FlagNameTerragruntIncludeModulePrefix            = "terragrunt-include-module-prefix"
 	FlagNameTerragruntFailOnStateBucketCreation      = "terragrunt-fail-on-state-bucket-creation"
 	FlagNameTerragruntDisableBucketUpdate            = "terragrunt-disable-bucket-update"
@@ -92,6 +114,7 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.WorkingDir,
 			EnvVar:      "TERRAGRUNT_WORKING_DIR",
 			Usage:       "The path to the Terraform templates. Default is current directory.",
+			Complete:    completion.UnitPaths(opts),
 		},
 		&cli.GenericFlag[string]{
 			Name:        FlagNameTerragruntDownloadDir,
@@ -136,6 +159,24 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			EnvVar:      "TERRAGRUNT_IAM_ASSUME_ROLE_SESSION_NAME",
 			Usage:       "Name for the IAM Assummed Role session. Can also be set via TERRAGRUNT_IAM_ASSUME_ROLE_SESSION_NAME environment variable.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntIAMMFASerial,
+			Destination: &opts.IAMRoleOptions.MFASerial,
+			EnvVar:      "TERRAGRUNT_IAM_MFA_SERIAL",
+			Usage:       "The serial number or ARN of the MFA device required to assume the IAM role. Terragrunt prompts for the MFA code once and caches the session. Can also be set via the TERRAGRUNT_IAM_MFA_SERIAL environment variable.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntIAMMFACacheKey,
+			Destination: &opts.MFASessionCacheKey,
+			EnvVar:      "TERRAGRUNT_IAM_MFA_CACHE_KEY",
+			Usage:       "A secret to encrypt the sessions of the IAM roles assumed with MFA with, to cache them on disk for the later runs. Can also be set via the TERRAGRUNT_IAM_MFA_CACHE_KEY environment variable.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntIAMWebIdentityToken,
+			Destination: &opts.IAMRoleOptions.WebIdentityToken,
+			EnvVar:      "TERRAGRUNT_IAM_WEB_IDENTITY_TOKEN",
+			Usage:       "The OIDC token, or the path of a file holding it, to assume the IAM role with web identity federation. Can also be set via the TERRAGRUNT_IAM_WEB_IDENTITY_TOKEN environment variable.",
+		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntIgnoreDependencyErrors,
 			Destination: &opts.IgnoreDependencyErrors,
@@ -146,6 +187,59 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.IgnoreDependencyOrder,
 			Usage:       "*-all commands will be run disregarding the dependencies",
 		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntContinueOnError,
+			Destination: &opts.ContinueOnError,
+			EnvVar:      "TERRAGRUNT_CONTINUE_ON_ERROR",
+			Usage:       "*-all commands keep running the modules that don't depend on a failed module, and print a JSON summary of succeeded, failed and skipped modules to stderr.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntRunSummaryJSON,
+			Destination: &opts.RunSummaryJSONFile,
+			EnvVar:      "TERRAGRUNT_RUN_SUMMARY_JSON",
+			Usage:       "The path of a file to write the JSON summary of --terragrunt-continue-on-error to, instead of stderr.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntTimeout,
+			Destination: &opts.ModuleTimeout,
+			EnvVar:      "TERRAGRUNT_TIMEOUT",
+			Usage:       "The maximum amount of time the commands run for a module may take, e.g. 30m, unless the module sets its own timeout. Hung commands are killed and the module fails.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntSkipUnchanged,
+			Destination: &opts.SkipUnchanged,
+			EnvVar:      "TERRAGRUNT_SKIP_UNCHANGED",
+			Usage:       "Skip plan and apply in modules whose configuration, inputs, source and files did not change since their last successful apply.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntEnv,
+			Destination: &opts.ConfigEnv,
+			EnvVar:      "TERRAGRUNT_ENV",
+			Usage:       "The environment whose overlay config files, e.g. terragrunt.<env>.hcl, are deep merged over the terragrunt.hcl files next to them.",
+		},
+		&cli.MapFlag[string, string]{
+			Name:        FlagNameFeature,
+			Destination: &opts.FeatureFlags,
+			EnvVar:      "TERRAGRUNT_FEATURE",
+			Usage:       "Set the value of a feature flag declared with a feature block, e.g. --feature enable_monitoring=true. Can be passed multiple times.",
+		},
+		&cli.MapFlag[string, string]{
+			Name:        FlagNameTerragruntVar,
+			Destination: &opts.TerragruntVars,
+			Usage:       "Set the value of a variable declared with a variable block in the terragrunt config, e.g. --terragrunt-var region=us-east-1. Can be passed multiple times.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        FlagNameTerragruntVarFile,
+			Destination: &opts.TerragruntVarFiles,
+			EnvVar:      "TERRAGRUNT_VAR_FILE",
+			Usage:       "A file, in HCL or JSON, that sets the values of variables declared with variable blocks in the terragrunt config. Can be passed multiple times.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntStrictConfig,
+			Destination: &opts.StrictConfig,
+			EnvVar:      "TERRAGRUNT_STRICT_CONFIG",
+			Usage:       "Fail on unknown and deprecated blocks and attributes in all the config files that are parsed, including the ones only partially parsed by *-all commands.",
+		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntIgnoreExternalDependencies,
 			Destination: &opts.IgnoreExternalDependencies,
@@ -168,11 +262,13 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.ExcludeDirs,
 			EnvVar:      "TERRAGRUNT_EXCLUDE_DIR",
 			Usage:       "Unix-style glob of directories to exclude when running *-all commands.",
+			Complete:    completion.UnitPaths(opts),
 		},
 		&cli.SliceFlag[string]{
 			Name:        FlagNameTerragruntIncludeDir,
 			Destination: &opts.IncludeDirs,
 			Usage:       "Unix-style glob of directories to include when running *-all commands",
+			Complete:    completion.UnitPaths(opts),
 		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntDebug,
@@ -186,6 +282,12 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			EnvVar:      "TERRAGRUNT_LOG_LEVEL",
 			Usage:       "Sets the logging level for Terragrunt. Supported levels: panic, fatal, error, warn, info, debug, trace.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntLogFormat,
+			Destination: &opts.LogFormat,
+			EnvVar:      "TERRAGRUNT_LOG_FORMAT",
+			Usage:       "Sets the format of the logs and Terraform output. Supported formats: text, json-stream.",
+		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntNoColor,
 			Destination: &opts.DisableLogColors,
@@ -198,12 +300,54 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			EnvVar:      "TERRAGRUNT_USE_PARTIAL_PARSE_CONFIG_CACHE",
 			Usage:       "Enables caching of includes during partial parsing operations. Will also be used for the --terragrunt-iam-role option if provided.",
 		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntPersistentParseConfigCache,
+			Destination: &opts.UsePersistentParseConfigCache,
+			EnvVar:      "TERRAGRUNT_PERSISTENT_PARSE_CONFIG_CACHE",
+			Usage:       "Cache the partial parses of the configs in their .terragrunt-cache folder, reused across runs until the configs or their includes change.",
+		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntFetchDependencyOutputFromState,
 			Destination: &opts.FetchDependencyOutputFromState,
 			EnvVar:      "TERRAGRUNT_FETCH_DEPENDENCY_OUTPUT_FROM_STATE",
 			Usage:       "The option fetchs dependency output directly from the state file instead of init dependencies and running terraform on them.",
 		},
+		&cli.GenericFlag[int]{
+			Name:        FlagNameTerragruntDependencyFetchParallelism,
+			Destination: &opts.DependencyFetchParallelism,
+			EnvVar:      "TERRAGRUNT_DEPENDENCY_FETCH_PARALLELISM",
+			Usage:       "The maximum number of dependency outputs of a module to fetch concurrently.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDependencyOutputCacheDir,
+			Destination: &opts.DependencyOutputCacheDir,
+			EnvVar:      "TERRAGRUNT_DEPENDENCY_OUTPUT_CACHE_DIR",
+			Usage:       "The directory to cache the outputs of dependencies in across terragrunt runs, by the version of their state.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDependencyOutputCacheTTL,
+			Destination: &opts.DependencyOutputCacheTTL,
+			EnvVar:      "TERRAGRUNT_DEPENDENCY_OUTPUT_CACHE_TTL",
+			Usage:       "The maximum age of the cached outputs of dependencies, e.g. 15m, after which they are fetched again.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntCheckRemoteStateDependencies,
+			Destination: &opts.CheckRemoteStateDependencies,
+			EnvVar:      "TERRAGRUNT_CHECK_REMOTE_STATE_DEPENDENCIES",
+			Usage:       "Warn about the modules whose state is read by terraform_remote_state data sources, but that are not declared as dependencies.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntStrictMockOutputs,
+			Destination: &opts.StrictMockOutputs,
+			EnvVar:      "TERRAGRUNT_STRICT_MOCK_OUTPUTS",
+			Usage:       "Fail apply and destroy when the mock outputs of a dependency would be used, listing the outputs that could not be resolved.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntBreakCycleHints,
+			Destination: &opts.BreakCycleHints,
+			EnvVar:      "TERRAGRUNT_BREAK_CYCLE_HINTS",
+			Usage:       "When a dependency cycle is found, analyze all the cycles of the graph and suggest the dependencies to remove to break them.",
+		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntIncludeModulePrefix,
 			Destination: &opts.IncludeModulePrefix,
@@ -220,6 +364,12 @@ func NewGlobalFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.ModulesThatInclude,
 			Usage:       "If flag is set, 'run-all' will only run the command against Terragrunt modules that include the specified file.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntChangedSince,
+			Destination: &opts.ChangedSince,
+			EnvVar:      "TERRAGRUNT_CHANGED_SINCE",
+			Usage:       "If flag is set, 'run-all' will only run the command against Terragrunt modules affected by the files changed since the specified git ref, and the modules that depend on them.",
+		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntFailOnStateBucketCreation,
 			Destination: &opts.FailIfBucketCreationRequired,
@@ -1,8 +1,13 @@
 package graphdependencies
 
 import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/configstack"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
 // Run graph dependencies prints the dependency graph to stdout
@@ -12,7 +17,36 @@ func Run(opts *options.TerragruntOptions) error {
 		return err
 	}
 
+	if opts.GraphDependentsOf != "" {
+		return printDependents(opts, stack)
+	}
+
 	// Exit early if the operation wanted is to get the graph
 	stack.Graph(opts)
 	return nil
 }
+
+// printDependents prints the paths of the modules that depend on the module passed with --terragrunt-dependents, one
+// per line, so the impact of changing that module can be assessed.
+func printDependents(opts *options.TerragruntOptions, stack *configstack.Stack) error {
+	modulePath, err := util.CanonicalPath(opts.GraphDependentsOf, opts.WorkingDir)
+	if err != nil {
+		return err
+	}
+	// Allow pointing at the terragrunt configuration file of the module as well as its directory.
+	if util.FileExists(modulePath) && !util.IsDir(modulePath) {
+		modulePath = filepath.Dir(modulePath)
+	}
+
+	dependents, err := stack.FindDependents(modulePath)
+	if err != nil {
+		return err
+	}
+
+	for _, module := range dependents {
+		if _, err := fmt.Fprintln(opts.Writer, module.Path); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+	return nil
+}
@@ -7,12 +7,27 @@ import (
 
 const (
 	CommandName = "graph-dependencies"
+
+	FlagNameTerragruntDependents = "terragrunt-dependents"
 )
 
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDependents,
+			Aliases:     []string{"dependents"},
+			Destination: &opts.GraphDependentsOf,
+			EnvVar:      "TERRAGRUNT_DEPENDENTS",
+			Usage:       "List the modules that depend on the module at the given path, directly or transitively, instead of printing the graph.",
+		},
+	}
+}
+
 func NewCommand(opts *options.TerragruntOptions) *cli.Command {
 	return &cli.Command{
 		Name:   CommandName,
 		Usage:  "Prints the terragrunt dependency graph to stdout.",
+		Flags:  NewFlags(opts).Sort(),
 		Action: func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
 	}
 }
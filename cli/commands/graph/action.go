@@ -0,0 +1,32 @@
+package graph
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/run"
+	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+func action(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		if opts.TerraformCommand == "" {
+			return errors.WithStackTrace(MissingCommand{})
+		}
+
+		opts.RunGraph = true
+		if err := run.NarrowToGraph(opts); err != nil {
+			return err
+		}
+		return runall.Action(opts)(ctx)
+	}
+}
+
+// Custom error types
+
+type MissingCommand struct{}
+
+func (err MissingCommand) Error() string {
+	return "Missing graph command argument (Example: terragrunt graph apply)"
+}
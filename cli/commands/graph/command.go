@@ -0,0 +1,25 @@
+package graph
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+	"github.com/gruntwork-io/terragrunt/cli/commands/run"
+	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "graph"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Run a terraform command in the module of the working directory and in the modules that depend on it, directly or transitively, in dependency order.",
+		UsageText:   "terragrunt graph <terraform command> [terraform args]",
+		Description: "The modules that depend on the module of the working directory are looked for under --terragrunt-graph-root, which defaults to the root of the git repository. It is equivalent to 'terragrunt run --graph -- <command>'.",
+		Flags:       append(append(commands.NewGlobalFlags(opts), runall.NewFlags(opts)...), run.NewGraphRootFlag(opts)).Sort(),
+		Subcommands: runall.SubCommands(opts).SkipRunning(),
+		Action:      action(opts),
+	}
+}
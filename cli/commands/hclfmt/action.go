@@ -1,5 +1,6 @@
 // `hclFmt` command recursively looks for hcl files in the directory tree starting at workingDir, and formats them
-// based on the language style guides provided by Hashicorp. This is done using the official hcl2 library.
+// based on the language style guides provided by Hashicorp. This is done using the official hcl2 library. The format can
+// be customized with a style file, see style.go.
 
 package hclfmt
 
@@ -17,7 +18,6 @@ import (
 
 	"github.com/hashicorp/go-multierror"
 	"github.com/hashicorp/hcl/v2/hclparse"
-	"github.com/hashicorp/hcl/v2/hclwrite"
 	"github.com/mattn/go-zglob"
 
 	"github.com/gruntwork-io/terragrunt/options"
@@ -34,7 +34,11 @@ func Run(opts *options.TerragruntOptions) error {
 			targetFile = util.JoinPath(workingDir, targetFile)
 		}
 		opts.Logger.Debugf("Formatting hcl file at: %s.", targetFile)
-		return formatTgHCL(opts, targetFile)
+		diff, err := formatTgHCL(opts, targetFile)
+		if writeErr := writeDiffOut(opts, diff); writeErr != nil {
+			return writeErr
+		}
+		return err
 	}
 
 	opts.Logger.Debugf("Formatting hcl files from the directory tree %s.", opts.WorkingDir)
@@ -57,67 +61,113 @@ func Run(opts *options.TerragruntOptions) error {
 	opts.Logger.Debugf("Found %d hcl files", len(filteredTgHclFiles))
 
 	var formatErrors *multierror.Error
+	var diffs []byte
 	for _, tgHclFile := range filteredTgHclFiles {
-		err := formatTgHCL(opts, tgHclFile)
+		diff, err := formatTgHCL(opts, tgHclFile)
 		if err != nil {
 			formatErrors = multierror.Append(formatErrors, err)
 		}
+		diffs = append(diffs, diff...)
+	}
+
+	if err := writeDiffOut(opts, diffs); err != nil {
+		formatErrors = multierror.Append(formatErrors, err)
 	}
 
 	return formatErrors.ErrorOrNil()
 }
 
-// formatTgHCL uses the hcl2 library to format the hcl file. This will attempt to parse the HCL file first to
-// ensure that there are no syntax errors, before attempting to format it.
-func formatTgHCL(opts *options.TerragruntOptions, tgHclFile string) error {
+// writeDiffOut writes the given diffs to the patch file set with --terragrunt-diff-out, if any. The patch file is
+// written even if there are no diffs, so that a patch file left by a previous run is not mistaken for the current one.
+func writeDiffOut(opts *options.TerragruntOptions, diffs []byte) error {
+	if opts.DiffOut == "" {
+		return nil
+	}
+
+	diffOut := opts.DiffOut
+	if !filepath.IsAbs(diffOut) {
+		diffOut = util.JoinPath(opts.WorkingDir, diffOut)
+	}
+	opts.Logger.Debugf("Writing the diffs of the hcl files to %s", diffOut)
+	return errors.WithStackTrace(os.WriteFile(diffOut, diffs, 0644))
+}
+
+// formatTgHCL uses the hcl2 library to format the hcl file, with the style of the nearest style file. This will attempt
+// to parse the HCL file first to ensure that there are no syntax errors, before attempting to format it. When diffs are requested, this returns the
+// unified diff between the original and formatted contents of the file.
+func formatTgHCL(opts *options.TerragruntOptions, tgHclFile string) ([]byte, error) {
 	opts.Logger.Debugf("Formatting %s", tgHclFile)
 
 	info, err := os.Stat(tgHclFile)
 	if err != nil {
 		opts.Logger.Errorf("Error retrieving file info of %s", tgHclFile)
-		return err
+		return nil, err
 	}
 
 	contentsStr, err := util.ReadFileAsString(tgHclFile)
 	if err != nil {
 		opts.Logger.Errorf("Error reading %s", tgHclFile)
-		return err
+		return nil, err
 	}
 	contents := []byte(contentsStr)
 
 	err = checkErrors(opts.Logger, contents, tgHclFile)
 	if err != nil {
 		opts.Logger.Errorf("Error parsing %s", tgHclFile)
-		return err
+		return nil, err
 	}
 
-	newContents := hclwrite.Format(contents)
+	style, err := findStyle(filepath.Dir(tgHclFile))
+	if err != nil {
+		opts.Logger.Errorf("Error reading the style file of %s", tgHclFile)
+		return nil, err
+	}
+
+	newContents, err := formatWithStyle(contents, tgHclFile, style)
+	if err != nil {
+		opts.Logger.Errorf("Error formatting %s", tgHclFile)
+		return nil, err
+	}
 
 	fileUpdated := !bytes.Equal(newContents, contents)
 
-	if opts.Diff && fileUpdated {
-		diff, err := bytesDiff(opts, contents, newContents, tgHclFile)
+	var diff []byte
+	if (opts.Diff || opts.DiffOut != "") && fileUpdated {
+		diff, err = bytesDiff(opts, contents, newContents, diffPath(opts, tgHclFile))
 		if err != nil {
 			opts.Logger.Errorf("Failed to generate diff for %s", tgHclFile)
-			return err
+			return nil, err
 		}
+	}
+
+	if opts.Diff && fileUpdated {
 		_, err = fmt.Fprintf(opts.Writer, "%s\n", diff)
 		if err != nil {
 			opts.Logger.Errorf("Failed to print diff for %s", tgHclFile)
-			return err
+			return nil, err
 		}
 	}
 
 	if opts.Check && fileUpdated {
-		return fmt.Errorf("Invalid file format %s", tgHclFile)
+		return diff, fmt.Errorf("Invalid file format %s", tgHclFile)
 	}
 
 	if fileUpdated {
 		opts.Logger.Infof("%s was updated", tgHclFile)
-		return os.WriteFile(tgHclFile, newContents, info.Mode())
+		return diff, os.WriteFile(tgHclFile, newContents, info.Mode())
 	}
 
-	return nil
+	return nil, nil
+}
+
+// diffPath returns the path of the given file to show in its diff, which is relative to the working dir, so that the
+// diffs can be applied as a patch from the working dir.
+func diffPath(opts *options.TerragruntOptions, tgHclFile string) string {
+	relPath, err := filepath.Rel(opts.WorkingDir, tgHclFile)
+	if err != nil || strings.HasPrefix(relPath, "..") {
+		return tgHclFile
+	}
+	return filepath.ToSlash(relPath)
 }
 
 // checkErrors takes in the contents of a hcl file and looks for syntax errors.
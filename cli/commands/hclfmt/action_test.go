@@ -2,6 +2,7 @@ package hclfmt
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"testing"
 
@@ -201,6 +202,48 @@ func TestHCLFmtCheckErrors(t *testing.T) {
 	}
 }
 
+func TestHCLFmtCheckDiffOut(t *testing.T) {
+	t.Parallel()
+
+	tmpPath, err := files.CopyFolderToTemp("../../../test/fixture-hclfmt", t.Name(), func(path string) bool { return true })
+	defer os.RemoveAll(tmpPath)
+	require.NoError(t, err)
+
+	original, err := os.ReadFile("../../../test/fixture-hclfmt/terragrunt.hcl")
+	require.NoError(t, err)
+	expected, err := os.ReadFile("../../../test/fixture-hclfmt/expected.hcl")
+	require.NoError(t, err)
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	patchPath := filepath.Join(t.TempDir(), "hclfmt.patch")
+	tgOptions.Check = true
+	tgOptions.DiffOut = patchPath
+	tgOptions.WorkingDir = tmpPath
+
+	err = Run(tgOptions)
+	require.Error(t, err)
+
+	// Check mode doesn't update the files, but writes the diffs of all of them to the patch file.
+	actual, err := os.ReadFile(filepath.Join(tmpPath, "a/terragrunt.hcl"))
+	require.NoError(t, err)
+	assert.Equal(t, original, actual)
+
+	patch, err := os.ReadFile(patchPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(patch), "--- old/terragrunt.hcl\n+++ new/terragrunt.hcl\n")
+	assert.Contains(t, string(patch), "--- old/a/b/c/d/services.hcl\n+++ new/a/b/c/d/services.hcl\n")
+	assert.NotContains(t, string(patch), util.TerragruntCacheDir)
+
+	// The patch formats the files when applied from the working dir.
+	output, err := exec.Command("patch", "-p1", "-d", tmpPath, "-i", patchPath).CombinedOutput()
+	require.NoError(t, err, string(output))
+	actual, err = os.ReadFile(filepath.Join(tmpPath, "a/terragrunt.hcl"))
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
 func TestHCLFmtFile(t *testing.T) {
 	t.Parallel()
 
@@ -281,3 +324,49 @@ func TestHCLFmtHeredoc(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, expected, actual)
 }
+
+func TestHCLFmtStyle(t *testing.T) {
+	t.Parallel()
+
+	tmpPath, err := files.CopyFolderToTemp("../../../test/fixture-hclfmt-style", t.Name(), func(path string) bool { return true })
+	defer os.RemoveAll(tmpPath)
+	require.NoError(t, err)
+
+	expected, err := os.ReadFile("../../../test/fixture-hclfmt-style/expected.hcl")
+	require.NoError(t, err)
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	tgOptions.WorkingDir = tmpPath
+
+	err = Run(tgOptions)
+	require.NoError(t, err)
+
+	tgHclPath := filepath.Join(tmpPath, "terragrunt.hcl")
+	actual, err := os.ReadFile(tgHclPath)
+	require.NoError(t, err)
+	assert.Equal(t, expected, actual)
+
+	// Formatting is idempotent with a style file.
+	tgOptions.Check = true
+	err = Run(tgOptions)
+	require.NoError(t, err)
+}
+
+func TestHCLFmtStyleInvalid(t *testing.T) {
+	t.Parallel()
+
+	tmpPath := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(tmpPath, StyleFileName), []byte("indent_width = 0\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(tmpPath, "terragrunt.hcl"), []byte("inputs = {}\n"), 0644))
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	tgOptions.WorkingDir = tmpPath
+
+	err = Run(tgOptions)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "indent_width")
+}
@@ -8,9 +8,10 @@ import (
 const (
 	CommandName = "hclfmt"
 
-	FlagNameTerragruntHCLFmt = "terragrunt-hclfmt-file"
-	FlagNameTerragruntCheck  = "terragrunt-check"
-	FlagNameTerragruntDiff   = "terragrunt-diff"
+	FlagNameTerragruntHCLFmt  = "terragrunt-hclfmt-file"
+	FlagNameTerragruntCheck   = "terragrunt-check"
+	FlagNameTerragruntDiff    = "terragrunt-diff"
+	FlagNameTerragruntDiffOut = "terragrunt-diff-out"
 )
 
 func NewFlags(opts *options.TerragruntOptions) cli.Flags {
@@ -28,10 +29,18 @@ func NewFlags(opts *options.TerragruntOptions) cli.Flags {
 		},
 		&cli.BoolFlag{
 			Name:        FlagNameTerragruntDiff,
+			Aliases:     []string{"diff"},
 			Destination: &opts.Diff,
 			EnvVar:      "TERRAGRUNT_DIFF",
 			Usage:       "Print diff between original and modified file versions when running with 'hclfmt'.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntDiffOut,
+			Aliases:     []string{"diff-out"},
+			Destination: &opts.DiffOut,
+			EnvVar:      "TERRAGRUNT_DIFF_OUT",
+			Usage:       "Write the diffs between original and modified file versions to the given patch file when running with 'hclfmt'.",
+		},
 	}
 }
 
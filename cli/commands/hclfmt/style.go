@@ -0,0 +1,307 @@
+package hclfmt
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// StyleFileName is the name of the file that configures the style hclfmt formats the files in its folder and subfolders
+// with. The nearest style file to the formatted file, in its folder or in a parent folder, is used.
+const StyleFileName = ".terragrunt-fmt.hcl"
+
+// The indentation of the canonical format.
+const canonicalIndentWidth = 2
+
+// Style configures how hclfmt formats the files, on top of the canonical format of hclwrite.
+type Style struct {
+	// The number of spaces of each level of indentation.
+	IndentWidth int
+	// Whether the equal signs of consecutive attributes are aligned.
+	AlignAttributes bool
+	// The number of blank lines around top level blocks, or -1 to keep the blank lines of the file.
+	BlankLinesBetweenBlocks int
+	// The order of the top level blocks and attributes, by block type or attribute name. The blocks and attributes that
+	// are not listed are kept in their order, after the listed ones.
+	BlockOrder []string
+}
+
+// DefaultStyle is the canonical format of hclwrite, which is used when there is no style file.
+var DefaultStyle = Style{
+	IndentWidth:             canonicalIndentWidth,
+	AlignAttributes:         true,
+	BlankLinesBetweenBlocks: -1,
+}
+
+// styleFile is the content of a style file.
+type styleFile struct {
+	IndentWidth             *int     `hcl:"indent_width,optional"`
+	AlignAttributes         *bool    `hcl:"align_attributes,optional"`
+	BlankLinesBetweenBlocks *int     `hcl:"blank_lines_between_blocks,optional"`
+	BlockOrder              []string `hcl:"block_order,optional"`
+}
+
+// findStyle returns the style configured by the nearest style file to the given folder, or the default style if there
+// is none.
+func findStyle(dir string) (Style, error) {
+	for {
+		stylePath := filepath.Join(dir, StyleFileName)
+		if util.FileExists(stylePath) {
+			return parseStyleFile(stylePath)
+		}
+		parentDir := filepath.Dir(dir)
+		if parentDir == dir {
+			return DefaultStyle, nil
+		}
+		dir = parentDir
+	}
+}
+
+// parseStyleFile parses the style file at the given path. The settings that are not set in the file are the ones of
+// the default style.
+func parseStyleFile(stylePath string) (Style, error) {
+	parser := hclparse.NewParser()
+	file, diags := parser.ParseHCLFile(stylePath)
+	if diags.HasErrors() {
+		return Style{}, errors.WithStackTrace(diags)
+	}
+
+	decoded := styleFile{}
+	if diags := gohcl.DecodeBody(file.Body, nil, &decoded); diags.HasErrors() {
+		return Style{}, errors.WithStackTrace(diags)
+	}
+
+	style := DefaultStyle
+	if decoded.IndentWidth != nil {
+		if *decoded.IndentWidth < 1 {
+			return Style{}, errors.WithStackTrace(InvalidStyleSetting{StylePath: stylePath, Setting: "indent_width", Reason: "must be at least 1"})
+		}
+		style.IndentWidth = *decoded.IndentWidth
+	}
+	if decoded.AlignAttributes != nil {
+		style.AlignAttributes = *decoded.AlignAttributes
+	}
+	if decoded.BlankLinesBetweenBlocks != nil {
+		if *decoded.BlankLinesBetweenBlocks < 0 {
+			return Style{}, errors.WithStackTrace(InvalidStyleSetting{StylePath: stylePath, Setting: "blank_lines_between_blocks", Reason: "can't be negative"})
+		}
+		style.BlankLinesBetweenBlocks = *decoded.BlankLinesBetweenBlocks
+	}
+	style.BlockOrder = decoded.BlockOrder
+	return style, nil
+}
+
+// formatWithStyle formats the given contents in the canonical format, and then applies the given style to them.
+func formatWithStyle(contents []byte, filename string, style Style) ([]byte, error) {
+	formatted := hclwrite.Format(contents)
+
+	if len(style.BlockOrder) > 0 || style.BlankLinesBetweenBlocks >= 0 {
+		arranged, err := arrangeTopLevelItems(formatted, filename, style)
+		if err != nil {
+			return nil, err
+		}
+		// Format again, as moving attributes may change which ones are aligned together.
+		formatted = hclwrite.Format(arranged)
+	}
+	if !style.AlignAttributes {
+		formatted = unalignAttributes(formatted, filename)
+	}
+	if style.IndentWidth != canonicalIndentWidth {
+		formatted = reindent(formatted, filename, style.IndentWidth)
+	}
+	return formatted, nil
+}
+
+// topLevelItem is a top level block or attribute of a file, along with the comments and blank lines before it.
+type topLevelItem struct {
+	name        string
+	isBlock     bool
+	blankLines  int
+	contents    []byte
+	originalIdx int
+}
+
+// arrangeTopLevelItems sorts the top level blocks and attributes of the given contents in the order of the style, and
+// sets the number of blank lines around the top level blocks. Comments stay attached to the block or attribute that
+// follows them.
+func arrangeTopLevelItems(contents []byte, filename string, style Style) ([]byte, error) {
+	file, diags := hclsyntax.ParseConfig(contents, filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+	body := file.Body.(*hclsyntax.Body)
+
+	type itemRange struct {
+		name    string
+		isBlock bool
+		rng     hcl.Range
+	}
+	ranges := []itemRange{}
+	for name, attr := range body.Attributes {
+		ranges = append(ranges, itemRange{name: name, rng: attr.SrcRange})
+	}
+	for _, block := range body.Blocks {
+		ranges = append(ranges, itemRange{name: block.Type, isBlock: true, rng: block.Range()})
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].rng.Start.Byte < ranges[j].rng.Start.Byte })
+
+	items := []topLevelItem{}
+	start := 0
+	for i, item := range ranges {
+		end := item.rng.End.Byte
+		if newline := bytes.IndexByte(contents[end:], '\n'); newline >= 0 {
+			end += newline + 1
+		} else {
+			end = len(contents)
+		}
+		blankLines, itemContents := splitLeadingBlankLines(contents[start:end])
+		items = append(items, topLevelItem{name: item.name, isBlock: item.isBlock, blankLines: blankLines, contents: itemContents, originalIdx: i})
+		start = end
+	}
+	trailer := contents[start:]
+
+	if len(style.BlockOrder) > 0 {
+		rank := func(name string) int {
+			for i, ordered := range style.BlockOrder {
+				if ordered == name {
+					return i
+				}
+			}
+			return len(style.BlockOrder)
+		}
+		sort.SliceStable(items, func(i, j int) bool { return rank(items[i].name) < rank(items[j].name) })
+	}
+
+	var arranged bytes.Buffer
+	for i, item := range items {
+		blankLines := item.blankLines
+		switch {
+		case i == 0:
+			if item.originalIdx != 0 {
+				blankLines = 0
+			}
+		case style.BlankLinesBetweenBlocks >= 0 && (item.isBlock || items[i-1].isBlock):
+			blankLines = style.BlankLinesBetweenBlocks
+		case item.originalIdx == 0:
+			// The first item of the file was moved, so it has no blank line to keep.
+			blankLines = 1
+		}
+		arranged.WriteString(strings.Repeat("\n", blankLines))
+		arranged.Write(item.contents)
+	}
+	arranged.Write(trailer)
+	return arranged.Bytes(), nil
+}
+
+// splitLeadingBlankLines returns the number of blank lines at the start of the given contents, and the contents
+// after them.
+func splitLeadingBlankLines(contents []byte) (int, []byte) {
+	blankLines := 0
+	for {
+		newline := bytes.IndexByte(contents, '\n')
+		if newline < 0 || len(bytes.TrimSpace(contents[:newline])) > 0 {
+			return blankLines, contents
+		}
+		blankLines++
+		contents = contents[newline+1:]
+	}
+}
+
+// unalignAttributes replaces the spaces that align the equal signs of consecutive attributes, and of the keys of
+// objects, with a single space.
+func unalignAttributes(contents []byte, filename string) []byte {
+	tokens, _ := hclsyntax.LexConfig(contents, filename, hcl.InitialPos)
+
+	var unaligned bytes.Buffer
+	last := 0
+	for i, token := range tokens {
+		if token.Type != hclsyntax.TokenEqual || i == 0 {
+			continue
+		}
+		keyIdx := i - 1
+		if tokens[keyIdx].Type == hclsyntax.TokenCQuote {
+			for keyIdx > 0 && tokens[keyIdx].Type != hclsyntax.TokenOQuote {
+				keyIdx--
+			}
+		} else if tokens[keyIdx].Type != hclsyntax.TokenIdent {
+			continue
+		}
+		if keyIdx > 0 && !startsLine(tokens[keyIdx-1]) {
+			continue
+		}
+
+		gapStart, gapEnd := tokens[i-1].Range.End.Byte, token.Range.Start.Byte
+		if gapEnd-gapStart <= 1 || len(bytes.Trim(contents[gapStart:gapEnd], " ")) > 0 {
+			continue
+		}
+		unaligned.Write(contents[last:gapStart])
+		unaligned.WriteByte(' ')
+		last = gapEnd
+	}
+	unaligned.Write(contents[last:])
+	return unaligned.Bytes()
+}
+
+// startsLine returns true if the token that follows the given token is the first of its line, or of the contents of
+// an object.
+func startsLine(token hclsyntax.Token) bool {
+	switch token.Type {
+	case hclsyntax.TokenNewline, hclsyntax.TokenComment, hclsyntax.TokenOBrace, hclsyntax.TokenComma:
+		return true
+	}
+	return false
+}
+
+// reindent replaces each level of the canonical indentation of the given contents with the given number of spaces.
+// The contents of heredocs are kept as is, as their indentation is part of their value.
+func reindent(contents []byte, filename string, indentWidth int) []byte {
+	tokens, _ := hclsyntax.LexConfig(contents, filename, hcl.InitialPos)
+	heredocLines := map[int]bool{}
+	for i, token := range tokens {
+		if token.Type != hclsyntax.TokenOHeredoc {
+			continue
+		}
+		for _, closing := range tokens[i:] {
+			if closing.Type == hclsyntax.TokenCHeredoc {
+				for line := token.Range.Start.Line + 1; line <= closing.Range.Start.Line; line++ {
+					heredocLines[line] = true
+				}
+				break
+			}
+		}
+	}
+
+	lines := strings.Split(string(contents), "\n")
+	for i, line := range lines {
+		if heredocLines[i+1] {
+			continue
+		}
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+		levels, rest := indent/canonicalIndentWidth, indent%canonicalIndentWidth
+		lines[i] = strings.Repeat(" ", levels*indentWidth+rest) + line[indent:]
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// Custom error types
+
+type InvalidStyleSetting struct {
+	StylePath string
+	Setting   string
+	Reason    string
+}
+
+func (err InvalidStyleSetting) Error() string {
+	return fmt.Sprintf("Invalid %s in %s: it %s.", err.Setting, err.StylePath, err.Reason)
+}
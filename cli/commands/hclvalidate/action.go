@@ -0,0 +1,174 @@
+// `hclvalidate` command recursively looks for terragrunt configs in the directory tree starting at workingDir, parses
+// each of them, and prints the syntax and reference errors of all of them as json diagnostics. The dependency outputs
+// are not retrieved, so the mock outputs are used instead if any, and the outputs are unknown otherwise.
+
+package hclvalidate
+
+import (
+	"encoding/json"
+	goerrors "errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// Severities of the diagnostics.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+// ValidationResult is the result of hclvalidate, which is printed as json.
+type ValidationResult struct {
+	Valid        bool         `json:"valid"`
+	FileCount    int          `json:"file_count"`
+	ErrorCount   int          `json:"error_count"`
+	WarningCount int          `json:"warning_count"`
+	Diagnostics  []Diagnostic `json:"diagnostics"`
+}
+
+// Diagnostic is an error or warning found in a terragrunt config.
+type Diagnostic struct {
+	// The terragrunt config that was parsed when the diagnostic was found, which may differ from the file of the range
+	// when the diagnostic is in an included config.
+	ConfigPath string `json:"config_path"`
+	Severity   string `json:"severity"`
+	Summary    string `json:"summary"`
+	Detail     string `json:"detail,omitempty"`
+	// The range of the source the diagnostic is about, which is not set for the errors that are not about a
+	// specific part of the config.
+	Range *DiagnosticRange `json:"range,omitempty"`
+}
+
+// DiagnosticRange is the range of the source of a diagnostic.
+type DiagnosticRange struct {
+	Filename string        `json:"filename"`
+	Start    DiagnosticPos `json:"start"`
+	End      DiagnosticPos `json:"end"`
+}
+
+// DiagnosticPos is a position in the source of a diagnostic.
+type DiagnosticPos struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+	Byte   int `json:"byte"`
+}
+
+func Run(opts *options.TerragruntOptions) error {
+	opts.Logger.Debugf("Validating the terragrunt configs from the directory tree %s.", opts.WorkingDir)
+	configPaths, err := config.FindConfigFilesInPath(opts.WorkingDir, opts)
+	if err != nil {
+		return err
+	}
+	opts.Logger.Debugf("Found %d terragrunt configs", len(configPaths))
+
+	result := ValidationResult{FileCount: len(configPaths), Diagnostics: []Diagnostic{}}
+	for _, configPath := range configPaths {
+		// Keep going after a config fails to parse, so that the errors of all the configs are reported at once.
+		result.Diagnostics = append(result.Diagnostics, validateConfig(opts, configPath)...)
+	}
+	for _, diag := range result.Diagnostics {
+		if diag.Severity == SeverityError {
+			result.ErrorCount++
+		} else {
+			result.WarningCount++
+		}
+	}
+	result.Valid = result.ErrorCount == 0
+
+	resultJSON, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := fmt.Fprintf(opts.Writer, "%s\n", resultJSON); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if !result.Valid {
+		return errors.WithStackTrace(InvalidConfigs{ErrorCount: result.ErrorCount, FileCount: result.FileCount})
+	}
+	return nil
+}
+
+// validateConfig parses the terragrunt config at the given path, without retrieving the dependency outputs, and returns
+// the diagnostics of the errors found.
+func validateConfig(opts *options.TerragruntOptions, configPath string) []Diagnostic {
+	opts.Logger.Debugf("Validating %s", configPath)
+
+	configOpts := opts.Clone(configPath)
+	configOpts.SkipDependencyOutputs = true
+
+	_, err := config.ParseConfigFile(configPath, configOpts, nil, nil)
+	if err == nil {
+		return nil
+	}
+
+	displayPath := relPath(opts, configPath)
+	var diags hcl.Diagnostics
+	if !goerrors.As(err, &diags) {
+		// The errors that are not hcl diagnostics are not about a specific part of the config.
+		return []Diagnostic{{ConfigPath: displayPath, Severity: SeverityError, Summary: errors.Unwrap(err).Error()}}
+	}
+
+	converted := []Diagnostic{}
+	for _, diag := range diags {
+		converted = append(converted, convertDiagnostic(opts, displayPath, diag))
+	}
+	return converted
+}
+
+// convertDiagnostic converts the given hcl diagnostic into the json diagnostic of the given config.
+func convertDiagnostic(opts *options.TerragruntOptions, configPath string, diag *hcl.Diagnostic) Diagnostic {
+	converted := Diagnostic{
+		ConfigPath: configPath,
+		Severity:   SeverityError,
+		Summary:    diag.Summary,
+		Detail:     diag.Detail,
+	}
+	if diag.Severity == hcl.DiagWarning {
+		converted.Severity = SeverityWarning
+	}
+	if diag.Subject != nil {
+		converted.Range = &DiagnosticRange{
+			Filename: relPath(opts, diag.Subject.Filename),
+			Start:    DiagnosticPos{Line: diag.Subject.Start.Line, Column: diag.Subject.Start.Column, Byte: diag.Subject.Start.Byte},
+			End:      DiagnosticPos{Line: diag.Subject.End.Line, Column: diag.Subject.End.Column, Byte: diag.Subject.End.Byte},
+		}
+	}
+	return converted
+}
+
+// relPath returns the given path relative to the working dir, so that the diagnostics don't depend on where the
+// working dir is checked out. The paths outside of the working dir are kept as is.
+func relPath(opts *options.TerragruntOptions, path string) string {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return path
+	}
+	workingDir, err := filepath.Abs(opts.WorkingDir)
+	if err != nil {
+		return path
+	}
+	rel, err := filepath.Rel(workingDir, absPath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
+
+// Custom error types
+
+type InvalidConfigs struct {
+	ErrorCount int
+	FileCount  int
+}
+
+func (err InvalidConfigs) Error() string {
+	return fmt.Sprintf("Found %d errors while validating %d terragrunt configs.", err.ErrorCount, err.FileCount)
+}
@@ -0,0 +1,78 @@
+package hclvalidate
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestHCLValidate(t *testing.T) {
+	t.Parallel()
+
+	workingDir, err := filepath.Abs("../../../test/fixture-hclvalidate")
+	require.NoError(t, err)
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	tgOptions.Writer = &stdout
+	tgOptions.WorkingDir = workingDir
+
+	err = Run(tgOptions)
+	require.Error(t, err)
+	assert.Equal(t, InvalidConfigs{ErrorCount: 3, FileCount: 4}, errors.Unwrap(err))
+
+	result := ValidationResult{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.False(t, result.Valid)
+	assert.Equal(t, 4, result.FileCount)
+	assert.Equal(t, 3, result.ErrorCount)
+	assert.Equal(t, 0, result.WarningCount)
+
+	// The errors of all the configs are reported, and the dependency outputs are unknown instead of failing to be
+	// retrieved.
+	type diagLocation struct {
+		configPath string
+		summary    string
+		line       int
+	}
+	locations := []diagLocation{}
+	for _, diag := range result.Diagnostics {
+		require.NotNil(t, diag.Range)
+		assert.Equal(t, diag.ConfigPath, diag.Range.Filename)
+		locations = append(locations, diagLocation{configPath: diag.ConfigPath, summary: diag.Summary, line: diag.Range.Start.Line})
+	}
+	assert.ElementsMatch(t, []diagLocation{
+		{configPath: "reference-error/terragrunt.hcl", summary: "Unsupported attribute", line: 6},
+		{configPath: "reference-error/terragrunt.hcl", summary: "Error in function call", line: 7},
+		{configPath: "syntax-error/terragrunt.hcl", summary: "Invalid expression", line: 3},
+	}, locations)
+}
+
+func TestHCLValidateValid(t *testing.T) {
+	t.Parallel()
+
+	workingDir, err := filepath.Abs("../../../test/fixture-hclvalidate/valid")
+	require.NoError(t, err)
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	var stdout bytes.Buffer
+	tgOptions.Writer = &stdout
+	tgOptions.WorkingDir = workingDir
+
+	require.NoError(t, Run(tgOptions))
+
+	result := ValidationResult{}
+	require.NoError(t, json.Unmarshal(stdout.Bytes(), &result))
+	assert.Equal(t, ValidationResult{Valid: true, FileCount: 1, Diagnostics: []Diagnostic{}}, result)
+}
@@ -0,0 +1,19 @@
+package hclvalidate
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "hclvalidate"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Recursively find terragrunt configs and report all their errors as json diagnostics.",
+		Description: "The dependency outputs are not retrieved, so this is fast enough to run as a pre-commit hook or a CI check.",
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -0,0 +1,270 @@
+// `import-gen` command imports existing resources into the state of the unit in the working dir: it writes terraform
+// import blocks for them, to be imported by the next plan and apply, or runs terraform import for each of them. The
+// resources are given as an address and an ID, or listed in a CSV or JSON manifest to import them in a batch.
+
+package importgen
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// ImportBlocksFile is the file the import blocks are written to, next to the terragrunt config of the unit, so that it
+// is copied to the working directory of terraform along with the other files of the unit.
+const ImportBlocksFile = "terragrunt_imports.tf"
+
+const terraformCommandImport = "import"
+
+// Import is a resource to import: its address in the terraform code and its ID in the provider.
+type Import struct {
+	Address string `json:"address"`
+	ID      string `json:"id"`
+}
+
+func Run(opts *options.TerragruntOptions, args []string) error {
+	imports, err := readImports(opts, args)
+	if err != nil {
+		return err
+	}
+
+	terragruntConfig, err := config.ReadTerragruntConfig(opts)
+	if err != nil {
+		return err
+	}
+	evalContext, err := config.ParseConfigEvalContext(opts.TerragruntConfigPath, opts)
+	if err != nil {
+		return err
+	}
+	evalContext, err = config.EvalContextWithInputs(evalContext, terragruntConfig.Inputs)
+	if err != nil {
+		return err
+	}
+
+	for i := range imports {
+		id, err := config.EvaluateTemplate(imports[i].ID, evalContext)
+		if err != nil {
+			return err
+		}
+		imports[i].ID = id
+	}
+
+	if opts.ImportRun {
+		return runImports(opts, imports)
+	}
+
+	importBlocksFile := filepath.Join(filepath.Dir(opts.TerragruntConfigPath), ImportBlocksFile)
+	if err := writeImportBlocks(importBlocksFile, imports); err != nil {
+		return err
+	}
+	opts.Logger.Infof("Wrote %d import blocks to %s. Run terragrunt plan to review the imports, and terragrunt apply to import the resources.", len(imports), importBlocksFile)
+	return nil
+}
+
+// readImports returns the resource given as args, if any, followed by the resources of the manifest, if any.
+func readImports(opts *options.TerragruntOptions, args []string) ([]Import, error) {
+	imports := []Import{}
+	switch len(args) {
+	case 0:
+	case 2:
+		imports = append(imports, Import{Address: args[0], ID: args[1]})
+	default:
+		return nil, errors.WithStackTrace(InvalidImportArgs(args))
+	}
+
+	if opts.ImportManifest != "" {
+		manifestImports, err := readManifest(opts.ImportManifest)
+		if err != nil {
+			return nil, err
+		}
+		imports = append(imports, manifestImports...)
+	}
+
+	if len(imports) == 0 {
+		return nil, errors.WithStackTrace(MissingImports{})
+	}
+	return imports, nil
+}
+
+// readManifest reads the resources listed in the given manifest: a JSON file with a list of objects with address and
+// id keys, or else a CSV file with address,id rows, optionally preceded by an address,id header.
+func readManifest(path string) ([]Import, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	imports := []Import{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		if err := json.Unmarshal(contents, &imports); err != nil {
+			return nil, errors.WithStackTrace(InvalidManifest{Path: path, Reason: err.Error()})
+		}
+	} else {
+		reader := csv.NewReader(strings.NewReader(string(contents)))
+		reader.FieldsPerRecord = 2
+		reader.TrimLeadingSpace = true
+		records, err := reader.ReadAll()
+		if err != nil {
+			return nil, errors.WithStackTrace(InvalidManifest{Path: path, Reason: err.Error()})
+		}
+		for i, record := range records {
+			if i == 0 && strings.EqualFold(record[0], "address") && strings.EqualFold(record[1], "id") {
+				continue
+			}
+			imports = append(imports, Import{Address: record[0], ID: record[1]})
+		}
+	}
+
+	for _, resource := range imports {
+		if resource.Address == "" || resource.ID == "" {
+			return nil, errors.WithStackTrace(InvalidManifest{Path: path, Reason: "every resource must have an address and an id"})
+		}
+	}
+	return imports, nil
+}
+
+// writeImportBlocks writes an import block for each of the given resources to the given file, replacing the import
+// blocks of the file for the same addresses, if it exists.
+func writeImportBlocks(path string, imports []Import) error {
+	file := hclwrite.NewEmptyFile()
+	if util.FileExists(path) {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		var diags hcl.Diagnostics
+		file, diags = hclwrite.ParseConfig(contents, path, hcl.InitialPos)
+		if diags.HasErrors() {
+			return errors.WithStackTrace(diags)
+		}
+	}
+
+	body := file.Body()
+	for _, resource := range imports {
+		traversal, diags := hclsyntax.ParseTraversalAbs([]byte(resource.Address), path, hcl.InitialPos)
+		if diags.HasErrors() || !isResourceAddress(traversal) {
+			return errors.WithStackTrace(InvalidImportAddress(resource.Address))
+		}
+		to := hclwrite.TokensForTraversal(traversal)
+
+		removeImportBlock(body, to)
+		if len(body.Blocks()) > 0 || len(body.Attributes()) > 0 {
+			body.AppendNewline()
+		}
+		block := body.AppendNewBlock("import", nil)
+		block.Body().SetAttributeRaw("to", to)
+		block.Body().SetAttributeValue("id", cty.StringVal(resource.ID))
+	}
+
+	// The blocks that were replaced leave blank lines behind, which are trimmed at the top of the file.
+	contents := bytes.TrimLeft(hclwrite.Format(file.Bytes()), "\n")
+	if err := os.WriteFile(path, contents, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// isResourceAddress returns true if the given traversal is the address of a resource, e.g. aws_instance.web[0], in the
+// root module or in a module, e.g. module.app["prod"].aws_instance.web.
+func isResourceAddress(traversal hcl.Traversal) bool {
+	names := []string{}
+	for i, step := range traversal {
+		switch step := step.(type) {
+		case hcl.TraverseRoot:
+			names = append(names, step.Name)
+		case hcl.TraverseAttr:
+			names = append(names, step.Name)
+		case hcl.TraverseIndex:
+			// The instance keys only follow the names of modules and resources, e.g. not module[0].
+			if _, isIndex := traversal[i-1].(hcl.TraverseIndex); isIndex || len(names)%2 != 0 {
+				return false
+			}
+		default:
+			return false
+		}
+	}
+
+	for len(names) > 2 && names[0] == "module" {
+		names = names[2:]
+	}
+	return len(names) == 2 && names[0] != "module"
+}
+
+// removeImportBlock removes the import blocks of the given body that import to the given address.
+func removeImportBlock(body *hclwrite.Body, to hclwrite.Tokens) {
+	address := withoutSpaces(string(to.Bytes()))
+	for _, block := range body.Blocks() {
+		if block.Type() != "import" {
+			continue
+		}
+		attr := block.Body().GetAttribute("to")
+		if attr != nil && withoutSpaces(string(attr.Expr().BuildTokens(nil).Bytes())) == address {
+			body.RemoveBlock(block)
+		}
+	}
+}
+
+func withoutSpaces(str string) string {
+	return strings.Join(strings.Fields(str), "")
+}
+
+// runImports runs terraform import for each of the given resources, in order, stopping at the first import that fails.
+// The imports run as terraform commands of the unit, with its inputs, generated files and IAM role.
+func runImports(opts *options.TerragruntOptions, imports []Import) error {
+	for _, resource := range imports {
+		opts.Logger.Infof("Importing %s with ID %s", resource.Address, resource.ID)
+
+		importOpts := opts.Clone(opts.TerragruntConfigPath)
+		importOpts.TerraformCommand = terraformCommandImport
+		importOpts.OriginalTerraformCommand = terraformCommandImport
+		importOpts.TerraformCliArgs = []string{terraformCommandImport, resource.Address, resource.ID}
+		if err := terraform.Run(importOpts); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Custom error types
+
+type InvalidImportArgs []string
+
+func (err InvalidImportArgs) Error() string {
+	return fmt.Sprintf("Expected the address and the ID of the resource to import (Example: terragrunt import-gen aws_s3_bucket.this my-bucket), got %d args: %s", len(err), strings.Join(err, " "))
+}
+
+type MissingImports struct{}
+
+func (err MissingImports) Error() string {
+	return fmt.Sprintf("Missing the resources to import. Pass the address and the ID of a resource (Example: terragrunt import-gen aws_s3_bucket.this my-bucket), or a manifest with --%s.", FlagNameTerragruntImportManifest)
+}
+
+type InvalidManifest struct {
+	Path   string
+	Reason string
+}
+
+func (err InvalidManifest) Error() string {
+	return fmt.Sprintf("Invalid import manifest %s: %s", err.Path, err.Reason)
+}
+
+type InvalidImportAddress string
+
+func (err InvalidImportAddress) Error() string {
+	return fmt.Sprintf("Invalid resource address %s (Example: aws_s3_bucket.this or module.app.aws_instance.web[0])", string(err))
+}
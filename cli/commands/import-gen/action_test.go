@@ -0,0 +1,152 @@
+package importgen
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadManifest(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		file          string
+		contents      string
+		expected      []Import
+		expectedError string
+	}{
+		{
+			"csv",
+			"imports.csv",
+			"aws_s3_bucket.logs,my-logs\n\"aws_iam_role.this[\"\"app\"\"]\", app-role\n",
+			[]Import{{Address: "aws_s3_bucket.logs", ID: "my-logs"}, {Address: `aws_iam_role.this["app"]`, ID: "app-role"}},
+			"",
+		},
+		{
+			"csv with header",
+			"imports.csv",
+			"address,id\naws_s3_bucket.logs,${inputs.bucket}\n",
+			[]Import{{Address: "aws_s3_bucket.logs", ID: "${inputs.bucket}"}},
+			"",
+		},
+		{
+			"json",
+			"imports.json",
+			`[{"address": "module.app.aws_instance.web[0]", "id": "i-123"}]`,
+			[]Import{{Address: "module.app.aws_instance.web[0]", ID: "i-123"}},
+			"",
+		},
+		{"csv missing id", "imports.csv", "aws_s3_bucket.logs\n", nil, "wrong number of fields"},
+		{"json missing id", "imports.json", `[{"address": "aws_s3_bucket.logs"}]`, nil, "every resource must have an address and an id"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			path := filepath.Join(t.TempDir(), testCase.file)
+			require.NoError(t, os.WriteFile(path, []byte(testCase.contents), 0644))
+
+			imports, err := readManifest(path)
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, imports)
+		})
+	}
+}
+
+func TestWriteImportBlocks(t *testing.T) {
+	t.Parallel()
+
+	path := filepath.Join(t.TempDir(), ImportBlocksFile)
+	require.NoError(t, writeImportBlocks(path, []Import{
+		{Address: "aws_s3_bucket.logs", ID: "old-logs"},
+		{Address: `aws_iam_role.this["app"]`, ID: "app-role"},
+	}))
+	require.NoError(t, writeImportBlocks(path, []Import{{Address: "aws_s3_bucket.logs", ID: "my-logs"}}))
+
+	contents, err := os.ReadFile(path)
+	require.NoError(t, err)
+	expected := `import {
+  to = aws_iam_role.this["app"]
+  id = "app-role"
+}
+
+import {
+  to = aws_s3_bucket.logs
+  id = "my-logs"
+}
+`
+	assert.Equal(t, expected, string(contents))
+
+	for _, address := range []string{"aws_s3_bucket", "module.app", "aws_s3_bucket.logs.id", "module[0].app.aws_s3_bucket.logs", "aws_s3_bucket.logs[0][1]"} {
+		err = writeImportBlocks(path, []Import{{Address: address, ID: "my-logs"}})
+		require.Error(t, err, address)
+		assert.Contains(t, err.Error(), "Invalid resource address")
+	}
+	require.NoError(t, writeImportBlocks(path, []Import{{Address: `module.app["prod"].aws_s3_bucket.logs[0]`, ID: "my-logs"}}))
+}
+
+func TestRunWritesImportBlocks(t *testing.T) {
+	t.Parallel()
+
+	unitDir := t.TempDir()
+	terragruntConfig := `
+locals {
+  env = "prod"
+}
+
+inputs = {
+  bucket = "${local.env}-logs"
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, config.DefaultTerragruntConfigPath), []byte(terragruntConfig), 0644))
+	manifest := filepath.Join(unitDir, "imports.csv")
+	require.NoError(t, os.WriteFile(manifest, []byte("aws_s3_bucket_policy.logs,${inputs.bucket}\n"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(unitDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.ImportManifest = manifest
+
+	require.NoError(t, Run(opts, []string{"aws_s3_bucket.logs", "${local.env}-${inputs.bucket}"}))
+
+	contents, err := os.ReadFile(filepath.Join(unitDir, ImportBlocksFile))
+	require.NoError(t, err)
+	expected := `import {
+  to = aws_s3_bucket.logs
+  id = "prod-prod-logs"
+}
+
+import {
+  to = aws_s3_bucket_policy.logs
+  id = "prod-logs"
+}
+`
+	assert.Equal(t, expected, string(contents))
+}
+
+func TestRunInvalidArgs(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	err = Run(opts, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Missing the resources to import")
+
+	err = Run(opts, []string{"aws_s3_bucket.logs"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Expected the address and the ID")
+}
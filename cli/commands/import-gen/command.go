@@ -0,0 +1,43 @@
+package importgen
+
+import (
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "import-gen"
+
+	FlagNameTerragruntImportManifest = "terragrunt-import-manifest"
+	FlagNameTerragruntImportRun      = "terragrunt-import-run"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntImportManifest,
+			Aliases:     []string{"manifest"},
+			Destination: &opts.ImportManifest,
+			EnvVar:      "TERRAGRUNT_IMPORT_MANIFEST",
+			Usage:       "The path of a CSV file with address,id rows, or of a JSON file with a list of {\"address\", \"id\"} objects, listing the resources to import.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntImportRun,
+			Aliases:     []string{"run-import"},
+			Destination: &opts.ImportRun,
+			EnvVar:      "TERRAGRUNT_IMPORT_RUN",
+			Usage:       "Run terraform import for each resource, instead of writing import blocks.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Write terraform import blocks for resources of the unit, e.g. `terragrunt import-gen aws_s3_bucket.this my-bucket`, or import them with terraform import.",
+		UsageText:   "terragrunt import-gen <address> <id>",
+		Description: "The IDs are rendered as templates against the config of the unit, so that they can reference its resolved inputs, e.g. '${inputs.bucket_name}', and its locals and dependency outputs.",
+		Flags:       NewFlags(opts).Sort(),
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx), ctx.Args().Slice()) },
+	}
+}
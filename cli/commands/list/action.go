@@ -0,0 +1,170 @@
+// `list` command discovers the units in the working dir, as run-all does, and writes them with their terraform source,
+// tags, dependencies and included files, either as a list of paths or as JSON. The units can be filtered on their tags,
+// their source and the units they depend on.
+
+package list
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The formats the units can be listed in.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Unit is a unit as listed by the list command. The paths are relative to the working dir, unless the unit or file is
+// outside of it.
+type Unit struct {
+	Path         string   `json:"path"`
+	Source       string   `json:"source,omitempty"`
+	Tags         []string `json:"tags"`
+	Dependencies []string `json:"dependencies"`
+	Includes     []string `json:"includes"`
+}
+
+func Run(opts *options.TerragruntOptions) error {
+	if opts.ListFormat != "" && opts.ListFormat != FormatText && opts.ListFormat != FormatJSON {
+		return errors.WithStackTrace(UnsupportedListFormat(opts.ListFormat))
+	}
+
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	units, err := filterUnits(opts, stack.Modules)
+	if err != nil {
+		return err
+	}
+
+	if opts.ListFormat == FormatJSON {
+		unitsJSON, err := json.MarshalIndent(units, "", "  ")
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		_, err = fmt.Fprintf(opts.Writer, "%s\n", unitsJSON)
+		return errors.WithStackTrace(err)
+	}
+
+	for _, unit := range units {
+		if _, err := fmt.Fprintln(opts.Writer, unit.Path); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+	return nil
+}
+
+// filterUnits returns the units of the given modules that match the filters of the given options, sorted by path. The
+// modules excluded from the stack, e.g. with --terragrunt-exclude-dir, and the external dependencies are left out.
+func filterUnits(opts *options.TerragruntOptions, modules []*configstack.TerraformModule) ([]Unit, error) {
+	var dependsOn *configstack.TerraformModule
+	if opts.ListDependsOn != "" {
+		dependsOnPath := opts.ListDependsOn
+		if !filepath.IsAbs(dependsOnPath) {
+			dependsOnPath = filepath.Join(opts.WorkingDir, dependsOnPath)
+		}
+		for _, module := range modules {
+			if util.CleanPath(module.Path) == util.CleanPath(dependsOnPath) {
+				dependsOn = module
+			}
+		}
+		if dependsOn == nil {
+			return nil, errors.WithStackTrace(UnitNotFound(opts.ListDependsOn))
+		}
+	}
+
+	units := []Unit{}
+	for _, module := range modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+		if !hasTags(module, opts.ListTags) {
+			continue
+		}
+		unit := newUnit(opts, module)
+		if opts.ListSource != "" && !strings.Contains(unit.Source, opts.ListSource) {
+			continue
+		}
+		if dependsOn != nil && !dependsOnModule(module, dependsOn, map[string]bool{}) {
+			continue
+		}
+		units = append(units, unit)
+	}
+	sort.Slice(units, func(i, j int) bool { return units[i].Path < units[j].Path })
+	return units, nil
+}
+
+func newUnit(opts *options.TerragruntOptions, module *configstack.TerraformModule) Unit {
+	unit := Unit{
+		Path:         util.GetPathRelativeToIfInside(module.Path, opts.WorkingDir),
+		Tags:         []string{},
+		Dependencies: []string{},
+		Includes:     []string{},
+	}
+	if module.Config.Terraform != nil && module.Config.Terraform.Source != nil {
+		unit.Source = *module.Config.Terraform.Source
+	}
+	unit.Tags = append(unit.Tags, module.Config.Tags...)
+	for _, dependency := range module.Dependencies {
+		unit.Dependencies = append(unit.Dependencies, util.GetPathRelativeToIfInside(dependency.Path, opts.WorkingDir))
+	}
+	sort.Strings(unit.Dependencies)
+	for _, include := range module.Config.ProcessedIncludes {
+		includePath := include.Path
+		if !filepath.IsAbs(includePath) {
+			includePath = filepath.Join(module.Path, includePath)
+		}
+		unit.Includes = append(unit.Includes, util.GetPathRelativeToIfInside(includePath, opts.WorkingDir))
+	}
+	sort.Strings(unit.Includes)
+	return unit
+}
+
+func hasTags(module *configstack.TerraformModule, tags []string) bool {
+	for _, tag := range tags {
+		if !util.ListContainsElement(module.Config.Tags, tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// dependsOnModule returns true if the given module depends on the other module, directly or through its dependencies.
+func dependsOnModule(module *configstack.TerraformModule, other *configstack.TerraformModule, visited map[string]bool) bool {
+	for _, dependency := range module.Dependencies {
+		if visited[dependency.Path] {
+			continue
+		}
+		visited[dependency.Path] = true
+		if dependency.Path == other.Path || dependsOnModule(dependency, other, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+// Custom error types
+
+type UnsupportedListFormat string
+
+func (err UnsupportedListFormat) Error() string {
+	return fmt.Sprintf("Unsupported list format %s. The supported formats are %s and %s.", string(err), FormatText, FormatJSON)
+}
+
+type UnitNotFound string
+
+func (err UnitNotFound) Error() string {
+	return fmt.Sprintf("Could not find the unit %s in the units of the working directory.", string(err))
+}
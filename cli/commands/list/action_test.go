@@ -0,0 +1,129 @@
+package list
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunListsUnits(t *testing.T) {
+	t.Parallel()
+
+	stackDir := t.TempDir()
+	writeFile := func(path string, contents string) {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+	writeFile(filepath.Join(stackDir, "root.hcl"), `tags = ["prod"]`)
+	writeFile(filepath.Join(stackDir, "vpc", config.DefaultTerragruntConfigPath), `
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+tags = ["network"]
+
+terraform {
+  source = "git::https://example.com/modules.git//vpc?ref=v1.2.0"
+}
+`)
+	writeFile(filepath.Join(stackDir, "app", config.DefaultTerragruntConfigPath), `
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+dependencies {
+  paths = ["../vpc"]
+}
+
+terraform {
+  source = "git::https://example.com/modules.git//app?ref=v2.0.0"
+}
+`)
+	writeFile(filepath.Join(stackDir, "web", config.DefaultTerragruntConfigPath), `
+dependencies {
+  paths = ["../app"]
+}
+
+terraform {
+  source = "git::https://example.com/modules.git//web?ref=v2.0.0"
+}
+`)
+
+	testCases := []struct {
+		name      string
+		tags      []string
+		source    string
+		dependsOn string
+		expected  []string
+	}{
+		{"all", nil, "", "", []string{"app", "vpc", "web"}},
+		{"tags", []string{"network"}, "", "", []string{"vpc"}},
+		{"all tags", []string{"prod", "network"}, "", "", []string{}},
+		{"source", nil, "ref=v2.0.0", "", []string{"app", "web"}},
+		{"depends on", nil, "", "vpc", []string{"app", "web"}},
+		{"depends on and tags", []string{"prod"}, "", "vpc", []string{"app"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+			require.NoError(t, err)
+			opts.WorkingDir = stackDir
+			opts.ListFormat = FormatJSON
+			opts.ListTags = testCase.tags
+			opts.ListSource = testCase.source
+			opts.ListDependsOn = testCase.dependsOn
+			output := &bytes.Buffer{}
+			opts.Writer = output
+
+			require.NoError(t, Run(opts))
+
+			var units []Unit
+			require.NoError(t, json.Unmarshal(output.Bytes(), &units))
+			paths := []string{}
+			for _, unit := range units {
+				paths = append(paths, unit.Path)
+			}
+			assert.Equal(t, testCase.expected, paths)
+
+			if testCase.name == "all" {
+				assert.Equal(t, Unit{
+					Path:         "app",
+					Source:       "git::https://example.com/modules.git//app?ref=v2.0.0",
+					Tags:         []string{"prod"},
+					Dependencies: []string{"vpc"},
+					Includes:     []string{"root.hcl"},
+				}, units[0])
+				// The tags of the unit override the ones of the included config, as with the other attributes.
+				assert.Equal(t, []string{"network"}, units[1].Tags)
+			}
+		})
+	}
+}
+
+func TestRunUnknownDependsOn(t *testing.T) {
+	t.Parallel()
+
+	stackDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(stackDir, "vpc"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(stackDir, "vpc", config.DefaultTerragruntConfigPath), []byte(`terraform { source = "../modules/vpc" }`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = stackDir
+	opts.ListDependsOn = "db"
+
+	err = Run(opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Could not find the unit db")
+}
@@ -0,0 +1,62 @@
+package list
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/commands/completion"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "list"
+
+	FlagNameTerragruntListFormat    = "terragrunt-list-format"
+	FlagNameTerragruntListTag       = "terragrunt-list-tag"
+	FlagNameTerragruntListSource    = "terragrunt-list-source"
+	FlagNameTerragruntListDependsOn = "terragrunt-list-depends-on"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntListFormat,
+			Aliases:     []string{"format"},
+			Destination: &opts.ListFormat,
+			EnvVar:      "TERRAGRUNT_LIST_FORMAT",
+			Usage:       "The format to list the units in: " + FormatText + " or " + FormatJSON + ". Defaults to " + FormatText + ".",
+		},
+		&cli.SliceFlag[string]{
+			Name:        FlagNameTerragruntListTag,
+			Aliases:     []string{"tag"},
+			Destination: &opts.ListTags,
+			EnvVar:      "TERRAGRUNT_LIST_TAG",
+			Usage:       "Only list the units with the given tag. Can be specified multiple times, to list the units with all the tags.",
+			Complete:    completion.Tags(opts),
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntListSource,
+			Aliases:     []string{"source-contains"},
+			Destination: &opts.ListSource,
+			EnvVar:      "TERRAGRUNT_LIST_SOURCE",
+			Usage:       "Only list the units whose terraform source contains the given text.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntListDependsOn,
+			Aliases:     []string{"depends-on"},
+			Destination: &opts.ListDependsOn,
+			EnvVar:      "TERRAGRUNT_LIST_DEPENDS_ON",
+			Usage:       "Only list the units that depend on the unit at the given path, directly or through other units.",
+			Complete:    completion.UnitPaths(opts),
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Aliases:     []string{"find"},
+		Usage:       "List the units in the current directory tree, with their source, tags, dependencies and included files.",
+		Description: "The units can be filtered on their tags, their source and the units they depend on, as well as with --terragrunt-include-dir and --terragrunt-exclude-dir.",
+		Flags:       NewFlags(opts).Sort(),
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -13,7 +13,12 @@ func Run(opts *options.TerragruntOptions) error {
 		return err
 	}
 
-	js, err := stack.JsonModuleDeployOrder(opts.TerraformCommand)
+	var js string
+	if opts.ModuleGroupsMatrixFormat != "" {
+		js, err = stack.CIMatrixModuleDeployOrder(opts.TerraformCommand, opts.ModuleGroupsMatrixFormat, opts.Parallelism)
+	} else {
+		js, err = stack.JsonModuleDeployOrder(opts.TerraformCommand)
+	}
 	if err != nil {
 		return err
 	}
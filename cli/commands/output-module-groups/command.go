@@ -2,7 +2,9 @@ package outputmodulegroups
 
 import (
 	"fmt"
+	"strings"
 
+	"github.com/gruntwork-io/terragrunt/configstack"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/pkg/cli"
 )
@@ -11,12 +13,27 @@ const (
 	CommandName       = "output-module-groups"
 	SubCommandApply   = "apply"
 	SubCommandDestroy = "destroy"
+
+	FlagNameTerragruntMatrixFormat = "terragrunt-matrix-format"
 )
 
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntMatrixFormat,
+			Aliases:     []string{"matrix-format"},
+			Destination: &opts.ModuleGroupsMatrixFormat,
+			EnvVar:      "TERRAGRUNT_MATRIX_FORMAT",
+			Usage:       "Output the groups as a CI matrix for the given CI system: " + strings.Join(configstack.CIMatrixFormats, ", ") + ".",
+		},
+	}
+}
+
 func NewCommand(opts *options.TerragruntOptions) *cli.Command {
 	return &cli.Command{
 		Name:        CommandName,
 		Usage:       "Output groups of modules ordered by command (apply or destroy) as a list of list in JSON (useful for CI use cases).",
+		Flags:       NewFlags(opts).Sort(),
 		Subcommands: subCommands(opts),
 		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
 	}
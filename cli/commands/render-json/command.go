@@ -8,8 +8,9 @@ import (
 const (
 	CommandName = "render-json"
 
-	FlagNameTerragruntJSONOut = "terragrunt-json-out"
-	FlagNameWithMetadata      = "with-metadata"
+	FlagNameTerragruntJSONOut   = "terragrunt-json-out"
+	FlagNameWithMetadata        = "with-metadata"
+	FlagNameWithSourcePositions = "with-source-positions"
 )
 
 func NewFlags(opts *options.TerragruntOptions) cli.Flags {
@@ -24,13 +25,18 @@ func NewFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.RenderJsonWithMetadata,
 			Usage:       "Add metadata to the rendered JSON file.",
 		},
+		&cli.BoolFlag{
+			Name:        FlagNameWithSourcePositions,
+			Destination: &opts.RenderJsonWithSourcePositions,
+			Usage:       "Add the line and include level each value was defined at to the metadata of the rendered JSON file. Implies --with-metadata.",
+		},
 	}
 }
 
 func NewCommand(opts *options.TerragruntOptions) *cli.Command {
 	return &cli.Command{
 		Name:        CommandName,
-		Usage:       "Render the final terragrunt config, with all variables, includes, and functions resolved, as json.",
+		Usage:       "DEPRECATED: use `render --format json --write`. Render the final terragrunt config, with all variables, includes, and functions resolved, as json.",
 		Description: "This is useful for enforcing policies using static analysis tools like Open Policy Agent, or for debugging your terragrunt config.",
 		Flags:       NewFlags(opts).Sort(),
 		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
@@ -0,0 +1,183 @@
+// `render` command renders the parsed TerragruntConfig struct, with all the included configs merged and all the
+// functions resolved, as HCL, json or yaml, so that it can be reviewed or processed by other tools. The config is printed
+// to stdout, or written next to the config of the unit with --write.
+// The json and yaml formats use the cty representation of the config as an intermediary.
+// NOTE: An unspecified advantage of using the cty representation is that the final block outputs would be a map
+// representation, which is easier to work with than the list representation that will be returned by a naive go-struct
+// to json conversion.
+
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+	"gopkg.in/yaml.v3"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+func Run(opts *options.TerragruntOptions) error {
+	if err := validateOptions(opts); err != nil {
+		return err
+	}
+
+	// Unless the dependency-resolved view is requested, the config is rendered without reading the outputs of the
+	// dependencies, so that it can be rendered before they are applied.
+	if !opts.RenderResolveDependencies {
+		opts.SkipDependencyOutputs = true
+	}
+
+	target := terraform.NewTarget(terraform.TargetPointParseConfig, runRender)
+
+	return terraform.RunWithTarget(opts, target)
+}
+
+// validateOptions checks the format and the metadata flags before the config is parsed.
+func validateOptions(opts *options.TerragruntOptions) error {
+	if opts.RenderFormat != "" && !util.ListContainsElement(Formats, opts.RenderFormat) {
+		return errors.WithStackTrace(UnsupportedRenderFormat(opts.RenderFormat))
+	}
+	if opts.RenderFormat == FormatHCL && (opts.RenderJsonWithMetadata || opts.RenderJsonWithSourcePositions) {
+		return errors.WithStackTrace(MetadataNotSupported(opts.RenderFormat))
+	}
+	return nil
+}
+
+func runRender(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+	if cfg == nil {
+		return fmt.Errorf("Terragrunt was not able to render the config because it received no config. This is almost certainly a bug in Terragrunt. Please open an issue on github.com/gruntwork-io/terragrunt with this message and the contents of your terragrunt.hcl.")
+	}
+
+	format := opts.RenderFormat
+	if format == "" {
+		format = FormatHCL
+	}
+	withMetadata := opts.RenderJsonWithMetadata || opts.RenderJsonWithSourcePositions
+
+	if format == FormatJSON || format == FormatYAML {
+		dependentModules := configstack.FindWhereWorkingDirIsIncluded(opts, cfg)
+		var dependentModulesPath []*string
+		for _, module := range dependentModules {
+			dependentModulesPath = append(dependentModulesPath, &module.Path)
+		}
+
+		cfg.DependentModulesPath = dependentModulesPath
+		cfg.SetFieldMetadata(config.MetadataDependentModules, map[string]interface{}{config.FoundInFile: opts.TerragruntConfigPath})
+	}
+
+	rendered, err := renderConfig(cfg, format, withMetadata)
+	if err != nil {
+		return err
+	}
+
+	if !opts.RenderWrite && opts.RenderOut == "" {
+		_, err = fmt.Fprintf(opts.Writer, "%s", rendered)
+		return errors.WithStackTrace(err)
+	}
+
+	outPath := opts.RenderOut
+	if outPath == "" {
+		outPath = DefaultOutName + "." + format
+	}
+	if !filepath.IsAbs(outPath) {
+		outPath = filepath.Join(filepath.Dir(opts.TerragruntConfigPath), outPath)
+	}
+	if err := util.EnsureDirectory(filepath.Dir(outPath)); err != nil {
+		return err
+	}
+	opts.Logger.Debugf("Rendering config %s as %s to %s", opts.TerragruntConfigPath, format, outPath)
+
+	if err := os.WriteFile(outPath, rendered, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// renderConfig renders the given config in the given format, with the metadata of its fields if withMetadata is set.
+func renderConfig(cfg *config.TerragruntConfig, format string, withMetadata bool) ([]byte, error) {
+	if format == FormatHCL {
+		if withMetadata {
+			return nil, errors.WithStackTrace(MetadataNotSupported(format))
+		}
+		return config.TerragruntConfigAsHcl(cfg)
+	}
+	if format != FormatJSON && format != FormatYAML {
+		return nil, errors.WithStackTrace(UnsupportedRenderFormat(format))
+	}
+
+	var terragruntConfigCty cty.Value
+	var err error
+	if withMetadata {
+		terragruntConfigCty, err = config.TerragruntConfigAsCtyWithMetadata(cfg)
+	} else {
+		terragruntConfigCty, err = config.TerragruntConfigAsCty(cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	jsonBytes, err := MarshalCtyValueJSONWithoutType(terragruntConfigCty)
+	if err != nil {
+		return nil, err
+	}
+	if format == FormatJSON {
+		return append(jsonBytes, '\n'), nil
+	}
+
+	// The yaml is converted from the json rendering, which has the same structure, with the keys sorted.
+	var value interface{}
+	if err := json.Unmarshal(jsonBytes, &value); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	var yamlBuffer bytes.Buffer
+	encoder := yaml.NewEncoder(&yamlBuffer)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(value); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return yamlBuffer.Bytes(), nil
+}
+
+// MarshalCtyValueJSONWithoutType marshals the given cty.Value object into a JSON object that does not have the type.
+// Using ctyjson directly would render a json object with two attributes, "value" and "type", and this function returns
+// just the "value".
+// NOTE: We have to do two marshalling passes so that we can extract just the value.
+func MarshalCtyValueJSONWithoutType(ctyVal cty.Value) ([]byte, error) {
+	jsonBytesIntermediate, err := ctyjson.Marshal(ctyVal, cty.DynamicPseudoType)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	var ctyJsonOutput config.CtyJsonOutput
+	if err := json.Unmarshal(jsonBytesIntermediate, &ctyJsonOutput); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	jsonBytes, err := json.Marshal(ctyJsonOutput.Value)
+	return jsonBytes, errors.WithStackTrace(err)
+}
+
+// Custom error types
+
+type UnsupportedRenderFormat string
+
+func (format UnsupportedRenderFormat) Error() string {
+	return fmt.Sprintf("Unsupported render format %q, supported formats are: %s, %s, %s.", string(format), FormatHCL, FormatJSON, FormatYAML)
+}
+
+type MetadataNotSupported string
+
+func (format MetadataNotSupported) Error() string {
+	return fmt.Sprintf("The metadata of the config can't be rendered in the %s format, use the %s or %s format instead.", string(format), FormatJSON, FormatYAML)
+}
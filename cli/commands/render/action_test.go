@@ -0,0 +1,118 @@
+package render
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderFormats(t *testing.T) {
+	t.Parallel()
+
+	unitDir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(unitDir, "vpc"), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, "vpc", config.DefaultTerragruntConfigPath), []byte(``), 0644))
+	require.NoError(t, os.MkdirAll(filepath.Join(unitDir, "app"), os.ModePerm))
+	configPath := filepath.Join(unitDir, "app", config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+locals {
+  env = "prod"
+}
+
+dependency "vpc" {
+  config_path = "../vpc"
+
+  mock_outputs = {
+    vpc_id = "mock-vpc"
+  }
+}
+
+inputs = {
+  name   = "app-${local.env}"
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`), 0644))
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{FormatHCL, `vpc_id = "mock-vpc"`},
+		{FormatJSON, `"vpc_id":"mock-vpc"`},
+		{FormatYAML, `vpc_id: mock-vpc`},
+	}
+
+	for _, testCase := range testCases {
+		opts, err := options.NewTerragruntOptionsForTest(configPath)
+		require.NoError(t, err)
+		opts.RenderFormat = testCase.format
+		output := &bytes.Buffer{}
+		opts.Writer = output
+
+		opts.SkipDependencyOutputs = true
+		cfg, err := config.ReadTerragruntConfig(opts)
+		require.NoError(t, err)
+
+		require.NoError(t, runRender(opts, cfg))
+		assert.Contains(t, output.String(), testCase.expected, testCase.format)
+		assert.Contains(t, output.String(), "app-prod", testCase.format)
+	}
+}
+
+func TestRenderWrite(t *testing.T) {
+	t.Parallel()
+
+	unitDir := t.TempDir()
+	configPath := filepath.Join(unitDir, config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`inputs = { name = "app" }`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	opts.RenderFormat = FormatYAML
+	opts.RenderWrite = true
+	output := &bytes.Buffer{}
+	opts.Writer = output
+
+	cfg, err := config.ReadTerragruntConfig(opts)
+	require.NoError(t, err)
+
+	require.NoError(t, runRender(opts, cfg))
+	assert.Empty(t, output.String())
+	rendered, err := os.ReadFile(filepath.Join(unitDir, "terragrunt_rendered.yaml"))
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), `name: app`)
+
+	opts.RenderFormat = FormatJSON
+	opts.RenderOut = filepath.Join("out", "config.json")
+	require.NoError(t, runRender(opts, cfg))
+	rendered, err = os.ReadFile(filepath.Join(unitDir, "out", "config.json"))
+	require.NoError(t, err)
+	assert.Contains(t, string(rendered), `"inputs":{"name":"app"}`)
+}
+
+func TestRunErrors(t *testing.T) {
+	t.Parallel()
+
+	unitDir := t.TempDir()
+	configPath := filepath.Join(unitDir, config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`inputs = { name = "app" }`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	opts.RenderFormat = "toml"
+	err = Run(opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Unsupported render format "toml"`)
+
+	opts.RenderFormat = FormatHCL
+	opts.RenderJsonWithMetadata = true
+	err = Run(opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "metadata of the config can't be rendered in the hcl format")
+}
@@ -0,0 +1,76 @@
+package render
+
+import (
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "render"
+
+	FlagNameFormat              = "format"
+	FlagNameWrite               = "write"
+	FlagNameOut                 = "out"
+	FlagNameWithMetadata        = "with-metadata"
+	FlagNameWithSourcePositions = "with-source-positions"
+	FlagNameResolveDependencies = "resolve-dependencies"
+
+	FormatHCL  = "hcl"
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+
+	// DefaultOutName is the name of the file the config is written to with --write, followed by the format as
+	// extension.
+	DefaultOutName = "terragrunt_rendered"
+)
+
+// Formats are the formats the config can be rendered in.
+var Formats = []string{FormatHCL, FormatJSON, FormatYAML}
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameFormat,
+			Destination: &opts.RenderFormat,
+			EnvVar:      "TERRAGRUNT_RENDER_FORMAT",
+			Usage:       "The format to render the config in: " + strings.Join(Formats, ", ") + ".",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameWrite,
+			Destination: &opts.RenderWrite,
+			Usage:       "Write the rendered config to " + DefaultOutName + ".<format> next to the config of the unit, rather than printing it.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameOut,
+			Destination: &opts.RenderOut,
+			Usage:       "The path, relative to the config of the unit, to write the rendered config to. Implies --write.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameWithMetadata,
+			Destination: &opts.RenderJsonWithMetadata,
+			Usage:       "Add the file each value was defined in to the rendered config. Only supported with the json and yaml formats.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameWithSourcePositions,
+			Destination: &opts.RenderJsonWithSourcePositions,
+			Usage:       "Add the line and include level each value was defined at to the metadata of the rendered config. Implies --with-metadata.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameResolveDependencies,
+			Destination: &opts.RenderResolveDependencies,
+			Usage:       "Read the outputs of the dependencies to render the config, rather than using their mock outputs.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Print or write the final terragrunt config, with all variables, includes, and functions resolved, as HCL, json or yaml.",
+		Description: "This is useful for reviewing the config that terragrunt will actually use, once all the included configs are merged in, or for processing it with static analysis tools like Open Policy Agent.",
+		Flags:       NewFlags(opts).Sort(),
+		Action:      func(ctx *cli.Context) error { return Run(opts.OptionsFromContext(ctx)) },
+	}
+}
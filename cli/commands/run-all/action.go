@@ -1,6 +1,9 @@
 package runall
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/configstack"
 	"github.com/gruntwork-io/terragrunt/options"
@@ -38,16 +41,35 @@ func Run(opts *options.TerragruntOptions) error {
 		}
 	}
 
+	if opts.RunAllOutputFormat != "" {
+		if opts.TerraformCommand != "output" {
+			return errors.WithStackTrace(FormatRequiresOutputCommand{command: opts.TerraformCommand})
+		}
+		if opts.RunAllOutputFormat != configstack.OutputFormatJSON {
+			return errors.WithStackTrace(UnsupportedOutputFormat(opts.RunAllOutputFormat))
+		}
+	}
+
 	stack, err := configstack.FindStackInSubfolders(opts, nil)
 	if err != nil {
 		return err
 	}
 
 	opts.Logger.Debugf("%s", stack.String())
+	if opts.RunAllPreviewOrder {
+		return stack.PreviewModuleRunOrder(opts.Writer, opts.TerraformCommand)
+	}
+
 	if err := stack.LogModuleDeployOrder(opts.Logger, opts.TerraformCommand); err != nil {
 		return err
 	}
 
+	if opts.TerraformCommand == "destroy" {
+		if err := checkExternalDependents(opts, stack); err != nil {
+			return err
+		}
+	}
+
 	var prompt string
 	switch opts.TerraformCommand {
 	case "apply":
@@ -69,3 +91,26 @@ func Run(opts *options.TerragruntOptions) error {
 
 	return stack.Run(opts)
 }
+
+// checkExternalDependents warns about the modules outside of the stack that depend on modules of the stack, as
+// destroying the stack would break them, and returns an error unless --terragrunt-ignore-external-dependents is set.
+func checkExternalDependents(opts *options.TerragruntOptions, stack *configstack.Stack) error {
+	dependents := stack.FindExternalDependents(opts)
+	if len(dependents) == 0 {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("The following modules are outside of the stack, but depend on modules that will be destroyed:\n")
+	paths := []string{}
+	for _, dependent := range dependents {
+		sb.WriteString(fmt.Sprintf("- Module %s depends on %s\n", dependent.Path, strings.Join(dependent.Dependencies, ", ")))
+		paths = append(paths, dependent.Path)
+	}
+	opts.Logger.Warn(sb.String())
+
+	if opts.IgnoreExternalDependents {
+		return nil
+	}
+	return errors.WithStackTrace(ExternalDependentsFound{Paths: paths})
+}
@@ -25,3 +25,28 @@ func TestMissingRunAllArguments(t *testing.T) {
 	fmt.Println(err, errors.Unwrap(err))
 	assert.True(t, ok)
 }
+
+func TestRunAllFormatRequiresOutputCommand(t *testing.T) {
+	t.Parallel()
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	tgOptions.TerraformCommand = "plan"
+	tgOptions.RunAllOutputFormat = "json"
+
+	err = Run(tgOptions)
+	require.Error(t, err)
+
+	_, ok := errors.Unwrap(err).(FormatRequiresOutputCommand)
+	assert.True(t, ok)
+
+	tgOptions.TerraformCommand = "output"
+	tgOptions.RunAllOutputFormat = "yaml"
+
+	err = Run(tgOptions)
+	require.Error(t, err)
+
+	_, ok = errors.Unwrap(err).(UnsupportedOutputFormat)
+	assert.True(t, ok)
+}
@@ -0,0 +1,23 @@
+package runall
+
+import (
+	"context"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/agent"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// runOnAgent dispatches the given module to one of the agents instead of running it locally. The module is identified
+// by its path relative to the working directory of run-all, which the agents resolve against their own root directory.
+func runOnAgent(ctx context.Context, client *agent.Client, rootOpts *options.TerragruntOptions, moduleOpts *options.TerragruntOptions) error {
+	modulePath, err := filepath.Rel(rootOpts.WorkingDir, filepath.Dir(moduleOpts.TerragruntConfigPath))
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	moduleOpts.Logger.Debugf("Dispatching module %s to an agent", modulePath)
+	return client.RunModule(ctx, filepath.ToSlash(modulePath), moduleOpts.TerraformCliArgs, moduleOpts.Writer, moduleOpts.ErrWriter)
+}
@@ -3,10 +3,14 @@ package runall
 import (
 	"sort"
 
+	agentclient "github.com/gruntwork-io/terragrunt/agent"
 	"github.com/gruntwork-io/terragrunt/cli/commands"
+	"github.com/gruntwork-io/terragrunt/cli/commands/agent"
 	awsproviderpatch "github.com/gruntwork-io/terragrunt/cli/commands/aws-provider-patch"
+	"github.com/gruntwork-io/terragrunt/cli/commands/cost"
 	graphdependencies "github.com/gruntwork-io/terragrunt/cli/commands/graph-dependencies"
 	"github.com/gruntwork-io/terragrunt/cli/commands/hclfmt"
+	"github.com/gruntwork-io/terragrunt/cli/commands/render"
 	renderjson "github.com/gruntwork-io/terragrunt/cli/commands/render-json"
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	terragruntinfo "github.com/gruntwork-io/terragrunt/cli/commands/terragrunt-info"
@@ -17,21 +21,111 @@ import (
 
 const (
 	CommandName = "run-all"
+
+	FlagNameTerragruntPreviewOrder = "terragrunt-preview-order"
+	FlagNameTerragruntAgents       = "terragrunt-agents"
+
+	FlagNameTerragruntPlanSummary     = "terragrunt-plan-summary"
+	FlagNameTerragruntPlanSummaryJSON = "terragrunt-plan-summary-json"
+
+	FlagNameTerragruntIgnoreExternalDependents = "terragrunt-ignore-external-dependents"
+
+	FlagNameTerragruntDependencyPlannedOutputs = "terragrunt-dependency-planned-outputs"
+
+	FlagNameFormat = "format"
 )
 
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	flags := cli.Flags{
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntPreviewOrder,
+			Aliases:     []string{"preview-order"},
+			Destination: &opts.RunAllPreviewOrder,
+			EnvVar:      "TERRAGRUNT_PREVIEW_ORDER",
+			Usage:       "Print the groups in which the modules would be processed and the dependencies that dictate the order, without running any command.",
+		},
+		&cli.SliceFlag[string]{
+			Name:        FlagNameTerragruntAgents,
+			Destination: &opts.Agents,
+			EnvVar:      "TERRAGRUNT_AGENTS",
+			Usage:       "Addresses of terragrunt agents to dispatch the modules to, instead of running them locally. Can be specified multiple times.",
+		},
+		agent.NewTokenFlag(opts),
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntPlanSummary,
+			Destination: &opts.PlanSummary,
+			EnvVar:      "TERRAGRUNT_PLAN_SUMMARY",
+			Usage:       "Print the number of resources to add, change and destroy in every module, and in total, after run-all plan.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTerragruntPlanSummaryJSON,
+			Destination: &opts.PlanSummaryJSONFile,
+			EnvVar:      "TERRAGRUNT_PLAN_SUMMARY_JSON",
+			Usage:       "The path of a file to write the number of resources to add, change and destroy in every module, and in total, to as JSON after run-all plan.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntIgnoreExternalDependents,
+			Destination: &opts.IgnoreExternalDependents,
+			EnvVar:      "TERRAGRUNT_IGNORE_EXTERNAL_DEPENDENTS",
+			Usage:       "Run destroy even if modules outside of the stack depend on modules of the stack.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameTerragruntDependencyPlannedOutputs,
+			Destination: &opts.DependencyPlannedOutputs,
+			EnvVar:      "TERRAGRUNT_DEPENDENCY_PLANNED_OUTPUTS",
+			Usage:       "Read the outputs of the dependencies of run-all plan from the values planned for them in the same run, rather than from their state.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameFormat,
+			Destination: &opts.RunAllOutputFormat,
+			Usage:       "The format of run-all output. With json, the outputs of all the modules are printed as a single JSON document keyed by module path.",
+		},
+	}
+
+	flags = append(flags, agent.NewTLSFlags(opts)...)
+
+	// The flags of the cost command configure the report aggregated across the stack, so they are flags of run-all too.
+	return append(flags, cost.NewFlags(opts)...)
+}
+
 func NewCommand(opts *options.TerragruntOptions) *cli.Command {
 	return &cli.Command{
 		Name:        CommandName,
 		Usage:       "Run a terraform command against a 'stack' by running the specified command in each subfolder.",
 		Description: "The command will recursively find terragrunt modules in the current directory tree and run the terraform command in dependency order (unless the command is destroy, in which case the command is run in reverse dependency order).",
-		Flags:       commands.NewGlobalFlags(opts),
-		Subcommands: subCommands(opts).SkipRunning(),
-		Action:      action(opts),
+		Flags:       append(commands.NewGlobalFlags(opts), NewFlags(opts)...).Sort(),
+		Subcommands: SubCommands(opts).SkipRunning(),
+		Action: func(ctx *cli.Context) error {
+			opts.Logger.Warnf("'%s' is deprecated. Please update your workflows to use 'terragrunt run --all', as '%s' may be removed in the future!", CommandName, CommandName)
+			return Action(opts)(ctx)
+		},
 	}
 }
 
-func action(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
+// Action returns the action running the terraform command, or one of the subcommands of the given context, in each
+// module of the stack. It is shared with `run --all`.
+func Action(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
 	return func(ctx *cli.Context) error {
+		var agentClient *agentclient.Client
+		if len(opts.Agents) > 0 {
+			tlsConfig, err := agentclient.ClientTLSConfig(opts.AgentTLSCert, opts.AgentTLSKey, opts.AgentTLSCA)
+			if err != nil {
+				return err
+			}
+			for _, address := range opts.Agents {
+				if err := agentclient.CheckTokenTransport(address, opts.AgentToken, tlsConfig); err != nil {
+					return err
+				}
+			}
+			client, err := agentclient.Dial(opts.Agents, opts.AgentToken, tlsConfig)
+			if err != nil {
+				return err
+			}
+			defer client.Close()
+			agentClient = client
+		}
+
+		rootOpts := opts
 		opts.RunTerragrunt = func(opts *options.TerragruntOptions) error {
 			if cmd := ctx.Command.Subcommand(opts.TerraformCommand); cmd != nil {
 				ctx := ctx.WithValue(options.ContextKey, opts)
@@ -39,6 +133,14 @@ func action(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
 				return cmd.Action(ctx)
 			}
 
+			if agentClient != nil {
+				return runOnAgent(ctx, agentClient, rootOpts, opts)
+			}
+
+			if found, err := terraform.RunCustomCommand(opts); found || err != nil {
+				return err
+			}
+
 			return terraform.Run(opts)
 		}
 
@@ -46,14 +148,17 @@ func action(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
 	}
 }
 
-func subCommands(opts *options.TerragruntOptions) cli.Commands {
+// SubCommands returns the terragrunt commands that can be run in each module of the stack.
+func SubCommands(opts *options.TerragruntOptions) cli.Commands {
 	cmds := cli.Commands{
 		terragruntinfo.NewCommand(opts),    // terragrunt-info
 		validateinputs.NewCommand(opts),    // validate-inputs
 		graphdependencies.NewCommand(opts), // graph-dependencies
 		hclfmt.NewCommand(opts),            // hclfmt
 		renderjson.NewCommand(opts),        // render-json
+		render.NewCommand(opts),            // render
 		awsproviderpatch.NewCommand(opts),  // aws-provider-patch
+		cost.NewCommand(opts),              // cost
 	}
 
 	sort.Sort(cmds)
@@ -1,6 +1,9 @@
 package runall
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type RunAllDisabledErr struct {
 	command string
@@ -16,3 +19,25 @@ type MissingCommand struct{}
 func (err MissingCommand) Error() string {
 	return "Missing run-all command argument (Example: terragrunt run-all plan)"
 }
+
+type ExternalDependentsFound struct {
+	Paths []string
+}
+
+func (err ExternalDependentsFound) Error() string {
+	return fmt.Sprintf("Modules outside of the stack depend on modules that would be destroyed: %s. Pass --terragrunt-ignore-external-dependents to destroy them anyway.", strings.Join(err.Paths, ", "))
+}
+
+type FormatRequiresOutputCommand struct {
+	command string
+}
+
+func (err FormatRequiresOutputCommand) Error() string {
+	return fmt.Sprintf("The --%s flag is only supported by run-all output, not run-all %s.", FlagNameFormat, err.command)
+}
+
+type UnsupportedOutputFormat string
+
+func (format UnsupportedOutputFormat) Error() string {
+	return fmt.Sprintf("Unsupported run-all output format %q. The only supported format is json.", string(format))
+}
@@ -0,0 +1,81 @@
+package run
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+func action(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		if opts.RunAll && opts.RunGraph {
+			return errors.WithStackTrace(ConflictingFlags{})
+		}
+		if opts.TerraformCommand == "" {
+			return errors.WithStackTrace(MissingCommand{})
+		}
+
+		if opts.RunGraph {
+			if err := NarrowToGraph(opts); err != nil {
+				return err
+			}
+		}
+		if opts.RunAll || opts.RunGraph {
+			return runall.Action(opts)(ctx)
+		}
+
+		if cmd := ctx.Command.Subcommand(opts.TerraformCommand); cmd != nil {
+			return cmd.Action(ctx)
+		}
+		return terraform.NewCommand(opts).Action(ctx)
+	}
+}
+
+// NarrowToGraph changes the given options so that running the stack of their working directory only runs the module of
+// the working directory and the modules that depend on it: the working directory becomes the graph root, and these
+// modules become the only included directories. The dependencies of the module are not run. It is shared with `graph`.
+func NarrowToGraph(opts *options.TerragruntOptions) error {
+	modulePath, err := util.CanonicalPath(opts.WorkingDir, ".")
+	if err != nil {
+		return err
+	}
+
+	graphRoot := opts.GraphRoot
+	if graphRoot == "" {
+		graphRoot, err = shell.GitTopLevelDir(opts, opts.WorkingDir)
+		if err != nil {
+			return errors.WithStackTrace(GraphRootNotFound{WorkingDir: opts.WorkingDir, Err: err})
+		}
+	}
+	graphRoot, err = util.CanonicalPath(graphRoot, opts.WorkingDir)
+	if err != nil {
+		return err
+	}
+
+	stackOpts := opts.Clone(opts.TerragruntConfigPath)
+	stackOpts.WorkingDir = graphRoot
+	stack, err := configstack.FindStackInSubfolders(stackOpts, nil)
+	if err != nil {
+		return err
+	}
+	dependents, err := stack.FindDependents(modulePath)
+	if err != nil {
+		return err
+	}
+
+	includeDirs := []string{modulePath}
+	for _, module := range dependents {
+		includeDirs = append(includeDirs, module.Path)
+	}
+	opts.Logger.Debugf("Running %s in %s and its dependents under %s", opts.TerraformCommand, modulePath, graphRoot)
+
+	opts.WorkingDir = graphRoot
+	opts.IncludeDirs = includeDirs
+	opts.StrictInclude = true
+	return nil
+}
@@ -0,0 +1,61 @@
+package run
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNarrowToGraph(t *testing.T) {
+	t.Parallel()
+
+	root, err := util.CanonicalPath(t.TempDir(), ".")
+	require.NoError(t, err)
+
+	modules := map[string]string{
+		"vpc":   ``,
+		"db":    `dependencies { paths = ["../vpc"] }`,
+		"app":   `dependencies { paths = ["../db"] }`,
+		"other": ``,
+	}
+	for name, contents := range modules {
+		require.NoError(t, os.MkdirAll(filepath.Join(root, name), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name, config.DefaultTerragruntConfigPath), []byte(contents), os.ModePerm))
+		require.NoError(t, os.WriteFile(filepath.Join(root, name, "main.tf"), []byte(``), os.ModePerm))
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(root, "db", config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.GraphRoot = root
+
+	require.NoError(t, NarrowToGraph(opts))
+	assert.Equal(t, root, opts.WorkingDir)
+	assert.Equal(t, []string{filepath.Join(root, "db"), filepath.Join(root, "app")}, opts.IncludeDirs)
+	assert.True(t, opts.StrictInclude)
+}
+
+func TestNarrowToGraphModuleOutsideOfRoot(t *testing.T) {
+	t.Parallel()
+
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, config.DefaultTerragruntConfigPath), []byte(``), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(root, "main.tf"), []byte(``), os.ModePerm))
+
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath), []byte(``), os.ModePerm))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(``), os.ModePerm))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.GraphRoot = root
+
+	err = NarrowToGraph(opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "was not found in the stack")
+}
@@ -0,0 +1,56 @@
+package run
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName = "run"
+
+	FlagNameAll                 = "all"
+	FlagNameGraph               = "graph"
+	FlagNameTerragruntGraphRoot = "terragrunt-graph-root"
+)
+
+func NewFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.BoolFlag{
+			Name:        FlagNameAll,
+			Destination: &opts.RunAll,
+			Usage:       "Run the terraform command in each module of the stack, in dependency order, like run-all.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameGraph,
+			Destination: &opts.RunGraph,
+			Usage:       "Run the terraform command in the module of the working directory and in the modules that depend on it, directly or transitively, in dependency order.",
+		},
+		NewGraphRootFlag(opts),
+	}
+}
+
+// NewGraphRootFlag returns the flag setting the directory in which the dependents of the module of the working directory
+// are looked for, shared by `run --graph` and `graph`.
+func NewGraphRootFlag(opts *options.TerragruntOptions) cli.Flag {
+	return &cli.GenericFlag[string]{
+		Name:        FlagNameTerragruntGraphRoot,
+		Aliases:     []string{"graph-root"},
+		Destination: &opts.GraphRoot,
+		EnvVar:      "TERRAGRUNT_GRAPH_ROOT",
+		Usage:       "The directory in which --graph, and the graph command, look for the modules that depend on the module of the working directory. Defaults to the root of the git repository.",
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Run a terraform command in the module of the working directory, in each module of the stack with --all, or in the module and its dependents with --graph.",
+		UsageText:   "terragrunt run [--all|--graph] -- <terraform command> [terraform args]",
+		Description: "The consolidated entry point for running terraform commands. Without flags it is equivalent to 'terragrunt <command>', with --all it is equivalent to 'terragrunt run-all <command>'.",
+		Flags:       append(append(commands.NewGlobalFlags(opts), runall.NewFlags(opts)...), NewFlags(opts)...).Sort(),
+		Subcommands: runall.SubCommands(opts).SkipRunning(),
+		Action:      action(opts),
+	}
+}
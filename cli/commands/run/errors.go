@@ -0,0 +1,28 @@
+package run
+
+import "fmt"
+
+type ConflictingFlags struct{}
+
+func (err ConflictingFlags) Error() string {
+	return fmt.Sprintf("The --%s and --%s flags can't be used together.", FlagNameAll, FlagNameGraph)
+}
+
+type MissingCommand struct{}
+
+func (err MissingCommand) Error() string {
+	return "Missing run command argument (Example: terragrunt run --all -- plan)"
+}
+
+type GraphRootNotFound struct {
+	WorkingDir string
+	Err        error
+}
+
+func (err GraphRootNotFound) Error() string {
+	return fmt.Sprintf("Could not find the git repository of %s to look for the dependents of its module in: %v. Pass --%s to set the directory to look in.", err.WorkingDir, err.Err, FlagNameTerragruntGraphRoot)
+}
+
+func (err GraphRootNotFound) Unwrap() error {
+	return err.Err
+}
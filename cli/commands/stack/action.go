@@ -0,0 +1,140 @@
+// `stack` command generates the units declared in a terragrunt.stack.hcl file, each in its own folder of the
+// .terragrunt-stack folder, with a terragrunt.hcl file setting its terraform source, its dependencies and its inputs,
+// and runs a terraform command in all of them, in dependency order.
+
+package stack
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// Generate writes the terragrunt.hcl file of every unit of the stack file of the working dir, and removes the generated
+// configs of the units that are no longer in the stack file, so that run-all doesn't run them.
+func Generate(opts *options.TerragruntOptions) error {
+	stackPath := filepath.Join(opts.WorkingDir, config.DefaultStackFile)
+	if !util.FileExists(stackPath) {
+		return errors.WithStackTrace(StackFileNotFound(stackPath))
+	}
+
+	stack, err := config.ReadStackConfigFile(stackPath, opts)
+	if err != nil {
+		return err
+	}
+
+	generated := map[string]bool{}
+	for _, unit := range stack.Units {
+		contents, err := stack.UnitConfig(unit)
+		if err != nil {
+			return err
+		}
+
+		unitDir := stack.UnitDir(unit)
+		if err := os.MkdirAll(unitDir, os.ModePerm); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		configPath := filepath.Join(unitDir, config.DefaultTerragruntConfigPath)
+		if err := os.WriteFile(configPath, contents, 0644); err != nil {
+			return errors.WithStackTrace(err)
+		}
+		generated[configPath] = true
+		opts.Logger.Debugf("Generated unit %s in %s", unit.Name, unitDir)
+	}
+
+	if err := removeStaleUnits(opts, filepath.Join(opts.WorkingDir, config.StackDir), generated); err != nil {
+		return err
+	}
+
+	opts.Logger.Infof("Generated %d units of %s in %s", len(stack.Units), stackPath, filepath.Join(opts.WorkingDir, config.StackDir))
+	return nil
+}
+
+// removeStaleUnits removes the configs under the given dir that were generated from the stack file, but not in this
+// run. The rest of the folders of the stale units is kept, as it may hold their state or their cache.
+func removeStaleUnits(opts *options.TerragruntOptions, dir string, generated map[string]bool) error {
+	if !util.FileExists(dir) {
+		return nil
+	}
+
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == util.TerragruntCacheDir || info.Name() == ".terraform" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if info.Name() != config.DefaultTerragruntConfigPath || generated[path] {
+			return nil
+		}
+
+		isGenerated, err := isGeneratedUnitConfig(path)
+		if err != nil || !isGenerated {
+			return err
+		}
+		opts.Logger.Infof("Removing %s, as its unit is no longer in the stack", path)
+		return os.Remove(path)
+	})
+	return errors.WithStackTrace(err)
+}
+
+// isGeneratedUnitConfig returns true if the given config was generated from a stack file.
+func isGeneratedUnitConfig(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	return scanner.Scan() && scanner.Text() == config.StackUnitHeader, scanner.Err()
+}
+
+func runAction(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
+	return func(ctx *cli.Context) error {
+		// The args of `stack run` are handled as the ones of the run command, as the commands share their name.
+		if opts.TerraformCommand == "" {
+			return errors.WithStackTrace(MissingCommand{})
+		}
+
+		if err := Generate(opts); err != nil {
+			return err
+		}
+
+		opts.WorkingDir = filepath.Join(opts.WorkingDir, config.StackDir)
+		opts.TerragruntConfigPath = config.GetDefaultConfigPath(opts.WorkingDir)
+		return runall.Action(opts)(ctx)
+	}
+}
+
+// Custom error types
+
+type MissingSubcommand struct{}
+
+func (err MissingSubcommand) Error() string {
+	return fmt.Sprintf("Missing %s subcommand, e.g. `terragrunt %s %s` or `terragrunt %s %s plan`.", CommandName, CommandName, CommandNameGenerate, CommandName, CommandNameRun)
+}
+
+type MissingCommand struct{}
+
+func (err MissingCommand) Error() string {
+	return fmt.Sprintf("Missing %s %s command argument (Example: terragrunt %s %s plan)", CommandName, CommandNameRun, CommandName, CommandNameRun)
+}
+
+type StackFileNotFound string
+
+func (err StackFileNotFound) Error() string {
+	return fmt.Sprintf("Could not find the stack file %s.", string(err))
+}
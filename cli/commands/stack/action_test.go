@@ -0,0 +1,74 @@
+package stack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerateWritesAndRemovesUnits(t *testing.T) {
+	t.Parallel()
+
+	stackDir := t.TempDir()
+	stackPath := filepath.Join(stackDir, config.DefaultStackFile)
+	require.NoError(t, os.WriteFile(stackPath, []byte(`
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "vpc"
+}
+
+unit "app" {
+  source       = "../modules/app"
+  path         = "app"
+  dependencies = ["vpc"]
+}
+`), 0644))
+
+	// A config written by hand in the stack folder is never removed.
+	manualConfigPath := filepath.Join(stackDir, config.StackDir, "manual", config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(manualConfigPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(manualConfigPath, []byte(`inputs = {}`), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = stackDir
+
+	require.NoError(t, Generate(opts))
+	appConfigPath := filepath.Join(stackDir, config.StackDir, "app", config.DefaultTerragruntConfigPath)
+	appConfig, err := os.ReadFile(appConfigPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(appConfig), `source = "../../../modules/app"`)
+	assert.Contains(t, string(appConfig), `config_path = "../vpc"`)
+
+	require.NoError(t, os.WriteFile(stackPath, []byte(`
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "vpc"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(appConfigPath), "terraform.tfstate"), []byte(`{}`), 0644))
+
+	require.NoError(t, Generate(opts))
+	assert.NoFileExists(t, appConfigPath)
+	assert.FileExists(t, filepath.Join(filepath.Dir(appConfigPath), "terraform.tfstate"))
+	assert.FileExists(t, filepath.Join(stackDir, config.StackDir, "vpc", config.DefaultTerragruntConfigPath))
+	assert.FileExists(t, manualConfigPath)
+}
+
+func TestGenerateWithoutStackFile(t *testing.T) {
+	t.Parallel()
+
+	stackDir := t.TempDir()
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(stackDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = stackDir
+
+	err = Generate(opts)
+	assert.Equal(t, StackFileNotFound(filepath.Join(stackDir, config.DefaultStackFile)), errors.Unwrap(err))
+}
@@ -0,0 +1,45 @@
+package stack
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands"
+	runall "github.com/gruntwork-io/terragrunt/cli/commands/run-all"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName         = "stack"
+	CommandNameGenerate = "generate"
+	CommandNameRun      = "run"
+)
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Generate the units declared in the terragrunt.stack.hcl file of the working directory with `stack generate`, and run a terraform command in all of them with `stack run`.",
+		Subcommands: cli.Commands{newGenerateCommand(opts), newRunCommand(opts)},
+		Action:      func(ctx *cli.Context) error { return errors.WithStackTrace(MissingSubcommand{}) },
+	}
+}
+
+func newGenerateCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameGenerate,
+		Usage:       "Write the terragrunt.hcl file of every unit of the terragrunt.stack.hcl file of the working directory in the .terragrunt-stack folder.",
+		Description: "The generated configs of the units that were removed from the stack file are deleted, but not the rest of their folders, such as their .terragrunt-cache folders.",
+		Action:      func(ctx *cli.Context) error { return Generate(opts.OptionsFromContext(ctx)) },
+	}
+}
+
+func newRunCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameRun,
+		Usage:       "Generate the units of the terragrunt.stack.hcl file of the working directory, then run a terraform command in all of them, in dependency order, as run-all does.",
+		UsageText:   "terragrunt stack run <terraform command> [terraform args]",
+		Flags:       append(commands.NewGlobalFlags(opts), runall.NewFlags(opts)...).Sort(),
+		Subcommands: runall.SubCommands(opts).SkipRunning(),
+		Action:      runAction(opts),
+	}
+}
@@ -0,0 +1,224 @@
+package state
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// RunMigrate migrates the state of each module of the stack in the working dir from the backend of the remote_state
+// block in opts.StateMigrateFrom to the one in opts.StateMigrateTo.
+func RunMigrate(opts *options.TerragruntOptions) error {
+	if opts.StateMigrateFrom == "" {
+		return errors.WithStackTrace(MissingFlag(FlagNameFrom))
+	}
+	if opts.StateMigrateTo == "" {
+		return errors.WithStackTrace(MissingFlag(FlagNameTo))
+	}
+
+	// The files are evaluated from the working dirs of the modules, so they are resolved from the current working dir
+	// first.
+	from := util.JoinPath(opts.WorkingDir, opts.StateMigrateFrom)
+	if filepath.IsAbs(opts.StateMigrateFrom) {
+		from = opts.StateMigrateFrom
+	}
+	to := util.JoinPath(opts.WorkingDir, opts.StateMigrateTo)
+	if filepath.IsAbs(opts.StateMigrateTo) {
+		to = opts.StateMigrateTo
+	}
+
+	opts.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+		target := terraform.NewTarget(terraform.TargetPointGenerateConfig, func(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
+			return migrateModuleState(opts, from, to)
+		})
+		return terraform.RunWithTarget(opts, target)
+	}
+
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	if err := stack.LogModuleDeployOrder(opts.Logger, opts.TerraformCommand); err != nil {
+		return err
+	}
+
+	if !opts.StateDryRun {
+		prompt := fmt.Sprintf("Are you sure you want to migrate the state of each module of the stack described above from the backend in %s to the backend in %s?", opts.StateMigrateFrom, opts.StateMigrateTo)
+		shouldMigrate, err := shell.PromptUserForYesNo(prompt, opts)
+		if err != nil {
+			return err
+		}
+		if !shouldMigrate {
+			return nil
+		}
+	}
+
+	return stack.Run(opts)
+}
+
+// migrateModuleState migrates the state of the module whose source is in the working dir of the given options from
+// the backend in the from file to the one in the to file, and verifies that the state is the same after the migration.
+func migrateModuleState(opts *options.TerragruntOptions, from, to string) error {
+	fromState, err := readRemoteState(opts, from)
+	if err != nil {
+		return err
+	}
+
+	toState, err := readRemoteState(opts, to)
+	if err != nil {
+		return err
+	}
+
+	if err := initBackend(opts, fromState, "-reconfigure"); err != nil {
+		return err
+	}
+
+	before, err := pullState(opts)
+	if err != nil {
+		return err
+	}
+
+	if before == nil {
+		opts.Logger.Infof("Module %s has no state in the %s backend, there is nothing to migrate", opts.TerragruntConfigPath, fromState.Backend)
+		return nil
+	}
+
+	if opts.StateDryRun {
+		opts.Logger.Infof("Would migrate the state of module %s (serial %d, %d resources) from the %s backend %s to the %s backend %s", opts.TerragruntConfigPath, before.Serial, len(before.Resources), fromState.Backend, describeBackend(fromState), toState.Backend, describeBackend(toState))
+		return nil
+	}
+
+	if err := toState.Initialize(opts); err != nil {
+		return err
+	}
+
+	if err := initBackend(opts, toState, "-migrate-state", "-force-copy"); err != nil {
+		return err
+	}
+
+	after, err := pullState(opts)
+	if err != nil {
+		return err
+	}
+
+	if err := verifyStateIntegrity(before, after); err != nil {
+		return errors.WithStackTrace(StateIntegrityError{ConfigPath: opts.TerragruntConfigPath, Reason: err.Error()})
+	}
+
+	opts.Logger.Infof("Migrated the state of module %s (serial %d, %d resources) from the %s backend to the %s backend", opts.TerragruntConfigPath, after.Serial, len(after.Resources), fromState.Backend, toState.Backend)
+	return nil
+}
+
+// readRemoteState evaluates the remote_state block of the given file in the context of the module of the given options,
+// as if the file was included by the module, so that functions such as path_relative_to_include() resolve to the path
+// of the module.
+func readRemoteState(opts *options.TerragruntOptions, path string) (*remote.RemoteState, error) {
+	include := &config.IncludeConfig{Path: path}
+	cfg, err := config.PartialParseConfigFile(path, opts, include, []config.PartialDecodeSectionType{config.RemoteStateBlock})
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.RemoteState == nil {
+		return nil, errors.WithStackTrace(MissingRemoteState(path))
+	}
+
+	return cfg.RemoteState, nil
+}
+
+// initBackend runs terraform init with the given extra args to initialize the given backend. The backend code of
+// backends with a generate attribute is regenerated, replacing the one generated for the module.
+func initBackend(opts *options.TerragruntOptions, remoteState *remote.RemoteState, extraArgs ...string) error {
+	if remoteState.Generate != nil {
+		generate := *remoteState.Generate
+		generate.IfExists = codegen.ExistsOverwriteTerragruntStr
+
+		remoteStateCopy := *remoteState
+		remoteStateCopy.Generate = &generate
+		if err := remoteStateCopy.GenerateTerraformCode(opts); err != nil {
+			return err
+		}
+	}
+
+	args := append([]string{terraform.CommandNameInit, "-input=false"}, extraArgs...)
+	args = append(args, remoteState.ToTerraformInitArgs()...)
+
+	return shell.RunTerraformCommand(opts, args...)
+}
+
+// terraformState is the part of a terraform state that is compared before and after a migration.
+type terraformState struct {
+	Serial    int64         `json:"serial"`
+	Lineage   string        `json:"lineage"`
+	Resources []interface{} `json:"resources"`
+	Outputs   interface{}   `json:"outputs"`
+}
+
+// pullState returns the state of the backend the working dir is initialized with, or nil if there is no state.
+func pullState(opts *options.TerragruntOptions) (*terraformState, error) {
+	out, err := shell.RunShellCommandWithOutput(opts, "", true, false, opts.TerraformPath, "state", "pull")
+	if err != nil {
+		return nil, err
+	}
+
+	return parseState(out.Stdout)
+}
+
+func parseState(contents string) (*terraformState, error) {
+	if strings.TrimSpace(contents) == "" {
+		return nil, nil
+	}
+
+	var state terraformState
+	if err := json.Unmarshal([]byte(contents), &state); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	return &state, nil
+}
+
+// verifyStateIntegrity returns an error if the state after the migration is not the same as before.
+func verifyStateIntegrity(before, after *terraformState) error {
+	switch {
+	case after == nil:
+		return fmt.Errorf("the state is empty after the migration")
+	case after.Lineage != before.Lineage:
+		return fmt.Errorf("the lineage of the state changed from %s to %s", before.Lineage, after.Lineage)
+	case after.Serial < before.Serial:
+		return fmt.Errorf("the serial of the state went back from %d to %d", before.Serial, after.Serial)
+	case !reflect.DeepEqual(after.Resources, before.Resources):
+		return fmt.Errorf("the resources of the state differ, %d before and %d after the migration", len(before.Resources), len(after.Resources))
+	case !reflect.DeepEqual(after.Outputs, before.Outputs):
+		return fmt.Errorf("the outputs of the state differ")
+	}
+
+	return nil
+}
+
+// describeBackend returns the location of the state in the given backend, as far as it is known, for the dry run.
+func describeBackend(remoteState *remote.RemoteState) string {
+	keys := []string{"bucket", "prefix", "storage_account_name", "container_name", "key", "address", "path"}
+
+	parts := []string{}
+	for _, key := range keys {
+		if value, ok := remoteState.Config[key]; ok {
+			parts = append(parts, fmt.Sprintf("%s=%v", key, value))
+		}
+	}
+
+	return fmt.Sprintf("(%s)", strings.Join(parts, ", "))
+}
@@ -0,0 +1,119 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestParseState(t *testing.T) {
+	t.Parallel()
+
+	state, err := parseState("")
+	require.NoError(t, err)
+	assert.Nil(t, state)
+
+	state, err = parseState(`{"version": 4, "serial": 7, "lineage": "abc", "resources": [{"type": "null_resource", "name": "a"}], "outputs": {}}`)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), state.Serial)
+	assert.Equal(t, "abc", state.Lineage)
+	assert.Len(t, state.Resources, 1)
+
+	_, err = parseState("not json")
+	assert.Error(t, err)
+}
+
+func TestVerifyStateIntegrity(t *testing.T) {
+	t.Parallel()
+
+	before := &terraformState{Serial: 3, Lineage: "abc", Resources: []interface{}{map[string]interface{}{"name": "a"}}}
+
+	testCases := []struct {
+		name        string
+		after       *terraformState
+		expectError bool
+	}{
+		{"same", &terraformState{Serial: 3, Lineage: "abc", Resources: []interface{}{map[string]interface{}{"name": "a"}}}, false},
+		{"serial-increased", &terraformState{Serial: 4, Lineage: "abc", Resources: []interface{}{map[string]interface{}{"name": "a"}}}, false},
+		{"empty", nil, true},
+		{"lineage-changed", &terraformState{Serial: 3, Lineage: "def", Resources: []interface{}{map[string]interface{}{"name": "a"}}}, true},
+		{"serial-decreased", &terraformState{Serial: 2, Lineage: "abc", Resources: []interface{}{map[string]interface{}{"name": "a"}}}, true},
+		{"resources-changed", &terraformState{Serial: 3, Lineage: "abc", Resources: []interface{}{}}, true},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := verifyStateIntegrity(before, testCase.after)
+			if testCase.expectError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestReadRemoteState(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	moduleDir := filepath.Join(rootDir, "app")
+	require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+
+	backendPath := filepath.Join(rootDir, "new-backend.hcl")
+	backendContents := `
+remote_state {
+  backend = "gcs"
+  config = {
+    bucket = "my-state"
+    prefix = "${path_relative_to_include()}/terraform.tfstate"
+  }
+}
+`
+	require.NoError(t, os.WriteFile(backendPath, []byte(backendContents), 0644))
+
+	emptyPath := filepath.Join(rootDir, "empty.hcl")
+	require.NoError(t, os.WriteFile(emptyPath, []byte(`inputs = {}`), 0644))
+
+	configPath := filepath.Join(moduleDir, "terragrunt.hcl")
+	require.NoError(t, os.WriteFile(configPath, []byte(``), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+
+	// The block is evaluated as if the module included it.
+	remoteState, err := readRemoteState(opts, backendPath)
+	require.NoError(t, err)
+	assert.Equal(t, "gcs", remoteState.Backend)
+	assert.Equal(t, "app/terraform.tfstate", remoteState.Config["prefix"])
+
+	_, err = readRemoteState(opts, emptyPath)
+	assert.Equal(t, MissingRemoteState(emptyPath), errors.Unwrap(err))
+}
+
+func TestRunMigrateRequiresFlags(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	opts.StateMigrateTo = "new-backend.hcl"
+	err = RunMigrate(opts)
+	assert.Equal(t, MissingFlag(FlagNameFrom), errors.Unwrap(err))
+
+	opts.StateMigrateFrom = "old-backend.hcl"
+	opts.StateMigrateTo = ""
+	err = RunMigrate(opts)
+	assert.Equal(t, MissingFlag(FlagNameTo), errors.Unwrap(err))
+}
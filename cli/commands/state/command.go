@@ -0,0 +1,98 @@
+package state
+
+import (
+	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/pkg/cli"
+)
+
+const (
+	CommandName        = "state"
+	CommandNameMigrate = "migrate"
+
+	CommandNameRemoveLockTable = "remove-lock-table"
+	CommandNameInventory       = "inventory"
+
+	FlagNameFrom   = "from"
+	FlagNameTo     = "to"
+	FlagNameDryRun = "dry-run"
+	FlagNameFormat = "format"
+	FlagNameTable  = "table"
+)
+
+func NewMigrateFlags(opts *options.TerragruntOptions) cli.Flags {
+	return cli.Flags{
+		&cli.GenericFlag[string]{
+			Name:        FlagNameFrom,
+			Destination: &opts.StateMigrateFrom,
+			Usage:       "The path of a file with the remote_state block of the backend to migrate the state from.",
+		},
+		&cli.GenericFlag[string]{
+			Name:        FlagNameTo,
+			Destination: &opts.StateMigrateTo,
+			Usage:       "The path of a file with the remote_state block of the backend to migrate the state to.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagNameDryRun,
+			Destination: &opts.StateDryRun,
+			Usage:       "Print the migrations that would be done, without migrating the state.",
+		},
+	}
+}
+
+func NewCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandName,
+		Usage:       "Migrate the state of the modules to another backend with `state migrate`, delete the DynamoDB lock tables that are not needed anymore with `state remove-lock-table`, or list the backends of the modules with `state inventory`. Terragrunt forwards all other state commands directly to Terraform.",
+		Subcommands: cli.Commands{newMigrateCommand(opts), newRemoveLockTableCommand(opts), newInventoryCommand(opts)},
+		// The other `state` subcommands, such as `state list`, are terraform's.
+		Action: func(ctx *cli.Context) error { return terraform.Run(opts.OptionsFromContext(ctx)) },
+	}
+}
+
+func newMigrateCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameMigrate,
+		Usage:       "Migrate the state of each module in the current directory tree from one backend to another, e.g. `terragrunt state migrate --from old-backend.hcl --to new-backend.hcl`.",
+		Description: "The --from and --to files contain a remote_state block, which is evaluated in the context of each module, as if it was included by the module. The state is migrated with `terraform init -migrate-state` and verified after the migration.",
+		Flags:       NewMigrateFlags(opts).Sort(),
+		Action:      func(ctx *cli.Context) error { return RunMigrate(opts.OptionsFromContext(ctx)) },
+	}
+}
+
+func newRemoveLockTableCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameRemoveLockTable,
+		Usage:       "Report the DynamoDB lock tables of the S3 backends of the modules in the current directory tree, and delete the tables selected with --table once all of the modules are locked with a lockfile in the S3 bucket.",
+		Description: "Set use_lockfile = true along with dynamodb_table in the remote_state config of the modules and apply them, so that the state is locked with both, then run this command with --table to delete the tables. Only the modules in the current directory tree are checked, so make sure no other stack uses a table before deleting it.",
+		Flags: cli.Flags{
+			&cli.BoolFlag{
+				Name:        FlagNameDryRun,
+				Destination: &opts.StateDryRun,
+				Usage:       "Only report the lock tables, even if tables are selected with --table.",
+			},
+			&cli.SliceFlag[string]{
+				Name:        FlagNameTable,
+				Destination: &opts.StateRemoveLockTables,
+				Usage:       "The name of a DynamoDB lock table to delete, after a confirmation. Can be passed several times.",
+			},
+		},
+		Action: func(ctx *cli.Context) error { return RunRemoveLockTable(opts.OptionsFromContext(ctx)) },
+	}
+}
+
+func newInventoryCommand(opts *options.TerragruntOptions) *cli.Command {
+	return &cli.Command{
+		Name:        CommandNameInventory,
+		Usage:       "Print the backend, bucket, key, region and lock table of the state of each module in the current directory tree, for audits and backend migrations.",
+		Description: "The remote_state block of each module is rendered as it would be when running the module. The modules without a remote_state block are listed with an empty backend.",
+		Flags: cli.Flags{
+			&cli.GenericFlag[string]{
+				Name:        FlagNameFormat,
+				Destination: &opts.StateInventoryFormat,
+				Usage:       "The format of the inventory, json (default) or csv.",
+			},
+		},
+		Action: func(ctx *cli.Context) error { return RunInventory(opts.OptionsFromContext(ctx)) },
+	}
+}
@@ -0,0 +1,54 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+)
+
+type MissingFlag string
+
+func (flagName MissingFlag) Error() string {
+	return fmt.Sprintf("The --%s flag is required for `state migrate`.", string(flagName))
+}
+
+type MissingRemoteState string
+
+func (path MissingRemoteState) Error() string {
+	return fmt.Sprintf("The file %s does not have a remote_state block.", string(path))
+}
+
+type StateIntegrityError struct {
+	ConfigPath string
+	Reason     string
+}
+
+func (err StateIntegrityError) Error() string {
+	return fmt.Sprintf("The state of module %s could not be verified after the migration: %s. The state in the previous backend was kept, so you can restore it.", err.ConfigPath, err.Reason)
+}
+
+type ModulesWithoutLockfile struct {
+	TableName string
+	Modules   []string
+}
+
+func (err ModulesWithoutLockfile) Error() string {
+	return fmt.Sprintf("The DynamoDB lock table %s can not be deleted, as the following modules are not locked with a lockfile yet. Set use_lockfile = true in their remote_state config and apply them first:\n%s", err.TableName, strings.Join(err.Modules, "\n"))
+}
+
+type UnsupportedInventoryFormat string
+
+func (format UnsupportedInventoryFormat) Error() string {
+	return fmt.Sprintf("Unsupported inventory format %s. Supported formats are: %s", string(format), strings.Join(InventoryFormats, ", "))
+}
+
+type LockTableNotUsed string
+
+func (tableName LockTableNotUsed) Error() string {
+	return fmt.Sprintf("None of the modules in the current directory tree lock their state with the DynamoDB table %s.", string(tableName))
+}
+
+type LockTableDeletionRequiresConfirmation struct{}
+
+func (err LockTableDeletionRequiresConfirmation) Error() string {
+	return "The DynamoDB lock tables may still be used by stacks outside of the current directory tree, so they are only deleted after a confirmation, which can't be prompted for with --terragrunt-non-interactive."
+}
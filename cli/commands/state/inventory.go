@@ -0,0 +1,177 @@
+package state
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+const (
+	InventoryFormatJSON = "json"
+	InventoryFormatCSV  = "csv"
+)
+
+var InventoryFormats = []string{InventoryFormatJSON, InventoryFormatCSV}
+
+// The settings of the config of each backend that are listed as the bucket, key and region of the state in the
+// inventory.
+var inventoryConfigs = map[string]struct{ bucket, key, region string }{
+	"s3":      {bucket: "bucket", key: "key", region: "region"},
+	"gcs":     {bucket: "bucket", key: "prefix", region: "location"},
+	"azurerm": {bucket: "container_name", key: "key"},
+	"consul":  {bucket: "address", key: "path"},
+	"etcdv3":  {key: "prefix"},
+	"http":    {key: "address"},
+	"local":   {key: "path"},
+}
+
+// InventoryEntry is where the state of a module is stored.
+type InventoryEntry struct {
+	Module    string `json:"module"`
+	Backend   string `json:"backend"`
+	Bucket    string `json:"bucket,omitempty"`
+	Key       string `json:"key,omitempty"`
+	Region    string `json:"region,omitempty"`
+	LockTable string `json:"lock_table,omitempty"`
+	// Where the state is stored, as a single string such as s3://<bucket>/<key>, when it can be determined.
+	Location string `json:"location,omitempty"`
+}
+
+// RunInventory prints where the state of each module in the working dir is stored, as json or csv.
+func RunInventory(opts *options.TerragruntOptions) error {
+	format := opts.StateInventoryFormat
+	if format == "" {
+		format = InventoryFormatJSON
+	}
+	if format != InventoryFormatJSON && format != InventoryFormatCSV {
+		return errors.WithStackTrace(UnsupportedInventoryFormat(format))
+	}
+
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	inventory, err := buildInventory(opts, stack.Modules)
+	if err != nil {
+		return err
+	}
+
+	if format == InventoryFormatCSV {
+		return writeInventoryCSV(opts, inventory)
+	}
+
+	inventoryJSON, err := json.MarshalIndent(inventory, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := fmt.Fprintf(opts.Writer, "%s\n", inventoryJSON); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// buildInventory returns where the state of each of the given modules is stored, sorted by module. The excluded
+// modules are skipped.
+func buildInventory(opts *options.TerragruntOptions, modules []*configstack.TerraformModule) ([]InventoryEntry, error) {
+	inventory := []InventoryEntry{}
+
+	for _, module := range modules {
+		if module.FlagExcluded {
+			continue
+		}
+
+		configPath := module.TerragruntOptions.TerragruntConfigPath
+
+		cfg, err := config.PartialParseConfigFile(configPath, module.TerragruntOptions, nil, []config.PartialDecodeSectionType{config.RemoteStateBlock})
+		if err != nil {
+			return nil, err
+		}
+
+		entry := InventoryEntry{Module: inventoryModulePath(opts, module.Path)}
+		if cfg.RemoteState != nil {
+			if err := fillInventoryEntry(&entry, cfg.RemoteState); err != nil {
+				return nil, err
+			}
+		}
+
+		inventory = append(inventory, entry)
+	}
+
+	sort.Slice(inventory, func(i, j int) bool { return inventory[i].Module < inventory[j].Module })
+
+	return inventory, nil
+}
+
+// fillInventoryEntry sets the backend, bucket, key, region and lock table of the given entry from the given remote
+// state.
+func fillInventoryEntry(entry *InventoryEntry, remoteState *remote.RemoteState) error {
+	entry.Backend = remoteState.Backend
+	entry.Location = remoteState.StateLocation()
+
+	configValue := func(name string) string {
+		if value, ok := remoteState.Config[name]; ok && name != "" && value != nil {
+			return fmt.Sprintf("%v", value)
+		}
+		return ""
+	}
+
+	configs := inventoryConfigs[remoteState.Backend]
+	entry.Bucket = configValue(configs.bucket)
+	entry.Key = configValue(configs.key)
+	entry.Region = configValue(configs.region)
+
+	switch remoteState.Backend {
+	case "s3":
+		lockTable, _, err := remote.GetS3Locking(remoteState.Config)
+		if err != nil {
+			return err
+		}
+		if lockTable != nil {
+			entry.LockTable = lockTable.Name
+		}
+	case "azurerm":
+		if account := configValue("storage_account_name"); account != "" {
+			entry.Bucket = account + "/" + entry.Bucket
+		}
+	}
+
+	return nil
+}
+
+// writeInventoryCSV writes the given inventory as csv, with a header row.
+func writeInventoryCSV(opts *options.TerragruntOptions, inventory []InventoryEntry) error {
+	writer := csv.NewWriter(opts.Writer)
+
+	rows := [][]string{{"module", "backend", "bucket", "key", "region", "lock_table", "location"}}
+	for _, entry := range inventory {
+		rows = append(rows, []string{entry.Module, entry.Backend, entry.Bucket, entry.Key, entry.Region, entry.LockTable, entry.Location})
+	}
+
+	if err := writer.WriteAll(rows); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// inventoryModulePath returns the given path relative to the working dir, so that the inventory doesn't depend on
+// where the working dir is checked out. The paths outside of the working dir are kept as is.
+func inventoryModulePath(opts *options.TerragruntOptions, path string) string {
+	rel, err := filepath.Rel(opts.WorkingDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
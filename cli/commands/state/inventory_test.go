@@ -0,0 +1,90 @@
+package state
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestBuildInventory(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+
+	configs := map[string]string{
+		"vpc": `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket         = "my-state"
+    key            = "vpc/terraform.tfstate"
+    region         = "us-east-1"
+    dynamodb_table = "locks"
+  }
+}
+`,
+		"mysql": `
+remote_state {
+  backend = "gcs"
+  config = {
+    bucket = "my-state"
+    prefix = "mysql"
+  }
+}
+`,
+		"app": ``,
+	}
+
+	modules := []*configstack.TerraformModule{}
+	for name, contents := range configs {
+		moduleDir := filepath.Join(rootDir, name)
+		require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+
+		configPath := filepath.Join(moduleDir, "terragrunt.hcl")
+		require.NoError(t, os.WriteFile(configPath, []byte(contents), 0644))
+
+		opts, err := options.NewTerragruntOptionsForTest(configPath)
+		require.NoError(t, err)
+		modules = append(modules, &configstack.TerraformModule{Path: moduleDir, TerragruntOptions: opts})
+	}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = rootDir
+
+	inventory, err := buildInventory(opts, modules)
+	require.NoError(t, err)
+
+	assert.Equal(t, []InventoryEntry{
+		{Module: "app"},
+		{Module: "mysql", Backend: "gcs", Bucket: "my-state", Key: "mysql", Location: "gcs://my-state/mysql"},
+		{Module: "vpc", Backend: "s3", Bucket: "my-state", Key: "vpc/terraform.tfstate", Region: "us-east-1", LockTable: "locks", Location: "s3://my-state/vpc/terraform.tfstate"},
+	}, inventory)
+
+	var out bytes.Buffer
+	opts.Writer = &out
+	require.NoError(t, writeInventoryCSV(opts, inventory))
+	assert.Equal(t, `module,backend,bucket,key,region,lock_table,location
+app,,,,,,
+mysql,gcs,my-state,mysql,,,gcs://my-state/mysql
+vpc,s3,my-state,vpc/terraform.tfstate,us-east-1,locks,s3://my-state/vpc/terraform.tfstate
+`, out.String())
+}
+
+func TestRunInventoryUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	opts.StateInventoryFormat = "yaml"
+
+	err = RunInventory(opts)
+	assert.ErrorContains(t, err, "Unsupported inventory format yaml")
+}
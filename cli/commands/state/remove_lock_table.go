@@ -0,0 +1,166 @@
+package state
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// lockTableUsage is a DynamoDB lock table along with the modules whose state is locked with it.
+type lockTableUsage struct {
+	lockTable *remote.S3LockTable
+	// The modules that are also locked with a lockfile in the S3 bucket, which don't need the table anymore.
+	modulesWithLockfile []string
+	// The modules that are only locked with the table.
+	modulesWithoutLockfile []string
+}
+
+// RunRemoveLockTable reports the DynamoDB lock tables of the S3 backends of the modules in the working dir, and whether
+// all the modules that use a table are locked with a lockfile in the S3 bucket instead. As the tables may be shared with
+// stacks outside of the working dir, a table is only deleted when it is selected explicitly and the user confirms it.
+func RunRemoveLockTable(opts *options.TerragruntOptions) error {
+	stack, err := configstack.FindStackInSubfolders(opts, nil)
+	if err != nil {
+		return err
+	}
+
+	return removeLockTables(opts, stack.Modules)
+}
+
+func removeLockTables(opts *options.TerragruntOptions, modules []*configstack.TerraformModule) error {
+	usages, err := findLockTableUsages(modules)
+	if err != nil {
+		return err
+	}
+
+	if len(usages) == 0 {
+		opts.Logger.Infof("None of the modules in %s lock their state with a DynamoDB table", opts.WorkingDir)
+		return nil
+	}
+
+	for _, usage := range usages {
+		if len(usage.modulesWithoutLockfile) > 0 {
+			opts.Logger.Infof("DynamoDB lock table %s is used by %d modules, %d of which are not locked with a lockfile yet: %s", usage.lockTable, len(usage.modulesWithLockfile)+len(usage.modulesWithoutLockfile), len(usage.modulesWithoutLockfile), strings.Join(usage.modulesWithoutLockfile, ", "))
+		} else {
+			opts.Logger.Infof("DynamoDB lock table %s is used by %d modules, which are all also locked with a lockfile", usage.lockTable, len(usage.modulesWithLockfile))
+		}
+	}
+
+	if len(opts.StateRemoveLockTables) == 0 || opts.StateDryRun {
+		opts.Logger.Infof("Only the modules in %s were checked, the tables may still be used by stacks outside of it. Pass --%s with the name of a table to delete it.", opts.WorkingDir, FlagNameTable)
+		return nil
+	}
+
+	selectedUsages, err := selectLockTables(usages, opts.StateRemoveLockTables)
+	if err != nil {
+		return err
+	}
+
+	// Deleting a table that other stacks still use breaks their locking, so it is never done without asking the user.
+	if opts.NonInteractive {
+		return errors.WithStackTrace(LockTableDeletionRequiresConfirmation{})
+	}
+
+	for _, usage := range selectedUsages {
+		prompt := fmt.Sprintf("The modules in %s that use the DynamoDB lock table %s are all locked with a lockfile in their S3 bucket, but the table may still be used by stacks outside of %s. Are you sure you want to delete it?", opts.WorkingDir, usage.lockTable, opts.WorkingDir)
+		shouldDelete, err := shell.PromptUserForYesNo(prompt, opts)
+		if err != nil {
+			return err
+		}
+		if !shouldDelete {
+			opts.Logger.Infof("Not deleting DynamoDB lock table %s", usage.lockTable)
+			continue
+		}
+
+		if err := remote.DeleteS3LockTable(usage.lockTable, opts); err != nil {
+			return err
+		}
+		opts.Logger.Infof("Deleted DynamoDB lock table %s. Remove the dynamodb_table setting from the remote_state blocks of the modules now, or Terragrunt will create the table again.", usage.lockTable)
+	}
+
+	return nil
+}
+
+// selectLockTables returns the usages of the lock tables with the given names, which must be used by the modules, all
+// of them also being locked with a lockfile.
+func selectLockTables(usages []*lockTableUsage, tableNames []string) ([]*lockTableUsage, error) {
+	selectedUsages := []*lockTableUsage{}
+	for _, tableName := range tableNames {
+		found := false
+		for _, usage := range usages {
+			if usage.lockTable.Name != tableName {
+				continue
+			}
+			found = true
+
+			// A table is only deleted once none of the modules depend on it anymore.
+			if len(usage.modulesWithoutLockfile) > 0 {
+				return nil, errors.WithStackTrace(ModulesWithoutLockfile{TableName: usage.lockTable.Name, Modules: usage.modulesWithoutLockfile})
+			}
+			selectedUsages = append(selectedUsages, usage)
+		}
+		if !found {
+			return nil, errors.WithStackTrace(LockTableNotUsed(tableName))
+		}
+	}
+	return selectedUsages, nil
+}
+
+// findLockTableUsages returns the DynamoDB lock tables of the S3 backends of the given modules, sorted by table.
+func findLockTableUsages(modules []*configstack.TerraformModule) ([]*lockTableUsage, error) {
+	usages := map[string]*lockTableUsage{}
+
+	for _, module := range modules {
+		configPath := module.TerragruntOptions.TerragruntConfigPath
+
+		cfg, err := config.PartialParseConfigFile(configPath, module.TerragruntOptions, nil, []config.PartialDecodeSectionType{config.RemoteStateBlock})
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.RemoteState == nil || cfg.RemoteState.Backend != "s3" {
+			continue
+		}
+
+		lockTable, useLockfile, err := remote.GetS3Locking(cfg.RemoteState.Config)
+		if err != nil {
+			return nil, err
+		}
+		if lockTable == nil {
+			continue
+		}
+
+		usage, ok := usages[lockTable.String()]
+		if !ok {
+			usage = &lockTableUsage{lockTable: lockTable}
+			usages[lockTable.String()] = usage
+		}
+
+		if useLockfile {
+			usage.modulesWithLockfile = append(usage.modulesWithLockfile, module.Path)
+		} else {
+			usage.modulesWithoutLockfile = append(usage.modulesWithoutLockfile, module.Path)
+		}
+	}
+
+	keys := []string{}
+	for key := range usages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sortedUsages := []*lockTableUsage{}
+	for _, key := range keys {
+		sortedUsages = append(sortedUsages, usages[key])
+	}
+
+	return sortedUsages, nil
+}
@@ -0,0 +1,122 @@
+package state
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+func TestFindLockTableUsages(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+
+	configs := map[string]string{
+		"switched":     `dynamodb_table = "locks", use_lockfile = true`,
+		"not-switched": `dynamodb_table = "locks"`,
+		"lockfile":     `use_lockfile = true`,
+		"other-table":  `dynamodb_table = "other-locks", use_lockfile = true`,
+	}
+
+	modules := []*configstack.TerraformModule{}
+	for name, locking := range configs {
+		moduleDir := filepath.Join(rootDir, name)
+		require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+
+		contents := fmt.Sprintf(`
+remote_state {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "%s/terraform.tfstate"
+    region = "us-east-1"
+    %s
+  }
+}
+`, name, locking)
+		configPath := filepath.Join(moduleDir, "terragrunt.hcl")
+		require.NoError(t, os.WriteFile(configPath, []byte(contents), 0644))
+
+		opts, err := options.NewTerragruntOptionsForTest(configPath)
+		require.NoError(t, err)
+		modules = append(modules, &configstack.TerraformModule{Path: moduleDir, TerragruntOptions: opts})
+	}
+
+	usages, err := findLockTableUsages(modules)
+	require.NoError(t, err)
+	require.Len(t, usages, 2)
+
+	assert.Equal(t, "locks", usages[0].lockTable.Name)
+	assert.Equal(t, "us-east-1", usages[0].lockTable.Region)
+	assert.Equal(t, []string{filepath.Join(rootDir, "switched")}, usages[0].modulesWithLockfile)
+	assert.Equal(t, []string{filepath.Join(rootDir, "not-switched")}, usages[0].modulesWithoutLockfile)
+
+	assert.Equal(t, "other-locks", usages[1].lockTable.Name)
+	assert.Equal(t, []string{filepath.Join(rootDir, "other-table")}, usages[1].modulesWithLockfile)
+	assert.Empty(t, usages[1].modulesWithoutLockfile)
+}
+
+func TestSelectLockTables(t *testing.T) {
+	t.Parallel()
+
+	switched := &lockTableUsage{lockTable: &remote.S3LockTable{Name: "locks", Region: "us-east-1"}, modulesWithLockfile: []string{"a"}}
+	notSwitched := &lockTableUsage{lockTable: &remote.S3LockTable{Name: "other-locks", Region: "us-east-1"}, modulesWithLockfile: []string{"b"}, modulesWithoutLockfile: []string{"c"}}
+	usages := []*lockTableUsage{switched, notSwitched}
+
+	selected, err := selectLockTables(usages, []string{"locks"})
+	require.NoError(t, err)
+	assert.Equal(t, []*lockTableUsage{switched}, selected)
+
+	_, err = selectLockTables(usages, []string{"other-locks"})
+	assert.Error(t, err)
+
+	_, err = selectLockTables(usages, []string{"unknown-locks"})
+	assert.Error(t, err)
+}
+
+func TestRemoveLockTableNonInteractive(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	contents := `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket         = "my-state"
+    key            = "terraform.tfstate"
+    region         = "us-east-1"
+    dynamodb_table = "locks"
+    use_lockfile   = true
+  }
+}
+`
+	moduleDir := filepath.Join(rootDir, "app")
+	configPath := filepath.Join(moduleDir, "terragrunt.hcl")
+	require.NoError(t, os.MkdirAll(moduleDir, os.ModePerm))
+	require.NoError(t, os.WriteFile(configPath, []byte(contents), 0644))
+
+	moduleOpts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	modules := []*configstack.TerraformModule{{Path: moduleDir, TerragruntOptions: moduleOpts}}
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, "terragrunt.hcl"))
+	require.NoError(t, err)
+	opts.WorkingDir = rootDir
+	opts.NonInteractive = true
+
+	// Without a selected table, the tables are only reported.
+	require.NoError(t, removeLockTables(opts, modules))
+
+	opts.StateRemoveLockTables = []string{"locks"}
+	err = removeLockTables(opts, modules)
+	assert.Equal(t, LockTableDeletionRequiresConfirmation{}, errors.Unwrap(err))
+}
@@ -38,6 +38,7 @@ const (
 	CommandNameOutput               = "output"
 	CommandNameProviders            = "providers"
 	CommandNameLock                 = "lock"
+	CommandNameConsole              = "console"
 	CommandNameTerragruntReadConfig = "terragrunt-read-config"
 	NullTFVarsFile                  = ".terragrunt-null-vars.auto.tfvars.json"
 
@@ -63,6 +64,13 @@ var TerraformCommandsThatUseState = []string{
 	"state",
 }
 
+// TerraformCommandsThatChangeOutputs are the commands after which the cached outputs of the module are dropped.
+var TerraformCommandsThatChangeOutputs = []string{
+	"apply",
+	"destroy",
+	"refresh",
+}
+
 var TerraformCommandsThatDoNotNeedInit = []string{
 	"version",
 	"terragrunt-info",
@@ -91,7 +99,21 @@ func RunWithTarget(opts *options.TerragruntOptions, target *Target) error {
 	return runTerraform(opts, target)
 }
 
+// runTerraform runs the module, and then waits for the hooks it started in the background, as they are part of the run
+// of the module.
 func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target) error {
+	startModuleRun(terragruntOptions)
+	defer finishModuleRun(terragruntOptions)
+
+	runErr := runTerraformWithoutJoin(terragruntOptions, target)
+
+	if err := waitForBackgroundHooks(terragruntOptions); err != nil {
+		return multierror.Append(runErr, err)
+	}
+	return runErr
+}
+
+func runTerraformWithoutJoin(terragruntOptions *options.TerragruntOptions, target *Target) error {
 	if err := checkVersionConstraints(terragruntOptions); err != nil {
 		return err
 	}
@@ -101,6 +123,10 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 		return err
 	}
 
+	if err := config.CheckFeatureFlags(terragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
 	if target.isPoint(TargetPointParseConfig) {
 		return target.runCallback(terragruntOptions, terragruntConfig)
 	}
@@ -120,6 +146,21 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 		return nil
 	}
 
+	if terragruntConfig.Exclude != nil && terragruntConfig.Exclude.ExcludesCommand(terragruntOptions.TerraformCommand) {
+		terragruntOptions.Logger.Infof(
+			"Skipping terragrunt module %s due to exclude block for command %s.",
+			terragruntOptions.TerragruntConfigPath,
+			terragruntOptions.TerraformCommand,
+		)
+		return nil
+	}
+
+	// The values exported by hooks can be used anywhere in the config, so the hooks exporting them run first.
+	terragruntConfig, err = runExportHooks(terragruntOptions, terragruntConfig)
+	if err != nil {
+		return err
+	}
+
 	// We merge the OriginalIAMRoleOptions into the one from the config, because the CLI passed IAMRoleOptions has
 	// precedence.
 	terragruntOptions.IAMRoleOptions = options.MergeIAMRoleOptions(
@@ -162,6 +203,20 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 		terragruntOptions.RetrySleepIntervalSec = time.Duration(*terragruntConfig.RetrySleepIntervalSec) * time.Second
 	}
 
+	// The retry blocks inherit the attempts and sleep interval that are not set explicitly from the settings above
+	terragruntOptions.RetryPolicies = nil
+	for _, retryConfig := range terragruntConfig.RetryConfigs {
+		policy, err := retryConfig.ToRetryPolicy(terragruntOptions.DefaultRetryPolicy())
+		if err != nil {
+			return err
+		}
+		terragruntOptions.RetryPolicies = append(terragruntOptions.RetryPolicies, *policy)
+	}
+
+	if err := setModuleDeadline(terragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
 	updatedTerragruntOptions := terragruntOptions
 	sourceUrl, err := config.GetTerraformSourceUrl(terragruntOptions, terragruntConfig)
 	if err != nil {
@@ -169,7 +224,12 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 	}
 
 	if sourceUrl != "" {
-		updatedTerragruntOptions, err = downloadTerraformSource(sourceUrl, terragruntOptions, terragruntConfig)
+		mirrorUrls, err := config.GetTerraformSourceMirrorUrls(terragruntOptions, terragruntConfig)
+		if err != nil {
+			return err
+		}
+
+		updatedTerragruntOptions, err = downloadTerraformSourceWithMirrors(sourceUrl, mirrorUrls, terragruntOptions, terragruntConfig)
 		if err != nil {
 			return err
 		}
@@ -191,6 +251,10 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 		return target.runCallback(updatedTerragruntOptions, terragruntConfig)
 	}
 
+	if terragruntOptions.CheckRemoteStateDependencies {
+		warnAboutUndeclaredRemoteStateDependencies(terragruntOptions, updatedTerragruntOptions.WorkingDir, terragruntConfig)
+	}
+
 	// We do the debug file generation here, after all the terragrunt generated terraform files are created so that we
 	// can ensure the tfvars json file only includes the vars that are defined in the module.
 	if updatedTerragruntOptions.Debug {
@@ -210,7 +274,128 @@ func runTerraform(terragruntOptions *options.TerragruntOptions, target *Target)
 			return nil
 		}
 	}
-	return runTerragruntWithConfig(terragruntOptions, updatedTerragruntOptions, terragruntConfig, target)
+
+	contentHash, skip := checkModuleUnchanged(terragruntOptions, terragruntConfig, sourceUrl)
+	if skip {
+		terragruntOptions.Logger.Infof("Skipping %s in %s: the module did not change since its last successful apply", terragruntOptions.TerraformCommand, terragruntOptions.WorkingDir)
+		return nil
+	}
+
+	planFile, cleanupPlanFile, err := preparePlanFile(updatedTerragruntOptions, terragruntConfig)
+	if err != nil {
+		return err
+	}
+	defer cleanupPlanFile()
+	recordPlanFile(updatedTerragruntOptions, planFile)
+
+	runErr := runTerragruntWithConfig(terragruntOptions, updatedTerragruntOptions, terragruntConfig, target)
+
+	// The state of the module may have changed even if the command failed, so the modules that depend on it must not
+	// read the outputs cached before.
+	if util.ListContainsElement(TerraformCommandsThatChangeOutputs, util.FirstArg(updatedTerragruntOptions.TerraformCliArgs)) {
+		config.InvalidateDependencyOutputCache(terragruntOptions, terragruntOptions.TerragruntConfigPath)
+	}
+
+	if runErr == nil {
+		runErr = recordSuccessfulRun(terragruntOptions, updatedTerragruntOptions, terragruntConfig, planFile, contentHash)
+	}
+
+	// The result of the module includes the hooks running in the background.
+	if err := waitForBackgroundHooks(updatedTerragruntOptions); err != nil {
+		runErr = multierror.Append(runErr, err)
+	}
+
+	return processResultHooks(updatedTerragruntOptions, terragruntConfig, runErr)
+}
+
+// recordSuccessfulRun saves what must be kept of a successful run of the command: the state snapshot, the planned
+// outputs and the content hash of the module.
+func recordSuccessfulRun(terragruntOptions *options.TerragruntOptions, updatedTerragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, planFile string, contentHash string) error {
+	if err := saveStateSnapshotIfNecessary(updatedTerragruntOptions, terragruntConfig); err != nil {
+		return err
+	}
+
+	if err := storePlanIfNecessary(updatedTerragruntOptions, planFile); err != nil {
+		return err
+	}
+
+	if contentHash != "" {
+		if err := writeAppliedHash(terragruntOptions, contentHash); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to record the content hash of the module: %v", err)
+		}
+	}
+	return nil
+}
+
+// checkModuleUnchanged computes the content hash of the module for the commands that can be skipped with
+// --terragrunt-skip-unchanged, and returns whether the command should be skipped because the hash matches the one
+// recorded after the last successful apply. The returned hash is empty unless it should be recorded once the command
+// succeeds. Destroying the module removes the recorded hash, so that the next apply runs. As computing the hash reads
+// the whole module, it is only computed when the command can be skipped or the hash has to be recorded.
+func checkModuleUnchanged(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, sourceUrl string) (string, bool) {
+	args := terragruntOptions.TerraformCliArgs
+	command := util.FirstArg(args)
+
+	if command == CommandNameDestroy || (command == CommandNameApply && hasArgWithPrefix(args, "-destroy")) {
+		if err := removeAppliedHash(terragruntOptions); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to remove the content hash of the module: %v", err)
+		}
+		return "", false
+	}
+	if command != CommandNameApply && command != CommandNamePlan {
+		return "", false
+	}
+
+	// Only a full apply brings the infrastructure in line with the content of the module.
+	recordHash := command == CommandNameApply && !hasArgWithPrefix(args, "-target") && !hasArgWithPrefix(args, "-refresh-only")
+	if !terragruntOptions.SkipUnchanged && !recordHash {
+		return "", false
+	}
+
+	contentHash, err := moduleContentHash(terragruntOptions, terragruntConfig, sourceUrl)
+	if err != nil {
+		terragruntOptions.Logger.Warnf("Failed to compute the content hash of the module, it will not be skipped: %v", err)
+		return "", false
+	}
+
+	if terragruntOptions.SkipUnchanged && contentHash == readAppliedHash(terragruntOptions) {
+		return "", true
+	}
+
+	if !recordHash {
+		return "", false
+	}
+	return contentHash, false
+}
+
+// hasArgWithPrefix returns true if one of the given args is the given flag, with or without a value.
+func hasArgWithPrefix(args []string, flag string) bool {
+	for _, arg := range args {
+		if arg == flag || strings.HasPrefix(arg, flag+"=") {
+			return true
+		}
+	}
+	return false
+}
+
+// setModuleDeadline sets the time by which the commands run for the module must finish, based on the timeout attribute
+// of the terraform block or, if not set, the --terragrunt-timeout flag.
+func setModuleDeadline(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	timeout := terragruntOptions.ModuleTimeout
+	if terragruntConfig.Terraform != nil && terragruntConfig.Terraform.Timeout != nil {
+		timeout = *terragruntConfig.Terraform.Timeout
+	}
+	if timeout == "" {
+		return nil
+	}
+
+	duration, err := time.ParseDuration(timeout)
+	if err != nil || duration <= 0 {
+		return errors.WithStackTrace(InvalidModuleTimeout(timeout))
+	}
+	terragruntOptions.Deadline = time.Now().Add(duration)
+	terragruntOptions.Logger.Debugf("Commands for the module must finish within %s", duration)
+	return nil
 }
 
 func generateConfig(terragruntConfig *config.TerragruntConfig, updatedTerragruntOptions *options.TerragruntOptions) error {
@@ -253,7 +438,7 @@ func runTerragruntWithConfig(originalTerragruntOptions *options.TerragruntOption
 		}
 	}
 
-	if err := setTerragruntInputsAsEnvVars(terragruntOptions, terragruntConfig); err != nil {
+	if err := SetTerragruntInputsAsEnvVars(terragruntOptions, terragruntConfig); err != nil {
 		return err
 	}
 
@@ -279,6 +464,14 @@ func runTerragruntWithConfig(originalTerragruntOptions *options.TerragruntOption
 		}
 	}()
 
+	if util.FirstArg(terragruntOptions.TerraformCliArgs) == CommandNameConsole {
+		consoleFiles, err := setConsoleVarFile(terragruntOptions, terragruntConfig)
+		if err != nil {
+			return err
+		}
+		defer removeConsoleFilesOnExit(terragruntOptions, consoleFiles)()
+	}
+
 	// Now that we've run 'init' and have all the source code locally, we can finally run the patch command
 	if target.isPoint(TargetPointInitCommand) {
 		return target.runCallback(terragruntOptions, terragruntConfig)
@@ -381,7 +574,7 @@ func runActionWithHooks(description string, terragruntOptions *options.Terragrun
 
 // The Terragrunt configuration can contain a set of inputs to pass to Terraform as environment variables. This method
 // sets these environment variables in the given terragruntOptions.
-func setTerragruntInputsAsEnvVars(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+func SetTerragruntInputsAsEnvVars(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
 	asEnvVars, err := toTerraformEnvVars(terragruntConfig.Inputs)
 	if err != nil {
 		return err
@@ -401,22 +594,37 @@ func setTerragruntInputsAsEnvVars(terragruntOptions *options.TerragruntOptions,
 }
 
 func runTerraformWithRetry(terragruntOptions *options.TerragruntOptions) error {
-	// Retry the command configurable time with sleep in between
-	for i := 0; i < terragruntOptions.RetryMaxAttempts; i++ {
-		if out, tferr := shell.RunTerraformCommandWithOutput(terragruntOptions, terragruntOptions.TerraformCliArgs...); tferr != nil {
-			if out != nil && isRetryable(out.Stdout, out.Stderr, tferr, terragruntOptions) {
-				terragruntOptions.Logger.Infof("Encountered an error eligible for retrying. Sleeping %v before retrying.\n", terragruntOptions.RetrySleepIntervalSec)
-				time.Sleep(terragruntOptions.RetrySleepIntervalSec)
-			} else {
-				terragruntOptions.Logger.Errorf("%s invocation failed in %s", terragruntOptions.TerraformImplementation, terragruntOptions.WorkingDir)
-				return tferr
-			}
-		} else {
+	// Number of failed attempts per retry policy, so that every policy gets its own budget of attempts
+	attempts := map[string]int{}
+
+	for {
+		out, tferr := shell.RunTerraformCommandWithOutput(terragruntOptions, terragruntOptions.TerraformCliArgs...)
+		// A plan with -detailed-exitcode fails when there are changes, so its output is recorded either way.
+		if util.FirstArg(terragruntOptions.TerraformCliArgs) == CommandNamePlan && out != nil {
+			recordPlanOutput(terragruntOptions, out.Stdout)
+		}
+		if tferr == nil {
 			return nil
 		}
-	}
 
-	return errors.WithStackTrace(MaxRetriesExceeded{terragruntOptions})
+		var policy *options.RetryPolicy
+		if out != nil {
+			policy = findRetryPolicy(out.Stdout, out.Stderr, tferr, terragruntOptions)
+		}
+		if policy == nil {
+			terragruntOptions.Logger.Errorf("%s invocation failed in %s", terragruntOptions.TerraformImplementation, terragruntOptions.WorkingDir)
+			return tferr
+		}
+
+		attempts[policy.Name]++
+		if attempts[policy.Name] >= policy.MaxAttempts {
+			return errors.WithStackTrace(MaxRetriesExceeded{Opts: terragruntOptions, Policy: policy})
+		}
+
+		sleep := policy.SleepDuration(attempts[policy.Name])
+		terragruntOptions.Logger.Infof("Encountered an error eligible for retrying (retry policy %s, attempt %d of %d). Sleeping %v before retrying.\n", policy.Name, attempts[policy.Name], policy.MaxAttempts, sleep)
+		time.Sleep(sleep)
+	}
 }
 
 // Prepare for running 'terraform init' by initializing remote state storage and adding backend configuration arguments
@@ -642,13 +850,18 @@ func checkProtectedModule(terragruntOptions *options.TerragruntOptions, terragru
 	return nil
 }
 
-// isRetryable checks whether there was an error and if the output matches any of the configured RetryableErrors
+// isRetryable checks whether there was an error and if the output matches any of the configured retry policies
 func isRetryable(stdout string, stderr string, tferr error, terragruntOptions *options.TerragruntOptions) bool {
+	return findRetryPolicy(stdout, stderr, tferr, terragruntOptions) != nil
+}
+
+// findRetryPolicy returns the retry policy that matches the output of the failed terraform command, or nil if the
+// command should not be retried.
+func findRetryPolicy(stdout string, stderr string, tferr error, terragruntOptions *options.TerragruntOptions) *options.RetryPolicy {
 	if !terragruntOptions.AutoRetry || tferr == nil {
-		return false
+		return nil
 	}
-	// When -json is enabled, Terraform will send all output, errors included, to stdout.
-	return util.MatchesAny(terragruntOptions.RetryableErrors, stderr) || util.MatchesAny(terragruntOptions.RetryableErrors, stdout)
+	return terragruntOptions.FindRetryPolicy(util.FirstArg(terragruntOptions.TerraformCliArgs), stdout, stderr)
 }
 
 func filterTerraformExtraArgs(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) []string {
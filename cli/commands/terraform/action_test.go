@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	goerrors "github.com/go-errors/errors"
 	"github.com/gruntwork-io/go-commons/errors"
@@ -74,7 +75,7 @@ func TestSetTerragruntInputsAsEnvVars(t *testing.T) {
 
 			cfg := &config.TerragruntConfig{Inputs: testCase.inputsInConfig}
 
-			require.NoError(t, setTerragruntInputsAsEnvVars(opts, cfg))
+			require.NoError(t, SetTerragruntInputsAsEnvVars(opts, cfg))
 
 			assert.Equal(t, testCase.expected, opts.Env)
 		})
@@ -243,6 +244,63 @@ func TestAutoRetryFalseDisablesRetry(t *testing.T) {
 	require.False(t, retryable, "The error should not retry")
 }
 
+func TestFindRetryPolicyPrefersCommandSpecificPolicies(t *testing.T) {
+	t.Parallel()
+
+	tgOptions, err := options.NewTerragruntOptionsForTest("")
+	require.NoError(t, err)
+
+	tgOptions.AutoRetry = true
+	tgOptions.RetryableErrors = []string{".*default.*"}
+	tgOptions.RetryPolicies = []options.RetryPolicy{
+		{Name: "generic", RetryableErrors: []string{".*error.*"}, MaxAttempts: 2},
+		{Name: "apply_only", RetryableErrors: []string{".*error.*"}, Commands: []string{"apply"}, MaxAttempts: 5},
+	}
+	tferr := errors.WithStackTrace(goerrors.New("dummy error"))
+
+	tgOptions.TerraformCliArgs = []string{"apply"}
+	policy := findRetryPolicy("", "error is here", tferr, tgOptions)
+	require.NotNil(t, policy)
+	assert.Equal(t, "apply_only", policy.Name)
+
+	tgOptions.TerraformCliArgs = []string{"plan"}
+	policy = findRetryPolicy("", "error is here", tferr, tgOptions)
+	require.NotNil(t, policy)
+	assert.Equal(t, "generic", policy.Name)
+
+	policy = findRetryPolicy("default error", "", tferr, tgOptions)
+	require.NotNil(t, policy)
+	assert.Equal(t, "generic", policy.Name)
+
+	policy = findRetryPolicy("", "the default", tferr, tgOptions)
+	require.NotNil(t, policy)
+	assert.Equal(t, tgOptions.DefaultRetryPolicy().Name, policy.Name)
+
+	assert.Nil(t, findRetryPolicy("", "nothing to see", tferr, tgOptions))
+}
+
+func TestRetryPolicySleepDuration(t *testing.T) {
+	t.Parallel()
+
+	policy := options.RetryPolicy{
+		SleepInterval:     time.Second,
+		MaxSleepInterval:  10 * time.Second,
+		BackoffMultiplier: 3,
+	}
+
+	assert.Equal(t, time.Second, policy.SleepDuration(1))
+	assert.Equal(t, 3*time.Second, policy.SleepDuration(2))
+	assert.Equal(t, 9*time.Second, policy.SleepDuration(3))
+	assert.Equal(t, 10*time.Second, policy.SleepDuration(4))
+
+	policy.Jitter = true
+	for attempt := 1; attempt <= 4; attempt++ {
+		sleep := policy.SleepDuration(attempt)
+		assert.LessOrEqual(t, sleep, 10*time.Second)
+		assert.GreaterOrEqual(t, sleep, 500*time.Millisecond)
+	}
+}
+
 func TestTerragruntHandlesCatastrophicTerraformFailure(t *testing.T) {
 	t.Parallel()
 
@@ -477,3 +535,51 @@ func createTempFile(t *testing.T) string {
 
 	return filepath.ToSlash(tmpFile.Name())
 }
+
+func TestSetModuleDeadline(t *testing.T) {
+	t.Parallel()
+
+	configTimeout := "1h"
+	invalidTimeout := "soon"
+
+	testCases := []struct {
+		name          string
+		flagTimeout   string
+		configTimeout *string
+		expected      time.Duration
+		expectedErr   bool
+	}{
+		{"no timeout", "", nil, 0, false},
+		{"flag timeout", "30m", nil, 30 * time.Minute, false},
+		{"config timeout takes precedence", "30m", &configTimeout, time.Hour, false},
+		{"invalid timeout", "", &invalidTimeout, 0, true},
+		{"negative timeout", "-5m", nil, 0, true},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+			require.NoError(t, err)
+			opts.ModuleTimeout = testCase.flagTimeout
+
+			cfg := &config.TerragruntConfig{Terraform: &config.TerraformConfig{Timeout: testCase.configTimeout}}
+
+			before := time.Now()
+			err = setModuleDeadline(opts, cfg)
+			if testCase.expectedErr {
+				assert.IsType(t, InvalidModuleTimeout(""), errors.Unwrap(err))
+				return
+			}
+			require.NoError(t, err)
+
+			if testCase.expected == 0 {
+				assert.True(t, opts.Deadline.IsZero())
+				return
+			}
+			assert.WithinDuration(t, before.Add(testCase.expected), opts.Deadline, time.Minute)
+		})
+	}
+}
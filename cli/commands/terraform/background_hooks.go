@@ -0,0 +1,105 @@
+package terraform
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/hashicorp/go-multierror"
+)
+
+// The hooks running in the background, by the path of the config of the module they were started for, so that the
+// run of the module can join them whichever copy of the options they were started with.
+var backgroundHooks sync.Map
+
+// backgroundHook is a hook running in the background. The done channel is closed once the hook finished, after which
+// duration and err hold how long it ran and its result.
+type backgroundHook struct {
+	name     string
+	done     chan struct{}
+	duration time.Duration
+	err      error
+}
+
+// backgroundHookGroup is the list of the hooks started in the background for a module.
+type backgroundHookGroup struct {
+	mutex sync.Mutex
+	hooks []*backgroundHook
+}
+
+// startBackgroundHook starts the given hook without waiting for it to finish. The hook is joined by
+// waitForBackgroundHooks at the end of the run of the module, and killed if it runs for longer than its background
+// timeout.
+func startBackgroundHook(terragruntOptions *options.TerragruntOptions, curHook config.Hook, workingDir string, suppressStdout bool) {
+	terragruntOptions.Logger.Infof("Executing hook in the background: %s", curHook.Name)
+
+	// The hook is killed at its background timeout, unless the deadline of the module comes first.
+	startedAt := time.Now()
+	timeout := curHook.GetBackgroundTimeout()
+	hookOptions, killedAtTimeout := curHook.CommandOptions(terragruntOptions).CloneWithTimeout(timeout)
+	hookOptions.KillProcessGroup = curHook.HookTimeout().KillsProcessGroup()
+
+	hook := &backgroundHook{name: curHook.Name, done: make(chan struct{})}
+
+	rawGroup, _ := backgroundHooks.LoadOrStore(terragruntOptions.TerragruntConfigPath, &backgroundHookGroup{})
+	group := rawGroup.(*backgroundHookGroup)
+	group.mutex.Lock()
+	group.hooks = append(group.hooks, hook)
+	group.mutex.Unlock()
+
+	go func() {
+		defer close(hook.done)
+		execute := curHook.ExecuteCommand(hookOptions, workingDir)
+		_, err := shell.RunShellCommandWithOutput(hookOptions, workingDir, suppressStdout, false, execute[0], execute[1:]...)
+		if _, timedOut := errors.Unwrap(err).(shell.TimeoutExceeded); timedOut && killedAtTimeout {
+			err = errors.WithStackTrace(BackgroundHookTimedOut{Name: curHook.Name, Timeout: timeout})
+		}
+		hook.duration = time.Since(startedAt).Round(time.Millisecond)
+		hook.err = err
+	}()
+}
+
+// waitForBackgroundHooks waits for the hooks started in the background for the module to finish, logs how each of them
+// ended, and returns their errors.
+func waitForBackgroundHooks(terragruntOptions *options.TerragruntOptions) error {
+	rawGroup, found := backgroundHooks.LoadAndDelete(terragruntOptions.TerragruntConfigPath)
+	if !found {
+		return nil
+	}
+	group := rawGroup.(*backgroundHookGroup)
+	group.mutex.Lock()
+	defer group.mutex.Unlock()
+
+	var errorsOccured *multierror.Error
+	for _, hook := range group.hooks {
+		select {
+		case <-hook.done:
+		default:
+			terragruntOptions.Logger.Infof("Waiting for background hook %s to finish", hook.name)
+			<-hook.done
+		}
+
+		if hook.err != nil {
+			terragruntOptions.Logger.Errorf("Background hook %s failed after %s: %v", hook.name, hook.duration, hook.err)
+			errorsOccured = multierror.Append(errorsOccured, hook.err)
+		} else {
+			terragruntOptions.Logger.Infof("Background hook %s finished successfully after %s", hook.name, hook.duration)
+		}
+	}
+	return errorsOccured.ErrorOrNil()
+}
+
+// Custom error types
+
+type BackgroundHookTimedOut struct {
+	Name    string
+	Timeout time.Duration
+}
+
+func (err BackgroundHookTimedOut) Error() string {
+	return fmt.Sprintf("The background hook %s was killed as it was still running after its background_timeout of %s.", err.Name, err.Timeout)
+}
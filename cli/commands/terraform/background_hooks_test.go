@@ -0,0 +1,64 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func backgroundHookOptionsForTest(t *testing.T) *options.TerragruntOptions {
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = filepath.Dir(terragruntOptions.TerragruntConfigPath)
+	terragruntOptions.TerraformCommand = "apply"
+	return terragruntOptions
+}
+
+func TestBackgroundHookIsJoined(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := backgroundHookOptionsForTest(t)
+	runInBackground := true
+	hook := config.Hook{Name: "upload", Commands: []string{"apply"}, Execute: []string{"sh", "-c", "sleep 0.2 && touch uploaded"}, RunInBackground: &runInBackground}
+
+	start := time.Now()
+	require.NoError(t, runHook(terragruntOptions, &config.TerragruntConfig{}, hook, config.HookStatusSuccess))
+	assert.Less(t, time.Since(start), 200*time.Millisecond)
+
+	require.NoError(t, waitForBackgroundHooks(terragruntOptions))
+	assert.True(t, util.FileExists(filepath.Join(terragruntOptions.WorkingDir, "uploaded")))
+
+	// The hooks are only joined once.
+	require.NoError(t, waitForBackgroundHooks(terragruntOptions))
+}
+
+func TestBackgroundHookErrors(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := backgroundHookOptionsForTest(t)
+	runInBackground := true
+	timeout := "200ms"
+	hooks := []config.Hook{
+		{Name: "fail", Commands: []string{"apply"}, Execute: []string{"false"}, RunInBackground: &runInBackground},
+		{Name: "hang", Commands: []string{"apply"}, Execute: []string{"sleep", "5"}, RunInBackground: &runInBackground, BackgroundTimeout: &timeout},
+	}
+	require.NoError(t, processHooks(hooks, terragruntOptions, &config.TerragruntConfig{}, nil))
+
+	start := time.Now()
+	err := waitForBackgroundHooks(terragruntOptions)
+	assert.Less(t, time.Since(start), 5*time.Second)
+
+	multiErr, ok := err.(*multierror.Error)
+	require.True(t, ok, "unexpected error: %v", err)
+	require.Len(t, multiErr.Errors, 2)
+	assert.Contains(t, multiErr.Errors[0].Error(), "exit status 1")
+	assert.Equal(t, BackgroundHookTimedOut{Name: "hang", Timeout: 200 * time.Millisecond}, errors.Unwrap(multiErr.Errors[1]))
+}
@@ -31,6 +31,10 @@ func action(opts *options.TerragruntOptions) func(ctx *cli.Context) error {
 			opts.CheckDependentModules = true
 		}
 
+		if found, err := RunCustomCommand(opts.OptionsFromContext(ctx)); found || err != nil {
+			return err
+		}
+
 		if !opts.DisableCommandValidation && !collections.ListContainsElement(nativeTerraformCommands, opts.TerraformCommand) {
 			return errors.WithStackTrace(WrongTerraformCommand(opts.TerraformCommand))
 		}
@@ -0,0 +1,146 @@
+package terraform
+
+import (
+	"encoding/json"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// ConsoleTFVarsFile is the temporary tfvars file passed to terraform console, with the inputs of the unit and the
+	// outputs of its dependencies.
+	ConsoleTFVarsFile = ".terragrunt-console.tfvars.json"
+	// ConsoleVariablesFile is the temporary file declaring the variable holding the outputs of the dependencies, as
+	// terraform console only sees the variables declared by the module.
+	ConsoleVariablesFile = util.TerraformConsoleVariablesFile
+	// ConsoleDependencyVariable is the variable holding the outputs of the dependencies in terraform console, e.g.
+	// var.dependency.vpc.vpc_id.
+	ConsoleDependencyVariable = "dependency"
+)
+
+// The variable has a default, so that the file doesn't break the other commands if it is ever left behind.
+const consoleVariablesFileContents = `# Generated by Terragrunt for terraform console, and removed when it exits.
+variable "` + ConsoleDependencyVariable + `" {
+  type    = any
+  default = null
+}
+`
+
+// setConsoleVarFile writes a temporary tfvars file with the inputs of the unit that the module defines, and the outputs
+// of its dependencies in the dependency variable, and passes it to terraform console, so that expressions can be
+// explored against the values terragrunt resolved. It returns the files written, to be removed once the console exits.
+func setConsoleVarFile(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) ([]string, error) {
+	required, optional, err := terraform.ModuleVariables(terragruntOptions.WorkingDir)
+	if err != nil {
+		return nil, err
+	}
+	moduleVariables := append(required, optional...)
+
+	values := map[string]interface{}{}
+	for varName, varValue := range terragruntConfig.Inputs {
+		// terraform warns about the values of variables the module doesn't define, which the console can't use anyway.
+		if util.ListContainsElement(moduleVariables, varName) {
+			values[varName] = varValue
+		}
+	}
+
+	files := []string{}
+	dependencyOutputs, err := consoleDependencyOutputs(terragruntOptions, terragruntConfig)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case len(dependencyOutputs) == 0:
+	case util.ListContainsElement(moduleVariables, ConsoleDependencyVariable):
+		terragruntOptions.Logger.Warnf("The module defines the variable %s, so the outputs of the dependencies are not available in the console.", ConsoleDependencyVariable)
+	default:
+		variablesFile := filepath.Join(terragruntOptions.WorkingDir, ConsoleVariablesFile)
+		if err := os.WriteFile(variablesFile, []byte(consoleVariablesFileContents), os.FileMode(defaultPermissions)); err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		files = append(files, variablesFile)
+		values[ConsoleDependencyVariable] = dependencyOutputs
+	}
+
+	jsonContents, err := json.MarshalIndent(values, "", "  ")
+	if err != nil {
+		removeConsoleFiles(terragruntOptions, files)
+		return nil, errors.WithStackTrace(err)
+	}
+	varFile := filepath.Join(terragruntOptions.WorkingDir, ConsoleTFVarsFile)
+	if err := os.WriteFile(varFile, jsonContents, os.FileMode(defaultPermissions)); err != nil {
+		removeConsoleFiles(terragruntOptions, files)
+		return nil, errors.WithStackTrace(err)
+	}
+	files = append(files, varFile)
+
+	terragruntOptions.Logger.Debugf("Passing the inputs and the outputs of the dependencies to terraform console with %s", varFile)
+	terragruntOptions.InsertTerraformCliArgs("-var-file=" + varFile)
+	return files, nil
+}
+
+// consoleDependencyOutputs returns the outputs of each dependency of the unit as JSON, by dependency name.
+func consoleDependencyOutputs(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (map[string]json.RawMessage, error) {
+	outputs, err := terragruntConfig.DependencyOutputs(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	outputsJSON := map[string]json.RawMessage{}
+	for name, value := range outputs {
+		valueJSON, err := ctyjson.SimpleJSONValue{Value: value}.MarshalJSON()
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		outputsJSON[name] = valueJSON
+	}
+	return outputsJSON, nil
+}
+
+// removeConsoleFilesOnExit removes the given temporary files written for terraform console when the returned function
+// is called, or as soon as terragrunt is interrupted or terminated, e.g. while the module is initialized before the
+// console starts. The signal is then raised again, so that it is handled as usual.
+func removeConsoleFilesOnExit(terragruntOptions *options.TerragruntOptions, files []string) func() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case sig := <-signals:
+			signal.Stop(signals)
+			removeConsoleFiles(terragruntOptions, files)
+			if process, err := os.FindProcess(os.Getpid()); err == nil {
+				if err := process.Signal(sig); err != nil {
+					terragruntOptions.Logger.Debugf("Failed to raise signal %v again: %v", sig, err)
+				}
+			}
+		case <-done:
+		}
+	}()
+
+	return func() {
+		signal.Stop(signals)
+		close(done)
+		removeConsoleFiles(terragruntOptions, files)
+	}
+}
+
+// removeConsoleFiles removes the temporary files written for terraform console.
+func removeConsoleFiles(terragruntOptions *options.TerragruntOptions, files []string) {
+	for _, file := range files {
+		if err := os.Remove(file); err != nil && !os.IsNotExist(err) {
+			terragruntOptions.Logger.Debugf("Failed to remove console file %s: %v", file, err)
+		}
+	}
+}
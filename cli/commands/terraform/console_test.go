@@ -0,0 +1,95 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const consoleTestConfig = `
+dependency "vpc" {
+  config_path  = "../vpc"
+  skip_outputs = true
+
+  mock_outputs = {
+    vpc_id = "vpc-123"
+  }
+}
+
+inputs = {
+  name      = "app"
+  vpc_id    = dependency.vpc.outputs.vpc_id
+  undefined = "ignored"
+}
+`
+
+func TestSetConsoleVarFile(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	unitDir := filepath.Join(rootDir, "app")
+	require.NoError(t, os.MkdirAll(unitDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "vpc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "vpc", config.DefaultTerragruntConfigPath), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, config.DefaultTerragruntConfigPath), []byte(consoleTestConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, "variables.tf"), []byte("variable \"name\" {}\nvariable \"vpc_id\" {}\n"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(unitDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCliArgs = []string{CommandNameConsole}
+	terragruntConfig, err := config.ReadTerragruntConfig(opts)
+	require.NoError(t, err)
+
+	files, err := setConsoleVarFile(opts, terragruntConfig)
+	require.NoError(t, err)
+
+	varFile := filepath.Join(unitDir, ConsoleTFVarsFile)
+	assert.Equal(t, []string{filepath.Join(unitDir, ConsoleVariablesFile), varFile}, files)
+	assert.Equal(t, []string{CommandNameConsole, "-var-file=" + varFile}, opts.TerraformCliArgs)
+
+	contents, err := os.ReadFile(varFile)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "app", "vpc_id": "vpc-123", "dependency": {"vpc": {"vpc_id": "vpc-123"}}}`, string(contents))
+
+	// The variable has a default, so that a file left behind doesn't break the other commands.
+	variables, err := os.ReadFile(filepath.Join(unitDir, ConsoleVariablesFile))
+	require.NoError(t, err)
+	assert.Contains(t, string(variables), "default = null")
+
+	removeConsoleFilesOnExit(opts, files)()
+	for _, file := range files {
+		assert.NoFileExists(t, file)
+	}
+}
+
+func TestSetConsoleVarFileModuleDefinesDependency(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	unitDir := filepath.Join(rootDir, "app")
+	require.NoError(t, os.MkdirAll(unitDir, 0755))
+	require.NoError(t, os.MkdirAll(filepath.Join(rootDir, "vpc"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "vpc", config.DefaultTerragruntConfigPath), []byte(""), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, config.DefaultTerragruntConfigPath), []byte(consoleTestConfig), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, "variables.tf"), []byte("variable \"name\" {}\nvariable \"dependency\" {}\n"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(unitDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.TerraformCliArgs = []string{CommandNameConsole}
+	terragruntConfig, err := config.ReadTerragruntConfig(opts)
+	require.NoError(t, err)
+
+	files, err := setConsoleVarFile(opts, terragruntConfig)
+	require.NoError(t, err)
+	defer removeConsoleFiles(opts, files)
+
+	assert.Equal(t, []string{filepath.Join(unitDir, ConsoleTFVarsFile)}, files)
+	contents, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"name": "app"}`, string(contents))
+}
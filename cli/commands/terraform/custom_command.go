@@ -0,0 +1,73 @@
+package terraform
+
+import (
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/gruntwork-cli/collections"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The environment variables set for the executables run by the steps of custom commands, so they know which unit and
+// command they run for.
+const (
+	EnvNameUnitDir       = "TERRAGRUNT_UNIT_DIR"
+	EnvNameCustomCommand = "TERRAGRUNT_CUSTOM_COMMAND"
+)
+
+// RunCustomCommand runs the steps of the custom command of the unit of the given options named after the terraform
+// command, in order, stopping at the first step that fails. It returns false, without running anything, if the command
+// is a terraform command, or if the unit has no custom command of that name.
+func RunCustomCommand(opts *options.TerragruntOptions) (bool, error) {
+	if collections.ListContainsElement(nativeTerraformCommands, opts.TerraformCommand) || !util.FileExists(opts.TerragruntConfigPath) {
+		return false, nil
+	}
+
+	terragruntConfig, err := config.PartialParseConfigFile(opts.TerragruntConfigPath, opts, nil, []config.PartialDecodeSectionType{config.CustomCommandsBlock})
+	if err != nil {
+		return false, err
+	}
+	command := terragruntConfig.GetCustomCommand(opts.TerraformCommand)
+	if command == nil {
+		return false, nil
+	}
+
+	// The steps define their own args, so there is no step the args passed along the command could go to.
+	if len(opts.TerraformCliArgs) > 1 {
+		return true, errors.WithStackTrace(CustomCommandArgs{Name: command.Name, Args: opts.TerraformCliArgs[1:]})
+	}
+
+	for i, step := range command.Steps {
+		opts.Logger.Infof("Running step %d/%d of command %s", i+1, len(command.Steps), command.Name)
+		if err := runCustomCommandStep(opts, command.Name, step); err != nil {
+			return true, err
+		}
+	}
+	return true, nil
+}
+
+// runCustomCommandStep runs a step of a custom command: a terraform command, run as if it was passed to terragrunt, or
+// an executable, run in the directory of the unit.
+func runCustomCommandStep(opts *options.TerragruntOptions, commandName string, step config.CustomCommandStep) error {
+	stepOpts := opts.Clone(opts.TerragruntConfigPath)
+
+	if step.IsExecute() {
+		stepOpts.Env[EnvNameUnitDir] = filepath.Dir(opts.TerragruntConfigPath)
+		stepOpts.Env[EnvNameCustomCommand] = commandName
+		return shell.RunShellCommand(stepOpts, step.Execute[0], step.Execute[1:]...)
+	}
+
+	stepOpts.TerraformCommand = step.Command[0]
+	stepOpts.OriginalTerraformCommand = step.Command[0]
+	stepOpts.TerraformCliArgs = step.Command
+	if !stepOpts.DisableCommandValidation && !collections.ListContainsElement(nativeTerraformCommands, stepOpts.TerraformCommand) {
+		return errors.WithStackTrace(WrongTerraformCommand(stepOpts.TerraformCommand))
+	}
+	if stepOpts.TerraformCommand == CommandNameDestroy {
+		stepOpts.CheckDependentModules = true
+	}
+	return Run(stepOpts)
+}
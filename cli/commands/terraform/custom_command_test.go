@@ -0,0 +1,82 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const customCommandTestConfig = `
+commands {
+  record = [
+    ["sh", "-c", "echo \"$TERRAGRUNT_CUSTOM_COMMAND $TERRAGRUNT_UNIT_DIR\" > record.txt"],
+    ["sh", "-c", "echo second >> record.txt"],
+  ]
+  fail   = [["false"], ["touch", "not-run.txt"]]
+  broken = ["deploy"]
+}
+`
+
+func customCommandTestOptions(t *testing.T, command string, args ...string) *options.TerragruntOptions {
+	t.Helper()
+
+	unitDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(unitDir, config.DefaultTerragruntConfigPath), []byte(customCommandTestConfig), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(unitDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = unitDir
+	opts.TerraformCommand = command
+	opts.TerraformCliArgs = append([]string{command}, args...)
+	return opts
+}
+
+func TestRunCustomCommand(t *testing.T) {
+	t.Parallel()
+
+	opts := customCommandTestOptions(t, "record")
+	found, err := RunCustomCommand(opts)
+	require.NoError(t, err)
+	assert.True(t, found)
+
+	record, err := os.ReadFile(filepath.Join(opts.WorkingDir, "record.txt"))
+	require.NoError(t, err)
+	assert.Equal(t, "record "+opts.WorkingDir+"\nsecond\n", string(record))
+}
+
+func TestRunCustomCommandStopsAtFailedStep(t *testing.T) {
+	t.Parallel()
+
+	opts := customCommandTestOptions(t, "fail")
+	found, err := RunCustomCommand(opts)
+	require.Error(t, err)
+	assert.True(t, found)
+	assert.NoFileExists(t, filepath.Join(opts.WorkingDir, "not-run.txt"))
+}
+
+func TestRunCustomCommandNotFound(t *testing.T) {
+	t.Parallel()
+
+	for _, command := range []string{"plan", "unknown"} {
+		found, err := RunCustomCommand(customCommandTestOptions(t, command))
+		require.NoError(t, err)
+		assert.False(t, found, command)
+	}
+}
+
+func TestRunCustomCommandInvalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := RunCustomCommand(customCommandTestOptions(t, "record", "-var", "foo=bar"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "The custom command record doesn't take args, got -var foo=bar")
+
+	_, err = RunCustomCommand(customCommandTestOptions(t, "broken"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `Terraform has no command named "deploy"`)
+}
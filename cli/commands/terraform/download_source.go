@@ -60,6 +60,21 @@ func downloadTerraformSource(source string, terragruntOptions *options.Terragrun
 	return updatedTerragruntOptions, nil
 }
 
+// Download the given source URL as downloadTerraformSource does, but if that fails, retry with each of the given mirror
+// URLs in order, until one succeeds. The error of the last mirror is returned if none does.
+func downloadTerraformSourceWithMirrors(source string, mirrors []string, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (*options.TerragruntOptions, error) {
+	updatedTerragruntOptions, err := downloadTerraformSource(source, terragruntOptions, terragruntConfig)
+	for _, mirror := range mirrors {
+		if err == nil {
+			break
+		}
+		terragruntOptions.Logger.Warnf("Failed to download Terraform configurations from %s, retrying with mirror %s: %v", source, mirror, err)
+		source = mirror
+		updatedTerragruntOptions, err = downloadTerraformSource(source, terragruntOptions, terragruntConfig)
+	}
+	return updatedTerragruntOptions, err
+}
+
 // Download the specified TerraformSource if the latest code hasn't already been downloaded.
 func downloadTerraformSourceIfNecessary(terraformSource *terraform.Source, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
 	if terragruntOptions.SourceUpdate {
@@ -238,6 +238,29 @@ func TestDownloadTerraformSourceIfNecessaryRemoteUrlOverrideSource(t *testing.T)
 	testDownloadTerraformSourceIfNecessary(t, canonicalUrl, downloadDir, true, "# Hello, World", true)
 }
 
+func TestDownloadTerraformSourceWithMirrors(t *testing.T) {
+	t.Parallel()
+
+	downloadDir := tmpDir(t)
+	defer os.Remove(downloadDir)
+
+	_, terragruntOptions, terragruntConfig, err := createConfig(t, "file:///should-not-be-used", downloadDir, false)
+	require.NoError(t, err)
+	terragruntOptions.DownloadDir = downloadDir
+	terragruntOptions.WorkingDir = tmpDir(t)
+	defer os.Remove(terragruntOptions.WorkingDir)
+
+	source := fmt.Sprintf("file://%s", absPath(t, "../../../test/fixture-download-source/does-not-exist"))
+	mirror := fmt.Sprintf("file://%s", absPath(t, "../../../test/fixture-download-source/hello-world"))
+
+	updatedTerragruntOptions, err := downloadTerraformSourceWithMirrors(source, []string{mirror}, terragruntOptions, terragruntConfig)
+	require.NoError(t, err)
+	assert.Equal(t, "# Hello, World", readFile(t, util.JoinPath(updatedTerragruntOptions.WorkingDir, "main.tf")))
+
+	_, err = downloadTerraformSourceWithMirrors(source, []string{source}, terragruntOptions, terragruntConfig)
+	require.Error(t, err)
+}
+
 func TestInvalidModulePath(t *testing.T) {
 	t.Parallel()
 
@@ -45,9 +45,56 @@ func (err ModuleIsProtected) Error() string {
 }
 
 type MaxRetriesExceeded struct {
-	Opts *options.TerragruntOptions
+	Opts   *options.TerragruntOptions
+	Policy *options.RetryPolicy
 }
 
 func (err MaxRetriesExceeded) Error() string {
-	return fmt.Sprintf("Exhausted retries (%v) for command %v %v", err.Opts.RetryMaxAttempts, err.Opts.TerraformPath, strings.Join(err.Opts.TerraformCliArgs, " "))
+	maxAttempts := err.Opts.RetryMaxAttempts
+	if err.Policy != nil {
+		maxAttempts = err.Policy.MaxAttempts
+	}
+	return fmt.Sprintf("Exhausted retries (%v) for command %v %v", maxAttempts, err.Opts.TerraformPath, strings.Join(err.Opts.TerraformCliArgs, " "))
+}
+
+type InvalidModuleTimeout string
+
+func (timeout InvalidModuleTimeout) Error() string {
+	return fmt.Sprintf("Invalid module timeout %q: expected a positive duration, such as 30m or 1h30m", string(timeout))
+}
+
+type StateSnapshotFailed struct {
+	Location string
+	Err      error
+}
+
+func (err StateSnapshotFailed) Error() string {
+	return fmt.Sprintf("The apply succeeded, but the snapshot of the state could not be saved to %s: %v", err.Location, err.Err)
+}
+
+type PlannedOutputsFailed struct {
+	PlanFile string
+	Err      error
+}
+
+func (err PlannedOutputsFailed) Error() string {
+	return fmt.Sprintf("The plan succeeded, but the planned outputs could not be read from %s: %v", err.PlanFile, err.Err)
+}
+
+type PlanConditionFailed struct {
+	Name string
+	Err  error
+}
+
+func (err PlanConditionFailed) Error() string {
+	return fmt.Sprintf("The plan could not be read to evaluate the if_plan condition of hook %s: %v", err.Name, err.Err)
+}
+
+type CustomCommandArgs struct {
+	Name string
+	Args []string
+}
+
+func (err CustomCommandArgs) Error() string {
+	return fmt.Sprintf("The custom command %s doesn't take args, got %s. Add them to its steps instead.", err.Name, strings.Join(err.Args, " "))
 }
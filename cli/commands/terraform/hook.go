@@ -43,9 +43,10 @@ func processErrorHooks(hooks []config.ErrorHook, terragruntOptions *options.Terr
 		},
 	}
 	errorMessage := customMultierror.Error()
+	terragruntOptions.Logger.Debugf("Detected error classes: %v", config.ClassifyError(errorMessage))
 
 	for _, curHook := range hooks {
-		if util.MatchesAny(curHook.OnErrors, errorMessage) && util.ListContainsElement(curHook.Commands, terragruntOptions.TerraformCommand) {
+		if curHook.MatchesError(errorMessage) && util.ListContainsElement(curHook.Commands, terragruntOptions.TerraformCommand) {
 			terragruntOptions.Logger.Infof("Executing hook: %s", curHook.Name)
 			workingDir := ""
 			if curHook.WorkingDir != nil {
@@ -57,16 +58,7 @@ func processErrorHooks(hooks []config.ErrorHook, terragruntOptions *options.Terr
 				suppressStdout = true
 			}
 
-			actionToExecute := curHook.Execute[0]
-			actionParams := curHook.Execute[1:]
-
-			_, possibleError := shell.RunShellCommandWithOutput(
-				terragruntOptions,
-				workingDir,
-				suppressStdout,
-				false,
-				actionToExecute, actionParams...,
-			)
+			possibleError := config.RunHookCommand(curHook.CommandOptions(terragruntOptions), curHook.Name, curHook.HookTimeout(), workingDir, suppressStdout, curHook.Execute)
 			if possibleError != nil {
 				terragruntOptions.Logger.Errorf("Error running hook %s with message: %s", curHook.Name, possibleError.Error())
 				errorsOccured = multierror.Append(errorsOccured, possibleError)
@@ -77,6 +69,13 @@ func processErrorHooks(hooks []config.ErrorHook, terragruntOptions *options.Terr
 }
 
 func processHooks(hooks []config.Hook, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, previousExecErrors *multierror.Error) error {
+	return processHooksWithStatus(hooks, terragruntOptions, terragruntConfig, previousExecErrors, "")
+}
+
+// processHooksWithStatus runs the given hooks like processHooks, reporting the given status of the run of the module to
+// the http hooks. When the status is empty, it is a failure if an error occurred before the hook, and a success
+// otherwise.
+func processHooksWithStatus(hooks []config.Hook, terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, previousExecErrors *multierror.Error, status string) error {
 	if len(hooks) == 0 {
 		return nil
 	}
@@ -86,9 +85,35 @@ func processHooks(hooks []config.Hook, terragruntOptions *options.TerragruntOpti
 	terragruntOptions.Logger.Debugf("Detected %d Hooks", len(hooks))
 
 	for _, curHook := range hooks {
+		// The hooks with export already ran, when the config was read.
+		if curHook.Export != nil {
+			continue
+		}
+
 		allPreviousErrors := multierror.Append(previousExecErrors, errorsOccured)
 		if shouldRunHook(curHook, terragruntOptions, allPreviousErrors) {
-			err := runHook(terragruntOptions, terragruntConfig, curHook)
+			if curHook.IfPlan != nil {
+				matches, err := planConditionMatches(terragruntOptions, curHook)
+				if err != nil {
+					terragruntOptions.Logger.Errorf("Error running hook %s with message: %s", curHook.Name, err.Error())
+					errorsOccured = multierror.Append(errorsOccured, err)
+					continue
+				}
+				if !matches {
+					terragruntOptions.Logger.Infof("Skipping hook %s, as the plan doesn't match its if_plan condition", curHook.Name)
+					continue
+				}
+			}
+
+			hookStatus := status
+			if hookStatus == "" {
+				hookStatus = config.HookStatusSuccess
+				if allPreviousErrors.ErrorOrNil() != nil {
+					hookStatus = config.HookStatusFailure
+				}
+			}
+
+			err := runHook(terragruntOptions, terragruntConfig, curHook, hookStatus)
 			if err != nil {
 				errorsOccured = multierror.Append(errorsOccured, err)
 			}
@@ -98,6 +123,19 @@ func processHooks(hooks []config.Hook, terragruntOptions *options.TerragruntOpti
 	return errorsOccured.ErrorOrNil()
 }
 
+// processResultHooks runs the on_success hooks if the given result of running the module is nil, and the on_failure
+// hooks otherwise. The errors of the hooks are added to the result, but the on_failure hooks run regardless of it.
+func processResultHooks(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, runErr error) error {
+	if runErr == nil {
+		return processHooksWithStatus(terragruntConfig.Terraform.GetOnSuccessHooks(), terragruntOptions, terragruntConfig, nil, config.HookStatusSuccess)
+	}
+
+	if hookErr := processHooksWithStatus(terragruntConfig.Terraform.GetOnFailureHooks(), terragruntOptions, terragruntConfig, nil, config.HookStatusFailure); hookErr != nil {
+		return multierror.Append(runErr, hookErr)
+	}
+	return runErr
+}
+
 func shouldRunHook(hook config.Hook, terragruntOptions *options.TerragruntOptions, previousExecErrors *multierror.Error) bool {
 	// if there's no previous error, execute command
 	// OR if a previous error DID happen AND we want to run anyways
@@ -111,8 +149,19 @@ func shouldRunHook(hook config.Hook, terragruntOptions *options.TerragruntOption
 	return isCommandInHook && (!hasErrors || (hook.RunOnError != nil && *hook.RunOnError))
 }
 
-func runHook(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, curHook config.Hook) error {
-	terragruntOptions.Logger.Infof("Executing hook: %s", curHook.Name)
+func runHook(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, curHook config.Hook, status string) error {
+	if curHook.IsHTTP() {
+		terragruntOptions.Logger.Infof("Executing http hook: %s", curHook.Name)
+		if err := curHook.SendHTTPRequest(terragruntOptions, newHookEvent(terragruntOptions, curHook, status)); err != nil {
+			terragruntOptions.Logger.Errorf("Error running hook %s with message: %s", curHook.Name, err.Error())
+			return err
+		}
+		return nil
+	}
+
+	if !curHook.IsBackground() {
+		terragruntOptions.Logger.Infof("Executing hook: %s", curHook.Name)
+	}
 	workingDir := ""
 	if curHook.WorkingDir != nil {
 		workingDir = *curHook.WorkingDir
@@ -123,21 +172,15 @@ func runHook(terragruntOptions *options.TerragruntOptions, terragruntConfig *con
 		suppressStdout = true
 	}
 
-	actionToExecute := curHook.Execute[0]
-	actionParams := curHook.Execute[1:]
-
-	if actionToExecute == "tflint" {
-		if err := executeTFLint(terragruntOptions, terragruntConfig, curHook, workingDir); err != nil {
+	if curHook.IsBackground() {
+		startBackgroundHook(terragruntOptions, curHook, workingDir, suppressStdout)
+	} else if curHook.Execute[0] == "tflint" {
+		if err := executeTFLint(curHook.CommandOptions(terragruntOptions), terragruntConfig, curHook, workingDir); err != nil {
 			return err
 		}
 	} else {
-		_, possibleError := shell.RunShellCommandWithOutput(
-			terragruntOptions,
-			workingDir,
-			suppressStdout,
-			false,
-			actionToExecute, actionParams...,
-		)
+		hookOptions := curHook.CommandOptions(terragruntOptions)
+		possibleError := config.RunHookCommand(hookOptions, curHook.Name, curHook.HookTimeout(), workingDir, suppressStdout, curHook.ExecuteCommand(hookOptions, workingDir))
 		if possibleError != nil {
 			terragruntOptions.Logger.Errorf("Error running hook %s with message: %s", curHook.Name, possibleError.Error())
 			return possibleError
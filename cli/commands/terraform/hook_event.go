@@ -0,0 +1,62 @@
+package terraform
+
+import (
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The runs of the modules in progress, by the path of their config, which the events sent by the http hooks describe.
+var moduleRuns sync.Map
+
+// moduleRun records what the events of the http hooks report about the run of a module beyond its options, and the
+// plan the if_plan conditions of the hooks are evaluated against.
+type moduleRun struct {
+	startedAt time.Time
+	plan      *config.HookEventPlan
+
+	planFile        string
+	resourceChanges []config.PlanResourceChange
+}
+
+// startModuleRun records that the module of the given options starts to run.
+func startModuleRun(terragruntOptions *options.TerragruntOptions) {
+	moduleRuns.Store(terragruntOptions.TerragruntConfigPath, &moduleRun{startedAt: time.Now()})
+}
+
+// finishModuleRun forgets the run of the module of the given options, along with the values exported by its hooks.
+func finishModuleRun(terragruntOptions *options.TerragruntOptions) {
+	moduleRuns.Delete(terragruntOptions.TerragruntConfigPath)
+	config.ClearHookOutputs(terragruntOptions)
+}
+
+// recordPlanOutput records the change summary found in the given output of a plan of the module, if any.
+func recordPlanOutput(terragruntOptions *options.TerragruntOptions, output string) {
+	rawRun, found := moduleRuns.Load(terragruntOptions.TerragruntConfigPath)
+	if !found {
+		return
+	}
+	if changes, found := configstack.ParsePlanChanges(output); found {
+		rawRun.(*moduleRun).plan = &config.HookEventPlan{Add: changes.Add, Change: changes.Change, Destroy: changes.Destroy}
+	}
+}
+
+// newHookEvent returns the event describing the run of the module of the given options to the given http hook.
+func newHookEvent(terragruntOptions *options.TerragruntOptions, hook config.Hook, status string) config.HookEvent {
+	event := config.HookEvent{
+		Hook:    hook.Name,
+		Path:    filepath.Dir(terragruntOptions.TerragruntConfigPath),
+		Command: terragruntOptions.TerraformCommand,
+		Status:  status,
+	}
+	if rawRun, found := moduleRuns.Load(terragruntOptions.TerragruntConfigPath); found {
+		run := rawRun.(*moduleRun)
+		event.Duration = time.Since(run.startedAt).Round(time.Millisecond).Seconds()
+		event.Plan = run.plan
+	}
+	return event
+}
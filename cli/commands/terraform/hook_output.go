@@ -0,0 +1,46 @@
+package terraform
+
+import (
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// runExportHooks runs the before hooks with export that run for the command, in order, and returns the config read
+// again with the values they export, or the given config if there are none. The config is read again after each hook,
+// so that a hook can use the values exported by the hooks before it.
+func runExportHooks(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (*config.TerragruntConfig, error) {
+	for i := 0; ; i++ {
+		hooks := terragruntConfig.Terraform.GetExportHooks(terragruntOptions.TerraformCommand)
+		if i >= len(hooks) {
+			return terragruntConfig, nil
+		}
+		curHook := hooks[i]
+
+		terragruntOptions.Logger.Infof("Executing hook: %s", curHook.Name)
+		workingDir := ""
+		if curHook.WorkingDir != nil {
+			workingDir = *curHook.WorkingDir
+		}
+		suppressStdout := curHook.SuppressStdout != nil && *curHook.SuppressStdout
+
+		hookOptions := curHook.CommandOptions(terragruntOptions)
+		out, err := config.RunHookCommandWithOutput(hookOptions, curHook.Name, curHook.HookTimeout(), workingDir, suppressStdout, curHook.ExecuteCommand(hookOptions, workingDir))
+		if err != nil {
+			terragruntOptions.Logger.Errorf("Error running hook %s with message: %s", curHook.Name, err.Error())
+			return nil, err
+		}
+		// A hook killed at its timeout that only warns has no output.
+		stdout := ""
+		if out != nil {
+			stdout = out.Stdout
+		}
+		if err := config.SetHookOutput(terragruntOptions, terragruntConfig, curHook, stdout); err != nil {
+			return nil, err
+		}
+
+		terragruntConfig, err = config.ReadTerragruntConfig(terragruntOptions)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
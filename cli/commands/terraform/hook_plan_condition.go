@@ -0,0 +1,45 @@
+package terraform
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// recordPlanFile records the file the plan of the module of the given options is written to, which the if_plan
+// conditions of its hooks are evaluated against.
+func recordPlanFile(terragruntOptions *options.TerragruntOptions, planFile string) {
+	if rawRun, found := moduleRuns.Load(terragruntOptions.TerragruntConfigPath); found {
+		rawRun.(*moduleRun).planFile = planFile
+	}
+}
+
+// planConditionMatches returns true if the plan of the module matches the if_plan condition of the given hook. The plan
+// is read with `terraform show -json` the first time a condition is evaluated. There is nothing to match when the plan
+// was not written, e.g. because it failed.
+func planConditionMatches(terragruntOptions *options.TerragruntOptions, hook config.Hook) (bool, error) {
+	rawRun, found := moduleRuns.Load(terragruntOptions.TerragruntConfigPath)
+	if !found {
+		return false, nil
+	}
+	run := rawRun.(*moduleRun)
+	if run.planFile == "" || !util.FileExists(run.planFile) {
+		return false, nil
+	}
+
+	if run.resourceChanges == nil {
+		out, err := shell.RunShellCommandWithOutput(terragruntOptions, "", true, false, terragruntOptions.TerraformPath, "show", "-json", run.planFile)
+		if err != nil {
+			return false, errors.WithStackTrace(PlanConditionFailed{Name: hook.Name, Err: err})
+		}
+		changes, err := config.ParsePlanResourceChanges([]byte(out.Stdout))
+		if err != nil {
+			return false, errors.WithStackTrace(PlanConditionFailed{Name: hook.Name, Err: err})
+		}
+		run.resourceChanges = changes
+	}
+	return hook.IfPlan.Matches(run.resourceChanges), nil
+}
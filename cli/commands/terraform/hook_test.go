@@ -0,0 +1,181 @@
+package terraform
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessResultHooks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		runErr        error
+		expectedFiles []string
+	}{
+		{"success", nil, []string{"succeeded"}},
+		{"failure", fmt.Errorf("apply failed"), []string{"failed"}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			workingDir := t.TempDir()
+			terragruntConfig := &config.TerragruntConfig{
+				Terraform: &config.TerraformConfig{
+					OnSuccessHooks: []config.Hook{{Name: "success", Commands: []string{"apply"}, Execute: []string{"touch", "succeeded"}, WorkingDir: &workingDir}},
+					OnFailureHooks: []config.Hook{{Name: "failure", Commands: []string{"apply"}, Execute: []string{"touch", "failed"}, WorkingDir: &workingDir}},
+				},
+			}
+
+			terragruntOptions, err := options.NewTerragruntOptionsForTest("mock-path-for-test.hcl")
+			require.NoError(t, err)
+			terragruntOptions.TerraformCommand = "apply"
+
+			err = processResultHooks(terragruntOptions, terragruntConfig, testCase.runErr)
+			assert.Equal(t, testCase.runErr, err)
+
+			for _, file := range []string{"succeeded", "failed"} {
+				assert.Equal(t, util.ListContainsElement(testCase.expectedFiles, file), util.FileExists(filepath.Join(workingDir, file)), file)
+			}
+		})
+	}
+}
+
+func TestRunHTTPHookSendsEvent(t *testing.T) {
+	t.Parallel()
+
+	var event config.HookEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&event))
+	}))
+	defer server.Close()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "plan"
+
+	startModuleRun(terragruntOptions)
+	defer finishModuleRun(terragruntOptions)
+	recordPlanOutput(terragruntOptions, "Plan: 2 to add, 1 to change, 0 to destroy.")
+
+	hookType := config.HookTypeHTTP
+	hook := config.Hook{Name: "notify", Commands: []string{"plan"}, Type: &hookType, URL: &server.URL}
+	require.NoError(t, processHooks([]config.Hook{hook}, terragruntOptions, &config.TerragruntConfig{}, nil))
+
+	assert.Equal(t, "notify", event.Hook)
+	assert.Equal(t, filepath.Dir(terragruntOptions.TerragruntConfigPath), event.Path)
+	assert.Equal(t, "plan", event.Command)
+	assert.Equal(t, config.HookStatusSuccess, event.Status)
+	assert.Equal(t, &config.HookEventPlan{Add: 2, Change: 1}, event.Plan)
+}
+
+const testPlanJSON = `{"resource_changes": [
+  {"address": "aws_db_instance.main", "type": "aws_db_instance", "change": {"actions": ["delete", "create"]}},
+  {"address": "aws_s3_bucket.logs", "type": "aws_s3_bucket", "change": {"actions": ["no-op"]}}
+]}`
+
+func TestProcessHooksWithPlanCondition(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "plan"
+	terragruntOptions.WorkingDir = moduleDir
+
+	// A fake terraform that shows the plan as JSON.
+	terragruntOptions.TerraformPath = filepath.Join(moduleDir, "terraform")
+	require.NoError(t, os.WriteFile(terragruntOptions.TerraformPath, []byte("#!/bin/sh\ncat <<'EOF'\n"+testPlanJSON+"\nEOF\n"), 0755))
+	planFile := filepath.Join(moduleDir, "tfplan")
+	require.NoError(t, os.WriteFile(planFile, []byte("plan"), 0644))
+
+	startModuleRun(terragruntOptions)
+	defer finishModuleRun(terragruntOptions)
+	recordPlanFile(terragruntOptions, planFile)
+
+	hook := func(name string, condition config.HookPlanCondition) config.Hook {
+		return config.Hook{Name: name, Commands: []string{"plan"}, Execute: []string{"touch", filepath.Join(moduleDir, name)}, IfPlan: &condition}
+	}
+	hooks := []config.Hook{
+		hook("replaced", config.HookPlanCondition{Actions: []string{"replace"}}),
+		hook("destroyed-db", config.HookPlanCondition{Actions: []string{"delete"}, ResourceTypes: []string{"aws_db_instance"}}),
+		hook("changed-bucket", config.HookPlanCondition{ResourceTypes: []string{"aws_s3_bucket"}}),
+		hook("updated", config.HookPlanCondition{Actions: []string{"update"}}),
+	}
+	require.NoError(t, processHooks(hooks, terragruntOptions, &config.TerragruntConfig{}, nil))
+
+	assert.True(t, util.FileExists(filepath.Join(moduleDir, "replaced")))
+	assert.True(t, util.FileExists(filepath.Join(moduleDir, "destroyed-db")))
+	assert.False(t, util.FileExists(filepath.Join(moduleDir, "changed-bucket")))
+	assert.False(t, util.FileExists(filepath.Join(moduleDir, "updated")))
+}
+
+func TestProcessHooksWithPlanConditionWithoutPlan(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "plan"
+
+	startModuleRun(terragruntOptions)
+	defer finishModuleRun(terragruntOptions)
+
+	hook := config.Hook{Name: "approve", Commands: []string{"plan"}, Execute: []string{"touch", filepath.Join(moduleDir, "approve")}, IfPlan: &config.HookPlanCondition{}}
+	require.NoError(t, processHooks([]config.Hook{hook}, terragruntOptions, &config.TerragruntConfig{}, nil))
+	assert.False(t, util.FileExists(filepath.Join(moduleDir, "approve")))
+}
+
+func TestRunExportHooks(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "apply"
+	terragruntOptions.WorkingDir = moduleDir
+	defer config.ClearHookOutputs(terragruntOptions)
+
+	terragruntConfigContents := `
+terraform {
+  before_hook "ami" {
+    commands = ["apply"]
+    execute  = ["echo", "ami-123"]
+    export   = "ami"
+  }
+
+  before_hook "image" {
+    commands    = ["apply"]
+    execute     = ["echo", "{\"ami\": \"${hook_output.ami}\", \"region\": \"eu-west-1\"}"]
+    export      = "image"
+    export_json = true
+  }
+}
+
+inputs = {
+  image = hook_output.image
+}
+`
+	require.NoError(t, os.WriteFile(terragruntOptions.TerragruntConfigPath, []byte(terragruntConfigContents), 0644))
+	terragruntConfig, err := config.ReadTerragruntConfig(terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"image": nil}, terragruntConfig.Inputs)
+
+	terragruntConfig, err = runExportHooks(terragruntOptions, terragruntConfig)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"image": map[string]interface{}{"ami": "ami-123", "region": "eu-west-1"}}, terragruntConfig.Inputs)
+}
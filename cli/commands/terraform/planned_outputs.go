@@ -0,0 +1,109 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// preparePlanFile returns the path of the file plan writes the plan to, when the outputs of the dependencies of run-all
+// plan are read from the planned values, so that the outputs planned for the module can be read from it once the plan
+// is done, when hooks of the module have an if_plan condition to evaluate against it, or when run-all writes a summary
+// of the changes of the plans. Unless the plan is already
+// written to a file with -out, it is written to a temporary file, which the returned function removes. The path is
+// empty when the plan file is not needed.
+func preparePlanFile(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) (string, func(), error) {
+	noCleanup := func() {}
+
+	if util.FirstArg(terragruntOptions.TerraformCliArgs) != CommandNamePlan {
+		return "", noCleanup, nil
+	}
+	if !terragruntOptions.DependencyPlannedOutputs && !needsPlanChanges(terragruntOptions) && !terragruntConfig.Terraform.HasPlanConditionHooks(CommandNamePlan) {
+		return "", noCleanup, nil
+	}
+
+	if planFile := planOutArg(terragruntOptions.TerraformCliArgs); planFile != "" {
+		if !filepath.IsAbs(planFile) {
+			planFile = filepath.Join(terragruntOptions.WorkingDir, planFile)
+		}
+		return planFile, noCleanup, nil
+	}
+
+	planDir, err := os.MkdirTemp("", "terragrunt-plan")
+	if err != nil {
+		return "", noCleanup, errors.WithStackTrace(err)
+	}
+	cleanup := func() {
+		if err := os.RemoveAll(planDir); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to remove %s: %v", planDir, err)
+		}
+	}
+
+	planFile := filepath.Join(planDir, "terragrunt.tfplan")
+	terragruntOptions.InsertTerraformCliArgs("-out=" + planFile)
+	return planFile, cleanup, nil
+}
+
+// planOutArg returns the value of the -out flag of the given plan args, or an empty string if it is not set.
+func planOutArg(args []string) string {
+	for i, arg := range args {
+		if value, ok := strings.CutPrefix(arg, "-out="); ok {
+			return value
+		}
+		if arg == "-out" && i+1 < len(args) {
+			return args[i+1]
+		}
+	}
+	return ""
+}
+
+// needsPlanChanges returns true if the changes of the plan are recorded for the plan summary of run-all.
+func needsPlanChanges(terragruntOptions *options.TerragruntOptions) bool {
+	return terragruntOptions.PlanSummary || terragruntOptions.PlanSummaryJSONFile != ""
+}
+
+// storePlanIfNecessary reads the given plan file of a successful plan with `terraform show -json`, once for all that is
+// recorded from it: the outputs planned for the module and the changes of the plan. The terraform working dir must be
+// initialized, as showing the plan requires the providers of the module.
+func storePlanIfNecessary(terragruntOptions *options.TerragruntOptions, planFile string) error {
+	if planFile == "" || (!terragruntOptions.DependencyPlannedOutputs && !needsPlanChanges(terragruntOptions)) {
+		return nil
+	}
+
+	out, err := shell.RunShellCommandWithOutput(terragruntOptions, "", true, false, terragruntOptions.TerraformPath, "show", "-json", planFile)
+	if err != nil {
+		return errors.WithStackTrace(PlannedOutputsFailed{PlanFile: planFile, Err: err})
+	}
+	planJSON := []byte(out.Stdout)
+
+	if needsPlanChanges(terragruntOptions) {
+		// The summary reports the modules whose changes could not be read, so the plan itself doesn't fail.
+		if err := configstack.StorePlanChanges(terragruntOptions.TerragruntConfigPath, planJSON); err != nil {
+			terragruntOptions.Logger.Warnf("Failed to read the changes of the plan %s: %v", planFile, err)
+		}
+	}
+	return storePlannedOutputsIfNecessary(terragruntOptions, planFile, planJSON)
+}
+
+// storePlannedOutputsIfNecessary records the outputs planned for the module, read from the given plan in the format of
+// `terraform show -json`, so that the modules that depend on it read them instead of the outputs in its state.
+func storePlannedOutputsIfNecessary(terragruntOptions *options.TerragruntOptions, planFile string, planJSON []byte) error {
+	if !terragruntOptions.DependencyPlannedOutputs {
+		return nil
+	}
+
+	if err := config.StorePlannedOutputs(terragruntOptions.TerragruntConfigPath, planJSON); err != nil {
+		return errors.WithStackTrace(PlannedOutputsFailed{PlanFile: planFile, Err: err})
+	}
+
+	terragruntOptions.Logger.Debugf("Read the outputs planned for %s from %s", terragruntOptions.TerragruntConfigPath, planFile)
+	return nil
+}
@@ -0,0 +1,71 @@
+package terraform
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPreparePlanFile(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = moduleDir
+	opts.TerraformCliArgs = []string{"plan", "-input=false"}
+	terragruntConfig := &config.TerragruntConfig{}
+
+	// The plan file is only needed when the flag is set.
+	planFile, cleanup, err := preparePlanFile(opts, terragruntConfig)
+	require.NoError(t, err)
+	cleanup()
+	assert.Empty(t, planFile)
+	assert.Equal(t, []string{"plan", "-input=false"}, opts.TerraformCliArgs)
+
+	opts.DependencyPlannedOutputs = true
+	planFile, cleanup, err = preparePlanFile(opts, terragruntConfig)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"plan", "-out=" + planFile, "-input=false"}, opts.TerraformCliArgs)
+	assert.True(t, util.IsDir(filepath.Dir(planFile)))
+	cleanup()
+	assert.False(t, util.IsDir(filepath.Dir(planFile)))
+
+	// The plan file passed with -out is used as is.
+	opts.TerraformCliArgs = []string{"plan", "-out", "tfplan"}
+	planFile, cleanup, err = preparePlanFile(opts, terragruntConfig)
+	require.NoError(t, err)
+	cleanup()
+	assert.Equal(t, filepath.Join(moduleDir, "tfplan"), planFile)
+	assert.Equal(t, []string{"plan", "-out", "tfplan"}, opts.TerraformCliArgs)
+
+	opts.TerraformCliArgs = []string{"apply"}
+	planFile, cleanup, err = preparePlanFile(opts, terragruntConfig)
+	require.NoError(t, err)
+	cleanup()
+	assert.Empty(t, planFile)
+}
+
+func TestPreparePlanFileForPlanConditionHooks(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = moduleDir
+	opts.TerraformCliArgs = []string{"plan"}
+
+	terragruntConfig := &config.TerragruntConfig{Terraform: &config.TerraformConfig{
+		AfterHooks: []config.Hook{{Name: "approve", Commands: []string{"plan"}, Execute: []string{"true"}, IfPlan: &config.HookPlanCondition{Actions: []string{"delete"}}}},
+	}}
+	planFile, cleanup, err := preparePlanFile(opts, terragruntConfig)
+	require.NoError(t, err)
+	defer cleanup()
+	assert.NotEmpty(t, planFile)
+	assert.Equal(t, []string{"plan", "-out=" + planFile}, opts.TerraformCliArgs)
+}
@@ -0,0 +1,33 @@
+package terraform
+
+import (
+	"path/filepath"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/configstack"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// warnAboutUndeclaredRemoteStateDependencies logs a warning for every module whose state is read by the
+// terraform_remote_state data sources of the terraform code in the given dir, but that is not declared as a dependency
+// of the module, as run-all would not run them in the right order. Failing to look for them doesn't fail the command.
+func warnAboutUndeclaredRemoteStateDependencies(terragruntOptions *options.TerragruntOptions, terraformDir string, terragruntConfig *config.TerragruntConfig) {
+	undeclared, err := configstack.FindUndeclaredRemoteStateDependencies(terragruntOptions, terraformDir, terragruntConfig)
+	if err != nil {
+		terragruntOptions.Logger.Warnf("Failed to look for the terraform_remote_state data sources of %s: %v", terragruntOptions.TerragruntConfigPath, err)
+		return
+	}
+
+	for _, dependency := range undeclared {
+		path, err := filepath.Rel(filepath.Dir(terragruntOptions.TerragruntConfigPath), dependency.ModulePath)
+		if err != nil {
+			path = dependency.ModulePath
+		}
+		terragruntOptions.Logger.Warnf(
+			"%s reads the state of %s, which is not declared as a dependency of %s. Run `terragrunt dependency discover --write` to add it to the dependencies block.",
+			dependency.DataSource,
+			filepath.ToSlash(path),
+			terragruntOptions.TerragruntConfigPath,
+		)
+	}
+}
@@ -0,0 +1,149 @@
+package terraform
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The name of the file, in the download dir of the module, that stores the content hash of the module as of its last
+// successful apply.
+const appliedHashFile = ".terragrunt-applied-hash"
+
+// moduleContentHash computes a hash of everything that determines the outcome of applying the module: the resolved
+// configuration, including the inputs and the outputs of dependencies, the terraform source, the files of the module
+// and, for local sources, the files of the source, and the terraform version.
+func moduleContentHash(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig, sourceUrl string) (string, error) {
+	hash := sha256.New()
+
+	configCty, err := config.TerragruntConfigAsCty(terragruntConfig)
+	if err != nil {
+		return "", err
+	}
+	configJSON, err := ctyjson.Marshal(configCty, cty.DynamicPseudoType)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	fmt.Fprintf(hash, "config:%s\n", configJSON)
+
+	if terragruntOptions.TerraformVersion != nil {
+		fmt.Fprintf(hash, "terraform-version:%s\n", terragruntOptions.TerraformVersion.String())
+	}
+
+	if err := hashDirContents(hash, terragruntOptions.WorkingDir); err != nil {
+		return "", err
+	}
+
+	if sourceUrl != "" {
+		fmt.Fprintf(hash, "source:%s\n", sourceUrl)
+
+		source, err := terraform.NewSource(sourceUrl, terragruntOptions.DownloadDir, terragruntOptions.WorkingDir, terragruntOptions.Logger)
+		if err != nil {
+			return "", err
+		}
+		// The version of a local source is not in the URL, so the files themselves have to be hashed.
+		if terraform.IsLocalSource(source.CanonicalSourceURL) {
+			if err := hashDirContents(hash, source.CanonicalSourceURL.Path); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	return fmt.Sprintf("%x", hash.Sum(nil)), nil
+}
+
+// hashDirContents writes the relative path and a hash of the contents of every file in the given directory to the
+// given writer, in a deterministic order. Generated directories such as the terragrunt cache are skipped. Unlike the
+// source version used for downloads, this doesn't depend on modification times, which change with every checkout.
+func hashDirContents(writer io.Writer, dir string) error {
+	files := []string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == util.TerragruntCacheDir || info.Name() == options.DefaultTFDataDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		files = append(files, path)
+		return nil
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	sort.Strings(files)
+
+	for _, path := range files {
+		contents, err := os.ReadFile(path)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		relPath, err := filepath.Rel(dir, path)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		fmt.Fprintf(writer, "file:%s:%x\n", filepath.ToSlash(relPath), sha256.Sum256(contents))
+	}
+	return nil
+}
+
+// appliedHashPath returns the path of the file that stores the content hash of the module as of its last successful
+// apply. Like downloaded sources, it is stored in a folder of the download dir specific to the working dir of the
+// module, so that modules sharing a download dir don't overwrite each other.
+func appliedHashPath(terragruntOptions *options.TerragruntOptions) (string, error) {
+	canonicalWorkingDir, err := util.CanonicalPath(terragruntOptions.WorkingDir, "")
+	if err != nil {
+		return "", err
+	}
+	return util.JoinPath(terragruntOptions.DownloadDir, util.EncodeBase64Sha1(canonicalWorkingDir), appliedHashFile), nil
+}
+
+// readAppliedHash returns the content hash of the module as of its last successful apply, or an empty string if it
+// was never recorded.
+func readAppliedHash(terragruntOptions *options.TerragruntOptions) string {
+	path, err := appliedHashPath(terragruntOptions)
+	if err != nil {
+		return ""
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(contents)
+}
+
+func writeAppliedHash(terragruntOptions *options.TerragruntOptions, hash string) error {
+	path, err := appliedHashPath(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	if err := util.EnsureDirectory(filepath.Dir(path)); err != nil {
+		return err
+	}
+	return errors.WithStackTrace(os.WriteFile(path, []byte(hash), 0644))
+}
+
+func removeAppliedHash(terragruntOptions *options.TerragruntOptions) error {
+	path, err := appliedHashPath(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
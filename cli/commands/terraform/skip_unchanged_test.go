@@ -0,0 +1,109 @@
+package terraform
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestModuleContentHash(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte("# main"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = moduleDir
+	opts.DownloadDir = filepath.Join(moduleDir, util.TerragruntCacheDir)
+
+	cfg := &config.TerragruntConfig{Inputs: map[string]interface{}{"name": "foo"}}
+
+	hash, err := moduleContentHash(opts, cfg, "")
+	require.NoError(t, err)
+
+	// Generated files don't change the hash
+	require.NoError(t, os.MkdirAll(filepath.Join(moduleDir, util.TerragruntCacheDir), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, util.TerragruntCacheDir, "generated.tf"), []byte("# generated"), 0644))
+	sameHash, err := moduleContentHash(opts, cfg, "")
+	require.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	// Different inputs change the hash
+	otherInputsHash, err := moduleContentHash(opts, &config.TerragruntConfig{Inputs: map[string]interface{}{"name": "bar"}}, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherInputsHash)
+
+	// Different files change the hash
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte("# changed"), 0644))
+	otherFilesHash, err := moduleContentHash(opts, cfg, "")
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, otherFilesHash)
+
+	// Different source versions change the hash
+	sourceHash, err := moduleContentHash(opts, cfg, "git::https://github.com/foo/bar.git//vpc?ref=v0.1.0")
+	require.NoError(t, err)
+	otherSourceHash, err := moduleContentHash(opts, cfg, "git::https://github.com/foo/bar.git//vpc?ref=v0.2.0")
+	require.NoError(t, err)
+	assert.NotEqual(t, sourceHash, otherSourceHash)
+}
+
+func TestCheckModuleUnchanged(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte("# main"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(moduleDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = moduleDir
+	opts.DownloadDir = filepath.Join(moduleDir, util.TerragruntCacheDir)
+	opts.SkipUnchanged = true
+
+	cfg := &config.TerragruntConfig{}
+
+	// Nothing was applied yet, so apply runs and the hash is to be recorded
+	opts.TerraformCliArgs = []string{"apply", "-auto-approve"}
+	hash, skip := checkModuleUnchanged(opts, cfg, "")
+	assert.False(t, skip)
+	require.NotEmpty(t, hash)
+	require.NoError(t, writeAppliedHash(opts, hash))
+
+	// Once applied, plan and apply are skipped
+	opts.TerraformCliArgs = []string{"plan"}
+	_, skip = checkModuleUnchanged(opts, cfg, "")
+	assert.True(t, skip)
+
+	opts.TerraformCliArgs = []string{"apply", "-auto-approve"}
+	_, skip = checkModuleUnchanged(opts, cfg, "")
+	assert.True(t, skip)
+
+	// Without the flag, plan runs
+	opts.SkipUnchanged = false
+	opts.TerraformCliArgs = []string{"plan"}
+	_, skip = checkModuleUnchanged(opts, cfg, "")
+	assert.False(t, skip)
+
+	// Targeted applies are not recorded
+	opts.TerraformCliArgs = []string{"apply", "-target=aws_instance.foo"}
+	hash, skip = checkModuleUnchanged(opts, cfg, "")
+	assert.False(t, skip)
+	assert.Empty(t, hash)
+	opts.SkipUnchanged = true
+
+	// Destroying removes the recorded hash, so the next apply runs
+	opts.TerraformCliArgs = []string{"destroy"}
+	_, skip = checkModuleUnchanged(opts, cfg, "")
+	assert.False(t, skip)
+	assert.Empty(t, readAppliedHash(opts))
+
+	opts.TerraformCliArgs = []string{"apply"}
+	_, skip = checkModuleUnchanged(opts, cfg, "")
+	assert.False(t, skip)
+}
@@ -0,0 +1,43 @@
+package terraform
+
+import (
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// saveStateSnapshotIfNecessary copies the state of the module to the snapshot bucket of the remote_state block after a
+// successful apply. The terraform working dir must be initialized with the backend of the module.
+func saveStateSnapshotIfNecessary(terragruntOptions *options.TerragruntOptions, terragruntConfig *config.TerragruntConfig) error {
+	if terragruntConfig.RemoteState == nil || terragruntConfig.RemoteState.Snapshot == nil {
+		return nil
+	}
+
+	if util.FirstArg(terragruntOptions.TerraformCliArgs) != CommandNameApply {
+		return nil
+	}
+
+	snapshot := terragruntConfig.RemoteState.Snapshot
+
+	out, err := shell.RunShellCommandWithOutput(terragruntOptions, "", true, false, terragruntOptions.TerraformPath, "state", "pull")
+	if err != nil {
+		return errors.WithStackTrace(StateSnapshotFailed{Location: snapshot.String(), Err: err})
+	}
+
+	state := strings.TrimSpace(out.Stdout)
+	if state == "" {
+		terragruntOptions.Logger.Debugf("Module %s has no state, there is nothing to save to %s", terragruntOptions.TerragruntConfigPath, snapshot)
+		return nil
+	}
+
+	if err := snapshot.SaveStateSnapshot([]byte(state), terragruntOptions); err != nil {
+		return errors.WithStackTrace(StateSnapshotFailed{Location: snapshot.String(), Err: err})
+	}
+
+	return nil
+}
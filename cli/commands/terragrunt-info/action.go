@@ -3,12 +3,20 @@ package terragruntinfo
 import (
 	"encoding/json"
 	"fmt"
+	"path/filepath"
 
 	"github.com/gruntwork-io/terragrunt/cli/commands/terraform"
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
+	tfsource "github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
+// SchemaVersion is the version of the schema of the JSON output by 'terragrunt-info'. It is increased whenever a field
+// is removed or changes meaning, so that wrappers can check that they understand the output. Adding fields doesn't
+// change the version.
+const SchemaVersion = 1
+
 func Run(opts *options.TerragruntOptions) error {
 	target := terraform.NewTarget(terraform.TargetPointDownloadSource, runTerragruntInfo)
 
@@ -17,22 +25,40 @@ func Run(opts *options.TerragruntOptions) error {
 
 // Struct is output as JSON by 'terragrunt-info':
 type TerragruntInfoGroup struct {
+	SchemaVersion    int
 	ConfigPath       string
 	DownloadDir      string
 	IamRole          string
 	TerraformBinary  string
 	TerraformCommand string
 	WorkingDir       string
+
+	// The version of terraform or OpenTofu found, and which of the two it is: "terraform", "tofu" or "unknown".
+	TerraformVersion        string
+	TerraformImplementation string
+
+	// The source of the module, once resolved from the terraform block or --terragrunt-source, as a canonical URL
+	// without its subdirectory, and the ref it points to, if any. Both are empty when the module has no source.
+	SourceURL string
+	SourceRef string
+
+	// The backend of the remote_state block and its config, which are empty and null without remote_state.
+	Backend       string
+	BackendConfig map[string]interface{}
+
+	// The absolute paths of the modules the module depends on, from its dependency and dependencies blocks.
+	Dependencies []string
+
+	// The terraform data directory of the module, in the working directory, and the plugin cache directory set with
+	// TF_PLUGIN_CACHE_DIR, if any.
+	DataDir        string
+	PluginCacheDir string
 }
 
 func runTerragruntInfo(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) error {
-	group := TerragruntInfoGroup{
-		ConfigPath:       opts.TerragruntConfigPath,
-		DownloadDir:      opts.DownloadDir,
-		IamRole:          opts.IAMRoleOptions.RoleARN,
-		TerraformBinary:  opts.TerraformPath,
-		TerraformCommand: opts.TerraformCommand,
-		WorkingDir:       opts.WorkingDir,
+	group, err := newTerragruntInfoGroup(opts, cfg)
+	if err != nil {
+		return err
 	}
 
 	b, err := json.MarshalIndent(group, "", "  ")
@@ -43,5 +69,56 @@ func runTerragruntInfo(opts *options.TerragruntOptions, cfg *config.TerragruntCo
 	fmt.Fprintf(opts.Writer, "%s\n", b)
 
 	return nil
+}
+
+// newTerragruntInfoGroup returns the information output by 'terragrunt-info' for the module of the given options, once
+// its source is downloaded.
+func newTerragruntInfoGroup(opts *options.TerragruntOptions, cfg *config.TerragruntConfig) (*TerragruntInfoGroup, error) {
+	group := &TerragruntInfoGroup{
+		SchemaVersion:           SchemaVersion,
+		ConfigPath:              opts.TerragruntConfigPath,
+		DownloadDir:             opts.DownloadDir,
+		IamRole:                 opts.IAMRoleOptions.RoleARN,
+		TerraformBinary:         opts.TerraformPath,
+		TerraformCommand:        opts.TerraformCommand,
+		WorkingDir:              opts.WorkingDir,
+		TerraformImplementation: string(opts.TerraformImplementation),
+		Dependencies:            []string{},
+		DataDir:                 opts.DataDir(),
+		PluginCacheDir:          opts.Env["TF_PLUGIN_CACHE_DIR"],
+	}
+	if opts.TerraformVersion != nil {
+		group.TerraformVersion = opts.TerraformVersion.String()
+	}
+
+	configDir := filepath.Dir(opts.TerragruntConfigPath)
+	sourceURL, err := config.GetTerraformSourceUrl(opts, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if sourceURL != "" {
+		source, err := tfsource.NewSource(sourceURL, opts.DownloadDir, configDir, opts.Logger)
+		if err != nil {
+			return nil, err
+		}
+		group.SourceURL = source.CanonicalSourceURL.String()
+		group.SourceRef = source.CanonicalSourceURL.Query().Get("ref")
+	}
+
+	if cfg.RemoteState != nil {
+		group.Backend = cfg.RemoteState.Backend
+		group.BackendConfig = cfg.RemoteState.Config
+	}
+
+	if cfg.Dependencies != nil {
+		for _, path := range cfg.Dependencies.Paths {
+			dependencyPath, err := util.CanonicalPath(path, configDir)
+			if err != nil {
+				return nil, err
+			}
+			group.Dependencies = append(group.Dependencies, dependencyPath)
+		}
+	}
 
+	return group, nil
 }
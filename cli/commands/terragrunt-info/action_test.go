@@ -0,0 +1,64 @@
+package terragruntinfo
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+func TestNewTerragruntInfoGroup(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	configPath := filepath.Join(rootDir, "app", config.DefaultTerragruntConfigPath)
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	opts.DownloadDir = filepath.Join(rootDir, "app", ".terragrunt-cache")
+	opts.TerraformVersion = version.Must(version.NewVersion("1.7.2"))
+	opts.TerraformImplementation = options.OpenTofuImpl
+	opts.Env = map[string]string{"TF_PLUGIN_CACHE_DIR": "/tmp/plugins"}
+
+	source := "git::https://github.com/acme/modules.git//vpc?ref=v1.2.0"
+	cfg := &config.TerragruntConfig{
+		Terraform:    &config.TerraformConfig{Source: &source},
+		RemoteState:  &remote.RemoteState{Backend: "s3", Config: map[string]interface{}{"bucket": "acme-state", "key": "app/terraform.tfstate"}},
+		Dependencies: &config.ModuleDependencies{Paths: []string{"../vpc", "../db"}},
+	}
+
+	group, err := newTerragruntInfoGroup(opts, cfg)
+	require.NoError(t, err)
+
+	assert.Equal(t, SchemaVersion, group.SchemaVersion)
+	assert.Equal(t, "1.7.2", group.TerraformVersion)
+	assert.Equal(t, "tofu", group.TerraformImplementation)
+	assert.Equal(t, "git::https://github.com/acme/modules.git?ref=v1.2.0", group.SourceURL)
+	assert.Equal(t, "v1.2.0", group.SourceRef)
+	assert.Equal(t, "s3", group.Backend)
+	assert.Equal(t, map[string]interface{}{"bucket": "acme-state", "key": "app/terraform.tfstate"}, group.BackendConfig)
+	assert.Equal(t, []string{filepath.Join(rootDir, "vpc"), filepath.Join(rootDir, "db")}, group.Dependencies)
+	assert.Equal(t, "/tmp/plugins", group.PluginCacheDir)
+}
+
+func TestNewTerragruntInfoGroupWithoutSource(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	group, err := newTerragruntInfoGroup(opts, &config.TerragruntConfig{})
+	require.NoError(t, err)
+
+	assert.Empty(t, group.SourceURL)
+	assert.Empty(t, group.SourceRef)
+	assert.Empty(t, group.Backend)
+	assert.Nil(t, group.BackendConfig)
+	assert.Equal(t, []string{}, group.Dependencies)
+	assert.Empty(t, group.TerraformVersion)
+}
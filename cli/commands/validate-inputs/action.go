@@ -9,6 +9,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/google/shlex"
@@ -35,10 +37,16 @@ func runValidateInputs(opts *options.TerragruntOptions, cfg *config.TerragruntCo
 	}
 	allVars := append(required, optional...)
 
+	varTypes, err := tr.ModuleVariableTypes(opts.WorkingDir)
+	if err != nil {
+		return err
+	}
+
 	allInputs, err := getDefinedTerragruntInputs(opts, cfg)
 	if err != nil {
 		return err
 	}
+	sort.Strings(allInputs)
 
 	// Unused variables are those that are passed in by terragrunt, but are not defined in terraform.
 	unusedVars := []string{}
@@ -55,6 +63,10 @@ func runValidateInputs(opts *options.TerragruntOptions, cfg *config.TerragruntCo
 			missingVars = append(missingVars, varName)
 		}
 	}
+	sort.Strings(missingVars)
+
+	// Mismatched inputs are those of the inputs attribute whose value doesn't match the type of their variable.
+	typeMismatches := inputTypeMismatches(opts, cfg.Inputs, varTypes)
 
 	// Now print out all the information
 	if len(unusedVars) > 0 {
@@ -79,12 +91,48 @@ func runValidateInputs(opts *options.TerragruntOptions, cfg *config.TerragruntCo
 		opts.Logger.Debug(fmt.Sprintf("Strict mode enabled: %t", opts.ValidateStrict))
 	}
 
+	if len(typeMismatches) > 0 {
+		opts.Logger.Error("The following inputs don't match the type of their variable:\n")
+		for _, mismatch := range typeMismatches {
+			opts.Logger.Errorf("\t- %s (%s): %s", mismatch.Name, mismatch.Type, mismatch.Error)
+		}
+		opts.Logger.Error("")
+	}
+
+	report := &InputsReport{
+		UnusedInputs:       unusedVars,
+		MissingInputs:      missingVars,
+		TypeMismatches:     typeMismatches,
+		CommentedOutInputs: []string{},
+		AddedInputs:        []string{},
+		StrictMode:         opts.ValidateStrict,
+	}
+	if opts.ValidateInputsFix {
+		report.CommentedOutInputs, report.AddedInputs, err = fixInputs(opts, unusedVars, missingVars, varTypes)
+		if err != nil {
+			return err
+		}
+	}
+
+	// The unused inputs that were commented out are no longer misaligned. The missing inputs that were added are still
+	// missing, as their stubs are set to null until they are filled in.
+	remainingUnusedVars := slices.DeleteFunc(slices.Clone(unusedVars), func(varName string) bool {
+		return util.ListContainsElement(report.CommentedOutInputs, varName)
+	})
+	report.Valid = len(missingVars) == 0 && len(typeMismatches) == 0 && (len(remainingUnusedVars) == 0 || !opts.ValidateStrict)
+
+	if opts.ValidateInputsJSONFile != "" {
+		if err := report.writeJSONFile(opts, opts.ValidateInputsJSONFile); err != nil {
+			return err
+		}
+	}
+
 	// Return an error when there are misaligned inputs. Terragrunt strict mode defaults to false. When it is false,
-	// an error will only be returned if required inputs are missing. When strict mode is true, an error will be
-	// returned if required inputs are missing OR if any unused variables are passed
-	if len(missingVars) > 0 || len(unusedVars) > 0 && opts.ValidateStrict {
+	// an error will only be returned if required inputs are missing, or inputs don't match the type of their variable.
+	// When strict mode is true, an error will be returned if any unused variables are passed too.
+	if !report.Valid {
 		return fmt.Errorf(fmt.Sprintf("Terragrunt configuration has misaligned inputs. Strict mode enabled: %t.", opts.ValidateStrict))
-	} else if len(unusedVars) > 0 {
+	} else if len(remainingUnusedVars) > 0 {
 		opts.Logger.Warn("Terragrunt configuration has misaligned inputs, but running in relaxed mode so ignoring.")
 	}
 
@@ -9,6 +9,8 @@ const (
 	CommandName = "validate-inputs"
 
 	FlagTerragruntStrictValidate = "terragrunt-strict-validate"
+	FlagTerragruntValidateJSON   = "terragrunt-validate-inputs-json"
+	FlagTerragruntValidateFix    = "terragrunt-validate-inputs-fix"
 )
 
 func NewFlags(opts *options.TerragruntOptions) cli.Flags {
@@ -18,6 +20,18 @@ func NewFlags(opts *options.TerragruntOptions) cli.Flags {
 			Destination: &opts.ValidateStrict,
 			Usage:       "Sets strict mode for the validate-inputs command. By default, strict mode is off. When this flag is passed, strict mode is turned on. When strict mode is turned off, the validate-inputs command will only return an error if required inputs are missing from all input sources (env vars, var files, etc). When strict mode is turned on, an error will be returned if required inputs are missing OR if unused variables are passed to Terragrunt.",
 		},
+		&cli.GenericFlag[string]{
+			Name:        FlagTerragruntValidateJSON,
+			Destination: &opts.ValidateInputsJSONFile,
+			EnvVar:      "TERRAGRUNT_VALIDATE_INPUTS_JSON",
+			Usage:       "The path of a file to write the report of the validate-inputs command to as JSON: the unused inputs, the missing required inputs, and the inputs whose value doesn't match the type of their variable.",
+		},
+		&cli.BoolFlag{
+			Name:        FlagTerragruntValidateFix,
+			Aliases:     []string{"fix"},
+			Destination: &opts.ValidateInputsFix,
+			Usage:       "Comment out the unused inputs of the inputs attribute of terragrunt.hcl, and add stubs for the missing required inputs to it.",
+		},
 	}
 }
 
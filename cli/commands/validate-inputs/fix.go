@@ -0,0 +1,161 @@
+package validateinputs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// fixInputs comments out the given unused inputs of the inputs attribute of the terragrunt.hcl of the module, and adds
+// stubs set to null for the given missing inputs to it, or to a new inputs attribute if there is none. It returns the
+// names of the inputs it commented out and added. Only the inputs set in the terragrunt.hcl of the module, on lines of
+// their own, can be commented out, as the inputs of included configs are shared with other modules, and the inputs
+// attribute must be an object, e.g. not a call to merge, to be fixed.
+func fixInputs(opts *options.TerragruntOptions, unused []string, missing []string, types map[string]string) ([]string, []string, error) {
+	commented, added := []string{}, []string{}
+	if len(unused) == 0 && len(missing) == 0 {
+		return commented, added, nil
+	}
+
+	configPath := opts.TerragruntConfigPath
+	contents, err := os.ReadFile(configPath)
+	if err != nil {
+		return nil, nil, errors.WithStackTrace(err)
+	}
+	file, diags := hclsyntax.ParseConfig(contents, configPath, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, nil, errors.WithStackTrace(diags)
+	}
+	// The lines keep their newline, so that they can be joined back as is.
+	lines := strings.SplitAfter(string(contents), "\n")
+
+	attribute, found := file.Body.(*hclsyntax.Body).Attributes[config.MetadataInputs]
+	if !found {
+		if len(missing) == 0 {
+			return commented, added, nil
+		}
+		if !strings.HasSuffix(string(contents), "\n") {
+			lines = append(lines, "\n")
+		}
+		lines = append(lines, "\n", config.MetadataInputs+" = {\n")
+		lines = append(lines, inputStubs(missing, types, "  ")...)
+		lines = append(lines, "}\n")
+		added = missing
+	} else {
+		object, isObject := attribute.Expr.(*hclsyntax.ObjectConsExpr)
+		if !isObject {
+			opts.Logger.Warnf("Not fixing the inputs of %s, as its inputs attribute is not an object.", configPath)
+			return commented, added, nil
+		}
+
+		// The lines taken by other items, or by the braces of the object, can't be commented out.
+		sharedLines := map[int]int{object.OpenRange.Start.Line: 1, object.SrcRange.End.Line: 1}
+		for _, item := range object.Items {
+			for line := item.KeyExpr.Range().Start.Line; line <= item.ValueExpr.Range().End.Line; line++ {
+				sharedLines[line]++
+			}
+		}
+		for _, item := range object.Items {
+			name, isName := inputName(item)
+			if !isName || !util.ListContainsElement(unused, name) {
+				continue
+			}
+			start, end := item.KeyExpr.Range().Start.Line, item.ValueExpr.Range().End.Line
+			if sharedLinesBetween(sharedLines, start, end) {
+				opts.Logger.Warnf("Not commenting out the unused input %s of %s, as it shares its lines with other code.", name, configPath)
+				continue
+			}
+			indent := leadingWhitespace(lines[start-1])
+			for line := start; line <= end; line++ {
+				lines[line-1] = commentOut(lines[line-1], indent)
+			}
+			commented = append(commented, name)
+		}
+
+		if len(missing) > 0 {
+			closingLine := object.SrcRange.End.Line
+			if strings.TrimSpace(lines[closingLine-1][:object.SrcRange.End.Column-1]) != "}" {
+				opts.Logger.Warnf("Not adding the missing inputs to %s, as the closing brace of its inputs attribute is not on a line of its own.", configPath)
+			} else {
+				indent := leadingWhitespace(lines[closingLine-1]) + "  "
+				if len(object.Items) > 0 {
+					indent = leadingWhitespace(lines[object.Items[0].KeyExpr.Range().Start.Line-1])
+				}
+				stubs := inputStubs(missing, types, indent)
+				lines = append(lines[:closingLine-1], append(stubs, lines[closingLine-1:]...)...)
+				added = missing
+			}
+		}
+	}
+
+	if len(commented) == 0 && len(added) == 0 {
+		return commented, added, nil
+	}
+	if err := util.WriteFileWithSamePermissions(configPath, configPath, []byte(strings.Join(lines, ""))); err != nil {
+		return nil, nil, err
+	}
+	for _, name := range commented {
+		opts.Logger.Infof("Commented out the unused input %s in %s", name, configPath)
+	}
+	for _, name := range added {
+		opts.Logger.Infof("Added a stub for the missing input %s to %s", name, configPath)
+	}
+	return commented, added, nil
+}
+
+// inputName returns the name of the given item of the inputs attribute, if its key is a name or a string literal.
+func inputName(item hclsyntax.ObjectConsItem) (string, bool) {
+	key, diags := item.KeyExpr.Value(nil)
+	if diags.HasErrors() || !key.IsKnown() || key.IsNull() || key.Type() != cty.String {
+		return "", false
+	}
+	return key.AsString(), true
+}
+
+// sharedLinesBetween returns true if a line between the given lines, included, is taken by more than one item or
+// brace.
+func sharedLinesBetween(sharedLines map[int]int, start int, end int) bool {
+	for line := start; line <= end; line++ {
+		if sharedLines[line] > 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// commentOut returns the given line commented out after the given indentation, which is the indentation of the first
+// line of the input, so that the nested lines keep their own indentation.
+func commentOut(line string, indent string) string {
+	if !strings.HasPrefix(line, indent) {
+		indent = leadingWhitespace(line)
+	}
+	return indent + "# " + strings.TrimPrefix(line, indent)
+}
+
+// leadingWhitespace returns the indentation of the given line.
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
+
+// inputStubs returns the lines of the stubs of the given missing inputs, set to null along with a comment giving the
+// type of their variable.
+func inputStubs(missing []string, types map[string]string, indent string) []string {
+	stubs := []string{}
+	for _, name := range missing {
+		comment := "TODO: set the required variable"
+		if varType, found := types[name]; found {
+			comment = fmt.Sprintf("%s of type %s", comment, varType)
+		}
+		stubs = append(stubs, fmt.Sprintf("%s%s = null # %s\n", indent, name, comment))
+	}
+	return stubs
+}
@@ -0,0 +1,144 @@
+package validateinputs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestFixInputs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name              string
+		config            string
+		unused            []string
+		missing           []string
+		expectedConfig    string
+		expectedCommented []string
+		expectedAdded     []string
+	}{
+		{
+			"comment out and add",
+			`inputs = {
+  region = "us-east-1"
+  legacy = {
+    enabled = true
+  }
+  "quoted" = 1 # unused too
+}
+`,
+			[]string{"legacy", "quoted", "from_env"},
+			[]string{"vpc_id", "tags"},
+			`inputs = {
+  region = "us-east-1"
+  # legacy = {
+  #   enabled = true
+  # }
+  # "quoted" = 1 # unused too
+  vpc_id = null # TODO: set the required variable of type string
+  tags = null # TODO: set the required variable
+}
+`,
+			[]string{"legacy", "quoted"},
+			[]string{"vpc_id", "tags"},
+		},
+		{
+			"no inputs attribute",
+			`terraform {
+  source = "../modules/vpc"
+}`,
+			nil,
+			[]string{"vpc_id"},
+			`terraform {
+  source = "../modules/vpc"
+}
+
+inputs = {
+  vpc_id = null # TODO: set the required variable of type string
+}
+`,
+			[]string{},
+			[]string{"vpc_id"},
+		},
+		{
+			"shared lines",
+			`inputs = { region = "us-east-1", legacy = true }
+`,
+			[]string{"legacy"},
+			[]string{"vpc_id"},
+			`inputs = { region = "us-east-1", legacy = true }
+`,
+			[]string{},
+			[]string{},
+		},
+		{
+			"not an object",
+			`inputs = merge(local.common, { legacy = true })
+`,
+			[]string{"legacy"},
+			nil,
+			`inputs = merge(local.common, { legacy = true })
+`,
+			[]string{},
+			[]string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			configPath := filepath.Join(t.TempDir(), config.DefaultTerragruntConfigPath)
+			require.NoError(t, os.WriteFile(configPath, []byte(testCase.config), 0644))
+			opts, err := options.NewTerragruntOptionsForTest(configPath)
+			require.NoError(t, err)
+
+			commented, added, err := fixInputs(opts, testCase.unused, testCase.missing, map[string]string{"vpc_id": "string"})
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedCommented, commented)
+			assert.Equal(t, testCase.expectedAdded, added)
+
+			contents, err := os.ReadFile(configPath)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedConfig, string(contents))
+		})
+	}
+}
+
+func TestRunValidateInputsFixKeepsMissingInputsInvalid(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(moduleDir, "main.tf"), []byte(`variable "vpc_id" {
+  type = string
+}
+`), 0644))
+	configPath := filepath.Join(moduleDir, config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte("inputs = {\n}\n"), 0644))
+
+	opts, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	opts.WorkingDir = moduleDir
+	opts.ValidateInputsFix = true
+	opts.ValidateInputsJSONFile = filepath.Join(t.TempDir(), "report.json")
+
+	// The stub of the missing input is added, but it is set to null until it is filled in.
+	require.Error(t, runValidateInputs(opts, &config.TerragruntConfig{}))
+
+	contents, err := os.ReadFile(configPath)
+	require.NoError(t, err)
+	assert.Contains(t, string(contents), "vpc_id = null # TODO")
+
+	report, err := os.ReadFile(opts.ValidateInputsJSONFile)
+	require.NoError(t, err)
+	assert.Contains(t, string(report), `"added_inputs": [`)
+	assert.Contains(t, string(report), `"valid": false`)
+}
@@ -0,0 +1,103 @@
+package validateinputs
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty/convert"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// InputsReport is the report of the validate-inputs command, written as JSON with the
+// --terragrunt-validate-inputs-json flag.
+type InputsReport struct {
+	// The inputs passed in by terragrunt that no variable of the module is defined for.
+	UnusedInputs []string `json:"unused_inputs"`
+	// The required variables of the module that terragrunt passes no input for.
+	MissingInputs []string `json:"missing_required_inputs"`
+	// The inputs of the inputs attribute whose value doesn't match the type of their variable.
+	TypeMismatches []InputTypeMismatch `json:"type_mismatches"`
+	// The unused inputs commented out, and the missing inputs added as stubs, with the --terragrunt-validate-inputs-fix
+	// flag.
+	CommentedOutInputs []string `json:"commented_out_inputs"`
+	AddedInputs        []string `json:"added_inputs"`
+	StrictMode         bool     `json:"strict_mode"`
+	// Whether the inputs are valid once fixed, in which case the command succeeds. The added stubs of the missing
+	// inputs don't make them valid.
+	Valid bool `json:"valid"`
+}
+
+// InputTypeMismatch is an input whose value doesn't match the type of its variable.
+type InputTypeMismatch struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Error string `json:"error"`
+}
+
+// writeJSONFile writes the report as JSON to the given path, relative to the directory of the terragrunt config.
+func (report *InputsReport) writeJSONFile(opts *options.TerragruntOptions, path string) error {
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(filepath.Dir(opts.TerragruntConfigPath), path)
+	}
+	contents, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := os.WriteFile(path, append(contents, '\n'), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	opts.Logger.Infof("Wrote the report of the inputs to %s", path)
+	return nil
+}
+
+// inputTypeMismatches returns the given inputs whose value can't be converted to the type of their variable, among the
+// given types of the variables of the module, sorted by name. The inputs set to null, and the variables whose type
+// can't be parsed, are not checked.
+func inputTypeMismatches(opts *options.TerragruntOptions, inputs map[string]interface{}, types map[string]string) []InputTypeMismatch {
+	mismatches := []InputTypeMismatch{}
+	for name, value := range inputs {
+		typeString, found := types[name]
+		if !found || value == nil {
+			continue
+		}
+
+		typeExpr, diags := hclsyntax.ParseExpression([]byte(typeString), "", hcl.InitialPos)
+		if diags.HasErrors() {
+			opts.Logger.Debugf("Not checking the type of input %s, as the type %s of its variable can't be parsed: %v", name, typeString, diags)
+			continue
+		}
+		varType, _, diags := typeexpr.TypeConstraintWithDefaults(typeExpr)
+		if diags.HasErrors() {
+			opts.Logger.Debugf("Not checking the type of input %s, as the type %s of its variable can't be parsed: %v", name, typeString, diags)
+			continue
+		}
+
+		// The inputs are decoded from JSON, so they are encoded back to JSON to get their value.
+		jsonValue, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		impliedType, err := ctyjson.ImpliedType(jsonValue)
+		if err != nil {
+			continue
+		}
+		ctyValue, err := ctyjson.Unmarshal(jsonValue, impliedType)
+		if err != nil {
+			continue
+		}
+
+		if _, err := convert.Convert(ctyValue, varType); err != nil {
+			mismatches = append(mismatches, InputTypeMismatch{Name: name, Type: typeString, Error: err.Error()})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool { return mismatches[i].Name < mismatches[j].Name })
+	return mismatches
+}
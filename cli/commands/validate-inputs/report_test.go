@@ -0,0 +1,50 @@
+package validateinputs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestInputTypeMismatches(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	inputs := map[string]interface{}{
+		"region":         "us-east-1",
+		"instance_count": "3",
+		"subnets":        []interface{}{"a", "b"},
+		"tags":           map[string]interface{}{"team": "platform"},
+		"enabled":        "maybe",
+		"ports":          []interface{}{80, "https"},
+		"settings":       map[string]interface{}{"size": "large"},
+		"nullable":       nil,
+		"unused":         42,
+		"any":            []interface{}{1, "a"},
+	}
+	types := map[string]string{
+		"region":         "string",
+		"instance_count": "number",
+		"subnets":        "list(string)",
+		"tags":           "map(string)",
+		"enabled":        "bool",
+		"ports":          "list(number)",
+		"settings":       "object({ size = number, zone = optional(string) })",
+		"nullable":       "number",
+		"any":            "any",
+	}
+
+	mismatches := inputTypeMismatches(opts, inputs, types)
+	names := []string{}
+	for _, mismatch := range mismatches {
+		names = append(names, mismatch.Name)
+	}
+	assert.Equal(t, []string{"enabled", "ports", "settings"}, names)
+	assert.Equal(t, "bool", mismatches[0].Type)
+	assert.Equal(t, "a bool is required", mismatches[0].Error)
+}
@@ -0,0 +1,385 @@
+package codegen
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+)
+
+// The sections of the OpenTofu state encryption config, which are generated as blocks of the `encryption` block in
+// the terraform block of the backend. See https://opentofu.org/docs/language/state/encryption/.
+const (
+	EncryptionKeyProvider            = "key_provider"
+	EncryptionMethod                 = "method"
+	EncryptionState                  = "state"
+	EncryptionPlan                   = "plan"
+	EncryptionRemoteStateDataSources = "remote_state_data_sources"
+
+	encryptionFallback              = "fallback"
+	encryptionEnforced              = "enforced"
+	encryptionKeys                  = "keys"
+	encryptionChain                 = "chain"
+	encryptionDefault               = "default"
+	encryptionRemoteStateDataSource = "remote_state_data_source"
+)
+
+// ValidateEncryptionConfig validates the encryption config of a remote_state block, which has the following shape:
+//
+//	encryption = {
+//	  key_provider = {
+//	    pbkdf2 = {
+//	      default = { passphrase = "..." }
+//	    }
+//	  }
+//	  method = {
+//	    aes_gcm = {
+//	      default = { keys = "pbkdf2.default" }
+//	    }
+//	    unencrypted = {
+//	      migration = {}
+//	    }
+//	  }
+//	  state = {
+//	    method   = "aes_gcm.default"
+//	    fallback = { method = "unencrypted.migration" }
+//	  }
+//	  plan = { method = "aes_gcm.default" }
+//	}
+//
+// The `keys` and `chain` attributes reference a key provider as <type>.<name>, and the `method` attributes reference a
+// method the same way.
+func ValidateEncryptionConfig(encryption map[string]interface{}) error {
+	for key := range encryption {
+		switch key {
+		case EncryptionKeyProvider, EncryptionMethod, EncryptionState, EncryptionPlan, EncryptionRemoteStateDataSources:
+		default:
+			return errors.WithStackTrace(InvalidEncryptionConfig{Name: key, Reason: "is not a section of the encryption config"})
+		}
+	}
+
+	keyProviders, err := encryptionComponents(encryption, EncryptionKeyProvider)
+	if err != nil {
+		return err
+	}
+
+	methods, err := encryptionComponents(encryption, EncryptionMethod)
+	if err != nil {
+		return err
+	}
+
+	for _, keyProvider := range keyProviders {
+		if err := validateEncryptionReference(keyProvider.attributes, encryptionChain, EncryptionKeyProvider, keyProviders, keyProvider.String()); err != nil {
+			return err
+		}
+	}
+
+	for _, method := range methods {
+		if err := validateEncryptionReference(method.attributes, encryptionKeys, EncryptionKeyProvider, keyProviders, method.String()); err != nil {
+			return err
+		}
+	}
+
+	for _, target := range []string{EncryptionState, EncryptionPlan} {
+		if value, ok := encryption[target]; ok {
+			if err := validateEncryptionTarget(value, target, methods); err != nil {
+				return err
+			}
+		}
+	}
+
+	if value, ok := encryption[EncryptionRemoteStateDataSources]; ok {
+		dataSources, err := toEncryptionMap(value, EncryptionRemoteStateDataSources)
+		if err != nil {
+			return err
+		}
+
+		for key, value := range dataSources {
+			switch key {
+			case encryptionDefault:
+				if err := validateEncryptionTarget(value, EncryptionRemoteStateDataSources+"."+key, methods); err != nil {
+					return err
+				}
+			case encryptionRemoteStateDataSource:
+				named, err := toEncryptionMap(value, EncryptionRemoteStateDataSources+"."+key)
+				if err != nil {
+					return err
+				}
+				for name, target := range named {
+					if err := validateEncryptionTarget(target, EncryptionRemoteStateDataSources+"."+key+"."+name, methods); err != nil {
+						return err
+					}
+				}
+			default:
+				return errors.WithStackTrace(InvalidEncryptionConfig{Name: EncryptionRemoteStateDataSources + "." + key, Reason: fmt.Sprintf("must be %s or %s", encryptionDefault, encryptionRemoteStateDataSource)})
+			}
+		}
+	}
+
+	return nil
+}
+
+// appendEncryptionBlock appends the `encryption` block of the given encryption config to the given terraform block.
+func appendEncryptionBlock(terraformBody *hclwrite.Body, encryption map[string]interface{}) error {
+	if err := ValidateEncryptionConfig(encryption); err != nil {
+		return err
+	}
+
+	encryptionBody := terraformBody.AppendNewBlock("encryption", nil).Body()
+
+	for _, section := range []string{EncryptionKeyProvider, EncryptionMethod} {
+		// The config was validated above, so the components can be read without checking the errors again.
+		components, _ := encryptionComponents(encryption, section)
+		for _, component := range components {
+			body := encryptionBody.AppendNewBlock(section, []string{component.componentType, component.name}).Body()
+			if err := writeEncryptionAttributes(body, component.attributes); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, target := range []string{EncryptionState, EncryptionPlan} {
+		if value, ok := encryption[target]; ok {
+			if err := writeEncryptionTarget(encryptionBody, target, nil, encryptionMap(value)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if value, ok := encryption[EncryptionRemoteStateDataSources]; ok {
+		dataSources := encryptionMap(value)
+		dataSourcesBody := encryptionBody.AppendNewBlock(EncryptionRemoteStateDataSources, nil).Body()
+
+		if target, ok := dataSources[encryptionDefault]; ok {
+			if err := writeEncryptionTarget(dataSourcesBody, encryptionDefault, nil, encryptionMap(target)); err != nil {
+				return err
+			}
+		}
+
+		if value, ok := dataSources[encryptionRemoteStateDataSource]; ok {
+			named := encryptionMap(value)
+			for _, name := range sortedKeys(named) {
+				if err := writeEncryptionTarget(dataSourcesBody, encryptionRemoteStateDataSource, []string{name}, encryptionMap(named[name])); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// writeEncryptionTarget appends a block of the given type, such as `state` or `fallback`, that selects the method to
+// encrypt something with.
+func writeEncryptionTarget(body *hclwrite.Body, blockType string, labels []string, target map[string]interface{}) error {
+	targetBody := body.AppendNewBlock(blockType, labels).Body()
+
+	attributes := map[string]interface{}{}
+	for key, value := range target {
+		if key != encryptionFallback {
+			attributes[key] = value
+		}
+	}
+	if err := writeEncryptionAttributes(targetBody, attributes); err != nil {
+		return err
+	}
+
+	if fallback, ok := target[encryptionFallback]; ok {
+		return writeEncryptionTarget(targetBody, encryptionFallback, nil, encryptionMap(fallback))
+	}
+
+	return nil
+}
+
+// writeEncryptionAttributes sets the given attributes on the given body in a consistent order. The attributes that
+// reference a key provider or a method are written as references instead of strings.
+func writeEncryptionAttributes(body *hclwrite.Body, attributes map[string]interface{}) error {
+	for _, key := range sortedKeys(attributes) {
+		value := attributes[key]
+
+		if section, isReference := encryptionReferenceAttributes[key]; isReference {
+			componentType, name, err := parseEncryptionReference(value, key)
+			if err != nil {
+				return err
+			}
+			body.SetAttributeTraversal(key, hcl.Traversal{
+				hcl.TraverseRoot{Name: section},
+				hcl.TraverseAttr{Name: componentType},
+				hcl.TraverseAttr{Name: name},
+			})
+			continue
+		}
+
+		ctyValue, err := toCtyValue(value)
+		if err != nil {
+			return err
+		}
+		body.SetAttributeValue(key, ctyValue)
+	}
+
+	return nil
+}
+
+// The attributes that reference other components of the encryption config, mapped to the section they reference.
+var encryptionReferenceAttributes = map[string]string{
+	encryptionChain:  EncryptionKeyProvider,
+	encryptionKeys:   EncryptionKeyProvider,
+	EncryptionMethod: EncryptionMethod,
+}
+
+// encryptionComponent is a key provider or a method of the encryption config.
+type encryptionComponent struct {
+	componentType string
+	name          string
+	attributes    map[string]interface{}
+}
+
+func (component encryptionComponent) String() string {
+	return component.componentType + "." + component.name
+}
+
+// encryptionComponents returns the key providers or the methods of the given encryption config, sorted by type and
+// name, which are configured as <type> = { <name> = { <attributes> } }.
+func encryptionComponents(encryption map[string]interface{}, section string) ([]encryptionComponent, error) {
+	value, ok := encryption[section]
+	if !ok {
+		return nil, nil
+	}
+
+	types, err := toEncryptionMap(value, section)
+	if err != nil {
+		return nil, err
+	}
+
+	components := []encryptionComponent{}
+	for _, componentType := range sortedKeys(types) {
+		names, err := toEncryptionMap(types[componentType], section+"."+componentType)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, name := range sortedKeys(names) {
+			attributes, err := toEncryptionMap(names[name], section+"."+componentType+"."+name)
+			if err != nil {
+				return nil, err
+			}
+			components = append(components, encryptionComponent{componentType: componentType, name: name, attributes: attributes})
+		}
+	}
+
+	return components, nil
+}
+
+// validateEncryptionTarget validates a block that selects a method, such as `state`, including its fallback.
+func validateEncryptionTarget(value interface{}, name string, methods []encryptionComponent) error {
+	target, err := toEncryptionMap(value, name)
+	if err != nil {
+		return err
+	}
+
+	for key, value := range target {
+		switch key {
+		case EncryptionMethod:
+		case encryptionEnforced:
+			if _, ok := value.(bool); !ok {
+				return errors.WithStackTrace(InvalidEncryptionConfig{Name: name + "." + key, Reason: "must be a bool"})
+			}
+		case encryptionFallback:
+			if err := validateEncryptionTarget(value, name+"."+key, methods); err != nil {
+				return err
+			}
+		default:
+			return errors.WithStackTrace(InvalidEncryptionConfig{Name: name + "." + key, Reason: fmt.Sprintf("must be %s, %s or %s", EncryptionMethod, encryptionEnforced, encryptionFallback)})
+		}
+	}
+
+	if _, ok := target[EncryptionMethod]; !ok {
+		return errors.WithStackTrace(InvalidEncryptionConfig{Name: name, Reason: fmt.Sprintf("must set the %s", EncryptionMethod)})
+	}
+
+	return validateEncryptionReference(target, EncryptionMethod, EncryptionMethod, methods, name)
+}
+
+// validateEncryptionReference validates that the given attribute, if set, references one of the given components.
+func validateEncryptionReference(attributes map[string]interface{}, attribute string, section string, components []encryptionComponent, name string) error {
+	value, ok := attributes[attribute]
+	if !ok {
+		return nil
+	}
+
+	componentType, componentName, err := parseEncryptionReference(value, name+"."+attribute)
+	if err != nil {
+		return err
+	}
+
+	for _, component := range components {
+		if component.componentType == componentType && component.name == componentName {
+			return nil
+		}
+	}
+
+	return errors.WithStackTrace(InvalidEncryptionConfig{Name: name + "." + attribute, Reason: fmt.Sprintf("references the %s %s.%s, which is not configured", section, componentType, componentName)})
+}
+
+// parseEncryptionReference parses a reference to a component as <type>.<name>. The section of the component may be
+// set as a prefix, e.g. key_provider.pbkdf2.default.
+func parseEncryptionReference(value interface{}, name string) (string, string, error) {
+	reference, ok := value.(string)
+	if !ok {
+		return "", "", errors.WithStackTrace(InvalidEncryptionConfig{Name: name, Reason: "must be a reference as <type>.<name>"})
+	}
+
+	parts := strings.Split(reference, ".")
+	if len(parts) == 3 && (parts[0] == EncryptionKeyProvider || parts[0] == EncryptionMethod) {
+		parts = parts[1:]
+	}
+
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", "", errors.WithStackTrace(InvalidEncryptionConfig{Name: name, Reason: fmt.Sprintf("must be a reference as <type>.<name>, got %q", reference)})
+	}
+
+	return parts[0], parts[1], nil
+}
+
+func toEncryptionMap(value interface{}, name string) (map[string]interface{}, error) {
+	if value == nil {
+		return map[string]interface{}{}, nil
+	}
+
+	valueMap, ok := value.(map[string]interface{})
+	if !ok {
+		return nil, errors.WithStackTrace(InvalidEncryptionConfig{Name: name, Reason: "must be a map"})
+	}
+
+	return valueMap, nil
+}
+
+// encryptionMap returns the given value of a validated encryption config as a map.
+func encryptionMap(value interface{}) map[string]interface{} {
+	valueMap, _ := toEncryptionMap(value, "")
+	return valueMap
+}
+
+func sortedKeys(valueMap map[string]interface{}) []string {
+	keys := make([]string, 0, len(valueMap))
+	for key := range valueMap {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// Custom error types
+
+type InvalidEncryptionConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidEncryptionConfig) Error() string {
+	return fmt.Sprintf("Invalid remote_state encryption config %s: %s", err.Name, err.Reason)
+}
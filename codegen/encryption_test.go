@@ -0,0 +1,138 @@
+package codegen
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteStateConfigToTerraformCodeWithEncryption(t *testing.T) {
+	t.Parallel()
+
+	encryption := map[string]interface{}{
+		"key_provider": map[string]interface{}{
+			"pbkdf2": map[string]interface{}{
+				"default": map[string]interface{}{"passphrase": "correct-horse-battery-staple"},
+			},
+		},
+		"method": map[string]interface{}{
+			"aes_gcm": map[string]interface{}{
+				"default": map[string]interface{}{"keys": "pbkdf2.default"},
+			},
+			"unencrypted": map[string]interface{}{
+				"migration": map[string]interface{}{},
+			},
+		},
+		"state": map[string]interface{}{
+			"method":   "aes_gcm.default",
+			"enforced": false,
+			"fallback": map[string]interface{}{"method": "method.unencrypted.migration"},
+		},
+		"remote_state_data_sources": map[string]interface{}{
+			"default": map[string]interface{}{"method": "aes_gcm.default"},
+		},
+	}
+
+	expected := `terraform {
+  backend "s3" {
+    bucket = "my-state"
+  }
+  encryption {
+    key_provider "pbkdf2" "default" {
+      passphrase = "correct-horse-battery-staple"
+    }
+    method "aes_gcm" "default" {
+      keys = key_provider.pbkdf2.default
+    }
+    method "unencrypted" "migration" {
+    }
+    state {
+      enforced = false
+      method   = method.aes_gcm.default
+      fallback {
+        method = method.unencrypted.migration
+      }
+    }
+    remote_state_data_sources {
+      default {
+        method = method.aes_gcm.default
+      }
+    }
+  }
+}
+`
+
+	output, err := RemoteStateConfigToTerraformCode("s3", map[string]interface{}{"bucket": "my-state"}, encryption)
+	require.NoError(t, err)
+	assert.Equal(t, expected, string(output))
+}
+
+func TestValidateEncryptionConfig(t *testing.T) {
+	t.Parallel()
+
+	keyProviders := map[string]interface{}{
+		"pbkdf2": map[string]interface{}{
+			"default": map[string]interface{}{"passphrase": "correct-horse-battery-staple"},
+		},
+	}
+	methods := map[string]interface{}{
+		"aes_gcm": map[string]interface{}{
+			"default": map[string]interface{}{"keys": "pbkdf2.default"},
+		},
+	}
+
+	testCases := []struct {
+		name       string
+		encryption map[string]interface{}
+		expected   error
+	}{
+		{
+			"valid",
+			map[string]interface{}{"key_provider": keyProviders, "method": methods, "plan": map[string]interface{}{"method": "aes_gcm.default"}},
+			nil,
+		},
+		{
+			"unknown-section",
+			map[string]interface{}{"keys": keyProviders},
+			InvalidEncryptionConfig{Name: "keys", Reason: "is not a section of the encryption config"},
+		},
+		{
+			"unknown-key-provider",
+			map[string]interface{}{"method": methods},
+			InvalidEncryptionConfig{Name: "aes_gcm.default.keys", Reason: "references the key_provider pbkdf2.default, which is not configured"},
+		},
+		{
+			"unknown-fallback-method",
+			map[string]interface{}{"key_provider": keyProviders, "method": methods, "state": map[string]interface{}{"method": "aes_gcm.default", "fallback": map[string]interface{}{"method": "unencrypted.migration"}}},
+			InvalidEncryptionConfig{Name: "state.fallback.method", Reason: "references the method unencrypted.migration, which is not configured"},
+		},
+		{
+			"missing-method",
+			map[string]interface{}{"key_provider": keyProviders, "method": methods, "state": map[string]interface{}{"enforced": true}},
+			InvalidEncryptionConfig{Name: "state", Reason: "must set the method"},
+		},
+		{
+			"invalid-reference",
+			map[string]interface{}{"key_provider": keyProviders, "method": methods, "state": map[string]interface{}{"method": "aes_gcm"}},
+			InvalidEncryptionConfig{Name: "state.method", Reason: `must be a reference as <type>.<name>, got "aes_gcm"`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := ValidateEncryptionConfig(testCase.encryption)
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, testCase.expected, errors.Unwrap(err))
+			}
+		})
+	}
+}
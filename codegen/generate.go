@@ -6,10 +6,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"sort"
 	"strings"
 
+	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
 	ctyjson "github.com/zclconf/go-cty/cty/json"
 
 	"github.com/gruntwork-io/go-commons/errors"
@@ -24,8 +25,14 @@ const (
 
 	// The default prefix to use for comments in the generated file
 	DefaultCommentPrefix = "# "
+
+	// The Terraform Cloud backend, which is configured with a cloud block rather than a backend block
+	BackendCloud = "cloud"
 )
 
+// The backends whose workspaces are configured with a nested block rather than an attribute.
+var backendsWithWorkspacesBlock = []string{"remote", BackendCloud}
+
 // An enum to represent valid values for if_exists
 type GenerateConfigExists int
 
@@ -53,6 +60,10 @@ type GenerateConfig struct {
 	Contents         string `cty:"contents"`
 	DisableSignature bool   `cty:"disable_signature"`
 	Disable          bool   `cty:"disable"`
+
+	// The context to render Contents in as an HCL template, which is only set for the generate blocks with
+	// `template = true` until their template is rendered with the resolved inputs of the config.
+	TemplateContext *hcl.EvalContext
 }
 
 // WriteToFile will generate a new file at the given target path with the given contents. If a file already exists at
@@ -150,35 +161,67 @@ func fileWasGeneratedByTerragrunt(path string) (bool, error) {
 	return strings.HasSuffix(strings.TrimSpace(firstLine), TerragruntGeneratedSignature), nil
 }
 
-// Convert the arbitrary map that represents a remote state config into HCL code to configure that remote state.
-func RemoteStateConfigToTerraformCode(backend string, config map[string]interface{}) ([]byte, error) {
+// Convert the arbitrary map that represents a remote state config into HCL code to configure that remote state. If the
+// encryption config is set, the OpenTofu state encryption is configured in the same terraform block. The cloud backend
+// is configured with a cloud block rather than a backend block.
+func RemoteStateConfigToTerraformCode(backend string, config map[string]interface{}, encryption map[string]interface{}) ([]byte, error) {
 	f := hclwrite.NewEmptyFile()
-	backendBlock := f.Body().AppendNewBlock("terraform", nil).Body().AppendNewBlock("backend", []string{backend})
+	terraformBlockBody := f.Body().AppendNewBlock("terraform", nil).Body()
+	var backendBlock *hclwrite.Block
+	if backend == BackendCloud {
+		backendBlock = terraformBlockBody.AppendNewBlock(BackendCloud, nil)
+	} else {
+		backendBlock = terraformBlockBody.AppendNewBlock("backend", []string{backend})
+	}
 	backendBlockBody := backendBlock.Body()
-	var backendKeys []string
 
-	for key := range config {
-		backendKeys = append(backendKeys, key)
-	}
-	sort.Strings(backendKeys)
-	for _, key := range backendKeys {
-		// Since we don't have the cty type information for the config and since config can be arbitrary, we cheat by using
-		// json as an intermediate representation.
-		jsonBytes, err := json.Marshal(config[key])
-		if err != nil {
-			return nil, errors.WithStackTrace(err)
+	for _, key := range sortedKeys(config) {
+		// The workspaces of the remote and cloud backends are configured with a nested block.
+		if workspaces, isMap := config[key].(map[string]interface{}); isMap && key == "workspaces" && util.ListContainsElement(backendsWithWorkspacesBlock, backend) {
+			workspacesBlockBody := backendBlockBody.AppendNewBlock(key, nil).Body()
+			for _, workspacesKey := range sortedKeys(workspaces) {
+				ctyVal, err := toCtyValue(workspaces[workspacesKey])
+				if err != nil {
+					return nil, err
+				}
+
+				workspacesBlockBody.SetAttributeValue(workspacesKey, ctyVal)
+			}
+			continue
 		}
-		var ctyVal ctyjson.SimpleJSONValue
-		if err := ctyVal.UnmarshalJSON(jsonBytes); err != nil {
-			return nil, errors.WithStackTrace(err)
+
+		ctyVal, err := toCtyValue(config[key])
+		if err != nil {
+			return nil, err
 		}
 
-		backendBlockBody.SetAttributeValue(key, ctyVal.Value)
+		backendBlockBody.SetAttributeValue(key, ctyVal)
+	}
+
+	if len(encryption) > 0 {
+		if err := appendEncryptionBlock(terraformBlockBody, encryption); err != nil {
+			return nil, err
+		}
 	}
 
 	return f.Bytes(), nil
 }
 
+// Since we don't have the cty type information for the config and since config can be arbitrary, we cheat by using
+// json as an intermediate representation.
+func toCtyValue(value interface{}) (cty.Value, error) {
+	jsonBytes, err := json.Marshal(value)
+	if err != nil {
+		return cty.NilVal, errors.WithStackTrace(err)
+	}
+	var ctyVal ctyjson.SimpleJSONValue
+	if err := ctyVal.UnmarshalJSON(jsonBytes); err != nil {
+		return cty.NilVal, errors.WithStackTrace(err)
+	}
+
+	return ctyVal.Value, nil
+}
+
 // GenerateConfigExistsFromString converts a string representation of if_exists into the enum, returning an error if it
 // is not set to one of the known values.
 func GenerateConfigExistsFromString(val string) (GenerateConfigExists, error) {
@@ -23,6 +23,27 @@ func TestRemoteStateConfigToTerraformCode(t *testing.T) {
   backend "empty" {
   }
 }
+`)
+
+	expectedRemote := []byte(`terraform {
+  backend "remote" {
+    hostname     = "app.terraform.io"
+    organization = "my-org"
+    workspaces {
+      name = "vpc"
+    }
+  }
+}
+`)
+	expectedCloud := []byte(`terraform {
+  cloud {
+    organization = "my-org"
+    workspaces {
+      name    = "vpc"
+      project = "networking"
+    }
+  }
+}
 `)
 
 	testCases := []struct {
@@ -47,11 +68,30 @@ func TestRemoteStateConfigToTerraformCode(t *testing.T) {
 			map[string]interface{}{},
 			expectedEmpty,
 		},
+		{
+			"remote-state-config-remote-workspaces-block",
+			"remote",
+			map[string]interface{}{
+				"hostname":     "app.terraform.io",
+				"organization": "my-org",
+				"workspaces":   map[string]interface{}{"name": "vpc"},
+			},
+			expectedRemote,
+		},
+		{
+			"remote-state-config-cloud-block",
+			"cloud",
+			map[string]interface{}{
+				"organization": "my-org",
+				"workspaces":   map[string]interface{}{"project": "networking", "name": "vpc"},
+			},
+			expectedCloud,
+		},
 	}
 
 	for _, testCase := range testCases {
 		t.Run(testCase.name, func(t *testing.T) {
-			output, err := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config)
+			output, err := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config, nil)
 			// validates the first output.
 			require.True(t, bytes.Contains(output, []byte(testCase.backend)))
 			require.Equal(t, testCase.expected, output)
@@ -60,7 +100,7 @@ func TestRemoteStateConfigToTerraformCode(t *testing.T) {
 			// runs the function a few of times again. All the outputs must be
 			// equal to the first output.
 			for i := 0; i < 20; i++ {
-				actual, _ := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config)
+				actual, _ := RemoteStateConfigToTerraformCode(testCase.backend, testCase.config, nil)
 				require.Equal(t, output, actual)
 			}
 		})
@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/gruntwork-io/terragrunt/options"
 )
@@ -41,6 +42,47 @@ func (cache *StringCache) Put(key string, value string) {
 	cache.Cache[cacheKey] = value
 }
 
+// ExpiringStringCache - structure to store cached values along with the time they were cached, so that they can expire
+type ExpiringStringCache struct {
+	Cache map[string]expiringString
+	Mutex *sync.Mutex
+}
+
+type expiringString struct {
+	value    string
+	cachedAt time.Time
+}
+
+// NewExpiringStringCache - create new expiring string cache
+func NewExpiringStringCache() *ExpiringStringCache {
+	return &ExpiringStringCache{
+		Cache: map[string]expiringString{},
+		Mutex: &sync.Mutex{},
+	}
+}
+
+// Get - get cached value if it was cached less than ttl ago, or regardless of when it was cached if ttl is zero
+func (cache *ExpiringStringCache) Get(key string, ttl time.Duration) (string, bool) {
+	cache.Mutex.Lock()
+	defer cache.Mutex.Unlock()
+	keyHash := sha256.Sum256([]byte(key))
+	cacheKey := fmt.Sprintf("%x", keyHash)
+	entry, found := cache.Cache[cacheKey]
+	if !found || (ttl > 0 && time.Since(entry.cachedAt) >= ttl) {
+		return "", false
+	}
+	return entry.value, true
+}
+
+// Put - put value in cache along with the current time
+func (cache *ExpiringStringCache) Put(key string, value string) {
+	cache.Mutex.Lock()
+	defer cache.Mutex.Unlock()
+	keyHash := sha256.Sum256([]byte(key))
+	cacheKey := fmt.Sprintf("%x", keyHash)
+	cache.Cache[cacheKey] = expiringString{value: value, cachedAt: time.Now()}
+}
+
 // IAMRoleOptionsCache - cache for IAMRole options
 type IAMRoleOptionsCache struct {
 	Cache map[string]options.IAMRoleOptions
@@ -7,8 +7,11 @@ import (
 	"path"
 	"path/filepath"
 	"reflect"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/zclconf/go-cty/cty/convert"
 	"github.com/zclconf/go-cty/cty/gocty"
 
 	"github.com/hashicorp/go-getter"
@@ -30,6 +33,7 @@ import (
 const (
 	DefaultTerragruntConfigPath     = "terragrunt.hcl"
 	DefaultTerragruntJsonConfigPath = "terragrunt.hcl.json"
+	DefaultTerragruntYamlConfigPath = "terragrunt.hcl.yaml"
 )
 
 const FoundInFile = "found_in_file"
@@ -48,18 +52,30 @@ const (
 	MetadataIamRole                     = "iam_role"
 	MetadataIamAssumeRoleDuration       = "iam_assume_role_duration"
 	MetadataIamAssumeRoleSessionName    = "iam_assume_role_session_name"
+	MetadataIamMfaSerial                = "iam_mfa_serial"
+	MetadataIamWebIdentityToken         = "iam_web_identity_token"
 	MetadataInputs                      = "inputs"
 	MetadataLocals                      = "locals"
 	MetadataGenerateConfigs             = "generate"
 	MetadataRetryableErrors             = "retryable_errors"
 	MetadataRetryMaxAttempts            = "retry_max_attempts"
 	MetadataRetrySleepIntervalSec       = "retry_sleep_interval_sec"
+	MetadataRetry                       = "retry"
 	MetadataDependentModules            = "dependent_modules"
+	MetadataPriority                    = "priority"
+	MetadataTags                        = "tags"
+	MetadataExclude                     = "exclude"
+	MetadataStrictMockOutputs           = "strict_mock_outputs"
+	MetadataHookSet                     = "hook_set"
+	MetadataUseHooks                    = "use_hooks"
+	MetadataSkipPropagatedHooks         = "skip_propagated_hooks"
+	MetadataCustomCommands              = "commands"
 )
 
 // Order matters, for example if none of the files are found `GetDefaultConfigPath` func returns the last element.
 var DefaultTerragruntConfigPaths = []string{
 	DefaultTerragruntJsonConfigPath,
+	DefaultTerragruntYamlConfigPath,
 	DefaultTerragruntConfigPath,
 }
 
@@ -76,8 +92,11 @@ type TerragruntConfig struct {
 	PreventDestroy              *bool
 	Skip                        bool
 	IamRole                     string
+	IamRoles                    map[string]string
 	IamAssumeRoleDuration       *int64
 	IamAssumeRoleSessionName    string
+	IamMfaSerial                string
+	IamWebIdentityToken         string
 	Inputs                      map[string]interface{}
 	Locals                      map[string]interface{}
 	TerragruntDependencies      []Dependency
@@ -85,6 +104,16 @@ type TerragruntConfig struct {
 	RetryableErrors             []string
 	RetryMaxAttempts            *int
 	RetrySleepIntervalSec       *int
+	RetryConfigs                []RetryConfig
+	Priority                    *int
+	Tags                        []string
+	Validations                 []ValidationConfig
+	Exclude                     *ExcludeConfig
+	StrictMockOutputs           *bool
+	HookSets                    []HookSet
+	UseHooks                    []string
+	SkipPropagatedHooks         []string
+	CustomCommands              []CustomCommand
 
 	// Fields used for internal tracking
 	// Indicates whether or not this is the result of a partial evaluation
@@ -93,6 +122,9 @@ type TerragruntConfig struct {
 	// Map of processed includes
 	ProcessedIncludes IncludeConfigs
 
+	// Names of the feature flags declared by the config, the configs it includes and the configs it imports
+	DeclaredFeatures []string
+
 	// Map to store fields metadata
 	FieldsMetadata map[string]map[string]interface{}
 
@@ -110,6 +142,8 @@ func (conf *TerragruntConfig) GetIAMRoleOptions() options.IAMRoleOptions {
 	configIAMRoleOptions := options.IAMRoleOptions{
 		RoleARN:               conf.IamRole,
 		AssumeRoleSessionName: conf.IamAssumeRoleSessionName,
+		MFASerial:             conf.IamMfaSerial,
+		WebIdentityToken:      conf.IamWebIdentityToken,
 	}
 	if conf.IamAssumeRoleDuration != nil {
 		configIAMRoleOptions.AssumeRoleDuration = *conf.IamAssumeRoleDuration
@@ -117,6 +151,46 @@ func (conf *TerragruntConfig) GetIAMRoleOptions() options.IAMRoleOptions {
 	return configIAMRoleOptions
 }
 
+// The key of the role of the terraform commands that have no role of their own, when iam_role is a map of the terraform
+// commands to their role.
+const IamRoleDefaultCommand = "default"
+
+// decodeIamRole decodes the given iam_role attribute, which is either the ARN of the role to assume for all the
+// terraform commands, or a map of the terraform commands to the ARN of their role, e.g. so that a read-only role is used
+// for plan. It returns the role of the terraform command of the given options, which is the role of the default key for
+// the commands not in the map, and the map if the attribute is one.
+func decodeIamRole(value cty.Value, terragruntOptions *options.TerragruntOptions) (string, map[string]string, error) {
+	if value.Type() == cty.String {
+		var role string
+		if err := gocty.FromCtyValue(value, &role); err != nil {
+			return "", nil, errors.WithStackTrace(InvalidIamRole(err.Error()))
+		}
+		return role, nil, nil
+	}
+
+	if !value.Type().IsObjectType() && !value.Type().IsMapType() {
+		return "", nil, errors.WithStackTrace(InvalidIamRole("expected a string or a map of the terraform commands to the ARN of their role"))
+	}
+	rolesValue, err := convert.Convert(value, cty.Map(cty.String))
+	if err != nil {
+		return "", nil, errors.WithStackTrace(InvalidIamRole(err.Error()))
+	}
+	roles := map[string]string{}
+	if err := gocty.FromCtyValue(rolesValue, &roles); err != nil {
+		return "", nil, errors.WithStackTrace(InvalidIamRole(err.Error()))
+	}
+	return iamRoleForCommand(roles, terragruntOptions.TerraformCommand), roles, nil
+}
+
+// iamRoleForCommand returns the role of the given terraform command in the given map of the terraform commands to their
+// role, or the role of the default key if the command has no role of its own.
+func iamRoleForCommand(roles map[string]string, command string) string {
+	if role, found := roles[command]; found {
+		return role
+	}
+	return roles[IamRoleDefaultCommand]
+}
+
 // terragruntConfigFile represents the configuration supported in a Terragrunt configuration file (i.e.
 // terragrunt.hcl)
 type terragruntConfigFile struct {
@@ -146,9 +220,11 @@ type terragruntConfigFile struct {
 	DownloadDir              *string             `hcl:"download_dir,attr"`
 	PreventDestroy           *bool               `hcl:"prevent_destroy,attr"`
 	Skip                     *bool               `hcl:"skip,attr"`
-	IamRole                  *string             `hcl:"iam_role,attr"`
+	IamRole                  *cty.Value          `hcl:"iam_role,attr"`
 	IamAssumeRoleDuration    *int64              `hcl:"iam_assume_role_duration,attr"`
 	IamAssumeRoleSessionName *string             `hcl:"iam_assume_role_session_name,attr"`
+	IamMfaSerial             *string             `hcl:"iam_mfa_serial,attr"`
+	IamWebIdentityToken      *string             `hcl:"iam_web_identity_token,attr"`
 	TerragruntDependencies   []Dependency        `hcl:"dependency,block"`
 
 	// We allow users to configure code generation via blocks:
@@ -173,13 +249,72 @@ type terragruntConfigFile struct {
 	RetryMaxAttempts      *int     `hcl:"retry_max_attempts,optional"`
 	RetrySleepIntervalSec *int     `hcl:"retry_sleep_interval_sec,optional"`
 
+	// Fine grained retry policies:
+	//
+	// retry "rate_limits" {
+	//   retryable_errors   = [".*429 Too Many Requests.*"]
+	//   max_attempts       = 5
+	//   backoff_multiplier = 2
+	// }
+	RetryConfigs []RetryConfig `hcl:"retry,block"`
+
+	// The priority of the module in the run-all scheduler: within the modules that are ready to run, the ones with a
+	// higher priority are started first.
+	Priority *int `hcl:"priority,attr"`
+
+	// Labels of the module, which the list command can filter the modules of a stack on.
+	Tags []string `hcl:"tags,optional"`
+
+	// Whether running apply or destroy fails when the mock outputs of a dependency would be used, rather than running
+	// with placeholder data. Also enabled with --terragrunt-strict-mock-outputs.
+	StrictMockOutputs *bool `hcl:"strict_mock_outputs,attr"`
+
+	// Assertions on the resolved configuration:
+	//
+	// validation {
+	//   condition     = length(inputs.name) <= 32
+	//   error_message = "The name must be at most 32 characters long."
+	// }
+	Validations []terragruntValidationBlock `hcl:"validation,block"`
+
+	// Skips the module for the listed actions when the condition is true:
+	//
+	// exclude {
+	//   if      = feature.legacy.value
+	//   actions = ["plan", "apply"]
+	// }
+	Exclude *ExcludeConfig `hcl:"exclude,block"`
+
+	// Named sets of hooks, attached to the terraform block with use_hooks:
+	//
+	// hook_set "notify" {
+	//   on_failure_hook "slack" { ... }
+	// }
+	//
+	// use_hooks = ["notify"]
+	HookSets []HookSet `hcl:"hook_set,block"`
+	UseHooks []string  `hcl:"use_hooks,optional"`
+
+	// The names of the hooks with propagate of the included configs that don't apply to this unit.
+	SkipPropagatedHooks []string `hcl:"skip_propagated_hooks,optional"`
+
+	// Named sequences of steps run with `terragrunt <name>`:
+	//
+	// commands {
+	//   deploy = ["init", "plan", "apply"]
+	// }
+	Commands *terragruntCommands `hcl:"commands,block"`
+
 	// This struct is used for validating and parsing the entire terragrunt config. Since locals and include are
 	// evaluated in a completely separate cycle, it should not be evaluated here. Otherwise, we can't support self
 	// referencing other elements in the same block.
 	// We don't want to use the special Remain keyword here, as that would cause the checker to support parsing config
 	// that have extraneous, unsupported blocks and attributes.
-	Locals  *terragruntLocal          `hcl:"locals,block"`
-	Include []terragruntIncludeIgnore `hcl:"include,block"`
+	Locals    *terragruntLocal           `hcl:"locals,block"`
+	Include   []terragruntIncludeIgnore  `hcl:"include,block"`
+	Features  []terragruntFeatureIgnore  `hcl:"feature,block"`
+	Variables []terragruntVariableIgnore `hcl:"variable,block"`
+	Imports   []terragruntImportIgnore   `hcl:"import,block"`
 }
 
 // We use a struct designed to not parse the block, as locals and includes are parsed and decoded using a special
@@ -191,6 +326,18 @@ type terragruntIncludeIgnore struct {
 	Name   string   `hcl:"name,label"`
 	Remain hcl.Body `hcl:",remain"`
 }
+type terragruntFeatureIgnore struct {
+	Name   string   `hcl:"name,label"`
+	Remain hcl.Body `hcl:",remain"`
+}
+type terragruntVariableIgnore struct {
+	Name   string   `hcl:"name,label"`
+	Remain hcl.Body `hcl:",remain"`
+}
+type terragruntImportIgnore struct {
+	Name   string   `hcl:"name,label"`
+	Remain hcl.Body `hcl:",remain"`
+}
 
 // Configuration for Terraform remote state as parsed from a terragrunt.hcl config file
 type remoteStateConfigFile struct {
@@ -199,6 +346,13 @@ type remoteStateConfigFile struct {
 	DisableDependencyOptimization *bool                      `hcl:"disable_dependency_optimization,attr"`
 	Generate                      *remoteStateConfigGenerate `hcl:"generate,attr"`
 	Config                        cty.Value                  `hcl:"config,attr"`
+	Encryption                    *cty.Value                 `hcl:"encryption,attr"`
+	Snapshot                      *cty.Value                 `hcl:"snapshot,attr"`
+	// The role to access the backend with, which is set in the backend config
+	AssumeRole *cty.Value `hcl:"assume_role,attr"`
+	// The environment whose settings in Environments override the ones of Config
+	Environment  *string    `hcl:"environment,attr"`
+	Environments *cty.Value `hcl:"environments,attr"`
 }
 
 func (remoteState *remoteStateConfigFile) String() string {
@@ -213,6 +367,32 @@ func (remoteState *remoteStateConfigFile) toConfig() (*remote.RemoteState, error
 		return nil, err
 	}
 
+	if remoteState.Environments != nil && !remoteState.Environments.IsNull() {
+		environmentConfig, err := remoteState.environmentConfig()
+		if err != nil {
+			return nil, err
+		}
+		for key, value := range environmentConfig {
+			remoteStateConfig[key] = value
+		}
+	}
+
+	// The role is set after the settings of the environment, which may select the account of the state.
+	if remoteState.AssumeRole != nil && !remoteState.AssumeRole.IsNull() {
+		assumeRoleConfig, err := parseCtyValueToMap(*remoteState.AssumeRole)
+		if err != nil {
+			return nil, err
+		}
+
+		var assumeRole remote.RemoteStateAssumeRole
+		if err := mapstructure.Decode(assumeRoleConfig, &assumeRole); err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		if err := assumeRole.ApplyToBackendConfig(remoteState.Backend, remoteStateConfig); err != nil {
+			return nil, err
+		}
+	}
+
 	config := &remote.RemoteState{}
 	config.Backend = remoteState.Backend
 	if remoteState.Generate != nil {
@@ -223,6 +403,27 @@ func (remoteState *remoteStateConfigFile) toConfig() (*remote.RemoteState, error
 	}
 	config.Config = remoteStateConfig
 
+	if remoteState.Encryption != nil && !remoteState.Encryption.IsNull() {
+		encryption, err := parseCtyValueToMap(*remoteState.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		config.Encryption = encryption
+	}
+
+	if remoteState.Snapshot != nil && !remoteState.Snapshot.IsNull() {
+		snapshotConfig, err := parseCtyValueToMap(*remoteState.Snapshot)
+		if err != nil {
+			return nil, err
+		}
+
+		var snapshot remote.RemoteStateSnapshot
+		if err := mapstructure.Decode(snapshotConfig, &snapshot); err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		config.Snapshot = &snapshot
+	}
+
 	if remoteState.DisableInit != nil {
 		config.DisableInit = *remoteState.DisableInit
 	}
@@ -237,6 +438,33 @@ func (remoteState *remoteStateConfigFile) toConfig() (*remote.RemoteState, error
 	return config, err
 }
 
+// environmentConfig returns the backend settings of the environment selected with the environment attribute, e.g.
+// the bucket and role of the account of the environment.
+func (remoteState *remoteStateConfigFile) environmentConfig() (map[string]interface{}, error) {
+	environments, err := parseCtyValueToMap(*remoteState.Environments)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := ""
+	if remoteState.Environment != nil {
+		environment = *remoteState.Environment
+	}
+
+	environmentConfig, isMap := environments[environment].(map[string]interface{})
+	if !isMap {
+		environmentNames := []string{}
+		for name := range environments {
+			environmentNames = append(environmentNames, name)
+		}
+		sort.Strings(environmentNames)
+
+		return nil, errors.WithStackTrace(InvalidRemoteStateEnvironment{Environment: environment, Environments: environmentNames})
+	}
+
+	return environmentConfig, nil
+}
+
 type remoteStateConfigGenerate struct {
 	// We use cty instead of hcl, since we are using this type to convert an attr and not a block.
 	Path     string `cty:"path"`
@@ -253,6 +481,7 @@ type terragruntGenerateBlock struct {
 	Contents         string  `hcl:"contents,attr" mapstructure:"contents"`
 	DisableSignature *bool   `hcl:"disable_signature,attr" mapstructure:"disable_signature"`
 	Disable          *bool   `hcl:"disable,attr" mapstructure:"disable"`
+	Template         *bool   `hcl:"template,attr" mapstructure:"template"`
 }
 
 type IncludeConfigs map[string]IncludeConfig
@@ -275,10 +504,22 @@ type IncludeConfig struct {
 	Path          string  `hcl:"path,attr"`
 	Expose        *bool   `hcl:"expose,attr"`
 	MergeStrategy *string `hcl:"merge_strategy,attr"`
+
+	// Merge strategies for specific attributes, overriding MergeStrategy for those attributes:
+	//
+	// merge_strategies = {
+	//   inputs           = "deep"
+	//   retryable_errors = "no_merge"
+	//   remote_state     = "deep"
+	// }
+	MergeStrategies map[string]string `hcl:"merge_strategies,optional"`
+
+	// The files importing the config, for the configs parsed for an import block, to detect import cycles.
+	importChain []string
 }
 
 func (cfg *IncludeConfig) String() string {
-	return fmt.Sprintf("IncludeConfig{Path = %s, Expose = %v, MergeStrategy = %v}", cfg.Path, cfg.Expose, cfg.MergeStrategy)
+	return fmt.Sprintf("IncludeConfig{Path = %s, Expose = %v, MergeStrategy = %v, MergeStrategies = %v}", cfg.Path, cfg.Expose, cfg.MergeStrategy, cfg.MergeStrategies)
 }
 
 func (cfg *IncludeConfig) GetExpose() bool {
@@ -308,6 +549,34 @@ func (cfg *IncludeConfig) GetMergeStrategy() (MergeStrategyType, error) {
 	}
 }
 
+// GetAttributeMergeStrategy returns the strategy to merge the given attribute with: the one set for the attribute in
+// merge_strategies if any, or the merge strategy of the include block otherwise.
+func (cfg *IncludeConfig) GetAttributeMergeStrategy(attribute string) (MergeStrategyType, error) {
+	strategy, hasStrategy := cfg.MergeStrategies[attribute]
+	if !hasStrategy {
+		return cfg.GetMergeStrategy()
+	}
+
+	if !util.ListContainsElement(AttributeMergeStrategyAttributes, attribute) {
+		return NoMerge, errors.WithStackTrace(UnsupportedAttributeMergeStrategy(attribute))
+	}
+	switch strategy {
+	case string(NoMerge):
+		return NoMerge, nil
+	case string(ShallowMerge):
+		return ShallowMerge, nil
+	case string(DeepMerge):
+		return DeepMerge, nil
+	default:
+		return NoMerge, errors.WithStackTrace(InvalidAttributeMergeStrategyType{Attribute: attribute, Strategy: strategy})
+	}
+}
+
+// AttributeMergeStrategyAttributes are the attributes whose merge strategy can be set in the merge_strategies attribute
+// of include blocks. The other attributes and blocks, such as the hooks of the terraform block, are always merged with
+// the merge strategy of the include block, and setting their strategy is an error.
+var AttributeMergeStrategyAttributes = []string{MetadataInputs, MetadataRetryableErrors, MetadataGenerateConfigs, MetadataRemoteState}
+
 type MergeStrategyType string
 
 const (
@@ -340,23 +609,226 @@ func (deps *ModuleDependencies) String() string {
 	return fmt.Sprintf("ModuleDependencies{Paths = %v}", deps.Paths)
 }
 
+// The values of the merge attribute of the hooks, which sets how a hook is merged with the hook of the same name of an
+// included config.
+const (
+	// HookMergeOverride replaces the hook of the included config with the hook. This is the default.
+	HookMergeOverride = "override"
+	// HookMergeAppend keeps the hook of the included config, and runs the hook right after it.
+	HookMergeAppend = "append"
+	// HookMergePrepend keeps the hook of the included config, and runs the hook right before it.
+	HookMergePrepend = "prepend"
+)
+
 // Hook specifies terraform commands (apply/plan) and array of os commands to execute
 type Hook struct {
 	Name           string   `hcl:"name,label" cty:"name"`
 	Commands       []string `hcl:"commands,attr" cty:"commands"`
-	Execute        []string `hcl:"execute,attr" cty:"execute"`
+	Execute        []string `hcl:"execute,optional" cty:"execute"`
 	RunOnError     *bool    `hcl:"run_on_error,attr" cty:"run_on_error"`
 	SuppressStdout *bool    `hcl:"suppress_stdout,attr" cty:"suppress_stdout"`
 	WorkingDir     *string  `hcl:"working_dir,attr" cty:"working_dir"`
+	Merge          *string  `hcl:"merge,attr" cty:"merge"`
+
+	// The environment variables added to the environment of the commands of the hook, such as the key of the state.
+	Env *map[string]string `hcl:"env,attr" cty:"env"`
+
+	// A background hook is started without waiting for it to finish, and joined at the end of the run of the module. It
+	// is killed if it runs for longer than the background timeout, a duration string such as "10m".
+	RunInBackground   *bool   `hcl:"run_in_background,attr" cty:"run_in_background"`
+	BackgroundTimeout *string `hcl:"background_timeout,attr" cty:"background_timeout"`
+
+	// An http hook POSTs a JSON payload describing the run of the module to the URL instead of running a command.
+	Type    *string            `hcl:"type,attr" cty:"type"`
+	URL     *string            `hcl:"url,attr" cty:"url"`
+	Headers *map[string]string `hcl:"headers,attr" cty:"headers"`
+	Payload *string            `hcl:"payload,attr" cty:"payload"`
+
+	// The slack and msteams hooks post the message, rendered from its template, to a chat webhook instead of a payload.
+	// As the URL of a webhook often embeds a secret, it can be read from an environment variable, or from the output
+	// of a credential helper command, rather than set in the config.
+	Message        *string            `hcl:"message,attr" cty:"message"`
+	Channels       *map[string]string `hcl:"channels,attr" cty:"channels"`
+	URLFromEnv     *string            `hcl:"url_from_env,attr" cty:"url_from_env"`
+	URLFromCommand []string           `hcl:"url_from_command,optional" cty:"url_from_command"`
+
+	Timeout          *string `hcl:"timeout,attr" cty:"timeout"`
+	OnTimeout        *string `hcl:"on_timeout,attr" cty:"on_timeout"`
+	KillProcessGroup *bool   `hcl:"kill_process_group,attr" cty:"kill_process_group"`
+
+	// The hook only runs after a plan with a resource change matching the condition.
+	IfPlan *HookPlanCondition `hcl:"if_plan,block" cty:"if_plan"`
+
+	// A before hook with export runs as soon as the config is read, and its stdout, decoded from JSON if export_json is
+	// true, is exposed to the rest of the config as hook_output.<export>.
+	Export     *string `hcl:"export,attr" cty:"export"`
+	ExportJSON *bool   `hcl:"export_json,attr" cty:"export_json"`
+
+	// A hook with an image runs its command in a container of the image, with docker or podman, so that its tools don't
+	// have to be installed where terragrunt runs.
+	Image            *string `hcl:"image,attr" cty:"image"`
+	ContainerRuntime *string `hcl:"container_runtime,attr" cty:"container_runtime"`
+
+	// A hook with propagate applies to the units including its config even when their merge strategy is no_merge,
+	// unless they list it in skip_propagated_hooks.
+	Propagate *bool `hcl:"propagate,attr" cty:"propagate"`
+}
+
+// IsBackground returns true if the hook runs in the background.
+func (hook Hook) IsBackground() bool {
+	return hook.RunInBackground != nil && *hook.RunInBackground
+}
+
+// GetBackgroundTimeout returns the longest the hook may run in the background, or zero if it may run until it finishes.
+func (hook Hook) GetBackgroundTimeout() time.Duration {
+	if hook.BackgroundTimeout == nil {
+		return 0
+	}
+	timeout, _ := time.ParseDuration(*hook.BackgroundTimeout)
+	return timeout
+}
+
+// validateHookBackground checks the background settings of the hook. The tflint hook runs in process, so it can't be
+// killed and doesn't run in the background.
+func validateHookBackground(hook Hook) error {
+	if hook.BackgroundTimeout != nil {
+		if !hook.IsBackground() {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'background_timeout' can only be set when 'run_in_background' is true.", hook.Name))
+		}
+		if timeout, err := time.ParseDuration(*hook.BackgroundTimeout); err != nil || timeout <= 0 {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'background_timeout' must be a positive duration such as \"10m\", got %q.", hook.Name, *hook.BackgroundTimeout))
+		}
+	}
+	if hook.IsBackground() && !hook.IsHTTP() && hook.Execute[0] == "tflint" {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. The tflint hook can't run in the background.", hook.Name))
+	}
+	return nil
 }
 
 type ErrorHook struct {
 	Name           string   `hcl:"name,label" cty:"name"`
 	Commands       []string `hcl:"commands,attr" cty:"commands"`
 	Execute        []string `hcl:"execute,attr" cty:"execute"`
-	OnErrors       []string `hcl:"on_errors,attr" cty:"on_errors"`
+	OnErrors       []string `hcl:"on_errors,optional" cty:"on_errors"`
 	SuppressStdout *bool    `hcl:"suppress_stdout,attr" cty:"suppress_stdout"`
 	WorkingDir     *string  `hcl:"working_dir,attr" cty:"working_dir"`
+	Merge          *string  `hcl:"merge,attr" cty:"merge"`
+
+	// The hook also runs for the errors of these classes, such as lock_timeout, whatever their output.
+	OnErrorClasses []string `hcl:"on_error_classes,optional" cty:"on_error_classes"`
+
+	Env *map[string]string `hcl:"env,attr" cty:"env"`
+
+	Timeout          *string `hcl:"timeout,attr" cty:"timeout"`
+	OnTimeout        *string `hcl:"on_timeout,attr" cty:"on_timeout"`
+	KillProcessGroup *bool   `hcl:"kill_process_group,attr" cty:"kill_process_group"`
+
+	// The error hook applies to the units including its config even when their merge strategy is no_merge, as with
+	// the propagate attribute of the other hooks.
+	Propagate *bool `hcl:"propagate,attr" cty:"propagate"`
+}
+
+// hookMergeStrategy returns the value of the merge attribute of a hook, which defaults to override.
+func hookMergeStrategy(merge *string) string {
+	if merge == nil {
+		return HookMergeOverride
+	}
+	return *merge
+}
+
+// validateHookMerge returns an error if the value of the merge attribute of the given hook is not supported.
+func validateHookMerge(hookName string, merge *string) error {
+	switch hookMergeStrategy(merge) {
+	case HookMergeOverride, HookMergeAppend, HookMergePrepend:
+		return nil
+	default:
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'merge' must be one of %q, %q or %q, got %q.", hookName, HookMergeOverride, HookMergeAppend, HookMergePrepend, *merge))
+	}
+}
+
+// ExcludeActionAll is the action that makes the exclude block apply to all the commands.
+const ExcludeActionAll = "all"
+
+// ExcludeConfig is an exclude block, which skips the module for the actions it lists if its condition is true.
+type ExcludeConfig struct {
+	If      bool     `hcl:"if,attr" cty:"if"`
+	Actions []string `hcl:"actions,optional" cty:"actions"`
+}
+
+func (conf *ExcludeConfig) String() string {
+	return fmt.Sprintf("ExcludeConfig{If = %v, Actions = %v}", conf.If, conf.Actions)
+}
+
+// ExcludesCommand returns true if the module must be skipped when running the given terraform command: the condition is
+// true and the command is one of the actions, or the actions are not set or include "all".
+func (conf *ExcludeConfig) ExcludesCommand(command string) bool {
+	if !conf.If {
+		return false
+	}
+	if len(conf.Actions) == 0 {
+		return true
+	}
+	return util.ListContainsElement(conf.Actions, ExcludeActionAll) || util.ListContainsElement(conf.Actions, command)
+}
+
+// RetryConfig is a retry policy for terraform commands whose output matches one of the RetryableErrors.
+type RetryConfig struct {
+	Name                string   `hcl:"name,label" cty:"name"`
+	RetryableErrors     []string `hcl:"retryable_errors,attr" cty:"retryable_errors"`
+	Commands            []string `hcl:"commands,optional" cty:"commands"`
+	MaxAttempts         *int     `hcl:"max_attempts,optional" cty:"max_attempts"`
+	SleepIntervalSec    *int     `hcl:"sleep_interval_sec,optional" cty:"sleep_interval_sec"`
+	MaxSleepIntervalSec *int     `hcl:"max_sleep_interval_sec,optional" cty:"max_sleep_interval_sec"`
+	BackoffMultiplier   *float64 `hcl:"backoff_multiplier,optional" cty:"backoff_multiplier"`
+	Jitter              *bool    `hcl:"jitter,optional" cty:"jitter"`
+}
+
+func (conf *RetryConfig) String() string {
+	return fmt.Sprintf("RetryConfig{Name = %s, RetryableErrors = %v}", conf.Name, len(conf.RetryableErrors))
+}
+
+// ToRetryPolicy converts the retry block to the retry policy used at runtime. Settings that are not defined in the
+// block fall back to the given default policy.
+func (conf *RetryConfig) ToRetryPolicy(defaultPolicy *options.RetryPolicy) (*options.RetryPolicy, error) {
+	policy := &options.RetryPolicy{
+		Name:              conf.Name,
+		RetryableErrors:   conf.RetryableErrors,
+		Commands:          conf.Commands,
+		MaxAttempts:       defaultPolicy.MaxAttempts,
+		SleepInterval:     defaultPolicy.SleepInterval,
+		MaxSleepInterval:  defaultPolicy.MaxSleepInterval,
+		BackoffMultiplier: 1,
+	}
+
+	if conf.MaxAttempts != nil {
+		if *conf.MaxAttempts < 1 {
+			return nil, errors.WithStackTrace(InvalidRetryConfig{Name: conf.Name, Reason: fmt.Sprintf("max_attempts must be at least 1, but you specified %d", *conf.MaxAttempts)})
+		}
+		policy.MaxAttempts = *conf.MaxAttempts
+	}
+	if conf.SleepIntervalSec != nil {
+		if *conf.SleepIntervalSec < 0 {
+			return nil, errors.WithStackTrace(InvalidRetryConfig{Name: conf.Name, Reason: fmt.Sprintf("sleep_interval_sec cannot be less than 0, but you specified %d", *conf.SleepIntervalSec)})
+		}
+		policy.SleepInterval = time.Duration(*conf.SleepIntervalSec) * time.Second
+	}
+	if conf.MaxSleepIntervalSec != nil {
+		if *conf.MaxSleepIntervalSec < 0 {
+			return nil, errors.WithStackTrace(InvalidRetryConfig{Name: conf.Name, Reason: fmt.Sprintf("max_sleep_interval_sec cannot be less than 0, but you specified %d", *conf.MaxSleepIntervalSec)})
+		}
+		policy.MaxSleepInterval = time.Duration(*conf.MaxSleepIntervalSec) * time.Second
+	}
+	if conf.BackoffMultiplier != nil {
+		if *conf.BackoffMultiplier < 1 {
+			return nil, errors.WithStackTrace(InvalidRetryConfig{Name: conf.Name, Reason: fmt.Sprintf("backoff_multiplier cannot be less than 1, but you specified %v", *conf.BackoffMultiplier)})
+		}
+		policy.BackoffMultiplier = *conf.BackoffMultiplier
+	}
+	if conf.Jitter != nil {
+		policy.Jitter = *conf.Jitter
+	}
+
+	return policy, nil
 }
 
 func (conf *Hook) String() string {
@@ -371,20 +843,78 @@ func (conf *ErrorHook) String() string {
 // well.
 type TerraformConfig struct {
 	ExtraArgs   []TerraformExtraArguments `hcl:"extra_arguments,block"`
-	Source      *string                   `hcl:"source,attr"`
 	BeforeHooks []Hook                    `hcl:"before_hook,block"`
 	AfterHooks  []Hook                    `hcl:"after_hook,block"`
 	ErrorHooks  []ErrorHook               `hcl:"error_hook,block"`
 
+	// The hooks run once the result of the module is known: after the command and all the steps following it succeeded,
+	// after any of them failed, or when the module was skipped because one of its dependencies failed in a run-all.
+	OnSuccessHooks []Hook `hcl:"on_success_hook,block"`
+	OnFailureHooks []Hook `hcl:"on_failure_hook,block"`
+	OnSkipHooks    []Hook `hcl:"on_skip_hook,block"`
+
+	// The source attribute is either a URL or an ordered list of URLs, the primary one followed by its mirrors, so it
+	// is decoded as is and then split into Source and SourceMirrors by decodeSource. It is always nil after decoding,
+	// so it has no counterpart in ctyTerraformConfig.
+	RawSource *cty.Value `hcl:"source,attr" structs:"-"`
+
+	Source *string
+	// The URLs to download the source from, in order, if downloading it from Source fails.
+	SourceMirrors []string
+
 	// Ideally we can avoid the pointer to list slice, but if it is not a pointer, Terraform requires the attribute to
 	// be defined and we want to make this optional.
 	IncludeInCopy *[]string `hcl:"include_in_copy,attr"`
+
+	// The maximum amount of time the commands run for the module may take, as a duration string such as "30m". When
+	// exceeded, the running command is killed and the module fails.
+	Timeout *string `hcl:"timeout,attr"`
 }
 
 func (conf *TerraformConfig) String() string {
 	return fmt.Sprintf("TerraformConfig{Source = %v}", conf.Source)
 }
 
+// decodeSource sets Source and SourceMirrors from the source attribute decoded in RawSource.
+func (conf *TerraformConfig) decodeSource() error {
+	if conf == nil || conf.RawSource == nil {
+		return nil
+	}
+
+	source, mirrors, err := decodeTerraformSource(*conf.RawSource)
+	if err != nil {
+		return err
+	}
+	conf.Source = source
+	conf.SourceMirrors = mirrors
+	conf.RawSource = nil
+	return nil
+}
+
+// decodeTerraformSource returns the primary URL and the mirrors of the given source attribute, which is either a URL or
+// a non-empty list of URLs.
+func decodeTerraformSource(value cty.Value) (*string, []string, error) {
+	if value.IsNull() {
+		return nil, nil, nil
+	}
+
+	if value.Type() == cty.String {
+		source := value.AsString()
+		return &source, nil, nil
+	}
+
+	urls := []string{}
+	if listValue, err := convert.Convert(value, cty.List(cty.String)); err == nil && listValue.IsWhollyKnown() {
+		if err := gocty.FromCtyValue(listValue, &urls); err != nil {
+			return nil, nil, errors.WithStackTrace(err)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, nil, errors.WithStackTrace(InvalidTerraformSource{Type: value.Type().FriendlyName()})
+	}
+	return &urls[0], urls[1:], nil
+}
+
 func (conf *TerraformConfig) GetBeforeHooks() []Hook {
 	if conf == nil {
 		return nil
@@ -409,19 +939,89 @@ func (conf *TerraformConfig) GetErrorHooks() []ErrorHook {
 	return conf.ErrorHooks
 }
 
-func (conf *TerraformConfig) ValidateHooks() error {
-	beforeAndAfterHooks := append(conf.GetBeforeHooks(), conf.GetAfterHooks()...)
+func (conf *TerraformConfig) GetOnSuccessHooks() []Hook {
+	if conf == nil {
+		return nil
+	}
 
-	for _, curHook := range beforeAndAfterHooks {
-		if len(curHook.Execute) < 1 || curHook.Execute[0] == "" {
-			return InvalidArgError(fmt.Sprintf("Error with hook %s. Need at least one non-empty argument in 'execute'.", curHook.Name))
+	return conf.OnSuccessHooks
+}
+
+func (conf *TerraformConfig) GetOnFailureHooks() []Hook {
+	if conf == nil {
+		return nil
+	}
+
+	return conf.OnFailureHooks
+}
+
+func (conf *TerraformConfig) GetOnSkipHooks() []Hook {
+	if conf == nil {
+		return nil
+	}
+
+	return conf.OnSkipHooks
+}
+
+func (conf *TerraformConfig) ValidateHooks() error {
+	hooks := append([]Hook{}, conf.GetBeforeHooks()...)
+	hooks = append(hooks, conf.GetAfterHooks()...)
+	hooks = append(hooks, conf.GetOnSuccessHooks()...)
+	hooks = append(hooks, conf.GetOnFailureHooks()...)
+	hooks = append(hooks, conf.GetOnSkipHooks()...)
+
+	for i, curHook := range hooks {
+		if err := validateHookType(curHook); err != nil {
+			return err
+		}
+		// The before and on_skip hooks, which come first and last in the list, run without a plan.
+		runsAfterCommand := i >= len(conf.GetBeforeHooks()) && i < len(hooks)-len(conf.GetOnSkipHooks())
+		if err := validateHookPlanCondition(curHook, runsAfterCommand); err != nil {
+			return err
+		}
+		if err := validateHookExport(curHook, i < len(conf.GetBeforeHooks())); err != nil {
+			return err
 		}
+		if err := validateHookMerge(curHook.Name, curHook.Merge); err != nil {
+			return err
+		}
+		if err := validateHookBackground(curHook); err != nil {
+			return err
+		}
+		if err := validateHookContainer(curHook); err != nil {
+			return err
+		}
+		if err := validateHookEnv(curHook.Name, curHook.Env); err != nil {
+			return err
+		}
+		if err := validateHookTimeout(curHook.Name, curHook.HookTimeout()); err != nil {
+			return err
+		}
+		if curHook.Timeout != nil && (curHook.IsBackground() || (!curHook.IsHTTP() && curHook.Execute[0] == "tflint")) {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'timeout' can't be set on a background hook, which has 'background_timeout', nor on the tflint hook.", curHook.Name))
+		}
+	}
+
+	if err := validateHookExportNames(conf.GetBeforeHooks()); err != nil {
+		return err
 	}
 
 	for _, curHook := range conf.GetErrorHooks() {
 		if len(curHook.Execute) < 1 || curHook.Execute[0] == "" {
 			return InvalidArgError(fmt.Sprintf("Error with hook %s. Need at least one non-empty argument in 'execute'.", curHook.Name))
 		}
+		if err := validateErrorHookMatchers(curHook); err != nil {
+			return err
+		}
+		if err := validateHookMerge(curHook.Name, curHook.Merge); err != nil {
+			return err
+		}
+		if err := validateHookEnv(curHook.Name, curHook.Env); err != nil {
+			return err
+		}
+		if err := validateHookTimeout(curHook.Name, curHook.HookTimeout()); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -484,6 +1084,25 @@ func GetTerraformSourceUrl(terragruntOptions *options.TerragruntOptions, terragr
 	}
 }
 
+// GetTerraformSourceMirrorUrls returns the URLs to download the Terraform configurations from, in order, if downloading
+// them from the URL returned by GetTerraformSourceUrl fails. There are no mirrors when the source is set with the
+// command-line option.
+func GetTerraformSourceMirrorUrls(terragruntOptions *options.TerragruntOptions, terragruntConfig *TerragruntConfig) ([]string, error) {
+	if terragruntOptions.Source != "" || terragruntConfig.Terraform == nil {
+		return nil, nil
+	}
+
+	mirrorUrls := []string{}
+	for _, mirror := range terragruntConfig.Terraform.SourceMirrors {
+		mirrorUrl, err := adjustSourceWithMap(terragruntOptions.SourceMap, mirror, terragruntOptions.OriginalTerragruntConfigPath)
+		if err != nil {
+			return nil, err
+		}
+		mirrorUrls = append(mirrorUrls, mirrorUrl)
+	}
+	return mirrorUrls, nil
+}
+
 // adjustSourceWithMap implements the --terragrunt-source-map feature. This function will check if the URL portion of a
 // terraform source matches any entry in the provided source map and if it does, replace it with the configured source
 // in the map. Note that this only performs literal matches with the URL portion.
@@ -697,12 +1316,51 @@ func ParseConfigFile(filename string, terragruntOptions *options.TerragruntOptio
 //     - dependency
 //  5. Merge the included config with the parsed config. Note that all the config data is mergable except for `locals`
 //     blocks, which are only scoped to be available within the defining config.
+//  6. Deep merge the overlay of the config for the environment selected with --terragrunt-env, if any, unless this is an
+//     included config, add the hooks of the hook sets listed in use_hooks, evaluate the validation blocks, and render
+//     the templates of the generate blocks.
 func ParseConfigString(
 	configString string,
 	terragruntOptions *options.TerragruntOptions,
 	includeFromChild *IncludeConfig,
 	filename string,
 	contextExtensions *EvalContextExtensions,
+) (*TerragruntConfig, error) {
+	config, err := parseConfigString(configString, terragruntOptions, includeFromChild, filename, contextExtensions)
+	if err != nil {
+		return nil, err
+	}
+
+	// The overlays, validations and generate templates of included configurations are handled along with the
+	// configuration that includes them, so that validations and templates are evaluated with its inputs.
+	if includeFromChild != nil {
+		return config, nil
+	}
+
+	config, err = mergeEnvOverlay(config, filename, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+	config, err = applyHookSets(config, filename, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+	if err := evaluateValidations(config, terragruntOptions); err != nil {
+		return nil, err
+	}
+	if err := renderGenerateTemplates(config, terragruntOptions); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// parseConfigString runs the steps 1 to 5 of ParseConfigString.
+func parseConfigString(
+	configString string,
+	terragruntOptions *options.TerragruntOptions,
+	includeFromChild *IncludeConfig,
+	filename string,
+	contextExtensions *EvalContextExtensions,
 ) (*TerragruntConfig, error) {
 	// Parse the HCL string into an AST body that can be decoded multiple times later without having to re-parse
 	parser := hclparse.NewParser()
@@ -710,6 +1368,9 @@ func ParseConfigString(
 	if err != nil {
 		return nil, err
 	}
+	if err := validateStrictConfig(file, filename, terragruntOptions); err != nil {
+		return nil, err
+	}
 
 	// Initial evaluation of configuration to load flags like IamRole which will be used for final parsing
 	// https://github.com/gruntwork-io/terragrunt/issues/667
@@ -717,24 +1378,11 @@ func ParseConfigString(
 		return nil, err
 	}
 
-	// Decode just the Base blocks. See the function docs for DecodeBaseBlocks for more info on what base blocks are.
-	localsAsCty, trackInclude, err := DecodeBaseBlocks(terragruntOptions, parser, file, filename, includeFromChild, nil)
+	baseBlocks, err := decodeContextExtensions(parser, file, filename, terragruntOptions, includeFromChild, contextExtensions)
 	if err != nil {
 		return nil, err
 	}
-
-	contextExtensions.Locals = localsAsCty
-	contextExtensions.TrackInclude = trackInclude
-
-	if contextExtensions.DecodedDependencies == nil {
-		// Decode just the `dependency` blocks, retrieving the outputs from the target terragrunt config in the
-		// process.
-		retrievedOutputs, err := decodeAndRetrieveOutputs(file, filename, terragruntOptions, trackInclude, contextExtensions)
-		if err != nil {
-			return nil, err
-		}
-		contextExtensions.DecodedDependencies = retrievedOutputs
-	}
+	trackInclude := baseBlocks.TrackInclude
 
 	evalContext, err := contextExtensions.CreateTerragruntEvalContext(filename, terragruntOptions)
 	if err != nil {
@@ -751,7 +1399,18 @@ func ParseConfigString(
 		return nil, errors.WithStackTrace(CouldNotResolveTerragruntConfigInFile(filename))
 	}
 
-	config, err := convertToTerragruntConfig(terragruntConfigFile, filename, terragruntOptions, contextExtensions)
+	config, err := convertToTerragruntConfig(terragruntConfigFile, filename, terragruntOptions, contextExtensions, evalContext)
+	if err != nil {
+		return nil, err
+	}
+	config.DeclaredFeatures = declaredFeatureNames(baseBlocks.Features)
+	config.Validations = newValidationConfigs(terragruntConfigFile.Validations, filename, evalContext)
+	if terragruntOptions.RenderJsonWithSourcePositions {
+		setFieldsSourcePositions(config, file, includeFromChild != nil)
+	}
+
+	// Merge the imported configs under this config, before the included configs are merged in.
+	config, err = mergeImportedConfigs(config, baseBlocks.Imports, terragruntOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -771,11 +1430,47 @@ func ParseConfigString(
 		//   config.
 		mergedConfig.Locals = config.Locals
 
-		return mergedConfig, nil
+		config = mergedConfig
 	}
+
 	return config, nil
 }
 
+// decodeContextExtensions runs the steps 1 to 3 of ParseConfigString: it decodes the base blocks and the `dependency`
+// blocks of the given config into the given context extensions, and returns the base blocks.
+func decodeContextExtensions(
+	parser *hclparse.Parser,
+	file *hcl.File,
+	filename string,
+	terragruntOptions *options.TerragruntOptions,
+	includeFromChild *IncludeConfig,
+	contextExtensions *EvalContextExtensions,
+) (*DecodedBaseBlocks, error) {
+	// Decode just the Base blocks. See the function docs for DecodeBaseBlocks for more info on what base blocks are.
+	baseBlocks, err := DecodeBaseBlocks(terragruntOptions, parser, file, filename, includeFromChild, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	contextExtensions.Locals = baseBlocks.Locals
+	contextExtensions.TrackInclude = baseBlocks.TrackInclude
+	contextExtensions.Features = baseBlocks.Features
+	contextExtensions.Variables = baseBlocks.Variables
+	contextExtensions.Imports = baseBlocks.ImportsAsCty
+
+	if contextExtensions.DecodedDependencies == nil {
+		// Decode just the `dependency` blocks, retrieving the outputs from the target terragrunt config in the
+		// process.
+		retrievedOutputs, err := decodeAndRetrieveOutputs(file, filename, terragruntOptions, baseBlocks.TrackInclude, contextExtensions)
+		if err != nil {
+			return nil, err
+		}
+		contextExtensions.DecodedDependencies = retrievedOutputs
+	}
+
+	return baseBlocks, nil
+}
+
 // iamRoleCache - store for cached values of IAM roles
 var iamRoleCache = NewIAMRoleOptionsCache()
 
@@ -785,8 +1480,9 @@ func setIAMRole(configString string, terragruntOptions *options.TerragruntOption
 	if terragruntOptions.OriginalIAMRoleOptions.RoleARN != "" {
 		terragruntOptions.IAMRoleOptions = terragruntOptions.OriginalIAMRoleOptions
 	} else {
-		// as key is considered HCL code and include configuration
-		var key = fmt.Sprintf("%v-%v", configString, includeFromChild)
+		// as key is considered HCL code, include configuration, and the terraform command, which selects the role when
+		// iam_role is a map
+		var key = fmt.Sprintf("%v-%v-%v", configString, includeFromChild, terragruntOptions.TerraformCommand)
 		var config, found = iamRoleCache.Get(key)
 		if !found {
 			iamConfig, err := TerragruntConfigFromPartialConfigString(configString, terragruntOptions, includeFromChild, filename, []PartialDecodeSectionType{TerragruntFlags})
@@ -814,8 +1510,8 @@ func decodeAsTerragruntConfigFile(
 ) (*terragruntConfigFile, error) {
 	terragruntConfig := terragruntConfigFile{}
 	err := decodeHcl(file, filename, &terragruntConfig, evalContext)
-	// in case of render-json command and inputs reference error, we update the inputs with default value
-	if diagErr, ok := err.(hcl.Diagnostics); ok && isRenderJsonCommand(terragruntOptions) && isAttributeAccessError(diagErr) {
+	// in case of render-json or render command and inputs reference error, we update the inputs with default value
+	if diagErr, ok := err.(hcl.Diagnostics); ok && isRenderCommand(terragruntOptions) && isAttributeAccessError(diagErr) {
 		terragruntOptions.Logger.Warnf("Failed to decode inputs %v", diagErr)
 		// update unknown inputs with default value
 		updatedValue := map[string]cty.Value{}
@@ -889,6 +1585,7 @@ func convertToTerragruntConfig(
 	configPath string,
 	terragruntOptions *options.TerragruntOptions,
 	contextExtensions *EvalContextExtensions,
+	evalContext *hcl.EvalContext,
 ) (cfg *TerragruntConfig, err error) {
 	// The HCL2 parser and especially cty conversions will panic in many types of errors, so we have to recover from
 	// those panics here and convert them to normal errors
@@ -929,6 +1626,10 @@ func convertToTerragruntConfig(
 		terragruntConfig.SetFieldMetadata(MetadataRemoteState, defaultMetadata)
 	}
 
+	if err := terragruntConfigFromFile.Terraform.decodeSource(); err != nil {
+		return nil, err
+	}
+
 	if err := terragruntConfigFromFile.Terraform.ValidateHooks(); err != nil {
 		return nil, err
 	}
@@ -973,6 +1674,14 @@ func convertToTerragruntConfig(
 		terragruntConfig.SetFieldMetadata(MetadataRetrySleepIntervalSec, defaultMetadata)
 	}
 
+	if err := validateRetryBlocks(terragruntConfigFromFile.RetryConfigs); err != nil {
+		return nil, err
+	}
+	terragruntConfig.RetryConfigs = terragruntConfigFromFile.RetryConfigs
+	for _, retryConfig := range terragruntConfig.RetryConfigs {
+		terragruntConfig.SetFieldMetadataWithType(MetadataRetry, retryConfig.Name, defaultMetadata)
+	}
+
 	if terragruntConfigFromFile.DownloadDir != nil {
 		terragruntConfig.DownloadDir = *terragruntConfigFromFile.DownloadDir
 		terragruntConfig.SetFieldMetadata(MetadataDownloadDir, defaultMetadata)
@@ -999,7 +1708,10 @@ func convertToTerragruntConfig(
 	}
 
 	if terragruntConfigFromFile.IamRole != nil {
-		terragruntConfig.IamRole = *terragruntConfigFromFile.IamRole
+		terragruntConfig.IamRole, terragruntConfig.IamRoles, err = decodeIamRole(*terragruntConfigFromFile.IamRole, terragruntOptions)
+		if err != nil {
+			return nil, err
+		}
 		terragruntConfig.SetFieldMetadata(MetadataIamRole, defaultMetadata)
 	}
 
@@ -1013,6 +1725,63 @@ func convertToTerragruntConfig(
 		terragruntConfig.SetFieldMetadata(MetadataIamAssumeRoleSessionName, defaultMetadata)
 	}
 
+	if terragruntConfigFromFile.IamMfaSerial != nil {
+		terragruntConfig.IamMfaSerial = *terragruntConfigFromFile.IamMfaSerial
+		terragruntConfig.SetFieldMetadata(MetadataIamMfaSerial, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.IamWebIdentityToken != nil {
+		terragruntConfig.IamWebIdentityToken = *terragruntConfigFromFile.IamWebIdentityToken
+		terragruntConfig.SetFieldMetadata(MetadataIamWebIdentityToken, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Priority != nil {
+		terragruntConfig.Priority = terragruntConfigFromFile.Priority
+		terragruntConfig.SetFieldMetadata(MetadataPriority, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Tags != nil {
+		terragruntConfig.Tags = terragruntConfigFromFile.Tags
+		terragruntConfig.SetFieldMetadata(MetadataTags, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.StrictMockOutputs != nil {
+		terragruntConfig.StrictMockOutputs = terragruntConfigFromFile.StrictMockOutputs
+		terragruntConfig.SetFieldMetadata(MetadataStrictMockOutputs, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.Exclude != nil {
+		terragruntConfig.Exclude = terragruntConfigFromFile.Exclude
+		terragruntConfig.SetFieldMetadata(MetadataExclude, defaultMetadata)
+	}
+
+	if err := validateHookSets(terragruntConfigFromFile.HookSets, configPath); err != nil {
+		return nil, err
+	}
+	if terragruntConfigFromFile.HookSets != nil {
+		terragruntConfig.HookSets = terragruntConfigFromFile.HookSets
+		terragruntConfig.SetFieldMetadata(MetadataHookSet, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.UseHooks != nil {
+		terragruntConfig.UseHooks = terragruntConfigFromFile.UseHooks
+		terragruntConfig.SetFieldMetadata(MetadataUseHooks, defaultMetadata)
+	}
+
+	if terragruntConfigFromFile.SkipPropagatedHooks != nil {
+		terragruntConfig.SkipPropagatedHooks = terragruntConfigFromFile.SkipPropagatedHooks
+		terragruntConfig.SetFieldMetadata(MetadataSkipPropagatedHooks, defaultMetadata)
+	}
+
+	customCommands, err := decodeCustomCommands(terragruntConfigFromFile.Commands, configPath, evalContext)
+	if err != nil {
+		return nil, err
+	}
+	if customCommands != nil {
+		terragruntConfig.CustomCommands = customCommands
+		terragruntConfig.SetFieldMetadata(MetadataCustomCommands, defaultMetadata)
+	}
+
 	generateBlocks := []terragruntGenerateBlock{}
 	generateBlocks = append(generateBlocks, terragruntConfigFromFile.GenerateBlocks...)
 
@@ -1060,12 +1829,21 @@ func convertToTerragruntConfig(
 		} else {
 			genConfig.Disable = *block.Disable
 		}
+		if block.Template != nil && *block.Template {
+			genConfig.TemplateContext = evalContext
+		}
 		terragruntConfig.GenerateConfigs[block.Name] = genConfig
 		terragruntConfig.SetFieldMetadataWithType(MetadataGenerateConfigs, block.Name, defaultMetadata)
 	}
 
 	if terragruntConfigFromFile.Inputs != nil {
-		inputs, err := parseCtyValueToMap(*terragruntConfigFromFile.Inputs)
+		inputsVal := *terragruntConfigFromFile.Inputs
+		// The inputs that reference skipped dependency outputs, or the outputs of hooks that did not run yet, are unknown,
+		// and can't be converted, so they are null.
+		if terragruntOptions.SkipDependencyOutputs || hasPendingHookOutputs(terragruntOptions) {
+			inputsVal = unknownValuesToNull(inputsVal)
+		}
+		inputs, err := parseCtyValueToMap(inputsVal)
 		if err != nil {
 			return nil, err
 		}
@@ -1075,7 +1853,11 @@ func convertToTerragruntConfig(
 	}
 
 	if contextExtensions.Locals != nil && *contextExtensions.Locals != cty.NilVal {
-		localsParsed, err := parseCtyValueToMap(*contextExtensions.Locals)
+		localsVal := *contextExtensions.Locals
+		if hasPendingHookOutputs(terragruntOptions) {
+			localsVal = unknownValuesToNull(localsVal)
+		}
+		localsParsed, err := parseCtyValueToMap(localsVal)
 		if err != nil {
 			return nil, err
 		}
@@ -1127,6 +1909,24 @@ func validateGenerateBlocks(blocks *[]terragruntGenerateBlock) error {
 	return nil
 }
 
+// Iterate over retry blocks and detect duplicate names, return error with list of duplicated names
+func validateRetryBlocks(blocks []RetryConfig) error {
+	var blockNames = map[string]bool{}
+	var duplicatedRetryBlockNames []string
+
+	for _, block := range blocks {
+		if blockNames[block.Name] {
+			duplicatedRetryBlockNames = append(duplicatedRetryBlockNames, block.Name)
+			continue
+		}
+		blockNames[block.Name] = true
+	}
+	if len(duplicatedRetryBlockNames) != 0 {
+		return DuplicatedRetryBlocks{duplicatedRetryBlockNames}
+	}
+	return nil
+}
+
 // configFileHasDependencyBlock statically checks the terrragrunt config file at the given path and checks if it has any
 // dependency or dependencies blocks defined. Note that this does not do any decoding of the blocks, as it is only meant
 // to check for block presence.
@@ -1158,7 +1958,7 @@ func (conf *TerragruntConfig) SetFieldMetadataWithType(fieldType, fieldName stri
 		conf.FieldsMetadata = map[string]map[string]interface{}{}
 	}
 
-	field := fmt.Sprintf("%s-%s", fieldType, fieldName)
+	field := fieldMetadataKey(fieldType, fieldName)
 
 	metadata, found := conf.FieldsMetadata[field]
 	if !found {
@@ -1193,7 +1993,7 @@ func (conf *TerragruntConfig) GetMapFieldMetadata(fieldType, fieldName string) (
 	if conf.FieldsMetadata == nil {
 		return nil, false
 	}
-	field := fmt.Sprintf("%s-%s", fieldType, fieldName)
+	field := fieldMetadataKey(fieldType, fieldName)
 
 	value, found := conf.FieldsMetadata[field]
 	if !found {
@@ -1215,6 +2015,35 @@ func (e InvalidArgError) Error() string {
 	return string(e)
 }
 
+type InvalidRemoteStateEnvironment struct {
+	Environment  string
+	Environments []string
+}
+
+func (err InvalidRemoteStateEnvironment) Error() string {
+	if err.Environment == "" {
+		return fmt.Sprintf("The remote_state environments attribute requires the environment attribute to select one of the environments %s.", strings.Join(err.Environments, ", "))
+	}
+	return fmt.Sprintf("The remote_state environment %q is not one of the environments %s.", err.Environment, strings.Join(err.Environments, ", "))
+}
+
+type InvalidTerraformSource struct {
+	Type string
+}
+
+func (err InvalidTerraformSource) Error() string {
+	return fmt.Sprintf("The source of the terraform block must be a URL or a non-empty list of URLs, got %s.", err.Type)
+}
+
+type InvalidYamlConfig struct {
+	ConfigPath string
+	Err        error
+}
+
+func (err InvalidYamlConfig) Error() string {
+	return fmt.Sprintf("Invalid YAML config %s: %v", err.ConfigPath, err.Err)
+}
+
 type IncludedConfigMissingPath string
 
 func (err IncludedConfigMissingPath) Error() string {
@@ -1246,6 +2075,12 @@ func (err ErrorParsingTerragruntConfig) Error() string {
 	return fmt.Sprintf("Error parsing Terragrunt config at %s: %v", err.ConfigPath, err.Underlying)
 }
 
+type InvalidIamRole string
+
+func (err InvalidIamRole) Error() string {
+	return fmt.Sprintf("Invalid iam_role: %s", string(err))
+}
+
 type PanicWhileParsingConfig struct {
 	ConfigFile     string
 	RecoveredValue interface{}
@@ -1277,6 +2112,32 @@ func (err InvalidMergeStrategyType) Error() string {
 	)
 }
 
+type InvalidAttributeMergeStrategyType struct {
+	Attribute string
+	Strategy  string
+}
+
+func (err InvalidAttributeMergeStrategyType) Error() string {
+	return fmt.Sprintf(
+		"Include merge strategy %s for attribute %s is unknown. Valid strategies are: %s, %s, %s",
+		err.Strategy,
+		err.Attribute,
+		NoMerge,
+		ShallowMerge,
+		DeepMerge,
+	)
+}
+
+type UnsupportedAttributeMergeStrategy string
+
+func (err UnsupportedAttributeMergeStrategy) Error() string {
+	return fmt.Sprintf(
+		"The merge strategy of attribute %s can't be set in merge_strategies. Supported attributes are: %s",
+		string(err),
+		strings.Join(AttributeMergeStrategyAttributes, ", "),
+	)
+}
+
 type DependencyDirNotFound struct {
 	Dir []string
 }
@@ -1296,3 +2157,22 @@ func (err DuplicatedGenerateBlocks) Error() string {
 		"Detected generate blocks with the same name: %v", err.BlockName,
 	)
 }
+
+type DuplicatedRetryBlocks struct {
+	BlockName []string
+}
+
+func (err DuplicatedRetryBlocks) Error() string {
+	return fmt.Sprintf(
+		"Detected retry blocks with the same name: %v", err.BlockName,
+	)
+}
+
+type InvalidRetryConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidRetryConfig) Error() string {
+	return fmt.Sprintf("Invalid retry block %s: %s", err.Name, err.Reason)
+}
@@ -9,6 +9,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
 // Serialize TerragruntConfig struct to a cty Value that can be used to reference the attributes in other config. Note
@@ -27,6 +28,8 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 	output[MetadataIamRole] = gostringToCty(config.IamRole)
 	output[MetadataSkip] = goboolToCty(config.Skip)
 	output[MetadataIamAssumeRoleSessionName] = gostringToCty(config.IamAssumeRoleSessionName)
+	output[MetadataIamMfaSerial] = gostringToCty(config.IamMfaSerial)
+	output[MetadataIamWebIdentityToken] = gostringToCty(redactWebIdentityToken(config.IamWebIdentityToken))
 
 	terraformConfigCty, err := terraformConfigAsCty(config.Terraform)
 	if err != nil {
@@ -89,6 +92,23 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 		output[MetadataIamAssumeRoleDuration] = iamAssumeRoleDurationCty
 	}
 
+	// When iam_role is a map of the terraform commands to their role, it is rendered as declared.
+	if config.IamRoles != nil {
+		iamRolesCty, err := goTypeToCty(config.IamRoles)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataIamRole] = iamRolesCty
+	}
+
+	retryCty, err := retryBlocksAsCty(config.RetryConfigs)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if retryCty != cty.NilVal {
+		output[MetadataRetry] = retryCty
+	}
+
 	retryMaxAttemptsCty, err := goTypeToCty(config.RetryMaxAttempts)
 	if err != nil {
 		return cty.NilVal, err
@@ -105,6 +125,62 @@ func TerragruntConfigAsCty(config *TerragruntConfig) (cty.Value, error) {
 		output[MetadataRetrySleepIntervalSec] = retrySleepIntervalSecCty
 	}
 
+	if config.Priority != nil {
+		priorityCty, err := goTypeToCty(*config.Priority)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataPriority] = priorityCty
+	}
+
+	if config.Tags != nil {
+		tagsCty, err := goTypeToCty(config.Tags)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataTags] = tagsCty
+	}
+
+	if config.StrictMockOutputs != nil {
+		output[MetadataStrictMockOutputs] = goboolToCty(*config.StrictMockOutputs)
+	}
+
+	if config.Exclude != nil {
+		excludeCty, err := goTypeToCty(*config.Exclude)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataExclude] = excludeCty
+	}
+
+	hookSetsCty, err := hookSetsAsCty(config.HookSets)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if hookSetsCty != cty.NilVal {
+		output[MetadataHookSet] = hookSetsCty
+	}
+
+	if config.UseHooks != nil {
+		useHooksCty, err := goTypeToCty(config.UseHooks)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataUseHooks] = useHooksCty
+	}
+
+	if config.SkipPropagatedHooks != nil {
+		skipPropagatedHooksCty, err := goTypeToCty(config.SkipPropagatedHooks)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataSkipPropagatedHooks] = skipPropagatedHooksCty
+	}
+
+	if customCommandsCty := customCommandsAsCty(config.CustomCommands); customCommandsCty != cty.NilVal {
+		output[MetadataCustomCommands] = customCommandsCty
+	}
+
 	inputsCty, err := convertToCtyWithJson(config.Inputs)
 	if err != nil {
 		return cty.NilVal, err
@@ -154,7 +230,11 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 		return cty.NilVal, err
 	}
 
-	if err := wrapWithMetadata(config, config.IamRole, MetadataIamRole, &output); err != nil {
+	var iamRole interface{} = config.IamRole
+	if config.IamRoles != nil {
+		iamRole = config.IamRoles
+	}
+	if err := wrapWithMetadata(config, iamRole, MetadataIamRole, &output); err != nil {
 		return cty.NilVal, err
 	}
 
@@ -166,6 +246,14 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 		return cty.NilVal, err
 	}
 
+	if err := wrapWithMetadata(config, config.IamMfaSerial, MetadataIamMfaSerial, &output); err != nil {
+		return cty.NilVal, err
+	}
+
+	if err := wrapWithMetadata(config, redactWebIdentityToken(config.IamWebIdentityToken), MetadataIamWebIdentityToken, &output); err != nil {
+		return cty.NilVal, err
+	}
+
 	if config.PreventDestroy != nil {
 		if err := wrapWithMetadata(config, *config.PreventDestroy, MetadataPreventDestroy, &output); err != nil {
 			return cty.NilVal, err
@@ -191,6 +279,70 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 		return cty.NilVal, err
 	}
 
+	if config.Priority != nil {
+		if err := wrapWithMetadata(config, *config.Priority, MetadataPriority, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if config.Tags != nil {
+		if err := wrapWithMetadata(config, config.Tags, MetadataTags, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if config.StrictMockOutputs != nil {
+		if err := wrapWithMetadata(config, *config.StrictMockOutputs, MetadataStrictMockOutputs, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if config.Exclude != nil {
+		if err := wrapWithMetadata(config, *config.Exclude, MetadataExclude, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	hookSetsCty, err := hookSetsAsCty(config.HookSets)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	if hookSetsCty != cty.NilVal {
+		content := ValueWithMetadata{Value: hookSetsCty}
+		if metadata, found := config.GetFieldMetadata(MetadataHookSet); found {
+			content.Metadata = metadata
+		}
+		contentCty, err := goTypeToCty(content)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataHookSet] = contentCty
+	}
+
+	if config.UseHooks != nil {
+		if err := wrapWithMetadata(config, config.UseHooks, MetadataUseHooks, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if config.SkipPropagatedHooks != nil {
+		if err := wrapWithMetadata(config, config.SkipPropagatedHooks, MetadataSkipPropagatedHooks, &output); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if customCommandsCty := customCommandsAsCty(config.CustomCommands); customCommandsCty != cty.NilVal {
+		content := ValueWithMetadata{Value: customCommandsCty}
+		if metadata, found := config.GetFieldMetadata(MetadataCustomCommands); found {
+			content.Metadata = metadata
+		}
+		contentCty, err := goTypeToCty(content)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		output[MetadataCustomCommands] = contentCty
+	}
+
 	// Terraform
 	terraformConfigCty, err := terraformConfigAsCty(config.Terraform)
 	if err != nil {
@@ -305,6 +457,35 @@ func TerragruntConfigAsCtyWithMetadata(config *TerragruntConfig) (cty.Value, err
 		}
 	}
 
+	if config.RetryConfigs != nil {
+		var retryConfigsWithMetadata = map[string]cty.Value{}
+		for _, block := range config.RetryConfigs {
+			ctyValue, err := goTypeToCty(block)
+			if err != nil {
+				continue
+			}
+			var content = ValueWithMetadata{}
+			content.Value = ctyValue
+			metadata, found := config.GetMapFieldMetadata(MetadataRetry, block.Name)
+			if found {
+				content.Metadata = metadata
+			}
+
+			v, err := goTypeToCty(content)
+			if err != nil {
+				continue
+			}
+			retryConfigsWithMetadata[block.Name] = v
+		}
+		if len(retryConfigsWithMetadata) > 0 {
+			retryCty, err := convertValuesMapToCtyVal(retryConfigsWithMetadata)
+			if err != nil {
+				return cty.NilVal, err
+			}
+			output[MetadataRetry] = retryCty
+		}
+	}
+
 	return convertValuesMapToCtyVal(output)
 }
 
@@ -337,6 +518,15 @@ func wrapCtyMapWithMetadata(config *TerragruntConfig, data *map[string]interface
 	return nil
 }
 
+// redactWebIdentityToken returns the given iam_web_identity_token if it is the path of the file holding the token, and
+// the redacted value otherwise, so that an OIDC token given inline doesn't end up in the rendered config.
+func redactWebIdentityToken(token string) string {
+	if token == "" || util.FileExists(token) {
+		return token
+	}
+	return util.RedactedValue
+}
+
 func wrapWithMetadata(config *TerragruntConfig, value interface{}, metadataName string, output *map[string]cty.Value) error {
 	if value == nil {
 		return nil
@@ -370,12 +560,17 @@ type ValueWithMetadata struct {
 // ctyTerraformConfig is an alternate representation of TerraformConfig that converts internal blocks into a map that
 // maps the name to the underlying struct, as opposed to a list representation.
 type ctyTerraformConfig struct {
-	ExtraArgs     map[string]TerraformExtraArguments `cty:"extra_arguments"`
-	Source        *string                            `cty:"source"`
-	IncludeInCopy *[]string                          `cty:"include_in_copy"`
-	BeforeHooks   map[string]Hook                    `cty:"before_hook"`
-	AfterHooks    map[string]Hook                    `cty:"after_hook"`
-	ErrorHooks    map[string]ErrorHook               `cty:"error_hook"`
+	ExtraArgs      map[string]TerraformExtraArguments `cty:"extra_arguments"`
+	Source         *string                            `cty:"source"`
+	SourceMirrors  []string                           `cty:"source_mirrors"`
+	IncludeInCopy  *[]string                          `cty:"include_in_copy"`
+	Timeout        *string                            `cty:"timeout"`
+	BeforeHooks    map[string]Hook                    `cty:"before_hook"`
+	AfterHooks     map[string]Hook                    `cty:"after_hook"`
+	ErrorHooks     map[string]ErrorHook               `cty:"error_hook"`
+	OnSuccessHooks map[string]Hook                    `cty:"on_success_hook"`
+	OnFailureHooks map[string]Hook                    `cty:"on_failure_hook"`
+	OnSkipHooks    map[string]Hook                    `cty:"on_skip_hook"`
 }
 
 // Serialize TerraformConfig to a cty Value, but with maps instead of lists for the blocks.
@@ -385,12 +580,17 @@ func terraformConfigAsCty(config *TerraformConfig) (cty.Value, error) {
 	}
 
 	configCty := ctyTerraformConfig{
-		Source:        config.Source,
-		IncludeInCopy: config.IncludeInCopy,
-		ExtraArgs:     map[string]TerraformExtraArguments{},
-		BeforeHooks:   map[string]Hook{},
-		AfterHooks:    map[string]Hook{},
-		ErrorHooks:    map[string]ErrorHook{},
+		Source:         config.Source,
+		SourceMirrors:  config.SourceMirrors,
+		IncludeInCopy:  config.IncludeInCopy,
+		Timeout:        config.Timeout,
+		ExtraArgs:      map[string]TerraformExtraArguments{},
+		BeforeHooks:    map[string]Hook{},
+		AfterHooks:     map[string]Hook{},
+		ErrorHooks:     map[string]ErrorHook{},
+		OnSuccessHooks: map[string]Hook{},
+		OnFailureHooks: map[string]Hook{},
+		OnSkipHooks:    map[string]Hook{},
 	}
 
 	for _, arg := range config.ExtraArgs {
@@ -405,10 +605,74 @@ func terraformConfigAsCty(config *TerraformConfig) (cty.Value, error) {
 	for _, errorHook := range config.ErrorHooks {
 		configCty.ErrorHooks[errorHook.Name] = errorHook
 	}
+	for _, hook := range config.OnSuccessHooks {
+		configCty.OnSuccessHooks[hook.Name] = hook
+	}
+	for _, hook := range config.OnFailureHooks {
+		configCty.OnFailureHooks[hook.Name] = hook
+	}
+	for _, hook := range config.OnSkipHooks {
+		configCty.OnSkipHooks[hook.Name] = hook
+	}
 
 	return goTypeToCty(configCty)
 }
 
+// ctyHookSet is an alternate representation of HookSet that maps the names of the hooks to the hooks, as
+// ctyTerraformConfig does.
+type ctyHookSet struct {
+	BeforeHooks    map[string]Hook      `cty:"before_hook"`
+	AfterHooks     map[string]Hook      `cty:"after_hook"`
+	ErrorHooks     map[string]ErrorHook `cty:"error_hook"`
+	OnSuccessHooks map[string]Hook      `cty:"on_success_hook"`
+	OnFailureHooks map[string]Hook      `cty:"on_failure_hook"`
+	OnSkipHooks    map[string]Hook      `cty:"on_skip_hook"`
+}
+
+// Serialize the hook sets to a cty Value, as a map of the names of the sets to the sets.
+func hookSetsAsCty(sets []HookSet) (cty.Value, error) {
+	if len(sets) == 0 {
+		return cty.NilVal, nil
+	}
+
+	setsCty := map[string]cty.Value{}
+	for _, set := range sets {
+		setCty := ctyHookSet{
+			BeforeHooks:    map[string]Hook{},
+			AfterHooks:     map[string]Hook{},
+			ErrorHooks:     map[string]ErrorHook{},
+			OnSuccessHooks: map[string]Hook{},
+			OnFailureHooks: map[string]Hook{},
+			OnSkipHooks:    map[string]Hook{},
+		}
+		for _, hook := range set.BeforeHooks {
+			setCty.BeforeHooks[hook.Name] = hook
+		}
+		for _, hook := range set.AfterHooks {
+			setCty.AfterHooks[hook.Name] = hook
+		}
+		for _, errorHook := range set.ErrorHooks {
+			setCty.ErrorHooks[errorHook.Name] = errorHook
+		}
+		for _, hook := range set.OnSuccessHooks {
+			setCty.OnSuccessHooks[hook.Name] = hook
+		}
+		for _, hook := range set.OnFailureHooks {
+			setCty.OnFailureHooks[hook.Name] = hook
+		}
+		for _, hook := range set.OnSkipHooks {
+			setCty.OnSkipHooks[hook.Name] = hook
+		}
+
+		value, err := goTypeToCty(setCty)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		setsCty[set.Name] = value
+	}
+	return convertValuesMapToCtyVal(setsCty)
+}
+
 // Serialize RemoteState to a cty Value. We can't directly serialize the struct because `config` is an arbitrary
 // interface whose type we do not know, so we have to do a hack to go through json.
 func remoteStateAsCty(remoteState *remote.RemoteState) (cty.Value, error) {
@@ -433,6 +697,18 @@ func remoteStateAsCty(remoteState *remote.RemoteState) (cty.Value, error) {
 	}
 	output["config"] = ctyJsonVal
 
+	encryptionCty, err := convertToCtyWithJson(remoteState.Encryption)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	output["encryption"] = encryptionCty
+
+	snapshotCty, err := goTypeToCty(remoteState.Snapshot)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	output["snapshot"] = snapshotCty
+
 	return convertValuesMapToCtyVal(output)
 }
 
@@ -449,6 +725,18 @@ func dependencyBlocksAsCty(dependencyBlocks []Dependency) (cty.Value, error) {
 	return convertValuesMapToCtyVal(out)
 }
 
+func retryBlocksAsCty(retryBlocks []RetryConfig) (cty.Value, error) {
+	out := map[string]cty.Value{}
+	for _, block := range retryBlocks {
+		blockCty, err := goTypeToCty(block)
+		if err != nil {
+			return cty.NilVal, err
+		}
+		out[block.Name] = blockCty
+	}
+	return convertValuesMapToCtyVal(out)
+}
+
 // Converts arbitrary go types that are json serializable to a cty Value by using json as an intermediary
 // representation. This avoids the strict type nature of cty, where you need to know the output type beforehand to
 // serialize to cty.
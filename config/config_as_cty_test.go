@@ -1,6 +1,8 @@
 package config
 
 import (
+	"os"
+	"path/filepath"
 	"sort"
 	"testing"
 
@@ -11,6 +13,7 @@ import (
 
 	"github.com/gruntwork-io/terragrunt/codegen"
 	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
 )
 
 // This test makes sure that all the fields from the TerragruntConfig struct are accounted for in the conversion to
@@ -19,6 +22,7 @@ func TestTerragruntConfigAsCtyDrift(t *testing.T) {
 	testSource := "./foo"
 	testTrue := true
 	testFalse := false
+	testPriority := 10
 	mockOutputs := cty.Zero
 	mockOutputsAllowedTerraformCommands := []string{"init"}
 	dependentModulesPath := []*string{&testSource}
@@ -68,10 +72,31 @@ func TestTerragruntConfigAsCtyDrift(t *testing.T) {
 		Dependencies: &ModuleDependencies{
 			Paths: []string{"foo"},
 		},
-		DownloadDir:    ".terragrunt-cache",
-		PreventDestroy: &testTrue,
-		Skip:           true,
-		IamRole:        "terragruntRole",
+		DownloadDir:       ".terragrunt-cache",
+		PreventDestroy:    &testTrue,
+		Skip:              true,
+		IamRole:           "terragruntRole",
+		Priority:          &testPriority,
+		Tags:              []string{"network", "prod"},
+		Exclude:           &ExcludeConfig{If: true, Actions: []string{"apply"}},
+		StrictMockOutputs: &testTrue,
+		HookSets: []HookSet{
+			HookSet{
+				Name: "notify",
+				OnFailureHooks: []Hook{
+					Hook{
+						Name:     "notify",
+						Commands: []string{"apply"},
+						Execute:  []string{"true"},
+					},
+				},
+			},
+		},
+		UseHooks:            []string{"notify"},
+		SkipPropagatedHooks: []string{"tfsec"},
+		CustomCommands: []CustomCommand{
+			{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"plan", "-out=tfplan"}}, {Execute: []string{"./notify.sh"}}}},
+		},
 		Inputs: map[string]interface{}{
 			"aws_region": "us-east-1",
 		},
@@ -90,6 +115,14 @@ func TestTerragruntConfigAsCtyDrift(t *testing.T) {
 				RenderedOutputs:                     &mockOutputs,
 			},
 		},
+		RetryConfigs: []RetryConfig{
+			RetryConfig{
+				Name:            "rate_limits",
+				RetryableErrors: []string{".*429.*"},
+				Commands:        []string{"apply"},
+				Jitter:          &testTrue,
+			},
+		},
 		GenerateConfigs: map[string]codegen.GenerateConfig{
 			"provider": codegen.GenerateConfig{
 				Path:          "foo",
@@ -126,6 +159,22 @@ func TestTerragruntConfigAsCtyDrift(t *testing.T) {
 	}
 }
 
+func TestTerragruntConfigAsCtyRedactsWebIdentityToken(t *testing.T) {
+	t.Parallel()
+
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte("eyJhbGciOiJSUzI1NiJ9.from-file"), 0600))
+
+	configCty, err := TerragruntConfigAsCty(&TerragruntConfig{IamWebIdentityToken: "eyJhbGciOiJSUzI1NiJ9.inline"})
+	require.NoError(t, err)
+	assert.Equal(t, cty.StringVal(util.RedactedValue), configCty.GetAttr(MetadataIamWebIdentityToken))
+
+	// The path of the file holding the token is not a secret.
+	configCty, err = TerragruntConfigAsCty(&TerragruntConfig{IamWebIdentityToken: tokenPath})
+	require.NoError(t, err)
+	assert.Equal(t, cty.StringVal(tokenPath), configCty.GetAttr(MetadataIamWebIdentityToken))
+}
+
 // This test makes sure that all the fields in RemoteState are converted to cty
 func TestRemoteStateAsCtyDrift(t *testing.T) {
 	testConfig := remote.RemoteState{
@@ -139,6 +188,13 @@ func TestRemoteStateAsCtyDrift(t *testing.T) {
 		Config: map[string]interface{}{
 			"bar": "baz",
 		},
+		Encryption: map[string]interface{}{
+			"method": map[string]interface{}{"unencrypted": map[string]interface{}{"migration": map[string]interface{}{}}},
+		},
+		Snapshot: &remote.RemoteStateSnapshot{
+			Bucket: "foo-snapshots",
+			Region: "us-east-1",
+		},
 	}
 
 	ctyVal, err := remoteStateAsCty(&testConfig)
@@ -199,10 +255,16 @@ func terragruntConfigStructFieldToMapKey(t *testing.T, fieldName string) (string
 		return "skip", true
 	case "IamRole":
 		return "iam_role", true
+	case "IamRoles":
+		return "", false
 	case "IamAssumeRoleDuration":
 		return "iam_assume_role_duration", true
 	case "IamAssumeRoleSessionName":
 		return "iam_assume_role_session_name", true
+	case "IamMfaSerial":
+		return "iam_mfa_serial", true
+	case "IamWebIdentityToken":
+		return "iam_web_identity_token", true
 	case "Inputs":
 		return "inputs", true
 	case "Locals":
@@ -215,6 +277,8 @@ func terragruntConfigStructFieldToMapKey(t *testing.T, fieldName string) (string
 		return "", false
 	case "ProcessedIncludes":
 		return "", false
+	case "DeclaredFeatures":
+		return "", false
 	case "FieldsMetadata":
 		return "", false
 	case "RetryableErrors":
@@ -223,8 +287,28 @@ func terragruntConfigStructFieldToMapKey(t *testing.T, fieldName string) (string
 		return "retry_max_attempts", true
 	case "RetrySleepIntervalSec":
 		return "retry_sleep_interval_sec", true
+	case "RetryConfigs":
+		return "retry", true
 	case "DependentModulesPath":
 		return "dependent_modules", true
+	case "Priority":
+		return "priority", true
+	case "Tags":
+		return "tags", true
+	case "Validations":
+		return "", false
+	case "Exclude":
+		return "exclude", true
+	case "StrictMockOutputs":
+		return "strict_mock_outputs", true
+	case "HookSets":
+		return "hook_set", true
+	case "UseHooks":
+		return "use_hooks", true
+	case "SkipPropagatedHooks":
+		return "skip_propagated_hooks", true
+	case "CustomCommands":
+		return "commands", true
 	default:
 		t.Fatalf("Unknown struct property: %s", fieldName)
 		// This should not execute
@@ -244,6 +328,10 @@ func remoteStateStructFieldToMapKey(t *testing.T, fieldName string) (string, boo
 		return "generate", true
 	case "Config":
 		return "config", true
+	case "Encryption":
+		return "encryption", true
+	case "Snapshot":
+		return "snapshot", true
 	default:
 		t.Fatalf("Unknown struct property: %s", fieldName)
 		// This should not execute
@@ -0,0 +1,183 @@
+package config
+
+import (
+	"bytes"
+
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// hclBlockSpec describes how an attribute of the cty representation of the config is rendered as a block in HCL.
+type hclBlockSpec struct {
+	// Labeled blocks are represented in cty as a map of the label to the block, such as the `dependency` blocks.
+	Labeled bool
+	// The nested blocks of the block.
+	Blocks map[string]hclBlockSpec
+}
+
+// hclBlocks are the attributes of the cty representation of the config that are rendered as blocks, in the order they
+// are rendered in. All the other attributes are rendered as attributes, after the blocks, with inputs last.
+var hclBlocks = []string{
+	MetadataLocals,
+	MetadataTerraform,
+	MetadataRemoteState,
+	MetadataDependencies,
+	MetadataDependency,
+	MetadataGenerateConfigs,
+	MetadataRetry,
+	MetadataExclude,
+	MetadataHookSet,
+}
+
+// hookBlockSpecs are the nested blocks of the hooks.
+var hookBlockSpecs = map[string]hclBlockSpec{
+	"if_plan": {},
+}
+
+var hclBlockSpecs = map[string]hclBlockSpec{
+	MetadataLocals: {},
+	MetadataTerraform: {Blocks: map[string]hclBlockSpec{
+		"extra_arguments": {Labeled: true},
+		"before_hook":     {Labeled: true},
+		"after_hook":      {Labeled: true, Blocks: hookBlockSpecs},
+		"error_hook":      {Labeled: true},
+		"on_success_hook": {Labeled: true, Blocks: hookBlockSpecs},
+		"on_failure_hook": {Labeled: true, Blocks: hookBlockSpecs},
+		"on_skip_hook":    {Labeled: true},
+	}},
+	MetadataRemoteState:     {},
+	MetadataDependencies:    {},
+	MetadataDependency:      {Labeled: true},
+	MetadataGenerateConfigs: {Labeled: true},
+	MetadataRetry:           {Labeled: true},
+	MetadataExclude:         {},
+	MetadataHookSet: {Labeled: true, Blocks: map[string]hclBlockSpec{
+		"before_hook":     {Labeled: true},
+		"after_hook":      {Labeled: true, Blocks: hookBlockSpecs},
+		"error_hook":      {Labeled: true},
+		"on_success_hook": {Labeled: true, Blocks: hookBlockSpecs},
+		"on_failure_hook": {Labeled: true, Blocks: hookBlockSpecs},
+		"on_skip_hook":    {Labeled: true},
+	}},
+}
+
+// TerragruntConfigAsHcl renders the given config, with all its includes merged and functions resolved, as formatted
+// HCL. Attributes that are not set are omitted.
+func TerragruntConfigAsHcl(config *TerragruntConfig) ([]byte, error) {
+	configCty, err := TerragruntConfigAsCty(config)
+	if err != nil {
+		return nil, err
+	}
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	values := configCty.AsValueMap()
+
+	for _, name := range hclBlocks {
+		value, found := values[name]
+		if !found || !isRenderedValue(value) {
+			continue
+		}
+		writeHclBlock(body, name, hclBlockSpecs[name], value)
+	}
+
+	attributes := map[string]cty.Value{}
+	for name, value := range values {
+		if _, isBlock := hclBlockSpecs[name]; !isBlock && name != MetadataInputs {
+			attributes[name] = value
+		}
+	}
+	if len(attributes) > 0 {
+		body.AppendNewline()
+		writeHclAttributes(body, cty.ObjectVal(attributes), "")
+	}
+
+	if inputs, found := values[MetadataInputs]; found && isRenderedValue(inputs) {
+		body.AppendNewline()
+		body.SetAttributeValue(MetadataInputs, inputs)
+	}
+
+	// Every group of blocks and attributes is preceded by a blank line, which is not needed for the first one.
+	return hclwrite.Format(bytes.TrimLeft(file.Bytes(), "\n")), nil
+}
+
+// writeHclBlock appends the given value as a block to the given body, or as one block per label if the block is
+// labeled.
+func writeHclBlock(body *hclwrite.Body, name string, spec hclBlockSpec, value cty.Value) {
+	if !spec.Labeled {
+		body.AppendNewline()
+		writeHclBlockBody(body.AppendNewBlock(name, nil).Body(), spec, value, "")
+		return
+	}
+
+	for it := value.ElementIterator(); it.Next(); {
+		label, blockValue := it.Element()
+		if !isRenderedValue(blockValue) {
+			continue
+		}
+		body.AppendNewline()
+		writeHclBlockBody(body.AppendNewBlock(name, []string{label.AsString()}).Body(), spec, blockValue, label.AsString())
+	}
+}
+
+// writeHclBlockBody renders the given value as the body of a block, rendering the attributes of the value that are
+// nested blocks as blocks, and the others as attributes.
+func writeHclBlockBody(body *hclwrite.Body, spec hclBlockSpec, value cty.Value, label string) {
+	writeHclAttributes(body, value, label)
+	for it := value.ElementIterator(); it.Next(); {
+		name, nestedValue := it.Element()
+		if nestedSpec, isBlock := spec.Blocks[name.AsString()]; isBlock && isRenderedValue(nestedValue) {
+			writeHclBlock(body, name.AsString(), nestedSpec, nestedValue)
+		}
+	}
+}
+
+// writeHclAttributes renders the attributes of the given object that are set, except for the nested blocks, and the
+// name of labeled blocks, which is rendered as the label.
+func writeHclAttributes(body *hclwrite.Body, value cty.Value, label string) {
+	for it := value.ElementIterator(); it.Next(); {
+		name, attrValue := it.Element()
+		if !isRenderedValue(attrValue) {
+			continue
+		}
+		if label != "" && name.AsString() == "name" {
+			continue
+		}
+		if attrValue.Type().IsObjectType() || attrValue.Type().IsMapType() {
+			if _, isBlock := nestedBlockNames[name.AsString()]; isBlock {
+				continue
+			}
+		}
+		body.SetAttributeValue(name.AsString(), attrValue)
+	}
+}
+
+// nestedBlockNames are the names of all the nested blocks, which are skipped when writing attributes.
+var nestedBlockNames = func() map[string]bool {
+	names := map[string]bool{}
+	var addNames func(specs map[string]hclBlockSpec)
+	addNames = func(specs map[string]hclBlockSpec) {
+		for name, spec := range specs {
+			names[name] = true
+			addNames(spec.Blocks)
+		}
+	}
+	for _, spec := range hclBlockSpecs {
+		addNames(spec.Blocks)
+	}
+	return names
+}()
+
+// isRenderedValue returns true if the given value is set, and so is rendered.
+func isRenderedValue(value cty.Value) bool {
+	if value.IsNull() || !value.IsKnown() {
+		return false
+	}
+	if value.Type() == cty.String && value.AsString() == "" {
+		return false
+	}
+	if (value.Type().IsObjectType() || value.Type().IsMapType()) && value.LengthInt() == 0 {
+		return false
+	}
+	return true
+}
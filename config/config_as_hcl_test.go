@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTerragruntConfigAsHcl(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  region = "us-east-1"
+}
+
+terraform {
+  source = "git::https://github.com/acme/modules.git//vpc?ref=v1.0.0"
+
+  before_hook "echo" {
+    commands = ["plan"]
+    execute  = ["echo", local.region]
+  }
+
+  after_hook "approve" {
+    commands = ["plan"]
+    execute  = ["./approve.sh"]
+
+    if_plan {
+      actions = ["delete"]
+    }
+  }
+}
+
+retry "network" {
+  retryable_errors = [".*timeout.*"]
+  max_attempts     = 3
+}
+
+prevent_destroy = true
+
+inputs = {
+  region = local.region
+  zones  = [for zone in ["a", "b"] : "${local.region}${zone}"]
+}
+`
+	opts := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	terragruntConfig, err := ParseConfigString(config, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	rendered, err := TerragruntConfigAsHcl(terragruntConfig)
+	require.NoError(t, err)
+
+	expected := `locals {
+  region = "us-east-1"
+}
+
+terraform {
+  source = "git::https://github.com/acme/modules.git//vpc?ref=v1.0.0"
+
+  after_hook "approve" {
+    commands = ["plan"]
+    execute  = ["./approve.sh"]
+
+    if_plan {
+      actions = ["delete"]
+    }
+  }
+
+  before_hook "echo" {
+    commands = ["plan"]
+    execute  = ["echo", "us-east-1"]
+  }
+}
+
+retry "network" {
+  max_attempts     = 3
+  retryable_errors = [".*timeout.*"]
+}
+
+prevent_destroy = true
+skip            = false
+
+inputs = {
+  region = "us-east-1"
+  zones  = ["us-east-1a", "us-east-1b"]
+}
+`
+	assert.Equal(t, expected, string(rendered))
+
+	// The rendered config is a valid config, which resolves to the same config.
+	reparsedConfig, err := ParseConfigString(string(rendered), opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	assert.Equal(t, terragruntConfig.Inputs, reparsedConfig.Inputs)
+	assert.Equal(t, terragruntConfig.Terraform.BeforeHooks, reparsedConfig.Terraform.BeforeHooks)
+	assert.Equal(t, terragruntConfig.Terraform.AfterHooks, reparsedConfig.Terraform.AfterHooks)
+	assert.Equal(t, terragruntConfig.RetryConfigs, reparsedConfig.RetryConfigs)
+}
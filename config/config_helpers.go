@@ -8,15 +8,19 @@ import (
 	"regexp"
 	"runtime"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	"go.mozilla.org/sops/v3/cmd/sops/formats"
 
+	"github.com/BurntSushi/toml"
 	"github.com/hashicorp/go-getter"
 	"github.com/hashicorp/hcl/v2"
 	tflang "github.com/hashicorp/terraform/lang"
+	ctyyaml "github.com/zclconf/go-cty-yaml"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/function"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
 	"go.mozilla.org/sops/v3/decrypt"
 
 	"github.com/gruntwork-io/go-commons/errors"
@@ -59,6 +63,9 @@ const (
 	FuncNameGetTerragruntSourceCLIFlag              = "get_terragrunt_source_cli_flag"
 	FuncNameGetDefaultRetryableErrors               = "get_default_retryable_errors"
 	FuncNameReadTfvarsFile                          = "read_tfvars_file"
+	FuncNameReadYaml                                = "read_yaml"
+	FuncNameReadToml                                = "read_toml"
+	FuncNameReadJsonWithDefault                     = "read_json_with_default"
 	FuncNameGetWorkingDir                           = "get_working_dir"
 	FuncNameStartsWith                              = "startswith"
 	FuncNameEndsWith                                = "endswith"
@@ -134,6 +141,15 @@ type EvalContextExtensions struct {
 	// Locals are preevaluated variable bindings that can be used by reference in the code.
 	Locals *cty.Value
 
+	// Features are the values of the feature flags declared in the config, exposed as `feature.<name>.value`.
+	Features *cty.Value
+
+	// Variables are the values of the variables declared in the config, exposed as `var.<name>`.
+	Variables *cty.Value
+
+	// Imports are the configs imported with import blocks, exposed as `import.<name>`.
+	Imports *cty.Value
+
 	// DecodedDependencies are references of other terragrunt config. This contains the following attributes that map to
 	// various fields related to that config:
 	// - outputs: The map of outputs from the terraform state obtained by running `terragrunt output` on that target
@@ -183,6 +199,9 @@ func (extensions EvalContextExtensions) CreateTerragruntEvalContext(filename str
 		FuncNameGetTerragruntSourceCLIFlag:              wrapVoidToStringAsFuncImpl(getTerragruntSourceCliFlag, extensions.TrackInclude, terragruntOptions),
 		FuncNameGetDefaultRetryableErrors:               wrapVoidToStringSliceAsFuncImpl(getDefaultRetryableErrors, extensions.TrackInclude, terragruntOptions),
 		FuncNameReadTfvarsFile:                          wrapStringSliceToStringAsFuncImpl(readTFVarsFile, extensions.TrackInclude, terragruntOptions),
+		FuncNameReadYaml:                                readDataFileAsFuncImpl(FuncNameReadYaml, decodeYaml, false, terragruntOptions),
+		FuncNameReadToml:                                readDataFileAsFuncImpl(FuncNameReadToml, decodeToml, false, terragruntOptions),
+		FuncNameReadJsonWithDefault:                     readDataFileAsFuncImpl(FuncNameReadJsonWithDefault, decodeJson, true, terragruntOptions),
 		FuncNameGetWorkingDir:                           wrapVoidToStringAsFuncImpl(getWorkingDir, extensions.TrackInclude, terragruntOptions),
 
 		// Map with HCL functions introduced in Terraform after v0.15.3, since upgrade to a later version is not supported
@@ -211,9 +230,19 @@ func (extensions EvalContextExtensions) CreateTerragruntEvalContext(filename str
 	if extensions.Locals != nil {
 		ctx.Variables["local"] = *extensions.Locals
 	}
+	if extensions.Features != nil {
+		ctx.Variables["feature"] = *extensions.Features
+	}
+	if extensions.Variables != nil {
+		ctx.Variables["var"] = *extensions.Variables
+	}
+	if extensions.Imports != nil {
+		ctx.Variables["import"] = *extensions.Imports
+	}
 	if extensions.DecodedDependencies != nil {
 		ctx.Variables["dependency"] = *extensions.DecodedDependencies
 	}
+	ctx.Variables[hookOutputVariable] = hookOutputsAsCty(terragruntOptions)
 	if extensions.TrackInclude != nil && len(extensions.TrackInclude.CurrentList) > 0 {
 		// For each include block, check if we want to expose the included config, and if so, add under the include
 		// variable.
@@ -327,66 +356,198 @@ func parseGetEnvParameters(parameters []string) (EnvVar, error) {
 
 // runCommandCache - cache of evaluated `run_cmd` invocations
 // see: https://github.com/gruntwork-io/terragrunt/issues/1427
-var runCommandCache = NewStringCache()
+var runCommandCache = NewExpiringStringCache()
+
+// The special arguments that can be passed as the first arguments of `run_cmd` to control how the command is run.
+const (
+	runCmdQuietArg       = "--terragrunt-quiet"
+	runCmdGlobalCacheArg = "--terragrunt-global-cache"
+	runCmdNoCacheArg     = "--terragrunt-no-cache"
+	runCmdCacheKeyArg    = "--terragrunt-cache-key="
+	runCmdCacheTTLArg    = "--terragrunt-cache-ttl="
+	runCmdWorkingDirArg  = "--terragrunt-working-dir="
+	runCmdEnvAllowArg    = "--terragrunt-env-allow="
+	runCmdTimeoutArg     = "--terragrunt-timeout="
+)
+
+// runCmdOptions are the options of a `run_cmd` invocation, set with the special arguments passed before the command.
+type runCmdOptions struct {
+	suppressOutput bool
+	globalCache    bool
+	noCache        bool
+	cacheKey       string
+	cacheTTL       time.Duration
+	workingDir     string
+	envAllowList   []string
+	timeout        time.Duration
+}
+
+// parseRunCmdOptions parses the special arguments at the beginning of the given `run_cmd` arguments, and returns the
+// options along with the remaining arguments, which are the command and its arguments.
+func parseRunCmdOptions(args []string) (*runCmdOptions, []string, error) {
+	opts := &runCmdOptions{}
+	for len(args) > 0 {
+		arg := args[0]
+		switch {
+		case arg == runCmdQuietArg:
+			opts.suppressOutput = true
+		case arg == runCmdGlobalCacheArg:
+			opts.globalCache = true
+		case arg == runCmdNoCacheArg:
+			opts.noCache = true
+		case strings.HasPrefix(arg, runCmdCacheKeyArg):
+			opts.cacheKey = strings.TrimPrefix(arg, runCmdCacheKeyArg)
+			if opts.cacheKey == "" {
+				return nil, nil, errors.WithStackTrace(EmptyStringNotAllowed("value of " + runCmdCacheKeyArg))
+			}
+		case strings.HasPrefix(arg, runCmdCacheTTLArg):
+			ttl, err := parseRunCmdDuration(arg, runCmdCacheTTLArg)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts.cacheTTL = ttl
+		case strings.HasPrefix(arg, runCmdWorkingDirArg):
+			opts.workingDir = strings.TrimPrefix(arg, runCmdWorkingDirArg)
+			if opts.workingDir == "" {
+				return nil, nil, errors.WithStackTrace(EmptyStringNotAllowed("value of " + runCmdWorkingDirArg))
+			}
+		case strings.HasPrefix(arg, runCmdEnvAllowArg):
+			for _, name := range strings.Split(strings.TrimPrefix(arg, runCmdEnvAllowArg), ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					opts.envAllowList = append(opts.envAllowList, name)
+				}
+			}
+			// An empty allow list is kept non-nil, so that the command runs without any environment variable.
+			if opts.envAllowList == nil {
+				opts.envAllowList = []string{}
+			}
+		case strings.HasPrefix(arg, runCmdTimeoutArg):
+			timeout, err := parseRunCmdDuration(arg, runCmdTimeoutArg)
+			if err != nil {
+				return nil, nil, err
+			}
+			opts.timeout = timeout
+		default:
+			return opts, args, nil
+		}
+		args = args[1:]
+	}
+	return opts, args, nil
+}
+
+func parseRunCmdDuration(arg string, prefix string) (time.Duration, error) {
+	value := strings.TrimPrefix(arg, prefix)
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, errors.WithStackTrace(InvalidRunCmdDuration{Arg: strings.TrimSuffix(prefix, "="), Value: value})
+	}
+	return duration, nil
+}
 
 // runCommand is a helper function that runs a command and returns the stdout as the interporation
 // for each `run_cmd` in locals section, function is called twice
 // result
 func runCommand(args []string, trackInclude *TrackInclude, terragruntOptions *options.TerragruntOptions) (string, error) {
+	runCmdOpts, args, err := parseRunCmdOptions(args)
+	if err != nil {
+		return "", err
+	}
 	if len(args) == 0 {
 		return "", errors.WithStackTrace(EmptyStringNotAllowed("parameter to the run_cmd function"))
 	}
 
-	suppressOutput := false
 	currentPath := filepath.Dir(terragruntOptions.TerragruntConfigPath)
-	cachePath := currentPath
-
-	checkOptions := true
-	for checkOptions && len(args) > 0 {
-		switch args[0] {
-		case "--terragrunt-quiet":
-			suppressOutput = true
-			args = append(args[:0], args[1:]...)
-		case "--terragrunt-global-cache":
-			cachePath = "_global_"
-			args = append(args[:0], args[1:]...)
-		default:
-			checkOptions = false
+	workingDir := currentPath
+	if runCmdOpts.workingDir != "" {
+		workingDir = runCmdOpts.workingDir
+		if !filepath.IsAbs(workingDir) {
+			workingDir = util.JoinPath(currentPath, workingDir)
 		}
 	}
 
 	// To avoid re-run of the same run_cmd command, is used in memory cache for command results, with caching key path + arguments
 	// see: https://github.com/gruntwork-io/terragrunt/issues/1427
-	cacheKey := fmt.Sprintf("%v-%v", cachePath, args)
-	cachedValue, foundInCache := runCommandCache.Get(cacheKey)
-	if foundInCache {
-		if suppressOutput {
-			terragruntOptions.Logger.Debugf("run_cmd, cached output: [REDACTED]")
-		} else {
-			terragruntOptions.Logger.Debugf("run_cmd, cached output: [%s]", cachedValue)
+	cacheKey := runCommandCacheKey(runCmdOpts, currentPath, workingDir, args)
+	if !runCmdOpts.noCache {
+		cachedValue, foundInCache := runCommandCache.Get(cacheKey, runCmdOpts.cacheTTL)
+		if foundInCache {
+			if runCmdOpts.suppressOutput {
+				terragruntOptions.Logger.Debugf("run_cmd, cached output: [REDACTED]")
+			} else {
+				terragruntOptions.Logger.Debugf("run_cmd, cached output: [%s]", util.RedactSecrets(cachedValue))
+			}
+			return cachedValue, nil
 		}
-		return cachedValue, nil
 	}
 
-	cmdOutput, err := shell.RunShellCommandWithOutput(terragruntOptions, currentPath, suppressOutput, false, args[0], args[1:]...)
+	cmdOptions := terragruntOptions
+	if runCmdOpts.envAllowList != nil || runCmdOpts.timeout > 0 {
+		cmdOptions = terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+		if runCmdOpts.envAllowList != nil {
+			cmdOptions.Env = allowedEnvVars(terragruntOptions.Env, runCmdOpts.envAllowList)
+		}
+		if runCmdOpts.timeout > 0 {
+			deadline := time.Now().Add(runCmdOpts.timeout)
+			if cmdOptions.Deadline.IsZero() || deadline.Before(cmdOptions.Deadline) {
+				cmdOptions.Deadline = deadline
+			}
+		}
+	}
+
+	cmdOutput, err := shell.RunShellCommandWithOutput(cmdOptions, workingDir, runCmdOpts.suppressOutput, false, args[0], args[1:]...)
 	if err != nil {
 		return "", errors.WithStackTrace(err)
 	}
 
 	value := strings.TrimSuffix(cmdOutput.Stdout, "\n")
 
-	if suppressOutput {
+	if runCmdOpts.suppressOutput {
 		terragruntOptions.Logger.Debugf("run_cmd output: [REDACTED]")
 	} else {
-		terragruntOptions.Logger.Debugf("run_cmd output: [%s]", value)
+		terragruntOptions.Logger.Debugf("run_cmd output: [%s]", util.RedactSecrets(value))
 	}
 
 	// Persisting result in cache to avoid future re-evaluation
 	// see: https://github.com/gruntwork-io/terragrunt/issues/1427
-	runCommandCache.Put(cacheKey, value)
+	if !runCmdOpts.noCache {
+		runCommandCache.Put(cacheKey, value)
+	}
 	return value, nil
 }
 
+// runCommandCacheKey returns the key under which the output of the given `run_cmd` invocation is cached. An explicit
+// cache key is shared by all the configs, so that the command is only run once across a run-all.
+func runCommandCacheKey(runCmdOpts *runCmdOptions, currentPath string, workingDir string, args []string) string {
+	if runCmdOpts.cacheKey != "" {
+		return fmt.Sprintf("_key_-%s", runCmdOpts.cacheKey)
+	}
+
+	cachePath := currentPath
+	if runCmdOpts.globalCache {
+		cachePath = "_global_"
+	}
+	cacheKey := fmt.Sprintf("%v-%v", cachePath, args)
+	// The working dir and the allowed env vars change the output of the command, so they are part of the key when set.
+	if workingDir != currentPath {
+		cacheKey = fmt.Sprintf("%s-dir=%s", cacheKey, workingDir)
+	}
+	if runCmdOpts.envAllowList != nil {
+		cacheKey = fmt.Sprintf("%s-env=%v", cacheKey, runCmdOpts.envAllowList)
+	}
+	return cacheKey
+}
+
+// allowedEnvVars returns the env vars of the given ones whose names are in the given allow list.
+func allowedEnvVars(env map[string]string, allowList []string) map[string]string {
+	allowed := map[string]string{}
+	for _, name := range allowList {
+		if value, ok := env[name]; ok {
+			allowed[name] = value
+		}
+	}
+	return allowed
+}
+
 func getEnvironmentVariable(parameters []string, trackInclude *TrackInclude, terragruntOptions *options.TerragruntOptions) (string, error) {
 	parameterMap, err := parseGetEnvParameters(parameters)
 
@@ -788,6 +949,7 @@ func sopsDecryptFile(params []string, trackInclude *TrackInclude, terragruntOpti
 
 	if utf8.Valid(rawData) {
 		value := string(rawData)
+		registerSopsSecrets(value, format)
 		sopsCache.Put(canonicalSourceFile, value)
 		return value, nil
 	}
@@ -795,6 +957,34 @@ func sopsDecryptFile(params []string, trackInclude *TrackInclude, terragruntOpti
 	return "", errors.WithStackTrace(InvalidSopsFormat{SourceFilePath: sourceFile})
 }
 
+// registerSopsSecrets marks the values of a decrypted sops file as secret, so that they are redacted from the logs, for
+// example when they are passed to terraform as arguments or come back as outputs of a dependency.
+func registerSopsSecrets(decrypted string, format string) {
+	switch format {
+	case "json", "yaml":
+		// JSON is valid YAML, so both formats can be parsed with the YAML parser.
+		value, err := ctyyaml.Standard.Unmarshal([]byte(decrypted), cty.DynamicPseudoType)
+		if err != nil {
+			util.RegisterSecret(decrypted)
+			return
+		}
+		_ = cty.Walk(value, func(_ cty.Path, v cty.Value) (bool, error) {
+			if v.IsKnown() && !v.IsNull() && v.Type() == cty.String {
+				util.RegisterSecret(v.AsString())
+			}
+			return true, nil
+		})
+	case "dotenv", "ini":
+		for _, line := range strings.Split(decrypted, "\n") {
+			if _, value, found := strings.Cut(line, "="); found {
+				util.RegisterSecret(value)
+			}
+		}
+	default:
+		util.RegisterSecret(decrypted)
+	}
+}
+
 // Mapping of SOPS format to string
 var sopsFormatToString = map[formats.Format]string{
 	formats.Binary: "binary",
@@ -970,8 +1160,86 @@ func readTFVarsFile(args []string, trackInclude *TrackInclude, terragruntOptions
 	return string(data), nil
 }
 
+// Create a cty Function that reads a data file, at a path relative to the working directory like read_tfvars_file, and decodes it into a cty
+// value with the given decode function. When withDefault is true, the function takes a second param that is returned
+// if the file does not exist.
+func readDataFileAsFuncImpl(funcName string, decode func(contents []byte) (cty.Value, error), withDefault bool, terragruntOptions *options.TerragruntOptions) function.Function {
+	params := []function.Parameter{{Name: "path", Type: cty.String}}
+	if withDefault {
+		params = append(params, function.Parameter{Name: "default", Type: cty.DynamicPseudoType, AllowNull: true})
+	}
+
+	return function.New(&function.Spec{
+		Params: params,
+		// We don't know the return type until we decode the file, so we use a dynamic type
+		Type: function.StaticReturnType(cty.DynamicPseudoType),
+		Impl: func(args []cty.Value, retType cty.Type) (cty.Value, error) {
+			dataFile, err := util.CanonicalPath(args[0].AsString(), terragruntOptions.WorkingDir)
+			if err != nil {
+				return cty.NilVal, errors.WithStackTrace(err)
+			}
+			if !util.FileExists(dataFile) {
+				if withDefault {
+					return args[1], nil
+				}
+				return cty.NilVal, errors.WithStackTrace(DataFileNotFoundError{Func: funcName, File: dataFile})
+			}
+
+			contents, err := os.ReadFile(dataFile)
+			if err != nil {
+				return cty.NilVal, errors.WithStackTrace(fmt.Errorf("could not read file %q: %w", dataFile, err))
+			}
+
+			value, err := decode(contents)
+			if err != nil {
+				return cty.NilVal, errors.WithStackTrace(DataFileDecodeError{Func: funcName, File: dataFile, Err: err})
+			}
+			return value, nil
+		},
+	})
+}
+
+func decodeYaml(contents []byte) (cty.Value, error) {
+	return ctyyaml.Standard.Unmarshal(contents, cty.DynamicPseudoType)
+}
+
+func decodeToml(contents []byte) (cty.Value, error) {
+	var data map[string]interface{}
+	if err := toml.Unmarshal(contents, &data); err != nil {
+		return cty.NilVal, err
+	}
+	return convertToCtyWithJson(data)
+}
+
+func decodeJson(contents []byte) (cty.Value, error) {
+	impliedType, err := ctyjson.ImpliedType(contents)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	return ctyjson.Unmarshal(contents, impliedType)
+}
+
 // Custom error types
 
+type DataFileNotFoundError struct {
+	Func string
+	File string
+}
+
+func (e DataFileNotFoundError) Error() string {
+	return fmt.Sprintf("%s: file %s does not exist", e.Func, e.File)
+}
+
+type DataFileDecodeError struct {
+	Func string
+	File string
+	Err  error
+}
+
+func (e DataFileDecodeError) Error() string {
+	return fmt.Sprintf("%s: could not decode file %s: %v", e.Func, e.File, e.Err)
+}
+
 type TFVarFileNotFoundError struct {
 	File  string
 	Cause string
@@ -1010,6 +1278,15 @@ func (err ParentFileNotFound) Error() string {
 	return fmt.Sprintf("ParentFileNotFound: Could not find a %s in any of the parent folders of %s. Cause: %s.", err.File, err.Path, err.Cause)
 }
 
+type InvalidRunCmdDuration struct {
+	Arg   string
+	Value string
+}
+
+func (err InvalidRunCmdDuration) Error() string {
+	return fmt.Sprintf("The value of %s for run_cmd must be a positive duration, such as 30s or 5m, got %q.", err.Arg, err.Value)
+}
+
 type InvalidGetEnvParams struct {
 	ActualNumParams int
 	Example         string
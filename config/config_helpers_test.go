@@ -5,10 +5,13 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/test/helpers"
+	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/zclconf/go-cty/cty"
@@ -207,11 +210,35 @@ func TestRunCommand(t *testing.T) {
 			nil,
 		},
 		{
+			[]string{"--terragrunt-no-cache", "--terragrunt-working-dir=/", "/bin/bash", "-c", "pwd"},
+			terragruntOptionsForTest(t, homeDir),
+			"/",
 			nil,
+		},
+		{
+			nil,
+			terragruntOptionsForTest(t, homeDir),
+			"",
+			EmptyStringNotAllowed("{run_cmd()}"),
+		},
+		{
+			[]string{"--terragrunt-quiet"},
 			terragruntOptionsForTest(t, homeDir),
 			"",
 			EmptyStringNotAllowed("{run_cmd()}"),
 		},
+		{
+			[]string{"--terragrunt-cache-ttl=soon", "/bin/bash", "-c", "echo foo"},
+			terragruntOptionsForTest(t, homeDir),
+			"",
+			InvalidRunCmdDuration{},
+		},
+		{
+			[]string{"--terragrunt-timeout=-1s", "/bin/bash", "-c", "echo foo"},
+			terragruntOptionsForTest(t, homeDir),
+			"",
+			InvalidRunCmdDuration{},
+		},
 	}
 	for _, testCase := range testCases {
 		t.Run(testCase.terragruntOptions.TerragruntConfigPath, func(t *testing.T) {
@@ -228,6 +255,58 @@ func TestRunCommand(t *testing.T) {
 	}
 }
 
+func TestRunCommandCacheOptions(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := terragruntOptionsForTest(t, os.Getenv("HOME"))
+	// The command prints a different value on each run, so that the cached values can be told apart.
+	command := []string{"/bin/bash", "-c", "echo " + t.Name() + " $RANDOM$RANDOM"}
+
+	runCmd := func(options ...string) string {
+		output, err := runCommand(append(options, command...), nil, terragruntOptions)
+		require.NoError(t, err)
+		return output
+	}
+
+	cached := runCmd()
+	assert.Equal(t, cached, runCmd())
+	assert.NotEqual(t, cached, runCmd("--terragrunt-no-cache"))
+
+	// An explicit cache key is shared by the commands regardless of their arguments.
+	keyed := runCmd("--terragrunt-cache-key=" + t.Name())
+	output, err := runCommand([]string{"--terragrunt-cache-key=" + t.Name(), "/bin/bash", "-c", "echo other"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, keyed, output)
+
+	expiring := runCmd("--terragrunt-cache-ttl=10ms")
+	time.Sleep(20 * time.Millisecond)
+	assert.NotEqual(t, expiring, runCmd("--terragrunt-cache-ttl=10ms"))
+}
+
+func TestRunCommandEnvAllowList(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := terragruntOptionsForTest(t, os.Getenv("HOME"))
+	terragruntOptions.Env = map[string]string{"ALLOWED": "foo", "SECRET": "bar"}
+
+	output, err := runCommand([]string{"--terragrunt-no-cache", "--terragrunt-env-allow=ALLOWED", "/bin/bash", "-c", "echo -n $ALLOWED-$SECRET"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, "foo-", output)
+
+	output, err = runCommand([]string{"--terragrunt-no-cache", "/bin/bash", "-c", "echo -n $ALLOWED-$SECRET"}, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, "foo-bar", output)
+}
+
+func TestRunCommandTimeout(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := terragruntOptionsForTest(t, os.Getenv("HOME"))
+	_, err := runCommand([]string{"--terragrunt-no-cache", "--terragrunt-timeout=100ms", "/bin/bash", "-c", "sleep 5"}, nil, terragruntOptions)
+	require.Error(t, err)
+	assert.IsType(t, shell.TimeoutExceeded{}, errors.Unwrap(err))
+}
+
 func absPath(t *testing.T, path string) string {
 	out, err := filepath.Abs(path)
 	require.NoError(t, err)
@@ -1150,6 +1229,76 @@ func TestReadTFVarsFiles(t *testing.T) {
 	assert.Equal(t, locals["json_bool_var"].(bool), false)
 }
 
+func TestReadDataFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "env.yaml"), []byte("region: us-east-1\nzones:\n  - a\n  - b\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "env.toml"), []byte("region = \"us-east-1\"\n\n[limits]\ninstances = 3\n"), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "env.json"), []byte(`{"region": "us-east-1", "enabled": true}`), 0644))
+
+	testCases := []struct {
+		config   string
+		expected map[string]interface{}
+	}{
+		{
+			`value = read_yaml("env.yaml")`,
+			map[string]interface{}{"region": "us-east-1", "zones": []interface{}{"a", "b"}},
+		},
+		{
+			`value = read_toml("env.toml")`,
+			map[string]interface{}{"region": "us-east-1", "limits": map[string]interface{}{"instances": float64(3)}},
+		},
+		{
+			`value = read_json_with_default("env.json", {})`,
+			map[string]interface{}{"region": "us-east-1", "enabled": true},
+		},
+		{
+			`value = read_json_with_default("missing.json", { region = "eu-west-1" })`,
+			map[string]interface{}{"region": "eu-west-1"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.config, func(t *testing.T) {
+			t.Parallel()
+
+			terragruntOptions := terragruntOptionsForTest(t, filepath.Join(dir, DefaultTerragruntConfigPath))
+			terragruntOptions.WorkingDir = dir
+
+			config := fmt.Sprintf("inputs = {\n  %s\n}", testCase.config)
+			terragruntConfig, err := ParseConfigString(config, terragruntOptions, nil, terragruntOptions.TerragruntConfigPath, &EvalContextExtensions{})
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, terragruntConfig.Inputs["value"])
+		})
+	}
+}
+
+func TestReadYamlMissingFile(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	_, err := ParseConfigString(`locals { env = read_yaml("does-not-exist.yaml") }`, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "read_yaml: file")
+}
+
+func TestRegisterSopsSecrets(t *testing.T) {
+	t.Parallel()
+
+	registerSopsSecrets(`{"db": {"password": "json-db-password"}, "port": 5432}`, "json")
+	registerSopsSecrets("api_key: yaml-api-key\n", "yaml")
+	registerSopsSecrets("TOKEN=dotenv-token\n", "dotenv")
+
+	assert.Equal(
+		t,
+		"-var password=[REDACTED] -var api_key=[REDACTED] -var token=[REDACTED] -var port=5432",
+		util.RedactSecrets("-var password=json-db-password -var api_key=yaml-api-key -var token=dotenv-token -var port=5432"),
+	)
+}
+
 func mockConfigWithSource(sourceUrl string) *TerragruntConfig {
 	cfg := TerragruntConfig{IsPartial: true}
 	cfg.Terraform = &TerraformConfig{Source: &sourceUrl}
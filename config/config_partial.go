@@ -3,6 +3,7 @@ package config
 import (
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
@@ -25,6 +26,9 @@ const (
 	TerragruntFlags
 	TerragruntVersionConstraints
 	RemoteStateBlock
+	TerragruntPriority
+	TerragruntTags
+	CustomCommandsBlock
 )
 
 // terragruntIncludeMultiple is a struct that can be used to only decode the include block with labels.
@@ -39,10 +43,15 @@ type terragruntDependencies struct {
 	Remain       hcl.Body            `hcl:",remain"`
 }
 
-// terragruntTerraform is a struct that can be used to only decode the terraform block
+// terragruntTerraform is a struct that can be used to only decode the terraform block, along with the hook sets that
+// add hooks to it.
 type terragruntTerraform struct {
 	Terraform *TerraformConfig `hcl:"terraform,block"`
+	HookSets  []HookSet        `hcl:"hook_set,block"`
+	UseHooks  []string         `hcl:"use_hooks,optional"`
 	Remain    hcl.Body         `hcl:",remain"`
+
+	SkipPropagatedHooks []string `hcl:"skip_propagated_hooks,optional"`
 }
 
 // terragruntTerraformSource is a struct that can be used to only decode the terraform block, and only the source
@@ -54,16 +63,39 @@ type terragruntTerraformSource struct {
 
 // terraformConfigSourceOnly is a struct that can be used to decode only the source attribute of the terraform block.
 type terraformConfigSourceOnly struct {
-	Source *string  `hcl:"source,attr"`
-	Remain hcl.Body `hcl:",remain"`
+	Source *cty.Value `hcl:"source,attr"`
+	Remain hcl.Body   `hcl:",remain"`
 }
 
 // terragruntFlags is a struct that can be used to only decode the flag attributes (skip and prevent_destroy)
 type terragruntFlags struct {
-	IamRole        *string  `hcl:"iam_role,attr"`
-	PreventDestroy *bool    `hcl:"prevent_destroy,attr"`
-	Skip           *bool    `hcl:"skip,attr"`
-	Remain         hcl.Body `hcl:",remain"`
+	IamRole             *cty.Value `hcl:"iam_role,attr"`
+	IamMfaSerial        *string    `hcl:"iam_mfa_serial,attr"`
+	IamWebIdentityToken *string    `hcl:"iam_web_identity_token,attr"`
+	PreventDestroy      *bool      `hcl:"prevent_destroy,attr"`
+	Skip                *bool      `hcl:"skip,attr"`
+	Remain              hcl.Body   `hcl:",remain"`
+}
+
+// terragruntPriority is a struct that can be used to only decode the priority attribute, which the run-all scheduler
+// needs before the modules are fully parsed.
+type terragruntPriority struct {
+	Priority *int     `hcl:"priority,attr"`
+	Remain   hcl.Body `hcl:",remain"`
+}
+
+// terragruntTags is a struct that can be used to only decode the tags attribute, which the list command filters the
+// modules on.
+type terragruntTags struct {
+	Tags   []string `hcl:"tags,optional"`
+	Remain hcl.Body `hcl:",remain"`
+}
+
+// terragruntCustomCommands is a struct that can be used to only decode the commands block, which is needed to run a
+// custom command before the config is fully parsed.
+type terragruntCustomCommands struct {
+	Commands *terragruntCommands `hcl:"commands,block"`
+	Remain   hcl.Body            `hcl:",remain"`
 }
 
 // terragruntVersionConstraints is a struct that can be used to only decode the attributes related to constraining the
@@ -77,8 +109,9 @@ type terragruntVersionConstraints struct {
 
 // terragruntDependency is a struct that can be used to only decode the dependency blocks in the terragrunt config
 type terragruntDependency struct {
-	Dependencies []Dependency `hcl:"dependency,block"`
-	Remain       hcl.Body     `hcl:",remain"`
+	Dependencies      []Dependency `hcl:"dependency,block"`
+	StrictMockOutputs *bool        `hcl:"strict_mock_outputs,attr"`
+	Remain            hcl.Body     `hcl:",remain"`
 }
 
 // terragruntRemoteState is a struct that can be used to only decode the remote_state blocks in the terragrunt config
@@ -87,11 +120,24 @@ type terragruntRemoteState struct {
 	Remain      hcl.Body               `hcl:",remain"`
 }
 
+// DecodedBaseBlocks are the bindings resulting from decoding the base blocks of a config.
+type DecodedBaseBlocks struct {
+	TrackInclude *TrackInclude
+	Locals       *cty.Value
+	Features     *cty.Value
+	Variables    *cty.Value
+	Imports      []ImportedConfig
+	ImportsAsCty *cty.Value
+}
+
 // DecodeBaseBlocks takes in a parsed HCL2 file and decodes the base blocks. Base blocks are blocks that should always
 // be decoded even in partial decoding, because they provide bindings that are necessary for parsing any block in the
 // file. Currently base blocks are:
 // - locals
 // - include
+// - feature
+// - variable
+// - import
 func DecodeBaseBlocks(
 	terragruntOptions *options.TerragruntOptions,
 	parser *hclparse.Parser,
@@ -99,12 +145,12 @@ func DecodeBaseBlocks(
 	filename string,
 	includeFromChild *IncludeConfig,
 	decodeList []PartialDecodeSectionType,
-) (*cty.Value, *TrackInclude, error) {
+) (*DecodedBaseBlocks, error) {
 	extensions := EvalContextExtensions{PartialParseDecodeList: decodeList}
 
 	evalContext, err := extensions.CreateTerragruntEvalContext(filename, terragruntOptions)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	// Decode just the `include` and `import` blocks, and verify that it's allowed here
@@ -114,12 +160,31 @@ func DecodeBaseBlocks(
 		evalContext,
 	)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
 	trackInclude, err := getTrackInclude(terragruntIncludeList, includeFromChild, terragruntOptions)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	imports, err := decodeImportBlocks(hclFile, filename, terragruntOptions, includeFromChild, evalContext)
+	if err != nil {
+		return nil, err
+	}
+	importsAsCty, err := importedConfigsAsCty(imports)
+	if err != nil {
+		return nil, err
+	}
+
+	// Evaluate the variable and feature blocks before the locals, so that locals can reference them.
+	variables, err := evaluateVariableBlocks(hclFile, filename, terragruntOptions, evalContext)
+	if err != nil {
+		return nil, err
+	}
+	features, err := evaluateFeatureBlocks(hclFile, filename, terragruntOptions, evalContext)
+	if err != nil {
+		return nil, err
 	}
 
 	// Evaluate all the expressions in the locals block separately and generate the variables list to use in the
@@ -130,17 +195,20 @@ func DecodeBaseBlocks(
 		hclFile,
 		filename,
 		trackInclude,
+		features,
+		variables,
+		importsAsCty,
 		decodeList,
 	)
 	if err != nil {
-		return nil, trackInclude, err
+		return nil, err
 	}
 	localsAsCty, err := convertValuesMapToCtyVal(locals)
 	if err != nil {
-		return nil, trackInclude, err
+		return nil, err
 	}
 
-	return &localsAsCty, trackInclude, nil
+	return &DecodedBaseBlocks{TrackInclude: trackInclude, Locals: &localsAsCty, Features: features, Variables: variables, Imports: imports, ImportsAsCty: importsAsCty}, nil
 }
 
 func PartialParseConfigFile(
@@ -148,6 +216,21 @@ func PartialParseConfigFile(
 	terragruntOptions *options.TerragruntOptions,
 	include *IncludeConfig,
 	decodeList []PartialDecodeSectionType,
+) (*TerragruntConfig, error) {
+	// The included configs are not cached on their own, as they are cached along with the configs that include them.
+	if include == nil && terragruntOptions.UsePersistentParseConfigCache && isPersistentlyCacheable(decodeList) {
+		return partialParseConfigFileWithPersistentCache(filename, terragruntOptions, decodeList, func() (*TerragruntConfig, error) {
+			return partialParseConfigFile(filename, terragruntOptions, include, decodeList)
+		})
+	}
+	return partialParseConfigFile(filename, terragruntOptions, include, decodeList)
+}
+
+func partialParseConfigFile(
+	filename string,
+	terragruntOptions *options.TerragruntOptions,
+	include *IncludeConfig,
+	decodeList []PartialDecodeSectionType,
 ) (*TerragruntConfig, error) {
 	configString, err := util.ReadFileAsString(filename)
 	if err != nil {
@@ -159,14 +242,24 @@ func PartialParseConfigFile(
 		return nil, err
 	}
 
+	if include == nil {
+		config, err = mergePartialEnvOverlay(config, filename, terragruntOptions, decodeList)
+		if err != nil {
+			return nil, err
+		}
+		if slices.Contains(decodeList, TerraformBlock) {
+			return applyHookSets(config, filename, terragruntOptions)
+		}
+	}
 	return config, nil
 }
 
 var terragruntConfigCache = NewTerragruntConfigCache()
 
 // Wrapper of PartialParseConfigString which checks for cached configs.
-// filename, configString, includeFromChild and decodeList are used for the cache key,
-// by getting the default value (%#v) through fmt.
+// filename, configString, includeFromChild, decodeList and the terraform command are used for the cache key,
+// by getting the default value (%#v) through fmt. The command is part of the key, as the config depends on it, e.g. the
+// role picked from an iam_role map.
 func TerragruntConfigFromPartialConfigString(
 	configString string,
 	terragruntOptions *options.TerragruntOptions,
@@ -175,7 +268,7 @@ func TerragruntConfigFromPartialConfigString(
 	decodeList []PartialDecodeSectionType,
 ) (*TerragruntConfig, error) {
 	if terragruntOptions.UsePartialParseConfigCache {
-		var cacheKey = fmt.Sprintf("%#v-%#v-%#v-%#v", filename, configString, includeFromChild, decodeList)
+		var cacheKey = fmt.Sprintf("%#v-%#v-%#v-%#v-%#v", filename, configString, includeFromChild, decodeList, terragruntOptions.TerraformCommand)
 		var config, found = terragruntConfigCache.Get(cacheKey)
 
 		if !found {
@@ -201,11 +294,13 @@ func TerragruntConfigFromPartialConfigString(
 // TerragruntConfig. Valid values are:
 //   - DependenciesBlock: Parses the `dependencies` block in the config
 //   - DependencyBlock: Parses the `dependency` block in the config
-//   - TerraformBlock: Parses the `terraform` block in the config
+//   - TerraformBlock: Parses the `terraform` block in the config, along with the `hook_set` blocks and `use_hooks`
 //   - TerragruntFlags: Parses the boolean flags `prevent_destroy` and `skip` in the config
 //   - TerragruntVersionConstraints: Parses the attributes related to constraining terragrunt and terraform versions in
 //     the config.
 //   - RemoteStateBlock: Parses the `remote_state` block in the config
+//   - TerragruntPriority: Parses the `priority` attribute in the config
+//   - TerragruntTags: Parses the `tags` attribute in the config
 //
 // Note that the following blocks are always decoded:
 // - locals
@@ -225,17 +320,24 @@ func PartialParseConfigString(
 	if err != nil {
 		return nil, err
 	}
+	if err := validateStrictConfig(file, filename, terragruntOptions); err != nil {
+		return nil, err
+	}
 
 	// Decode just the Base blocks. See the function docs for DecodeBaseBlocks for more info on what base blocks are.
-	localsAsCty, trackInclude, err := DecodeBaseBlocks(terragruntOptions, parser, file, filename, includeFromChild, decodeList)
+	baseBlocks, err := DecodeBaseBlocks(terragruntOptions, parser, file, filename, includeFromChild, decodeList)
 	if err != nil {
 		return nil, err
 	}
+	trackInclude := baseBlocks.TrackInclude
 
 	// Initialize evaluation context extensions from base blocks.
 	contextExtensions := EvalContextExtensions{
-		Locals:                 localsAsCty,
+		Locals:                 baseBlocks.Locals,
 		TrackInclude:           trackInclude,
+		Features:               baseBlocks.Features,
+		Variables:              baseBlocks.Variables,
+		Imports:                baseBlocks.ImportsAsCty,
 		PartialParseDecodeList: decodeList,
 	}
 
@@ -279,7 +381,16 @@ func PartialParseConfigString(
 			if err != nil {
 				return nil, err
 			}
+			if err := decoded.Terraform.decodeSource(); err != nil {
+				return nil, err
+			}
+			if err := validateHookSets(decoded.HookSets, filename); err != nil {
+				return nil, err
+			}
 			output.Terraform = decoded.Terraform
+			output.HookSets = decoded.HookSets
+			output.UseHooks = decoded.UseHooks
+			output.SkipPropagatedHooks = decoded.SkipPropagatedHooks
 
 		case TerraformSource:
 			decoded := terragruntTerraformSource{}
@@ -288,7 +399,10 @@ func PartialParseConfigString(
 				return nil, err
 			}
 			if decoded.Terraform != nil {
-				output.Terraform = &TerraformConfig{Source: decoded.Terraform.Source}
+				output.Terraform = &TerraformConfig{RawSource: decoded.Terraform.Source}
+				if err := output.Terraform.decodeSource(); err != nil {
+					return nil, err
+				}
 			}
 
 		case DependencyBlock:
@@ -298,6 +412,9 @@ func PartialParseConfigString(
 				return nil, err
 			}
 			output.TerragruntDependencies = decoded.Dependencies
+			if decoded.StrictMockOutputs != nil {
+				output.StrictMockOutputs = decoded.StrictMockOutputs
+			}
 
 			// Convert dependency blocks into module depenency lists. If we already decoded some dependencies,
 			// merge them in. Otherwise, set as the new list.
@@ -321,8 +438,49 @@ func PartialParseConfigString(
 				output.Skip = *decoded.Skip
 			}
 			if decoded.IamRole != nil {
-				output.IamRole = *decoded.IamRole
+				output.IamRole, output.IamRoles, err = decodeIamRole(*decoded.IamRole, terragruntOptions)
+				if err != nil {
+					return nil, err
+				}
+			}
+			if decoded.IamMfaSerial != nil {
+				output.IamMfaSerial = *decoded.IamMfaSerial
+			}
+			if decoded.IamWebIdentityToken != nil {
+				output.IamWebIdentityToken = *decoded.IamWebIdentityToken
+			}
+
+		case TerragruntPriority:
+			decoded := terragruntPriority{}
+			err := decodeHcl(file, filename, &decoded, evalContext)
+			if err != nil {
+				return nil, err
+			}
+			if decoded.Priority != nil {
+				output.Priority = decoded.Priority
+			}
+
+		case TerragruntTags:
+			decoded := terragruntTags{}
+			err := decodeHcl(file, filename, &decoded, evalContext)
+			if err != nil {
+				return nil, err
+			}
+			if decoded.Tags != nil {
+				output.Tags = decoded.Tags
+			}
+
+		case CustomCommandsBlock:
+			decoded := terragruntCustomCommands{}
+			err := decodeHcl(file, filename, &decoded, evalContext)
+			if err != nil {
+				return nil, err
+			}
+			customCommands, err := decodeCustomCommands(decoded.Commands, filename, evalContext)
+			if err != nil {
+				return nil, err
 			}
+			output.CustomCommands = customCommands
 
 		case TerragruntVersionConstraints:
 			decoded := terragruntVersionConstraints{}
@@ -359,6 +517,13 @@ func PartialParseConfigString(
 		}
 	}
 
+	// Merge the imported configs under this config, before the included configs are merged in.
+	merged, err := mergeImportedConfigs(&output, baseBlocks.Imports, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+	output = *merged
+
 	// If this file includes another, parse and merge the partial blocks.  Otherwise just return this config.
 	if len(trackInclude.CurrentList) > 0 {
 		config, err := handleIncludePartial(&output, trackInclude, terragruntOptions, decodeList)
@@ -99,6 +99,49 @@ func TestPartialParseOmittedItems(t *testing.T) {
 	assert.Nil(t, terragruntConfig.Locals)
 }
 
+func TestPartialParsePriority(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  base_priority = 5
+}
+
+priority = local.base_priority * 2
+
+terraform {
+  source = "../vpc"
+}
+`
+
+	terragruntConfig, err := PartialParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{TerragruntPriority})
+	require.NoError(t, err)
+	require.NotNil(t, terragruntConfig.Priority)
+	assert.Equal(t, 10, *terragruntConfig.Priority)
+	assert.Nil(t, terragruntConfig.Terraform)
+}
+
+func TestPartialParseTags(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  env = "prod"
+}
+
+tags = ["network", local.env]
+
+terraform {
+  source = "../vpc"
+}
+`
+
+	terragruntConfig, err := PartialParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{TerragruntTags})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"network", "prod"}, terragruntConfig.Tags)
+	assert.Nil(t, terragruntConfig.Terraform)
+}
+
 func TestPartialParseDoesNotResolveIgnoredBlockEvenInParent(t *testing.T) {
 	t.Parallel()
 
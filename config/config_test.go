@@ -5,9 +5,11 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
 	"github.com/gruntwork-io/terragrunt/util"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -130,6 +132,167 @@ remote_state {
 	}
 }
 
+func TestParseTerragruntHclConfigRemoteStateEncryption(t *testing.T) {
+	t.Parallel()
+
+	config := `
+remote_state {
+	backend = "s3"
+	generate = {
+		path      = "backend.tf"
+		if_exists = "overwrite_terragrunt"
+	}
+	config = {
+		bucket = "my-bucket"
+	}
+	encryption = {
+		key_provider = {
+			pbkdf2 = {
+				default = { passphrase = "correct-horse-battery-staple" }
+			}
+		}
+		method = {
+			aes_gcm = {
+				default = { keys = "pbkdf2.default" }
+			}
+		}
+		state = { method = "aes_gcm.default" }
+	}
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, map[string]interface{}{"method": "aes_gcm.default"}, terragruntConfig.RemoteState.Encryption["state"])
+	}
+
+	// The encryption can only be generated along with the backend.
+	configWithoutGenerate := `
+remote_state {
+	backend = "s3"
+	config = {
+		bucket = "my-bucket"
+	}
+	encryption = {
+		method = {
+			unencrypted = {
+				migration = {}
+			}
+		}
+	}
+}
+`
+
+	_, err = ParseConfigString(configWithoutGenerate, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), remote.ErrEncryptionWithoutGenerate.Error())
+}
+
+func TestParseTerragruntHclConfigRemoteStateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	config := `
+remote_state {
+	backend = "s3"
+	config = {
+		bucket = "my-bucket"
+	}
+	snapshot = {
+		bucket          = "my-snapshots"
+		prefix          = "prod/vpc"
+		region          = "us-east-1"
+		retention_count = 30
+	}
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, &remote.RemoteStateSnapshot{Bucket: "my-snapshots", Prefix: "prod/vpc", Region: "us-east-1", RetentionCount: 30}, terragruntConfig.RemoteState.Snapshot)
+	}
+}
+
+func TestParseTerragruntHclConfigRemoteStateEnvironments(t *testing.T) {
+	t.Parallel()
+
+	config := `
+remote_state {
+	backend = "s3"
+	config = {
+		bucket = "dev-state"
+		key    = "vpc/terraform.tfstate"
+		region = "us-east-1"
+	}
+	environment = "%s"
+	environments = {
+		dev = {}
+		prod = {
+			bucket   = "prod-state"
+			role_arn = "arn:aws:iam::123456789012:role/terragrunt"
+		}
+	}
+}
+`
+
+	terragruntConfig, err := ParseConfigString(fmt.Sprintf(config, "prod"), mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, map[string]interface{}{
+			"bucket":   "prod-state",
+			"key":      "vpc/terraform.tfstate",
+			"region":   "us-east-1",
+			"role_arn": "arn:aws:iam::123456789012:role/terragrunt",
+		}, terragruntConfig.RemoteState.Config)
+	}
+
+	terragruntConfig, err = ParseConfigString(fmt.Sprintf(config, "dev"), mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, "dev-state", terragruntConfig.RemoteState.Config["bucket"])
+	}
+
+	_, err = ParseConfigString(fmt.Sprintf(config, "stage"), mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), InvalidRemoteStateEnvironment{Environment: "stage", Environments: []string{"dev", "prod"}}.Error())
+}
+
+func TestParseTerragruntHclConfigRemoteStateAssumeRole(t *testing.T) {
+	t.Parallel()
+
+	config := `
+iam_role = "arn:aws:iam::222222222222:role/deploy"
+
+remote_state {
+	backend = "s3"
+	config = {
+		bucket = "my-bucket"
+		key    = "terraform.tfstate"
+		region = "us-east-1"
+	}
+	assume_role = {
+		role_arn     = "arn:aws:iam::111111111111:role/state"
+		session_name = "terragrunt"
+	}
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	// The role of the state is distinct from the role the resources are deployed with.
+	assert.Equal(t, "arn:aws:iam::222222222222:role/deploy", terragruntConfig.GetIAMRoleOptions().RoleARN)
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, map[string]interface{}{"role_arn": "arn:aws:iam::111111111111:role/state", "session_name": "terragrunt"}, terragruntConfig.RemoteState.Config["assume_role"])
+		assert.Equal(t, true, terragruntConfig.RemoteState.Config["assume_role_with_iam_role"])
+	}
+}
+
 func TestParseTerragruntJsonConfigRemoteStateFullConfig(t *testing.T) {
 	t.Parallel()
 
@@ -191,6 +354,82 @@ retryable_errors = [
 	}
 }
 
+func TestParseTerragruntHclConfigRetryBlocks(t *testing.T) {
+	t.Parallel()
+
+	config := `
+retry "rate_limits" {
+  retryable_errors       = [".*429 Too Many Requests.*"]
+  commands               = ["apply"]
+  max_attempts           = 5
+  sleep_interval_sec     = 2
+  max_sleep_interval_sec = 30
+  backoff_multiplier     = 2
+  jitter                 = true
+}
+
+retry "network" {
+  retryable_errors = [".*connection reset by peer.*"]
+}
+`
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	require.Len(t, terragruntConfig.RetryConfigs, 2)
+
+	rateLimits := terragruntConfig.RetryConfigs[0]
+	assert.Equal(t, "rate_limits", rateLimits.Name)
+	assert.Equal(t, []string{"apply"}, rateLimits.Commands)
+	assert.Equal(t, 5, *rateLimits.MaxAttempts)
+	assert.Equal(t, 2.0, *rateLimits.BackoffMultiplier)
+	assert.True(t, *rateLimits.Jitter)
+
+	policy, err := rateLimits.ToRetryPolicy(mockOptionsForTest(t).DefaultRetryPolicy())
+	require.NoError(t, err)
+	assert.Equal(t, 5, policy.MaxAttempts)
+	assert.Equal(t, 2*time.Second, policy.SleepInterval)
+	assert.Equal(t, 30*time.Second, policy.MaxSleepInterval)
+
+	network := terragruntConfig.RetryConfigs[1]
+	assert.Equal(t, "network", network.Name)
+	assert.Nil(t, network.MaxAttempts)
+
+	policy, err = network.ToRetryPolicy(mockOptionsForTest(t).DefaultRetryPolicy())
+	require.NoError(t, err)
+	assert.Equal(t, options.DEFAULT_RETRY_MAX_ATTEMPTS, policy.MaxAttempts)
+	assert.Equal(t, options.DEFAULT_RETRY_SLEEP_INTERVAL_SEC, policy.SleepInterval)
+}
+
+func TestParseTerragruntHclConfigDuplicatedRetryBlocks(t *testing.T) {
+	t.Parallel()
+
+	config := `
+retry "network" {
+  retryable_errors = [".*timeout.*"]
+}
+
+retry "network" {
+  retryable_errors = [".*connection reset by peer.*"]
+}
+`
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	var duplicatedErr DuplicatedRetryBlocks
+	require.ErrorAs(t, err, &duplicatedErr)
+	assert.Equal(t, []string{"network"}, duplicatedErr.BlockName)
+}
+
+func TestRetryConfigInvalidBackoffMultiplier(t *testing.T) {
+	t.Parallel()
+
+	multiplier := 0.5
+	retryConfig := RetryConfig{Name: "network", RetryableErrors: []string{".*"}, BackoffMultiplier: &multiplier}
+
+	_, err := retryConfig.ToRetryPolicy(mockOptionsForTest(t).DefaultRetryPolicy())
+	require.Error(t, err)
+}
+
 func TestParseTerragruntJsonConfigRetryConfiguration(t *testing.T) {
 	t.Parallel()
 
@@ -272,6 +511,94 @@ func TestParseIamAssumeRoleSessionName(t *testing.T) {
 	assert.Equal(t, "terragrunt-iam-assume-role-session-name", terragruntConfig.IamAssumeRoleSessionName)
 }
 
+func TestParseIamRoleByCommand(t *testing.T) {
+	t.Parallel()
+
+	config := `
+iam_role = {
+  plan     = "arn:aws:iam::111111111111:role/read-only"
+  validate = "arn:aws:iam::111111111111:role/read-only"
+  default  = "arn:aws:iam::111111111111:role/write"
+}
+`
+
+	testCases := []struct {
+		command  string
+		expected string
+	}{
+		{"plan", "arn:aws:iam::111111111111:role/read-only"},
+		{"validate", "arn:aws:iam::111111111111:role/read-only"},
+		{"apply", "arn:aws:iam::111111111111:role/write"},
+		{"", "arn:aws:iam::111111111111:role/write"},
+	}
+
+	for _, testCase := range testCases {
+		opts := mockOptionsForTest(t)
+		opts.TerraformCommand = testCase.command
+
+		terragruntConfig, err := ParseConfigString(config, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, terragruntConfig.IamRole, testCase.command)
+		assert.Equal(t, testCase.expected, terragruntConfig.GetIAMRoleOptions().RoleARN, testCase.command)
+		assert.Len(t, terragruntConfig.IamRoles, 3)
+
+		partialConfig, err := PartialParseConfigString(config, opts, nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{TerragruntFlags})
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, partialConfig.IamRole, testCase.command)
+
+		// The role of another command is not reused from the cache of the partially parsed configs.
+		opts.UsePartialParseConfigCache = true
+		cachedConfig, err := TerragruntConfigFromPartialConfigString(config, opts, nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{TerragruntFlags})
+		require.NoError(t, err)
+		assert.Equal(t, testCase.expected, cachedConfig.IamRole, testCase.command)
+	}
+
+	// Without a default role, the commands not in the map don't assume a role.
+	opts := mockOptionsForTest(t)
+	opts.TerraformCommand = "apply"
+	terragruntConfig, err := ParseConfigString(`iam_role = { plan = "arn:aws:iam::111111111111:role/read-only" }`, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	assert.Empty(t, terragruntConfig.IamRole)
+
+	_, err = ParseConfigString(`iam_role = ["arn:aws:iam::111111111111:role/read-only"]`, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Invalid iam_role")
+}
+
+func TestParseIamMfaSerial(t *testing.T) {
+	t.Parallel()
+
+	config := `
+iam_role       = "arn:aws:iam::111111111111:role/terragrunt"
+iam_mfa_serial = "arn:aws:iam::111111111111:mfa/user"
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "arn:aws:iam::111111111111:mfa/user", terragruntConfig.IamMfaSerial)
+	assert.Equal(t, "arn:aws:iam::111111111111:mfa/user", terragruntConfig.GetIAMRoleOptions().MFASerial)
+}
+
+func TestParseIamWebIdentityToken(t *testing.T) {
+	t.Parallel()
+
+	config := `
+iam_role               = "arn:aws:iam::111111111111:role/ci"
+iam_web_identity_token = "/var/run/secrets/oidc/token"
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assert.Equal(t, "/var/run/secrets/oidc/token", terragruntConfig.IamWebIdentityToken)
+	assert.Equal(t, "/var/run/secrets/oidc/token", terragruntConfig.GetIAMRoleOptions().WebIdentityToken)
+}
+
 func TestParseTerragruntConfigDependenciesOnePath(t *testing.T) {
 	t.Parallel()
 
@@ -417,6 +744,80 @@ func TestParseTerragruntJsonConfigRemoteStateDynamoDbTerraformConfigAndDependenc
 	}
 }
 
+func TestParseTerragruntYamlConfig(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals:
+  source: foo
+terraform:
+  source: "${local.source}"
+  before_hook:
+    second:
+      commands: [apply]
+      execute: [echo, second]
+    first:
+      commands: [apply]
+      execute: [echo, first]
+remote_state:
+  backend: s3
+  config:
+    encrypt: true
+    bucket: my-bucket
+    key: terraform.tfstate
+    region: us-east-1
+dependencies:
+  paths:
+    - ../test/fixture
+inputs:
+  created: 2024-01-01
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntYamlConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	require.NotNil(t, terragruntConfig.Terraform)
+	require.NotNil(t, terragruntConfig.Terraform.Source)
+	assert.Equal(t, "foo", *terragruntConfig.Terraform.Source)
+
+	// The hooks are kept in the order they are written in.
+	require.Len(t, terragruntConfig.Terraform.BeforeHooks, 2)
+	assert.Equal(t, "second", terragruntConfig.Terraform.BeforeHooks[0].Name)
+	assert.Equal(t, "first", terragruntConfig.Terraform.BeforeHooks[1].Name)
+
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, "s3", terragruntConfig.RemoteState.Backend)
+		assert.Equal(t, true, terragruntConfig.RemoteState.Config["encrypt"])
+		assert.Equal(t, "my-bucket", terragruntConfig.RemoteState.Config["bucket"])
+	}
+
+	if assert.NotNil(t, terragruntConfig.Dependencies) {
+		assert.Equal(t, []string{"../test/fixture"}, terragruntConfig.Dependencies.Paths)
+	}
+
+	assert.Equal(t, "2024-01-01", terragruntConfig.Inputs["created"])
+}
+
+func TestParseTerragruntYamlConfigEmpty(t *testing.T) {
+	t.Parallel()
+
+	cfg, err := ParseConfigString("", mockOptionsForTest(t), nil, DefaultTerragruntYamlConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	assert.Nil(t, cfg.Terraform)
+	assert.Nil(t, cfg.RemoteState)
+}
+
+func TestParseTerragruntYamlConfigInvalidTopLevel(t *testing.T) {
+	t.Parallel()
+
+	_, err := ParseConfigString("- foo\n- bar\n", mockOptionsForTest(t), nil, DefaultTerragruntYamlConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	var invalidYamlErr InvalidYamlConfig
+	require.ErrorAs(t, err, &invalidYamlErr)
+}
+
 func TestParseTerragruntConfigInclude(t *testing.T) {
 	t.Parallel()
 
@@ -613,6 +1014,35 @@ func TestParseTerragruntJsonConfigIncludeOverrideAll(t *testing.T) {
 	assert.Equal(t, []string{"override"}, terragruntConfig.Dependencies.Paths)
 }
 
+func TestParseTerragruntYamlConfigIncludeOverrideAll(t *testing.T) {
+	t.Parallel()
+
+	config :=
+		fmt.Sprintf(`
+include:
+  path: ../../../%s
+terraform:
+  source: foo
+dependencies:
+  paths: [override]
+`, DefaultTerragruntConfigPath)
+
+	opts := mockOptionsForTestWithConfigPath(t, "../test/fixture-parent-folders/terragrunt-in-root/child/sub-child/sub-sub-child/"+DefaultTerragruntYamlConfigPath)
+
+	terragruntConfig, err := ParseConfigString(config, opts, nil, opts.TerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err, "Unexpected error: %v", errors.PrintErrorWithStackTrace(err))
+
+	require.NotNil(t, terragruntConfig.Terraform)
+	require.NotNil(t, terragruntConfig.Terraform.Source)
+	assert.Equal(t, "foo", *terragruntConfig.Terraform.Source)
+
+	if assert.NotNil(t, terragruntConfig.RemoteState) {
+		assert.Equal(t, "s3", terragruntConfig.RemoteState.Backend)
+	}
+
+	assert.Equal(t, []string{"override"}, terragruntConfig.Dependencies.Paths)
+}
+
 func TestParseTerragruntConfigTwoLevels(t *testing.T) {
 	t.Parallel()
 
@@ -655,6 +1085,206 @@ func TestParseTerragruntConfigThreeLevels(t *testing.T) {
 	assert.True(t, errors.IsError(actualErr, expectedErr), "Expected error %v but got %v", expectedErr, actualErr)
 }
 
+func TestParseTerragruntConfigHookInvalidMerge(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terraform {
+  before_hook "lint" {
+    commands = ["apply"]
+    execute  = ["tflint"]
+    merge    = "replace"
+  }
+}
+`
+
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `The value of 'merge' must be one of "override", "append" or "prepend", got "replace".`)
+}
+
+func TestParseTerragruntConfigResultHooks(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terraform {
+  on_success_hook "notify" {
+    commands = ["apply"]
+    execute  = ["echo", "applied"]
+  }
+
+  on_failure_hook "notify" {
+    commands = ["apply"]
+    execute  = ["echo", "failed"]
+  }
+
+  on_skip_hook "notify" {
+    commands = ["apply"]
+    execute  = ["echo", "skipped"]
+  }
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	require.Len(t, terragruntConfig.Terraform.OnSuccessHooks, 1)
+	require.Len(t, terragruntConfig.Terraform.OnFailureHooks, 1)
+	require.Len(t, terragruntConfig.Terraform.OnSkipHooks, 1)
+	assert.Equal(t, []string{"echo", "applied"}, terragruntConfig.Terraform.OnSuccessHooks[0].Execute)
+	assert.Equal(t, []string{"echo", "failed"}, terragruntConfig.Terraform.OnFailureHooks[0].Execute)
+	assert.Equal(t, []string{"echo", "skipped"}, terragruntConfig.Terraform.OnSkipHooks[0].Execute)
+}
+
+func TestParseTerragruntConfigResultHookWithoutExecute(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terraform {
+  on_skip_hook "notify" {
+    commands = ["apply"]
+    execute  = []
+  }
+}
+`
+
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Error with hook notify. Need at least one non-empty argument in 'execute'.")
+}
+
+func TestParseTerragruntConfigBackgroundHooks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		hook          string
+		expectedError string
+	}{
+		{"background", `run_in_background = true`, ""},
+		{"timeout", "run_in_background = true\n    background_timeout = \"10m\"", ""},
+		{"timeout without background", `background_timeout = "10m"`, "'background_timeout' can only be set when 'run_in_background' is true."},
+		{"invalid timeout", "run_in_background = true\n    background_timeout = \"soon\"", `The value of 'background_timeout' must be a positive duration such as "10m", got "soon".`},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := fmt.Sprintf(`
+terraform {
+  after_hook "upload" {
+    commands = ["apply"]
+    execute  = ["./upload.sh"]
+    %s
+  }
+}
+`, testCase.hook)
+
+			terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, terragruntConfig.Terraform.AfterHooks[0].IsBackground())
+		})
+	}
+}
+
+func TestParseTerragruntConfigGenerateTemplate(t *testing.T) {
+	t.Parallel()
+
+	configPath := "../test/fixture-generate-template/child/" + DefaultTerragruntConfigPath
+	terragruntConfig, err := ParseConfigFile(configPath, mockOptionsForTestWithConfigPath(t, configPath), nil, nil)
+	require.NoError(t, err)
+
+	// The template of the included config is rendered with its locals and the inputs of the child config.
+	expected := `provider "aws" {
+  region = "us-east-1"
+}
+
+provider "aws" {
+  alias  = "eu_west_1"
+  region = "eu-west-1"
+}
+
+provider "aws" {
+  alias  = "us_west_2"
+  region = "us-west-2"
+}
+`
+	require.Contains(t, terragruntConfig.GenerateConfigs, "provider")
+	assert.Equal(t, expected, terragruntConfig.GenerateConfigs["provider"].Contents)
+	assert.Nil(t, terragruntConfig.GenerateConfigs["provider"].TemplateContext)
+}
+
+func TestParseTerragruntConfigGenerateTemplateInline(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  prefix = "tg"
+}
+
+generate "tags" {
+  path      = "tags.txt"
+  if_exists = "overwrite"
+  template  = true
+  contents  = <<EOF
+%%{ for name, value in inputs.tags ~}
+$${local.prefix}-$${name}=$${value}
+%%{ endfor ~}
+EOF
+}
+
+generate "raw" {
+  path      = "raw.txt"
+  if_exists = "overwrite"
+  contents  = "$${inputs.tags}"
+}
+
+inputs = {
+  tags = {
+    env  = "dev"
+    team = "infra"
+  }
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, "tg-env=dev\ntg-team=infra\n", terragruntConfig.GenerateConfigs["tags"].Contents)
+	// The contents of the generate blocks that are not templates are kept as is.
+	assert.Equal(t, "${inputs.tags}", terragruntConfig.GenerateConfigs["raw"].Contents)
+}
+
+func TestParseTerragruntConfigGenerateTemplateNotString(t *testing.T) {
+	t.Parallel()
+
+	config := `
+generate "regions" {
+  path      = "regions.txt"
+  if_exists = "overwrite"
+  template  = true
+  contents  = "$${inputs.regions}"
+}
+
+inputs = {
+  regions = ["eu-west-1"]
+}
+`
+
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	var invalidResultErr InvalidGenerateTemplateResult
+	require.ErrorAs(t, err, &invalidResultErr)
+	assert.Equal(t, "regions", invalidResultErr.Name)
+}
+
 func TestParseTerragruntConfigEmptyConfig(t *testing.T) {
 	t.Parallel()
 
@@ -728,6 +1358,57 @@ terraform {
 	assert.Equal(t, "foo", *terragruntConfig.Terraform.Source)
 }
 
+func TestParseTerragruntConfigTerraformWithSourceMirrors(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terraform {
+	source = ["git::https://github.com/foo/modules.git//vpc", "git::https://mirror.example.com/foo/modules.git//vpc", "/opt/modules/vpc"]
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	require.NotNil(t, terragruntConfig.Terraform)
+	require.NotNil(t, terragruntConfig.Terraform.Source)
+	assert.Equal(t, "git::https://github.com/foo/modules.git//vpc", *terragruntConfig.Terraform.Source)
+	assert.Equal(t, []string{"git::https://mirror.example.com/foo/modules.git//vpc", "/opt/modules/vpc"}, terragruntConfig.Terraform.SourceMirrors)
+
+	mirrorUrls, err := GetTerraformSourceMirrorUrls(mockOptionsForTest(t), terragruntConfig)
+	require.NoError(t, err)
+	assert.Equal(t, terragruntConfig.Terraform.SourceMirrors, mirrorUrls)
+
+	partialConfig, err := PartialParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{TerraformSource})
+	require.NoError(t, err)
+	assert.Equal(t, terragruntConfig.Terraform.Source, partialConfig.Terraform.Source)
+	assert.Equal(t, terragruntConfig.Terraform.SourceMirrors, partialConfig.Terraform.SourceMirrors)
+
+	// The source set with the command-line option has no mirrors.
+	opts := mockOptionsForTest(t)
+	opts.Source = "/tmp/vpc"
+	mirrorUrls, err = GetTerraformSourceMirrorUrls(opts, terragruntConfig)
+	require.NoError(t, err)
+	assert.Empty(t, mirrorUrls)
+}
+
+func TestParseTerragruntConfigTerraformWithInvalidSource(t *testing.T) {
+	t.Parallel()
+
+	testCases := []string{`[]`, `42`, `{ url = "foo" }`}
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase, func(t *testing.T) {
+			t.Parallel()
+
+			config := fmt.Sprintf("terraform {\n\tsource = %s\n}\n", testCase)
+			_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			require.Error(t, err)
+			assert.IsType(t, InvalidTerraformSource{}, errors.Unwrap(err))
+		})
+	}
+}
+
 func TestParseTerragruntConfigTerraformWithExtraArguments(t *testing.T) {
 	t.Parallel()
 
@@ -1167,6 +1848,48 @@ skip = false
 	assert.Equal(t, false, terragruntConfig.Skip)
 }
 
+func TestParseTerragruntConfigExclude(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  env = "prod"
+}
+
+exclude {
+  if      = local.env == "prod"
+  actions = ["destroy"]
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, &ExcludeConfig{If: true, Actions: []string{"destroy"}}, terragruntConfig.Exclude)
+	assert.True(t, terragruntConfig.Exclude.ExcludesCommand("destroy"))
+	assert.False(t, terragruntConfig.Exclude.ExcludesCommand("apply"))
+}
+
+func TestExcludeConfigExcludesCommand(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		exclude  ExcludeConfig
+		command  string
+		expected bool
+	}{
+		{ExcludeConfig{If: false, Actions: []string{"apply"}}, "apply", false},
+		{ExcludeConfig{If: true, Actions: []string{"plan", "apply"}}, "apply", true},
+		{ExcludeConfig{If: true, Actions: []string{"plan", "apply"}}, "output", false},
+		{ExcludeConfig{If: true, Actions: []string{ExcludeActionAll}}, "output", true},
+		{ExcludeConfig{If: true}, "destroy", true},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, testCase.exclude.ExcludesCommand(testCase.command), "%s %s", testCase.exclude.String(), testCase.command)
+	}
+}
+
 func TestIncludeFunctionsWorkInChildConfig(t *testing.T) {
 	config := `
 include {
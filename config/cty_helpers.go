@@ -239,6 +239,19 @@ func parseCtyValueToMap(value cty.Value) (map[string]interface{}, error) {
 	return ctyJsonOutput.Value, nil
 }
 
+// unknownValuesToNull replaces the unknown values nested in the given value with null values of the same type, so that
+// the value can be converted to JSON.
+func unknownValuesToNull(value cty.Value) cty.Value {
+	// The callback never returns an error, so neither does Transform.
+	converted, _ := cty.Transform(value, func(_ cty.Path, nested cty.Value) (cty.Value, error) {
+		if !nested.IsKnown() {
+			return cty.NullVal(nested.Type()), nil
+		}
+		return nested, nil
+	})
+	return converted
+}
+
 // When you convert a cty value to JSON, if any of that types are not yet known (i.e., are labeled as
 // DynamicPseudoType), cty's Marshall method will write the type information to a type field and the actual value to
 // a value field. This struct is used to capture that information so when we parse the JSON back into a Go struct, we
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// CustomCommand is a command defined in the commands block of a config, which runs a sequence of steps in the unit
+// with `terragrunt <name>`:
+//
+//	commands {
+//	  deploy = ["init", "plan -out=tfplan", "apply tfplan"]
+//	  lint   = [["sh", "-c", "tflint | tee lint.txt"], "validate"]
+//	}
+type CustomCommand struct {
+	Name  string
+	Steps []CustomCommandStep
+}
+
+// CustomCommandStep is a step of a custom command. A string step is a terraform command with its args, split on
+// whitespace, and a list step is an executable with its args, run in the directory of the unit.
+type CustomCommandStep struct {
+	Command []string
+	Execute []string
+}
+
+func (command *CustomCommand) String() string {
+	return fmt.Sprintf("CustomCommand{Name = %s}", command.Name)
+}
+
+// IsExecute returns true if the step runs an executable rather than a terraform command.
+func (step CustomCommandStep) IsExecute() bool {
+	return len(step.Execute) > 0
+}
+
+// asCty returns the step in the form it is written in the config.
+func (step CustomCommandStep) asCty() cty.Value {
+	if !step.IsExecute() {
+		return cty.StringVal(strings.Join(step.Command, " "))
+	}
+	args := []cty.Value{}
+	for _, arg := range step.Execute {
+		args = append(args, cty.StringVal(arg))
+	}
+	return cty.ListVal(args)
+}
+
+// GetCustomCommand returns the custom command of the config with the given name, or nil if there is none.
+func (conf *TerragruntConfig) GetCustomCommand(name string) *CustomCommand {
+	index := slices.IndexFunc(conf.CustomCommands, func(command CustomCommand) bool { return command.Name == name })
+	if index == -1 {
+		return nil
+	}
+	return &conf.CustomCommands[index]
+}
+
+// terragruntCommands is the commands block, whose attributes are the custom commands. It is decoded attribute by
+// attribute, as the names of the commands are not known in advance.
+type terragruntCommands struct {
+	Remain hcl.Body `hcl:",remain"`
+}
+
+// decodeCustomCommands evaluates the attributes of the given commands block into custom commands, sorted by name.
+func decodeCustomCommands(block *terragruntCommands, configPath string, evalContext *hcl.EvalContext) ([]CustomCommand, error) {
+	if block == nil {
+		return nil, nil
+	}
+
+	attributes, diags := block.Remain.JustAttributes()
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+
+	commands := []CustomCommand{}
+	for name, attribute := range attributes {
+		value, diags := attribute.Expr.Value(evalContext)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+		steps, err := customCommandSteps(value)
+		if err != nil {
+			return nil, errors.WithStackTrace(InvalidCustomCommand{Name: name, ConfigPath: configPath, Reason: err.Error()})
+		}
+		commands = append(commands, CustomCommand{Name: name, Steps: steps})
+	}
+
+	sort.Slice(commands, func(i, j int) bool { return commands[i].Name < commands[j].Name })
+	return commands, nil
+}
+
+// customCommandSteps converts the value of an attribute of the commands block to the steps of the command.
+func customCommandSteps(value cty.Value) ([]CustomCommandStep, error) {
+	if value.IsNull() || !value.IsWhollyKnown() || !(value.Type().IsListType() || value.Type().IsTupleType()) {
+		return nil, fmt.Errorf("the value must be a list of steps")
+	}
+	if value.LengthInt() == 0 {
+		return nil, fmt.Errorf("the command has no steps")
+	}
+
+	steps := []CustomCommandStep{}
+	for it := value.ElementIterator(); it.Next(); {
+		_, stepValue := it.Element()
+		if stepValue.IsNull() {
+			return nil, fmt.Errorf("a step can't be null")
+		}
+
+		if stepValue.Type() == cty.String {
+			command := strings.Fields(stepValue.AsString())
+			if len(command) == 0 {
+				return nil, fmt.Errorf("a step can't be empty")
+			}
+			steps = append(steps, CustomCommandStep{Command: command})
+			continue
+		}
+
+		if !(stepValue.Type().IsListType() || stepValue.Type().IsTupleType()) || !stepValue.IsWhollyKnown() {
+			return nil, fmt.Errorf("a step must be a terraform command or a list of strings")
+		}
+		execute, err := ctySliceToStringSlice(stepValue.AsValueSlice())
+		if err != nil {
+			return nil, fmt.Errorf("a step must be a terraform command or a list of strings: %w", err)
+		}
+		if len(execute) == 0 || execute[0] == "" {
+			return nil, fmt.Errorf("a step can't be empty")
+		}
+		steps = append(steps, CustomCommandStep{Execute: execute})
+	}
+	return steps, nil
+}
+
+// mergeCustomCommands merges the custom commands of a child config into the ones of its parent. A child's command
+// replaces the parent's command of the same name, as a whole.
+func mergeCustomCommands(childCommands []CustomCommand, parentCommands []CustomCommand) []CustomCommand {
+	if len(childCommands) == 0 {
+		return parentCommands
+	}
+
+	result := append([]CustomCommand{}, parentCommands...)
+	for _, child := range childCommands {
+		index := slices.IndexFunc(result, func(parent CustomCommand) bool { return parent.Name == child.Name })
+		if index == -1 {
+			result = append(result, child)
+		} else {
+			result[index] = child
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// customCommandsAsCty returns the custom commands as the commands block they are written as.
+func customCommandsAsCty(commands []CustomCommand) cty.Value {
+	if len(commands) == 0 {
+		return cty.NilVal
+	}
+
+	commandsCty := map[string]cty.Value{}
+	for _, command := range commands {
+		steps := []cty.Value{}
+		for _, step := range command.Steps {
+			steps = append(steps, step.asCty())
+		}
+		commandsCty[command.Name] = cty.TupleVal(steps)
+	}
+	return cty.ObjectVal(commandsCty)
+}
+
+// Custom error types
+
+type InvalidCustomCommand struct {
+	Name       string
+	ConfigPath string
+	Reason     string
+}
+
+func (err InvalidCustomCommand) Error() string {
+	return fmt.Sprintf("Invalid command %s in the commands block of %s: %s.", err.Name, err.ConfigPath, err.Reason)
+}
@@ -0,0 +1,90 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerragruntConfigCustomCommands(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  plan_file = "tfplan"
+}
+
+commands {
+  deploy = ["init", "plan -out=${local.plan_file}", "apply ${local.plan_file}"]
+  lint   = [["sh", "-c", "tflint | tee lint.txt"], "validate"]
+}
+`
+	terragruntConfig, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	expected := []CustomCommand{
+		{Name: "deploy", Steps: []CustomCommandStep{
+			{Command: []string{"init"}},
+			{Command: []string{"plan", "-out=tfplan"}},
+			{Command: []string{"apply", "tfplan"}},
+		}},
+		{Name: "lint", Steps: []CustomCommandStep{
+			{Execute: []string{"sh", "-c", "tflint | tee lint.txt"}},
+			{Command: []string{"validate"}},
+		}},
+	}
+	assert.Equal(t, expected, terragruntConfig.CustomCommands)
+	assert.Equal(t, &expected[1], terragruntConfig.GetCustomCommand("lint"))
+	assert.Nil(t, terragruntConfig.GetCustomCommand("plan"))
+
+	partialConfig, err := PartialParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{CustomCommandsBlock})
+	require.NoError(t, err)
+	assert.Equal(t, expected, partialConfig.CustomCommands)
+}
+
+func TestParseTerragruntConfigInvalidCustomCommands(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		command  string
+		expected string
+	}{
+		{"not a list", `deploy = "apply"`, "the value must be a list of steps"},
+		{"no steps", `deploy = []`, "the command has no steps"},
+		{"empty step", `deploy = ["init", " "]`, "a step can't be empty"},
+		{"empty execute step", `deploy = [[]]`, "a step can't be empty"},
+		{"invalid step", `deploy = [{ command = "apply" }]`, "a step must be a terraform command or a list of strings"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := "commands {\n  " + testCase.command + "\n}\n"
+			_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), "Invalid command deploy")
+			assert.Contains(t, err.Error(), testCase.expected)
+		})
+	}
+}
+
+func TestMergeCustomCommands(t *testing.T) {
+	t.Parallel()
+
+	parent := []CustomCommand{
+		{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"apply"}}}},
+		{Name: "lint", Steps: []CustomCommandStep{{Execute: []string{"tflint"}}}},
+	}
+	child := []CustomCommand{
+		{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"init"}}, {Command: []string{"apply"}}}},
+		{Name: "check", Steps: []CustomCommandStep{{Command: []string{"validate"}}}},
+	}
+
+	expected := []CustomCommand{child[1], child[0], parent[1]}
+	assert.Equal(t, expected, mergeCustomCommands(child, parent))
+	assert.Equal(t, parent, mergeCustomCommands(nil, parent))
+}
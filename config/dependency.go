@@ -9,8 +9,10 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/service/s3"
@@ -31,13 +33,20 @@ import (
 	"github.com/gruntwork-io/terragrunt/util"
 )
 
-const renderJsonCommand = "render-json"
+// The commands that render the config, for which the mock outputs of dependencies are used when their outputs can't be
+// read.
+var renderCommands = []string{"render-json", "render"}
+
+// The commands for which using the mock outputs of dependencies fails in strict mock outputs mode, as they would deploy
+// the placeholder data.
+var strictMockOutputsCommands = []string{"apply", "destroy"}
 
 type Dependency struct {
 	Name                                string     `hcl:",label" cty:"name"`
 	Enabled                             *bool      `hcl:"enabled,attr" cty:"enabled"`
 	ConfigPath                          string     `hcl:"config_path,attr" cty:"config_path"`
 	SkipOutputs                         *bool      `hcl:"skip_outputs,attr" cty:"skip"`
+	OrderingOnly                        *bool      `hcl:"ordering_only,attr" cty:"ordering_only"`
 	MockOutputs                         *cty.Value `hcl:"mock_outputs,attr" cty:"mock_outputs"`
 	MockOutputsAllowedTerraformCommands *[]string  `hcl:"mock_outputs_allowed_terraform_commands,attr" cty:"mock_outputs_allowed_terraform_commands"`
 
@@ -45,8 +54,31 @@ type Dependency struct {
 	MockOutputsMergeWithState         *bool              `hcl:"mock_outputs_merge_with_state,attr" cty:"mock_outputs_merge_with_state"`
 	MockOutputsMergeStrategyWithState *MergeStrategyType `hcl:"mock_outputs_merge_strategy_with_state" cty:"mock_outputs_merge_strategy_with_state"`
 
+	// The repository the config_path is in, relative to its root, when the dependency is in another repository, e.g.
+	// git::https://github.com/acme/platform-live.git?ref=v1.0.0.
+	Source *string `hcl:"source,attr" cty:"source"`
+
+	// The outputs expected from the dependency and their types, e.g. { vpc_id = string }, which the outputs read from
+	// its state are checked against.
+	ExpectedOutputs hcl.Expression `hcl:"expected_outputs,optional"`
+
+	// Values derived from the outputs of the dependency, e.g. { private_subnet_ids = [for s in outputs.subnets : s.id] },
+	// which are evaluated once the outputs are read and exposed as dependency.<name>.derived.
+	Derived hcl.Expression `hcl:"derived,optional"`
+
 	// Used to store the rendered outputs for use when the config is imported or read with `read_terragrunt_config`
 	RenderedOutputs *cty.Value `cty:"outputs"`
+
+	// The outputs referenced in the config, which are the only ones read from the state of the dependency. All the
+	// outputs are read when nil.
+	referencedOutputs map[string]bool
+
+	// Whether using the mock outputs fails for the strictMockOutputsCommands, set by the strict_mock_outputs attribute of
+	// the config or --terragrunt-strict-mock-outputs.
+	strictMockOutputs bool
+
+	// The context of the config the dependency block is in, which the derived values are evaluated in.
+	evalContext *hcl.EvalContext
 }
 
 // DeepMerge will deep merge two Dependency configs, updating the target. Deep merge for Dependency configs is defined
@@ -62,6 +94,10 @@ func (targetDepConfig *Dependency) DeepMerge(sourceDepConfig Dependency) error {
 		targetDepConfig.ConfigPath = sourceDepConfig.ConfigPath
 	}
 
+	if sourceDepConfig.Source != nil {
+		targetDepConfig.Source = sourceDepConfig.Source
+	}
+
 	if sourceDepConfig.Enabled != nil {
 		targetDepConfig.Enabled = sourceDepConfig.Enabled
 	}
@@ -70,6 +106,18 @@ func (targetDepConfig *Dependency) DeepMerge(sourceDepConfig Dependency) error {
 		targetDepConfig.SkipOutputs = sourceDepConfig.SkipOutputs
 	}
 
+	if sourceDepConfig.OrderingOnly != nil {
+		targetDepConfig.OrderingOnly = sourceDepConfig.OrderingOnly
+	}
+
+	if sourceDepConfig.hasExpectedOutputs() {
+		targetDepConfig.ExpectedOutputs = sourceDepConfig.ExpectedOutputs
+	}
+
+	if sourceDepConfig.hasDerived() {
+		targetDepConfig.Derived = sourceDepConfig.Derived
+	}
+
 	if sourceDepConfig.MockOutputs != nil {
 		if targetDepConfig.MockOutputs == nil {
 			targetDepConfig.MockOutputs = sourceDepConfig.MockOutputs
@@ -112,7 +160,12 @@ func (dependencyConfig Dependency) getMockOutputsMergeStrategy() MergeStrategyTy
 
 // Given a dependency config, we should only attempt to get the outputs if SkipOutputs is nil or false
 func (dependencyConfig Dependency) shouldGetOutputs() bool {
-	return dependencyConfig.isEnabled() && (dependencyConfig.SkipOutputs == nil || !*dependencyConfig.SkipOutputs)
+	return dependencyConfig.isEnabled() && !dependencyConfig.isOrderingOnly() && (dependencyConfig.SkipOutputs == nil || !*dependencyConfig.SkipOutputs)
+}
+
+// isOrderingOnly returns true if the dependency only orders the run of run-all, so that its outputs are never read.
+func (dependencyConfig Dependency) isOrderingOnly() bool {
+	return dependencyConfig.OrderingOnly != nil && *dependencyConfig.OrderingOnly
 }
 
 // isEnabled returns true if the dependency is enabled
@@ -123,6 +176,40 @@ func (dependencyConfig Dependency) isEnabled() bool {
 	return *dependencyConfig.Enabled
 }
 
+// isRemote returns true if the dependency is in another repository.
+func (dependencyConfig Dependency) isRemote() bool {
+	return dependencyConfig.Source != nil && *dependencyConfig.Source != ""
+}
+
+// excludeDisabledDependencyPaths removes the config paths of the disabled dependency blocks from the module
+// dependencies of the given config, so that they are excluded from the graph. This is needed after merging the
+// included configs, as a dependency block of an included config can be disabled by the block of the same name in the
+// child config, while its config path was already added to the module dependencies by the included config.
+func excludeDisabledDependencyPaths(config *TerragruntConfig) {
+	if config == nil || config.Dependencies == nil {
+		return
+	}
+
+	enabledPaths := []string{}
+	disabledPaths := []string{}
+	for _, dependency := range config.TerragruntDependencies {
+		if dependency.isEnabled() {
+			enabledPaths = append(enabledPaths, dependency.ConfigPath)
+		} else {
+			disabledPaths = append(disabledPaths, dependency.ConfigPath)
+		}
+	}
+
+	paths := []string{}
+	for _, path := range config.Dependencies.Paths {
+		if util.ListContainsElement(disabledPaths, path) && !util.ListContainsElement(enabledPaths, path) {
+			continue
+		}
+		paths = append(paths, path)
+	}
+	config.Dependencies.Paths = paths
+}
+
 // Given a dependency config, we should only attempt to merge mocks outputs with the outputs if MockOutputsMergeWithState is not nil or true
 func (dependencyConfig Dependency) shouldMergeMockOutputsWithState(terragruntOptions *options.TerragruntOptions) bool {
 	allowedCommand :=
@@ -137,6 +224,18 @@ func (dependencyConfig *Dependency) setRenderedOutputs(terragruntOptions *option
 		return nil
 	}
 
+	// The outputs of the disabled dependencies are never read, as they are not part of the graph, so their mock outputs
+	// are used as placeholders instead, for the references to them to still resolve.
+	if !dependencyConfig.isEnabled() {
+		dependencyConfig.RenderedOutputs = dependencyConfig.MockOutputs
+		return nil
+	}
+
+	// The ordering only dependencies have no outputs, so that the dependency doesn't even need to be applied.
+	if dependencyConfig.isOrderingOnly() {
+		return nil
+	}
+
 	if dependencyConfig.shouldGetOutputs() || dependencyConfig.shouldReturnMockOutputs(terragruntOptions) {
 		outputVal, err := getTerragruntOutputIfAppliedElseConfiguredDefault(*dependencyConfig, terragruntOptions)
 		if err != nil {
@@ -147,6 +246,32 @@ func (dependencyConfig *Dependency) setRenderedOutputs(terragruntOptions *option
 	return nil
 }
 
+// DependencyOutputs returns the outputs of the enabled dependencies of the config by the name of their blocks, or
+// their mock outputs when these are used. The outputs are cached when the config is read, so they are not read from the
+// state again. The dependencies without outputs, such as the ordering only ones, are left out.
+func (conf *TerragruntConfig) DependencyOutputs(terragruntOptions *options.TerragruntOptions) (map[string]cty.Value, error) {
+	outputs := map[string]cty.Value{}
+	for _, dependencyConfig := range conf.TerragruntDependencies {
+		if !dependencyConfig.isEnabled() {
+			continue
+		}
+		// The dependency is a copy, so the config, which may be cached, is left as is.
+		if err := dependencyConfig.setRenderedOutputs(terragruntOptions); err != nil {
+			return nil, err
+		}
+		if dependencyConfig.RenderedOutputs != nil {
+			outputs[dependencyConfig.Name] = *dependencyConfig.RenderedOutputs
+		}
+	}
+	return outputs, nil
+}
+
+// cachedOutputJson are the outputs of a config cached in the jsonOutputCache, and when they were fetched.
+type cachedOutputJson struct {
+	jsonBytes []byte
+	fetchedAt time.Time
+}
+
 // jsonOutputCache is a map that maps config paths to the outputs so that they can be reused across calls for common
 // modules. We use sync.Map to ensure atomic updates during concurrent access.
 var jsonOutputCache = sync.Map{}
@@ -178,16 +303,6 @@ func decodeAndRetrieveOutputs(
 		return nil, err
 	}
 
-	// Skip disabled dependencies
-	updatedDependencies := terragruntDependency{}
-	for _, dep := range decodedDependency.Dependencies {
-		if !dep.isEnabled() {
-			continue
-		}
-		updatedDependencies.Dependencies = append(updatedDependencies.Dependencies, dep)
-	}
-	decodedDependency = updatedDependencies
-
 	// Merge in included dependencies
 	if trackInclude != nil {
 		mergedDecodedDependency, err := handleIncludeForDependency(decodedDependency, trackInclude, terragruntOptions)
@@ -197,9 +312,31 @@ func decodeAndRetrieveOutputs(
 		decodedDependency = *mergedDecodedDependency
 	}
 
+	if err := resolveDependencySourcePaths(decodedDependency.Dependencies, filename, terragruntOptions); err != nil {
+		return nil, err
+	}
+
+	if err := validateOrderingOnlyDependencies(decodedDependency.Dependencies); err != nil {
+		return nil, err
+	}
+
 	if err := checkForDependencyBlockCycles(filename, decodedDependency, terragruntOptions); err != nil {
 		return nil, err
 	}
+
+	if references, ok := findDependencyOutputReferences(file, filename, terragruntOptions, trackInclude); ok {
+		for i := range decodedDependency.Dependencies {
+			decodedDependency.Dependencies[i].referencedOutputs = references.outputs(decodedDependency.Dependencies[i].Name)
+			decodedDependency.Dependencies[i].addDerivedOutputReferences()
+		}
+	}
+
+	strictMockOutputs := terragruntOptions.StrictMockOutputs || (decodedDependency.StrictMockOutputs != nil && *decodedDependency.StrictMockOutputs)
+	for i := range decodedDependency.Dependencies {
+		decodedDependency.Dependencies[i].strictMockOutputs = strictMockOutputs
+		decodedDependency.Dependencies[i].evalContext = evalContext
+	}
+
 	return dependencyBlocksToCtyValue(decodedDependency.Dependencies, terragruntOptions)
 }
 
@@ -216,6 +353,10 @@ func dependencyBlocksToModuleDependencies(decodedDependencyBlocks []Dependency)
 		if !decodedDependencyBlock.isEnabled() {
 			continue
 		}
+		// The dependencies in other repositories are not part of the stack, as they are applied from their repository.
+		if decodedDependencyBlock.isRemote() {
+			continue
+		}
 		paths = append(paths, decodedDependencyBlock.ConfigPath)
 	}
 
@@ -228,6 +369,11 @@ func checkForDependencyBlockCycles(filename string, decodedDependency terragrunt
 	visitedPaths := []string{}
 	currentTraversalPaths := []string{filename}
 	for _, dependency := range decodedDependency.Dependencies {
+		// Disabled dependencies are not part of the graph, and the outputs of the ordering only dependencies are never
+		// read, so they can't be part of a cycle of `terragrunt output` calls.
+		if !dependency.isEnabled() || dependency.isOrderingOnly() {
+			continue
+		}
 		dependencyPath := getCleanedTargetConfigPath(dependency.ConfigPath, filename)
 		dependencyOptions := cloneTerragruntOptionsForDependency(terragruntOptions, dependencyPath)
 		if err := checkForDependencyBlockCyclesUsingDFS(dependencyPath, &visitedPaths, &currentTraversalPaths, dependencyOptions); err != nil {
@@ -252,7 +398,8 @@ func checkForDependencyBlockCyclesUsingDFS(
 	}
 
 	if util.ListContainsElement(*currentTraversalPaths, currentConfigPath) {
-		return errors.WithStackTrace(DependencyCycle(append(*currentTraversalPaths, currentConfigPath)))
+		cycle := DependencyCycle(append(*currentTraversalPaths, currentConfigPath))
+		return errors.WithStackTrace(DependencyCycleWithDeclarations{Cycle: cycle, Declarations: dependencyBlockCycleDeclarations(cycle, terragruntOptions)})
 	}
 
 	*currentTraversalPaths = append(*currentTraversalPaths, currentConfigPath)
@@ -287,7 +434,34 @@ func getDependencyBlockConfigPathsByFilepath(configPath string, terragruntOption
 	if tgConfig.Dependencies == nil {
 		return []string{}, nil
 	}
-	return tgConfig.Dependencies.Paths, nil
+
+	orderingOnlyPaths := []string{}
+	for _, dependency := range tgConfig.TerragruntDependencies {
+		if dependency.isOrderingOnly() {
+			orderingOnlyPaths = append(orderingOnlyPaths, dependency.ConfigPath)
+		}
+	}
+	paths := []string{}
+	for _, path := range tgConfig.Dependencies.Paths {
+		if !util.ListContainsElement(orderingOnlyPaths, path) {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// validateOrderingOnlyDependencies returns an error if an ordering only dependency sets the attributes about its
+// outputs, as they are never read.
+func validateOrderingOnlyDependencies(dependencyConfigs []Dependency) error {
+	for _, dependencyConfig := range dependencyConfigs {
+		if !dependencyConfig.isOrderingOnly() {
+			continue
+		}
+		if dependencyConfig.MockOutputs != nil || dependencyConfig.hasExpectedOutputs() || dependencyConfig.hasDerived() {
+			return errors.WithStackTrace(OrderingOnlyDependencyWithOutputs{Name: dependencyConfig.Name})
+		}
+	}
+	return nil
 }
 
 // Encode the list of dependency blocks into a single cty.Value object that maps the dependency block name to the
@@ -299,16 +473,23 @@ func getDependencyBlockConfigPathsByFilepath(configPath string, terragruntOption
 func dependencyBlocksToCtyValue(dependencyConfigs []Dependency, terragruntOptions *options.TerragruntOptions) (*cty.Value, error) {
 	paths := []string{}
 
+	// The dependencies whose mock outputs would have been used in strict mock outputs mode, which are all reported at
+	// once.
+	forbiddenMocks := []MockOutputsForbidden{}
+
 	// dependencyMap is the top level map that maps dependency block names to the encoded version, which includes
 	// various attributes for accessing information about the target config (including the module outputs).
 	dependencyMap := map[string]cty.Value{}
 	lock := sync.Mutex{}
 	dependencyErrGroup, _ := errgroup.WithContext(context.Background())
 
+	// Each dependency may run terraform init and output, so only a limited number of them are fetched at a time.
+	if terragruntOptions.DependencyFetchParallelism > 0 {
+		dependencyErrGroup.SetLimit(terragruntOptions.DependencyFetchParallelism)
+	}
+	start := time.Now()
+
 	for _, dependencyConfig := range dependencyConfigs {
-		if !dependencyConfig.isEnabled() {
-			continue
-		}
 		dependencyConfig := dependencyConfig // https://golang.org/doc/faq#closures_and_goroutines
 		dependencyErrGroup.Go(func() error {
 			// Loose struct to hold the attributes of the dependency. This includes:
@@ -316,12 +497,31 @@ func dependencyBlocksToCtyValue(dependencyConfigs []Dependency, terragruntOption
 			dependencyEncodingMap := map[string]cty.Value{}
 
 			// Encode the outputs and nest under `outputs` attribute if we should get the outputs or the `mock_outputs`
+			dependencyStart := time.Now()
 			if err := dependencyConfig.setRenderedOutputs(terragruntOptions); err != nil {
+				if forbidden, ok := errors.Unwrap(err).(MockOutputsForbidden); ok {
+					lock.Lock()
+					forbiddenMocks = append(forbiddenMocks, forbidden)
+					lock.Unlock()
+					return nil
+				}
 				return err
 			}
+			terragruntOptions.Logger.Debugf("Fetched the outputs of dependency %s (%s) in %s", dependencyConfig.Name, dependencyConfig.ConfigPath, time.Since(dependencyStart))
+
 			if dependencyConfig.RenderedOutputs != nil {
+				lock.Lock()
 				paths = append(paths, dependencyConfig.ConfigPath)
+				lock.Unlock()
 				dependencyEncodingMap["outputs"] = *dependencyConfig.RenderedOutputs
+
+				if dependencyConfig.hasDerived() {
+					derived, err := dependencyConfig.evaluateDerived(*dependencyConfig.RenderedOutputs)
+					if err != nil {
+						return err
+					}
+					dependencyEncodingMap["derived"] = derived
+				}
 			}
 
 			// Once the dependency is encoded into a map, we need to convert to a cty.Value again so that it can be fed to
@@ -345,6 +545,13 @@ func dependencyBlocksToCtyValue(dependencyConfigs []Dependency, terragruntOption
 	if err := dependencyErrGroup.Wait(); err != nil {
 		return nil, err
 	}
+	if len(forbiddenMocks) > 0 {
+		sort.Slice(forbiddenMocks, func(i, j int) bool { return forbiddenMocks[i].Name < forbiddenMocks[j].Name })
+		return nil, errors.WithStackTrace(DependencyMockOutputsForbidden{ConfigPath: terragruntOptions.TerragruntConfigPath, Command: terragruntOptions.OriginalTerraformCommand, Dependencies: forbiddenMocks})
+	}
+	if len(dependencyConfigs) > 0 {
+		terragruntOptions.Logger.Debugf("Fetched the outputs of %d dependencies of %s in %s", len(dependencyConfigs), terragruntOptions.TerragruntConfigPath, time.Since(start))
+	}
 
 	// We need to convert the value map to a single cty.Value at the end so that it can be used in the execution context
 	convertedOutput, err := gocty.ToCtyValue(dependencyMap, generateTypeFromValuesMap(dependencyMap))
@@ -356,13 +563,40 @@ func dependencyBlocksToCtyValue(dependencyConfigs []Dependency, terragruntOption
 
 // This will attempt to get the outputs from the target terragrunt config if it is applied. If it is not applied, the
 // behavior is different depending on the configuration of the dependency:
+//   - If the outputs are skipped with SkipDependencyOutputs, this will return the mock_outputs if any, or an unknown
+//     value otherwise.
 //   - If the dependency block indicates a mock_outputs attribute, this will return that.
 //     If the dependency block indicates a mock_outputs_merge_strategy_with_state attribute, mock_outputs and state outputs will be merged following the merge strategy
 //   - If the dependency block does NOT indicate a mock_outputs attribute, this will return an error.
+//
+// When the outputs of the dependencies of run-all plan are read from the planned values, and the target config was
+// planned in the same run, this will return the outputs planned for it instead.
 func getTerragruntOutputIfAppliedElseConfiguredDefault(dependencyConfig Dependency, terragruntOptions *options.TerragruntOptions) (*cty.Value, error) {
 	if !dependencyConfig.isEnabled() {
 		return nil, nil
 	}
+	if terragruntOptions.SkipDependencyOutputs {
+		if dependencyConfig.mockOutputsForbidden(terragruntOptions) {
+			return nil, dependencyConfig.forbiddenMockOutputs(dependencyConfig.MockOutputs, nil)
+		}
+		if dependencyConfig.MockOutputs != nil {
+			return dependencyConfig.MockOutputs, nil
+		}
+		unknownOutputs := cty.DynamicVal
+		return &unknownOutputs, nil
+	}
+	if dependencyConfig.shouldGetPlannedOutputs(terragruntOptions) {
+		outputVal, found, err := getTerragruntPlannedOutput(dependencyConfig, terragruntOptions)
+		if err != nil || found {
+			return outputVal, err
+		}
+	}
+	return getTerragruntAppliedOutputElseConfiguredDefault(dependencyConfig, terragruntOptions)
+}
+
+// getTerragruntAppliedOutputElseConfiguredDefault returns the outputs in the state of the target config if it is
+// applied, or the mock outputs otherwise, as described in getTerragruntOutputIfAppliedElseConfiguredDefault.
+func getTerragruntAppliedOutputElseConfiguredDefault(dependencyConfig Dependency, terragruntOptions *options.TerragruntOptions) (*cty.Value, error) {
 	if dependencyConfig.shouldGetOutputs() {
 		outputVal, isEmpty, err := getTerragruntOutput(dependencyConfig, terragruntOptions)
 		if err != nil {
@@ -371,6 +605,11 @@ func getTerragruntOutputIfAppliedElseConfiguredDefault(dependencyConfig Dependen
 
 		if !isEmpty && dependencyConfig.shouldMergeMockOutputsWithState(terragruntOptions) && dependencyConfig.MockOutputs != nil {
 			mockMergeStrategy := dependencyConfig.getMockOutputsMergeStrategy()
+			if mockMergeStrategy != NoMerge && dependencyConfig.mockOutputsForbidden(terragruntOptions) {
+				if err := dependencyConfig.forbiddenMockOutputs(dependencyConfig.MockOutputs, outputVal); err != nil {
+					return nil, err
+				}
+			}
 			switch mockMergeStrategy {
 			case NoMerge:
 				return outputVal, nil
@@ -393,6 +632,9 @@ func getTerragruntOutputIfAppliedElseConfiguredDefault(dependencyConfig Dependen
 	targetConfig := getCleanedTargetConfigPath(dependencyConfig.ConfigPath, terragruntOptions.TerragruntConfigPath)
 	currentConfig := terragruntOptions.TerragruntConfigPath
 	if dependencyConfig.shouldReturnMockOutputs(terragruntOptions) {
+		if dependencyConfig.mockOutputsForbidden(terragruntOptions) {
+			return nil, dependencyConfig.forbiddenMockOutputs(dependencyConfig.MockOutputs, nil)
+		}
 		terragruntOptions.Logger.Debugf("WARNING: config %s is a dependency of %s that has no outputs, but mock outputs provided and returning those in dependency output.",
 			targetConfig,
 			currentConfig,
@@ -418,7 +660,35 @@ func (dependencyConfig Dependency) shouldReturnMockOutputs(terragruntOptions *op
 		dependencyConfig.MockOutputsAllowedTerraformCommands == nil ||
 			len(*dependencyConfig.MockOutputsAllowedTerraformCommands) == 0 ||
 			util.ListContainsElement(*dependencyConfig.MockOutputsAllowedTerraformCommands, terragruntOptions.OriginalTerraformCommand)
-	return defaultOutputsSet && allowedCommand || isRenderJsonCommand(terragruntOptions)
+	return defaultOutputsSet && allowedCommand || isRenderCommand(terragruntOptions)
+}
+
+// mockOutputsForbidden returns true if using the mock outputs of the dependency must fail, as strict mock outputs mode
+// is enabled and the command would deploy them.
+func (dependencyConfig Dependency) mockOutputsForbidden(terragruntOptions *options.TerragruntOptions) bool {
+	return dependencyConfig.strictMockOutputs &&
+		util.ListContainsElement(strictMockOutputsCommands, terragruntOptions.OriginalTerraformCommand) &&
+		!isRenderCommand(terragruntOptions)
+}
+
+// forbiddenMockOutputs returns a MockOutputsForbidden error listing the keys of the given mock outputs that are not in
+// the given outputs read from the state, or nil if there are none. All the mock outputs are listed if no outputs were
+// read, and an empty list of keys means all the outputs when there are no mock outputs.
+func (dependencyConfig Dependency) forbiddenMockOutputs(mockOutputs *cty.Value, outputs *cty.Value) error {
+	keys := []string{}
+	if mockOutputs != nil && mockOutputs.IsWhollyKnown() && !mockOutputs.IsNull() && mockOutputs.CanIterateElements() {
+		for it := mockOutputs.ElementIterator(); it.Next(); {
+			key, _ := it.Element()
+			if outputs != nil && outputs.IsKnown() && !outputs.IsNull() && outputs.Type().IsObjectType() && outputs.Type().HasAttribute(key.AsString()) {
+				continue
+			}
+			keys = append(keys, key.AsString())
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+	}
+	return MockOutputsForbidden{Name: dependencyConfig.Name, ConfigPath: dependencyConfig.ConfigPath, Keys: keys}
 }
 
 // Return the output from the state of another module, managed by terragrunt. This function will parse the provided
@@ -432,11 +702,13 @@ func getTerragruntOutput(dependencyConfig Dependency, terragruntOptions *options
 		return nil, true, errors.WithStackTrace(DependencyConfigNotFound{Path: targetConfig})
 	}
 
+	usedMockOutputs := false
 	jsonBytes, err := getOutputJsonWithCaching(targetConfig, terragruntOptions)
 	if err != nil {
-		if !isRenderJsonCommand(terragruntOptions) {
+		if !isRenderCommand(terragruntOptions) {
 			return nil, true, err
 		}
+		usedMockOutputs = true
 		terragruntOptions.Logger.Warnf("Failed to read outputs from %s referenced in %s as %s, fallback to mock outputs. Error: %v", targetConfig, terragruntOptions.TerragruntConfigPath, dependencyConfig.Name, err)
 		jsonBytes, err = json.Marshal(dependencyConfig.MockOutputs)
 		if err != nil {
@@ -445,11 +717,27 @@ func getTerragruntOutput(dependencyConfig Dependency, terragruntOptions *options
 	}
 	isEmpty := string(jsonBytes) == "{}"
 
-	outputMap, err := terraformOutputJsonToCtyValueMap(targetConfig, jsonBytes)
+	if dependencyConfig.referencedOutputs != nil {
+		outputNames := []string{}
+		for name := range dependencyConfig.referencedOutputs {
+			outputNames = append(outputNames, name)
+		}
+		sort.Strings(outputNames)
+		terragruntOptions.Logger.Debugf("Reading the outputs %v of %s referenced in %s as %s", outputNames, targetConfig, terragruntOptions.TerragruntConfigPath, dependencyConfig.Name)
+	}
+
+	outputMap, err := filteredTerraformOutputJsonToCtyValueMap(targetConfig, jsonBytes, dependencyConfig.referencedOutputs)
 	if err != nil {
 		return nil, isEmpty, err
 	}
 
+	// The mock outputs are used instead when there are no outputs, so only the outputs read from the state are checked.
+	if !isEmpty && !usedMockOutputs {
+		if err := dependencyConfig.checkExpectedOutputs(targetConfig, outputMap, nil); err != nil {
+			return nil, isEmpty, err
+		}
+	}
+
 	// We need to convert the value map to a single cty.Value at the end for use in the terragrunt config.
 	convertedOutput, err := gocty.ToCtyValue(outputMap, generateTypeFromValuesMap(outputMap))
 	if err != nil {
@@ -458,9 +746,14 @@ func getTerragruntOutput(dependencyConfig Dependency, terragruntOptions *options
 	return &convertedOutput, isEmpty, errors.WithStackTrace(err)
 }
 
-// isRenderJsonCommand This function will true if terragrunt was invoked with render-json
-func isRenderJsonCommand(terragruntOptions *options.TerragruntOptions) bool {
-	return util.ListContainsElement(terragruntOptions.TerraformCliArgs, renderJsonCommand)
+// isRenderCommand This function will true if terragrunt was invoked with render-json or render
+func isRenderCommand(terragruntOptions *options.TerragruntOptions) bool {
+	for _, command := range renderCommands {
+		if util.ListContainsElement(terragruntOptions.TerraformCliArgs, command) {
+			return true
+		}
+	}
+	return false
 }
 
 // getOutputJsonWithCaching will run terragrunt output on the target config if it is not already cached.
@@ -476,12 +769,21 @@ func getOutputJsonWithCaching(targetConfig string, terragruntOptions *options.Te
 	// output" log for the dependency.
 	terragruntOptions.Logger.Debugf("Getting output of dependency %s for config %s", targetConfig, terragruntOptions.TerragruntConfigPath)
 
+	ttl, err := dependencyOutputCacheTTL(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
 	// Look up if we have already run terragrunt output for this target config
-	rawJsonBytes, hasRun := jsonOutputCache.Load(targetConfig)
+	rawCachedOutput, hasRun := jsonOutputCache.Load(targetConfig)
 	if hasRun {
-		// Cache hit, so return cached output
-		terragruntOptions.Logger.Debugf("%s was run before. Using cached output.", targetConfig)
-		return rawJsonBytes.([]byte), nil
+		cachedOutput := rawCachedOutput.(cachedOutputJson)
+		if ttl == 0 || time.Since(cachedOutput.fetchedAt) <= ttl {
+			// Cache hit, so return cached output
+			terragruntOptions.Logger.Debugf("%s was run before. Using cached output.", targetConfig)
+			return cachedOutput.jsonBytes, nil
+		}
+		terragruntOptions.Logger.Debugf("The cached output of %s is older than %s. Fetching it again.", targetConfig, ttl)
 	}
 
 	// Cache miss, so look up the output and store in cache
@@ -497,7 +799,7 @@ func getOutputJsonWithCaching(targetConfig string, terragruntOptions *options.Te
 		newJsonBytes = newJsonBytes[index:]
 	}
 
-	jsonOutputCache.Store(targetConfig, newJsonBytes)
+	jsonOutputCache.Store(targetConfig, cachedOutputJson{jsonBytes: newJsonBytes, fetchedAt: time.Now()})
 	return newJsonBytes, nil
 }
 
@@ -608,16 +910,23 @@ func getTerragruntOutputJson(terragruntOptions *options.TerragruntOptions, targe
 		return runTerragruntOutputJson(targetTGOptions, targetConfig)
 	}
 
-	// In optimization mode, see if there is already an init-ed folder that terragrunt can use, and if so, run
-	// `terraform output` in the working directory.
-	isInit, workingDir, err := terragruntAlreadyInit(targetTGOptions, targetConfig)
-	if err != nil {
-		return nil, err
+	fetchOutputs := func() ([]byte, error) {
+		// In optimization mode, see if there is already an init-ed folder that terragrunt can use, and if so, run
+		// `terraform output` in the working directory.
+		isInit, workingDir, err := terragruntAlreadyInit(targetTGOptions, targetConfig)
+		if err != nil {
+			return nil, err
+		}
+		if isInit {
+			return getTerragruntOutputJsonFromInitFolder(targetTGOptions, workingDir, remoteStateTGConfig.GetIAMRoleOptions())
+		}
+		return getTerragruntOutputJsonFromRemoteState(targetTGOptions, targetConfig, remoteStateTGConfig.RemoteState, remoteStateTGConfig.GetIAMRoleOptions())
 	}
-	if isInit {
-		return getTerragruntOutputJsonFromInitFolder(targetTGOptions, workingDir, remoteStateTGConfig.GetIAMRoleOptions())
+
+	if terragruntOptions.DependencyOutputCacheDir != "" {
+		return getOutputJsonWithDiskCache(targetTGOptions, targetConfig, remoteStateTGConfig.RemoteState, remoteStateTGConfig.GetIAMRoleOptions(), fetchOutputs)
 	}
-	return getTerragruntOutputJsonFromRemoteState(targetTGOptions, targetConfig, remoteStateTGConfig.RemoteState, remoteStateTGConfig.GetIAMRoleOptions())
+	return fetchOutputs()
 }
 
 // canGetRemoteState returns true if the remote state block is not nil and dependency optimization is not disabled
@@ -680,7 +989,7 @@ func getTerragruntOutputJsonFromInitFolder(terragruntOptions *options.Terragrunt
 	}
 	jsonString := out.Stdout
 	jsonBytes := []byte(strings.TrimSpace(jsonString))
-	terragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, jsonString)
+	terragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, util.RedactSecrets(jsonString))
 	return jsonBytes, nil
 }
 
@@ -725,7 +1034,10 @@ func getTerragruntOutputJsonFromRemoteState(
 	}
 
 	// To speed up dependencies processing it is possible to retrieve its output directly from the backend without init dependencies
-	if terragruntOptions.FetchDependencyOutputFromState {
+	// The encrypted state can only be read by OpenTofu.
+	if terragruntOptions.FetchDependencyOutputFromState && len(remoteState.Encryption) > 0 {
+		terragruntOptions.Logger.Debugf("The state of %s is encrypted, it can not be fetched directly from the backend", targetConfig)
+	} else if terragruntOptions.FetchDependencyOutputFromState {
 		switch backend := remoteState.Backend; backend {
 		case "s3":
 			jsonBytes, err := getTerragruntOutputJsonFromRemoteStateS3(
@@ -735,7 +1047,14 @@ func getTerragruntOutputJsonFromRemoteState(
 			if err != nil {
 				return nil, err
 			}
-			terragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s using s3 bucket", targetConfig, jsonBytes)
+			terragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s using s3 bucket", targetConfig, util.RedactSecrets(string(jsonBytes)))
+			return jsonBytes, nil
+		case "gcs", "azurerm":
+			jsonBytes, err := getTerragruntOutputJsonFromRemoteStateObject(targetTGOptions, remoteState)
+			if err != nil {
+				return nil, err
+			}
+			terragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s using %s backend", targetConfig, util.RedactSecrets(string(jsonBytes)), backend)
 			return jsonBytes, nil
 		default:
 			terragruntOptions.Logger.Errorf("FetchDependencyOutputFromState is not supported for backend %s, falling back to normal method", backend)
@@ -773,7 +1092,7 @@ func getTerragruntOutputJsonFromRemoteState(
 	}
 	jsonString := out.Stdout
 	jsonBytes := []byte(strings.TrimSpace(jsonString))
-	terragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, jsonString)
+	terragruntOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, util.RedactSecrets(jsonString))
 
 	return jsonBytes, nil
 
@@ -817,12 +1136,37 @@ func getTerragruntOutputJsonFromRemoteStateS3(
 	if err != nil {
 		return nil, err
 	}
-	jsonState := string(steateBody)
-	jsonMap := make(map[string]interface{})
-	err = json.Unmarshal([]byte(jsonState), &jsonMap)
+	return stateOutputsJson(steateBody)
+}
+
+// getTerragruntOutputJsonFromRemoteStateObject pulls the output directly from the state object of a gcs or azurerm
+// backend without calling Terraform
+func getTerragruntOutputJsonFromRemoteStateObject(
+	terragruntOptions *options.TerragruntOptions,
+	remoteState *remote.RemoteState,
+) ([]byte, error) {
+	var stateBody []byte
+	var err error
+
+	switch remoteState.Backend {
+	case "gcs":
+		stateBody, err = remote.ReadGCSState(remoteState.Config, terragruntOptions)
+	case "azurerm":
+		stateBody, err = remote.ReadAzureRMState(remoteState.Config, terragruntOptions)
+	}
 	if err != nil {
 		return nil, err
 	}
+
+	return stateOutputsJson(stateBody)
+}
+
+// stateOutputsJson returns the outputs of the given state, in the format of `terraform output -json`.
+func stateOutputsJson(stateBody []byte) ([]byte, error) {
+	jsonMap := make(map[string]interface{})
+	if err := json.Unmarshal(stateBody, &jsonMap); err != nil {
+		return nil, err
+	}
 	jsonOutputs, err := json.Marshal(jsonMap["outputs"])
 	if err != nil {
 		return nil, err
@@ -871,13 +1215,20 @@ func runTerragruntOutputJson(targetTGOptions *options.TerragruntOptions, targetC
 	}
 	jsonString := stdoutBuffer.String()
 	jsonBytes := []byte(strings.TrimSpace(jsonString))
-	targetTGOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, jsonString)
+	targetTGOptions.Logger.Debugf("Retrieved output from %s as json: %s", targetConfig, util.RedactSecrets(jsonString))
 	return jsonBytes, nil
 }
 
 // terraformOutputJsonToCtyValueMap takes the terraform output json and converts to a mapping between output keys to the
 // parsed cty.Value encoding of the json objects.
 func terraformOutputJsonToCtyValueMap(targetConfig string, jsonBytes []byte) (map[string]cty.Value, error) {
+	return filteredTerraformOutputJsonToCtyValueMap(targetConfig, jsonBytes, nil)
+}
+
+// filteredTerraformOutputJsonToCtyValueMap is like terraformOutputJsonToCtyValueMap, but only converts the given
+// outputs, so that the large outputs that are not referenced are never decoded. All the outputs are converted when
+// outputNames is nil.
+func filteredTerraformOutputJsonToCtyValueMap(targetConfig string, jsonBytes []byte, outputNames map[string]bool) (map[string]cty.Value, error) {
 	// When getting all outputs, terraform returns a json with the data containing metadata about the types, so we
 	// can't quite return the data directly. Instead, we will need further processing to get the output we want.
 	// To do so, we first Unmarshal the json into a simple go map to a OutputMeta struct.
@@ -894,6 +1245,9 @@ func terraformOutputJsonToCtyValueMap(targetConfig string, jsonBytes []byte) (ma
 	}
 	flattenedOutput := map[string]cty.Value{}
 	for k, v := range outputs {
+		if outputNames != nil && !outputNames[k] {
+			continue
+		}
 		outputType, err := ctyjson.UnmarshalType(v.Type)
 		if err != nil {
 			return nil, errors.WithStackTrace(TerragruntOutputParsingError{Path: targetConfig, Err: err})
@@ -910,6 +1264,7 @@ func terraformOutputJsonToCtyValueMap(targetConfig string, jsonBytes []byte) (ma
 // ClearOutputCache clears the output cache. Useful during testing.
 func ClearOutputCache() {
 	jsonOutputCache = sync.Map{}
+	plannedOutputsCache = sync.Map{}
 }
 
 // runTerraformInitForDependencyOutput will run terraform init in a mode that doesn't pull down plugins or modules. Note
@@ -980,8 +1335,45 @@ func (err TerragruntOutputTargetNoOutputs) Error() string {
 	)
 }
 
+type OrderingOnlyDependencyWithOutputs struct {
+	Name string
+}
+
+func (err OrderingOnlyDependencyWithOutputs) Error() string {
+	return fmt.Sprintf("Dependency %s is ordering only, so its outputs are never read and it can not set mock_outputs, expected_outputs or derived.", err.Name)
+}
+
 type DependencyCycle []string
 
 func (err DependencyCycle) Error() string {
 	return fmt.Sprintf("Found a dependency cycle between modules: %s", strings.Join([]string(err), " -> "))
 }
+
+type MockOutputsForbidden struct {
+	Name       string
+	ConfigPath string
+	// The keys of the mock outputs that would have been used, or all the outputs if empty.
+	Keys []string
+}
+
+func (err MockOutputsForbidden) Error() string {
+	outputs := "all outputs"
+	if len(err.Keys) > 0 {
+		outputs = strings.Join(err.Keys, ", ")
+	}
+	return fmt.Sprintf("dependency %s (%s): %s", err.Name, err.ConfigPath, outputs)
+}
+
+type DependencyMockOutputsForbidden struct {
+	ConfigPath   string
+	Command      string
+	Dependencies []MockOutputsForbidden
+}
+
+func (err DependencyMockOutputsForbidden) Error() string {
+	dependencies := []string{}
+	for _, dependency := range err.Dependencies {
+		dependencies = append(dependencies, "\n  - "+dependency.Error())
+	}
+	return fmt.Sprintf("Refusing to run %s in %s with the mock outputs of dependencies, as strict mock outputs mode is enabled. Apply the dependencies first. The outputs that could not be resolved are:%s", err.Command, err.ConfigPath, strings.Join(dependencies, ""))
+}
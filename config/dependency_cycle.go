@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// DependencyDeclaration is the dependency or dependencies block of a config that makes it depend on another module,
+// i.e. an edge of the dependency graph.
+type DependencyDeclaration struct {
+	// The config or module that depends on the other module.
+	From string
+	// The config or module it depends on.
+	To string
+	// The block declaring the dependency, e.g. `dependency "vpc"` or `dependencies`, or empty if it was not found.
+	Block string
+	// The file and line the block is at, which is the included config when the block is inherited. The line is 0 when
+	// it is unknown, e.g. for configs in the JSON syntax.
+	File string
+	Line int
+}
+
+// OnlyOrdersRun returns true if the dependency is only declared in a dependencies block, so that no outputs are read
+// from the module it depends on, and it only orders the run.
+func (declaration DependencyDeclaration) OnlyOrdersRun() bool {
+	return declaration.Block == MetadataDependencies
+}
+
+func (declaration DependencyDeclaration) String() string {
+	edge := fmt.Sprintf("%s -> %s", declaration.From, declaration.To)
+	switch {
+	case declaration.Block == "":
+		return edge
+	case declaration.Line == 0:
+		return fmt.Sprintf("%s: %s in %s", edge, declaration.Block, declaration.File)
+	default:
+		return fmt.Sprintf("%s: %s at %s:%d", edge, declaration.Block, declaration.File, declaration.Line)
+	}
+}
+
+// FindDependencyDeclaration returns where the given config, parsed from configPath, declares its dependency on the
+// module in targetDir. The dependency blocks are looked for first, as they also read the outputs of the module, then
+// the dependencies block. The blocks are looked for in the config itself, then in the configs it includes.
+func FindDependencyDeclaration(terragruntConfig *TerragruntConfig, configPath string, targetDir string) DependencyDeclaration {
+	declaration := DependencyDeclaration{From: filepath.Dir(configPath), To: targetDir}
+
+	configDir := filepath.Dir(configPath)
+	targetDir = util.CleanPath(targetDir)
+	dependsOn := func(path string) bool {
+		if !filepath.IsAbs(path) {
+			path = util.JoinPath(configDir, path)
+		}
+		// The paths may point at the config file of the dependency rather than its dir.
+		if util.IsFile(path) {
+			path = filepath.Dir(path)
+		}
+		return util.CleanPath(path) == targetDir
+	}
+
+	blockType, blockLabel := "", ""
+	for _, dependency := range terragruntConfig.TerragruntDependencies {
+		if dependency.isEnabled() && dependsOn(dependency.ConfigPath) {
+			blockType, blockLabel = MetadataDependency, dependency.Name
+			declaration.Block = fmt.Sprintf("%s %q", MetadataDependency, dependency.Name)
+			break
+		}
+	}
+	if blockType == "" && terragruntConfig.Dependencies != nil {
+		for _, path := range terragruntConfig.Dependencies.Paths {
+			if dependsOn(path) {
+				blockType = MetadataDependencies
+				declaration.Block = MetadataDependencies
+				break
+			}
+		}
+	}
+	if blockType == "" {
+		return declaration
+	}
+
+	files := []string{configPath}
+	includeNames := []string{}
+	for name := range terragruntConfig.ProcessedIncludes {
+		includeNames = append(includeNames, name)
+	}
+	sort.Strings(includeNames)
+	for _, name := range includeNames {
+		includePath := terragruntConfig.ProcessedIncludes[name].Path
+		if !filepath.IsAbs(includePath) {
+			includePath = util.JoinPath(configDir, includePath)
+		}
+		files = append(files, includePath)
+	}
+
+	declaration.File = configPath
+	for _, file := range files {
+		if line, found := blockLine(file, blockType, blockLabel); found {
+			declaration.File = file
+			declaration.Line = line
+			break
+		}
+	}
+	return declaration
+}
+
+// blockLine returns the line of the first block of the given type and label in the given file, if it is in the native
+// HCL syntax. The label is ignored when empty.
+func blockLine(path string, blockType string, blockLabel string) (int, bool) {
+	if strings.HasSuffix(path, ".json") {
+		return 0, false
+	}
+
+	file, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return 0, false
+	}
+	body, isNativeSyntax := file.Body.(*hclsyntax.Body)
+	if !isNativeSyntax {
+		return 0, false
+	}
+
+	for _, block := range body.Blocks {
+		if block.Type != blockType {
+			continue
+		}
+		if blockLabel != "" && (len(block.Labels) == 0 || block.Labels[0] != blockLabel) {
+			continue
+		}
+		return block.DefRange().Start.Line, true
+	}
+	return 0, false
+}
+
+// dependencyBlockCycleDeclarations returns the dependency blocks making up the given cycle of config paths. The
+// configs are partially parsed again, which is cheap as they were all parsed while walking the graph.
+func dependencyBlockCycleDeclarations(cycle DependencyCycle, terragruntOptions *options.TerragruntOptions) []DependencyDeclaration {
+	declarations := []DependencyDeclaration{}
+	for i := 0; i < len(cycle)-1; i++ {
+		configPath, targetConfigPath := cycle[i], cycle[i+1]
+		declaration := DependencyDeclaration{From: filepath.Dir(configPath), To: filepath.Dir(targetConfigPath)}
+
+		configOptions := cloneTerragruntOptionsForDependency(terragruntOptions, configPath)
+		terragruntConfig, err := PartialParseConfigFile(configPath, configOptions, nil, []PartialDecodeSectionType{DependencyBlock})
+		if err == nil {
+			declaration = FindDependencyDeclaration(terragruntConfig, configPath, filepath.Dir(targetConfigPath))
+		} else {
+			terragruntOptions.Logger.Debugf("Could not find the dependency block of %s on %s: %v", configPath, targetConfigPath, err)
+		}
+		declarations = append(declarations, declaration)
+	}
+	return declarations
+}
+
+// Custom error types
+
+// DependencyCycleWithDeclarations is a dependency cycle error along with the blocks declaring each of its edges, so
+// that the cycle can be broken without having to search for them.
+type DependencyCycleWithDeclarations struct {
+	Cycle        error
+	Declarations []DependencyDeclaration
+	// Hints on how to break the cycles, when requested with --terragrunt-break-cycle-hints.
+	Hints []string
+}
+
+func (err DependencyCycleWithDeclarations) Error() string {
+	lines := []string{err.Cycle.Error(), "The dependencies making up the cycle are declared by:"}
+	for _, declaration := range err.Declarations {
+		lines = append(lines, "  "+declaration.String())
+	}
+	if len(err.Hints) > 0 {
+		lines = append(lines, "Hints to break the cycle:")
+		for _, hint := range err.Hints {
+			lines = append(lines, "  - "+hint)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (err DependencyCycleWithDeclarations) Unwrap() error {
+	return err.Cycle
+}
@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// derivedOutputsVariable is the variable the outputs of a dependency are exposed as to its derived values.
+const derivedOutputsVariable = "outputs"
+
+// isExpressionSet returns true if the given optional attribute expression is set. When it is not set, the decoder sets
+// it to an expression that returns null.
+func isExpressionSet(expr hcl.Expression) bool {
+	if expr == nil {
+		return false
+	}
+	value, diags := expr.Value(nil)
+	return diags.HasErrors() || !value.IsNull()
+}
+
+// hasDerived returns true if the derived attribute of the dependency is set.
+func (dependencyConfig Dependency) hasDerived() bool {
+	return isExpressionSet(dependencyConfig.Derived)
+}
+
+// addDerivedOutputReferences adds the outputs referenced by the derived values of the dependency to the outputs read
+// from its state, as the derived values are evaluated whether or not they are referenced.
+func (dependencyConfig *Dependency) addDerivedOutputReferences() {
+	if dependencyConfig.referencedOutputs == nil || !dependencyConfig.hasDerived() {
+		return
+	}
+	for _, traversal := range dependencyConfig.Derived.Variables() {
+		if traversal.RootName() != derivedOutputsVariable {
+			continue
+		}
+		outputName, ok := traversalStepName(traversal, 1)
+		if !ok {
+			// The outputs are used as a whole, e.g. with keys(outputs).
+			dependencyConfig.referencedOutputs = nil
+			return
+		}
+		dependencyConfig.referencedOutputs[outputName] = true
+	}
+}
+
+// evaluateDerived evaluates the derived values of the dependency with the given outputs, in the context of the config
+// the dependency block is in, so that the functions and locals are available along with the outputs.
+func (dependencyConfig Dependency) evaluateDerived(outputs cty.Value) (cty.Value, error) {
+	evalContext := &hcl.EvalContext{}
+	if dependencyConfig.evalContext != nil {
+		evalContext = dependencyConfig.evalContext.NewChild()
+	}
+	evalContext.Variables = map[string]cty.Value{derivedOutputsVariable: outputs}
+
+	derived, diags := dependencyConfig.Derived.Value(evalContext)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.WithStackTrace(diags)
+	}
+	if derived.IsKnown() && (derived.IsNull() || !(derived.Type().IsObjectType() || derived.Type().IsMapType())) {
+		return cty.NilVal, errors.WithStackTrace(InvalidDerived{Name: dependencyConfig.Name})
+	}
+	return derived, nil
+}
+
+// Custom error types
+
+type InvalidDerived struct {
+	Name string
+}
+
+func (err InvalidDerived) Error() string {
+	return fmt.Sprintf("The derived attribute of dependency %s must be a map of names to values computed from its outputs, e.g. { vpc_cidr = outputs.vpc.cidr_block }.", err.Name)
+}
@@ -0,0 +1,97 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testDerivedConfig = `
+locals {
+  tier = "private"
+}
+
+dependency "vpc" {
+  config_path  = "../vpc"
+  skip_outputs = true
+
+  mock_outputs = {
+    subnets = [
+      { id = "subnet-a", tier = "private" },
+      { id = "subnet-b", tier = "public" },
+      { id = "subnet-c", tier = "private" },
+    ]
+  }
+
+  derived = {
+    private_subnet_ids = [for s in outputs.subnets : s.id if s.tier == local.tier]
+    first_subnet_id    = upper(outputs.subnets[0].id)
+  }
+}
+
+inputs = {
+  subnet_ids = dependency.vpc.derived.private_subnet_ids
+  first      = dependency.vpc.derived.first_subnet_id
+  all        = length(dependency.vpc.outputs.subnets)
+}
+`
+
+func derivedTestConfigPath(t *testing.T) string {
+	rootDir := t.TempDir()
+	vpcConfig := filepath.Join(rootDir, "vpc", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(vpcConfig), os.ModePerm))
+	require.NoError(t, os.WriteFile(vpcConfig, []byte(``), 0644))
+	return filepath.Join(rootDir, "app", DefaultTerragruntConfigPath)
+}
+
+func TestDependencyDerived(t *testing.T) {
+	t.Parallel()
+
+	configPath := derivedTestConfigPath(t)
+	terragruntConfig, err := ParseConfigString(testDerivedConfig, mockOptionsForTestWithConfigPath(t, configPath), nil, configPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []interface{}{"subnet-a", "subnet-c"}, terragruntConfig.Inputs["subnet_ids"])
+	assert.Equal(t, "SUBNET-A", terragruntConfig.Inputs["first"])
+	assert.Equal(t, float64(3), terragruntConfig.Inputs["all"])
+}
+
+func TestDependencyDerivedMustBeMap(t *testing.T) {
+	t.Parallel()
+
+	config := `
+dependency "vpc" {
+  config_path  = "../vpc"
+  skip_outputs = true
+
+  mock_outputs = {
+    vpc_id = "vpc-1"
+  }
+
+  derived = outputs.vpc_id
+}
+`
+
+	configPath := derivedTestConfigPath(t)
+	_, err := ParseConfigString(config, mockOptionsForTestWithConfigPath(t, configPath), nil, configPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	_, ok := errors.Unwrap(err).(InvalidDerived)
+	assert.True(t, ok, "unexpected error: %v", err)
+}
+
+func TestDerivedOutputReferences(t *testing.T) {
+	t.Parallel()
+
+	dependency := Dependency{Name: "vpc", referencedOutputs: map[string]bool{"vpc_id": true}}
+	dependency.Derived = parseTestExpression(t, `{ ids = [for s in outputs.subnets : s.id], cidr = local.cidr }`)
+	dependency.addDerivedOutputReferences()
+	assert.Equal(t, map[string]bool{"vpc_id": true, "subnets": true}, dependency.referencedOutputs)
+
+	dependency.Derived = parseTestExpression(t, `{ names = keys(outputs) }`)
+	dependency.addDerivedOutputReferences()
+	assert.Nil(t, dependency.referencedOutputs)
+}
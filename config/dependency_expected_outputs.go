@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+// hasExpectedOutputs returns true if the expected_outputs attribute of the dependency is set.
+func (dependencyConfig Dependency) hasExpectedOutputs() bool {
+	return isExpressionSet(dependencyConfig.ExpectedOutputs)
+}
+
+// expectedOutputTypes returns the types of the outputs declared in the expected_outputs attribute of the dependency,
+// which is a map of output names to type constraints, e.g. { vpc_id = string, subnet_ids = list(string) }.
+func (dependencyConfig Dependency) expectedOutputTypes() (map[string]cty.Type, error) {
+	pairs, diags := hcl.ExprMap(dependencyConfig.ExpectedOutputs)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(InvalidExpectedOutputs{Name: dependencyConfig.Name, Err: diags})
+	}
+
+	types := map[string]cty.Type{}
+	for _, pair := range pairs {
+		name := hcl.ExprAsKeyword(pair.Key)
+		if name == "" {
+			key, diags := pair.Key.Value(nil)
+			if diags.HasErrors() {
+				return nil, errors.WithStackTrace(InvalidExpectedOutputs{Name: dependencyConfig.Name, Err: diags})
+			}
+			if key.Type() != cty.String || key.IsNull() {
+				return nil, errors.WithStackTrace(InvalidExpectedOutputs{Name: dependencyConfig.Name, Err: fmt.Errorf("the output names must be strings")})
+			}
+			name = key.AsString()
+		}
+
+		outputType, diags := typeexpr.TypeConstraint(pair.Value)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(InvalidExpectedOutputs{Name: dependencyConfig.Name, Err: diags})
+		}
+		types[name] = outputType
+	}
+	return types, nil
+}
+
+// checkExpectedOutputs returns a DependencyOutputsMismatch error listing the outputs declared in the expected_outputs
+// of the dependency that are missing from the given outputs read from the target config, or whose type doesn't match.
+// Only the outputs that were read are checked, i.e. the referenced ones, and the ignored outputs are skipped, e.g. the
+// planned outputs that are only known after apply.
+func (dependencyConfig Dependency) checkExpectedOutputs(targetConfig string, outputs map[string]cty.Value, ignored map[string]bool) error {
+	if !dependencyConfig.hasExpectedOutputs() {
+		return nil
+	}
+
+	expectedTypes, err := dependencyConfig.expectedOutputTypes()
+	if err != nil {
+		return err
+	}
+
+	names := []string{}
+	for name := range expectedTypes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	differences := []OutputDifference{}
+	for _, name := range names {
+		if ignored[name] || (dependencyConfig.referencedOutputs != nil && !dependencyConfig.referencedOutputs[name]) {
+			continue
+		}
+		expectedType := expectedTypes[name]
+		value, found := outputs[name]
+		if !found {
+			differences = append(differences, OutputDifference{Name: name, ExpectedType: expectedType})
+			continue
+		}
+		if !outputConformsToType(value, expectedType) {
+			actualType := value.Type()
+			differences = append(differences, OutputDifference{Name: name, ExpectedType: expectedType, ActualType: &actualType})
+		}
+	}
+
+	if len(differences) == 0 {
+		return nil
+	}
+	return errors.WithStackTrace(DependencyOutputsMismatch{Name: dependencyConfig.Name, ConfigPath: targetConfig, Differences: differences})
+}
+
+// outputConformsToType returns true if the given output value matches the given type constraint. The primitive types
+// must match exactly, as an output changing from a number to a string is a change of the interface of the module even
+// though it converts, while the collections only need to convert, as terraform outputs lists as tuples and maps as
+// objects unless they are typed.
+func outputConformsToType(value cty.Value, expectedType cty.Type) bool {
+	if expectedType == cty.DynamicPseudoType {
+		return true
+	}
+	if expectedType.IsPrimitiveType() {
+		return value.Type().Equals(expectedType)
+	}
+	_, err := convert.Convert(value, expectedType)
+	return err == nil
+}
+
+// Custom error types
+
+type InvalidExpectedOutputs struct {
+	Name string
+	Err  error
+}
+
+func (err InvalidExpectedOutputs) Error() string {
+	return fmt.Sprintf("The expected_outputs of dependency %s must be a map of output names to types, e.g. { vpc_id = string }: %v", err.Name, err.Err)
+}
+
+// OutputDifference is an output declared in the expected_outputs of a dependency that doesn't match the outputs of the
+// target config: it is missing when ActualType is nil, or of another type otherwise.
+type OutputDifference struct {
+	Name         string
+	ExpectedType cty.Type
+	ActualType   *cty.Type
+}
+
+func (difference OutputDifference) String() string {
+	if difference.ActualType == nil {
+		return fmt.Sprintf("- %s: expected %s, but the output is missing", difference.Name, typeexpr.TypeString(difference.ExpectedType))
+	}
+	return fmt.Sprintf("~ %s: expected %s, got %s", difference.Name, typeexpr.TypeString(difference.ExpectedType), typeexpr.TypeString(*difference.ActualType))
+}
+
+type DependencyOutputsMismatch struct {
+	Name        string
+	ConfigPath  string
+	Differences []OutputDifference
+}
+
+func (err DependencyOutputsMismatch) Error() string {
+	differences := []string{}
+	for _, difference := range err.Differences {
+		differences = append(differences, "\n  "+difference.String())
+	}
+	return fmt.Sprintf("The outputs of dependency %s (%s) don't match its expected_outputs, the interface of the module may have changed:%s", err.Name, err.ConfigPath, strings.Join(differences, ""))
+}
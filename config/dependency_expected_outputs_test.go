@@ -0,0 +1,85 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testExpectedOutputsJSON = `{
+  "vpc_id": {"sensitive": false, "type": "number", "value": 42},
+  "subnet_ids": {"sensitive": false, "type": ["tuple", ["string", "string"]], "value": ["subnet-a", "subnet-b"]}
+}`
+
+func expectedOutputsDependency(t *testing.T, expectedOutputs string) (Dependency, string) {
+	expr := parseTestExpression(t, expectedOutputs)
+
+	vpcConfig := filepath.Join(t.TempDir(), "vpc", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(vpcConfig), os.ModePerm))
+	require.NoError(t, os.WriteFile(vpcConfig, []byte(``), 0644))
+	jsonOutputCache.Store(vpcConfig, cachedOutputJson{jsonBytes: []byte(testExpectedOutputsJSON), fetchedAt: time.Now()})
+
+	return Dependency{Name: "vpc", ConfigPath: "../vpc", ExpectedOutputs: expr}, filepath.Join(filepath.Dir(filepath.Dir(vpcConfig)), "app", DefaultTerragruntConfigPath)
+}
+
+func parseTestExpression(t *testing.T, expression string) hcl.Expression {
+	expr, diags := hclsyntax.ParseExpression([]byte(expression), "", hcl.InitialPos)
+	require.False(t, diags.HasErrors(), diags.Error())
+	return expr
+}
+
+func TestExpectedOutputsMatch(t *testing.T) {
+	t.Parallel()
+
+	dependency, configPath := expectedOutputsDependency(t, `{ vpc_id = number, subnet_ids = list(string) }`)
+	opts := mockOptionsForTestWithConfigPath(t, configPath)
+
+	value, err := getTerragruntOutputIfAppliedElseConfiguredDefault(dependency, opts)
+	require.NoError(t, err)
+	assert.True(t, value.Type().HasAttribute("vpc_id"))
+}
+
+func TestExpectedOutputsMismatch(t *testing.T) {
+	t.Parallel()
+
+	dependency, configPath := expectedOutputsDependency(t, `{ vpc_id = string, subnet_ids = list(string), cidr_block = string }`)
+	opts := mockOptionsForTestWithConfigPath(t, configPath)
+
+	_, err := getTerragruntOutputIfAppliedElseConfiguredDefault(dependency, opts)
+	require.Error(t, err)
+	mismatch, ok := errors.Unwrap(err).(DependencyOutputsMismatch)
+	require.True(t, ok, "unexpected error: %v", err)
+	require.Len(t, mismatch.Differences, 2)
+	assert.Equal(t, "- cidr_block: expected string, but the output is missing", mismatch.Differences[0].String())
+	assert.Equal(t, "~ vpc_id: expected string, got number", mismatch.Differences[1].String())
+}
+
+func TestExpectedOutputsOnlyChecksReferencedOutputs(t *testing.T) {
+	t.Parallel()
+
+	dependency, configPath := expectedOutputsDependency(t, `{ vpc_id = string, subnet_ids = list(string) }`)
+	dependency.referencedOutputs = map[string]bool{"subnet_ids": true}
+	opts := mockOptionsForTestWithConfigPath(t, configPath)
+
+	_, err := getTerragruntOutputIfAppliedElseConfiguredDefault(dependency, opts)
+	require.NoError(t, err)
+}
+
+func TestExpectedOutputsInvalidType(t *testing.T) {
+	t.Parallel()
+
+	dependency, configPath := expectedOutputsDependency(t, `{ vpc_id = text }`)
+	opts := mockOptionsForTestWithConfigPath(t, configPath)
+
+	_, err := getTerragruntOutputIfAppliedElseConfiguredDefault(dependency, opts)
+	require.Error(t, err)
+	_, ok := errors.Unwrap(err).(InvalidExpectedOutputs)
+	assert.True(t, ok, "unexpected error: %v", err)
+}
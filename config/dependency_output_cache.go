@@ -0,0 +1,201 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// getOutputJsonWithDiskCache returns the outputs of the target config from the cache in the DependencyOutputCacheDir,
+// which is shared by all the terragrunt processes, e.g. the ones running the sibling modules of a pipeline. The outputs
+// are cached by the version of the state of the target config, so that they are fetched again whenever its state
+// changes, and for at most the DependencyOutputCacheTTL if set. When the version of the state can't be determined, the
+// outputs are only cached if the TTL is set, and are fetched with fetchOutputs without being cached otherwise.
+func getOutputJsonWithDiskCache(
+	terragruntOptions *options.TerragruntOptions,
+	targetConfig string,
+	remoteState *remote.RemoteState,
+	iamRoleOpts options.IAMRoleOptions,
+	fetchOutputs func() ([]byte, error),
+) ([]byte, error) {
+	ttl, err := dependencyOutputCacheTTL(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	// The encrypted states are never cached, as the outputs would be written to the cache in plain text.
+	if len(remoteState.Encryption) > 0 {
+		terragruntOptions.Logger.Debugf("The state of %s is encrypted, not caching its outputs", targetConfig)
+		return fetchOutputs()
+	}
+
+	stateVersion, err := dependencyStateVersion(terragruntOptions, targetConfig, remoteState, iamRoleOpts)
+	if err != nil {
+		terragruntOptions.Logger.Debugf("Could not get the version of the state of %s: %v", targetConfig, err)
+		stateVersion = ""
+	}
+	if stateVersion == "" && ttl == 0 {
+		terragruntOptions.Logger.Debugf("Not caching the outputs of %s, as the version of its state is unknown and no TTL is set", targetConfig)
+		return fetchOutputs()
+	}
+
+	cachePath := dependencyOutputCachePath(terragruntOptions.DependencyOutputCacheDir, targetConfig, stateVersion)
+	if jsonBytes, found := readDependencyOutputCache(cachePath, ttl); found {
+		terragruntOptions.Logger.Debugf("Using the outputs of %s cached in %s for the state version %q", targetConfig, cachePath, stateVersion)
+		return jsonBytes, nil
+	}
+
+	jsonBytes, err := fetchOutputs()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := writeDependencyOutputCache(cachePath, jsonBytes); err != nil {
+		terragruntOptions.Logger.Warnf("Failed to cache the outputs of %s in %s: %v", targetConfig, cachePath, err)
+	}
+
+	return jsonBytes, nil
+}
+
+// dependencyStateVersion returns a string identifying the version of the state of the given remote state, which
+// changes whenever the state is written, or an empty string if the version can't be determined for the backend.
+func dependencyStateVersion(terragruntOptions *options.TerragruntOptions, targetConfig string, remoteState *remote.RemoteState, iamRoleOpts options.IAMRoleOptions) (string, error) {
+	switch remoteState.Backend {
+	case "s3":
+		return s3StateVersion(terragruntOptions, targetConfig, remoteState, iamRoleOpts)
+	default:
+		terragruntOptions.Logger.Debugf("The version of the state of %s can not be determined for the %s backend", targetConfig, remoteState.Backend)
+		return "", nil
+	}
+}
+
+// s3StateVersion returns the version id and the ETag of the state object, which change whenever the state is written.
+func s3StateVersion(terragruntOptions *options.TerragruntOptions, targetConfig string, remoteState *remote.RemoteState, iamRoleOpts options.IAMRoleOptions) (string, error) {
+	s3ConfigExtended, err := remote.ParseExtendedS3Config(remoteState.Config)
+	if err != nil {
+		return "", err
+	}
+
+	targetOptions := cloneTerragruntOptionsForDependency(terragruntOptions, targetConfig)
+	targetOptions.IAMRoleOptions = options.MergeIAMRoleOptions(iamRoleOpts, targetOptions.OriginalIAMRoleOptions)
+
+	s3Client, err := remote.CreateS3Client(s3ConfigExtended.GetAwsSessionConfig(), targetOptions)
+	if err != nil {
+		return "", err
+	}
+
+	bucket := fmt.Sprintf("%v", remoteState.Config["bucket"])
+	key := fmt.Sprintf("%v", remoteState.Config["key"])
+
+	head, err := s3Client.HeadObject(&s3.HeadObjectInput{Bucket: aws.String(bucket), Key: aws.String(key)})
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	return fmt.Sprintf("s3://%s/%s?versionId=%s&etag=%s", bucket, key, aws.StringValue(head.VersionId), aws.StringValue(head.ETag)), nil
+}
+
+// dependencyOutputCacheTTL returns the maximum age of the cached outputs of the dependencies, or 0 if they don't expire.
+func dependencyOutputCacheTTL(terragruntOptions *options.TerragruntOptions) (time.Duration, error) {
+	if terragruntOptions.DependencyOutputCacheTTL == "" {
+		return 0, nil
+	}
+	ttl, err := time.ParseDuration(terragruntOptions.DependencyOutputCacheTTL)
+	if err != nil || ttl <= 0 {
+		return 0, errors.WithStackTrace(InvalidDependencyOutputCacheTTL(terragruntOptions.DependencyOutputCacheTTL))
+	}
+	return ttl, nil
+}
+
+// InvalidateDependencyOutputCache drops the outputs of the given config from the cache in memory and, unless they are
+// cached by the version of its state, from the cache in the DependencyOutputCacheDir, so that they are fetched again by
+// the modules that depend on it after its state was changed, e.g. by applying it earlier in the same run-all.
+func InvalidateDependencyOutputCache(terragruntOptions *options.TerragruntOptions, configPath string) {
+	configPath = util.CleanPath(configPath)
+
+	rawActualLock, _ := outputLocks.LoadOrStore(configPath, &sync.Mutex{})
+	actualLock := rawActualLock.(*sync.Mutex)
+	defer actualLock.Unlock()
+	actualLock.Lock()
+
+	jsonOutputCache.Delete(configPath)
+
+	if terragruntOptions.DependencyOutputCacheDir == "" {
+		return
+	}
+	cachePath := dependencyOutputCachePath(terragruntOptions.DependencyOutputCacheDir, configPath, "")
+	if err := os.Remove(cachePath); err != nil && !os.IsNotExist(err) {
+		terragruntOptions.Logger.Warnf("Failed to remove the cached outputs of %s from %s: %v", configPath, cachePath, err)
+	}
+}
+
+// dependencyOutputCachePath returns the path of the cache file of the outputs of the given target config at the given
+// state version, which is empty when the outputs are only cached for the TTL.
+func dependencyOutputCachePath(cacheDir string, targetConfig string, stateVersion string) string {
+	hash := sha256.Sum256([]byte(targetConfig + "\n" + stateVersion))
+	return filepath.Join(cacheDir, hex.EncodeToString(hash[:])+".json")
+}
+
+// readDependencyOutputCache returns the outputs in the given cache file, and false if there is no such file or it is
+// older than the given TTL.
+func readDependencyOutputCache(cachePath string, ttl time.Duration) ([]byte, bool) {
+	info, err := os.Stat(cachePath)
+	if err != nil || (ttl > 0 && time.Since(info.ModTime()) > ttl) {
+		return nil, false
+	}
+	jsonBytes, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, false
+	}
+	return jsonBytes, true
+}
+
+// writeDependencyOutputCache writes the given outputs to the cache file, which is only readable by the current user as
+// the outputs may be sensitive. The file is renamed into place, so that the other processes never read a partially
+// written file.
+func writeDependencyOutputCache(cachePath string, jsonBytes []byte) error {
+	cacheDir := filepath.Dir(cachePath)
+	if err := os.MkdirAll(cacheDir, 0700); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	tempFile, err := os.CreateTemp(cacheDir, filepath.Base(cachePath)+".*.tmp")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.Remove(tempFile.Name())
+
+	if _, err := tempFile.Write(jsonBytes); err != nil {
+		tempFile.Close()
+		return errors.WithStackTrace(err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if err := os.Rename(tempFile.Name(), cachePath); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Custom error types
+
+type InvalidDependencyOutputCacheTTL string
+
+func (ttl InvalidDependencyOutputCacheTTL) Error() string {
+	return fmt.Sprintf("Invalid dependency output cache TTL %q: expected a positive duration, such as 15m or 1h", string(ttl))
+}
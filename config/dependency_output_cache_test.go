@@ -0,0 +1,150 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+func TestDependencyOutputCachePath(t *testing.T) {
+	t.Parallel()
+
+	path := dependencyOutputCachePath("/cache", "/live/vpc/terragrunt.hcl", "s3://bucket/vpc?versionId=1&etag=a")
+
+	assert.Equal(t, "/cache", filepath.Dir(path))
+	assert.Equal(t, path, dependencyOutputCachePath("/cache", "/live/vpc/terragrunt.hcl", "s3://bucket/vpc?versionId=1&etag=a"))
+	assert.NotEqual(t, path, dependencyOutputCachePath("/cache", "/live/vpc/terragrunt.hcl", "s3://bucket/vpc?versionId=2&etag=b"))
+	assert.NotEqual(t, path, dependencyOutputCachePath("/cache", "/live/mysql/terragrunt.hcl", "s3://bucket/vpc?versionId=1&etag=a"))
+}
+
+func TestWriteDependencyOutputCache(t *testing.T) {
+	t.Parallel()
+
+	cachePath := filepath.Join(t.TempDir(), "outputs", "vpc.json")
+	require.NoError(t, writeDependencyOutputCache(cachePath, []byte(`{"vpc_id": {"value": "vpc-1"}}`)))
+
+	contents, err := os.ReadFile(cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, `{"vpc_id": {"value": "vpc-1"}}`, string(contents))
+
+	info, err := os.Stat(cachePath)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0600), info.Mode().Perm())
+
+	entries, err := os.ReadDir(filepath.Dir(cachePath))
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestGetOutputJsonWithDiskCacheUnsupportedBackend(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	opts.DependencyOutputCacheDir = t.TempDir()
+
+	fetchCount := 0
+	fetchOutputs := func() ([]byte, error) {
+		fetchCount++
+		return []byte(`{}`), nil
+	}
+
+	remoteState := &remote.RemoteState{Backend: "gcs", Config: map[string]interface{}{"bucket": "my-state", "prefix": "vpc"}}
+	for i := 0; i < 2; i++ {
+		_, err := getOutputJsonWithDiskCache(opts, "/live/vpc/terragrunt.hcl", remoteState, options.IAMRoleOptions{}, fetchOutputs)
+		require.NoError(t, err)
+	}
+
+	// The version of the state of the gcs backend can't be determined, so the outputs are fetched every time.
+	assert.Equal(t, 2, fetchCount)
+	entries, err := os.ReadDir(opts.DependencyOutputCacheDir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestGetOutputJsonWithDiskCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	opts.DependencyOutputCacheDir = t.TempDir()
+	opts.DependencyOutputCacheTTL = "1h"
+
+	fetchCount := 0
+	fetchOutputs := func() ([]byte, error) {
+		fetchCount++
+		return []byte(`{}`), nil
+	}
+
+	targetConfig := filepath.Join(t.TempDir(), "vpc", "terragrunt.hcl")
+	remoteState := &remote.RemoteState{Backend: "gcs", Config: map[string]interface{}{"bucket": "my-state", "prefix": "vpc"}}
+	for i := 0; i < 2; i++ {
+		_, err := getOutputJsonWithDiskCache(opts, targetConfig, remoteState, options.IAMRoleOptions{}, fetchOutputs)
+		require.NoError(t, err)
+	}
+
+	// With a TTL, the outputs are cached even though the version of the state of the gcs backend can't be determined.
+	assert.Equal(t, 1, fetchCount)
+
+	// The outputs older than the TTL are fetched again.
+	cachePath := dependencyOutputCachePath(opts.DependencyOutputCacheDir, targetConfig, "")
+	expired := time.Now().Add(-2 * time.Hour)
+	require.NoError(t, os.Chtimes(cachePath, expired, expired))
+	_, err = getOutputJsonWithDiskCache(opts, targetConfig, remoteState, options.IAMRoleOptions{}, fetchOutputs)
+	require.NoError(t, err)
+	assert.Equal(t, 2, fetchCount)
+
+	// Applying the target config drops its cached outputs.
+	InvalidateDependencyOutputCache(opts, targetConfig)
+	assert.False(t, util.FileExists(cachePath))
+	_, err = getOutputJsonWithDiskCache(opts, targetConfig, remoteState, options.IAMRoleOptions{}, fetchOutputs)
+	require.NoError(t, err)
+	assert.Equal(t, 3, fetchCount)
+}
+
+func TestDependencyOutputCacheTTL(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	ttl, err := dependencyOutputCacheTTL(opts)
+	require.NoError(t, err)
+	assert.Zero(t, ttl)
+
+	opts.DependencyOutputCacheTTL = "15m"
+	ttl, err = dependencyOutputCacheTTL(opts)
+	require.NoError(t, err)
+	assert.Equal(t, 15*time.Minute, ttl)
+
+	for _, invalid := range []string{"15", "-1m", "0s"} {
+		opts.DependencyOutputCacheTTL = invalid
+		_, err = dependencyOutputCacheTTL(opts)
+		require.Error(t, err)
+		_, ok := errors.Unwrap(err).(InvalidDependencyOutputCacheTTL)
+		assert.True(t, ok)
+	}
+}
+
+func TestInvalidateDependencyOutputCacheInMemory(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	targetConfig := filepath.Join(t.TempDir(), "vpc", "terragrunt.hcl")
+	jsonOutputCache.Store(targetConfig, cachedOutputJson{jsonBytes: []byte(`{}`), fetchedAt: time.Now()})
+
+	InvalidateDependencyOutputCache(opts, targetConfig)
+	_, found := jsonOutputCache.Load(targetConfig)
+	assert.False(t, found)
+}
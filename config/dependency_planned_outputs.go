@@ -0,0 +1,134 @@
+package config
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/gocty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// plannedOutputsCache maps the path of the config of a module to the outputs that were planned for it in this run.
+var plannedOutputsCache = sync.Map{}
+
+// plannedOutputs are the outputs planned for a module: the known ones, in the format of `terraform output -json`, and
+// the names of the ones that are only known after apply.
+type plannedOutputs struct {
+	known   []byte
+	unknown map[string]bool
+}
+
+// StorePlannedOutputs records the outputs planned for the module of the given config, read from its plan in the format
+// of `terraform show -json`, so that the modules that depend on it and are planned afterwards in the same run read
+// them instead of the outputs in its state.
+func StorePlannedOutputs(configPath string, planJSON []byte) error {
+	type OutputMeta struct {
+		Sensitive bool            `json:"sensitive"`
+		Type      json.RawMessage `json:"type,omitempty"`
+		Value     json.RawMessage `json:"value,omitempty"`
+	}
+	var plan struct {
+		PlannedValues struct {
+			Outputs map[string]OutputMeta `json:"outputs"`
+		} `json:"planned_values"`
+	}
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return errors.WithStackTrace(TerragruntOutputParsingError{Path: configPath, Err: err})
+	}
+
+	known := map[string]OutputMeta{}
+	unknown := map[string]bool{}
+	for name, output := range plan.PlannedValues.Outputs {
+		// The value of the outputs that are only known after apply is left out of the plan.
+		if len(output.Value) == 0 {
+			unknown[name] = true
+			continue
+		}
+		// Older versions of terraform leave the type of the outputs out of the plan, so it is implied from the value.
+		if len(output.Type) == 0 {
+			outputType, err := ctyjson.ImpliedType(output.Value)
+			if err != nil {
+				return errors.WithStackTrace(TerragruntOutputParsingError{Path: configPath, Err: err})
+			}
+			if output.Type, err = ctyjson.MarshalType(outputType); err != nil {
+				return errors.WithStackTrace(TerragruntOutputParsingError{Path: configPath, Err: err})
+			}
+		}
+		known[name] = output
+	}
+
+	knownJSON, err := json.Marshal(known)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	plannedOutputsCache.Store(util.CleanPath(configPath), plannedOutputs{known: knownJSON, unknown: unknown})
+	return nil
+}
+
+// shouldGetPlannedOutputs returns true if the outputs of the dependency are read from the values planned for it in this
+// run, when there are any.
+func (dependencyConfig Dependency) shouldGetPlannedOutputs(terragruntOptions *options.TerragruntOptions) bool {
+	return terragruntOptions.DependencyPlannedOutputs &&
+		terragruntOptions.OriginalTerraformCommand == "plan" &&
+		dependencyConfig.shouldGetOutputs() &&
+		!isRenderCommand(terragruntOptions)
+}
+
+// getTerragruntPlannedOutput returns the outputs planned for the target config of the dependency in this run, and false
+// if it was not planned in this run. The outputs that are only known after apply are read from the state of the target
+// config or the mock outputs, as if the outputs were not read from the planned values.
+func getTerragruntPlannedOutput(dependencyConfig Dependency, terragruntOptions *options.TerragruntOptions) (*cty.Value, bool, error) {
+	targetConfig := getCleanedTargetConfigPath(dependencyConfig.ConfigPath, terragruntOptions.TerragruntConfigPath)
+	rawPlanned, found := plannedOutputsCache.Load(targetConfig)
+	if !found {
+		return nil, false, nil
+	}
+	planned := rawPlanned.(plannedOutputs)
+
+	terragruntOptions.Logger.Debugf("Reading the outputs of %s referenced in %s as %s from the values planned for it", targetConfig, terragruntOptions.TerragruntConfigPath, dependencyConfig.Name)
+
+	outputMap, err := filteredTerraformOutputJsonToCtyValueMap(targetConfig, planned.known, dependencyConfig.referencedOutputs)
+	if err != nil {
+		return nil, true, err
+	}
+	if err := dependencyConfig.checkExpectedOutputs(targetConfig, outputMap, planned.unknown); err != nil {
+		return nil, true, err
+	}
+
+	unknown := []string{}
+	for name := range planned.unknown {
+		if dependencyConfig.referencedOutputs == nil || dependencyConfig.referencedOutputs[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		terragruntOptions.Logger.Debugf("The outputs %v of %s are only known after apply, reading them from its state or mock outputs", unknown, targetConfig)
+
+		appliedOutput, err := getTerragruntAppliedOutputElseConfiguredDefault(dependencyConfig, terragruntOptions)
+		if err != nil {
+			return nil, true, err
+		}
+		if appliedOutput != nil && appliedOutput.IsWhollyKnown() && !appliedOutput.IsNull() &&
+			(appliedOutput.Type().IsObjectType() || appliedOutput.Type().IsMapType()) {
+			appliedOutputMap := appliedOutput.AsValueMap()
+			for _, name := range unknown {
+				if value, ok := appliedOutputMap[name]; ok {
+					outputMap[name] = value
+				}
+			}
+		}
+	}
+
+	convertedOutput, err := gocty.ToCtyValue(outputMap, generateTypeFromValuesMap(outputMap))
+	if err != nil {
+		return nil, true, errors.WithStackTrace(TerragruntOutputEncodingError{Path: targetConfig, Err: err})
+	}
+	return &convertedOutput, true, nil
+}
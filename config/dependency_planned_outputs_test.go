@@ -0,0 +1,74 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const testPlanJSON = `{
+  "format_version": "1.2",
+  "planned_values": {
+    "outputs": {
+      "vpc_id": {"sensitive": false, "type": "string", "value": "vpc-planned"},
+      "cidr_blocks": {"sensitive": false, "value": ["10.0.0.0/16"]},
+      "subnet_id": {"sensitive": false}
+    }
+  }
+}`
+
+func TestPlannedOutputsOverrideState(t *testing.T) {
+	t.Parallel()
+
+	vpcConfig := filepath.Join(t.TempDir(), "vpc", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(vpcConfig), os.ModePerm))
+	require.NoError(t, os.WriteFile(vpcConfig, []byte(``), 0644))
+	require.NoError(t, StorePlannedOutputs(vpcConfig, []byte(testPlanJSON)))
+
+	opts := mockOptionsForTestWithConfigPath(t, filepath.Join(filepath.Dir(filepath.Dir(vpcConfig)), "app", DefaultTerragruntConfigPath))
+	opts.OriginalTerraformCommand = "plan"
+	opts.DependencyPlannedOutputs = true
+
+	dependency := Dependency{Name: "vpc", ConfigPath: "../vpc", referencedOutputs: map[string]bool{"vpc_id": true, "cidr_blocks": true}}
+
+	value, err := getTerragruntOutputIfAppliedElseConfiguredDefault(dependency, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-planned", value.GetAttr("vpc_id").AsString())
+	assert.Equal(t, "10.0.0.0/16", value.GetAttr("cidr_blocks").Index(cty.NumberIntVal(0)).AsString())
+	assert.False(t, value.Type().HasAttribute("subnet_id"))
+}
+
+func TestPlannedOutputsKnownAfterApplyUseMockOutputs(t *testing.T) {
+	t.Parallel()
+
+	vpcConfig := filepath.Join(t.TempDir(), "vpc", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(vpcConfig), os.ModePerm))
+	require.NoError(t, os.WriteFile(vpcConfig, []byte(``), 0644))
+	require.NoError(t, StorePlannedOutputs(vpcConfig, []byte(testPlanJSON)))
+
+	// The vpc was never applied, so it has no outputs in its state.
+	jsonOutputCache.Store(vpcConfig, cachedOutputJson{jsonBytes: []byte("{}"), fetchedAt: time.Now()})
+
+	opts := mockOptionsForTestWithConfigPath(t, filepath.Join(filepath.Dir(filepath.Dir(vpcConfig)), "app", DefaultTerragruntConfigPath))
+	opts.OriginalTerraformCommand = "plan"
+	opts.DependencyPlannedOutputs = true
+
+	mocks := cty.ObjectVal(map[string]cty.Value{"vpc_id": cty.StringVal("mock-vpc"), "subnet_id": cty.StringVal("mock-subnet")})
+	dependency := Dependency{Name: "vpc", ConfigPath: "../vpc", MockOutputs: &mocks}
+
+	value, err := getTerragruntOutputIfAppliedElseConfiguredDefault(dependency, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "vpc-planned", value.GetAttr("vpc_id").AsString())
+	assert.Equal(t, "mock-subnet", value.GetAttr("subnet_id").AsString())
+
+	// Without the flag, the outputs are not read from the planned values.
+	opts.DependencyPlannedOutputs = false
+	value, err = getTerragruntOutputIfAppliedElseConfiguredDefault(dependency, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "mock-vpc", value.GetAttr("vpc_id").AsString())
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"path/filepath"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// dependencyOutputReferences are the outputs of the dependency blocks referenced in a config, by dependency block
+// name. A dependency maps to nil if all of its outputs may be used, e.g. when `dependency.vpc.outputs` is passed to a
+// function as a whole.
+type dependencyOutputReferences map[string]map[string]bool
+
+// findDependencyOutputReferences returns the outputs of the dependency blocks referenced in the given config file and
+// the files it includes, so that only these outputs are read. It returns false if the referenced outputs can't be
+// determined, in which case all the outputs are read:
+//   - When the config is not the one being run, e.g. when it is read with read_terragrunt_config or as an exposed
+//     include, as any of its dependency outputs may be accessed by the config that reads it.
+//   - When the config is rendered, as the outputs of the dependencies are part of the rendered config.
+//   - When one of the files is not in the native HCL syntax, or `dependency` is referenced as a whole.
+func findDependencyOutputReferences(
+	file *hcl.File,
+	filename string,
+	terragruntOptions *options.TerragruntOptions,
+	trackInclude *TrackInclude,
+) (dependencyOutputReferences, bool) {
+	if filename != terragruntOptions.TerragruntConfigPath || isRenderCommand(terragruntOptions) {
+		return nil, false
+	}
+
+	files := []*hcl.File{file}
+	if trackInclude != nil {
+		parser := hclparse.NewParser()
+		for _, includeConfig := range trackInclude.CurrentList {
+			includePath := includeConfig.Path
+			if !filepath.IsAbs(includePath) {
+				includePath = util.JoinPath(filepath.Dir(filename), includePath)
+			}
+			includedFile, diags := parser.ParseHCLFile(includePath)
+			if diags.HasErrors() {
+				return nil, false
+			}
+			files = append(files, includedFile)
+		}
+	}
+
+	references := dependencyOutputReferences{}
+	for _, file := range files {
+		body, isNativeSyntax := file.Body.(*hclsyntax.Body)
+		if !isNativeSyntax {
+			return nil, false
+		}
+		for _, traversal := range bodyTraversals(body) {
+			if !references.add(traversal) {
+				return nil, false
+			}
+		}
+	}
+
+	return references, true
+}
+
+// add records the dependency output referenced by the given traversal, if any. It returns false if the traversal
+// references all the dependencies.
+func (references dependencyOutputReferences) add(traversal hcl.Traversal) bool {
+	if traversal.RootName() != MetadataDependency {
+		return true
+	}
+
+	dependencyName, ok := traversalStepName(traversal, 1)
+	if !ok {
+		return false
+	}
+
+	// Only the outputs and the derived values of the dependencies are exposed, and the outputs referenced by the derived
+	// values are added when the dependencies are decoded.
+	if attributeName, ok := traversalStepName(traversal, 2); ok && attributeName != "outputs" {
+		return true
+	}
+
+	outputName, ok := traversalStepName(traversal, 3)
+	if !ok {
+		references[dependencyName] = nil
+		return true
+	}
+
+	outputs, isReferenced := references[dependencyName]
+	if isReferenced && outputs == nil {
+		// All the outputs of the dependency are already referenced.
+		return true
+	}
+	if !isReferenced {
+		outputs = map[string]bool{}
+		references[dependencyName] = outputs
+	}
+	outputs[outputName] = true
+
+	return true
+}
+
+// outputs returns the referenced outputs of the given dependency, or nil if all of its outputs may be used.
+func (references dependencyOutputReferences) outputs(dependencyName string) map[string]bool {
+	outputs, isReferenced := references[dependencyName]
+	if !isReferenced {
+		return map[string]bool{}
+	}
+	return outputs
+}
+
+// traversalStepName returns the name of the attribute, or the static string key, of the traversal step at the given
+// index.
+func traversalStepName(traversal hcl.Traversal, index int) (string, bool) {
+	if len(traversal) <= index {
+		return "", false
+	}
+
+	switch step := traversal[index].(type) {
+	case hcl.TraverseAttr:
+		return step.Name, true
+	case hcl.TraverseIndex:
+		if step.Key.Type() == cty.String && step.Key.IsKnown() && !step.Key.IsNull() {
+			return step.Key.AsString(), true
+		}
+	}
+
+	return "", false
+}
+
+// bodyTraversals returns the variables referenced in all the attributes of the given body and its nested blocks.
+func bodyTraversals(body *hclsyntax.Body) []hcl.Traversal {
+	traversals := []hcl.Traversal{}
+	for _, attribute := range body.Attributes {
+		traversals = append(traversals, attribute.Expr.Variables()...)
+	}
+	for _, block := range body.Blocks {
+		traversals = append(traversals, bodyTraversals(block.Body)...)
+	}
+	return traversals
+}
@@ -0,0 +1,141 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindDependencyOutputReferences(t *testing.T) {
+	t.Parallel()
+
+	config := `
+dependency "vpc" {
+  config_path = "../vpc"
+}
+
+dependency "sql" {
+  config_path = "../sql"
+}
+
+dependency "dns" {
+  config_path = "../dns"
+}
+
+inputs = {
+  vpc_id     = dependency.vpc.outputs.vpc_id
+  subnet_ids = dependency.vpc.outputs["subnet_ids"]
+  all_sql    = merge(dependency.sql.outputs, {})
+  zone       = "${dependency.vpc.outputs.zone.name}.example.com"
+}
+
+generate "provider" {
+  path     = "provider.tf"
+  contents = dependency.vpc.outputs.provider_config
+}
+`
+	opts := mockOptionsForTest(t)
+	file, err := parseHcl(hclparse.NewParser(), config, opts.TerragruntConfigPath)
+	require.NoError(t, err)
+
+	references, ok := findDependencyOutputReferences(file, opts.TerragruntConfigPath, opts, nil)
+	require.True(t, ok)
+
+	assert.Equal(t, map[string]bool{"vpc_id": true, "subnet_ids": true, "zone": true, "provider_config": true}, references.outputs("vpc"))
+	assert.Nil(t, references.outputs("sql"))
+	assert.Equal(t, map[string]bool{}, references.outputs("dns"))
+}
+
+func TestFindDependencyOutputReferencesReadsAllOutputs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		config     string
+		configPath string
+		command    string
+	}{
+		{
+			"dependency referenced as a whole",
+			`inputs = { deps = dependency }`,
+			"",
+			"",
+		},
+		{
+			"dependency referenced with a dynamic key",
+			`inputs = { vpc = dependency[local.name].outputs.vpc_id }`,
+			"",
+			"",
+		},
+		{
+			"config read by another config",
+			`inputs = { vpc_id = dependency.vpc.outputs.vpc_id }`,
+			"../other/" + DefaultTerragruntConfigPath,
+			"",
+		},
+		{
+			"rendered config",
+			`inputs = { vpc_id = dependency.vpc.outputs.vpc_id }`,
+			"",
+			"render-json",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := mockOptionsForTest(t)
+			if testCase.command != "" {
+				opts.TerraformCliArgs = []string{testCase.command}
+			}
+			filename := opts.TerragruntConfigPath
+			if testCase.configPath != "" {
+				filename = testCase.configPath
+			}
+
+			file, err := parseHcl(hclparse.NewParser(), testCase.config, filename)
+			require.NoError(t, err)
+
+			_, ok := findDependencyOutputReferences(file, filename, opts, nil)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestFindDependencyOutputReferencesInIncludedConfig(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	includePath := filepath.Join(dir, "root.hcl")
+	require.NoError(t, os.WriteFile(includePath, []byte(`inputs = { vpc_id = dependency.vpc.outputs.vpc_id }`), 0644))
+
+	opts := mockOptionsForTestWithConfigPath(t, filepath.Join(dir, "child", DefaultTerragruntConfigPath))
+	file, err := parseHcl(hclparse.NewParser(), `inputs = { zone = dependency.vpc.outputs.zone }`, opts.TerragruntConfigPath)
+	require.NoError(t, err)
+
+	trackInclude := &TrackInclude{CurrentList: []IncludeConfig{{Path: "../root.hcl"}}}
+	references, ok := findDependencyOutputReferences(file, opts.TerragruntConfigPath, opts, trackInclude)
+	require.True(t, ok)
+	assert.Equal(t, map[string]bool{"vpc_id": true, "zone": true}, references.outputs("vpc"))
+}
+
+func TestFilteredTerraformOutputJsonToCtyValueMap(t *testing.T) {
+	t.Parallel()
+
+	outputs := `{"vpc_id": {"sensitive": false, "type": "string", "value": "vpc-1"}, "routes": {"sensitive": false, "type": ["list", "string"], "value": ["a", "b"]}}`
+
+	converted, err := filteredTerraformOutputJsonToCtyValueMap(DefaultTerragruntConfigPath, []byte(outputs), map[string]bool{"vpc_id": true, "missing": true})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"vpc_id": true}, getKeys(converted))
+	assert.Equal(t, "vpc-1", converted["vpc_id"].AsString())
+
+	converted, err = filteredTerraformOutputJsonToCtyValueMap(DefaultTerragruntConfigPath, []byte(outputs), nil)
+	require.NoError(t, err)
+	assert.Len(t, converted, 2)
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-getter"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// GetDependencyConfigPath returns the path of the config of the given dependency block of the given config file. When
+// the dependency is in another repository, the repository is downloaded and the config_path is resolved in it.
+func GetDependencyConfigPath(dependency Dependency, filename string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	if !dependency.isRemote() {
+		return getCleanedTargetConfigPath(dependency.ConfigPath, filename), nil
+	}
+
+	repoDir, err := resolveDependencySource(dependency, filename, terragruntOptions)
+	if err != nil {
+		return "", err
+	}
+
+	configPath := filepath.Join(repoDir, filepath.FromSlash(dependency.ConfigPath))
+	targetConfig := util.CleanPath(GetDefaultConfigPath(configPath))
+	if !util.FileExists(targetConfig) {
+		return "", errors.WithStackTrace(DependencySourceFailed{Name: dependency.Name, Source: *dependency.Source, Err: fmt.Errorf("%s does not exist in the repository", dependency.ConfigPath)})
+	}
+	return targetConfig, nil
+}
+
+// resolveDependencySourcePaths sets the config_path of the given dependency blocks that are in another repository to
+// the path of their config in the downloaded repository, so that their outputs are read like the ones of any other
+// dependency. The disabled dependencies are not downloaded.
+func resolveDependencySourcePaths(dependencies []Dependency, filename string, terragruntOptions *options.TerragruntOptions) error {
+	for i := range dependencies {
+		if !dependencies[i].isRemote() || !dependencies[i].isEnabled() {
+			continue
+		}
+
+		targetConfig, err := GetDependencyConfigPath(dependencies[i], filename, terragruntOptions)
+		if err != nil {
+			return err
+		}
+		terragruntOptions.Logger.Debugf("Resolved dependency %s from %s to %s", dependencies[i].Name, *dependencies[i].Source, targetConfig)
+		dependencies[i].ConfigPath = targetConfig
+	}
+	return nil
+}
+
+// resolveDependencySource downloads the repository of the given dependency block into the cache folder, unless it is
+// already there, and returns the folder it was downloaded into. Like the sources of import blocks, the repositories
+// must be pinned to a tag or a commit, e.g. ?ref=v1.2.0, so that a repository that was downloaded once is not downloaded
+// again.
+func resolveDependencySource(dependency Dependency, filename string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	source := *dependency.Source
+
+	detected, err := getter.Detect(source, filepath.Dir(filename), getter.Detectors)
+	if err != nil {
+		return "", errors.WithStackTrace(DependencySourceFailed{Name: dependency.Name, Source: source, Err: err})
+	}
+	if strings.HasPrefix(detected, "file://") {
+		return util.CanonicalPath(source, filepath.Dir(filename))
+	}
+	if !isPinnedSource(detected) {
+		return "", errors.WithStackTrace(DependencySourceFailed{Name: dependency.Name, Source: source, Err: fmt.Errorf("remote sources must be pinned to a ref, e.g. ?ref=v1.0.0")})
+	}
+
+	repoDir, err := downloadPinnedSource(detected, filepath.Join(sourceCacheDir(), "dependencies"), terragruntOptions)
+	if err != nil {
+		return "", errors.WithStackTrace(DependencySourceFailed{Name: dependency.Name, Source: source, Err: err})
+	}
+	return repoDir, nil
+}
+
+// Custom error types
+
+type DependencySourceFailed struct {
+	Name   string
+	Source string
+	Err    error
+}
+
+func (err DependencySourceFailed) Error() string {
+	return fmt.Sprintf("Failed to resolve dependency %s from the repository %s: %v", err.Name, err.Source, err.Err)
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetDependencyConfigPathFromLocalRepository(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	vpcConfig := filepath.Join(rootDir, "platform", "prod", "vpc", DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(vpcConfig), os.ModePerm))
+	require.NoError(t, os.WriteFile(vpcConfig, []byte(``), 0644))
+
+	appConfig := filepath.Join(rootDir, "app", "prod", DefaultTerragruntConfigPath)
+	opts := mockOptionsForTestWithConfigPath(t, appConfig)
+
+	source := "../../platform"
+	dependency := Dependency{Name: "vpc", ConfigPath: "prod/vpc", Source: &source}
+
+	targetConfig, err := GetDependencyConfigPath(dependency, appConfig, opts)
+	require.NoError(t, err)
+	assert.Equal(t, vpcConfig, targetConfig)
+
+	dependency.ConfigPath = "prod/mysql"
+	_, err = GetDependencyConfigPath(dependency, appConfig, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "prod/mysql does not exist in the repository")
+}
+
+func TestGetDependencyConfigPathRequiresPinnedSource(t *testing.T) {
+	t.Parallel()
+
+	opts := mockOptionsForTest(t)
+	source := "git::https://github.com/acme/platform-live.git"
+	dependency := Dependency{Name: "vpc", ConfigPath: "prod/vpc", Source: &source}
+
+	_, err := GetDependencyConfigPath(dependency, opts.TerragruntConfigPath, opts)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "must be pinned to a ref")
+}
+
+func TestRemoteDependencyIsNotAModuleDependency(t *testing.T) {
+	t.Parallel()
+
+	source := "git::https://github.com/acme/platform-live.git?ref=v1.0.0"
+	dependencies := []Dependency{
+		{Name: "vpc", ConfigPath: "prod/vpc", Source: &source},
+		{Name: "mysql", ConfigPath: "../mysql"},
+	}
+
+	moduleDependencies := dependencyBlocksToModuleDependencies(dependencies)
+	assert.Equal(t, []string{"../mysql"}, moduleDependencies.Paths)
+}
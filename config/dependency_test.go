@@ -3,10 +3,12 @@ package config
 import (
 	"testing"
 
+	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/hashicorp/hcl/v2"
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
 )
 
@@ -132,3 +134,186 @@ dependency "vpc" {
 	require.NoError(t, decodeHcl(file, filename, &decoded, &hcl.EvalContext{}))
 	assert.Equal(t, len(decoded.Dependencies), 2)
 }
+
+func TestDisabledDependencyUsesMockOutputs(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  env = "dev"
+}
+
+dependency "vpc" {
+  config_path = "../vpc"
+  enabled     = local.env != "dev"
+
+  mock_outputs = {
+    vpc_id = "mock-vpc-id"
+  }
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	// The outputs of the disabled dependency are not read.
+	require.Len(t, terragruntConfig.TerragruntDependencies, 1)
+	assert.False(t, terragruntConfig.TerragruntDependencies[0].isEnabled())
+	assert.Equal(t, "mock-vpc-id", terragruntConfig.Inputs["vpc_id"])
+
+	// The dependency is not part of the graph, which is built from the partially parsed dependency blocks.
+	partialConfig, err := PartialParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{DependencyBlock})
+	require.NoError(t, err)
+	require.NotNil(t, partialConfig.Dependencies)
+	assert.Empty(t, partialConfig.Dependencies.Paths)
+}
+
+func TestDisabledDependencyFromIncludedConfig(t *testing.T) {
+	t.Parallel()
+
+	configPath := "../test/fixture-disabled-dependency/child/" + DefaultTerragruntConfigPath
+	opts := mockOptionsForTestWithConfigPath(t, configPath)
+
+	terragruntConfig, err := ParseConfigFile(configPath, opts, nil, nil)
+	require.NoError(t, err)
+	// Both dependencies of the root config are disabled, the vpc one by the child config.
+	require.Len(t, terragruntConfig.TerragruntDependencies, 2)
+	for _, dependency := range terragruntConfig.TerragruntDependencies {
+		assert.False(t, dependency.isEnabled(), dependency.Name)
+	}
+	assert.Equal(t, "mock-vpc-id", terragruntConfig.Inputs["vpc_id"])
+
+	// The dependency disabled by the child config is also excluded from the graph when partially parsing the config.
+	partialConfig, err := PartialParseConfigFile(configPath, opts, nil, []PartialDecodeSectionType{DependencyBlock})
+	require.NoError(t, err)
+	require.NotNil(t, partialConfig.Dependencies)
+	assert.Empty(t, partialConfig.Dependencies.Paths)
+}
+
+func TestDependencyBlocksToCtyValueWithBoundedParallelism(t *testing.T) {
+	t.Parallel()
+
+	opts := mockOptionsForTest(t)
+	opts.DependencyFetchParallelism = 2
+
+	disabled := false
+	dependencies := []Dependency{}
+	for _, name := range []string{"vpc", "mysql", "redis", "app", "dns"} {
+		mockOutputs := cty.ObjectVal(map[string]cty.Value{"id": cty.StringVal("mock-" + name)})
+		dependencies = append(dependencies, Dependency{Name: name, ConfigPath: "../" + name, Enabled: &disabled, MockOutputs: &mockOutputs})
+	}
+
+	value, err := dependencyBlocksToCtyValue(dependencies, opts)
+	require.NoError(t, err)
+
+	for _, dependency := range dependencies {
+		id := value.GetAttr(dependency.Name).GetAttr("outputs").GetAttr("id")
+		assert.Equal(t, "mock-"+dependency.Name, id.AsString())
+	}
+}
+
+func TestStateOutputsJson(t *testing.T) {
+	t.Parallel()
+
+	state := `{"version": 4, "serial": 3, "outputs": {"vpc_id": {"value": "vpc-1", "type": "string"}}, "resources": []}`
+
+	outputs, err := stateOutputsJson([]byte(state))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"vpc_id": {"value": "vpc-1", "type": "string"}}`, string(outputs))
+}
+
+func TestStrictMockOutputsFailsApply(t *testing.T) {
+	t.Parallel()
+
+	skipOutputs := true
+	vpcMocks := cty.ObjectVal(map[string]cty.Value{"vpc_id": cty.StringVal("mock"), "subnet_ids": cty.ListValEmpty(cty.String)})
+	dnsMocks := cty.ObjectVal(map[string]cty.Value{"zone_id": cty.StringVal("mock")})
+	dependencies := []Dependency{
+		{Name: "vpc", ConfigPath: "../vpc", SkipOutputs: &skipOutputs, MockOutputs: &vpcMocks, strictMockOutputs: true},
+		{Name: "dns", ConfigPath: "../dns", SkipOutputs: &skipOutputs, MockOutputs: &dnsMocks, strictMockOutputs: true},
+	}
+
+	opts := mockOptionsForTest(t)
+	opts.OriginalTerraformCommand = "apply"
+
+	_, err := dependencyBlocksToCtyValue(dependencies, opts)
+	require.Error(t, err)
+
+	forbidden, ok := errors.Unwrap(err).(DependencyMockOutputsForbidden)
+	require.True(t, ok)
+	require.Len(t, forbidden.Dependencies, 2)
+	assert.Equal(t, "dns", forbidden.Dependencies[0].Name)
+	assert.Equal(t, []string{"zone_id"}, forbidden.Dependencies[0].Keys)
+	assert.Equal(t, "vpc", forbidden.Dependencies[1].Name)
+	assert.ElementsMatch(t, []string{"vpc_id", "subnet_ids"}, forbidden.Dependencies[1].Keys)
+
+	opts.OriginalTerraformCommand = "plan"
+	value, err := dependencyBlocksToCtyValue(dependencies, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "mock", value.GetAttr("vpc").GetAttr("outputs").GetAttr("vpc_id").AsString())
+}
+
+func TestForbiddenMockOutputsOnlyListsMissingOutputs(t *testing.T) {
+	t.Parallel()
+
+	mocks := cty.ObjectVal(map[string]cty.Value{"vpc_id": cty.StringVal("mock"), "subnet_ids": cty.ListValEmpty(cty.String)})
+	outputs := cty.ObjectVal(map[string]cty.Value{"vpc_id": cty.StringVal("vpc-1")})
+	dependency := Dependency{Name: "vpc", ConfigPath: "../vpc"}
+
+	err := dependency.forbiddenMockOutputs(&mocks, &outputs)
+	require.Error(t, err)
+	assert.Equal(t, []string{"subnet_ids"}, err.(MockOutputsForbidden).Keys)
+
+	outputs = cty.ObjectVal(map[string]cty.Value{"vpc_id": cty.StringVal("vpc-1"), "subnet_ids": cty.ListValEmpty(cty.String)})
+	assert.NoError(t, dependency.forbiddenMockOutputs(&mocks, &outputs))
+}
+
+func TestOrderingOnlyDependency(t *testing.T) {
+	t.Parallel()
+
+	config := `
+dependency "app" {
+  config_path   = "../app"
+  ordering_only = true
+}
+
+inputs = {
+  zone = "example.com"
+}
+`
+
+	// The app was never applied, and doesn't even exist, but its outputs are never read.
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	assert.Equal(t, "example.com", terragruntConfig.Inputs["zone"])
+
+	// The dependency is still part of the graph, so that it orders the run.
+	partialConfig, err := PartialParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{DependencyBlock})
+	require.NoError(t, err)
+	require.NotNil(t, partialConfig.Dependencies)
+	assert.Equal(t, []string{"../app"}, partialConfig.Dependencies.Paths)
+}
+
+func TestOrderingOnlyDependencyWithMockOutputsIsError(t *testing.T) {
+	t.Parallel()
+
+	config := `
+dependency "app" {
+  config_path   = "../app"
+  ordering_only = true
+
+  mock_outputs = {
+    url = "https://mock"
+  }
+}
+`
+
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	_, ok := errors.Unwrap(err).(OrderingOnlyDependencyWithOutputs)
+	assert.True(t, ok, "unexpected error: %v", err)
+}
@@ -0,0 +1,211 @@
+package config
+
+import (
+	goerrors "errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/hashicorp/hcl/v2"
+)
+
+// The stable codes of the config errors, which are shown along with the errors so that they can be looked up in the
+// docs and matched by scripts. The codes must never be reused for other errors.
+const (
+	ErrorCodeHclDiagnostics                = "TG1001"
+	ErrorCodePanicWhileParsingConfig       = "TG1002"
+	ErrorCodeInvalidYamlConfig             = "TG1003"
+	ErrorCodeCouldNotResolveConfig         = "TG1004"
+	ErrorCodeIncludedConfigMissingPath     = "TG1101"
+	ErrorCodeTooManyLevelsOfInheritance    = "TG1102"
+	ErrorCodeMultipleBareIncludeBlocks     = "TG1103"
+	ErrorCodeDependencyCycle               = "TG1201"
+	ErrorCodeDependencyDirNotFound         = "TG1202"
+	ErrorCodeDuplicatedGenerateBlocks      = "TG1301"
+	ErrorCodeInvalidGenerateTemplateResult = "TG1302"
+	ErrorCodeDuplicatedRetryBlocks         = "TG1401"
+	ErrorCodeInvalidRetryConfig            = "TG1402"
+	ErrorCodeValidationFailed              = "TG1501"
+	ErrorCodeInvalidArg                    = "TG1901"
+)
+
+// The number of lines shown before the offending lines in the code frames.
+const codeFrameContextLines = 1
+
+// errorCodes maps the config errors to their codes. The first match wins, so the errors that wrap other errors must
+// come before the errors they can wrap.
+var errorCodes = []struct {
+	code    string
+	matches func(err error) bool
+}{
+	{ErrorCodeValidationFailed, isErrorOfType[ValidationFailed]},
+	{ErrorCodeHclDiagnostics, isErrorOfType[hcl.Diagnostics]},
+	{ErrorCodePanicWhileParsingConfig, isErrorOfType[PanicWhileParsingConfig]},
+	{ErrorCodeInvalidYamlConfig, isErrorOfType[InvalidYamlConfig]},
+	{ErrorCodeCouldNotResolveConfig, isErrorOfType[CouldNotResolveTerragruntConfigInFile]},
+	{ErrorCodeIncludedConfigMissingPath, isErrorOfType[IncludedConfigMissingPath]},
+	{ErrorCodeTooManyLevelsOfInheritance, isErrorOfType[TooManyLevelsOfInheritance]},
+	{ErrorCodeMultipleBareIncludeBlocks, isErrorOfType[MultipleBareIncludeBlocksErr]},
+	{ErrorCodeDependencyCycle, isErrorOfType[DependencyCycle]},
+	{ErrorCodeDependencyDirNotFound, isErrorOfType[DependencyDirNotFound]},
+	{ErrorCodeDuplicatedGenerateBlocks, isErrorOfType[DuplicatedGenerateBlocks]},
+	{ErrorCodeInvalidGenerateTemplateResult, isErrorOfType[InvalidGenerateTemplateResult]},
+	{ErrorCodeDuplicatedRetryBlocks, isErrorOfType[DuplicatedRetryBlocks]},
+	{ErrorCodeInvalidRetryConfig, isErrorOfType[InvalidRetryConfig]},
+	{ErrorCodeInvalidArg, isErrorOfType[InvalidArgError]},
+}
+
+func isErrorOfType[T error](err error) bool {
+	var target T
+	return goerrors.As(err, &target)
+}
+
+// ErrorCode returns the stable code of the given config error, or an empty string if it is not a config error.
+func ErrorCode(err error) string {
+	for _, errorCode := range errorCodes {
+		if errorCode.matches(err) {
+			return errorCode.code
+		}
+	}
+	return ""
+}
+
+// FormatError formats the given error to be shown to the user. The config errors are shown with their code, a frame of
+// the source code they are about with a caret under the offending part, and the chain of the configs that included the
+// config they are in:
+//
+//	[TG1001] Error: Unsupported attribute
+//	  --> root.hcl:6:17
+//	   |
+//	 5 | inputs = {
+//	 6 |   name = local.nme
+//	   |                ^^^
+//	   = This object does not have an attribute named "nme".
+//	   = included by child/terragrunt.hcl
+//
+// The other errors are shown as is.
+func FormatError(err error) string {
+	var multiErr *multierror.Error
+	if goerrors.As(err, &multiErr) {
+		formatted := []string{}
+		for _, wrappedErr := range multiErr.Errors {
+			formatted = append(formatted, FormatError(wrappedErr))
+		}
+		return fmt.Sprintf("%d errors occurred:\n\n%s", len(formatted), strings.Join(formatted, "\n\n"))
+	}
+
+	code := ErrorCode(err)
+	if code == "" {
+		return err.Error()
+	}
+
+	includeChain := includeChainOf(err)
+
+	var diags hcl.Diagnostics
+	if code != ErrorCodeHclDiagnostics || !goerrors.As(err, &diags) {
+		lines := []string{fmt.Sprintf("[%s] %s", code, err.Error())}
+		for _, includedBy := range includeChain {
+			lines = append(lines, fmt.Sprintf("   = included by %s", includedBy))
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	formatted := []string{}
+	for _, diag := range diags {
+		formatted = append(formatted, formatDiagnostic(code, diag, includeChain))
+	}
+	return strings.Join(formatted, "\n\n")
+}
+
+// includeChainOf returns the paths of the configs that included the config the given error is in, from the config that
+// directly included it to the config that was being parsed.
+func includeChainOf(err error) []string {
+	chain := []string{}
+	for err != nil {
+		var includeErr IncludedConfigError
+		if !goerrors.As(err, &includeErr) {
+			break
+		}
+		chain = append(chain, includeErr.ConfigPath)
+		err = includeErr.Err
+	}
+
+	// The errors are wrapped from the outermost config, so the chain is reversed to start from the innermost one.
+	for i, j := 0, len(chain)-1; i < j; i, j = i+1, j-1 {
+		chain[i], chain[j] = chain[j], chain[i]
+	}
+	return chain
+}
+
+// formatDiagnostic formats the given HCL diagnostic with a frame of the source code it is about.
+func formatDiagnostic(code string, diag *hcl.Diagnostic, includeChain []string) string {
+	severity := "Error"
+	if diag.Severity == hcl.DiagWarning {
+		severity = "Warning"
+	}
+
+	lines := []string{fmt.Sprintf("[%s] %s: %s", code, severity, diag.Summary)}
+	if diag.Subject != nil {
+		lines = append(lines, fmt.Sprintf("  --> %s:%d:%d", diag.Subject.Filename, diag.Subject.Start.Line, diag.Subject.Start.Column))
+		lines = append(lines, codeFrame(*diag.Subject)...)
+	}
+	if diag.Detail != "" {
+		lines = append(lines, fmt.Sprintf("   = %s", diag.Detail))
+	}
+	for _, includedBy := range includeChain {
+		lines = append(lines, fmt.Sprintf("   = included by %s", includedBy))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codeFrame returns the lines of the source code of the given range, along with the lines before it, with a caret under
+// the part of the first line that is in the range. Returns nothing if the source can't be read, for example when the
+// range is in an expression that was not read from a file.
+func codeFrame(subject hcl.Range) []string {
+	contents, err := os.ReadFile(subject.Filename)
+	if err != nil {
+		return nil
+	}
+	sourceLines := strings.Split(string(contents), "\n")
+	if subject.Start.Line < 1 || subject.Start.Line > len(sourceLines) {
+		return nil
+	}
+
+	firstLine := max(subject.Start.Line-codeFrameContextLines, 1)
+	lastLine := min(max(subject.End.Line, subject.Start.Line), len(sourceLines))
+	numberWidth := len(strconv.Itoa(lastLine))
+	gutter := strings.Repeat(" ", numberWidth+1) + " |"
+
+	frame := []string{gutter}
+	for lineNumber := firstLine; lineNumber <= lastLine; lineNumber++ {
+		line := strings.TrimRight(sourceLines[lineNumber-1], "\r")
+		frame = append(frame, strings.TrimRight(fmt.Sprintf(" %*d | %s", numberWidth, lineNumber, line), " "))
+		if lineNumber == subject.Start.Line {
+			frame = append(frame, gutter+" "+caretLine(line, subject))
+		}
+	}
+	return frame
+}
+
+// caretLine returns the carets to show under the part of the given line that is in the given range, which starts on
+// that line. The tabs before the carets are kept so that the carets are aligned with the line.
+func caretLine(line string, subject hcl.Range) string {
+	runes := []rune(line)
+	start := min(max(subject.Start.Column-1, 0), len(runes))
+	end := len(runes)
+	if subject.End.Line == subject.Start.Line {
+		end = min(subject.End.Column-1, len(runes))
+	}
+
+	var padding strings.Builder
+	for _, r := range runes[:start] {
+		if r == '\t' {
+			padding.WriteRune('\t')
+		} else {
+			padding.WriteRune(' ')
+		}
+	}
+	return padding.String() + strings.Repeat("^", max(end-start, 1))
+}
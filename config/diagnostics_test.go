@@ -0,0 +1,46 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatErrorWithCodeFrameAndIncludeChain(t *testing.T) {
+	t.Parallel()
+
+	configPath := "../test/fixture-config-error/child/" + DefaultTerragruntConfigPath
+	_, err := ParseConfigFile(configPath, mockOptionsForTestWithConfigPath(t, configPath), nil, nil)
+	require.Error(t, err)
+	assert.Equal(t, ErrorCodeHclDiagnostics, ErrorCode(err))
+
+	formatted := FormatError(err)
+	assert.Contains(t, formatted, "[TG1001] Error: Unsupported attribute")
+	assert.Contains(t, formatted, "fixture-config-error/root.hcl:6:17")
+	assert.Contains(t, formatted, " 5 | inputs = {\n 6 |   region = local.regoin\n   |                 ^^^^^^^\n")
+	assert.Contains(t, formatted, "   = included by "+configPath)
+}
+
+func TestFormatErrorWithoutCode(t *testing.T) {
+	t.Parallel()
+
+	err := errors.WithStackTrace(assert.AnError)
+	assert.Equal(t, "", ErrorCode(err))
+	assert.Equal(t, assert.AnError.Error(), FormatError(err))
+}
+
+func TestFormatErrorMultipleErrors(t *testing.T) {
+	t.Parallel()
+
+	err := multierror.Append(
+		errors.WithStackTrace(DependencyCycle([]string{"a", "b", "a"})),
+		errors.WithStackTrace(InvalidArgError("bad arg")),
+	)
+	formatted := FormatError(err)
+	assert.Contains(t, formatted, "2 errors occurred:")
+	assert.Contains(t, formatted, "[TG1201] Found a dependency cycle between modules: a -> b -> a")
+	assert.Contains(t, formatted, "[TG1901] bad arg")
+}
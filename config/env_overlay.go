@@ -0,0 +1,83 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// GetEnvOverlayConfigPath returns the path of the overlay of the given config file for the given environment, which is
+// the config file with the environment inserted before its extensions: the overlay of terragrunt.hcl for the prod
+// environment is terragrunt.prod.hcl, in the same folder.
+func GetEnvOverlayConfigPath(configPath string, env string) string {
+	name, extensions, found := strings.Cut(filepath.Base(configPath), ".")
+	overlayName := name + "." + env
+	if found {
+		overlayName += "." + extensions
+	}
+	return filepath.Join(filepath.Dir(configPath), overlayName)
+}
+
+// envOverlayConfigPath returns the path of the overlay of the given config file for the environment selected with
+// --terragrunt-env, or an empty string if no environment is selected or the overlay does not exist.
+func envOverlayConfigPath(filename string, terragruntOptions *options.TerragruntOptions) string {
+	if terragruntOptions.ConfigEnv == "" {
+		return ""
+	}
+	overlayPath := GetEnvOverlayConfigPath(filename, terragruntOptions.ConfigEnv)
+	if !util.FileExists(overlayPath) {
+		return ""
+	}
+	return overlayPath
+}
+
+// mergeEnvOverlay parses the overlay of the given config for the selected environment, if there is one, and deep merges
+// it over the config. The overlay is parsed as a config of its own: it can't reference the locals and dependencies of
+// the config it overlays.
+func mergeEnvOverlay(config *TerragruntConfig, filename string, terragruntOptions *options.TerragruntOptions) (*TerragruntConfig, error) {
+	overlayPath := envOverlayConfigPath(filename, terragruntOptions)
+	if overlayPath == "" {
+		return config, nil
+	}
+
+	terragruntOptions.Logger.Debugf("Merging overlay %s for environment %s into %s", overlayPath, terragruntOptions.ConfigEnv, filename)
+	overlayString, err := util.ReadFileAsString(overlayPath)
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := parseConfigString(overlayString, terragruntOptions, nil, overlayPath, &EvalContextExtensions{})
+	if err != nil {
+		return nil, err
+	}
+	return mergeOverlay(config, overlay, terragruntOptions)
+}
+
+// mergePartialEnvOverlay is the equivalent of mergeEnvOverlay for partially parsed configs.
+func mergePartialEnvOverlay(config *TerragruntConfig, filename string, terragruntOptions *options.TerragruntOptions, decodeList []PartialDecodeSectionType) (*TerragruntConfig, error) {
+	overlayPath := envOverlayConfigPath(filename, terragruntOptions)
+	if overlayPath == "" {
+		return config, nil
+	}
+
+	overlayString, err := util.ReadFileAsString(overlayPath)
+	if err != nil {
+		return nil, err
+	}
+	overlay, err := TerragruntConfigFromPartialConfigString(overlayString, terragruntOptions, nil, overlayPath, decodeList)
+	if err != nil {
+		return nil, err
+	}
+	return mergeOverlay(config, overlay, terragruntOptions)
+}
+
+func mergeOverlay(config *TerragruntConfig, overlay *TerragruntConfig, terragruntOptions *options.TerragruntOptions) (*TerragruntConfig, error) {
+	// Like for included configs, locals are not merged, so that they remain local in scope.
+	locals := config.Locals
+	if err := config.DeepMerge(overlay, terragruntOptions); err != nil {
+		return nil, err
+	}
+	config.Locals = locals
+	return config, nil
+}
@@ -0,0 +1,115 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEnvOverlayConfigPath(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		configPath string
+		env        string
+		expected   string
+	}{
+		{"/live/app/terragrunt.hcl", "prod", "/live/app/terragrunt.prod.hcl"},
+		{"/live/app/terragrunt.hcl.json", "dev", "/live/app/terragrunt.dev.hcl.json"},
+		{"/live/app/custom", "prod", "/live/app/custom.prod"},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, GetEnvOverlayConfigPath(testCase.configPath, testCase.env))
+	}
+}
+
+func writeConfigWithOverlay(t *testing.T) string {
+	root := t.TempDir()
+	dir := filepath.Join(root, "app")
+	for _, moduleDir := range []string{dir, filepath.Join(root, "vpc"), filepath.Join(root, "monitoring")} {
+		require.NoError(t, os.MkdirAll(moduleDir, 0755))
+	}
+	base := `
+locals {
+  name = "app"
+}
+dependencies {
+  paths = ["../vpc"]
+}
+inputs = {
+  name     = local.name
+  replicas = 1
+  tags     = { team = "platform" }
+}
+validation {
+  condition     = inputs.replicas < 5
+  error_message = "Too many replicas."
+}
+`
+	overlay := `
+dependencies {
+  paths = ["../monitoring"]
+}
+inputs = {
+  replicas = 3
+  tags     = { env = "prod" }
+}
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DefaultTerragruntConfigPath), []byte(base), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "terragrunt.prod.hcl"), []byte(overlay), 0644))
+	return filepath.Join(dir, DefaultTerragruntConfigPath)
+}
+
+func TestParseConfigFileWithEnvOverlay(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeConfigWithOverlay(t)
+
+	terragruntOptions := terragruntOptionsForTest(t, configPath)
+	terragruntOptions.ConfigEnv = "prod"
+	terragruntConfig, err := ParseConfigFile(configPath, terragruntOptions, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name":     "app",
+		"replicas": float64(3),
+		"tags":     map[string]interface{}{"team": "platform", "env": "prod"},
+	}, terragruntConfig.Inputs)
+	assert.Equal(t, []string{"../vpc", "../monitoring"}, terragruntConfig.Dependencies.Paths)
+	assert.Contains(t, terragruntConfig.Locals, "name")
+
+	// The overlay of an environment that has none is ignored.
+	terragruntOptions = terragruntOptionsForTest(t, configPath)
+	terragruntOptions.ConfigEnv = "dev"
+	terragruntConfig, err = ParseConfigFile(configPath, terragruntOptions, nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), terragruntConfig.Inputs["replicas"])
+}
+
+func TestParseConfigFileWithEnvOverlayValidatesMergedInputs(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeConfigWithOverlay(t)
+	require.NoError(t, os.WriteFile(GetEnvOverlayConfigPath(configPath, "big"), []byte(`inputs = { replicas = 10 }`), 0644))
+
+	terragruntOptions := terragruntOptionsForTest(t, configPath)
+	terragruntOptions.ConfigEnv = "big"
+	_, err := ParseConfigFile(configPath, terragruntOptions, nil, nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Too many replicas.")
+}
+
+func TestPartialParseConfigFileWithEnvOverlay(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeConfigWithOverlay(t)
+
+	terragruntOptions := terragruntOptionsForTest(t, configPath)
+	terragruntOptions.ConfigEnv = "prod"
+	terragruntConfig, err := PartialParseConfigFile(configPath, terragruntOptions, nil, []PartialDecodeSectionType{DependenciesBlock})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"../vpc", "../monitoring"}, terragruntConfig.Dependencies.Paths)
+}
@@ -0,0 +1,93 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The classes of the errors of terraform that the error hooks can match with on_error_classes, instead of matching
+// regular expressions against the error output with on_errors.
+const (
+	// ErrorClassLockTimeout is the class of the errors acquiring the lock of the state.
+	ErrorClassLockTimeout = "lock_timeout"
+	// ErrorClassProviderThrottling is the class of the errors of the APIs of the providers rejecting requests because
+	// of their rate limits.
+	ErrorClassProviderThrottling = "provider_throttling"
+	// ErrorClassAuthFailure is the class of the errors authenticating to, or being authorized by, the providers and
+	// backends, e.g. because of expired credentials.
+	ErrorClassAuthFailure = "auth_failure"
+	// ErrorClassPlanDrift is the class of the errors applying a plan that no longer matches the infrastructure or
+	// the state, e.g. a stale saved plan.
+	ErrorClassPlanDrift = "plan_drift"
+)
+
+var errorClasses = []string{ErrorClassLockTimeout, ErrorClassProviderThrottling, ErrorClassAuthFailure, ErrorClassPlanDrift}
+
+// errorClassPatterns are the regular expressions matching the error output of terraform for each class of errors.
+var errorClassPatterns = map[string][]*regexp.Regexp{
+	ErrorClassLockTimeout: {
+		regexp.MustCompile(`Error acquiring the state lock`),
+		regexp.MustCompile(`Error locking state`),
+		regexp.MustCompile(`ConditionalCheckFailedException`),
+	},
+	ErrorClassProviderThrottling: {
+		regexp.MustCompile(`(?i)throttl`),
+		regexp.MustCompile(`(?i)rate ?limit ?exceeded|Rate exceeded`),
+		regexp.MustCompile(`RequestLimitExceeded|TooManyRequests|SlowDown`),
+		regexp.MustCompile(`429 Too Many Requests`),
+	},
+	ErrorClassAuthFailure: {
+		regexp.MustCompile(`ExpiredToken|InvalidClientTokenId|UnrecognizedClientException|SignatureDoesNotMatch`),
+		regexp.MustCompile(`AccessDenied|UnauthorizedOperation|AuthorizationFailed`),
+		regexp.MustCompile(`NoCredentialProviders|no valid credential sources|could not find default credentials`),
+		regexp.MustCompile(`401 Unauthorized|403 Forbidden|invalid_grant`),
+	},
+	ErrorClassPlanDrift: {
+		regexp.MustCompile(`Saved plan is stale`),
+		regexp.MustCompile(`Provider produced inconsistent (final plan|result after apply)`),
+		regexp.MustCompile(`produced an unexpected new value`),
+	},
+}
+
+// ClassifyError returns the classes of the errors found in the given error output of terraform, in the order of
+// errorClasses. An output can have several classes, e.g. when a lock can't be acquired because of expired credentials.
+func ClassifyError(output string) []string {
+	classes := []string{}
+	for _, class := range errorClasses {
+		if slices.ContainsFunc(errorClassPatterns[class], func(pattern *regexp.Regexp) bool { return pattern.MatchString(output) }) {
+			classes = append(classes, class)
+		}
+	}
+	return classes
+}
+
+// MatchesError returns true if the error hook runs for the given error output, as it matches one of the regular
+// expressions of on_errors, or has one of the classes of on_error_classes.
+func (hook ErrorHook) MatchesError(output string) bool {
+	if util.MatchesAny(hook.OnErrors, output) {
+		return true
+	}
+	if len(hook.OnErrorClasses) == 0 {
+		return false
+	}
+	classes := ClassifyError(output)
+	return slices.ContainsFunc(hook.OnErrorClasses, func(class string) bool { return slices.Contains(classes, class) })
+}
+
+// validateErrorHookMatchers checks that the error hook matches errors with on_errors, on_error_classes or both, and
+// that the classes exist.
+func validateErrorHookMatchers(hook ErrorHook) error {
+	if len(hook.OnErrors) == 0 && len(hook.OnErrorClasses) == 0 {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. At least one of 'on_errors' and 'on_error_classes' is required.", hook.Name))
+	}
+	for _, class := range hook.OnErrorClasses {
+		if !slices.Contains(errorClasses, class) {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The values of 'on_error_classes' must be one of %s, got %q.", hook.Name, strings.Join(errorClasses, ", "), class))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassifyError(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		output   string
+		expected []string
+	}{
+		{"lock", "Error: Error acquiring the state lock\n\nError message: ConditionalCheckFailedException: The conditional request failed", []string{ErrorClassLockTimeout}},
+		{"throttling", "Error: reading EC2 Instances: operation error EC2: DescribeInstances, https response error StatusCode: 503, api error RequestLimitExceeded: Request limit exceeded.", []string{ErrorClassProviderThrottling}},
+		{"gcp rate limit", "googleapi: Error 403: Quota exceeded, rateLimitExceeded", []string{ErrorClassProviderThrottling}},
+		{"expired token", "Error: error configuring S3 Backend: ExpiredToken: The security token included in the request is expired", []string{ErrorClassAuthFailure}},
+		{"stale plan", "Error: Saved plan is stale\n\nThe given plan file can no longer be applied because the state was changed", []string{ErrorClassPlanDrift}},
+		{"lock with expired token", "Error: Error acquiring the state lock\n\nError message: ExpiredToken: The security token included in the request is expired", []string{ErrorClassLockTimeout, ErrorClassAuthFailure}},
+		{"other", "Error: Unsupported argument", []string{}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, ClassifyError(testCase.output))
+		})
+	}
+}
+
+func TestErrorHookMatchesError(t *testing.T) {
+	t.Parallel()
+
+	output := "Error: Error acquiring the state lock"
+
+	assert.True(t, ErrorHook{OnErrorClasses: []string{ErrorClassAuthFailure, ErrorClassLockTimeout}}.MatchesError(output))
+	assert.False(t, ErrorHook{OnErrorClasses: []string{ErrorClassAuthFailure}}.MatchesError(output))
+	assert.True(t, ErrorHook{OnErrors: []string{"state lock"}, OnErrorClasses: []string{ErrorClassAuthFailure}}.MatchesError(output))
+	assert.False(t, ErrorHook{}.MatchesError(output))
+}
+
+func TestParseTerragruntConfigErrorHookClasses(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		hook          string
+		expectedError string
+	}{
+		{"classes", `on_error_classes = ["lock_timeout", "provider_throttling"]`, ""},
+		{"classes and regular expressions", `on_errors = [".*"]` + "\n" + `on_error_classes = ["plan_drift"]`, ""},
+		{"no matcher", ``, "At least one of 'on_errors' and 'on_error_classes' is required."},
+		{"unknown class", `on_error_classes = ["timeout"]`, `got "timeout"`},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := `
+terraform {
+  error_hook "unlock" {
+    commands = ["apply"]
+    execute  = ["./unlock.sh"]
+    ` + testCase.hook + `
+  }
+}
+`
+			_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// evalExpressionFilename is the name the expressions passed to EvaluateExpression are reported with in the errors.
+const evalExpressionFilename = "<eval>"
+
+// ParseConfigEvalContext parses the Terragrunt config file at the given path up to the `dependency` blocks, retrieving
+// the dependency outputs in the process, and returns the evaluation context the rest of the config is evaluated in. That
+// is, the expressions evaluated in the returned context can reference the same locals, include, dependency and feature
+// values as the attributes of the config.
+func ParseConfigEvalContext(filename string, terragruntOptions *options.TerragruntOptions) (*hcl.EvalContext, error) {
+	configString, err := util.ReadFileAsString(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := hclparse.NewParser()
+	file, err := parseHcl(parser, configString, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := setIAMRole(configString, terragruntOptions, nil, filename); err != nil {
+		return nil, err
+	}
+
+	contextExtensions := &EvalContextExtensions{}
+	if _, err := decodeContextExtensions(parser, file, filename, terragruntOptions, nil, contextExtensions); err != nil {
+		return nil, err
+	}
+
+	return contextExtensions.CreateTerragruntEvalContext(filename, terragruntOptions)
+}
+
+// EvaluateExpression parses the given string as an HCL expression, e.g. `local.region` or
+// `"${local.env}-${dependency.vpc.outputs.vpc_id}"`, and evaluates it in the given evaluation context.
+func EvaluateExpression(expression string, evalContext *hcl.EvalContext) (cty.Value, error) {
+	expr, diags := hclsyntax.ParseExpression([]byte(expression), evalExpressionFilename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.WithStackTrace(diags)
+	}
+
+	value, diags := expr.Value(evalContext)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.WithStackTrace(diags)
+	}
+	return value, nil
+}
+
+// FormatExpressionValue formats the given value of an expression in the HCL syntax. The values that are not known, such
+// as the outputs of the dependencies that are not applied yet when their retrieval is skipped, are shown as null.
+func FormatExpressionValue(value cty.Value) string {
+	if !value.IsWhollyKnown() {
+		value = unknownValuesToNull(value)
+	}
+	return string(hclwrite.Format(hclwrite.TokensForValue(value).Bytes()))
+}
+
+// EvalContextWithInputs returns a child of the given evaluation context where the given resolved inputs of the config
+// are exposed as `inputs`, e.g. `inputs.bucket_name`.
+func EvalContextWithInputs(evalContext *hcl.EvalContext, inputs map[string]interface{}) (*hcl.EvalContext, error) {
+	inputsCty, err := convertToCtyWithJson(inputs)
+	if err != nil {
+		return nil, err
+	}
+
+	child := evalContext.NewChild()
+	child.Variables = map[string]cty.Value{validationInputsVariable: inputsCty}
+	return child, nil
+}
+
+// EvaluateTemplate parses the given string as an HCL template, e.g. `arn:aws:s3:::${inputs.bucket_name}`, and renders
+// it in the given evaluation context.
+func EvaluateTemplate(template string, evalContext *hcl.EvalContext) (string, error) {
+	expr, diags := hclsyntax.ParseTemplate([]byte(template), evalExpressionFilename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return "", errors.WithStackTrace(diags)
+	}
+
+	value, diags := expr.Value(evalContext)
+	if diags.HasErrors() {
+		return "", errors.WithStackTrace(diags)
+	}
+	if value.IsNull() || !value.IsWhollyKnown() || value.Type() != cty.String {
+		return "", errors.WithStackTrace(TemplateNotRendered{Template: template})
+	}
+	return value.AsString(), nil
+}
+
+// Custom error types
+
+type TemplateNotRendered struct {
+	Template string
+}
+
+func (err TemplateNotRendered) Error() string {
+	return fmt.Sprintf("The template %q did not render to a string, as the values it references are null or not known yet.", err.Template)
+}
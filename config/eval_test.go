@@ -0,0 +1,52 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+)
+
+func TestEvaluateExpression(t *testing.T) {
+	t.Parallel()
+
+	evalContext := &hcl.EvalContext{
+		Variables: map[string]cty.Value{
+			"local": cty.ObjectVal(map[string]cty.Value{"region": cty.StringVal("us-east-1")}),
+		},
+	}
+
+	value, err := EvaluateExpression(`"${local.region}-a"`, evalContext)
+	require.NoError(t, err)
+	assert.Equal(t, cty.StringVal("us-east-1-a"), value)
+
+	_, err = EvaluateExpression("local.", evalContext)
+	require.Error(t, err)
+
+	_, err = EvaluateExpression("local.missing", evalContext)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Unsupported attribute")
+}
+
+func TestFormatExpressionValue(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		value    cty.Value
+		expected string
+	}{
+		{cty.StringVal("foo"), `"foo"`},
+		{cty.NumberIntVal(42), "42"},
+		{cty.UnknownVal(cty.String), "null"},
+		{
+			cty.ObjectVal(map[string]cty.Value{"id": cty.UnknownVal(cty.String), "name": cty.StringVal("foo")}),
+			"{\n  id   = null\n  name = \"foo\"\n}",
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, FormatExpressionValue(testCase.value))
+	}
+}
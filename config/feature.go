@@ -0,0 +1,177 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// terragruntFeatureBlock is the configuration of a feature flag, whose value is the default unless it is overridden with
+// --feature on the command line:
+//
+//	feature "enable_monitoring" {
+//	  default = false
+//	}
+//
+// The value of the flag is exposed to the rest of the configuration as `feature.enable_monitoring.value`.
+type terragruntFeatureBlock struct {
+	Name    string    `hcl:"name,label"`
+	Default cty.Value `hcl:"default,attr"`
+}
+
+// terragruntFeatures is a struct that can be used to only decode the feature blocks.
+type terragruntFeatures struct {
+	Features []terragruntFeatureBlock `hcl:"feature,block"`
+	Remain   hcl.Body                 `hcl:",remain"`
+}
+
+// evaluateFeatureBlocks decodes the feature blocks of the given file and returns the value of each flag, which is the
+// value passed in with --feature if there is one, or the default otherwise, as an object to expose as the `feature`
+// variable. The defaults can only reference functions, as the feature blocks are evaluated before the locals, so that
+// locals can reference them.
+func evaluateFeatureBlocks(file *hcl.File, filename string, terragruntOptions *options.TerragruntOptions, evalContext *hcl.EvalContext) (*cty.Value, error) {
+	decoded := terragruntFeatures{}
+	if err := decodeHcl(file, filename, &decoded, evalContext); err != nil {
+		return nil, err
+	}
+
+	features := map[string]cty.Value{}
+	for _, block := range decoded.Features {
+		if _, isDuplicate := features[block.Name]; isDuplicate {
+			return nil, errors.WithStackTrace(DuplicatedFeatureBlock{Name: block.Name, ConfigPath: filename})
+		}
+
+		value, err := featureValue(block, terragruntOptions)
+		if err != nil {
+			return nil, err
+		}
+		features[block.Name] = cty.ObjectVal(map[string]cty.Value{"value": value})
+	}
+
+	featuresAsCty := cty.EmptyObjectVal
+	if len(features) > 0 {
+		featuresAsCty = cty.ObjectVal(features)
+	}
+	return &featuresAsCty, nil
+}
+
+// featureValue returns the value of the given feature flag, converting the value passed in with --feature, if any, to
+// the type of its default.
+func featureValue(block terragruntFeatureBlock, terragruntOptions *options.TerragruntOptions) (cty.Value, error) {
+	if block.Default.IsNull() || !block.Default.IsKnown() {
+		return cty.NilVal, errors.WithStackTrace(InvalidFeatureDefault{Name: block.Name, Type: "null"})
+	}
+
+	override, isOverridden := terragruntOptions.FeatureFlags[block.Name]
+
+	switch block.Default.Type() {
+	case cty.Bool:
+		if !isOverridden {
+			return block.Default, nil
+		}
+		value, err := strconv.ParseBool(override)
+		if err != nil {
+			return cty.NilVal, errors.WithStackTrace(InvalidFeatureValue{Name: block.Name, Value: override, Type: "bool"})
+		}
+		return cty.BoolVal(value), nil
+	case cty.Number:
+		if !isOverridden {
+			return block.Default, nil
+		}
+		value, err := cty.ParseNumberVal(override)
+		if err != nil {
+			return cty.NilVal, errors.WithStackTrace(InvalidFeatureValue{Name: block.Name, Value: override, Type: "number"})
+		}
+		return value, nil
+	case cty.String:
+		if !isOverridden {
+			return block.Default, nil
+		}
+		return cty.StringVal(override), nil
+	default:
+		return cty.NilVal, errors.WithStackTrace(InvalidFeatureDefault{Name: block.Name, Type: block.Default.Type().FriendlyName()})
+	}
+}
+
+// declaredFeatureNames returns the names of the feature flags of the given object of feature values.
+func declaredFeatureNames(features *cty.Value) []string {
+	names := []string{}
+	if features == nil || !features.Type().IsObjectType() {
+		return names
+	}
+	for name := range features.Type().AttributeTypes() {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CheckFeatureFlags checks that the feature flags passed in with --feature are declared by the given config, the
+// configs it includes or the configs it imports, as a flag that matches no feature block, e.g. because of a typo, would
+// otherwise be silently ignored. The undeclared flags are a warning, or an error with --terragrunt-strict-config.
+func CheckFeatureFlags(terragruntOptions *options.TerragruntOptions, terragruntConfig *TerragruntConfig) error {
+	undeclared := []string{}
+	for name := range terragruntOptions.FeatureFlags {
+		if !util.ListContainsElement(terragruntConfig.DeclaredFeatures, name) {
+			undeclared = append(undeclared, name)
+		}
+	}
+	if len(undeclared) == 0 {
+		return nil
+	}
+	sort.Strings(undeclared)
+
+	err := UndeclaredFeatureFlags{Names: undeclared, ConfigPath: terragruntOptions.TerragruntConfigPath}
+	if terragruntOptions.StrictConfig {
+		return errors.WithStackTrace(err)
+	}
+	terragruntOptions.Logger.Warn(err.Error())
+	return nil
+}
+
+// Custom error types
+
+type DuplicatedFeatureBlock struct {
+	Name       string
+	ConfigPath string
+}
+
+func (err DuplicatedFeatureBlock) Error() string {
+	return fmt.Sprintf("Feature %s is declared more than once in %s.", err.Name, err.ConfigPath)
+}
+
+type InvalidFeatureDefault struct {
+	Name string
+	Type string
+}
+
+func (err InvalidFeatureDefault) Error() string {
+	return fmt.Sprintf("The default of feature %s must be a bool, a number or a string, got %s.", err.Name, err.Type)
+}
+
+type InvalidFeatureValue struct {
+	Name  string
+	Value string
+	Type  string
+}
+
+func (err InvalidFeatureValue) Error() string {
+	return fmt.Sprintf("The value %q passed in for feature %s is not a valid %s.", err.Value, err.Name, err.Type)
+}
+
+type UndeclaredFeatureFlags struct {
+	Names      []string
+	ConfigPath string
+}
+
+func (err UndeclaredFeatureFlags) Error() string {
+	return fmt.Sprintf("The features %s passed in with --feature are not declared by any feature block of %s or of the configs it includes or imports.", strings.Join(err.Names, ", "), err.ConfigPath)
+}
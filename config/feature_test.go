@@ -0,0 +1,133 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const featureTestConfig = `
+feature "enable_monitoring" {
+  default = false
+}
+
+feature "instance_type" {
+  default = "t3.micro"
+}
+
+feature "replicas" {
+  default = 1
+}
+
+locals {
+  monitoring = feature.enable_monitoring.value ? "on" : "off"
+}
+
+inputs = {
+  monitoring    = local.monitoring
+  instance_type = feature.instance_type.value
+  replicas      = feature.replicas.value
+}
+`
+
+func TestFeatureBlocks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name         string
+		featureFlags map[string]string
+		expected     map[string]interface{}
+	}{
+		{
+			"defaults",
+			map[string]string{},
+			map[string]interface{}{"monitoring": "off", "instance_type": "t3.micro", "replicas": float64(1)},
+		},
+		{
+			"overrides",
+			map[string]string{"enable_monitoring": "true", "instance_type": "m5.large", "replicas": "3", "undeclared": "foo"},
+			map[string]interface{}{"monitoring": "on", "instance_type": "m5.large", "replicas": float64(3)},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+			terragruntOptions.FeatureFlags = testCase.featureFlags
+			terragruntConfig, err := ParseConfigString(featureTestConfig, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, terragruntConfig.Inputs)
+		})
+	}
+}
+
+func TestFeatureBlockInvalidOverride(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	terragruntOptions.FeatureFlags = map[string]string{"enable_monitoring": "maybe"}
+	_, err := ParseConfigString(featureTestConfig, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	assert.Equal(t, InvalidFeatureValue{Name: "enable_monitoring", Value: "maybe", Type: "bool"}, errors.Unwrap(err))
+}
+
+func TestFeatureBlockDuplicated(t *testing.T) {
+	t.Parallel()
+
+	config := `
+feature "enabled" {
+  default = true
+}
+feature "enabled" {
+  default = false
+}
+`
+	terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	_, err := ParseConfigString(config, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	assert.Equal(t, DuplicatedFeatureBlock{Name: "enabled", ConfigPath: DefaultTerragruntConfigPath}, errors.Unwrap(err))
+}
+
+func TestCheckFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "root.hcl"), []byte(`
+feature "enable_monitoring" {
+  default = false
+}
+`), 0644))
+	childDir := filepath.Join(rootDir, "child")
+	require.NoError(t, os.MkdirAll(childDir, 0755))
+	childConfigPath := filepath.Join(childDir, DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(childConfigPath, []byte(`
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+feature "replicas" {
+  default = 1
+}
+`), 0644))
+
+	terragruntOptions := terragruntOptionsForTest(t, childConfigPath)
+	terragruntOptions.FeatureFlags = map[string]string{"enable_monitoring": "true", "replicas": "3", "enable_monitorng": "true"}
+	terragruntConfig, err := ParseConfigFile(childConfigPath, terragruntOptions, nil, nil)
+	require.NoError(t, err)
+
+	// The features declared by the included config count as declared.
+	assert.ElementsMatch(t, []string{"enable_monitoring", "replicas"}, terragruntConfig.DeclaredFeatures)
+
+	// The undeclared flag is only a warning, unless the config is strict.
+	require.NoError(t, CheckFeatureFlags(terragruntOptions, terragruntConfig))
+
+	terragruntOptions.StrictConfig = true
+	err = CheckFeatureFlags(terragruntOptions, terragruntConfig)
+	assert.Equal(t, UndeclaredFeatureFlags{Names: []string{"enable_monitorng"}, ConfigPath: childConfigPath}, errors.Unwrap(err))
+}
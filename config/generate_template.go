@@ -0,0 +1,110 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The name of the variable that exposes the resolved inputs of the configuration to the templates of generate blocks.
+const generateTemplateInputsVariable = "inputs"
+
+// renderGenerateTemplates renders the contents of the generate blocks with `template = true` as HCL templates, such as:
+//
+//	generate "provider" {
+//	  path      = "provider.tf"
+//	  if_exists = "overwrite_terragrunt"
+//	  template  = true
+//	  contents  = file("provider.tftpl")
+//	}
+//
+// The templates are rendered in the context of the configuration that defines the block, so they can reference its
+// locals and dependency outputs, with the resolved inputs of the configuration, including the ones of included
+// configurations, exposed as `inputs`. As with `templatefile` in Terraform, the templates can use the `%{ for }` and
+// `%{ if }` directives to generate, for example, a provider block per region.
+func renderGenerateTemplates(config *TerragruntConfig, terragruntOptions *options.TerragruntOptions) error {
+	names := []string{}
+	for name, genConfig := range config.GenerateConfigs {
+		if genConfig.TemplateContext != nil {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	// Render the templates in a stable order, so that the first error is always the same one.
+	sort.Strings(names)
+
+	inputs, err := convertToCtyWithJson(config.Inputs)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range names {
+		genConfig := config.GenerateConfigs[name]
+		contents, err := renderGenerateTemplate(name, genConfig, inputs)
+		if err != nil {
+			return err
+		}
+		if contents == nil {
+			// The template depends on values that are not known yet, such as the outputs of dependencies that were
+			// not retrieved, so it is left as is.
+			terragruntOptions.Logger.Debugf("Skipping rendering the template of generate block %s whose value is unknown", name)
+			continue
+		}
+
+		genConfig.Contents = *contents
+		genConfig.TemplateContext = nil
+		config.GenerateConfigs[name] = genConfig
+	}
+	return nil
+}
+
+// renderGenerateTemplate renders the contents of the given generate block as an HCL template, with the given inputs.
+// Returns nil if the rendered contents are not known.
+func renderGenerateTemplate(name string, genConfig codegen.GenerateConfig, inputs cty.Value) (*string, error) {
+	filename := fmt.Sprintf("<template of generate %q>", name)
+	expr, diags := hclsyntax.ParseTemplate([]byte(genConfig.Contents), filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+
+	evalContext := genConfig.TemplateContext.NewChild()
+	evalContext.Variables = map[string]cty.Value{generateTemplateInputsVariable: inputs}
+
+	value, diags := expr.Value(evalContext)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+	if !value.IsWhollyKnown() {
+		return nil, nil
+	}
+
+	// A template that is a single interpolation, e.g. "${inputs.regions}", can result in a value of any type.
+	value, err := convert.Convert(value, cty.String)
+	if err != nil || value.IsNull() {
+		return nil, errors.WithStackTrace(InvalidGenerateTemplateResult{Name: name, Range: expr.Range()})
+	}
+
+	contents := value.AsString()
+	return &contents, nil
+}
+
+// Custom error types
+
+type InvalidGenerateTemplateResult struct {
+	Name  string
+	Range hcl.Range
+}
+
+func (err InvalidGenerateTemplateResult) Error() string {
+	return fmt.Sprintf("The template of generate block %s must result in a string (%s).", err.Name, err.Range)
+}
@@ -1,6 +1,9 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
 	"path/filepath"
 
 	"github.com/gruntwork-io/go-commons/errors"
@@ -9,6 +12,7 @@ import (
 	"github.com/hashicorp/hcl/v2/hclparse"
 	"github.com/zclconf/go-cty/cty"
 	"github.com/zclconf/go-cty/cty/gocty"
+	"gopkg.in/yaml.v3"
 )
 
 // parseHcl uses the HCL2 parser to parse the given string into an HCL file body.
@@ -21,6 +25,19 @@ func parseHcl(parser *hclparse.Parser, hcl string, filename string) (file *hcl.F
 		}
 	}()
 
+	if isYamlConfig(filename) {
+		jsonBytes, err := yamlToHclJSON([]byte(hcl), filename)
+		if err != nil {
+			return nil, err
+		}
+		file, parseDiagnostics := parser.ParseJSON(jsonBytes, filename)
+		if parseDiagnostics != nil && parseDiagnostics.HasErrors() {
+			return nil, parseDiagnostics
+		}
+
+		return file, nil
+	}
+
 	if filepath.Ext(filename) == ".json" {
 		file, parseDiagnostics := parser.ParseJSON([]byte(hcl), filename)
 		if parseDiagnostics != nil && parseDiagnostics.HasErrors() {
@@ -38,6 +55,100 @@ func parseHcl(parser *hclparse.Parser, hcl string, filename string) (file *hcl.F
 	return file, nil
 }
 
+// isYamlConfig returns true if the given config file is written in the YAML front-end, which is the JSON syntax of HCL
+// written as YAML.
+func isYamlConfig(filename string) bool {
+	ext := filepath.Ext(filename)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// yamlToHclJSON converts the given config written in the YAML front-end into the JSON syntax of HCL, so that it is
+// parsed into the same config as the equivalent terragrunt.hcl.json. The strings are kept as is, so they can contain
+// template expressions such as "${local.name}", as in the JSON syntax, and the order of the keys is kept, as it is the
+// order of the blocks.
+func yamlToHclJSON(contents []byte, filename string) ([]byte, error) {
+	var document yaml.Node
+	if err := yaml.Unmarshal(contents, &document); err != nil {
+		return nil, errors.WithStackTrace(InvalidYamlConfig{ConfigPath: filename, Err: err})
+	}
+	// An empty document is an empty config.
+	if len(document.Content) == 0 {
+		return []byte("{}"), nil
+	}
+	if root := document.Content[0]; root.Kind != yaml.MappingNode {
+		return nil, errors.WithStackTrace(InvalidYamlConfig{ConfigPath: filename, Err: fmt.Errorf("line %d: the top level value must be a mapping", root.Line)})
+	}
+
+	var jsonBytes bytes.Buffer
+	if err := writeYamlNodeAsJSON(&jsonBytes, document.Content[0]); err != nil {
+		return nil, errors.WithStackTrace(InvalidYamlConfig{ConfigPath: filename, Err: err})
+	}
+	return jsonBytes.Bytes(), nil
+}
+
+// writeYamlNodeAsJSON writes the given YAML node as JSON, keeping the order of the keys of the mappings.
+func writeYamlNodeAsJSON(out *bytes.Buffer, node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.AliasNode:
+		return writeYamlNodeAsJSON(out, node.Alias)
+
+	case yaml.MappingNode:
+		out.WriteByte('{')
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Kind != yaml.ScalarNode {
+				return fmt.Errorf("line %d: the keys of mappings must be strings", key.Line)
+			}
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := writeJSONValue(out, key.Value); err != nil {
+				return err
+			}
+			out.WriteByte(':')
+			if err := writeYamlNodeAsJSON(out, value); err != nil {
+				return err
+			}
+		}
+		out.WriteByte('}')
+		return nil
+
+	case yaml.SequenceNode:
+		out.WriteByte('[')
+		for i, item := range node.Content {
+			if i > 0 {
+				out.WriteByte(',')
+			}
+			if err := writeYamlNodeAsJSON(out, item); err != nil {
+				return err
+			}
+		}
+		out.WriteByte(']')
+		return nil
+
+	default:
+		// Dates are kept as the strings they are written as, instead of being converted to timestamps.
+		if node.Tag == "!!timestamp" {
+			return writeJSONValue(out, node.Value)
+		}
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return err
+		}
+		return writeJSONValue(out, value)
+	}
+}
+
+// writeJSONValue writes the given scalar value as JSON.
+func writeJSONValue(out *bytes.Buffer, value interface{}) error {
+	valueJSON, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	out.Write(valueJSON)
+	return nil
+}
+
 // decodeHcl uses the HCL2 parser to decode the parsed HCL into the struct specified by out.
 //
 // Note that we take a two pass approach to support parsing include blocks without a label. Ideally we can parse include
@@ -74,7 +185,14 @@ func decodeHcl(
 		}
 	}
 
-	decodeDiagnostics := gohcl.DecodeBody(file.Body, evalContext, out)
+	body := file.Body
+	if evalContext != nil {
+		if hookOutputs, found := evalContext.Variables[hookOutputVariable]; found && !hookOutputs.IsWhollyKnown() {
+			body = hookOutputPlaceholderBody{Body: body, topLevel: true}
+		}
+	}
+
+	decodeDiagnostics := gohcl.DecodeBody(body, evalContext, out)
 	if decodeDiagnostics != nil && decodeDiagnostics.HasErrors() {
 		return decodeDiagnostics
 	}
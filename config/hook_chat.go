@@ -0,0 +1,153 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+)
+
+var hookStatuses = []string{HookStatusSuccess, HookStatusFailure, HookStatusSkipped}
+
+// How the default message of the slack and msteams hooks describes each status of the run of the module.
+var hookStatusDescriptions = map[string]string{
+	HookStatusSuccess: "succeeded",
+	HookStatusFailure: "failed",
+	HookStatusSkipped: "was skipped",
+}
+
+// The colors of the message of the msteams hooks for each status, as named by adaptive cards.
+var msTeamsStatusColors = map[string]string{
+	HookStatusSuccess: "Good",
+	HookStatusFailure: "Attention",
+	HookStatusSkipped: "Warning",
+}
+
+// validateChatHook checks the attributes of a slack or msteams hook, whose payload is built from its message.
+func validateChatHook(hook Hook) error {
+	if hook.Headers != nil || hook.Payload != nil {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. 'headers' and 'payload' can only be set when 'type' is %q. The text of a %q hook is set with 'message'.", hook.Name, HookTypeHTTP, hookType(hook)))
+	}
+	if hook.Channels == nil {
+		return nil
+	}
+	if hookType(hook) == HookTypeMSTeams {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. 'channels' can't be set when 'type' is %q, as the channel of a Microsoft Teams webhook is the one of its URL. Use an on_success_hook and an on_failure_hook with different URLs instead.", hook.Name, HookTypeMSTeams))
+	}
+	for status := range *hook.Channels {
+		if !slices.Contains(hookStatuses, status) {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The keys of 'channels' must be one of %s, got %q.", hook.Name, strings.Join(hookStatuses, ", "), status))
+		}
+	}
+	return nil
+}
+
+// notifiesStatus returns true if the hook sends its request for the given status. A hook with channels only posts for
+// the statuses that have a channel.
+func (hook Hook) notifiesStatus(status string) bool {
+	if hook.Channels == nil {
+		return true
+	}
+	_, found := (*hook.Channels)[status]
+	return found
+}
+
+// slackPayload returns the payload of a slack hook for the given event, in the format of the incoming webhooks of
+// Slack. The channel is the one of the status in channels, if any, and the default channel of the webhook otherwise.
+func (hook Hook) slackPayload(event HookEvent) ([]byte, error) {
+	message, err := hook.renderMessage(event)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := struct {
+		Text    string `json:"text"`
+		Channel string `json:"channel,omitempty"`
+	}{Text: message}
+	if hook.Channels != nil {
+		payload.Channel = (*hook.Channels)[event.Status]
+	}
+
+	body, err := json.Marshal(payload)
+	return body, errors.WithStackTrace(err)
+}
+
+// msTeamsPayload returns the payload of an msteams hook for the given event, which is an adaptive card holding the
+// message, colored by the status, as accepted by the webhooks of Microsoft Teams workflows.
+func (hook Hook) msTeamsPayload(event HookEvent) ([]byte, error) {
+	message, err := hook.renderMessage(event)
+	if err != nil {
+		return nil, err
+	}
+
+	textBlock := map[string]any{"type": "TextBlock", "text": message, "wrap": true}
+	if color, found := msTeamsStatusColors[event.Status]; found {
+		textBlock["color"] = color
+	}
+	payload := map[string]any{
+		"type": "message",
+		"attachments": []any{
+			map[string]any{
+				"contentType": "application/vnd.microsoft.card.adaptive",
+				"content": map[string]any{
+					"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+					"type":    "AdaptiveCard",
+					"version": "1.4",
+					"body":    []any{textBlock},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	return body, errors.WithStackTrace(err)
+}
+
+// renderMessage returns the message of a slack or msteams hook for the given event. Without a message attribute, the
+// message states the command, the status and the path of the module, along with the change summary of the plan if
+// any. Otherwise, the message is rendered as an HCL template with the event exposed as `event`, as the payload of the
+// http hooks is.
+func (hook Hook) renderMessage(event HookEvent) (string, error) {
+	if hook.Message == nil {
+		return defaultHookMessage(event), nil
+	}
+
+	value, err := renderHookTemplate(*hook.Message, fmt.Sprintf("<message of hook %q>", hook.Name), event)
+	if err != nil {
+		return "", err
+	}
+	value, err = convert.Convert(value, cty.String)
+	if err != nil || value.IsNull() || !value.IsKnown() {
+		return "", errors.WithStackTrace(InvalidHookMessage{Name: hook.Name})
+	}
+	return value.AsString(), nil
+}
+
+// defaultHookMessage returns the message of the slack and msteams hooks without a message attribute, e.g.
+// "terragrunt apply failed in /live/prod/vpc (1 to add, 0 to change, 0 to destroy)".
+func defaultHookMessage(event HookEvent) string {
+	description, found := hookStatusDescriptions[event.Status]
+	if !found {
+		description = "finished with status " + event.Status
+	}
+
+	message := fmt.Sprintf("terragrunt %s %s in %s", event.Command, description, event.Path)
+	if event.Plan != nil {
+		message += fmt.Sprintf(" (%d to add, %d to change, %d to destroy)", event.Plan.Add, event.Plan.Change, event.Plan.Destroy)
+	}
+	return message
+}
+
+// Custom error types
+
+type InvalidHookMessage struct {
+	Name string
+}
+
+func (err InvalidHookMessage) Error() string {
+	return fmt.Sprintf("The message of hook %s must be a template resulting in a string.", err.Name)
+}
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookSendSlackMessage(t *testing.T) {
+	t.Parallel()
+
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+	}))
+	defer server.Close()
+
+	opts := mockOptionsForTest(t)
+	opts.Env = map[string]string{"SLACK_WEBHOOK_URL": server.URL}
+
+	hookType := HookTypeSlack
+	envName := "SLACK_WEBHOOK_URL"
+	message := "${event.path}: ${event.command} ${event.status}"
+	channels := map[string]string{HookStatusFailure: "#infra-alerts", HookStatusSuccess: ""}
+	hook := Hook{Name: "notify", Type: &hookType, URLFromEnv: &envName, Message: &message, Channels: &channels}
+
+	require.NoError(t, hook.SendHTTPRequest(opts, HookEvent{Path: "/live/vpc", Command: "apply", Status: HookStatusFailure}))
+	require.NoError(t, hook.SendHTTPRequest(opts, HookEvent{Path: "/live/vpc", Command: "apply", Status: HookStatusSuccess}))
+	require.NoError(t, hook.SendHTTPRequest(opts, HookEvent{Path: "/live/vpc", Command: "apply", Status: HookStatusSkipped}))
+
+	require.Len(t, bodies, 2)
+	assert.JSONEq(t, `{"text": "/live/vpc: apply failure", "channel": "#infra-alerts"}`, bodies[0])
+	assert.JSONEq(t, `{"text": "/live/vpc: apply success"}`, bodies[1])
+}
+
+func TestHookSendMSTeamsMessage(t *testing.T) {
+	t.Parallel()
+
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	hookType := HookTypeMSTeams
+	hook := Hook{Name: "notify", Type: &hookType, URLFromCommand: []string{"echo", server.URL}}
+	event := HookEvent{Path: "/live/vpc", Command: "plan", Status: HookStatusSuccess, Plan: &HookEventPlan{Add: 1, Destroy: 2}}
+
+	require.NoError(t, hook.SendHTTPRequest(mockOptionsForTest(t), event))
+	assert.JSONEq(t, `{
+  "type": "message",
+  "attachments": [{
+    "contentType": "application/vnd.microsoft.card.adaptive",
+    "content": {
+      "$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+      "type": "AdaptiveCard",
+      "version": "1.4",
+      "body": [{"type": "TextBlock", "text": "terragrunt plan succeeded in /live/vpc (1 to add, 0 to change, 2 to destroy)", "wrap": true, "color": "Good"}]
+    }
+  }]
+}`, string(body))
+}
+
+func TestHookSendChatMessageWithoutURL(t *testing.T) {
+	t.Parallel()
+
+	hookType := HookTypeSlack
+	envName := "SLACK_WEBHOOK_URL"
+	hook := Hook{Name: "notify", Type: &hookType, URLFromEnv: &envName}
+
+	err := hook.SendHTTPRequest(mockOptionsForTest(t), HookEvent{Status: HookStatusFailure})
+	require.Error(t, err)
+	assert.Equal(t, HookHTTPRequestFailed{Name: "notify", Reason: "the environment variable SLACK_WEBHOOK_URL of 'url_from_env' is not set"}, errors.Unwrap(err))
+}
+
+func TestParseTerragruntConfigChatHooks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		hook          string
+		expectedError string
+	}{
+		{"slack", `type = "slack"` + "\n" + `url_from_env = "SLACK_WEBHOOK_URL"` + "\n" + `channels = { failure = "#alerts" }`, ""},
+		{"msteams", `type = "msteams"` + "\n" + `url_from_command = ["vault", "read", "-field=url", "secret/teams"]` + "\n" + `message = "$${event.path} failed"`, ""},
+		{"several urls", `type = "slack"` + "\n" + `url = "https://hooks.slack.com"` + "\n" + `url_from_env = "SLACK_WEBHOOK_URL"`, `Only one of 'url', 'url_from_env' and 'url_from_command' can be set.`},
+		{"slack with payload", `type = "slack"` + "\n" + `url = "https://hooks.slack.com"` + "\n" + `payload = "{}"`, `'headers' and 'payload' can only be set when 'type' is "http".`},
+		{"msteams with channels", `type = "msteams"` + "\n" + `url = "https://example.webhook.office.com"` + "\n" + `channels = { failure = "alerts" }`, `'channels' can't be set when 'type' is "msteams"`},
+		{"unknown status", `type = "slack"` + "\n" + `url = "https://hooks.slack.com"` + "\n" + `channels = { error = "#alerts" }`, `got "error"`},
+		{"http with message", `type = "http"` + "\n" + `url = "https://hooks.example.com"` + "\n" + `message = "failed"`, `'message' and 'channels' can only be set when 'type' is "slack" or "msteams".`},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := `
+terraform {
+  on_failure_hook "notify" {
+    commands = ["apply"]
+    ` + testCase.hook + `
+  }
+}
+`
+			terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, terragruntConfig.Terraform.OnFailureHooks[0].IsHTTP())
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// ContainerRuntimeDocker runs the hooks with an image with docker. This is the default.
+	ContainerRuntimeDocker = "docker"
+	// ContainerRuntimePodman runs the hooks with an image with podman.
+	ContainerRuntimePodman = "podman"
+)
+
+// The environment variables that are passed on to the hooks running in a container, apart from the ones of their env
+// attribute: the inputs of the module, and the credentials terragrunt got by assuming an IAM role. The rest of the
+// environment stays on the host, as it rarely makes sense in the container.
+var (
+	containerEnvPrefixes = []string{"TF_VAR_"}
+	containerEnvNames    = []string{"AWS_ACCESS_KEY_ID", "AWS_SECRET_ACCESS_KEY", "AWS_SESSION_TOKEN", "AWS_SECURITY_TOKEN"}
+)
+
+// IsContainerized returns true if the command of the hook runs in a container of its image.
+func (hook Hook) IsContainerized() bool {
+	return hook.Image != nil
+}
+
+// ExecuteCommand returns the command that runs the hook in the given working directory: its execute attribute, or, if
+// the hook has an image, the command running execute in a container of the image. The directory of the module and the
+// working directory are mounted in the container at the same paths, so that the paths given to the hook work the same
+// in and out of the container.
+func (hook Hook) ExecuteCommand(terragruntOptions *options.TerragruntOptions, workingDir string) []string {
+	if !hook.IsContainerized() {
+		return hook.Execute
+	}
+
+	runtime := ContainerRuntimeDocker
+	if hook.ContainerRuntime != nil {
+		runtime = *hook.ContainerRuntime
+	}
+	if workingDir == "" {
+		workingDir = terragruntOptions.WorkingDir
+	}
+	workingDir = hostAbsPath(workingDir)
+	moduleDir := hostAbsPath(filepath.Dir(terragruntOptions.TerragruntConfigPath))
+
+	command := []string{runtime, "run", "--rm", "-i", "-v", moduleDir + ":" + moduleDir}
+	if workingDir != moduleDir && !strings.HasPrefix(workingDir, moduleDir+string(filepath.Separator)) {
+		command = append(command, "-v", workingDir+":"+workingDir)
+	}
+	command = append(command, "-w", workingDir)
+	// Only the names of the variables are given, so that their values, which may be secrets, don't show in the command
+	// line. The runtime reads them from its own environment.
+	for _, name := range containerEnvVars(terragruntOptions.Env, hook.Env) {
+		command = append(command, "-e", name)
+	}
+	command = append(command, *hook.Image)
+	return append(command, hook.Execute...)
+}
+
+// containerEnvVars returns the names of the given environment variables that are passed on to the hooks running in a
+// container, along with all the variables of the env attribute of the hook, sorted.
+func containerEnvVars(env map[string]string, hookEnv *map[string]string) []string {
+	names := envNames(hookEnv)
+	for name := range env {
+		if util.ListContainsElement(names, name) {
+			continue
+		}
+		if util.ListContainsElement(containerEnvNames, name) || slices.ContainsFunc(containerEnvPrefixes, func(prefix string) bool { return strings.HasPrefix(name, prefix) }) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// hostAbsPath returns the absolute version of the given path, or the path as is if it can't be made absolute.
+func hostAbsPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// validateHookContainer checks the container attributes of the hook. The tflint hook runs in process, so it can't run
+// in a container.
+func validateHookContainer(hook Hook) error {
+	if !hook.IsContainerized() {
+		if hook.ContainerRuntime != nil {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'container_runtime' can only be set along with 'image'.", hook.Name))
+		}
+		return nil
+	}
+	if hook.IsHTTP() {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. 'image' can only be set when 'type' is %q.", hook.Name, HookTypeCommand))
+	}
+	if *hook.Image == "" {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'image' can't be empty.", hook.Name))
+	}
+	if hook.Execute[0] == "tflint" {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. The tflint hook can't run in a container.", hook.Name))
+	}
+	if hook.ContainerRuntime != nil && *hook.ContainerRuntime != ContainerRuntimeDocker && *hook.ContainerRuntime != ContainerRuntimePodman {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'container_runtime' must be one of %q or %q, got %q.", hook.Name, ContainerRuntimeDocker, ContainerRuntimePodman, *hook.ContainerRuntime))
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookExecuteCommand(t *testing.T) {
+	t.Parallel()
+
+	moduleDir := t.TempDir()
+	cacheDir := filepath.Join(moduleDir, ".terragrunt-cache", "abc")
+	otherDir := t.TempDir()
+	env := map[string]string{"TF_VAR_region": "us-east-1", "AWS_SESSION_TOKEN": "secret", "HOME": "/root"}
+	image := "ghcr.io/acme/tools:1.2"
+	podman := ContainerRuntimePodman
+
+	testCases := []struct {
+		name       string
+		hook       Hook
+		workingDir string
+		expected   []string
+	}{
+		{
+			"no image",
+			Hook{Execute: []string{"tfsec", "."}},
+			"",
+			[]string{"tfsec", "."},
+		},
+		{
+			"image",
+			Hook{Execute: []string{"tfsec", "."}, Image: &image},
+			"",
+			[]string{"docker", "run", "--rm", "-i", "-v", moduleDir + ":" + moduleDir, "-w", cacheDir, "-e", "AWS_SESSION_TOKEN", "-e", "TF_VAR_region", image, "tfsec", "."},
+		},
+		{
+			"env",
+			Hook{Execute: []string{"tfsec", "."}, Image: &image, Env: &map[string]string{"STATE_KEY": "unit/terraform.tfstate", "HOME": "/home/tools"}},
+			"",
+			[]string{"docker", "run", "--rm", "-i", "-v", moduleDir + ":" + moduleDir, "-w", cacheDir, "-e", "AWS_SESSION_TOKEN", "-e", "HOME", "-e", "STATE_KEY", "-e", "TF_VAR_region", image, "tfsec", "."},
+		},
+		{
+			"podman outside of the module",
+			Hook{Execute: []string{"tfsec", "."}, Image: &image, ContainerRuntime: &podman},
+			otherDir,
+			[]string{"podman", "run", "--rm", "-i", "-v", moduleDir + ":" + moduleDir, "-v", otherDir + ":" + otherDir, "-w", otherDir, "-e", "AWS_SESSION_TOKEN", "-e", "TF_VAR_region", image, "tfsec", "."},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := terragruntOptionsForTestWithEnv(t, filepath.Join(moduleDir, DefaultTerragruntConfigPath), env)
+			opts.WorkingDir = cacheDir
+			assert.Equal(t, testCase.expected, testCase.hook.ExecuteCommand(opts, testCase.workingDir))
+		})
+	}
+}
+
+func TestParseTerragruntConfigHookContainer(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		hook          string
+		expectedError string
+	}{
+		{"image", `execute = ["tfsec", "."]` + "\n" + `image = "ghcr.io/acme/tools:1.2"` + "\n" + `container_runtime = "podman"`, ""},
+		{"container_runtime without image", `execute = ["tfsec", "."]` + "\n" + `container_runtime = "podman"`, "'container_runtime' can only be set along with 'image'."},
+		{"invalid container_runtime", `execute = ["tfsec", "."]` + "\n" + `image = "ghcr.io/acme/tools:1.2"` + "\n" + `container_runtime = "containerd"`, `The value of 'container_runtime' must be one of "docker" or "podman", got "containerd".`},
+		{"empty image", `execute = ["tfsec", "."]` + "\n" + `image = ""`, "The value of 'image' can't be empty."},
+		{"tflint", `execute = ["tflint"]` + "\n" + `image = "ghcr.io/acme/tools:1.2"`, "The tflint hook can't run in a container."},
+		{"http", `type = "http"` + "\n" + `url = "https://example.com"` + "\n" + `image = "ghcr.io/acme/tools:1.2"`, `'image' can only be set when 'type' is "command".`},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := `
+terraform {
+  before_hook "scan" {
+    commands = ["apply"]
+    ` + testCase.hook + `
+  }
+}
+`
+			_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
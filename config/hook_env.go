@@ -0,0 +1,56 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// CommandOptions returns the options to run the commands of the hook with: the given options, with the variables of
+// the env attribute of the hook added to their environment.
+func (hook Hook) CommandOptions(terragruntOptions *options.TerragruntOptions) *options.TerragruntOptions {
+	return withHookEnv(terragruntOptions, hook.Env)
+}
+
+// CommandOptions returns the options to run the command of the error hook with, as for the other hooks.
+func (hook ErrorHook) CommandOptions(terragruntOptions *options.TerragruntOptions) *options.TerragruntOptions {
+	return withHookEnv(terragruntOptions, hook.Env)
+}
+
+// withHookEnv returns a copy of the given options with the given environment variables added to their environment, or
+// the options as is if there are none, as the options are shared with the rest of the run of the module.
+func withHookEnv(terragruntOptions *options.TerragruntOptions, env *map[string]string) *options.TerragruntOptions {
+	if env == nil || len(*env) == 0 {
+		return terragruntOptions
+	}
+	hookOptions := terragruntOptions.Clone(terragruntOptions.TerragruntConfigPath)
+	hookOptions.WorkingDir = terragruntOptions.WorkingDir
+	maps.Copy(hookOptions.Env, *env)
+	return hookOptions
+}
+
+// envNames returns the sorted names of the environment variables of the env attribute of a hook.
+func envNames(env *map[string]string) []string {
+	if env == nil {
+		return nil
+	}
+	names := []string{}
+	for name := range *env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validateHookEnv checks the names of the environment variables of the env attribute of the hook with the given name.
+func validateHookEnv(hookName string, env *map[string]string) error {
+	for _, name := range envNames(env) {
+		if name == "" || strings.ContainsAny(name, "=\x00") {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The names of the variables of 'env' must be non-empty and can't contain '=', got %q.", hookName, name))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookEnv(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  state_key = "${basename(get_terragrunt_dir())}/terraform.tfstate"
+}
+
+terraform {
+  before_hook "backup_state" {
+    commands    = ["apply"]
+    execute     = ["sh", "-c", "echo $STATE_KEY"]
+    working_dir = get_terragrunt_dir()
+    env = {
+      STATE_KEY = local.state_key
+    }
+  }
+}
+`
+	configPath := filepath.Join(t.TempDir(), "unit", DefaultTerragruntConfigPath)
+	opts := terragruntOptionsForTest(t, configPath)
+	terragruntConfig, err := ParseConfigString(config, opts, nil, configPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	hook := terragruntConfig.Terraform.BeforeHooks[0]
+	assert.Equal(t, &map[string]string{"STATE_KEY": "unit/terraform.tfstate"}, hook.Env)
+
+	hookOptions := hook.CommandOptions(opts)
+	assert.Equal(t, "unit/terraform.tfstate", hookOptions.Env["STATE_KEY"])
+	assert.NotContains(t, opts.Env, "STATE_KEY")
+
+	out, err := RunHookCommandWithOutput(hookOptions, hook.Name, hook.HookTimeout(), t.TempDir(), true, hook.Execute)
+	require.NoError(t, err)
+	assert.Equal(t, "unit/terraform.tfstate\n", out.Stdout)
+}
+
+func TestHookEnvWithoutEnv(t *testing.T) {
+	t.Parallel()
+
+	opts := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	assert.Same(t, opts, Hook{Name: "fmt"}.CommandOptions(opts))
+}
+
+func TestParseTerragruntConfigInvalidHookEnv(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terraform {
+  error_hook "unlock" {
+    commands  = ["apply"]
+    execute   = ["./unlock.sh"]
+    on_errors = [".*"]
+    env = {
+      "STATE=KEY" = "unit/terraform.tfstate"
+    }
+  }
+}
+`
+	_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `The names of the variables of 'env' must be non-empty and can't contain '=', got "STATE=KEY".`)
+}
@@ -0,0 +1,289 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+	"github.com/zclconf/go-cty/cty/function"
+	"github.com/zclconf/go-cty/cty/function/stdlib"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+const (
+	// HookTypeCommand is the type of the hooks that run the command in execute. This is the default.
+	HookTypeCommand = "command"
+	// HookTypeHTTP is the type of the hooks that POST a JSON payload describing the run of the module to a URL.
+	HookTypeHTTP = "http"
+	// HookTypeSlack is the type of the hooks that post a message about the run of the module to a Slack webhook.
+	HookTypeSlack = "slack"
+	// HookTypeMSTeams is the type of the hooks that post a message about the run of the module to a Microsoft Teams
+	// webhook.
+	HookTypeMSTeams = "msteams"
+)
+
+// The name of the variable that exposes the event to the payload and message templates of the hooks.
+const hookPayloadEventVariable = "event"
+
+// How long terragrunt waits for the response to the request of an http hook without a timeout.
+const hookHTTPTimeout = 30 * time.Second
+
+// The statuses of the run of the module reported to the http hooks.
+const (
+	HookStatusSuccess = "success"
+	HookStatusFailure = "failure"
+	HookStatusSkipped = "skipped"
+)
+
+// HookEvent describes the run of a module to the http hooks. The duration is in seconds since the module started to
+// run, and the plan is only set once a plan of the module found its change summary.
+type HookEvent struct {
+	Hook     string         `json:"hook"`
+	Path     string         `json:"path"`
+	Command  string         `json:"command"`
+	Status   string         `json:"status"`
+	Duration float64        `json:"duration"`
+	Plan     *HookEventPlan `json:"plan"`
+}
+
+// HookEventPlan holds the number of resources the plan of the module adds, changes and destroys.
+type HookEventPlan struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+}
+
+// hookType returns the value of the type attribute of a hook, which defaults to command.
+func hookType(hook Hook) string {
+	if hook.Type == nil {
+		return HookTypeCommand
+	}
+	return *hook.Type
+}
+
+// IsHTTP returns true if the hook sends an HTTP request instead of running a command, which is the case of the http,
+// slack and msteams hooks.
+func (hook Hook) IsHTTP() bool {
+	return hookType(hook) != HookTypeCommand
+}
+
+// validateHookType checks that the attributes of the hook match its type.
+func validateHookType(hook Hook) error {
+	switch hookType(hook) {
+	case HookTypeCommand:
+		if hook.URL != nil || hook.URLFromEnv != nil || len(hook.URLFromCommand) > 0 || hook.Headers != nil || hook.Payload != nil || hook.Message != nil || hook.Channels != nil {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'url', 'url_from_env', 'url_from_command', 'headers', 'payload', 'message' and 'channels' can't be set when 'type' is %q.", hook.Name, HookTypeCommand))
+		}
+		if len(hook.Execute) < 1 || hook.Execute[0] == "" {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. Need at least one non-empty argument in 'execute'.", hook.Name))
+		}
+	case HookTypeHTTP, HookTypeSlack, HookTypeMSTeams:
+		if err := validateHookURL(hook); err != nil {
+			return err
+		}
+		if len(hook.Execute) > 0 || hook.IsBackground() {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'execute' and 'run_in_background' can't be set when 'type' is %q.", hook.Name, hookType(hook)))
+		}
+		if hookType(hook) != HookTypeHTTP {
+			return validateChatHook(hook)
+		}
+		if hook.Message != nil || hook.Channels != nil {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'message' and 'channels' can only be set when 'type' is %q or %q.", hook.Name, HookTypeSlack, HookTypeMSTeams))
+		}
+	default:
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'type' must be one of %q, %q, %q or %q, got %q.", hook.Name, HookTypeCommand, HookTypeHTTP, HookTypeSlack, HookTypeMSTeams, *hook.Type))
+	}
+	return nil
+}
+
+// validateHookURL checks that exactly one of the attributes the URL of the request of a hook is read from is set.
+func validateHookURL(hook Hook) error {
+	sources := 0
+	if hook.URL != nil && *hook.URL != "" {
+		sources++
+	}
+	if hook.URLFromEnv != nil && *hook.URLFromEnv != "" {
+		sources++
+	}
+	if len(hook.URLFromCommand) > 0 && hook.URLFromCommand[0] != "" {
+		sources++
+	}
+
+	switch sources {
+	case 0:
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. One of 'url', 'url_from_env' or 'url_from_command' is required when 'type' is %q.", hook.Name, hookType(hook)))
+	case 1:
+		return nil
+	default:
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. Only one of 'url', 'url_from_env' and 'url_from_command' can be set.", hook.Name))
+	}
+}
+
+// SendHTTPRequest POSTs the payload of the hook, rendered for the given event, to the URL of the hook, with the
+// headers of the hook. The payload of a slack or msteams hook is the message of the hook, in the format of the webhooks
+// of the chat. A response with a status code other than 2xx is an error.
+func (hook Hook) SendHTTPRequest(terragruntOptions *options.TerragruntOptions, event HookEvent) error {
+	if !hook.notifiesStatus(event.Status) {
+		terragruntOptions.Logger.Debugf("Skipping hook %s, as none of its channels is set for status %s", hook.Name, event.Status)
+		return nil
+	}
+
+	payload, err := hook.requestPayload(event)
+	if err != nil {
+		return err
+	}
+
+	hookURL, err := hook.requestURL(terragruntOptions)
+	if err != nil {
+		return err
+	}
+	request, err := http.NewRequest(http.MethodPost, hookURL, bytes.NewReader(payload))
+	if err != nil {
+		return errors.WithStackTrace(HookHTTPRequestFailed{Name: hook.Name, Reason: "the URL is invalid"})
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if hook.Headers != nil {
+		for name, value := range *hook.Headers {
+			request.Header.Set(name, value)
+		}
+	}
+
+	terragruntOptions.Logger.Debugf("Sending the request of hook %s", hook.Name)
+	client := &http.Client{Timeout: hookHTTPTimeout}
+	settings := hook.HookTimeout()
+	if settings.Duration() > 0 {
+		client.Timeout = settings.Duration()
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		// The URL of a webhook often embeds a secret, so it is left out of the error.
+		if urlErr, ok := err.(*url.Error); ok {
+			if urlErr.Timeout() && settings.Duration() > 0 {
+				return settings.timeoutExceeded(terragruntOptions, hook.Name)
+			}
+			err = urlErr.Err
+		}
+		return errors.WithStackTrace(HookHTTPRequestFailed{Name: hook.Name, Reason: err.Error()})
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < http.StatusOK || response.StatusCode >= http.StatusMultipleChoices {
+		return errors.WithStackTrace(HookHTTPRequestFailed{Name: hook.Name, Reason: fmt.Sprintf("the response has status code %d", response.StatusCode)})
+	}
+	return nil
+}
+
+// requestURL returns the URL the request of the hook is sent to, which is either the url attribute, the value of the
+// environment variable named in url_from_env, or the output of the credential helper command in url_from_command.
+func (hook Hook) requestURL(terragruntOptions *options.TerragruntOptions) (string, error) {
+	switch {
+	case hook.URLFromEnv != nil && *hook.URLFromEnv != "":
+		hookURL := strings.TrimSpace(terragruntOptions.Env[*hook.URLFromEnv])
+		if hookURL == "" {
+			return "", errors.WithStackTrace(HookHTTPRequestFailed{Name: hook.Name, Reason: fmt.Sprintf("the environment variable %s of 'url_from_env' is not set", *hook.URLFromEnv)})
+		}
+		return hookURL, nil
+	case len(hook.URLFromCommand) > 0:
+		workingDir := ""
+		if hook.WorkingDir != nil {
+			workingDir = *hook.WorkingDir
+		}
+		// The output is suppressed, as it is a secret.
+		out, err := shell.RunShellCommandWithOutput(hook.CommandOptions(terragruntOptions), workingDir, true, false, hook.URLFromCommand[0], hook.URLFromCommand[1:]...)
+		if err != nil {
+			return "", errors.WithStackTrace(HookHTTPRequestFailed{Name: hook.Name, Reason: fmt.Sprintf("the command of 'url_from_command' failed: %v", err)})
+		}
+		hookURL := strings.TrimSpace(out.Stdout)
+		if hookURL == "" {
+			return "", errors.WithStackTrace(HookHTTPRequestFailed{Name: hook.Name, Reason: "the command of 'url_from_command' printed no URL"})
+		}
+		return hookURL, nil
+	default:
+		return *hook.URL, nil
+	}
+}
+
+// requestPayload returns the body of the request of the hook for the given event, depending on its type.
+func (hook Hook) requestPayload(event HookEvent) ([]byte, error) {
+	switch hookType(hook) {
+	case HookTypeSlack:
+		return hook.slackPayload(event)
+	case HookTypeMSTeams:
+		return hook.msTeamsPayload(event)
+	default:
+		return hook.renderPayload(event)
+	}
+}
+
+// renderPayload returns the payload of the hook for the given event. Without a payload attribute, the payload is the
+// event as JSON. Otherwise, the payload is rendered as an HCL template with the event exposed as `event`, as with the
+// templates of generate blocks, e.g. "{\"text\": \"$${event.path} finished with $${event.status}\"}".
+func (hook Hook) renderPayload(event HookEvent) ([]byte, error) {
+	if hook.Payload == nil {
+		payload, err := json.Marshal(event)
+		return payload, errors.WithStackTrace(err)
+	}
+
+	value, err := renderHookTemplate(*hook.Payload, fmt.Sprintf("<payload of hook %q>", hook.Name), event)
+	if err != nil {
+		return nil, err
+	}
+	value, err = convert.Convert(value, cty.String)
+	if err != nil || value.IsNull() || !value.IsKnown() {
+		return nil, errors.WithStackTrace(InvalidHookPayload{Name: hook.Name})
+	}
+	return []byte(value.AsString()), nil
+}
+
+// renderHookTemplate evaluates the given HCL template of a hook, with the given event exposed as `event`.
+func renderHookTemplate(template string, filename string, event HookEvent) (cty.Value, error) {
+	expr, diags := hclsyntax.ParseTemplate([]byte(template), filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.WithStackTrace(diags)
+	}
+
+	eventValue, err := convertToCtyWithJson(event)
+	if err != nil {
+		return cty.NilVal, err
+	}
+	evalContext := &hcl.EvalContext{
+		Variables: map[string]cty.Value{hookPayloadEventVariable: eventValue},
+		Functions: map[string]function.Function{"jsonencode": stdlib.JSONEncodeFunc},
+	}
+
+	value, diags := expr.Value(evalContext)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.WithStackTrace(diags)
+	}
+	return value, nil
+}
+
+// Custom error types
+
+type HookHTTPRequestFailed struct {
+	Name   string
+	Reason string
+}
+
+func (err HookHTTPRequestFailed) Error() string {
+	return fmt.Sprintf("The request of hook %s failed: %s.", err.Name, err.Reason)
+}
+
+type InvalidHookPayload struct {
+	Name string
+}
+
+func (err InvalidHookPayload) Error() string {
+	return fmt.Sprintf("The payload of hook %s must be a template resulting in a string.", err.Name)
+}
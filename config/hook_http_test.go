@@ -0,0 +1,97 @@
+package config
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookSendHTTPRequest(t *testing.T) {
+	t.Parallel()
+
+	var body []byte
+	var authorization, contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		authorization = r.Header.Get("Authorization")
+		contentType = r.Header.Get("Content-Type")
+	}))
+	defer server.Close()
+
+	hookType := HookTypeHTTP
+	headers := map[string]string{"Authorization": "Bearer token"}
+	hook := Hook{Name: "notify", Type: &hookType, URL: &server.URL, Headers: &headers}
+	event := HookEvent{Hook: "notify", Path: "/live/vpc", Command: "plan", Status: HookStatusSuccess, Duration: 1.5, Plan: &HookEventPlan{Add: 1, Destroy: 2}}
+
+	require.NoError(t, hook.SendHTTPRequest(mockOptionsForTest(t), event))
+	assert.JSONEq(t, `{"hook": "notify", "path": "/live/vpc", "command": "plan", "status": "success", "duration": 1.5, "plan": {"add": 1, "change": 0, "destroy": 2}}`, string(body))
+	assert.Equal(t, "Bearer token", authorization)
+	assert.Equal(t, "application/json", contentType)
+
+	payload := `{"text": ${jsonencode("${event.path} ${event.status}: ${event.plan.add} to add")}}`
+	hook.Payload = &payload
+	require.NoError(t, hook.SendHTTPRequest(mockOptionsForTest(t), event))
+	assert.JSONEq(t, `{"text": "/live/vpc success: 1 to add"}`, string(body))
+}
+
+func TestHookSendHTTPRequestFailure(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	hookType := HookTypeHTTP
+	hook := Hook{Name: "notify", Type: &hookType, URL: &server.URL}
+
+	err := hook.SendHTTPRequest(mockOptionsForTest(t), HookEvent{Status: HookStatusFailure})
+	require.Error(t, err)
+	assert.Equal(t, HookHTTPRequestFailed{Name: "notify", Reason: "the response has status code 403"}, errors.Unwrap(err))
+}
+
+func TestParseTerragruntConfigHTTPHooks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		hook          string
+		expectedError string
+	}{
+		{"http", `type = "http"` + "\n" + `url = "https://hooks.example.com"`, ""},
+		{"http without url", `type = "http"`, `One of 'url', 'url_from_env' or 'url_from_command' is required when 'type' is "http".`},
+		{"http with execute", `type = "http"` + "\n" + `url = "https://hooks.example.com"` + "\n" + `execute = ["echo"]`, `'execute' and 'run_in_background' can't be set when 'type' is "http".`},
+		{"command with url", `execute = ["echo"]` + "\n" + `url = "https://hooks.example.com"`, `'url', 'url_from_env', 'url_from_command', 'headers', 'payload', 'message' and 'channels' can't be set when 'type' is "command".`},
+		{"command without execute", ``, `Need at least one non-empty argument in 'execute'.`},
+		{"unknown type", `type = "grpc"`, `The value of 'type' must be one of "command", "http", "slack" or "msteams", got "grpc".`},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := `
+terraform {
+  on_failure_hook "notify" {
+    commands = ["apply"]
+    ` + testCase.hook + `
+  }
+}
+`
+			terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, terragruntConfig.Terraform.OnFailureHooks[0].IsHTTP())
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package config
+
+import (
+	"fmt"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The name of the variable that exposes the values exported by the hooks of the module to its config.
+const hookOutputVariable = "hook_output"
+
+// hookOutputs holds the values exported by the hooks of the modules being run, by the path of their config.
+var hookOutputs sync.Map
+
+// GetExportHooks returns the before hooks with export that run for the given command, in order.
+func (conf *TerraformConfig) GetExportHooks(command string) []Hook {
+	hooks := []Hook{}
+	for _, hook := range conf.GetBeforeHooks() {
+		if hook.Export != nil && util.ListContainsElement(hook.Commands, command) {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks
+}
+
+// SetHookOutput records the value the given hook of the given config exports from its stdout, for the rest of the run
+// of the module of the given options. The values of the other hooks of the config with export are unknown until they
+// run.
+func SetHookOutput(terragruntOptions *options.TerragruntOptions, terragruntConfig *TerragruntConfig, hook Hook, stdout string) error {
+	value, err := hook.exportedValue(stdout)
+	if err != nil {
+		return err
+	}
+
+	outputs := map[string]cty.Value{}
+	for _, exportHook := range terragruntConfig.Terraform.GetBeforeHooks() {
+		if exportHook.Export != nil {
+			outputs[*exportHook.Export] = cty.DynamicVal
+		}
+	}
+	if rawOutputs, found := hookOutputs.Load(terragruntOptions.TerragruntConfigPath); found {
+		maps.Copy(outputs, rawOutputs.(map[string]cty.Value))
+	}
+	outputs[*hook.Export] = value
+	hookOutputs.Store(terragruntOptions.TerragruntConfigPath, outputs)
+	return nil
+}
+
+// ClearHookOutputs forgets the values exported by the hooks of the module of the given options, once its run is over.
+func ClearHookOutputs(terragruntOptions *options.TerragruntOptions) {
+	hookOutputs.Delete(terragruntOptions.TerragruntConfigPath)
+}
+
+// hookOutputsAsCty returns the value of the hook_output variable for the module of the given options. It is unknown as
+// a whole until a hook of the module exported a value, as the names of the values are only known once the hooks are
+// read.
+func hookOutputsAsCty(terragruntOptions *options.TerragruntOptions) cty.Value {
+	rawOutputs, found := hookOutputs.Load(terragruntOptions.TerragruntConfigPath)
+	if !found {
+		return cty.DynamicVal
+	}
+	return cty.ObjectVal(rawOutputs.(map[string]cty.Value))
+}
+
+// hasPendingHookOutputs returns true if some of the values exported by the hooks of the module of the given options
+// are not known yet.
+func hasPendingHookOutputs(terragruntOptions *options.TerragruntOptions) bool {
+	return !hookOutputsAsCty(terragruntOptions).IsWhollyKnown()
+}
+
+// exportedValue returns the value the hook exports from the given stdout: the stdout without its trailing newline, or
+// the stdout decoded from JSON if export_json is true.
+func (hook Hook) exportedValue(stdout string) (cty.Value, error) {
+	if hook.ExportJSON == nil || !*hook.ExportJSON {
+		return cty.StringVal(strings.TrimRight(stdout, "\r\n")), nil
+	}
+
+	value, err := decodeJson([]byte(stdout))
+	if err != nil {
+		return cty.NilVal, errors.WithStackTrace(InvalidHookOutput{Name: hook.Name, Err: err})
+	}
+	return value, nil
+}
+
+// validateHookExport checks the export attributes of the hook. Only the before hooks, which run before the rest of the
+// config is used, can export a value.
+func validateHookExport(hook Hook, isBeforeHook bool) error {
+	if hook.Export == nil {
+		if hook.ExportJSON != nil {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'export_json' can only be set along with 'export'.", hook.Name))
+		}
+		return nil
+	}
+	if !isBeforeHook {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. 'export' can only be set on before_hook blocks.", hook.Name))
+	}
+	if hook.IsHTTP() || hook.IsBackground() {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. 'export' can't be set on a background hook, nor when 'type' is not %q.", hook.Name, HookTypeCommand))
+	}
+	if !hclsyntax.ValidIdentifier(*hook.Export) {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'export' must be a valid identifier, got %q.", hook.Name, *hook.Export))
+	}
+	return nil
+}
+
+// validateHookExportNames checks that no two of the given hooks export a value of the same name.
+func validateHookExportNames(hooks []Hook) error {
+	exporters := map[string]string{}
+	for _, hook := range hooks {
+		if hook.Export == nil {
+			continue
+		}
+		if exporter, found := exporters[*hook.Export]; found {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The hook %s already exports %q.", hook.Name, exporter, *hook.Export))
+		}
+		exporters[*hook.Export] = hook.Name
+	}
+	return nil
+}
+
+// hookOutputPlaceholderBody wraps the body of a config read before its hooks exported their values, so that the
+// attributes referencing the unknown values of hook_output decode to placeholders rather than failing: an unknown
+// string is decoded as an empty string, and any other unknown value as null. This lets the config be read to find the
+// hooks with export in the first place. The inputs, at the top level of the config, are left as is, as they are set to
+// null rather than to placeholders.
+type hookOutputPlaceholderBody struct {
+	hcl.Body
+	topLevel bool
+}
+
+func (body hookOutputPlaceholderBody) Content(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Diagnostics) {
+	content, diags := body.Body.Content(schema)
+	return body.wrapContent(content), diags
+}
+
+func (body hookOutputPlaceholderBody) PartialContent(schema *hcl.BodySchema) (*hcl.BodyContent, hcl.Body, hcl.Diagnostics) {
+	content, remain, diags := body.Body.PartialContent(schema)
+	return body.wrapContent(content), hookOutputPlaceholderBody{Body: remain, topLevel: body.topLevel}, diags
+}
+
+func (body hookOutputPlaceholderBody) JustAttributes() (hcl.Attributes, hcl.Diagnostics) {
+	attributes, diags := body.Body.JustAttributes()
+	return body.wrapAttributes(attributes), diags
+}
+
+func (body hookOutputPlaceholderBody) wrapContent(content *hcl.BodyContent) *hcl.BodyContent {
+	if content == nil {
+		return nil
+	}
+	wrapped := *content
+	wrapped.Attributes = body.wrapAttributes(content.Attributes)
+	wrapped.Blocks = hcl.Blocks{}
+	for _, block := range content.Blocks {
+		wrappedBlock := *block
+		wrappedBlock.Body = hookOutputPlaceholderBody{Body: block.Body}
+		wrapped.Blocks = append(wrapped.Blocks, &wrappedBlock)
+	}
+	return &wrapped
+}
+
+func (body hookOutputPlaceholderBody) wrapAttributes(attributes hcl.Attributes) hcl.Attributes {
+	if attributes == nil {
+		return nil
+	}
+	wrapped := hcl.Attributes{}
+	for name, attribute := range attributes {
+		if !(body.topLevel && name == MetadataInputs) && referencesHookOutput(attribute.Expr) {
+			wrappedAttribute := *attribute
+			wrappedAttribute.Expr = hookOutputPlaceholderExpr{Expression: attribute.Expr}
+			attribute = &wrappedAttribute
+		}
+		wrapped[name] = attribute
+	}
+	return wrapped
+}
+
+// referencesHookOutput returns true if the given expression references the hook_output variable.
+func referencesHookOutput(expr hcl.Expression) bool {
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() == hookOutputVariable {
+			return true
+		}
+	}
+	return false
+}
+
+// hookOutputPlaceholderExpr is an expression whose unknown values are replaced by placeholders, as described in
+// hookOutputPlaceholderBody.
+type hookOutputPlaceholderExpr struct {
+	hcl.Expression
+}
+
+func (expr hookOutputPlaceholderExpr) Value(ctx *hcl.EvalContext) (cty.Value, hcl.Diagnostics) {
+	value, diags := expr.Expression.Value(ctx)
+	if diags.HasErrors() || value.IsWhollyKnown() {
+		return value, diags
+	}
+
+	// The callback never returns an error, so neither does Transform.
+	placeholder, _ := cty.Transform(value, func(_ cty.Path, nested cty.Value) (cty.Value, error) {
+		if nested.IsKnown() {
+			return nested, nil
+		}
+		if nested.Type() == cty.String || nested.Type() == cty.DynamicPseudoType {
+			return cty.StringVal(""), nil
+		}
+		return cty.NullVal(nested.Type()), nil
+	})
+	return placeholder, diags
+}
+
+// Custom error types
+
+type InvalidHookOutput struct {
+	Name string
+	Err  error
+}
+
+func (err InvalidHookOutput) Error() string {
+	return fmt.Sprintf("The output of hook %s, which has export_json, is not valid JSON: %v", err.Name, err.Err)
+}
+
+func (err InvalidHookOutput) Unwrap() error {
+	return err.Err
+}
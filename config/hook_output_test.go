@@ -0,0 +1,140 @@
+package config
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hookOutputTestConfig = `
+terraform {
+  before_hook "ami" {
+    commands = ["apply"]
+    execute  = ["./latest-ami.sh"]
+    export   = "ami"
+  }
+
+  before_hook "network" {
+    commands    = ["apply"]
+    execute     = ["./network.sh", hook_output.ami]
+    export      = "network"
+    export_json = true
+  }
+
+  after_hook "tag" {
+    commands = ["apply"]
+    execute  = ["./tag.sh", hook_output.ami, hook_output.network.subnet]
+  }
+}
+
+inputs = {
+  ami    = hook_output.ami
+  subnet = hook_output.network.subnet
+}
+`
+
+func TestHookOutput(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), DefaultTerragruntConfigPath)
+	opts := terragruntOptionsForTest(t, configPath)
+	defer ClearHookOutputs(opts)
+
+	// Before the hooks ran, the inputs referencing their outputs are null, and the hooks get placeholders.
+	terragruntConfig, err := ParseConfigString(hookOutputTestConfig, opts, nil, configPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ami": nil, "subnet": nil}, terragruntConfig.Inputs)
+	assert.Equal(t, []string{"./network.sh", ""}, terragruntConfig.Terraform.BeforeHooks[1].Execute)
+	assert.Equal(t, []string{"ami", "network"}, hookNames(terragruntConfig.Terraform.GetExportHooks("apply")))
+	assert.Empty(t, terragruntConfig.Terraform.GetExportHooks("plan"))
+
+	require.NoError(t, SetHookOutput(opts, terragruntConfig, terragruntConfig.Terraform.BeforeHooks[0], "ami-123\n"))
+	terragruntConfig, err = ParseConfigString(hookOutputTestConfig, opts, nil, configPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ami": "ami-123", "subnet": nil}, terragruntConfig.Inputs)
+	assert.Equal(t, []string{"./network.sh", "ami-123"}, terragruntConfig.Terraform.BeforeHooks[1].Execute)
+
+	require.NoError(t, SetHookOutput(opts, terragruntConfig, terragruntConfig.Terraform.BeforeHooks[1], `{"subnet": "subnet-456"}`))
+	terragruntConfig, err = ParseConfigString(hookOutputTestConfig, opts, nil, configPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"ami": "ami-123", "subnet": "subnet-456"}, terragruntConfig.Inputs)
+	assert.Equal(t, []string{"./tag.sh", "ami-123", "subnet-456"}, terragruntConfig.Terraform.AfterHooks[0].Execute)
+}
+
+func TestHookOutputInvalidJSON(t *testing.T) {
+	t.Parallel()
+
+	export, exportJSON := "network", true
+	hook := Hook{Name: "network", Export: &export, ExportJSON: &exportJSON}
+	configPath := filepath.Join(t.TempDir(), DefaultTerragruntConfigPath)
+
+	err := SetHookOutput(terragruntOptionsForTest(t, configPath), &TerragruntConfig{}, hook, "subnet-456")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "The output of hook network, which has export_json, is not valid JSON")
+}
+
+func TestParseTerragruntConfigInvalidHookExport(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			"after hook",
+			`after_hook "ami" {
+    commands = ["apply"]
+    execute  = ["./latest-ami.sh"]
+    export   = "ami"
+  }`,
+			"'export' can only be set on before_hook blocks",
+		},
+		{
+			"invalid name",
+			`before_hook "ami" {
+    commands = ["apply"]
+    execute  = ["./latest-ami.sh"]
+    export   = "ami id"
+  }`,
+			`must be a valid identifier, got "ami id"`,
+		},
+		{
+			"export_json without export",
+			`before_hook "ami" {
+    commands    = ["apply"]
+    execute     = ["./latest-ami.sh"]
+    export_json = true
+  }`,
+			"'export_json' can only be set along with 'export'",
+		},
+		{
+			"duplicated name",
+			`before_hook "ami" {
+    commands = ["apply"]
+    execute  = ["./latest-ami.sh"]
+    export   = "ami"
+  }
+  before_hook "other-ami" {
+    commands = ["apply"]
+    execute  = ["./other-ami.sh"]
+    export   = "ami"
+  }`,
+			`The hook ami already exports "ami"`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := "terraform {\n  " + testCase.config + "\n}\n"
+			_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expected)
+		})
+	}
+}
@@ -0,0 +1,126 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// The actions of the resource changes of a plan that the if_plan blocks of the hooks match. A replacement is both a
+// deletion and a creation, so it is matched by the three of delete, create and replace.
+const (
+	PlanActionCreate  = "create"
+	PlanActionUpdate  = "update"
+	PlanActionDelete  = "delete"
+	PlanActionReplace = "replace"
+)
+
+var planActions = []string{PlanActionCreate, PlanActionUpdate, PlanActionDelete, PlanActionReplace}
+
+// The only command whose hooks can have an if_plan block, as the condition is evaluated against the plan it writes.
+const planConditionCommand = "plan"
+
+// HookPlanCondition is the if_plan block of a hook, which only runs the hook after a plan that has a resource change
+// matching all the set attributes:
+//
+//	if_plan {
+//	  actions        = ["delete"]
+//	  resource_types = ["aws_db_instance"]
+//	}
+//
+// Without actions, any change other than a no-op or a read matches, and without resource_types, a change to a
+// resource of any type does.
+type HookPlanCondition struct {
+	Actions       []string `hcl:"actions,optional" cty:"actions"`
+	ResourceTypes []string `hcl:"resource_types,optional" cty:"resource_types"`
+}
+
+// PlanResourceChange is a resource change of a plan, read from the resource_changes of `terraform show -json`. The
+// actions are the ones of the plan, e.g. ["delete", "create"] for a replacement.
+type PlanResourceChange struct {
+	Address string
+	Type    string
+	Actions []string
+}
+
+// ParsePlanResourceChanges returns the resource changes of the given plan, in the format of `terraform show -json`.
+func ParsePlanResourceChanges(planJSON []byte) ([]PlanResourceChange, error) {
+	var plan struct {
+		ResourceChanges []struct {
+			Address string `json:"address"`
+			Type    string `json:"type"`
+			Change  struct {
+				Actions []string `json:"actions"`
+			} `json:"change"`
+		} `json:"resource_changes"`
+	}
+	if err := json.Unmarshal(planJSON, &plan); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	changes := []PlanResourceChange{}
+	for _, resourceChange := range plan.ResourceChanges {
+		changes = append(changes, PlanResourceChange{Address: resourceChange.Address, Type: resourceChange.Type, Actions: resourceChange.Change.Actions})
+	}
+	return changes, nil
+}
+
+// Matches returns true if any of the given resource changes matches the condition.
+func (condition *HookPlanCondition) Matches(changes []PlanResourceChange) bool {
+	return slices.ContainsFunc(changes, condition.matchesChange)
+}
+
+func (condition *HookPlanCondition) matchesChange(change PlanResourceChange) bool {
+	if len(condition.ResourceTypes) > 0 && !slices.Contains(condition.ResourceTypes, change.Type) {
+		return false
+	}
+
+	actions := []string{}
+	for _, action := range change.Actions {
+		if action == PlanActionCreate || action == PlanActionUpdate || action == PlanActionDelete {
+			actions = append(actions, action)
+		}
+	}
+	if slices.Contains(actions, PlanActionCreate) && slices.Contains(actions, PlanActionDelete) {
+		actions = append(actions, PlanActionReplace)
+	}
+
+	if len(condition.Actions) == 0 {
+		return len(actions) > 0
+	}
+	return slices.ContainsFunc(condition.Actions, func(action string) bool { return slices.Contains(actions, action) })
+}
+
+// HasPlanConditionHooks returns true if any of the hooks that run after the given command has an if_plan block, in
+// which case the plan must be written to a file for them.
+func (conf *TerraformConfig) HasPlanConditionHooks(command string) bool {
+	hooks := append([]Hook{}, conf.GetAfterHooks()...)
+	hooks = append(hooks, conf.GetOnSuccessHooks()...)
+	hooks = append(hooks, conf.GetOnFailureHooks()...)
+	return slices.ContainsFunc(hooks, func(hook Hook) bool {
+		return hook.IfPlan != nil && slices.Contains(hook.Commands, command)
+	})
+}
+
+// validateHookPlanCondition checks the if_plan block of the hook, if any. Only the hooks that run once the plan is
+// written, which are the after, on_success and on_failure hooks, can have one.
+func validateHookPlanCondition(hook Hook, runsAfterCommand bool) error {
+	if hook.IfPlan == nil {
+		return nil
+	}
+	if !runsAfterCommand {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. 'if_plan' can only be set on after_hook, on_success_hook and on_failure_hook blocks.", hook.Name))
+	}
+	if len(hook.Commands) != 1 || hook.Commands[0] != planConditionCommand {
+		return InvalidArgError(fmt.Sprintf("Error with hook %s. A hook with 'if_plan' must only run after %q, as its condition is evaluated against the plan.", hook.Name, planConditionCommand))
+	}
+	for _, action := range hook.IfPlan.Actions {
+		if !slices.Contains(planActions, action) {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The values of 'actions' in 'if_plan' must be one of %s, got %q.", hook.Name, strings.Join(planActions, ", "), action))
+		}
+	}
+	return nil
+}
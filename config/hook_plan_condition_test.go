@@ -0,0 +1,140 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlanResourceChanges(t *testing.T) {
+	t.Parallel()
+
+	planJSON := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_db_instance.main", "type": "aws_db_instance", "change": {"actions": ["delete", "create"]}},
+    {"address": "module.logs.aws_s3_bucket.this", "type": "aws_s3_bucket", "change": {"actions": ["update"]}}
+  ]
+}`
+	changes, err := ParsePlanResourceChanges([]byte(planJSON))
+	require.NoError(t, err)
+	assert.Equal(t, []PlanResourceChange{
+		{Address: "aws_db_instance.main", Type: "aws_db_instance", Actions: []string{"delete", "create"}},
+		{Address: "module.logs.aws_s3_bucket.this", Type: "aws_s3_bucket", Actions: []string{"update"}},
+	}, changes)
+
+	_, err = ParsePlanResourceChanges([]byte("not json"))
+	assert.Error(t, err)
+}
+
+func TestHookPlanConditionMatches(t *testing.T) {
+	t.Parallel()
+
+	changes := []PlanResourceChange{
+		{Address: "aws_db_instance.main", Type: "aws_db_instance", Actions: []string{"create", "delete"}},
+		{Address: "aws_s3_bucket.logs", Type: "aws_s3_bucket", Actions: []string{"no-op"}},
+		{Address: "data.aws_iam_policy_document.this", Type: "aws_iam_policy_document", Actions: []string{"read"}},
+		{Address: "aws_iam_role.app", Type: "aws_iam_role", Actions: []string{"update"}},
+	}
+
+	testCases := []struct {
+		name      string
+		condition HookPlanCondition
+		changes   []PlanResourceChange
+		expected  bool
+	}{
+		{"any change", HookPlanCondition{}, changes, true},
+		{"no change", HookPlanCondition{}, changes[1:3], false},
+		{"replace", HookPlanCondition{Actions: []string{"replace"}}, changes, true},
+		{"delete matches replace", HookPlanCondition{Actions: []string{"delete"}}, changes, true},
+		{"delete", HookPlanCondition{Actions: []string{"delete"}}, changes[1:], false},
+		{"resource type", HookPlanCondition{ResourceTypes: []string{"aws_iam_role"}}, changes, true},
+		{"unchanged resource type", HookPlanCondition{ResourceTypes: []string{"aws_s3_bucket"}}, changes, false},
+		{"action of resource type", HookPlanCondition{Actions: []string{"delete"}, ResourceTypes: []string{"aws_iam_role"}}, changes, false},
+		{"empty plan", HookPlanCondition{}, nil, false},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.expected, testCase.condition.Matches(testCase.changes))
+		})
+	}
+}
+
+func TestParseTerragruntConfigHookPlanCondition(t *testing.T) {
+	t.Parallel()
+
+	config := `
+terraform {
+  after_hook "approve" {
+    commands = ["plan"]
+    execute  = ["./approve.sh"]
+
+    if_plan {
+      actions        = ["delete"]
+      resource_types = ["aws_db_instance"]
+    }
+  }
+}
+`
+	terragruntConfig, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	assert.Equal(t, &HookPlanCondition{Actions: []string{"delete"}, ResourceTypes: []string{"aws_db_instance"}}, terragruntConfig.Terraform.AfterHooks[0].IfPlan)
+	assert.True(t, terragruntConfig.Terraform.HasPlanConditionHooks("plan"))
+	assert.False(t, terragruntConfig.Terraform.HasPlanConditionHooks("apply"))
+}
+
+func TestParseTerragruntConfigInvalidHookPlanCondition(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		config   string
+		expected string
+	}{
+		{
+			"before hook",
+			`before_hook "approve" {
+    commands = ["plan"]
+    execute  = ["./approve.sh"]
+    if_plan {}
+  }`,
+			"'if_plan' can only be set on after_hook, on_success_hook and on_failure_hook blocks",
+		},
+		{
+			"other command",
+			`after_hook "approve" {
+    commands = ["plan", "apply"]
+    execute  = ["./approve.sh"]
+    if_plan {}
+  }`,
+			"must only run after \"plan\"",
+		},
+		{
+			"invalid action",
+			`on_success_hook "approve" {
+    commands = ["plan"]
+    execute  = ["./approve.sh"]
+    if_plan {
+      actions = ["destroy"]
+    }
+  }`,
+			"got \"destroy\"",
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := "terraform {\n  " + testCase.config + "\n}\n"
+			_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expected)
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package config
+
+import (
+	"slices"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// IsPropagated returns true if the hook applies to the units including its config, whatever their merge strategy.
+func (hook Hook) IsPropagated() bool {
+	return hook.Propagate != nil && *hook.Propagate
+}
+
+// IsPropagated returns true if the error hook applies to the units including its config, whatever their merge
+// strategy.
+func (hook ErrorHook) IsPropagated() bool {
+	return hook.Propagate != nil && *hook.Propagate
+}
+
+// propagatedHooks returns a terraform block holding the hooks of the terraform block with propagate.
+func (conf *TerraformConfig) propagatedHooks() *TerraformConfig {
+	isLocal := func(hook Hook) bool { return !hook.IsPropagated() }
+	return &TerraformConfig{
+		BeforeHooks:    slices.DeleteFunc(slices.Clone(conf.GetBeforeHooks()), isLocal),
+		AfterHooks:     slices.DeleteFunc(slices.Clone(conf.GetAfterHooks()), isLocal),
+		ErrorHooks:     slices.DeleteFunc(slices.Clone(conf.GetErrorHooks()), func(hook ErrorHook) bool { return !hook.IsPropagated() }),
+		OnSuccessHooks: slices.DeleteFunc(slices.Clone(conf.GetOnSuccessHooks()), isLocal),
+		OnFailureHooks: slices.DeleteFunc(slices.Clone(conf.GetOnFailureHooks()), isLocal),
+		OnSkipHooks:    slices.DeleteFunc(slices.Clone(conf.GetOnSkipHooks()), isLocal),
+	}
+}
+
+// mergePropagatedHooks returns a copy of the given config with the hooks with propagate of the given included config
+// added to its terraform block, for an include whose merge strategy is no_merge, which doesn't merge the rest of the
+// included config in. The propagated hooks come first, and the hooks of the config are merged over them as with the
+// other merge strategies, so that a unit can still override a propagated hook by name.
+func mergePropagatedHooks(terragruntOptions *options.TerragruntOptions, includedConfig *TerragruntConfig, config *TerragruntConfig) *TerragruntConfig {
+	hooks := includedConfig.Terraform.propagatedHooks()
+	if len(hooks.BeforeHooks)+len(hooks.AfterHooks)+len(hooks.ErrorHooks)+len(hooks.OnSuccessHooks)+len(hooks.OnFailureHooks)+len(hooks.OnSkipHooks) == 0 {
+		return config
+	}
+
+	// The config is copied, rather than updated in place, as it may be cached.
+	merged := *config
+	if config.Terraform != nil {
+		terraform := *config.Terraform
+		mergeTerraformHooks(terragruntOptions, &terraform, hooks)
+		terraform.BeforeHooks = hooks.BeforeHooks
+		terraform.AfterHooks = hooks.AfterHooks
+		terraform.ErrorHooks = hooks.ErrorHooks
+		terraform.OnSuccessHooks = hooks.OnSuccessHooks
+		terraform.OnFailureHooks = hooks.OnFailureHooks
+		terraform.OnSkipHooks = hooks.OnSkipHooks
+		merged.Terraform = &terraform
+	} else {
+		merged.Terraform = hooks
+	}
+	return &merged
+}
+
+// removeSkippedPropagatedHooks removes the hooks with propagate listed in skip_propagated_hooks from the terraform
+// block of the given config, once the included configs are merged in, so that a unit can opt out of them. The hooks of
+// the unit itself are left as is.
+func removeSkippedPropagatedHooks(terragruntOptions *options.TerragruntOptions, config *TerragruntConfig) {
+	if len(config.SkipPropagatedHooks) == 0 || config.Terraform == nil {
+		return
+	}
+
+	isSkipped := func(name string, propagated bool) bool {
+		if !propagated || !util.ListContainsElement(config.SkipPropagatedHooks, name) {
+			return false
+		}
+		terragruntOptions.Logger.Debugf("Skipping propagated hook %s, as it is listed in skip_propagated_hooks", name)
+		return true
+	}
+	isSkippedHook := func(hook Hook) bool { return isSkipped(hook.Name, hook.IsPropagated()) }
+
+	// The terraform block is copied, rather than updated in place, as it may be shared with an included config.
+	terraform := *config.Terraform
+	terraform.BeforeHooks = slices.DeleteFunc(slices.Clone(terraform.BeforeHooks), isSkippedHook)
+	terraform.AfterHooks = slices.DeleteFunc(slices.Clone(terraform.AfterHooks), isSkippedHook)
+	terraform.ErrorHooks = slices.DeleteFunc(slices.Clone(terraform.ErrorHooks), func(hook ErrorHook) bool { return isSkipped(hook.Name, hook.IsPropagated()) })
+	terraform.OnSuccessHooks = slices.DeleteFunc(slices.Clone(terraform.OnSuccessHooks), isSkippedHook)
+	terraform.OnFailureHooks = slices.DeleteFunc(slices.Clone(terraform.OnFailureHooks), isSkippedHook)
+	terraform.OnSkipHooks = slices.DeleteFunc(slices.Clone(terraform.OnSkipHooks), isSkippedHook)
+	config.Terraform = &terraform
+}
@@ -0,0 +1,129 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const propagatedHooksTestRoot = `
+terraform {
+  before_hook "tfsec" {
+    commands  = ["plan", "apply"]
+    execute   = ["tfsec", "."]
+    propagate = true
+  }
+
+  after_hook "local" {
+    commands = ["apply"]
+    execute  = ["echo", "root only"]
+  }
+
+  error_hook "unlock" {
+    commands         = ["apply"]
+    execute          = ["./unlock.sh"]
+    on_error_classes = ["lock_timeout"]
+    propagate        = true
+  }
+}
+`
+
+func TestPropagatedHooks(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name                string
+		unit                string
+		expectedBeforeHooks []string
+		expectedAfterHooks  []string
+		expectedErrorHooks  []string
+	}{
+		{
+			"no merge",
+			`include "root" {
+  path           = "../terragrunt.hcl"
+  merge_strategy = "no_merge"
+}
+
+terraform {
+  before_hook "fmt" {
+    commands = ["plan"]
+    execute  = ["tofu", "fmt", "-check"]
+  }
+}`,
+			[]string{"tfsec", "fmt"},
+			[]string{},
+			[]string{"unlock"},
+		},
+		{
+			"no merge without terraform block",
+			`include "root" {
+  path           = "../terragrunt.hcl"
+  merge_strategy = "no_merge"
+}`,
+			[]string{"tfsec"},
+			[]string{},
+			[]string{"unlock"},
+		},
+		{
+			"shallow merge",
+			`include "root" {
+  path = "../terragrunt.hcl"
+}`,
+			[]string{"tfsec"},
+			[]string{"local"},
+			[]string{"unlock"},
+		},
+		{
+			"opt out",
+			`include "root" {
+  path           = "../terragrunt.hcl"
+  merge_strategy = "no_merge"
+}
+
+skip_propagated_hooks = ["tfsec"]`,
+			[]string{},
+			[]string{},
+			[]string{"unlock"},
+		},
+		{
+			"opt out with shallow merge",
+			`include "root" {
+  path = "../terragrunt.hcl"
+}
+
+skip_propagated_hooks = ["tfsec", "unlock"]`,
+			[]string{},
+			[]string{"local"},
+			[]string{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			rootDir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(rootDir, DefaultTerragruntConfigPath), []byte(propagatedHooksTestRoot), 0644))
+			unitDir := filepath.Join(rootDir, "unit")
+			require.NoError(t, os.Mkdir(unitDir, 0755))
+			configPath := filepath.Join(unitDir, DefaultTerragruntConfigPath)
+
+			terragruntConfig, err := ParseConfigString(testCase.unit, mockOptionsForTestWithConfigPath(t, configPath), nil, configPath, &EvalContextExtensions{})
+			require.NoError(t, err)
+			require.NotNil(t, terragruntConfig.Terraform)
+
+			assert.Equal(t, testCase.expectedBeforeHooks, hookNames(terragruntConfig.Terraform.BeforeHooks))
+			assert.Equal(t, testCase.expectedAfterHooks, hookNames(terragruntConfig.Terraform.AfterHooks))
+			errorHookNames := []string{}
+			for _, hook := range terragruntConfig.Terraform.ErrorHooks {
+				errorHookNames = append(errorHookNames, hook.Name)
+			}
+			assert.Equal(t, testCase.expectedErrorHooks, errorHookNames)
+		})
+	}
+}
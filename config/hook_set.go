@@ -0,0 +1,163 @@
+package config
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// HookSet is a hook_set block, which names a set of hooks that units attach with the use_hooks attribute, so that a
+// library of standard hooks can be shared, e.g. with an import block, without copying the hooks into each unit:
+//
+//	hook_set "security-scan" {
+//	  before_hook "tfsec" {
+//	    commands = ["plan", "apply"]
+//	    execute  = ["tfsec", "."]
+//	  }
+//	}
+type HookSet struct {
+	Name           string      `hcl:"name,label"`
+	BeforeHooks    []Hook      `hcl:"before_hook,block"`
+	AfterHooks     []Hook      `hcl:"after_hook,block"`
+	ErrorHooks     []ErrorHook `hcl:"error_hook,block"`
+	OnSuccessHooks []Hook      `hcl:"on_success_hook,block"`
+	OnFailureHooks []Hook      `hcl:"on_failure_hook,block"`
+	OnSkipHooks    []Hook      `hcl:"on_skip_hook,block"`
+}
+
+func (set *HookSet) String() string {
+	return fmt.Sprintf("HookSet{Name = %s}", set.Name)
+}
+
+// terraformConfig returns a terraform block holding the hooks of the set.
+func (set *HookSet) terraformConfig() *TerraformConfig {
+	return &TerraformConfig{
+		BeforeHooks:    set.BeforeHooks,
+		AfterHooks:     set.AfterHooks,
+		ErrorHooks:     set.ErrorHooks,
+		OnSuccessHooks: set.OnSuccessHooks,
+		OnFailureHooks: set.OnFailureHooks,
+		OnSkipHooks:    set.OnSkipHooks,
+	}
+}
+
+// validateHookSets checks that the names of the hook sets of a config are unique, and that their hooks are valid.
+func validateHookSets(sets []HookSet, configPath string) error {
+	names := map[string]bool{}
+	for _, set := range sets {
+		if names[set.Name] {
+			return errors.WithStackTrace(DuplicatedHookSet{Name: set.Name, ConfigPath: configPath})
+		}
+		names[set.Name] = true
+
+		if err := set.terraformConfig().ValidateHooks(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// mergeHookSets merges the hook sets of a child config into the ones of its parent. A child's set replaces the parent's
+// set of the same name, as a whole.
+func mergeHookSets(childSets []HookSet, parentSets []HookSet) []HookSet {
+	if len(childSets) == 0 {
+		return parentSets
+	}
+
+	result := append([]HookSet{}, parentSets...)
+	for _, child := range childSets {
+		index := slices.IndexFunc(result, func(parent HookSet) bool { return parent.Name == child.Name })
+		if index == -1 {
+			result = append(result, child)
+		} else {
+			result[index] = child
+		}
+	}
+	return result
+}
+
+// applyHookSets returns a copy of the given config with the hooks of the sets listed in its use_hooks attribute added
+// to its terraform block. The hooks of the sets come first, in the order of use_hooks, and the hooks of the config are
+// merged over them as the hooks of a child config are merged over the hooks of an included config, so that a unit can
+// override or extend a hook of a set by name.
+func applyHookSets(config *TerragruntConfig, configPath string, terragruntOptions *options.TerragruntOptions) (*TerragruntConfig, error) {
+	if len(config.UseHooks) == 0 {
+		return config, nil
+	}
+
+	hooks := &TerraformConfig{}
+	for _, name := range config.UseHooks {
+		index := slices.IndexFunc(config.HookSets, func(set HookSet) bool { return set.Name == name })
+		if index == -1 {
+			return nil, errors.WithStackTrace(UnknownHookSet{Name: name, ConfigPath: configPath, Available: hookSetNames(config.HookSets)})
+		}
+		terragruntOptions.Logger.Debugf("Using the hooks of hook set %s", name)
+		mergeTerraformHooks(terragruntOptions, config.HookSets[index].terraformConfig(), hooks)
+	}
+
+	// The config is copied, rather than updated in place, as it may be cached.
+	applied := *config
+	if config.Terraform != nil {
+		terraform := *config.Terraform
+		mergeTerraformHooks(terragruntOptions, &terraform, hooks)
+		terraform.BeforeHooks = hooks.BeforeHooks
+		terraform.AfterHooks = hooks.AfterHooks
+		terraform.ErrorHooks = hooks.ErrorHooks
+		terraform.OnSuccessHooks = hooks.OnSuccessHooks
+		terraform.OnFailureHooks = hooks.OnFailureHooks
+		terraform.OnSkipHooks = hooks.OnSkipHooks
+		applied.Terraform = &terraform
+	} else {
+		applied.Terraform = hooks
+	}
+	return &applied, nil
+}
+
+// mergeTerraformHooks merges all the hooks of the given child terraform block into the given parent one.
+func mergeTerraformHooks(terragruntOptions *options.TerragruntOptions, child *TerraformConfig, parent *TerraformConfig) {
+	mergeHooks(terragruntOptions, child.BeforeHooks, &parent.BeforeHooks)
+	mergeHooks(terragruntOptions, child.AfterHooks, &parent.AfterHooks)
+	mergeErrorHooks(terragruntOptions, child.ErrorHooks, &parent.ErrorHooks)
+	mergeHooks(terragruntOptions, child.OnSuccessHooks, &parent.OnSuccessHooks)
+	mergeHooks(terragruntOptions, child.OnFailureHooks, &parent.OnFailureHooks)
+	mergeHooks(terragruntOptions, child.OnSkipHooks, &parent.OnSkipHooks)
+}
+
+// hookSetNames returns the sorted names of the given hook sets.
+func hookSetNames(sets []HookSet) []string {
+	names := []string{}
+	for _, set := range sets {
+		names = append(names, set.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Custom error types
+
+type DuplicatedHookSet struct {
+	Name       string
+	ConfigPath string
+}
+
+func (err DuplicatedHookSet) Error() string {
+	return fmt.Sprintf("Detected multiple hook_set blocks named %s in %s.", err.Name, err.ConfigPath)
+}
+
+type UnknownHookSet struct {
+	Name       string
+	ConfigPath string
+	Available  []string
+}
+
+func (err UnknownHookSet) Error() string {
+	if len(err.Available) == 0 {
+		return fmt.Sprintf("%s uses the hook set %s, but no hook_set block is defined.", err.ConfigPath, err.Name)
+	}
+	return fmt.Sprintf("%s uses the hook set %s, which is not defined. The defined hook sets are: %s.", err.ConfigPath, err.Name, strings.Join(err.Available, ", "))
+}
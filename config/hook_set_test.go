@@ -0,0 +1,161 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const hookSetTestLibrary = `
+hook_set "security-scan" {
+  before_hook "tfsec" {
+    commands = ["plan", "apply"]
+    execute  = ["tfsec", "."]
+  }
+}
+
+hook_set "notify" {
+  after_hook "notify" {
+    commands = ["apply"]
+    execute  = ["echo", "applied"]
+  }
+
+  on_failure_hook "notify" {
+    commands = ["apply"]
+    execute  = ["echo", "failed"]
+  }
+}
+`
+
+const hookSetTestUnit = `
+import "hooks" {
+  source = "./hooks.hcl"
+}
+
+use_hooks = ["security-scan", "notify"]
+
+terraform {
+  before_hook "fmt" {
+    commands = ["plan"]
+    execute  = ["tofu", "fmt", "-check"]
+  }
+
+  after_hook "notify" {
+    commands = ["apply"]
+    execute  = ["echo", "overridden"]
+  }
+}
+`
+
+func writeHookSetTestLibrary(t *testing.T) string {
+	rootDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(rootDir, "hooks.hcl"), []byte(hookSetTestLibrary), 0644))
+	return filepath.Join(rootDir, DefaultTerragruntConfigPath)
+}
+
+func hookNames(hooks []Hook) []string {
+	names := []string{}
+	for _, hook := range hooks {
+		names = append(names, hook.Name)
+	}
+	return names
+}
+
+func TestUseHooks(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeHookSetTestLibrary(t)
+	terragruntConfig, err := ParseConfigString(hookSetTestUnit, mockOptionsForTestWithConfigPath(t, configPath), nil, configPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"tfsec", "fmt"}, hookNames(terragruntConfig.Terraform.BeforeHooks))
+	assert.Equal(t, []string{"notify"}, hookNames(terragruntConfig.Terraform.AfterHooks))
+	assert.Equal(t, []string{"echo", "overridden"}, terragruntConfig.Terraform.AfterHooks[0].Execute)
+	assert.Equal(t, []string{"notify"}, hookNames(terragruntConfig.Terraform.OnFailureHooks))
+	assert.Equal(t, []string{"notify", "security-scan"}, hookSetNames(terragruntConfig.HookSets))
+}
+
+func TestUseHooksPartialParse(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeHookSetTestLibrary(t)
+	require.NoError(t, os.WriteFile(configPath, []byte(hookSetTestUnit), 0644))
+
+	terragruntConfig, err := PartialParseConfigFile(configPath, mockOptionsForTestWithConfigPath(t, configPath), nil, []PartialDecodeSectionType{TerraformBlock})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"tfsec", "fmt"}, hookNames(terragruntConfig.Terraform.BeforeHooks))
+	assert.Equal(t, []string{"notify"}, hookNames(terragruntConfig.Terraform.OnFailureHooks))
+}
+
+func TestUseHooksWithoutTerraformBlock(t *testing.T) {
+	t.Parallel()
+
+	config := `
+hook_set "notify" {
+  on_success_hook "notify" {
+    commands = ["apply"]
+    execute  = ["echo", "applied"]
+  }
+}
+
+use_hooks = ["notify"]
+`
+	terragruntConfig, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	require.NotNil(t, terragruntConfig.Terraform)
+	assert.Equal(t, []string{"notify"}, hookNames(terragruntConfig.Terraform.OnSuccessHooks))
+}
+
+func TestUseHooksUnknownHookSet(t *testing.T) {
+	t.Parallel()
+
+	configPath := writeHookSetTestLibrary(t)
+	config := `
+import "hooks" {
+  source = "./hooks.hcl"
+}
+
+use_hooks = ["notify", "cost-estimate"]
+`
+	_, err := ParseConfigString(config, mockOptionsForTestWithConfigPath(t, configPath), nil, configPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	unknown, ok := errors.Unwrap(err).(UnknownHookSet)
+	require.True(t, ok, "unexpected error: %v", err)
+	assert.Equal(t, "cost-estimate", unknown.Name)
+	assert.Equal(t, []string{"notify", "security-scan"}, unknown.Available)
+}
+
+func TestHookSetDuplicated(t *testing.T) {
+	t.Parallel()
+
+	config := `
+hook_set "notify" {}
+hook_set "notify" {}
+`
+	_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	_, ok := errors.Unwrap(err).(DuplicatedHookSet)
+	assert.True(t, ok, "unexpected error: %v", err)
+}
+
+func TestHookSetInvalidHook(t *testing.T) {
+	t.Parallel()
+
+	config := `
+hook_set "notify" {
+  after_hook "notify" {
+    commands = ["apply"]
+  }
+}
+`
+	_, err := ParseConfigString(config, terragruntOptionsForTest(t, DefaultTerragruntConfigPath), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Need at least one non-empty argument in 'execute'")
+}
@@ -0,0 +1,120 @@
+package config
+
+import (
+	goerrors "errors"
+	"fmt"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+const (
+	// HookOnTimeoutFail fails the hook when it is killed because of its timeout. This is the default.
+	HookOnTimeoutFail = "fail"
+	// HookOnTimeoutWarn logs a warning when the hook is killed because of its timeout, and continues as if it succeeded.
+	HookOnTimeoutWarn = "warn"
+)
+
+// HookTimeout holds the attributes of a hook that limit how long it may run.
+type HookTimeout struct {
+	Timeout          *string
+	OnTimeout        *string
+	KillProcessGroup *bool
+}
+
+// HookTimeout returns the timeout settings of the hook.
+func (hook Hook) HookTimeout() HookTimeout {
+	return HookTimeout{Timeout: hook.Timeout, OnTimeout: hook.OnTimeout, KillProcessGroup: hook.KillProcessGroup}
+}
+
+// HookTimeout returns the timeout settings of the error hook.
+func (hook ErrorHook) HookTimeout() HookTimeout {
+	return HookTimeout{Timeout: hook.Timeout, OnTimeout: hook.OnTimeout, KillProcessGroup: hook.KillProcessGroup}
+}
+
+// Duration returns the longest the hook may run, or zero if it may run until it finishes.
+func (settings HookTimeout) Duration() time.Duration {
+	if settings.Timeout == nil {
+		return 0
+	}
+	timeout, _ := time.ParseDuration(*settings.Timeout)
+	return timeout
+}
+
+// WarnOnTimeout returns true if the hook continues with a warning when it is killed because of its timeout.
+func (settings HookTimeout) WarnOnTimeout() bool {
+	return settings.OnTimeout != nil && *settings.OnTimeout == HookOnTimeoutWarn
+}
+
+// KillsProcessGroup returns true if the processes started by the hook are killed along with it.
+func (settings HookTimeout) KillsProcessGroup() bool {
+	return settings.KillProcessGroup != nil && *settings.KillProcessGroup
+}
+
+// RunHookCommand runs the command of the hook with the given name within the timeout of the hook. When the timeout
+// passes, the command is killed, along with the processes it started if the hook kills its process group, and the hook
+// fails, unless it only warns on timeout.
+func RunHookCommand(terragruntOptions *options.TerragruntOptions, hookName string, settings HookTimeout, workingDir string, suppressStdout bool, execute []string) error {
+	_, err := RunHookCommandWithOutput(terragruntOptions, hookName, settings, workingDir, suppressStdout, execute)
+	return err
+}
+
+// RunHookCommandWithOutput runs the command of the hook like RunHookCommand, and returns its output.
+func RunHookCommandWithOutput(terragruntOptions *options.TerragruntOptions, hookName string, settings HookTimeout, workingDir string, suppressStdout bool, execute []string) (*shell.CmdOutput, error) {
+	hookOptions, killedAtTimeout := terragruntOptions, false
+	if settings.Duration() > 0 || settings.KillsProcessGroup() {
+		hookOptions, killedAtTimeout = terragruntOptions.CloneWithTimeout(settings.Duration())
+		hookOptions.KillProcessGroup = settings.KillsProcessGroup()
+	}
+
+	out, err := shell.RunShellCommandWithOutput(hookOptions, workingDir, suppressStdout, false, execute[0], execute[1:]...)
+
+	var timeoutErr shell.TimeoutExceeded
+	if killedAtTimeout && goerrors.As(err, &timeoutErr) {
+		return out, settings.timeoutExceeded(terragruntOptions, hookName)
+	}
+	return out, err
+}
+
+// timeoutExceeded returns the error of the hook with the given name killed because of its timeout, or logs a warning
+// and returns nil if the hook only warns on timeout.
+func (settings HookTimeout) timeoutExceeded(terragruntOptions *options.TerragruntOptions, hookName string) error {
+	err := HookTimeoutExceeded{Name: hookName, Timeout: settings.Duration()}
+	if settings.WarnOnTimeout() {
+		terragruntOptions.Logger.Warnf("%s Continuing as its on_timeout is %s.", err.Error(), HookOnTimeoutWarn)
+		return nil
+	}
+	return errors.WithStackTrace(err)
+}
+
+// validateHookTimeout checks the timeout settings of the hook with the given name.
+func validateHookTimeout(hookName string, settings HookTimeout) error {
+	if settings.Timeout != nil {
+		if timeout, err := time.ParseDuration(*settings.Timeout); err != nil || timeout <= 0 {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'timeout' must be a positive duration such as \"5m\", got %q.", hookName, *settings.Timeout))
+		}
+	}
+	if settings.OnTimeout != nil {
+		if settings.Timeout == nil {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. 'on_timeout' can only be set along with 'timeout'.", hookName))
+		}
+		if *settings.OnTimeout != HookOnTimeoutFail && *settings.OnTimeout != HookOnTimeoutWarn {
+			return InvalidArgError(fmt.Sprintf("Error with hook %s. The value of 'on_timeout' must be one of %q or %q, got %q.", hookName, HookOnTimeoutFail, HookOnTimeoutWarn, *settings.OnTimeout))
+		}
+	}
+	return nil
+}
+
+// Custom error types
+
+type HookTimeoutExceeded struct {
+	Name    string
+	Timeout time.Duration
+}
+
+func (err HookTimeoutExceeded) Error() string {
+	return fmt.Sprintf("The hook %s was killed as it was still running after its timeout of %s.", err.Name, err.Timeout)
+}
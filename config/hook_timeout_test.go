@@ -0,0 +1,99 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunHookCommandTimeout(t *testing.T) {
+	t.Parallel()
+
+	timeout := "200ms"
+	warn := HookOnTimeoutWarn
+	killProcessGroup := true
+
+	testCases := []struct {
+		name          string
+		settings      HookTimeout
+		execute       []string
+		expectedError error
+	}{
+		{"fail", HookTimeout{Timeout: &timeout}, []string{"sleep", "30"}, HookTimeoutExceeded{Name: "wedged", Timeout: 200 * time.Millisecond}},
+		// The background sleep keeps the output of the hook open, so the hook only finishes once it is killed too.
+		{"warn", HookTimeout{Timeout: &timeout, OnTimeout: &warn, KillProcessGroup: &killProcessGroup}, []string{"sh", "-c", "sleep 30 & sleep 30"}, nil},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			start := time.Now()
+			err := RunHookCommand(mockOptionsForTest(t), "wedged", testCase.settings, "", false, testCase.execute)
+			assert.Less(t, time.Since(start), 10*time.Second)
+
+			if testCase.expectedError == nil {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Equal(t, testCase.expectedError, errors.Unwrap(err))
+		})
+	}
+}
+
+func TestParseTerragruntConfigHookTimeouts(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		hook          string
+		expectedError string
+	}{
+		{"timeout", `timeout = "5m"` + "\n" + `on_timeout = "warn"` + "\n" + `kill_process_group = true`, ""},
+		{"invalid timeout", `timeout = "forever"`, `The value of 'timeout' must be a positive duration such as "5m", got "forever".`},
+		{"on_timeout without timeout", `on_timeout = "warn"`, "'on_timeout' can only be set along with 'timeout'."},
+		{"invalid on_timeout", `timeout = "5m"` + "\n" + `on_timeout = "retry"`, `The value of 'on_timeout' must be one of "fail" or "warn", got "retry".`},
+		{"background", `timeout = "5m"` + "\n" + `run_in_background = true`, "'timeout' can't be set on a background hook"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := `
+terraform {
+  before_hook "wait" {
+    commands = ["apply"]
+    execute  = ["./wait.sh"]
+    ` + testCase.hook + `
+  }
+
+  error_hook "cleanup" {
+    commands  = ["apply"]
+    execute   = ["./cleanup.sh"]
+    on_errors = [".*"]
+    timeout   = "1m"
+  }
+}
+`
+			terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			settings := terragruntConfig.Terraform.BeforeHooks[0].HookTimeout()
+			assert.Equal(t, 5*time.Minute, settings.Duration())
+			assert.True(t, settings.WarnOnTimeout())
+			assert.True(t, settings.KillsProcessGroup())
+			assert.Equal(t, time.Minute, terragruntConfig.Terraform.ErrorHooks[0].HookTimeout().Duration())
+		})
+	}
+}
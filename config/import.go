@@ -0,0 +1,340 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// ImportConfig is an import block, which pulls a shared config file, such as a library of hooks and retry blocks,
+// from a source pinned to a ref:
+//
+//	import "common" {
+//	  source = "git::https://github.com/acme/terragrunt-lib.git//common/hooks.hcl?ref=v1.2.0"
+//	}
+//
+// The imported config is deep merged into the config that imports it, whose own values take precedence, and is
+// exposed to it as `import.common`, e.g. `import.common.locals.region`.
+type ImportConfig struct {
+	Name   string `hcl:"name,label"`
+	Source string `hcl:"source,attr"`
+}
+
+func (cfg *ImportConfig) String() string {
+	return fmt.Sprintf("ImportConfig{Name = %s, Source = %s}", cfg.Name, cfg.Source)
+}
+
+// terragruntImports is a struct that can be used to only decode the import blocks.
+type terragruntImports struct {
+	Imports []ImportConfig `hcl:"import,block"`
+	Remain  hcl.Body       `hcl:",remain"`
+}
+
+// ImportedConfig is a config pulled in with an import block.
+type ImportedConfig struct {
+	Name   string
+	Path   string
+	Config *TerragruntConfig
+}
+
+// The folders where the remote sources of import and dependency blocks were downloaded in this run, by cache folder and
+// source, so that each source is only looked up once, even if it is used by many modules.
+var pinnedSourceDownloads = NewStringCache()
+
+// Serializes the downloads of remote sources in this process, so that two modules using the same source don't download
+// it at the same time. Other terragrunt processes sharing the cache folder download into their own temporary folders.
+var pinnedSourceDownloadMutex sync.Mutex
+
+// Matches the refs that are commit SHAs, which, unlike branches, always point to the same code.
+var commitSHARegex = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// decodeImportBlocks decodes the import blocks of the given file, downloads their sources and parses the imported
+// configs. The imported configs are parsed as if they were included, so they can't have include or dependency blocks.
+// If the file is itself imported, includeFromChild holds the chain of files importing it, so that an import cycle is
+// reported rather than recursing forever.
+func decodeImportBlocks(file *hcl.File, filename string, terragruntOptions *options.TerragruntOptions, includeFromChild *IncludeConfig, evalContext *hcl.EvalContext) ([]ImportedConfig, error) {
+	decoded := terragruntImports{}
+	if err := decodeHcl(file, filename, &decoded, evalContext); err != nil {
+		return nil, err
+	}
+
+	if len(decoded.Imports) == 0 {
+		return []ImportedConfig{}, nil
+	}
+
+	importChain := []string{filename}
+	if includeFromChild != nil {
+		importChain = append(append([]string{}, includeFromChild.importChain...), filename)
+	}
+
+	imported := []ImportedConfig{}
+	names := map[string]bool{}
+	for _, importConfig := range decoded.Imports {
+		if names[importConfig.Name] {
+			return nil, errors.WithStackTrace(DuplicatedImportBlock{Name: importConfig.Name, ConfigPath: filename})
+		}
+		names[importConfig.Name] = true
+
+		importPath, err := resolveImportSource(importConfig, filename, terragruntOptions)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, importingPath := range importChain {
+			if util.CleanPath(importingPath) == util.CleanPath(importPath) {
+				return nil, errors.WithStackTrace(ImportCycle{Name: importConfig.Name, Chain: append(importChain, importPath)})
+			}
+		}
+
+		// No dependency outputs are passed in, so that imports can't depend on other modules.
+		noDependencies := cty.EmptyObjectVal
+		importedConfig, err := ParseConfigFile(importPath, terragruntOptions, &IncludeConfig{Name: importConfig.Name, Path: importPath, importChain: importChain}, &noDependencies)
+		if err != nil {
+			return nil, err
+		}
+		imported = append(imported, ImportedConfig{Name: importConfig.Name, Path: importPath, Config: importedConfig})
+	}
+	return imported, nil
+}
+
+// importedConfigsAsCty returns the imported configs as an object to expose as the `import` variable.
+func importedConfigsAsCty(imported []ImportedConfig) (*cty.Value, error) {
+	if len(imported) == 0 {
+		return nil, nil
+	}
+
+	configs := map[string]cty.Value{}
+	for _, importedConfig := range imported {
+		configCty, err := TerragruntConfigAsCty(importedConfig.Config)
+		if err != nil {
+			return nil, err
+		}
+		configs[importedConfig.Name] = configCty
+	}
+	configsAsCty := cty.ObjectVal(configs)
+	return &configsAsCty, nil
+}
+
+// mergeImportedConfigs deep merges the given config over the imported configs, in order, so that the values of the
+// config take precedence over the imported ones, and the later imports over the earlier ones.
+func mergeImportedConfigs(config *TerragruntConfig, imported []ImportedConfig, terragruntOptions *options.TerragruntOptions) (*TerragruntConfig, error) {
+	if len(imported) == 0 {
+		return config, nil
+	}
+
+	merged := &TerragruntConfig{IsPartial: config.IsPartial}
+	declaredFeatures := append([]string{}, config.DeclaredFeatures...)
+	for _, importedConfig := range imported {
+		terragruntOptions.Logger.Debugf("Merging config imported from %s as %s", importedConfig.Path, importedConfig.Name)
+		if err := merged.DeepMerge(importedConfig.Config, terragruntOptions); err != nil {
+			return nil, err
+		}
+		declaredFeatures = util.RemoveDuplicatesFromList(append(declaredFeatures, importedConfig.Config.DeclaredFeatures...))
+	}
+	if err := merged.DeepMerge(config, terragruntOptions); err != nil {
+		return nil, err
+	}
+	// Like for included configs, locals are not merged, so that they remain local in scope.
+	merged.Locals = config.Locals
+	merged.ProcessedIncludes = config.ProcessedIncludes
+	merged.DeclaredFeatures = declaredFeatures
+	return merged, nil
+}
+
+// resolveImportSource returns the path of the file imported by the given import block, downloading its source if it
+// is remote. Local sources are used in place, while remote sources must be pinned with a ref, e.g. ?ref=v1.2.0, and are
+// downloaded once into a cache folder that is shared between runs, until the cache is refreshed with
+// --terragrunt-source-update.
+func resolveImportSource(importConfig ImportConfig, filename string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	sourceDir, filePath := getter.SourceDirSubdir(importConfig.Source)
+
+	detected, err := getter.Detect(sourceDir, filepath.Dir(filename), getter.Detectors)
+	if err != nil {
+		return "", errors.WithStackTrace(ImportFailed{Name: importConfig.Name, Source: importConfig.Source, Err: err})
+	}
+	if strings.HasPrefix(detected, "file://") {
+		return util.CanonicalPath(importConfig.Source, filepath.Dir(filename))
+	}
+
+	if filePath == "" {
+		return "", errors.WithStackTrace(ImportFailed{Name: importConfig.Name, Source: importConfig.Source, Err: fmt.Errorf("the source must contain the path of the file to import after a double slash, e.g. git::https://github.com/acme/lib.git//common.hcl?ref=v1.0.0")})
+	}
+	if !isPinnedSource(detected) {
+		return "", errors.WithStackTrace(ImportFailed{Name: importConfig.Name, Source: importConfig.Source, Err: fmt.Errorf("remote sources must be pinned to a ref, e.g. ?ref=v1.0.0")})
+	}
+
+	downloadDir, err := downloadPinnedSource(detected, filepath.Join(sourceCacheDir(), "imports"), terragruntOptions)
+	if err != nil {
+		return "", errors.WithStackTrace(ImportFailed{Name: importConfig.Name, Source: importConfig.Source, Err: err})
+	}
+
+	importPath := filepath.Join(downloadDir, filepath.FromSlash(filePath))
+	if !util.FileExists(importPath) {
+		return "", errors.WithStackTrace(ImportFailed{Name: importConfig.Name, Source: importConfig.Source, Err: fmt.Errorf("%s does not exist in the source", filePath)})
+	}
+	return importPath, nil
+}
+
+// isPinnedSource returns true if the given detected source has a ref query parameter.
+func isPinnedSource(source string) bool {
+	return sourceRef(source) != ""
+}
+
+// sourceRef returns the ref query parameter of the given detected source, or an empty string if it has none.
+func sourceRef(source string) string {
+	if _, rest, forced := strings.Cut(source, "::"); forced {
+		source = rest
+	}
+	sourceURL, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+	return sourceURL.Query().Get("ref")
+}
+
+// checkImmutableRef returns an error if the given detected source is a git repository pinned to a branch rather than
+// to a tag or a commit, as the download of a branch would be cached forever and never pick up its new commits.
+func checkImmutableRef(source string, terragruntOptions *options.TerragruntOptions) error {
+	ref := sourceRef(source)
+	if commitSHARegex.MatchString(ref) {
+		return nil
+	}
+	getterName, repo, forced := strings.Cut(source, "::")
+	if !forced || getterName != "git" {
+		return nil
+	}
+
+	repoURL, err := url.Parse(repo)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	repoURL.RawQuery = ""
+
+	out, err := shell.RunShellCommandWithOutput(terragruntOptions, "", true, false, "git", "ls-remote", "--heads", repoURL.String(), ref)
+	if err != nil {
+		return err
+	}
+	for _, line := range strings.Split(out.Stdout, "\n") {
+		if strings.HasSuffix(strings.TrimSpace(line), "refs/heads/"+ref) {
+			return errors.WithStackTrace(MutableSourceRef{Source: source, Ref: ref})
+		}
+	}
+	return nil
+}
+
+// downloadPinnedSource downloads the given source into the given cache folder, unless it is already there, and returns
+// the folder it was downloaded into. As sources are pinned to a tag or a commit, a source that was downloaded once is
+// not downloaded again, unless --terragrunt-source-update is set, e.g. to pick up a tag that was moved.
+func downloadPinnedSource(source string, cacheDir string, terragruntOptions *options.TerragruntOptions) (string, error) {
+	pinnedSourceDownloadMutex.Lock()
+	defer pinnedSourceDownloadMutex.Unlock()
+
+	downloadDir := filepath.Join(cacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(source))))
+	if _, found := pinnedSourceDownloads.Get(downloadDir); found {
+		return downloadDir, nil
+	}
+
+	if terragruntOptions.SourceUpdate || !util.IsDir(downloadDir) {
+		if err := checkImmutableRef(source, terragruntOptions); err != nil {
+			return "", err
+		}
+
+		terragruntOptions.Logger.Debugf("Downloading source %s into %s", source, downloadDir)
+
+		// Download into a temporary folder of its own next to the cache folder first, so that an interrupted download is
+		// not mistaken for a complete one, and that concurrent terragrunt processes don't download into the same folder.
+		// The download is then moved into place with an atomic rename.
+		if err := os.MkdirAll(cacheDir, os.ModePerm); err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		tempDir, err := os.MkdirTemp(cacheDir, filepath.Base(downloadDir)+".download-")
+		if err != nil {
+			return "", errors.WithStackTrace(err)
+		}
+		defer os.RemoveAll(tempDir)
+
+		// The getters expect the destination not to exist yet, e.g. the git getter updates an existing folder.
+		tempDownloadDir := filepath.Join(tempDir, "source")
+		if err := getter.Get(tempDownloadDir, source); err != nil {
+			return "", err
+		}
+		if terragruntOptions.SourceUpdate {
+			if err := os.RemoveAll(downloadDir); err != nil {
+				return "", errors.WithStackTrace(err)
+			}
+		}
+		if err := os.Rename(tempDownloadDir, downloadDir); err != nil {
+			// Another process moved its download of the same source into place first, which is used instead.
+			if !util.IsDir(downloadDir) {
+				return "", errors.WithStackTrace(err)
+			}
+			terragruntOptions.Logger.Debugf("Source %s was downloaded into %s by another process", source, downloadDir)
+		}
+	}
+
+	pinnedSourceDownloads.Put(downloadDir, source)
+	return downloadDir, nil
+}
+
+// sourceCacheDir returns the folder where the remote sources of import and dependency blocks are downloaded, in the
+// cache folder of the user.
+func sourceCacheDir() string {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	return filepath.Join(cacheDir, "terragrunt")
+}
+
+// Custom error types
+
+type DuplicatedImportBlock struct {
+	Name       string
+	ConfigPath string
+}
+
+func (err DuplicatedImportBlock) Error() string {
+	return fmt.Sprintf("Import %s is declared more than once in %s.", err.Name, err.ConfigPath)
+}
+
+type ImportFailed struct {
+	Name   string
+	Source string
+	Err    error
+}
+
+func (err ImportFailed) Error() string {
+	return fmt.Sprintf("Could not import %s from %s: %v", err.Name, err.Source, err.Err)
+}
+
+type ImportCycle struct {
+	Name  string
+	Chain []string
+}
+
+func (err ImportCycle) Error() string {
+	return fmt.Sprintf("Import %s creates an import cycle: %s.", err.Name, strings.Join(err.Chain, " -> "))
+}
+
+type MutableSourceRef struct {
+	Source string
+	Ref    string
+}
+
+func (err MutableSourceRef) Error() string {
+	return fmt.Sprintf("%s is pinned to the branch %s, which can move. Pin it to a tag or a commit instead.", err.Source, err.Ref)
+}
@@ -0,0 +1,186 @@
+package config
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const importTestLibrary = `
+locals {
+  region = "eu-west-1"
+}
+
+retryable_errors = ["(?s).*timeout.*"]
+
+inputs = {
+  region = local.region
+  owner  = "platform"
+}
+`
+
+// createImportTestRepo creates a git repository with the shared config at lib.hcl, tagged as v1.
+func createImportTestRepo(t *testing.T) string {
+	repoDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(repoDir, "lib.hcl"), []byte(importTestLibrary), 0644))
+	for _, args := range [][]string{
+		{"init", "--quiet"},
+		{"add", "lib.hcl"},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "--quiet", "-m", "Add lib"},
+		{"tag", "v1"},
+	} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		output, err := cmd.CombinedOutput()
+		require.NoError(t, err, string(output))
+	}
+	return repoDir
+}
+
+func TestImportBlocks(t *testing.T) {
+	t.Parallel()
+
+	repoDir := createImportTestRepo(t)
+
+	testCases := []struct {
+		name   string
+		source string
+	}{
+		{"local", filepath.Join(repoDir, "lib.hcl")},
+		{"git", "git::file://" + repoDir + "//lib.hcl?ref=v1"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := `
+import "shared" {
+  source = "` + testCase.source + `"
+}
+
+locals {
+  name = "app-${import.shared.locals.region}"
+}
+
+inputs = {
+  name  = local.name
+  owner = "app"
+}
+`
+			opts := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+			terragruntConfig, err := ParseConfigString(config, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			require.NoError(t, err)
+
+			assert.Equal(t, map[string]interface{}{"name": "app-eu-west-1", "owner": "app", "region": "eu-west-1"}, terragruntConfig.Inputs)
+			assert.Equal(t, []string{"(?s).*timeout.*"}, terragruntConfig.RetryableErrors)
+			assert.Equal(t, map[string]interface{}{"name": "app-eu-west-1"}, terragruntConfig.Locals)
+		})
+	}
+}
+
+func TestImportBlockRemoteSourceMustBePinned(t *testing.T) {
+	t.Parallel()
+
+	config := `
+import "shared" {
+  source = "git::https://github.com/acme/terragrunt-lib.git//lib.hcl"
+}
+`
+	opts := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	_, err := ParseConfigString(config, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	importFailed, isImportFailed := errors.Unwrap(err).(ImportFailed)
+	require.True(t, isImportFailed, err.Error())
+	assert.Equal(t, "shared", importFailed.Name)
+	assert.Contains(t, importFailed.Error(), "pinned to a ref")
+}
+
+func TestImportBlockRemoteSourceMustNotBeBranch(t *testing.T) {
+	t.Parallel()
+
+	repoDir := createImportTestRepo(t)
+	cmd := exec.Command("git", "branch", "release")
+	cmd.Dir = repoDir
+	output, err := cmd.CombinedOutput()
+	require.NoError(t, err, string(output))
+
+	config := `
+import "shared" {
+  source = "git::file://` + repoDir + `//lib.hcl?ref=release"
+}
+`
+	opts := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	_, err = ParseConfigString(config, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	importFailed, isImportFailed := errors.Unwrap(err).(ImportFailed)
+	require.True(t, isImportFailed, err.Error())
+	assert.IsType(t, MutableSourceRef{}, errors.Unwrap(importFailed.Err))
+}
+
+func TestImportBlockCycle(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.hcl"), []byte(`
+import "b" {
+  source = "./b.hcl"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.hcl"), []byte(`
+import "a" {
+  source = "./a.hcl"
+}
+`), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, DefaultTerragruntConfigPath), []byte(`
+import "self" {
+  source = "./terragrunt.hcl"
+}
+`), 0644))
+
+	for _, configPath := range []string{filepath.Join(dir, "a.hcl"), filepath.Join(dir, DefaultTerragruntConfigPath)} {
+		opts := terragruntOptionsForTest(t, configPath)
+		_, err := ParseConfigFile(configPath, opts, nil, nil)
+		require.Error(t, err, configPath)
+		assert.IsType(t, ImportCycle{}, errors.Unwrap(err), err.Error())
+	}
+}
+
+func TestDownloadPinnedSource(t *testing.T) {
+	t.Parallel()
+
+	repoDir := createImportTestRepo(t)
+	source := "git::file://" + repoDir + "?ref=v1"
+	cacheDir := t.TempDir()
+
+	// A partial download of another process is neither reused nor removed.
+	otherDownloadDir := filepath.Join(cacheDir, fmt.Sprintf("%x", sha256.Sum256([]byte(source)))+".download-other")
+	require.NoError(t, os.MkdirAll(otherDownloadDir, 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(otherDownloadDir, "partial"), []byte{}, 0644))
+
+	downloadDir, err := downloadPinnedSource(source, cacheDir, terragruntOptionsForTest(t, DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	assert.FileExists(t, filepath.Join(downloadDir, "lib.hcl"))
+	assert.NoFileExists(t, filepath.Join(downloadDir, "partial"))
+	assert.FileExists(t, filepath.Join(otherDownloadDir, "partial"))
+
+	// The temporary folder of the download is removed once the download is moved into place.
+	entries, err := os.ReadDir(cacheDir)
+	require.NoError(t, err)
+	names := []string{}
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.ElementsMatch(t, []string{filepath.Base(downloadDir), filepath.Base(otherDownloadDir)}, names)
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/gruntwork-io/terragrunt/codegen"
@@ -15,6 +16,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
 	"github.com/gruntwork-io/terragrunt/util"
 )
 
@@ -85,14 +87,22 @@ func parseIncludedConfig(
 	if err != nil {
 		return nil, err
 	}
+
+	var config *TerragruntConfig
 	if hasDependency && len(decodeList) > 0 {
 		terragruntOptions.Logger.Debugf(
 			"Included config %s can only be partially parsed during dependency graph formation for run-all command as it has a dependency block.",
 			includePath,
 		)
-		return PartialParseConfigFile(includePath, terragruntOptions, includedConfig, decodeList)
+		config, err = PartialParseConfigFile(includePath, terragruntOptions, includedConfig, decodeList)
+	} else {
+		config, err = ParseConfigFile(includePath, terragruntOptions, includedConfig, dependencyOutputs)
+	}
+	if err != nil {
+		// Keep track of the config that included the one with the error, so that the include chain can be shown.
+		return nil, IncludedConfigError{ConfigPath: terragruntOptions.TerragruntConfigPath, IncludePath: includePath, Err: err}
 	}
-	return ParseConfigFile(includePath, terragruntOptions, includedConfig, dependencyOutputs)
+	return config, nil
 }
 
 // handleInclude merges the included config into the current config depending on the merge strategy specified by the
@@ -111,6 +121,7 @@ func handleInclude(
 	// those in earlier includes, so we need to merge bottom up instead of top down to ensure this.
 	includeList := trackInclude.CurrentList
 	baseConfig := config
+	declaredFeatures := append([]string{}, config.DeclaredFeatures...)
 	for i := len(includeList) - 1; i >= 0; i-- {
 		includeConfig := includeList[i]
 		mergeStrategy, err := includeConfig.GetMergeStrategy()
@@ -122,10 +133,22 @@ func handleInclude(
 		if err != nil {
 			return nil, err
 		}
+		declaredFeatures = util.RemoveDuplicatesFromList(append(declaredFeatures, parsedIncludeConfig.DeclaredFeatures...))
+
+		// Merging modifies the included config, so the attributes that have their own merge strategy are saved first.
+		childConfig := baseConfig
+		var parent parentAttributes
+		if len(includeConfig.MergeStrategies) > 0 {
+			parent, err = saveParentAttributes(parsedIncludeConfig)
+			if err != nil {
+				return nil, err
+			}
+		}
 
 		switch mergeStrategy {
 		case NoMerge:
 			terragruntOptions.Logger.Debugf("Included config %s has strategy no merge: not merging config in.", includeConfig.Path)
+			baseConfig = mergePropagatedHooks(terragruntOptions, parsedIncludeConfig, baseConfig)
 		case ShallowMerge:
 			terragruntOptions.Logger.Debugf("Included config %s has strategy shallow merge: merging config in (shallow).", includeConfig.Path)
 			if err := parsedIncludeConfig.Merge(baseConfig, terragruntOptions); err != nil {
@@ -141,10 +164,138 @@ func handleInclude(
 		default:
 			return nil, fmt.Errorf("You reached an impossible condition. This is most likely a bug in terragrunt. Please open an issue at github.com/gruntwork-io/terragrunt with this error message. Code: UNKNOWN_MERGE_STRATEGY_%s", mergeStrategy)
 		}
+
+		if err := mergeAttributesWithOwnStrategy(&includeConfig, baseConfig, childConfig, parent, terragruntOptions); err != nil {
+			return nil, err
+		}
 	}
+	removeSkippedPropagatedHooks(terragruntOptions, baseConfig)
+	excludeDisabledDependencyPaths(baseConfig)
+	baseConfig.DeclaredFeatures = declaredFeatures
 	return baseConfig, nil
 }
 
+// parentAttributes are the values of the attributes of an included config that can have their own merge strategy,
+// saved before the merge of the include block modifies the included config.
+type parentAttributes struct {
+	inputs          map[string]interface{}
+	retryableErrors []string
+	generateConfigs map[string]codegen.GenerateConfig
+	remoteState     *remote.RemoteState
+}
+
+func saveParentAttributes(parentConfig *TerragruntConfig) (parentAttributes, error) {
+	inputs, err := copyInputs(parentConfig.Inputs)
+	if err != nil {
+		return parentAttributes{}, err
+	}
+	generateConfigs := map[string]codegen.GenerateConfig{}
+	for name, generateConfig := range parentConfig.GenerateConfigs {
+		generateConfigs[name] = generateConfig
+	}
+	return parentAttributes{
+		inputs:          inputs,
+		retryableErrors: append([]string{}, parentConfig.RetryableErrors...),
+		generateConfigs: generateConfigs,
+		// Merging replaces the remote state of the included config rather than modifying it.
+		remoteState: parentConfig.RemoteState,
+	}, nil
+}
+
+// mergeAttributesWithOwnStrategy merges again the attributes for which the include block sets a strategy in
+// merge_strategies, from the child and parent values, into the config resulting from the merge of the include block.
+func mergeAttributesWithOwnStrategy(
+	includeConfig *IncludeConfig,
+	mergedConfig *TerragruntConfig,
+	childConfig *TerragruntConfig,
+	parent parentAttributes,
+	terragruntOptions *options.TerragruntOptions,
+) error {
+	for attribute := range includeConfig.MergeStrategies {
+		strategy, err := includeConfig.GetAttributeMergeStrategy(attribute)
+		if err != nil {
+			return err
+		}
+		terragruntOptions.Logger.Debugf("Included config %s has strategy %s for attribute %s: merging attribute in.", includeConfig.Path, strategy, attribute)
+
+		switch attribute {
+		case MetadataInputs:
+			switch strategy {
+			case NoMerge:
+				mergedConfig.Inputs = childConfig.Inputs
+			case ShallowMerge:
+				mergedConfig.Inputs = mergeInputs(childConfig.Inputs, parent.inputs)
+			case DeepMerge:
+				mergedInputs, err := deepMergeInputs(childConfig.Inputs, parent.inputs)
+				if err != nil {
+					return err
+				}
+				mergedConfig.Inputs = mergedInputs
+			}
+		case MetadataRetryableErrors:
+			switch strategy {
+			case NoMerge:
+				mergedConfig.RetryableErrors = childConfig.RetryableErrors
+			case ShallowMerge:
+				mergedConfig.RetryableErrors = parent.retryableErrors
+				if childConfig.RetryableErrors != nil {
+					mergedConfig.RetryableErrors = childConfig.RetryableErrors
+				}
+			case DeepMerge:
+				mergedConfig.RetryableErrors = append(parent.retryableErrors, childConfig.RetryableErrors...)
+			}
+		case MetadataGenerateConfigs:
+			// Generate blocks are merged by name, so the shallow and deep merges are the same.
+			generateConfigs := map[string]codegen.GenerateConfig{}
+			if strategy != NoMerge {
+				for name, generateConfig := range parent.generateConfigs {
+					generateConfigs[name] = generateConfig
+				}
+			}
+			for name, generateConfig := range childConfig.GenerateConfigs {
+				generateConfigs[name] = generateConfig
+			}
+			mergedConfig.GenerateConfigs = generateConfigs
+		case MetadataRemoteState:
+			remoteState, err := mergeRemoteStateWithStrategy(childConfig.RemoteState, parent.remoteState, strategy)
+			if err != nil {
+				return err
+			}
+			mergedConfig.RemoteState = remoteState
+		}
+	}
+	return nil
+}
+
+// mergeRemoteStateWithStrategy merges the remote state of the child over the one of the parent with the given strategy.
+// The deep merge merges the config and encryption maps of the same backend recursively, while the other settings of the
+// child replace those of the parent, as they can't be told apart from their defaults.
+func mergeRemoteStateWithStrategy(childRemoteState *remote.RemoteState, parentRemoteState *remote.RemoteState, strategy MergeStrategyType) (*remote.RemoteState, error) {
+	switch {
+	case strategy == NoMerge || parentRemoteState == nil:
+		return childRemoteState, nil
+	case childRemoteState == nil:
+		return parentRemoteState, nil
+	case strategy == ShallowMerge || childRemoteState.Backend != parentRemoteState.Backend:
+		return childRemoteState, nil
+	}
+
+	merged := *childRemoteState
+	config, err := deepMergeInputs(childRemoteState.Config, parentRemoteState.Config)
+	if err != nil {
+		return nil, err
+	}
+	merged.Config = config
+	if childRemoteState.Encryption != nil || parentRemoteState.Encryption != nil {
+		encryption, err := deepMergeInputs(childRemoteState.Encryption, parentRemoteState.Encryption)
+		if err != nil {
+			return nil, err
+		}
+		merged.Encryption = encryption
+	}
+	return &merged, nil
+}
+
 // handleIncludePartial merges the a partially parsed include config into the child config according to the strategy
 // specified by the user.
 func handleIncludePartial(
@@ -176,6 +327,7 @@ func handleIncludePartial(
 		switch mergeStrategy {
 		case NoMerge:
 			terragruntOptions.Logger.Debugf("[Partial] Included config %s has strategy no merge: not merging config in.", includeConfig.Path)
+			baseConfig = mergePropagatedHooks(terragruntOptions, parsedIncludeConfig, baseConfig)
 		case ShallowMerge:
 			terragruntOptions.Logger.Debugf("[Partial] Included config %s has strategy shallow merge: merging config in (shallow).", includeConfig.Path)
 			if err := parsedIncludeConfig.Merge(baseConfig, terragruntOptions); err != nil {
@@ -192,6 +344,8 @@ func handleIncludePartial(
 			return nil, fmt.Errorf("You reached an impossible condition. This is most likely a bug in terragrunt. Please open an issue at github.com/gruntwork-io/terragrunt with this error message. Code: UNKNOWN_MERGE_STRATEGY_%s_PARTIAL", mergeStrategy)
 		}
 	}
+	removeSkippedPropagatedHooks(terragruntOptions, baseConfig)
+	excludeDisabledDependencyPaths(baseConfig)
 	return baseConfig, nil
 }
 
@@ -211,6 +365,7 @@ func handleIncludeForDependency(
 	// those in earlier includes, so we need to merge bottom up instead of top down to ensure this.
 	includeList := trackInclude.CurrentList
 	baseDependencyBlock := childDecodedDependency.Dependencies
+	strictMockOutputs := childDecodedDependency.StrictMockOutputs
 	for i := len(includeList) - 1; i >= 0; i-- {
 		includeConfig := includeList[i]
 		mergeStrategy, err := includeConfig.GetMergeStrategy()
@@ -223,6 +378,11 @@ func handleIncludeForDependency(
 			return nil, err
 		}
 
+		// The child config, and then the bottom most includes, take precedence.
+		if mergeStrategy != NoMerge && strictMockOutputs == nil {
+			strictMockOutputs = includedPartialParse.StrictMockOutputs
+		}
+
 		switch mergeStrategy {
 		case NoMerge:
 			terragruntOptions.Logger.Debugf("Included config %s has strategy no merge: not merging config in for dependency.", includeConfig.Path)
@@ -241,7 +401,7 @@ func handleIncludeForDependency(
 			return nil, fmt.Errorf("You reached an impossible condition. This is most likely a bug in terragrunt. Please open an issue at github.com/gruntwork-io/terragrunt with this error message. Code: UNKNOWN_MERGE_STRATEGY_%s_DEPENDENCY", mergeStrategy)
 		}
 	}
-	return &terragruntDependency{Dependencies: baseDependencyBlock}, nil
+	return &terragruntDependency{Dependencies: baseDependencyBlock, StrictMockOutputs: strictMockOutputs}, nil
 }
 
 // Merge performs a shallow merge of the given sourceConfig into the targetConfig. sourceConfig will override common
@@ -259,14 +419,24 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.DownloadDir = sourceConfig.DownloadDir
 	}
 
-	if sourceConfig.IamRole != "" {
+	// A map of roles by terraform command replaces the role of the target even when it has no role for the command.
+	if sourceConfig.IamRole != "" || sourceConfig.IamRoles != nil {
 		targetConfig.IamRole = sourceConfig.IamRole
+		targetConfig.IamRoles = sourceConfig.IamRoles
 	}
 
 	if sourceConfig.IamAssumeRoleDuration != nil {
 		targetConfig.IamAssumeRoleDuration = sourceConfig.IamAssumeRoleDuration
 	}
 
+	if sourceConfig.IamMfaSerial != "" {
+		targetConfig.IamMfaSerial = sourceConfig.IamMfaSerial
+	}
+
+	if sourceConfig.IamWebIdentityToken != "" {
+		targetConfig.IamWebIdentityToken = sourceConfig.IamWebIdentityToken
+	}
+
 	if sourceConfig.TerraformVersionConstraint != "" {
 		targetConfig.TerraformVersionConstraint = sourceConfig.TerraformVersionConstraint
 	}
@@ -279,6 +449,36 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.PreventDestroy = sourceConfig.PreventDestroy
 	}
 
+	if sourceConfig.Priority != nil {
+		targetConfig.Priority = sourceConfig.Priority
+	}
+
+	if sourceConfig.Tags != nil {
+		targetConfig.Tags = sourceConfig.Tags
+	}
+
+	if sourceConfig.StrictMockOutputs != nil {
+		targetConfig.StrictMockOutputs = sourceConfig.StrictMockOutputs
+	}
+
+	if sourceConfig.Exclude != nil {
+		targetConfig.Exclude = sourceConfig.Exclude
+	}
+
+	targetConfig.HookSets = mergeHookSets(sourceConfig.HookSets, targetConfig.HookSets)
+
+	if sourceConfig.UseHooks != nil {
+		targetConfig.UseHooks = sourceConfig.UseHooks
+	}
+
+	if sourceConfig.SkipPropagatedHooks != nil {
+		targetConfig.SkipPropagatedHooks = sourceConfig.SkipPropagatedHooks
+	}
+
+	targetConfig.CustomCommands = mergeCustomCommands(sourceConfig.CustomCommands, targetConfig.CustomCommands)
+
+	targetConfig.Validations = append(targetConfig.Validations, sourceConfig.Validations...)
+
 	if sourceConfig.RetryMaxAttempts != nil {
 		targetConfig.RetryMaxAttempts = sourceConfig.RetryMaxAttempts
 	}
@@ -304,12 +504,21 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		} else {
 			if sourceConfig.Terraform.Source != nil {
 				targetConfig.Terraform.Source = sourceConfig.Terraform.Source
+				targetConfig.Terraform.SourceMirrors = sourceConfig.Terraform.SourceMirrors
+			}
+
+			if sourceConfig.Terraform.Timeout != nil {
+				targetConfig.Terraform.Timeout = sourceConfig.Terraform.Timeout
 			}
+
 			mergeExtraArgs(terragruntOptions, sourceConfig.Terraform.ExtraArgs, &targetConfig.Terraform.ExtraArgs)
 
 			mergeHooks(terragruntOptions, sourceConfig.Terraform.BeforeHooks, &targetConfig.Terraform.BeforeHooks)
 			mergeHooks(terragruntOptions, sourceConfig.Terraform.AfterHooks, &targetConfig.Terraform.AfterHooks)
 			mergeErrorHooks(terragruntOptions, sourceConfig.Terraform.ErrorHooks, &targetConfig.Terraform.ErrorHooks)
+			mergeHooks(terragruntOptions, sourceConfig.Terraform.OnSuccessHooks, &targetConfig.Terraform.OnSuccessHooks)
+			mergeHooks(terragruntOptions, sourceConfig.Terraform.OnFailureHooks, &targetConfig.Terraform.OnFailureHooks)
+			mergeHooks(terragruntOptions, sourceConfig.Terraform.OnSkipHooks, &targetConfig.Terraform.OnSkipHooks)
 		}
 	}
 
@@ -330,6 +539,9 @@ func (targetConfig *TerragruntConfig) Merge(sourceConfig *TerragruntConfig, terr
 		targetConfig.RetryableErrors = sourceConfig.RetryableErrors
 	}
 
+	// Retry blocks are merged by name, with the child overriding the parent
+	targetConfig.RetryConfigs = mergeRetryConfigs(terragruntOptions, sourceConfig.RetryConfigs, targetConfig.RetryConfigs)
+
 	// Merge the generate configs. This is a shallow merge. Meaning, if the child has the same name generate block, then the
 	// child's generate block will override the parent's block.
 
@@ -371,14 +583,24 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.DownloadDir = sourceConfig.DownloadDir
 	}
 
-	if sourceConfig.IamRole != "" {
+	// A map of roles by terraform command replaces the role of the target even when it has no role for the command.
+	if sourceConfig.IamRole != "" || sourceConfig.IamRoles != nil {
 		targetConfig.IamRole = sourceConfig.IamRole
+		targetConfig.IamRoles = sourceConfig.IamRoles
 	}
 
 	if sourceConfig.IamAssumeRoleDuration != nil {
 		targetConfig.IamAssumeRoleDuration = sourceConfig.IamAssumeRoleDuration
 	}
 
+	if sourceConfig.IamMfaSerial != "" {
+		targetConfig.IamMfaSerial = sourceConfig.IamMfaSerial
+	}
+
+	if sourceConfig.IamWebIdentityToken != "" {
+		targetConfig.IamWebIdentityToken = sourceConfig.IamWebIdentityToken
+	}
+
 	if sourceConfig.TerraformVersionConstraint != "" {
 		targetConfig.TerraformVersionConstraint = sourceConfig.TerraformVersionConstraint
 	}
@@ -391,6 +613,44 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.PreventDestroy = sourceConfig.PreventDestroy
 	}
 
+	if sourceConfig.Priority != nil {
+		targetConfig.Priority = sourceConfig.Priority
+	}
+
+	// The tags of the child are added to the ones of the parent.
+	for _, tag := range sourceConfig.Tags {
+		if !util.ListContainsElement(targetConfig.Tags, tag) {
+			targetConfig.Tags = append(targetConfig.Tags, tag)
+		}
+	}
+
+	if sourceConfig.StrictMockOutputs != nil {
+		targetConfig.StrictMockOutputs = sourceConfig.StrictMockOutputs
+	}
+
+	if sourceConfig.Exclude != nil {
+		targetConfig.Exclude = sourceConfig.Exclude
+	}
+
+	targetConfig.HookSets = mergeHookSets(sourceConfig.HookSets, targetConfig.HookSets)
+
+	// The hook sets used by the child are added to the ones used by the parent.
+	for _, name := range sourceConfig.UseHooks {
+		if !util.ListContainsElement(targetConfig.UseHooks, name) {
+			targetConfig.UseHooks = append(targetConfig.UseHooks, name)
+		}
+	}
+
+	for _, name := range sourceConfig.SkipPropagatedHooks {
+		if !util.ListContainsElement(targetConfig.SkipPropagatedHooks, name) {
+			targetConfig.SkipPropagatedHooks = append(targetConfig.SkipPropagatedHooks, name)
+		}
+	}
+
+	targetConfig.CustomCommands = mergeCustomCommands(sourceConfig.CustomCommands, targetConfig.CustomCommands)
+
+	targetConfig.Validations = append(targetConfig.Validations, sourceConfig.Validations...)
+
 	if sourceConfig.RetryMaxAttempts != nil {
 		targetConfig.RetryMaxAttempts = sourceConfig.RetryMaxAttempts
 	}
@@ -450,6 +710,8 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		targetConfig.RetryableErrors = append(targetConfig.RetryableErrors, sourceConfig.RetryableErrors...)
 	}
 
+	targetConfig.RetryConfigs = mergeRetryConfigs(terragruntOptions, sourceConfig.RetryConfigs, targetConfig.RetryConfigs)
+
 	// Handle complex structs by recursively merging the structs together
 	if sourceConfig.Terraform != nil {
 		if targetConfig.Terraform == nil {
@@ -457,6 +719,11 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 		} else {
 			if sourceConfig.Terraform.Source != nil {
 				targetConfig.Terraform.Source = sourceConfig.Terraform.Source
+				targetConfig.Terraform.SourceMirrors = sourceConfig.Terraform.SourceMirrors
+			}
+
+			if sourceConfig.Terraform.Timeout != nil {
+				targetConfig.Terraform.Timeout = sourceConfig.Terraform.Timeout
 			}
 
 			if sourceConfig.Terraform.IncludeInCopy != nil {
@@ -475,6 +742,9 @@ func (targetConfig *TerragruntConfig) DeepMerge(sourceConfig *TerragruntConfig,
 			mergeHooks(terragruntOptions, sourceConfig.Terraform.BeforeHooks, &targetConfig.Terraform.BeforeHooks)
 			mergeHooks(terragruntOptions, sourceConfig.Terraform.AfterHooks, &targetConfig.Terraform.AfterHooks)
 			mergeErrorHooks(terragruntOptions, sourceConfig.Terraform.ErrorHooks, &targetConfig.Terraform.ErrorHooks)
+			mergeHooks(terragruntOptions, sourceConfig.Terraform.OnSuccessHooks, &targetConfig.Terraform.OnSuccessHooks)
+			mergeHooks(terragruntOptions, sourceConfig.Terraform.OnFailureHooks, &targetConfig.Terraform.OnFailureHooks)
+			mergeHooks(terragruntOptions, sourceConfig.Terraform.OnSkipHooks, &targetConfig.Terraform.OnSkipHooks)
 		}
 	}
 
@@ -507,6 +777,10 @@ func fetchDependencyPaths(config *TerragruntConfig) map[string]string {
 		return m
 	}
 	for _, dependency := range config.TerragruntDependencies {
+		// The dependencies in other repositories are not module dependencies.
+		if dependency.isRemote() {
+			continue
+		}
 		m[dependency.Name] = dependency.ConfigPath
 	}
 	return m
@@ -619,6 +893,22 @@ func mergeInputs(childInputs map[string]interface{}, parentInputs map[string]int
 	return out
 }
 
+// copyInputs returns a deep copy of the given inputs, which deep merges modify.
+func copyInputs(inputs map[string]interface{}) (map[string]interface{}, error) {
+	if inputs == nil {
+		return nil, nil
+	}
+	inputsJSON, err := json.Marshal(inputs)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	copied := map[string]interface{}{}
+	if err := json.Unmarshal(inputsJSON, &copied); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return copied, nil
+}
+
 func deepMergeInputs(childInputs map[string]interface{}, parentInputs map[string]interface{}) (map[string]interface{}, error) {
 	out := map[string]interface{}{}
 	for key, value := range parentInputs {
@@ -632,23 +922,36 @@ func deepMergeInputs(childInputs map[string]interface{}, parentInputs map[string
 // Merge the hooks (before_hook and after_hook).
 //
 // If a child's hook (before_hook or after_hook) has the same name a parent's hook,
-// then the child's hook will be selected (and the parent's ignored)
+// then the child's merge attribute selects how the two are merged:
+//   - override (the default): the child's hook will be selected (and the parent's ignored)
+//   - append: both are kept, and the child's hook runs right after the parent's
+//   - prepend: both are kept, and the child's hook runs right before the parent's
+//
 // If a child's hook has a different name from all of the parent's hooks,
 // then the child's hook will be added to the end of the parent's.
-// Therefore, the child with the same name overrides the parent
 func mergeHooks(terragruntOptions *options.TerragruntOptions, childHooks []Hook, parentHooks *[]Hook) {
 	result := *parentHooks
 	for _, child := range childHooks {
 		parentHookWithSameName := getIndexOfHookWithName(result, child.Name)
-		if parentHookWithSameName != -1 {
+		if parentHookWithSameName == -1 {
+			// If the parent does not contain a hook with the same name as the child
+			// then add the child to the end.
+			result = append(result, child)
+			continue
+		}
+
+		switch hookMergeStrategy(child.Merge) {
+		case HookMergeAppend:
+			terragruntOptions.Logger.Debugf("hook '%v' from child appended to the parent's", child.Name)
+			result = slices.Insert(result, parentHookWithSameName+1, child)
+		case HookMergePrepend:
+			terragruntOptions.Logger.Debugf("hook '%v' from child prepended to the parent's", child.Name)
+			result = slices.Insert(result, parentHookWithSameName, child)
+		default:
 			// If the parent contains a hook with the same name as the child,
 			// then override the parent's hook with the child's.
 			terragruntOptions.Logger.Debugf("hook '%v' from child overriding parent", child.Name)
 			result[parentHookWithSameName] = child
-		} else {
-			// If the parent does not contain a hook with the same name as the child
-			// then add the child to the end.
-			result = append(result, child)
 		}
 	}
 	*parentHooks = result
@@ -661,20 +964,56 @@ func mergeErrorHooks(terragruntOptions *options.TerragruntOptions, childHooks []
 	result := *parentHooks
 	for _, child := range childHooks {
 		parentHookWithSameName := getIndexOfErrorHookWithName(result, child.Name)
-		if parentHookWithSameName != -1 {
+		if parentHookWithSameName == -1 {
+			// If the parent does not contain a hook with the same name as the child
+			// then add the child to the end.
+			result = append(result, child)
+			continue
+		}
+
+		switch hookMergeStrategy(child.Merge) {
+		case HookMergeAppend:
+			terragruntOptions.Logger.Debugf("hook '%v' from child appended to the parent's", child.Name)
+			result = slices.Insert(result, parentHookWithSameName+1, child)
+		case HookMergePrepend:
+			terragruntOptions.Logger.Debugf("hook '%v' from child prepended to the parent's", child.Name)
+			result = slices.Insert(result, parentHookWithSameName, child)
+		default:
 			// If the parent contains a hook with the same name as the child,
 			// then override the parent's hook with the child's.
 			terragruntOptions.Logger.Debugf("hook '%v' from child overriding parent", child.Name)
 			result[parentHookWithSameName] = child
-		} else {
-			// If the parent does not contain a hook with the same name as the child
-			// then add the child to the end.
-			result = append(result, child)
 		}
 	}
 	*parentHooks = result
 }
 
+// Merge the retry blocks.
+//
+// If a child's retry block has the same name as a parent's retry block, then the child's block replaces the parent's
+// one in place. Otherwise the child's block is added to the end of the parent's blocks.
+func mergeRetryConfigs(terragruntOptions *options.TerragruntOptions, childRetryConfigs []RetryConfig, parentRetryConfigs []RetryConfig) []RetryConfig {
+	result := append([]RetryConfig{}, parentRetryConfigs...)
+	for _, child := range childRetryConfigs {
+		found := false
+		for i, parent := range result {
+			if parent.Name == child.Name {
+				terragruntOptions.Logger.Debugf("retry '%v' from child overriding parent", child.Name)
+				result[i] = child
+				found = true
+				break
+			}
+		}
+		if !found {
+			result = append(result, child)
+		}
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
 // getTrackInclude converts the terragrunt include blocks into TrackInclude structs that differentiate between an
 // included config in the current parsing context, and an included config that was passed through from a previous
 // parsing context.
@@ -726,7 +1065,8 @@ func getTrackInclude(
 //
 // Returns the updated contents, a boolean indicated whether anything changed, and an error (if any).
 func updateBareIncludeBlock(file *hcl.File, filename string) ([]byte, bool, error) {
-	if filepath.Ext(filename) == ".json" {
+	// The configs written in the YAML front-end are parsed from their conversion to the JSON syntax.
+	if filepath.Ext(filename) == ".json" || isYamlConfig(filename) {
 		return updateBareIncludeBlockJSON(file.Bytes)
 	}
 
@@ -900,6 +1240,22 @@ func (err MultipleBareIncludeBlocksErr) Error() string {
 	return "Multiple bare include blocks (include blocks without label) is not supported."
 }
 
+// IncludedConfigError is returned when the config included by another one can't be parsed. It has the same message as
+// the error of the included config, and only records the config that included it to show the include chain.
+type IncludedConfigError struct {
+	ConfigPath  string
+	IncludePath string
+	Err         error
+}
+
+func (err IncludedConfigError) Error() string {
+	return err.Err.Error()
+}
+
+func (err IncludedConfigError) Unwrap() error {
+	return err.Err
+}
+
 type IncludeIsNotABlockErr struct {
 	parsed interface{}
 }
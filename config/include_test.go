@@ -3,6 +3,7 @@ package config
 import (
 	"testing"
 
+	"github.com/gruntwork-io/terragrunt/codegen"
 	"github.com/gruntwork-io/terragrunt/remote"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -106,6 +107,26 @@ func TestMergeConfigIntoIncludedConfig(t *testing.T) {
 			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "overrideWithEmptyHooks", Commands: []string{"parent-apply"}}}}},
 			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "overrideWithEmptyHooks"}}}},
 		},
+		{
+			&TerragruntConfig{Terraform: &TerraformConfig{BeforeHooks: []Hook{Hook{Name: "lint", Commands: []string{"child-apply"}, Merge: ptr(HookMergeAppend)}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{BeforeHooks: []Hook{Hook{Name: "lint", Commands: []string{"parent-apply"}}, Hook{Name: "parentHooks"}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{BeforeHooks: []Hook{Hook{Name: "lint", Commands: []string{"parent-apply"}}, Hook{Name: "lint", Commands: []string{"child-apply"}, Merge: ptr(HookMergeAppend)}, Hook{Name: "parentHooks"}}}},
+		},
+		{
+			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "lint", Commands: []string{"child-apply"}, Merge: ptr(HookMergePrepend)}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "parentHooks"}, Hook{Name: "lint", Commands: []string{"parent-apply"}}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "parentHooks"}, Hook{Name: "lint", Commands: []string{"child-apply"}, Merge: ptr(HookMergePrepend)}, Hook{Name: "lint", Commands: []string{"parent-apply"}}}}},
+		},
+		{
+			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "lint", Commands: []string{"child-apply"}, Merge: ptr(HookMergeOverride)}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "lint", Commands: []string{"parent-apply"}}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{AfterHooks: []Hook{Hook{Name: "lint", Commands: []string{"child-apply"}, Merge: ptr(HookMergeOverride)}}}},
+		},
+		{
+			&TerragruntConfig{Terraform: &TerraformConfig{ErrorHooks: []ErrorHook{ErrorHook{Name: "notify", Commands: []string{"child-apply"}, Merge: ptr(HookMergeAppend)}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{ErrorHooks: []ErrorHook{ErrorHook{Name: "notify", Commands: []string{"parent-apply"}}}}},
+			&TerragruntConfig{Terraform: &TerraformConfig{ErrorHooks: []ErrorHook{ErrorHook{Name: "notify", Commands: []string{"parent-apply"}}, ErrorHook{Name: "notify", Commands: []string{"child-apply"}, Merge: ptr(HookMergeAppend)}}}},
+		},
 		{
 			&TerragruntConfig{},
 			&TerragruntConfig{Skip: true},
@@ -126,6 +147,31 @@ func TestMergeConfigIntoIncludedConfig(t *testing.T) {
 			&TerragruntConfig{IamRole: "role1"},
 			&TerragruntConfig{IamRole: "role2"},
 		},
+		{
+			&TerragruntConfig{IamRole: "", IamRoles: map[string]string{"plan": "read-only"}},
+			&TerragruntConfig{IamRole: "role1"},
+			&TerragruntConfig{IamRole: "", IamRoles: map[string]string{"plan": "read-only"}},
+		},
+		{
+			&TerragruntConfig{IamRole: "role2"},
+			&TerragruntConfig{IamRole: "read-only", IamRoles: map[string]string{"plan": "read-only"}},
+			&TerragruntConfig{IamRole: "role2"},
+		},
+		{
+			&TerragruntConfig{RetryConfigs: []RetryConfig{{Name: "network", RetryableErrors: []string{"child"}}, {Name: "throttling"}}},
+			&TerragruntConfig{RetryConfigs: []RetryConfig{{Name: "rate_limits"}, {Name: "network", RetryableErrors: []string{"parent"}}}},
+			&TerragruntConfig{RetryConfigs: []RetryConfig{{Name: "rate_limits"}, {Name: "network", RetryableErrors: []string{"child"}}, {Name: "throttling"}}},
+		},
+		{
+			&TerragruntConfig{CustomCommands: []CustomCommand{{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"apply"}}}}}},
+			&TerragruntConfig{CustomCommands: []CustomCommand{{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"plan"}}}}, {Name: "lint", Steps: []CustomCommandStep{{Execute: []string{"tflint"}}}}}},
+			&TerragruntConfig{CustomCommands: []CustomCommand{{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"apply"}}}}, {Name: "lint", Steps: []CustomCommandStep{{Execute: []string{"tflint"}}}}}},
+		},
+		{
+			&TerragruntConfig{Tags: []string{"app"}},
+			&TerragruntConfig{Tags: []string{"network", "prod"}},
+			&TerragruntConfig{Tags: []string{"app"}},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -263,6 +309,20 @@ func TestDeepMergeConfigIntoIncludedConfig(t *testing.T) {
 			&TerragruntConfig{RetryableErrors: []string{"original", "error"}},
 			&TerragruntConfig{RetryableErrors: []string{"original", "error", "error", "override"}},
 		},
+		// Deep merge custom commands
+		{
+			"custom commands",
+			&TerragruntConfig{CustomCommands: []CustomCommand{{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"apply"}}}}}},
+			&TerragruntConfig{CustomCommands: []CustomCommand{{Name: "lint", Steps: []CustomCommandStep{{Execute: []string{"tflint"}}}}}},
+			&TerragruntConfig{CustomCommands: []CustomCommand{{Name: "deploy", Steps: []CustomCommandStep{{Command: []string{"apply"}}}}, {Name: "lint", Steps: []CustomCommandStep{{Execute: []string{"tflint"}}}}}},
+		},
+		// Deep merge tags
+		{
+			"tags",
+			&TerragruntConfig{Tags: []string{"app", "prod"}},
+			&TerragruntConfig{Tags: []string{"prod"}},
+			&TerragruntConfig{Tags: []string{"prod", "app"}},
+		},
 		// Deep merge inputs
 		{
 			"inputs",
@@ -286,3 +346,195 @@ func TestDeepMergeConfigIntoIncludedConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestMergeAttributesWithOwnStrategy(t *testing.T) {
+	t.Parallel()
+
+	parentInputs := map[string]interface{}{"name": "parent", "tags": map[string]interface{}{"team": "infra", "env": "dev"}}
+	childInputs := map[string]interface{}{"tags": map[string]interface{}{"env": "prod"}}
+	parentErrors := []string{"parent"}
+	childErrors := []string{"child"}
+
+	testCases := []struct {
+		name           string
+		strategies     map[string]string
+		expectedInputs map[string]interface{}
+		expectedErrors []string
+	}{
+		{
+			"inputs deep",
+			map[string]string{"inputs": "deep"},
+			map[string]interface{}{"name": "parent", "tags": map[string]interface{}{"team": "infra", "env": "prod"}},
+			[]string{"merged"},
+		},
+		{
+			"inputs shallow",
+			map[string]string{"inputs": "shallow"},
+			map[string]interface{}{"name": "parent", "tags": map[string]interface{}{"env": "prod"}},
+			[]string{"merged"},
+		},
+		{
+			"inputs no merge",
+			map[string]string{"inputs": "no_merge"},
+			childInputs,
+			[]string{"merged"},
+		},
+		{
+			"retryable errors deep",
+			map[string]string{"retryable_errors": "deep"},
+			map[string]interface{}{"merged": true},
+			[]string{"parent", "child"},
+		},
+		{
+			"retryable errors shallow",
+			map[string]string{"retryable_errors": "shallow"},
+			map[string]interface{}{"merged": true},
+			[]string{"child"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			includeConfig := &IncludeConfig{Path: "../terragrunt.hcl", MergeStrategies: testCase.strategies}
+			childConfig := &TerragruntConfig{Inputs: childInputs, RetryableErrors: childErrors}
+			mergedConfig := &TerragruntConfig{Inputs: map[string]interface{}{"merged": true}, RetryableErrors: []string{"merged"}}
+
+			parent := parentAttributes{inputs: parentInputs, retryableErrors: parentErrors}
+			err := mergeAttributesWithOwnStrategy(includeConfig, mergedConfig, childConfig, parent, mockOptionsForTest(t))
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedInputs, mergedConfig.Inputs)
+			assert.Equal(t, testCase.expectedErrors, mergedConfig.RetryableErrors)
+		})
+	}
+}
+
+func TestMergeBlocksWithOwnStrategy(t *testing.T) {
+	t.Parallel()
+
+	parent := parentAttributes{
+		generateConfigs: map[string]codegen.GenerateConfig{
+			"provider": {Path: "provider.tf", Contents: "parent"},
+			"backend":  {Path: "backend.tf", Contents: "parent"},
+		},
+		remoteState: &remote.RemoteState{
+			Backend: "s3",
+			Config:  map[string]interface{}{"bucket": "parent", "tags": map[string]interface{}{"team": "infra"}},
+		},
+	}
+	childGenerateConfigs := map[string]codegen.GenerateConfig{"provider": {Path: "provider.tf", Contents: "child"}}
+	childRemoteState := &remote.RemoteState{
+		Backend: "s3",
+		Config:  map[string]interface{}{"key": "child", "tags": map[string]interface{}{"env": "prod"}},
+	}
+
+	testCases := []struct {
+		name                    string
+		strategies              map[string]string
+		childRemoteState        *remote.RemoteState
+		expectedGenerateConfigs map[string]codegen.GenerateConfig
+		expectedRemoteState     *remote.RemoteState
+	}{
+		{
+			"generate shallow",
+			map[string]string{"generate": "shallow"},
+			childRemoteState,
+			map[string]codegen.GenerateConfig{
+				"provider": {Path: "provider.tf", Contents: "child"},
+				"backend":  {Path: "backend.tf", Contents: "parent"},
+			},
+			nil,
+		},
+		{
+			"generate no merge",
+			map[string]string{"generate": "no_merge"},
+			childRemoteState,
+			childGenerateConfigs,
+			nil,
+		},
+		{
+			"remote state deep",
+			map[string]string{"remote_state": "deep"},
+			childRemoteState,
+			nil,
+			&remote.RemoteState{
+				Backend: "s3",
+				Config:  map[string]interface{}{"bucket": "parent", "key": "child", "tags": map[string]interface{}{"team": "infra", "env": "prod"}},
+			},
+		},
+		{
+			"remote state deep with other backend",
+			map[string]string{"remote_state": "deep"},
+			&remote.RemoteState{Backend: "gcs", Config: map[string]interface{}{"bucket": "child"}},
+			nil,
+			&remote.RemoteState{Backend: "gcs", Config: map[string]interface{}{"bucket": "child"}},
+		},
+		{
+			"remote state shallow",
+			map[string]string{"remote_state": "shallow"},
+			childRemoteState,
+			nil,
+			childRemoteState,
+		},
+		{
+			"remote state shallow without child",
+			map[string]string{"remote_state": "shallow"},
+			nil,
+			nil,
+			parent.remoteState,
+		},
+		{
+			"remote state no merge without child",
+			map[string]string{"remote_state": "no_merge"},
+			nil,
+			nil,
+			nil,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			includeConfig := &IncludeConfig{Path: "../terragrunt.hcl", MergeStrategies: testCase.strategies}
+			childConfig := &TerragruntConfig{GenerateConfigs: childGenerateConfigs, RemoteState: testCase.childRemoteState}
+			mergedConfig := &TerragruntConfig{}
+
+			err := mergeAttributesWithOwnStrategy(includeConfig, mergedConfig, childConfig, parent, mockOptionsForTest(t))
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expectedGenerateConfigs, mergedConfig.GenerateConfigs)
+			assert.Equal(t, testCase.expectedRemoteState, mergedConfig.RemoteState)
+		})
+	}
+}
+
+func TestGetAttributeMergeStrategy(t *testing.T) {
+	t.Parallel()
+
+	deep := string(DeepMerge)
+	includeConfig := &IncludeConfig{MergeStrategy: &deep, MergeStrategies: map[string]string{"inputs": "shallow"}}
+
+	strategy, err := includeConfig.GetAttributeMergeStrategy("inputs")
+	require.NoError(t, err)
+	assert.Equal(t, ShallowMerge, strategy)
+
+	strategy, err = includeConfig.GetAttributeMergeStrategy("retryable_errors")
+	require.NoError(t, err)
+	assert.Equal(t, DeepMerge, strategy)
+
+	includeConfig.MergeStrategies = map[string]string{"remote_state": "no_merge"}
+	strategy, err = includeConfig.GetAttributeMergeStrategy("remote_state")
+	require.NoError(t, err)
+	assert.Equal(t, NoMerge, strategy)
+
+	includeConfig.MergeStrategies = map[string]string{"terraform": "deep"}
+	_, err = includeConfig.GetAttributeMergeStrategy("terraform")
+	assert.Error(t, err)
+
+	includeConfig.MergeStrategies = map[string]string{"inputs": "deep_map_only"}
+	_, err = includeConfig.GetAttributeMergeStrategy("inputs")
+	assert.Error(t, err)
+}
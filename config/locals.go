@@ -48,6 +48,9 @@ func evaluateLocalsBlock(
 	hclFile *hcl.File,
 	filename string,
 	trackInclude *TrackInclude,
+	features *cty.Value,
+	variables *cty.Value,
+	imports *cty.Value,
 	decodeList []PartialDecodeSectionType,
 ) (map[string]cty.Value, error) {
 	diagsWriter := util.GetDiagnosticsWriter(terragruntOptions.Logger, parser)
@@ -96,6 +99,9 @@ func evaluateLocalsBlock(
 			locals,
 			evaluatedLocals,
 			trackInclude,
+			features,
+			variables,
+			imports,
 			decodeList,
 			diagsWriter,
 		)
@@ -129,6 +135,9 @@ func attemptEvaluateLocals(
 	locals []*Local,
 	evaluatedLocals map[string]cty.Value,
 	trackInclude *TrackInclude,
+	features *cty.Value,
+	variables *cty.Value,
+	imports *cty.Value,
 	decodeList []PartialDecodeSectionType,
 	diagsWriter hcl.DiagnosticWriter,
 ) (unevaluatedLocals []*Local, newEvaluatedLocals map[string]cty.Value, evaluated bool, err error) {
@@ -154,6 +163,9 @@ func attemptEvaluateLocals(
 	extensions := EvalContextExtensions{
 		TrackInclude:           trackInclude,
 		Locals:                 &evaluatedLocalsAsCty,
+		Features:               features,
+		Variables:              variables,
+		Imports:                imports,
 		PartialParseDecodeList: decodeList,
 	}
 
@@ -224,8 +236,8 @@ func canEvaluate(expression hcl.Expression,
 
 		rootName := var_.RootName()
 
-		// If the variable is `include`, then we can evaluate it now
-		if rootName == "include" {
+		// If the variable is `include`, `feature`, `var` or `import`, then we can evaluate it now
+		if rootName == "include" || rootName == "feature" || rootName == "var" || rootName == "import" {
 			continue
 		}
 
@@ -0,0 +1,456 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	ctyjson "github.com/zclconf/go-cty/cty/json"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The folder, in the .terragrunt-cache folder of a config, where the partial parses of the config are cached with
+// --terragrunt-persistent-parse-config-cache.
+const persistentConfigCacheDir = "parsed-configs"
+
+// The version of the format of the cache entries, which is part of their key, so that the entries written by another
+// version of the format are ignored.
+const persistentConfigCacheFormatVersion = 9
+
+// persistentlyCacheableSections are the sections of a partial parse that can be cached on disk, as they are decoded
+// into plain data. The sections with hooks or backend configs are always parsed.
+var persistentlyCacheableSections = []PartialDecodeSectionType{
+	DependenciesBlock,
+	DependencyBlock,
+	TerraformSource,
+	TerragruntFlags,
+	TerragruntVersionConstraints,
+	TerragruntPriority,
+	TerragruntTags,
+}
+
+// persistentlyCacheableFunctions are the functions whose result only depends on their arguments, on the paths of the
+// configs, or on the env vars, which are tracked. A config calling any other function, such as run_cmd or a function
+// reading files, is never cached on disk, as its result can't be tracked.
+var persistentlyCacheableFunctions = []string{
+	FuncNameFindInParentFolders,
+	FuncNamePathRelativeToInclude,
+	FuncNamePathRelativeFromInclude,
+	FuncNameGetEnv,
+	FuncNameGetPlatform,
+	FuncNameGetRepoRoot,
+	FuncNameGetPathFromRepoRoot,
+	FuncNameGetPathToRepoRoot,
+	FuncNameGetTerragruntDir,
+	FuncNameGetOriginalTerragruntDir,
+	FuncNameGetParentTerragruntDir,
+	FuncNameGetTerraformCommandsThatNeedVars,
+	FuncNameGetTerraformCommandsThatNeedLocking,
+	FuncNameGetTerraformCommandsThatNeedInput,
+	FuncNameGetTerraformCommandsThatNeedParallelism,
+	FuncNameGetDefaultRetryableErrors,
+	FuncNameStartsWith,
+	FuncNameEndsWith,
+	FuncNameStrContains,
+	// The functions of terraform that only depend on their arguments.
+	"abs", "ceil", "floor", "log", "max", "min", "parseint", "pow", "signum",
+	"chomp", "format", "formatlist", "indent", "join", "lower", "regex", "regexall", "replace", "split", "strrev",
+	"substr", "title", "trim", "trimprefix", "trimsuffix", "trimspace", "upper",
+	"alltrue", "anytrue", "chunklist", "coalesce", "coalescelist", "compact", "concat", "contains", "distinct",
+	"element", "flatten", "index", "keys", "length", "lookup", "matchkeys", "merge", "one", "range", "reverse",
+	"setintersection", "setproduct", "setsubtract", "setunion", "slice", "sort", "sum", "transpose", "values", "zipmap",
+	"base64decode", "base64encode", "csvdecode", "jsondecode", "jsonencode", "urlencode", "yamldecode", "yamlencode",
+	"cidrhost", "cidrnetmask", "cidrsubnet", "cidrsubnets",
+	"can", "try", "tobool", "tolist", "tomap", "tonumber", "toset", "tostring",
+	"md5", "sha1", "sha256", "sha512", "base64sha256", "base64sha512",
+}
+
+// persistentConfigCacheEntry is a partial parse cached on disk, along with the inputs of its evaluation: the content
+// hashes of the files it was parsed from, which are the config, its includes, its environment overlay and the variable
+// files, and the hashes of the values of the env vars read with get_env. The entry is only used if none of these
+// inputs changed.
+type persistentConfigCacheEntry struct {
+	FileHashes map[string]string   `json:"file_hashes"`
+	EnvHashes  map[string]string   `json:"env_hashes,omitempty"`
+	Config     cachedPartialConfig `json:"config"`
+}
+
+// cachedPartialConfig holds the fields of a TerragruntConfig that the persistently cacheable sections set. The locals of
+// a partial parse are converted from cty through json, so their numbers are float64 whether they are parsed or read from
+// the cache.
+type cachedPartialConfig struct {
+	TerraformSource             *string                `json:"terraform_source,omitempty"`
+	TerraformSourceMirrors      []string               `json:"terraform_source_mirrors,omitempty"`
+	TerraformBinary             string                 `json:"terraform_binary,omitempty"`
+	TerraformVersionConstraint  string                 `json:"terraform_version_constraint,omitempty"`
+	TerragruntVersionConstraint string                 `json:"terragrunt_version_constraint,omitempty"`
+	Dependencies                *ModuleDependencies    `json:"dependencies,omitempty"`
+	TerragruntDependencies      []cachedDependency     `json:"terragrunt_dependencies,omitempty"`
+	PreventDestroy              *bool                  `json:"prevent_destroy,omitempty"`
+	Skip                        bool                   `json:"skip,omitempty"`
+	IamRole                     string                 `json:"iam_role,omitempty"`
+	IamRoles                    map[string]string      `json:"iam_roles,omitempty"`
+	IamMfaSerial                string                 `json:"iam_mfa_serial,omitempty"`
+	Priority                    *int                   `json:"priority,omitempty"`
+	Tags                        []string               `json:"tags,omitempty"`
+	StrictMockOutputs           *bool                  `json:"strict_mock_outputs,omitempty"`
+	Locals                      map[string]interface{} `json:"locals,omitempty"`
+	ProcessedIncludes           IncludeConfigs         `json:"processed_includes,omitempty"`
+}
+
+// cachedDependency is a Dependency, with the mock outputs encoded as json along with their type, so that they are
+// decoded into the same cty value.
+type cachedDependency struct {
+	Name                                string             `json:"name"`
+	Enabled                             *bool              `json:"enabled,omitempty"`
+	ConfigPath                          string             `json:"config_path"`
+	Source                              *string            `json:"source,omitempty"`
+	SkipOutputs                         *bool              `json:"skip_outputs,omitempty"`
+	OrderingOnly                        *bool              `json:"ordering_only,omitempty"`
+	MockOutputs                         json.RawMessage    `json:"mock_outputs,omitempty"`
+	MockOutputsAllowedTerraformCommands *[]string          `json:"mock_outputs_allowed_terraform_commands,omitempty"`
+	MockOutputsMergeWithState           *bool              `json:"mock_outputs_merge_with_state,omitempty"`
+	MockOutputsMergeStrategyWithState   *MergeStrategyType `json:"mock_outputs_merge_strategy_with_state,omitempty"`
+}
+
+// isPersistentlyCacheable returns true if all the given sections can be cached on disk.
+func isPersistentlyCacheable(decodeList []PartialDecodeSectionType) bool {
+	for _, decode := range decodeList {
+		cacheable := false
+		for _, section := range persistentlyCacheableSections {
+			if decode == section {
+				cacheable = true
+			}
+		}
+		if !cacheable {
+			return false
+		}
+	}
+	return true
+}
+
+// partialParseConfigFileWithPersistentCache returns the partial parse of the given config cached in its
+// .terragrunt-cache folder if none of the files it was parsed from changed, and otherwise parses the config with the
+// given parse function and caches the result. Failing to read or write the cache is not an error, as the config can
+// still be parsed.
+func partialParseConfigFileWithPersistentCache(
+	filename string,
+	terragruntOptions *options.TerragruntOptions,
+	decodeList []PartialDecodeSectionType,
+	parse func() (*TerragruntConfig, error),
+) (*TerragruntConfig, error) {
+	cachePath := persistentConfigCachePath(filename, terragruntOptions, decodeList)
+
+	if config, found := readPersistentConfigCache(cachePath, terragruntOptions); found {
+		terragruntOptions.Logger.Debugf("Persistent cache hit for '%s' (partial parsing), decodeList: '%v'.", filename, decodeList)
+		// The cache is shared by the terraform commands, which select the role when iam_role is a map.
+		if config.IamRoles != nil {
+			config.IamRole = iamRoleForCommand(config.IamRoles, terragruntOptions.TerraformCommand)
+		}
+		return config, nil
+	}
+	terragruntOptions.Logger.Debugf("Persistent cache miss for '%s' (partial parsing), decodeList: '%v'.", filename, decodeList)
+
+	config, err := parse()
+	if err != nil {
+		return nil, err
+	}
+
+	// The token may be given inline, e.g. with get_env, which must not be written to disk, and it expires, so that a
+	// cached token would be reused after it expired.
+	if config.IamWebIdentityToken != "" {
+		terragruntOptions.Logger.Debugf("Not caching the partial parse of '%s', as it sets iam_web_identity_token.", filename)
+		return config, nil
+	}
+
+	if err := writePersistentConfigCache(cachePath, filename, terragruntOptions, config); err != nil {
+		terragruntOptions.Logger.Debugf("Failed to cache the partial parse of '%s' in %s: %v", filename, cachePath, err)
+	}
+	return config, nil
+}
+
+// persistentConfigCachePath returns the path of the cache entry of the partial parse of the given config with the
+// given sections. The environment selected with --terragrunt-env is part of the key, as it selects the overlay that is
+// merged in, and so are the values of the feature flags and variables passed in on the command line, which the config
+// can reference.
+func persistentConfigCachePath(filename string, terragruntOptions *options.TerragruntOptions, decodeList []PartialDecodeSectionType) string {
+	// The maps are printed sorted by key.
+	key := fmt.Sprintf("%d-%s-%s-%v-%v-%v", persistentConfigCacheFormatVersion, filename, terragruntOptions.ConfigEnv, decodeList, terragruntOptions.FeatureFlags, terragruntOptions.TerragruntVars)
+	return filepath.Join(filepath.Dir(filename), util.TerragruntCacheDir, persistentConfigCacheDir, fmt.Sprintf("%x.json", sha256.Sum256([]byte(key))))
+}
+
+// readPersistentConfigCache returns the config cached at the given path, if there is one and the files and the env vars
+// it was evaluated with didn't change since.
+func readPersistentConfigCache(cachePath string, terragruntOptions *options.TerragruntOptions) (*TerragruntConfig, bool) {
+	contents, err := os.ReadFile(cachePath)
+	if err != nil {
+		return nil, false
+	}
+	entry := persistentConfigCacheEntry{}
+	if err := json.Unmarshal(contents, &entry); err != nil {
+		return nil, false
+	}
+	for path, hash := range entry.FileHashes {
+		if fileContentHash(path) != hash {
+			return nil, false
+		}
+	}
+	for name, hash := range entry.EnvHashes {
+		if envValueHash(terragruntOptions.Env, name) != hash {
+			return nil, false
+		}
+	}
+	config, err := entry.Config.toTerragruntConfig()
+	if err != nil {
+		return nil, false
+	}
+	return config, true
+}
+
+// writePersistentConfigCache caches the given partial parse of the given config at the given path. The entry is
+// written to a temporary file that is then renamed, so that a concurrent read never sees a partial entry.
+func writePersistentConfigCache(cachePath string, filename string, terragruntOptions *options.TerragruntOptions, config *TerragruntConfig) error {
+	cached, err := newCachedPartialConfig(config)
+	if err != nil {
+		return err
+	}
+
+	files := []string{filename}
+	for _, include := range config.ProcessedIncludes {
+		includePath := include.Path
+		if !filepath.IsAbs(includePath) {
+			includePath = util.JoinPath(filepath.Dir(filename), includePath)
+		}
+		files = append(files, includePath)
+	}
+	if terragruntOptions.ConfigEnv != "" {
+		files = append(files, GetEnvOverlayConfigPath(filename, terragruntOptions.ConfigEnv))
+	}
+	fileHashes := map[string]string{}
+	envHashes := map[string]string{}
+	// The variable files only set plain values, which don't depend on other inputs.
+	for _, varFile := range terragruntOptions.TerragruntVarFiles {
+		absPath, err := util.CanonicalPath(varFile, terragruntOptions.WorkingDir)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		fileHashes[absPath] = fileContentHash(absPath)
+	}
+	for _, path := range files {
+		// The paths are absolute, so that the entry is valid whatever the working dir of the next run is.
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		fileHashes[absPath] = fileContentHash(absPath)
+
+		envVars, isCacheable := configEvaluationEnvVars(absPath)
+		if !isCacheable {
+			terragruntOptions.Logger.Debugf("Not caching the partial parse of '%s', as %s uses values that can't be tracked, such as the output of run_cmd or the contents of other files.", filename, absPath)
+			return nil
+		}
+		for _, name := range envVars {
+			envHashes[name] = envValueHash(terragruntOptions.Env, name)
+		}
+	}
+
+	contents, err := json.Marshal(persistentConfigCacheEntry{FileHashes: fileHashes, EnvHashes: envHashes, Config: *cached})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := util.EnsureDirectory(filepath.Dir(cachePath)); err != nil {
+		return err
+	}
+	tempFile, err := os.CreateTemp(filepath.Dir(cachePath), filepath.Base(cachePath)+".tmp")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(contents); err != nil {
+		tempFile.Close()
+		return errors.WithStackTrace(err)
+	}
+	if err := tempFile.Close(); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return errors.WithStackTrace(os.Rename(tempFile.Name(), cachePath))
+}
+
+// fileContentHash returns the sha256 of the contents of the given file, or an empty string if the file doesn't exist,
+// so that creating the file changes its hash too.
+func fileContentHash(path string) string {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256(contents))
+}
+
+// envValueHash returns the sha256 of the value of the given env var, which tells an unset env var from an empty one. The
+// values are hashed, so that secrets read from the env vars are not written to disk.
+func envValueHash(env map[string]string, name string) string {
+	value, isSet := env[name]
+	if !isSet {
+		return ""
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(value)))
+}
+
+// configEvaluationEnvVars returns the names of the env vars the given config file reads with get_env. It returns false
+// if the evaluation of the config depends on inputs that are not tracked by the cache: functions that are not
+// persistently cacheable, get_env calls whose name is not a literal, the outputs of the dependencies, and the configs
+// imported with import blocks. A file that doesn't exist has no inputs, as creating it changes its hash.
+func configEvaluationEnvVars(path string) ([]string, bool) {
+	if !util.FileExists(path) {
+		return nil, true
+	}
+	file, diags := hclparse.NewParser().ParseHCLFile(path)
+	if diags.HasErrors() {
+		return nil, false
+	}
+	body, isNativeSyntax := file.Body.(*hclsyntax.Body)
+	if !isNativeSyntax {
+		return nil, false
+	}
+
+	for _, traversal := range bodyTraversals(body) {
+		switch traversal.RootName() {
+		case "import":
+			return nil, false
+		case MetadataDependency:
+			if attributeName, ok := traversalStepName(traversal, 2); !ok || attributeName == "outputs" {
+				return nil, false
+			}
+		}
+	}
+
+	envVars := []string{}
+	isCacheable := true
+	hclsyntax.VisitAll(body, func(node hclsyntax.Node) hcl.Diagnostics {
+		call, isCall := node.(*hclsyntax.FunctionCallExpr)
+		if !isCall {
+			return nil
+		}
+		if !util.ListContainsElement(persistentlyCacheableFunctions, call.Name) {
+			isCacheable = false
+			return nil
+		}
+		if call.Name != FuncNameGetEnv {
+			return nil
+		}
+		if len(call.Args) == 0 {
+			isCacheable = false
+			return nil
+		}
+		name, diags := call.Args[0].Value(nil)
+		if diags.HasErrors() || !name.IsKnown() || name.IsNull() || name.Type() != cty.String {
+			isCacheable = false
+			return nil
+		}
+		envVars = append(envVars, name.AsString())
+		return nil
+	})
+
+	return envVars, isCacheable
+}
+
+// newCachedPartialConfig converts the given partial parse into its cached form.
+func newCachedPartialConfig(config *TerragruntConfig) (*cachedPartialConfig, error) {
+	cached := &cachedPartialConfig{
+		TerraformBinary:             config.TerraformBinary,
+		TerraformVersionConstraint:  config.TerraformVersionConstraint,
+		TerragruntVersionConstraint: config.TerragruntVersionConstraint,
+		Dependencies:                config.Dependencies,
+		PreventDestroy:              config.PreventDestroy,
+		Skip:                        config.Skip,
+		IamRole:                     config.IamRole,
+		IamRoles:                    config.IamRoles,
+		IamMfaSerial:                config.IamMfaSerial,
+		Priority:                    config.Priority,
+		Tags:                        config.Tags,
+		StrictMockOutputs:           config.StrictMockOutputs,
+		Locals:                      config.Locals,
+		ProcessedIncludes:           config.ProcessedIncludes,
+	}
+	if config.Terraform != nil {
+		cached.TerraformSource = config.Terraform.Source
+		cached.TerraformSourceMirrors = config.Terraform.SourceMirrors
+	}
+	for _, dependency := range config.TerragruntDependencies {
+		cachedDep := cachedDependency{
+			Name:                                dependency.Name,
+			Enabled:                             dependency.Enabled,
+			ConfigPath:                          dependency.ConfigPath,
+			Source:                              dependency.Source,
+			SkipOutputs:                         dependency.SkipOutputs,
+			OrderingOnly:                        dependency.OrderingOnly,
+			MockOutputsAllowedTerraformCommands: dependency.MockOutputsAllowedTerraformCommands,
+			MockOutputsMergeWithState:           dependency.MockOutputsMergeWithState,
+			MockOutputsMergeStrategyWithState:   dependency.MockOutputsMergeStrategyWithState,
+		}
+		if dependency.MockOutputs != nil {
+			mockOutputs, err := ctyjson.Marshal(*dependency.MockOutputs, cty.DynamicPseudoType)
+			if err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			cachedDep.MockOutputs = mockOutputs
+		}
+		cached.TerragruntDependencies = append(cached.TerragruntDependencies, cachedDep)
+	}
+	return cached, nil
+}
+
+// toTerragruntConfig converts the cached form of a partial parse back into the partial parse.
+func (cached cachedPartialConfig) toTerragruntConfig() (*TerragruntConfig, error) {
+	config := &TerragruntConfig{
+		TerraformBinary:             cached.TerraformBinary,
+		TerraformVersionConstraint:  cached.TerraformVersionConstraint,
+		TerragruntVersionConstraint: cached.TerragruntVersionConstraint,
+		Dependencies:                cached.Dependencies,
+		PreventDestroy:              cached.PreventDestroy,
+		Skip:                        cached.Skip,
+		IamRole:                     cached.IamRole,
+		IamRoles:                    cached.IamRoles,
+		IamMfaSerial:                cached.IamMfaSerial,
+		Priority:                    cached.Priority,
+		Tags:                        cached.Tags,
+		StrictMockOutputs:           cached.StrictMockOutputs,
+		Locals:                      cached.Locals,
+		ProcessedIncludes:           cached.ProcessedIncludes,
+		IsPartial:                   true,
+	}
+	if cached.TerraformSource != nil {
+		config.Terraform = &TerraformConfig{Source: cached.TerraformSource, SourceMirrors: cached.TerraformSourceMirrors}
+	}
+	for _, cachedDep := range cached.TerragruntDependencies {
+		dependency := Dependency{
+			Name:                                cachedDep.Name,
+			Enabled:                             cachedDep.Enabled,
+			ConfigPath:                          cachedDep.ConfigPath,
+			Source:                              cachedDep.Source,
+			SkipOutputs:                         cachedDep.SkipOutputs,
+			OrderingOnly:                        cachedDep.OrderingOnly,
+			MockOutputsAllowedTerraformCommands: cachedDep.MockOutputsAllowedTerraformCommands,
+			MockOutputsMergeWithState:           cachedDep.MockOutputsMergeWithState,
+			MockOutputsMergeStrategyWithState:   cachedDep.MockOutputsMergeStrategyWithState,
+		}
+		if cachedDep.MockOutputs != nil {
+			mockOutputs, err := ctyjson.Unmarshal(cachedDep.MockOutputs, cty.DynamicPseudoType)
+			if err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			dependency.MockOutputs = &mockOutputs
+		}
+		config.TerragruntDependencies = append(config.TerragruntDependencies, dependency)
+	}
+	return config, nil
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+var persistentCacheTestDecodeList = []PartialDecodeSectionType{TerraformSource, DependenciesBlock, DependencyBlock, TerragruntPriority}
+
+func writePersistentCacheTestConfigs(t *testing.T) (string, string) {
+	root := t.TempDir()
+	appDir := filepath.Join(root, "app")
+	require.NoError(t, os.MkdirAll(appDir, 0755))
+
+	rootConfigPath := filepath.Join(root, DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(rootConfigPath, []byte(`
+terraform {
+  source = "git::git@github.com:acme/modules.git//app?ref=v0.1.0"
+}
+`), 0644))
+
+	configPath := filepath.Join(appDir, DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+include "root" {
+  path = find_in_parent_folders()
+}
+
+priority = 2
+
+dependency "vpc" {
+  config_path = "../vpc"
+  mock_outputs = {
+    vpc_id  = "mock"
+    subnets = ["a", "b"]
+  }
+}
+`), 0644))
+
+	return rootConfigPath, configPath
+}
+
+func persistentCacheTestOptions(t *testing.T, configPath string) *options.TerragruntOptions {
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(configPath)
+	require.NoError(t, err)
+	terragruntOptions.UsePersistentParseConfigCache = true
+	return terragruntOptions
+}
+
+func TestPartialParseConfigFilePersistentCache(t *testing.T) {
+	t.Parallel()
+
+	_, configPath := writePersistentCacheTestConfigs(t)
+	terragruntOptions := persistentCacheTestOptions(t, configPath)
+
+	parsed, err := PartialParseConfigFile(configPath, terragruntOptions, nil, persistentCacheTestDecodeList)
+	require.NoError(t, err)
+
+	cachePath := persistentConfigCachePath(configPath, terragruntOptions, persistentCacheTestDecodeList)
+	assert.True(t, util.FileExists(cachePath))
+	assert.Equal(t, filepath.Join(filepath.Dir(configPath), util.TerragruntCacheDir, persistentConfigCacheDir), filepath.Dir(cachePath))
+
+	cached, err := PartialParseConfigFile(configPath, terragruntOptions, nil, persistentCacheTestDecodeList)
+	require.NoError(t, err)
+
+	assert.Equal(t, parsed.Terraform, cached.Terraform)
+	assert.Equal(t, parsed.Dependencies, cached.Dependencies)
+	assert.Equal(t, parsed.Priority, cached.Priority)
+	assert.Equal(t, parsed.ProcessedIncludes, cached.ProcessedIncludes)
+	assert.True(t, cached.IsPartial)
+	require.Len(t, cached.TerragruntDependencies, 1)
+	assert.Equal(t, "vpc", cached.TerragruntDependencies[0].Name)
+	assert.True(t, parsed.TerragruntDependencies[0].MockOutputs.Equals(*cached.TerragruntDependencies[0].MockOutputs).True())
+	assert.Equal(t, cty.StringVal("mock"), cached.TerragruntDependencies[0].MockOutputs.GetAttr("vpc_id"))
+}
+
+func TestPartialParseConfigFilePersistentCacheHit(t *testing.T) {
+	t.Parallel()
+
+	_, configPath := writePersistentCacheTestConfigs(t)
+	terragruntOptions := persistentCacheTestOptions(t, configPath)
+
+	_, err := PartialParseConfigFile(configPath, terragruntOptions, nil, persistentCacheTestDecodeList)
+	require.NoError(t, err)
+
+	// Tamper with the cache entry, to check that the config is read from it rather than parsed again.
+	cachePath := persistentConfigCachePath(configPath, terragruntOptions, persistentCacheTestDecodeList)
+	entry := persistentConfigCacheEntry{}
+	contents, err := os.ReadFile(cachePath)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(contents, &entry))
+	entry.Config.TerraformSource = ptr("from-cache")
+	contents, err = json.Marshal(entry)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(cachePath, contents, 0644))
+
+	cached, err := PartialParseConfigFile(configPath, terragruntOptions, nil, persistentCacheTestDecodeList)
+	require.NoError(t, err)
+	assert.Equal(t, "from-cache", *cached.Terraform.Source)
+}
+
+func TestPartialParseConfigFilePersistentCacheInvalidatedOnIncludeChange(t *testing.T) {
+	t.Parallel()
+
+	rootConfigPath, configPath := writePersistentCacheTestConfigs(t)
+	terragruntOptions := persistentCacheTestOptions(t, configPath)
+
+	_, err := PartialParseConfigFile(configPath, terragruntOptions, nil, persistentCacheTestDecodeList)
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(rootConfigPath, []byte(`
+terraform {
+  source = "git::git@github.com:acme/modules.git//app?ref=v0.2.0"
+}
+`), 0644))
+
+	parsed, err := PartialParseConfigFile(configPath, terragruntOptions, nil, persistentCacheTestDecodeList)
+	require.NoError(t, err)
+	assert.Equal(t, "git::git@github.com:acme/modules.git//app?ref=v0.2.0", *parsed.Terraform.Source)
+}
+
+func TestPartialParseConfigFilePersistentCacheSkipsUncacheableSections(t *testing.T) {
+	t.Parallel()
+
+	_, configPath := writePersistentCacheTestConfigs(t)
+	terragruntOptions := persistentCacheTestOptions(t, configPath)
+
+	decodeList := []PartialDecodeSectionType{TerraformBlock}
+	_, err := PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+	require.NoError(t, err)
+	assert.False(t, util.FileExists(persistentConfigCachePath(configPath, terragruntOptions, decodeList)))
+}
+
+func TestPartialParseConfigFilePersistentCacheSkipsWebIdentityToken(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+iam_role               = "arn:aws:iam::123456789012:role/ci"
+iam_web_identity_token = "eyJhbGciOiJSUzI1NiJ9.inline"
+`), 0644))
+	terragruntOptions := persistentCacheTestOptions(t, configPath)
+
+	decodeList := []PartialDecodeSectionType{TerragruntFlags}
+	parsed, err := PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+	require.NoError(t, err)
+	assert.Equal(t, "eyJhbGciOiJSUzI1NiJ9.inline", parsed.IamWebIdentityToken)
+	assert.False(t, util.FileExists(persistentConfigCachePath(configPath, terragruntOptions, decodeList)))
+}
+
+func TestPartialParseConfigFilePersistentCacheInvalidatedOnEnvChange(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+locals {
+  env = get_env("APP_ENV", "dev")
+}
+
+skip    = local.env == "prod"
+iam_role = "arn:aws:iam::123456789012:role/${local.env}"
+`), 0644))
+	terragruntOptions := persistentCacheTestOptions(t, configPath)
+	terragruntOptions.Env = map[string]string{"APP_ENV": "dev"}
+
+	decodeList := []PartialDecodeSectionType{TerragruntFlags}
+	parsed, err := PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+	require.NoError(t, err)
+	assert.False(t, parsed.Skip)
+	require.True(t, util.FileExists(persistentConfigCachePath(configPath, terragruntOptions, decodeList)))
+
+	terragruntOptions.Env = map[string]string{"APP_ENV": "prod"}
+	parsed, err = PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+	require.NoError(t, err)
+	assert.True(t, parsed.Skip)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/prod", parsed.IamRole)
+
+	// Unsetting the env var changes the value too.
+	terragruntOptions.Env = map[string]string{}
+	parsed, err = PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+	require.NoError(t, err)
+	assert.False(t, parsed.Skip)
+	assert.Equal(t, "arn:aws:iam::123456789012:role/dev", parsed.IamRole)
+}
+
+func TestPartialParseConfigFilePersistentCacheSkipsUntrackedInputs(t *testing.T) {
+	t.Parallel()
+
+	testCases := map[string]string{
+		"run_cmd":         `skip = run_cmd("echo", "true") == "true"`,
+		"file":            `iam_role = file("role.txt")`,
+		"dynamic get_env": `iam_role = get_env(lower("ROLE"), "")`,
+	}
+
+	for name, contents := range testCases {
+		contents := contents
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			configDir := t.TempDir()
+			configPath := filepath.Join(configDir, DefaultTerragruntConfigPath)
+			require.NoError(t, os.WriteFile(configPath, []byte(contents), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(configDir, "role.txt"), []byte("arn:aws:iam::123456789012:role/ci"), 0644))
+			terragruntOptions := persistentCacheTestOptions(t, configPath)
+
+			decodeList := []PartialDecodeSectionType{TerragruntFlags}
+			_, err := PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+			require.NoError(t, err)
+			assert.False(t, util.FileExists(persistentConfigCachePath(configPath, terragruntOptions, decodeList)))
+		})
+	}
+}
+
+func TestPersistentConfigCachePathDependsOnFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(DefaultTerragruntConfigPath)
+	require.NoError(t, err)
+	decodeList := []PartialDecodeSectionType{TerragruntFlags}
+
+	defaultPath := persistentConfigCachePath(DefaultTerragruntConfigPath, terragruntOptions, decodeList)
+	terragruntOptions.FeatureFlags = map[string]string{"skip_app": "true"}
+	assert.NotEqual(t, defaultPath, persistentConfigCachePath(DefaultTerragruntConfigPath, terragruntOptions, decodeList))
+}
+
+func TestPartialParseConfigFilePersistentCacheLocals(t *testing.T) {
+	t.Parallel()
+
+	configPath := filepath.Join(t.TempDir(), DefaultTerragruntConfigPath)
+	require.NoError(t, os.WriteFile(configPath, []byte(`
+locals {
+  priority = 3
+  settings = {
+    replicas = [1, 2]
+    name     = "app"
+    unset    = null
+  }
+}
+
+priority = local.priority
+`), 0644))
+	terragruntOptions := persistentCacheTestOptions(t, configPath)
+
+	decodeList := []PartialDecodeSectionType{TerragruntPriority}
+	parsed, err := PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+	require.NoError(t, err)
+	require.True(t, util.FileExists(persistentConfigCachePath(configPath, terragruntOptions, decodeList)))
+
+	cached, err := PartialParseConfigFile(configPath, terragruntOptions, nil, decodeList)
+	require.NoError(t, err)
+	assert.Equal(t, parsed.Locals, cached.Locals)
+	assert.Equal(t, 3.0, cached.Locals["priority"])
+}
@@ -0,0 +1,103 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+)
+
+const (
+	// FoundInLine is the metadata of the line a field was defined at, set with --with-source-positions.
+	FoundInLine = "found_in_line"
+	// FoundInIncludeLevel is the metadata of the level of the config a field was defined in, set with
+	// --with-source-positions: 0 for the config itself, and 1 for the configs it includes or imports.
+	FoundInIncludeLevel = "include_level"
+)
+
+// setFieldsSourcePositions adds the line and include level each field of the given config was defined at to its
+// metadata. It must be called before the config is merged with other configs, so that only the fields of the given
+// file are updated, and the positions of the fields merged in from other configs are kept. Lines are only known for
+// configs in the native HCL syntax.
+func setFieldsSourcePositions(config *TerragruntConfig, file *hcl.File, isIncluded bool) {
+	includeLevel := 0
+	if isIncluded {
+		includeLevel = 1
+	}
+
+	positions := fieldSourcePositions(file)
+	if config.Dependencies != nil {
+		// All the paths of the dependencies block are defined at the block.
+		if position, found := positions[fieldMetadataKey(MetadataDependencies, MetadataDependencies)]; found {
+			for _, path := range config.Dependencies.Paths {
+				positions[fieldMetadataKey(MetadataDependencies, path)] = position
+			}
+		}
+	}
+
+	for field, metadata := range config.FieldsMetadata {
+		metadata[FoundInIncludeLevel] = includeLevel
+		if position, found := positions[field]; found {
+			metadata[FoundInLine] = position.Start.Line
+		}
+	}
+}
+
+// fieldSourcePositions returns the ranges the fields of the given file are defined at, by the keys of their metadata.
+func fieldSourcePositions(file *hcl.File) map[string]hcl.Range {
+	positions := map[string]hcl.Range{}
+
+	body, isNativeSyntax := file.Body.(*hclsyntax.Body)
+	if !isNativeSyntax {
+		return positions
+	}
+
+	for name, attr := range body.Attributes {
+		positions[fieldMetadataKey(name, name)] = attr.SrcRange
+		if name == MetadataInputs {
+			for key, keyRange := range objectKeyRanges(attr.Expr) {
+				positions[fieldMetadataKey(MetadataInputs, key)] = keyRange
+			}
+		}
+	}
+
+	for _, block := range body.Blocks {
+		switch {
+		case block.Type == MetadataLocals:
+			for name, attr := range block.Body.Attributes {
+				positions[fieldMetadataKey(MetadataLocals, name)] = attr.SrcRange
+			}
+		case len(block.Labels) > 0:
+			positions[fieldMetadataKey(block.Type, block.Labels[0])] = block.DefRange()
+		default:
+			positions[fieldMetadataKey(block.Type, block.Type)] = block.DefRange()
+		}
+	}
+
+	return positions
+}
+
+// objectKeyRanges returns the ranges of the keys of the given object expression, for the keys that are known without
+// evaluating the expression.
+func objectKeyRanges(expr hcl.Expression) map[string]hcl.Range {
+	ranges := map[string]hcl.Range{}
+
+	object, isObject := expr.(*hclsyntax.ObjectConsExpr)
+	if !isObject {
+		return ranges
+	}
+	for _, item := range object.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || !key.IsKnown() || key.IsNull() || key.Type() != cty.String {
+			continue
+		}
+		ranges[key.AsString()] = item.KeyExpr.Range()
+	}
+	return ranges
+}
+
+// fieldMetadataKey returns the key of the metadata of the given field in TerragruntConfig.FieldsMetadata.
+func fieldMetadataKey(fieldType, fieldName string) string {
+	return fmt.Sprintf("%s-%s", fieldType, fieldName)
+}
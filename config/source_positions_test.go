@@ -0,0 +1,62 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFieldsSourcePositions(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  region = "us-east-1"
+}
+
+dependencies {
+  paths = ["../options"]
+}
+
+inputs = {
+  region = local.region
+  "name" = "app"
+}
+
+retryable_errors = [".*"]
+`
+	opts := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	opts.RenderJsonWithSourcePositions = true
+	terragruntConfig, err := ParseConfigString(config, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	testCases := []struct {
+		fieldType string
+		fieldName string
+		line      string
+	}{
+		{MetadataLocals, "region", "3"},
+		{MetadataDependencies, "../options", "6"},
+		{MetadataInputs, "region", "11"},
+		{MetadataInputs, "name", "12"},
+		{MetadataRetryableErrors, MetadataRetryableErrors, "15"},
+	}
+	for _, testCase := range testCases {
+		metadata, found := terragruntConfig.GetMapFieldMetadata(testCase.fieldType, testCase.fieldName)
+		require.True(t, found, testCase.fieldName)
+		assert.Equal(t, map[string]string{FoundInFile: DefaultTerragruntConfigPath, FoundInLine: testCase.line, FoundInIncludeLevel: "0"}, metadata, testCase.fieldName)
+	}
+}
+
+func TestSetFieldsSourcePositionsDisabled(t *testing.T) {
+	t.Parallel()
+
+	opts := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	terragruntConfig, err := ParseConfigString(`inputs = { name = "app" }`, opts, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+
+	metadata, found := terragruntConfig.GetMapFieldMetadata(MetadataInputs, "name")
+	require.True(t, found)
+	assert.Equal(t, map[string]string{FoundInFile: DefaultTerragruntConfigPath}, metadata)
+}
@@ -0,0 +1,367 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-getter"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// DefaultStackFile is the name of the file declaring the units of a stack.
+	DefaultStackFile = "terragrunt.stack.hcl"
+	// StackDir is the folder, next to the stack file, the units of the stack are generated in.
+	StackDir = ".terragrunt-stack"
+	// StackUnitHeader is the first line of the generated terragrunt.hcl files, and is used to tell them apart from the
+	// configs written by hand.
+	StackUnitHeader = "# Generated by terragrunt stack generate from " + DefaultStackFile + ". Changes to this file are overwritten."
+)
+
+// StackConfig is a terragrunt.stack.hcl file, which declares the units of a stack once, instead of copying a
+// terragrunt.hcl file in every unit:
+//
+//	unit "vpc" {
+//	  source = "git::https://github.com/acme/modules.git//vpc?ref=v1.2.0"
+//	  path   = "network/vpc"
+//	  inputs = {
+//	    cidr = local.cidr
+//	  }
+//	}
+//
+//	unit "app" {
+//	  source       = "../modules/app"
+//	  path         = "app"
+//	  dependencies = ["vpc"]
+//	  inputs = {
+//	    vpc_id = dependency.vpc.outputs.vpc_id
+//	  }
+//	}
+//
+// The stack file can have locals, which are evaluated as in terragrunt.hcl. The inputs that reference the outputs of
+// the dependencies are copied as is in the generated config of the unit, while the others are evaluated.
+type StackConfig struct {
+	Path  string
+	Units []StackUnit
+}
+
+// StackUnit is a unit block of a stack file.
+type StackUnit struct {
+	Name         string
+	Source       string
+	Path         string
+	Dependencies []string
+
+	inputs hclwrite.Tokens
+}
+
+// stackConfigFile is the struct the stack file is decoded into.
+type stackConfigFile struct {
+	Units     []stackUnitBlock           `hcl:"unit,block"`
+	Locals    *terragruntLocal           `hcl:"locals,block"`
+	Features  []terragruntFeatureIgnore  `hcl:"feature,block"`
+	Variables []terragruntVariableIgnore `hcl:"variable,block"`
+}
+
+type stackUnitBlock struct {
+	Name         string         `hcl:"name,label"`
+	Source       string         `hcl:"source,attr"`
+	Path         string         `hcl:"path,attr"`
+	Dependencies []string       `hcl:"dependencies,optional"`
+	Inputs       hcl.Expression `hcl:"inputs,optional"`
+}
+
+// ReadStackConfigFile parses the stack file at the given path and validates its units.
+func ReadStackConfigFile(filename string, terragruntOptions *options.TerragruntOptions) (*StackConfig, error) {
+	configString, err := util.ReadFileAsString(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	parser := hclparse.NewParser()
+	file, err := parseHcl(parser, configString, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	baseBlocks, err := DecodeBaseBlocks(terragruntOptions, parser, file, filename, nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	extensions := EvalContextExtensions{
+		Locals:    baseBlocks.Locals,
+		Features:  baseBlocks.Features,
+		Variables: baseBlocks.Variables,
+	}
+	evalContext, err := extensions.CreateTerragruntEvalContext(filename, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	decoded := stackConfigFile{}
+	if err := decodeHcl(file, filename, &decoded, evalContext); err != nil {
+		return nil, err
+	}
+
+	stack := &StackConfig{Path: filename}
+	names := map[string]bool{}
+	paths := map[string]string{}
+	for _, block := range decoded.Units {
+		if names[block.Name] {
+			return nil, errors.WithStackTrace(DuplicatedStackUnit{Name: block.Name, StackPath: filename})
+		}
+		names[block.Name] = true
+
+		unitPath := filepath.ToSlash(filepath.Clean(block.Path))
+		if filepath.IsAbs(block.Path) || unitPath == "." || unitPath == ".." || strings.HasPrefix(unitPath, "../") {
+			return nil, errors.WithStackTrace(InvalidStackUnitPath{Name: block.Name, Path: block.Path})
+		}
+		if other, found := paths[unitPath]; found {
+			return nil, errors.WithStackTrace(DuplicatedStackUnitPath{Name: block.Name, Other: other, Path: unitPath})
+		}
+		paths[unitPath] = block.Name
+
+		inputs, err := stackUnitInputs(block, file.Bytes, evalContext)
+		if err != nil {
+			return nil, err
+		}
+
+		stack.Units = append(stack.Units, StackUnit{
+			Name:         block.Name,
+			Source:       block.Source,
+			Path:         unitPath,
+			Dependencies: block.Dependencies,
+			inputs:       inputs,
+		})
+	}
+
+	for _, unit := range stack.Units {
+		for _, dependency := range unit.Dependencies {
+			if !names[dependency] {
+				return nil, errors.WithStackTrace(UnknownStackUnitDependency{Name: unit.Name, Dependency: dependency})
+			}
+		}
+	}
+
+	return stack, nil
+}
+
+// stackUnitInputs returns the tokens of the inputs of the given unit block, as they are written in the generated config
+// of the unit. When the inputs are an object, the inputs that reference `dependency` are copied from the stack file
+// as is, as the outputs of the dependencies are only known when the unit is run. The other inputs are evaluated.
+func stackUnitInputs(block stackUnitBlock, src []byte, evalContext *hcl.EvalContext) (hclwrite.Tokens, error) {
+	if !isExpressionSet(block.Inputs) {
+		return nil, nil
+	}
+
+	objectExpr, isObject := block.Inputs.(*hclsyntax.ObjectConsExpr)
+	if !isObject {
+		value, diags := block.Inputs.Value(evalContext)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+		if !value.Type().IsObjectType() && !value.Type().IsMapType() {
+			return nil, errors.WithStackTrace(InvalidStackUnitInputs(block.Name))
+		}
+		return hclwrite.TokensForValue(value), nil
+	}
+
+	var err error
+	attrs := []hclwrite.ObjectAttrTokens{}
+	for _, item := range objectExpr.Items {
+		key, diags := item.KeyExpr.Value(evalContext)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+		if key.IsNull() || !key.Type().Equals(cty.String) {
+			return nil, errors.WithStackTrace(InvalidStackUnitInputs(block.Name))
+		}
+
+		var valueTokens hclwrite.Tokens
+		if referencesDependency(item.ValueExpr) {
+			valueTokens, err = stackUnitRawInput(block, key.AsString(), item.ValueExpr, src)
+			if err != nil {
+				return nil, err
+			}
+		} else {
+			value, diags := item.ValueExpr.Value(evalContext)
+			if diags.HasErrors() {
+				return nil, errors.WithStackTrace(diags)
+			}
+			valueTokens = hclwrite.TokensForValue(value)
+		}
+		attrs = append(attrs, hclwrite.ObjectAttrTokens{Name: inputNameTokens(key.AsString()), Value: valueTokens})
+	}
+	return hclwrite.TokensForObject(attrs), nil
+}
+
+// stackUnitRawInput returns the tokens of the source of the given input, which references the outputs of the
+// dependencies of the unit. Such inputs can only reference the dependencies declared by the unit, as the other
+// variables of the stack file, such as the locals, aren't available in the generated config.
+func stackUnitRawInput(block stackUnitBlock, name string, expr hclsyntax.Expression, src []byte) (hclwrite.Tokens, error) {
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() != MetadataDependency {
+			return nil, errors.WithStackTrace(InvalidStackUnitInputReference{Unit: block.Name, Input: name, Reference: traversal.RootName()})
+		}
+		if len(traversal) < 2 {
+			return nil, errors.WithStackTrace(InvalidStackUnitInputReference{Unit: block.Name, Input: name, Reference: MetadataDependency})
+		}
+		attr, isAttr := traversal[1].(hcl.TraverseAttr)
+		if !isAttr || !util.ListContainsElement(block.Dependencies, attr.Name) {
+			return nil, errors.WithStackTrace(InvalidStackUnitInputReference{Unit: block.Name, Input: name, Reference: MetadataDependency + "." + attr.Name})
+		}
+	}
+
+	exprRange := expr.Range()
+	file, diags := hclwrite.ParseConfig(append([]byte("value = "), exprRange.SliceBytes(src)...), exprRange.Filename, hcl.InitialPos)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+	return file.Body().GetAttribute("value").Expr().BuildTokens(nil), nil
+}
+
+// inputNameTokens returns the tokens of the given input name, which is quoted unless it is a valid identifier.
+func inputNameTokens(name string) hclwrite.Tokens {
+	if hclsyntax.ValidIdentifier(name) {
+		return hclwrite.TokensForIdentifier(name)
+	}
+	return hclwrite.TokensForValue(cty.StringVal(name))
+}
+
+// referencesDependency returns true if the given expression references the outputs of the dependencies.
+func referencesDependency(expr hcl.Expression) bool {
+	for _, traversal := range expr.Variables() {
+		if traversal.RootName() == MetadataDependency {
+			return true
+		}
+	}
+	return false
+}
+
+// UnitDir returns the folder the given unit of the stack is generated in.
+func (stack *StackConfig) UnitDir(unit StackUnit) string {
+	return filepath.Join(filepath.Dir(stack.Path), StackDir, filepath.FromSlash(unit.Path))
+}
+
+// UnitConfig returns the contents of the terragrunt.hcl file generated for the given unit of the stack.
+func (stack *StackConfig) UnitConfig(unit StackUnit) ([]byte, error) {
+	unitDir := stack.UnitDir(unit)
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+
+	terraformBody := body.AppendNewBlock(MetadataTerraform, nil).Body()
+	source, err := stack.unitSource(unit, unitDir)
+	if err != nil {
+		return nil, err
+	}
+	terraformBody.SetAttributeValue("source", cty.StringVal(source))
+
+	dependencies := append([]string{}, unit.Dependencies...)
+	sort.Strings(dependencies)
+	for _, name := range dependencies {
+		for _, dependency := range stack.Units {
+			if dependency.Name != name {
+				continue
+			}
+			configPath, err := filepath.Rel(unitDir, stack.UnitDir(dependency))
+			if err != nil {
+				return nil, errors.WithStackTrace(err)
+			}
+			body.AppendNewline()
+			dependencyBody := body.AppendNewBlock(MetadataDependency, []string{name}).Body()
+			dependencyBody.SetAttributeValue("config_path", cty.StringVal(filepath.ToSlash(configPath)))
+		}
+	}
+
+	if unit.inputs != nil {
+		body.AppendNewline()
+		body.SetAttributeRaw(MetadataInputs, unit.inputs)
+	}
+
+	return append([]byte(StackUnitHeader+"\n\n"), hclwrite.Format(file.Bytes())...), nil
+}
+
+// unitSource returns the terraform source of the given unit. The local sources are relative to the stack file, and are
+// made relative to the folder of the generated unit.
+func (stack *StackConfig) unitSource(unit StackUnit, unitDir string) (string, error) {
+	if !strings.HasPrefix(unit.Source, "./") && !strings.HasPrefix(unit.Source, "../") {
+		return unit.Source, nil
+	}
+
+	sourceDir, subdir := getter.SourceDirSubdir(unit.Source)
+	source, err := filepath.Rel(unitDir, filepath.Join(filepath.Dir(stack.Path), sourceDir))
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	source = filepath.ToSlash(source)
+	if subdir != "" {
+		source += "//" + subdir
+	}
+	return source, nil
+}
+
+// Custom error types
+
+type DuplicatedStackUnit struct {
+	Name      string
+	StackPath string
+}
+
+func (err DuplicatedStackUnit) Error() string {
+	return fmt.Sprintf("Unit %s is declared more than once in %s.", err.Name, err.StackPath)
+}
+
+type DuplicatedStackUnitPath struct {
+	Name  string
+	Other string
+	Path  string
+}
+
+func (err DuplicatedStackUnitPath) Error() string {
+	return fmt.Sprintf("Units %s and %s are both generated in %s.", err.Other, err.Name, err.Path)
+}
+
+type InvalidStackUnitPath struct {
+	Name string
+	Path string
+}
+
+func (err InvalidStackUnitPath) Error() string {
+	return fmt.Sprintf("The path %s of unit %s must be a relative path inside of the stack.", err.Path, err.Name)
+}
+
+type UnknownStackUnitDependency struct {
+	Name       string
+	Dependency string
+}
+
+func (err UnknownStackUnitDependency) Error() string {
+	return fmt.Sprintf("Unit %s depends on %s, which is not a unit of the stack.", err.Name, err.Dependency)
+}
+
+type InvalidStackUnitInputs string
+
+func (err InvalidStackUnitInputs) Error() string {
+	return fmt.Sprintf("The inputs of unit %s must be an object.", string(err))
+}
+
+type InvalidStackUnitInputReference struct {
+	Unit      string
+	Input     string
+	Reference string
+}
+
+func (err InvalidStackUnitInputReference) Error() string {
+	return fmt.Sprintf("Input %s of unit %s references %s. The inputs that reference the outputs of dependencies can only reference the dependencies of the unit.", err.Input, err.Unit, err.Reference)
+}
@@ -0,0 +1,201 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const stackTestConfig = `
+locals {
+  env = "prod"
+}
+
+unit "vpc" {
+  source = "../modules//vpc"
+  path   = "network/vpc"
+  inputs = {
+    name = "${local.env}-vpc"
+  }
+}
+
+unit "app" {
+  source       = "git::https://example.com/modules.git//app?ref=v1.0.0"
+  path         = "app"
+  dependencies = ["vpc"]
+  inputs = {
+    vpc_id = dependency.vpc.outputs.vpc_id
+    env    = upper(local.env)
+  }
+}
+`
+
+func readStackConfigForTest(t *testing.T, contents string) (*StackConfig, error) {
+	t.Helper()
+
+	stackPath := filepath.Join(t.TempDir(), "live", DefaultStackFile)
+	require.NoError(t, os.MkdirAll(filepath.Dir(stackPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(stackPath, []byte(contents), 0644))
+	return ReadStackConfigFile(stackPath, terragruntOptionsForTest(t, stackPath))
+}
+
+func TestStackUnitConfig(t *testing.T) {
+	t.Parallel()
+
+	stack, err := readStackConfigForTest(t, stackTestConfig)
+	require.NoError(t, err)
+	require.Len(t, stack.Units, 2)
+	assert.Equal(t, "network/vpc", stack.Units[0].Path)
+	assert.Equal(t, filepath.Join(filepath.Dir(stack.Path), StackDir, "network", "vpc"), stack.UnitDir(stack.Units[0]))
+
+	vpcConfig, err := stack.UnitConfig(stack.Units[0])
+	require.NoError(t, err)
+	assert.Equal(t, StackUnitHeader+`
+
+terraform {
+  source = "../../../../modules//vpc"
+}
+
+inputs = {
+  name = "prod-vpc"
+}
+`, string(vpcConfig))
+
+	appConfig, err := stack.UnitConfig(stack.Units[1])
+	require.NoError(t, err)
+	assert.Equal(t, StackUnitHeader+`
+
+terraform {
+  source = "git::https://example.com/modules.git//app?ref=v1.0.0"
+}
+
+dependency "vpc" {
+  config_path = "../network/vpc"
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+  env    = "PROD"
+}
+`, string(appConfig))
+}
+
+func TestStackConfigErrors(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		config   string
+		expected error
+	}{
+		{
+			"duplicated unit",
+			`
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "vpc"
+}
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "other"
+}
+`,
+			DuplicatedStackUnit{Name: "vpc"},
+		},
+		{
+			"duplicated path",
+			`
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "vpc"
+}
+unit "other" {
+  source = "../modules/vpc"
+  path   = "./vpc"
+}
+`,
+			DuplicatedStackUnitPath{Name: "other", Other: "vpc", Path: "vpc"},
+		},
+		{
+			"path outside of the stack",
+			`
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "../vpc"
+}
+`,
+			InvalidStackUnitPath{Name: "vpc", Path: "../vpc"},
+		},
+		{
+			"unknown dependency",
+			`
+unit "app" {
+  source       = "../modules/app"
+  path         = "app"
+  dependencies = ["vpc"]
+}
+`,
+			UnknownStackUnitDependency{Name: "app", Dependency: "vpc"},
+		},
+		{
+			"undeclared dependency reference",
+			`
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "vpc"
+}
+unit "app" {
+  source = "../modules/app"
+  path   = "app"
+  inputs = {
+    vpc_id = dependency.vpc.outputs.vpc_id
+  }
+}
+`,
+			InvalidStackUnitInputReference{Unit: "app", Input: "vpc_id", Reference: "dependency.vpc"},
+		},
+		{
+			"dependency reference mixed with locals",
+			`
+locals {
+  prefix = "app"
+}
+unit "vpc" {
+  source = "../modules/vpc"
+  path   = "vpc"
+}
+unit "app" {
+  source       = "../modules/app"
+  path         = "app"
+  dependencies = ["vpc"]
+  inputs = {
+    name = "${local.prefix}-${dependency.vpc.outputs.name}"
+  }
+}
+`,
+			InvalidStackUnitInputReference{Unit: "app", Input: "name", Reference: "local"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := readStackConfigForTest(t, testCase.config)
+			require.Error(t, err)
+			if expected, ok := testCase.expected.(DuplicatedStackUnit); ok {
+				actual, ok := errors.Unwrap(err).(DuplicatedStackUnit)
+				require.True(t, ok, err.Error())
+				assert.Equal(t, expected.Name, actual.Name)
+				return
+			}
+			assert.Equal(t, testCase.expected, errors.Unwrap(err))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/gohcl"
+	"github.com/hashicorp/hcl/v2/hclparse"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// deprecatedAttributes maps the deprecated attributes, by block type and name, to the attributes that replace them.
+var deprecatedAttributes = map[string]map[string]string{
+	"dependency": {"mock_outputs_merge_with_state": "mock_outputs_merge_strategy_with_state"},
+}
+
+// strictBlockTypes are the types used to check the blocks that are decoded in a separate cycle, and so are ignored
+// by the terragruntConfigFile schema.
+var strictBlockTypes = map[string]reflect.Type{
+	"include":  reflect.TypeOf(IncludeConfig{}),
+	"feature":  reflect.TypeOf(terragruntFeatureBlock{}),
+	"variable": reflect.TypeOf(terragruntVariableBlock{}),
+	"import":   reflect.TypeOf(ImportConfig{}),
+}
+
+// validateStrictConfig checks, when --terragrunt-strict-config is set, that the given file only contains the blocks
+// and attributes that terragrunt supports, and no deprecated ones. Without it, the blocks and attributes that the
+// current command does not need, such as the ones ignored by partial parsing, are never checked, so typos can go
+// unnoticed.
+func validateStrictConfig(file *hcl.File, filename string, terragruntOptions *options.TerragruntOptions) error {
+	if !terragruntOptions.StrictConfig {
+		return nil
+	}
+
+	// Bare include blocks are labeled the same way as for decoding.
+	updatedBytes, isUpdated, err := updateBareIncludeBlock(file, filename)
+	if err != nil {
+		return err
+	}
+	if isUpdated {
+		file, err = parseHcl(hclparse.NewParser(), string(updatedBytes), filename)
+		if err != nil {
+			return err
+		}
+	}
+
+	if diags := validateStrictBody(file.Body, reflect.TypeOf(terragruntConfigFile{}), ""); diags.HasErrors() {
+		return errors.WithStackTrace(diags)
+	}
+	return nil
+}
+
+// validateStrictBody checks the given body against the schema of the given struct type, and then the nested blocks
+// against the schemas of their own types.
+func validateStrictBody(body hcl.Body, structType reflect.Type, blockType string) hcl.Diagnostics {
+	schema, isPartial := gohcl.ImpliedBodySchema(reflect.New(structType).Interface())
+	if isPartial {
+		// The body is decoded with its own rules, like locals, so it has no fixed schema.
+		return nil
+	}
+
+	content, diags := body.Content(schema)
+	if content == nil {
+		return diags
+	}
+
+	for name, attr := range content.Attributes {
+		if replacement, isDeprecated := deprecatedAttributes[blockType][name]; isDeprecated {
+			diags = append(diags, &hcl.Diagnostic{
+				Severity: hcl.DiagError,
+				Summary:  "Deprecated argument",
+				Detail:   fmt.Sprintf("The argument %q is deprecated. Use %q instead.", name, replacement),
+				Subject:  attr.NameRange.Ptr(),
+			})
+		}
+	}
+
+	blockTypes := blockFieldTypes(structType)
+	for _, block := range content.Blocks {
+		nestedType, found := blockTypes[block.Type]
+		if strictType, isIgnored := strictBlockTypes[block.Type]; isIgnored && blockType == "" {
+			nestedType, found = strictType, true
+		}
+		if found {
+			diags = append(diags, validateStrictBody(block.Body, nestedType, block.Type)...)
+		}
+	}
+	return diags
+}
+
+// blockFieldTypes returns the struct types of the blocks of the given struct type, by block type.
+func blockFieldTypes(structType reflect.Type) map[string]reflect.Type {
+	types := map[string]reflect.Type{}
+	for i := 0; i < structType.NumField(); i++ {
+		field := structType.Field(i)
+		name, kind, _ := strings.Cut(field.Tag.Get("hcl"), ",")
+		if kind != "block" {
+			continue
+		}
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr || fieldType.Kind() == reflect.Slice {
+			fieldType = fieldType.Elem()
+		}
+		if fieldType.Kind() == reflect.Struct {
+			types[name] = fieldType
+		}
+	}
+	return types
+}
@@ -0,0 +1,116 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStrictConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		config        string
+		expectedError string
+	}{
+		{
+			"valid",
+			`
+include {
+  path = "root.hcl"
+}
+feature "enabled" {
+  default = true
+}
+terraform {
+  source = "../modules/app"
+}
+dependency "vpc" {
+  config_path = "../vpc"
+}
+inputs = {}
+`,
+			"",
+		},
+		{
+			"unknown attribute",
+			`inpts = {}`,
+			`An argument named "inpts" is not expected here`,
+		},
+		{
+			"unknown nested attribute",
+			`
+terraform {
+  sorce = "../modules/app"
+}
+`,
+			`An argument named "sorce" is not expected here`,
+		},
+		{
+			"unknown attribute in feature block",
+			`
+feature "enabled" {
+  default    = true
+  descripton = "Whether the app is enabled."
+}
+`,
+			`An argument named "descripton" is not expected here`,
+		},
+		{
+			"deprecated attribute",
+			`
+dependency "vpc" {
+  config_path                   = "../vpc"
+  mock_outputs_merge_with_state = true
+}
+`,
+			`The argument "mock_outputs_merge_with_state" is deprecated`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			file, err := parseHcl(hclparse.NewParser(), testCase.config, DefaultTerragruntConfigPath)
+			require.NoError(t, err)
+
+			terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+			require.NoError(t, validateStrictConfig(file, DefaultTerragruntConfigPath, terragruntOptions), "not strict")
+
+			terragruntOptions.StrictConfig = true
+			err = validateStrictConfig(file, DefaultTerragruntConfigPath, terragruntOptions)
+			if testCase.expectedError == "" {
+				assert.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), testCase.expectedError)
+			assert.Contains(t, err.Error(), DefaultTerragruntConfigPath+":")
+		})
+	}
+}
+
+func TestStrictConfigPartialParse(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	config := `
+dependencies {
+  paths = []
+}
+inpts = {}
+`
+	_, err := PartialParseConfigString(config, terragruntOptions, nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{DependenciesBlock})
+	require.NoError(t, err)
+
+	terragruntOptions.StrictConfig = true
+	_, err = PartialParseConfigString(config, terragruntOptions, nil, DefaultTerragruntConfigPath, []PartialDecodeSectionType{DependenciesBlock})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "inpts")
+}
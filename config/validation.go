@@ -0,0 +1,127 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// The name of the variable that exposes the resolved inputs of the configuration to the conditions of validation
+// blocks.
+const validationInputsVariable = "inputs"
+
+// terragruntValidationBlock is the configuration of a validation block, which asserts a condition on the resolved
+// configuration:
+//
+//	validation {
+//	  condition     = length(inputs.name) <= 32
+//	  error_message = "The name must be at most 32 characters long."
+//	}
+//
+// The condition is only evaluated once the inputs of the configuration, including the ones of included
+// configurations, are resolved, so it is decoded as an expression.
+type terragruntValidationBlock struct {
+	Condition    hcl.Expression `hcl:"condition,attr"`
+	ErrorMessage string         `hcl:"error_message,attr"`
+}
+
+// ValidationConfig is a validation block of a configuration, along with the context to evaluate its condition in.
+type ValidationConfig struct {
+	Condition    hcl.Expression
+	ErrorMessage string
+	ConfigPath   string
+
+	evalContext *hcl.EvalContext
+}
+
+func (conf *ValidationConfig) String() string {
+	return fmt.Sprintf("ValidationConfig{ConfigPath = %s, ErrorMessage = %s}", conf.ConfigPath, conf.ErrorMessage)
+}
+
+// newValidationConfigs converts the validation blocks decoded from the given file, keeping the context they were decoded
+// in so that their conditions can reference the locals, dependencies and functions of that file.
+func newValidationConfigs(blocks []terragruntValidationBlock, filename string, evalContext *hcl.EvalContext) []ValidationConfig {
+	var validations []ValidationConfig
+	for _, block := range blocks {
+		validations = append(validations, ValidationConfig{
+			Condition:    block.Condition,
+			ErrorMessage: block.ErrorMessage,
+			ConfigPath:   filename,
+			evalContext:  evalContext,
+		})
+	}
+	return validations
+}
+
+// evaluateValidations evaluates the conditions of the validation blocks of the given configuration, with the resolved
+// inputs of the configuration exposed as `inputs`, and returns an error listing the error messages of all the
+// validations whose condition is false.
+func evaluateValidations(config *TerragruntConfig, terragruntOptions *options.TerragruntOptions) error {
+	if len(config.Validations) == 0 {
+		return nil
+	}
+
+	inputs, err := convertToCtyWithJson(config.Inputs)
+	if err != nil {
+		return err
+	}
+
+	failed := []ValidationConfig{}
+	for _, validation := range config.Validations {
+		evalContext := &hcl.EvalContext{Variables: map[string]cty.Value{}}
+		if validation.evalContext != nil {
+			evalContext = validation.evalContext.NewChild()
+			evalContext.Variables = map[string]cty.Value{}
+		}
+		evalContext.Variables[validationInputsVariable] = inputs
+
+		result, diags := validation.Condition.Value(evalContext)
+		if diags.HasErrors() {
+			return errors.WithStackTrace(diags)
+		}
+		if !result.IsKnown() {
+			// The condition depends on values that are not known yet, such as the outputs of dependencies that were
+			// not applied, so it can't be checked.
+			terragruntOptions.Logger.Debugf("Skipping validation of %s whose condition is unknown: %s", validation.ConfigPath, validation.ErrorMessage)
+			continue
+		}
+		if result.IsNull() || result.Type() != cty.Bool {
+			return errors.WithStackTrace(InvalidValidationCondition{Range: validation.Condition.Range()})
+		}
+		if result.False() {
+			failed = append(failed, validation)
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.WithStackTrace(ValidationFailed{Validations: failed})
+	}
+	return nil
+}
+
+// Custom error types
+
+type ValidationFailed struct {
+	Validations []ValidationConfig
+}
+
+func (err ValidationFailed) Error() string {
+	messages := []string{}
+	for _, validation := range err.Validations {
+		messages = append(messages, fmt.Sprintf("- %s (%s)", validation.ErrorMessage, validation.Condition.Range()))
+	}
+	return fmt.Sprintf("Validation failed:\n%s", strings.Join(messages, "\n"))
+}
+
+type InvalidValidationCondition struct {
+	Range hcl.Range
+}
+
+func (err InvalidValidationCondition) Error() string {
+	return fmt.Sprintf("The condition of the validation block at %s must be a boolean.", err.Range)
+}
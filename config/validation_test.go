@@ -0,0 +1,84 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTerragruntConfigValidationPasses(t *testing.T) {
+	t.Parallel()
+
+	config := `
+locals {
+  regions = ["us-east-1", "eu-west-1"]
+}
+
+inputs = {
+  region = "us-east-1"
+}
+
+validation {
+  condition     = contains(local.regions, inputs.region)
+  error_message = "The region must be one of ${join(", ", local.regions)}."
+}
+`
+
+	terragruntConfig, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.NoError(t, err)
+	require.Len(t, terragruntConfig.Validations, 1)
+	assert.Equal(t, "The region must be one of us-east-1, eu-west-1.", terragruntConfig.Validations[0].ErrorMessage)
+}
+
+func TestParseTerragruntConfigValidationFails(t *testing.T) {
+	t.Parallel()
+
+	config := `
+inputs = {
+  name = "a-very-long-name"
+  size = 3
+}
+
+validation {
+  condition     = length(inputs.name) <= 8
+  error_message = "The name must be at most 8 characters long."
+}
+
+validation {
+  condition     = inputs.size > 0
+  error_message = "The size must be positive."
+}
+
+validation {
+  condition     = inputs.size % 2 == 0
+  error_message = "The size must be even."
+}
+`
+
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+
+	validationErr, isValidationErr := errors.Unwrap(err).(ValidationFailed)
+	require.True(t, isValidationErr, "Unexpected error: %v", err)
+	require.Len(t, validationErr.Validations, 2)
+	assert.Equal(t, "The name must be at most 8 characters long.", validationErr.Validations[0].ErrorMessage)
+	assert.Equal(t, "The size must be even.", validationErr.Validations[1].ErrorMessage)
+}
+
+func TestParseTerragruntConfigValidationConditionNotBool(t *testing.T) {
+	t.Parallel()
+
+	config := `
+validation {
+  condition     = "yes"
+  error_message = "Never shown."
+}
+`
+
+	_, err := ParseConfigString(config, mockOptionsForTest(t), nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	_, isInvalidCondition := errors.Unwrap(err).(InvalidValidationCondition)
+	assert.True(t, isInvalidCondition, "Unexpected error: %v", err)
+}
@@ -0,0 +1,261 @@
+package config
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/ext/typeexpr"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+	"github.com/zclconf/go-cty/cty/convert"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The prefix of the environment variables that set the values of variables, e.g. TG_VAR_region for var.region.
+const VariableEnvVarPrefix = "TG_VAR_"
+
+// terragruntVariableBlock is the configuration of a typed variable of the config, which is exposed to the rest of the
+// configuration as `var.<name>`:
+//
+//	variable "region" {
+//	  type        = string
+//	  default     = "us-east-1"
+//	  description = "The AWS region to deploy to."
+//
+//	  validation {
+//	    condition     = startswith(var.region, "us-")
+//	    error_message = "Only US regions are supported."
+//	  }
+//	}
+//
+// The value of the variable is, in order of precedence, the one passed in with --terragrunt-var, the one set in a file
+// passed in with --terragrunt-var-file, the one of the TG_VAR_<name> environment variable, or the default.
+type terragruntVariableBlock struct {
+	Name        string                      `hcl:"name,label"`
+	Type        hcl.Expression              `hcl:"type,optional"`
+	Default     cty.Value                   `hcl:"default,optional"`
+	Description *string                     `hcl:"description,optional"`
+	Validations []terragruntValidationBlock `hcl:"validation,block"`
+}
+
+// terragruntVariables is a struct that can be used to only decode the variable blocks.
+type terragruntVariables struct {
+	Variables []terragruntVariableBlock `hcl:"variable,block"`
+	Remain    hcl.Body                  `hcl:",remain"`
+}
+
+// evaluateVariableBlocks decodes the variable blocks of the given file and returns the value of each variable,
+// converted to its type, as an object to expose as the `var` variable. The defaults can only reference functions, as
+// the variable blocks are evaluated before the locals, so that locals can reference them.
+func evaluateVariableBlocks(file *hcl.File, filename string, terragruntOptions *options.TerragruntOptions, evalContext *hcl.EvalContext) (*cty.Value, error) {
+	decoded := terragruntVariables{}
+	if err := decodeHcl(file, filename, &decoded, evalContext); err != nil {
+		return nil, err
+	}
+	if len(decoded.Variables) == 0 {
+		variablesAsCty := cty.EmptyObjectVal
+		return &variablesAsCty, nil
+	}
+
+	varFileValues, err := readVariableFiles(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	variables := map[string]cty.Value{}
+	for _, block := range decoded.Variables {
+		if _, isDuplicate := variables[block.Name]; isDuplicate {
+			return nil, errors.WithStackTrace(DuplicatedVariableBlock{Name: block.Name, ConfigPath: filename})
+		}
+
+		value, err := variableValue(block, varFileValues, terragruntOptions)
+		if err != nil {
+			return nil, err
+		}
+		variables[block.Name] = value
+	}
+	variablesAsCty := cty.ObjectVal(variables)
+
+	if err := evaluateVariableValidations(decoded.Variables, variablesAsCty, filename, evalContext); err != nil {
+		return nil, err
+	}
+	return &variablesAsCty, nil
+}
+
+// variableValue returns the value of the given variable from the source with the highest precedence, converted to the
+// type of the variable.
+func variableValue(block terragruntVariableBlock, varFileValues map[string]cty.Value, terragruntOptions *options.TerragruntOptions) (cty.Value, error) {
+	variableType, err := variableTypeConstraint(block)
+	if err != nil {
+		return cty.NilVal, err
+	}
+
+	value := block.Default
+	if value != cty.NilVal && value.IsNull() {
+		value = cty.NilVal
+	}
+	if raw, isSet := terragruntOptions.Env[VariableEnvVarPrefix+block.Name]; isSet {
+		if value, err = parseVariableString(block.Name, raw, variableType); err != nil {
+			return cty.NilVal, err
+		}
+	}
+	if fileValue, isSet := varFileValues[block.Name]; isSet {
+		value = fileValue
+	}
+	if raw, isSet := terragruntOptions.TerragruntVars[block.Name]; isSet {
+		if value, err = parseVariableString(block.Name, raw, variableType); err != nil {
+			return cty.NilVal, err
+		}
+	}
+
+	if value == cty.NilVal {
+		return cty.NilVal, errors.WithStackTrace(MissingVariableValue{Name: block.Name})
+	}
+
+	converted, err := convert.Convert(value, variableType)
+	if err != nil {
+		return cty.NilVal, errors.WithStackTrace(InvalidVariableValue{Name: block.Name, Err: err})
+	}
+	return converted, nil
+}
+
+// variableTypeConstraint returns the type of the given variable, which is any type if it is not set.
+func variableTypeConstraint(block terragruntVariableBlock) (cty.Type, error) {
+	// When the type is not set, the decoder sets it to an expression that returns null.
+	if value, diags := block.Type.Value(nil); !diags.HasErrors() && value.IsNull() {
+		return cty.DynamicPseudoType, nil
+	}
+
+	variableType, diags := typeexpr.TypeConstraint(block.Type)
+	if diags.HasErrors() {
+		return cty.NilType, errors.WithStackTrace(diags)
+	}
+	return variableType, nil
+}
+
+// parseVariableString parses the value of a variable passed in as a string, on the command line or in an environment
+// variable. Like with Terraform, the values of primitive types are taken as is, while the values of complex types are
+// parsed as HCL expressions, e.g. ["a", "b"] for a list.
+func parseVariableString(name string, raw string, variableType cty.Type) (cty.Value, error) {
+	if variableType.IsPrimitiveType() || variableType == cty.DynamicPseudoType {
+		return cty.StringVal(raw), nil
+	}
+
+	expr, diags := hclsyntax.ParseExpression([]byte(raw), fmt.Sprintf("<value for var.%s>", name), hcl.InitialPos)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.WithStackTrace(diags)
+	}
+	value, diags := expr.Value(nil)
+	if diags.HasErrors() {
+		return cty.NilVal, errors.WithStackTrace(diags)
+	}
+	return value, nil
+}
+
+// readVariableFiles reads the files passed in with --terragrunt-var-file, which can be in HCL syntax (.tfvars) or in
+// JSON (.json), and returns the values they set. Files passed in later take precedence over the earlier ones. The paths
+// are made absolute against the dir terragrunt is run in when the flag is parsed, so that all the modules of a run-all
+// read the same files.
+func readVariableFiles(terragruntOptions *options.TerragruntOptions) (map[string]cty.Value, error) {
+	values := map[string]cty.Value{}
+	parser := hclparse.NewParser()
+
+	for _, varFile := range terragruntOptions.TerragruntVarFiles {
+		varFilePath, err := util.CanonicalPath(varFile, terragruntOptions.WorkingDir)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+
+		var file *hcl.File
+		var diags hcl.Diagnostics
+		if strings.HasSuffix(filepath.Base(varFilePath), ".json") {
+			file, diags = parser.ParseJSONFile(varFilePath)
+		} else {
+			file, diags = parser.ParseHCLFile(varFilePath)
+		}
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+
+		attrs, diags := file.Body.JustAttributes()
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+		for name, attr := range attrs {
+			value, diags := attr.Expr.Value(nil)
+			if diags.HasErrors() {
+				return nil, errors.WithStackTrace(diags)
+			}
+			values[name] = value
+		}
+	}
+	return values, nil
+}
+
+// evaluateVariableValidations evaluates the conditions of the validation blocks of the variables, with the values of
+// the variables exposed as `var`, and returns an error listing the error messages of all the validations whose condition
+// is false.
+func evaluateVariableValidations(blocks []terragruntVariableBlock, variables cty.Value, filename string, evalContext *hcl.EvalContext) error {
+	validationContext := evalContext.NewChild()
+	validationContext.Variables = map[string]cty.Value{"var": variables}
+
+	// Sort the variables by name, so that the validations that failed are always listed in the same order.
+	sorted := append([]terragruntVariableBlock{}, blocks...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	failed := []ValidationConfig{}
+	for _, block := range sorted {
+		for _, validation := range block.Validations {
+			result, diags := validation.Condition.Value(validationContext)
+			if diags.HasErrors() {
+				return errors.WithStackTrace(diags)
+			}
+			if result.IsNull() || result.Type() != cty.Bool {
+				return errors.WithStackTrace(InvalidValidationCondition{Range: validation.Condition.Range()})
+			}
+			if result.False() {
+				failed = append(failed, ValidationConfig{Condition: validation.Condition, ErrorMessage: validation.ErrorMessage, ConfigPath: filename})
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		return errors.WithStackTrace(ValidationFailed{Validations: failed})
+	}
+	return nil
+}
+
+// Custom error types
+
+type DuplicatedVariableBlock struct {
+	Name       string
+	ConfigPath string
+}
+
+func (err DuplicatedVariableBlock) Error() string {
+	return fmt.Sprintf("Variable %s is declared more than once in %s.", err.Name, err.ConfigPath)
+}
+
+type MissingVariableValue struct {
+	Name string
+}
+
+func (err MissingVariableValue) Error() string {
+	return fmt.Sprintf("No value for variable %s, which has no default. Set it with --terragrunt-var %s=<value>, in a file passed in with --terragrunt-var-file or with the %s%s environment variable.", err.Name, err.Name, VariableEnvVarPrefix, err.Name)
+}
+
+type InvalidVariableValue struct {
+	Name string
+	Err  error
+}
+
+func (err InvalidVariableValue) Error() string {
+	return fmt.Sprintf("Invalid value for variable %s: %v", err.Name, err.Err)
+}
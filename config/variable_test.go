@@ -0,0 +1,123 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const variableTestConfig = `
+variable "region" {
+  type    = string
+  default = "us-east-1"
+
+  validation {
+    condition     = startswith(var.region, "us-")
+    error_message = "Only US regions are supported."
+  }
+}
+
+variable "replicas" {
+  type    = number
+  default = 1
+}
+
+variable "zones" {
+  type    = list(string)
+  default = ["a"]
+}
+
+locals {
+  name = "app-${var.region}"
+}
+
+inputs = {
+  name     = local.name
+  replicas = var.replicas
+  zones    = var.zones
+}
+`
+
+func TestVariableBlocks(t *testing.T) {
+	t.Parallel()
+
+	varFile := filepath.Join(t.TempDir(), "vars.tfvars")
+	require.NoError(t, os.WriteFile(varFile, []byte("replicas = 2\nzones = [\"b\", \"c\"]\n"), 0644))
+
+	testCases := []struct {
+		name     string
+		vars     map[string]string
+		varFiles []string
+		env      map[string]string
+		expected map[string]interface{}
+	}{
+		{
+			"defaults",
+			nil,
+			nil,
+			nil,
+			map[string]interface{}{"name": "app-us-east-1", "replicas": float64(1), "zones": []interface{}{"a"}},
+		},
+		{
+			"env vars",
+			nil,
+			nil,
+			map[string]string{"TG_VAR_region": "us-west-2", "TG_VAR_zones": `["x"]`},
+			map[string]interface{}{"name": "app-us-west-2", "replicas": float64(1), "zones": []interface{}{"x"}},
+		},
+		{
+			"var files override env vars",
+			nil,
+			[]string{varFile},
+			map[string]string{"TG_VAR_replicas": "5"},
+			map[string]interface{}{"name": "app-us-east-1", "replicas": float64(2), "zones": []interface{}{"b", "c"}},
+		},
+		{
+			"cli vars override var files",
+			map[string]string{"replicas": "3"},
+			[]string{varFile},
+			nil,
+			map[string]interface{}{"name": "app-us-east-1", "replicas": float64(3), "zones": []interface{}{"b", "c"}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+			terragruntOptions.TerragruntVars = testCase.vars
+			terragruntOptions.TerragruntVarFiles = testCase.varFiles
+			terragruntOptions.Env = testCase.env
+			terragruntConfig, err := ParseConfigString(variableTestConfig, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, terragruntConfig.Inputs)
+		})
+	}
+}
+
+func TestVariableBlockErrors(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions := terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	terragruntOptions.TerragruntVars = map[string]string{"replicas": "many"}
+	_, err := ParseConfigString(variableTestConfig, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.IsType(t, InvalidVariableValue{}, errors.Unwrap(err))
+
+	terragruntOptions = terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	terragruntOptions.TerragruntVars = map[string]string{"region": "eu-west-1"}
+	_, err = ParseConfigString(variableTestConfig, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Only US regions are supported.")
+
+	terragruntOptions = terragruntOptionsForTest(t, DefaultTerragruntConfigPath)
+	_, err = ParseConfigString(`variable "name" {}`, terragruntOptions, nil, DefaultTerragruntConfigPath, &EvalContextExtensions{})
+	assert.Equal(t, MissingVariableValue{Name: "name"}, errors.Unwrap(err))
+}
@@ -0,0 +1,96 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+const (
+	CIMatrixFormatGitHub = "github"
+	CIMatrixFormatGitLab = "gitlab"
+)
+
+// CIMatrixFormats are the CI systems that the groups of modules can be exported for.
+var CIMatrixFormats = []string{CIMatrixFormatGitHub, CIMatrixFormatGitLab}
+
+// githubMatrix is a GitHub Actions matrix, to be used with `strategy.matrix: ${{ fromJSON(...) }}`, with one job per
+// group of modules.
+type githubMatrix struct {
+	Include []githubMatrixGroup `json:"include"`
+}
+
+type githubMatrixGroup struct {
+	Group       int      `json:"group"`
+	Modules     []string `json:"modules"`
+	Parallelism int      `json:"parallelism"`
+}
+
+// gitlabMatrix is a GitLab CI `parallel:matrix` keyword, with one job per group of modules. GitLab only supports
+// string values in matrix variables, so the modules are separated by spaces.
+type gitlabMatrix struct {
+	Parallel gitlabParallel `json:"parallel"`
+}
+
+type gitlabParallel struct {
+	Matrix []map[string]string `json:"matrix"`
+}
+
+// CIMatrixModuleDeployOrder returns the groups of modules that will be processed for the given command as the matrix
+// of a CI system, in JSON. Every group has its index, the paths of its modules and a suggested parallelism, which is
+// the number of modules in the group, capped to the given max parallelism.
+func (stack *Stack) CIMatrixModuleDeployOrder(terraformCommand string, format string, maxParallelism int) (string, error) {
+	runGraph, err := stack.getModuleRunGraph(terraformCommand)
+	if err != nil {
+		return "", err
+	}
+
+	var matrix interface{}
+	switch format {
+	case CIMatrixFormatGitHub:
+		githubGroups := []githubMatrixGroup{}
+		for i, group := range runGraph {
+			githubGroups = append(githubGroups, githubMatrixGroup{
+				Group:       i + 1,
+				Modules:     groupModulePaths(group),
+				Parallelism: suggestedParallelism(len(group), maxParallelism),
+			})
+		}
+		matrix = githubMatrix{Include: githubGroups}
+	case CIMatrixFormatGitLab:
+		gitlabGroups := []map[string]string{}
+		for i, group := range runGraph {
+			gitlabGroups = append(gitlabGroups, map[string]string{
+				"TERRAGRUNT_GROUP":       fmt.Sprintf("%d", i+1),
+				"TERRAGRUNT_MODULES":     strings.Join(groupModulePaths(group), " "),
+				"TERRAGRUNT_PARALLELISM": fmt.Sprintf("%d", suggestedParallelism(len(group), maxParallelism)),
+			})
+		}
+		matrix = gitlabMatrix{Parallel: gitlabParallel{Matrix: gitlabGroups}}
+	default:
+		return "", errors.WithStackTrace(UnsupportedCIMatrixFormat(format))
+	}
+
+	j, err := json.MarshalIndent(matrix, "", "  ")
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return string(j), nil
+}
+
+func groupModulePaths(modules []*TerraformModule) []string {
+	paths := make([]string, len(modules))
+	for i, module := range modules {
+		paths[i] = module.Path
+	}
+	return paths
+}
+
+func suggestedParallelism(groupSize int, maxParallelism int) int {
+	if maxParallelism > 0 && groupSize > maxParallelism {
+		return maxParallelism
+	}
+	return groupSize
+}
@@ -0,0 +1,50 @@
+package configstack
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCIMatrixModuleDeployOrderGitHub(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+	matrixJSON, err := stack.CIMatrixModuleDeployOrder("apply", CIMatrixFormatGitHub, 1)
+	require.NoError(t, err)
+
+	var matrix githubMatrix
+	require.NoError(t, json.Unmarshal([]byte(matrixJSON), &matrix))
+	assert.Equal(t, githubMatrix{Include: []githubMatrixGroup{
+		{Group: 1, Modules: []string{"/stage/mystack/vpc"}, Parallelism: 1},
+		{Group: 2, Modules: []string{"/stage/mystack/mysql", "/stage/mystack/redis"}, Parallelism: 1},
+		{Group: 3, Modules: []string{"/stage/mystack/myapp"}, Parallelism: 1},
+	}}, matrix)
+}
+
+func TestCIMatrixModuleDeployOrderGitLab(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+	matrixJSON, err := stack.CIMatrixModuleDeployOrder("destroy", CIMatrixFormatGitLab, 10)
+	require.NoError(t, err)
+
+	var matrix gitlabMatrix
+	require.NoError(t, json.Unmarshal([]byte(matrixJSON), &matrix))
+	assert.Equal(t, []map[string]string{
+		{"TERRAGRUNT_GROUP": "1", "TERRAGRUNT_MODULES": "/stage/mystack/myapp", "TERRAGRUNT_PARALLELISM": "1"},
+		{"TERRAGRUNT_GROUP": "2", "TERRAGRUNT_MODULES": "/stage/mystack/mysql /stage/mystack/redis", "TERRAGRUNT_PARALLELISM": "2"},
+		{"TERRAGRUNT_GROUP": "3", "TERRAGRUNT_MODULES": "/stage/mystack/vpc", "TERRAGRUNT_PARALLELISM": "1"},
+	}, matrix.Parallel.Matrix)
+}
+
+func TestCIMatrixModuleDeployOrderUnsupportedFormat(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+	_, err := stack.CIMatrixModuleDeployOrder("apply", "jenkins", 1)
+	assert.Equal(t, UnsupportedCIMatrixFormat("jenkins"), errors.Unwrap(err))
+}
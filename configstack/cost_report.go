@@ -0,0 +1,53 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/gruntwork-io/terragrunt/cost"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// runCost runs the cost command in the modules of the stack, capturing the cost report of each module as JSON, and then
+// writes a single report with the cost of every module, keyed by its path relative to the working dir, and the total
+// cost, in the format of the given options. The modules whose report could not be read, e.g. because their plan
+// failed, are left out.
+func (stack *Stack) runCost(terragruntOptions *options.TerragruntOptions) error {
+	if err := cost.ValidateFormat(terragruntOptions.CostFormat); err != nil {
+		return err
+	}
+
+	outStreams := make([]bytes.Buffer, len(stack.Modules))
+	for n, module := range stack.Modules {
+		module.TerragruntOptions.Writer = &outStreams[n]
+		module.TerragruntOptions.CostFormat = cost.FormatJSON
+	}
+
+	runErr := stack.runModules(terragruntOptions)
+
+	units := []cost.UnitCost{}
+	for n, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+
+		var report cost.Report
+		err := json.Unmarshal(bytes.TrimSpace(outStreams[n].Bytes()), &report)
+		if err == nil && len(report.Units) == 0 {
+			// The module was skipped, so it has no cost.
+			continue
+		}
+		if err != nil || len(report.Units) != 1 {
+			terragruntOptions.Logger.Warnf("Leaving module %s out of the cost report, as its cost could not be estimated", module.Path)
+			continue
+		}
+		unit := report.Units[0]
+		unit.Path = outputsDocumentKey(terragruntOptions, module.Path)
+		units = append(units, unit)
+	}
+
+	if err := cost.NewReport(units).Write(terragruntOptions.Writer, terragruntOptions.CostFormat); err != nil {
+		return err
+	}
+	return runErr
+}
@@ -0,0 +1,52 @@
+package configstack
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/cost"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestRunCost(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.TerraformCommand = "cost"
+
+	var out bytes.Buffer
+	terragruntOptions.Writer = &out
+
+	monthlyCosts := map[string]string{"vpc": "10.5", "app": "2.25", "failed": ""}
+	modules := []*TerraformModule{}
+	for _, name := range []string{"vpc", "app", "failed"} {
+		module := &TerraformModule{Path: filepath.Join(rootDir, name)}
+		module.TerragruntOptions = terragruntOptions.Clone(filepath.Join(module.Path, config.DefaultTerragruntConfigPath))
+		monthlyCost := monthlyCosts[name]
+		module.TerragruntOptions.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			assert.Equal(t, cost.FormatJSON, opts.CostFormat)
+			if monthlyCost == "" {
+				return fmt.Errorf("plan failed")
+			}
+			_, err := fmt.Fprintf(opts.Writer, `{"units": [{"path": ".", "monthly_cost": %s, "currency": "USD"}], "total_monthly_cost": %s, "currency": "USD"}`, monthlyCost, monthlyCost)
+			return err
+		}
+		modules = append(modules, module)
+	}
+	modules[1].Dependencies = []*TerraformModule{modules[0]}
+
+	stack := &Stack{Path: rootDir, Modules: modules}
+	err = stack.Run(terragruntOptions)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "plan failed")
+
+	assert.Equal(t, "UNIT   MONTHLY COST\napp    2.25 USD\nvpc    10.50 USD\nTOTAL  12.75 USD\n", out.String())
+}
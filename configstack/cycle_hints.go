@@ -0,0 +1,193 @@
+package configstack
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// dependencyCycleWithDeclarations returns the given cycle between the given modules along with the blocks declaring
+// each of its edges and, when --terragrunt-break-cycle-hints is set, the hints on how to break all the cycles between
+// the modules.
+func dependencyCycleWithDeclarations(cycle DependencyCycle, modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) error {
+	modulesByPath := map[string]*TerraformModule{}
+	for _, module := range modules {
+		modulesByPath[module.Path] = module
+	}
+
+	declarations := []config.DependencyDeclaration{}
+	for i := 0; i < len(cycle)-1; i++ {
+		declarations = append(declarations, moduleDependencyDeclaration(modulesByPath[cycle[i]], cycle[i+1]))
+	}
+
+	err := config.DependencyCycleWithDeclarations{Cycle: cycle, Declarations: declarations}
+	if terragruntOptions.BreakCycleHints {
+		err.Hints = breakCycleHints(modules)
+	}
+	return errors.WithStackTrace(err)
+}
+
+// moduleDependencyDeclaration returns the block of the config of the given module declaring its dependency on the
+// module at the given path.
+func moduleDependencyDeclaration(module *TerraformModule, dependencyPath string) config.DependencyDeclaration {
+	if module.TerragruntOptions == nil {
+		return config.DependencyDeclaration{From: module.Path, To: dependencyPath}
+	}
+	declaration := config.FindDependencyDeclaration(&module.Config, module.TerragruntOptions.TerragruntConfigPath, dependencyPath)
+	declaration.From = module.Path
+	return declaration
+}
+
+// breakCycleHints analyzes all the cycles between the given modules, rather than only the first one found, and returns
+// the dependencies whose removal breaks them. The modules are grouped in strongly connected components, i.e. the
+// largest groups of modules that all depend on each other, and the dependencies between the modules of a group are
+// tried one at a time. The dependencies only declared in a dependencies block come first, as no outputs are read
+// through them, so removing them is the least disruptive.
+func breakCycleHints(modules []*TerraformModule) []string {
+	hints := []string{}
+	for _, component := range stronglyConnectedComponents(modules) {
+		inComponent := map[string]bool{}
+		paths := []string{}
+		for _, module := range component {
+			inComponent[module.Path] = true
+			paths = append(paths, module.Path)
+		}
+
+		breaking := []config.DependencyDeclaration{}
+		for _, module := range component {
+			for _, dependency := range module.Dependencies {
+				if !inComponent[dependency.Path] {
+					continue
+				}
+				if !hasCycle(component, module.Path, dependency.Path) {
+					breaking = append(breaking, moduleDependencyDeclaration(module, dependency.Path))
+				}
+			}
+		}
+
+		if len(breaking) == 0 {
+			hints = append(hints, fmt.Sprintf("No single dependency breaks all the cycles between %s, at least two of the dependencies between them must be removed", strings.Join(paths, ", ")))
+			continue
+		}
+
+		sort.SliceStable(breaking, func(i, j int) bool { return breaking[i].OnlyOrdersRun() && !breaking[j].OnlyOrdersRun() })
+		for _, declaration := range breaking {
+			hint := fmt.Sprintf("Removing %s breaks all the cycles between %s", declaration.String(), strings.Join(paths, ", "))
+			if declaration.OnlyOrdersRun() {
+				hint += ", and only changes the run order, as no outputs are read through it"
+			} else {
+				hint += ", but the outputs read through it must then be passed in another way, e.g. by moving them to a module both depend on"
+			}
+			hints = append(hints, hint)
+		}
+	}
+	return hints
+}
+
+// stronglyConnectedComponents returns the groups of the given modules that all depend on each other, directly or not,
+// and therefore form cycles, using Tarjan's algorithm. The modules that are not part of a cycle are left out.
+func stronglyConnectedComponents(modules []*TerraformModule) [][]*TerraformModule {
+	index := 0
+	indexes := map[string]int{}
+	lowLinks := map[string]int{}
+	onStack := map[string]bool{}
+	stack := []*TerraformModule{}
+	components := [][]*TerraformModule{}
+
+	var visit func(module *TerraformModule)
+	visit = func(module *TerraformModule) {
+		indexes[module.Path] = index
+		lowLinks[module.Path] = index
+		index++
+		stack = append(stack, module)
+		onStack[module.Path] = true
+
+		selfLoop := false
+		for _, dependency := range module.Dependencies {
+			if dependency.Path == module.Path {
+				selfLoop = true
+			}
+			if _, visited := indexes[dependency.Path]; !visited {
+				visit(dependency)
+				lowLinks[module.Path] = min(lowLinks[module.Path], lowLinks[dependency.Path])
+			} else if onStack[dependency.Path] {
+				lowLinks[module.Path] = min(lowLinks[module.Path], indexes[dependency.Path])
+			}
+		}
+
+		if lowLinks[module.Path] != indexes[module.Path] {
+			return
+		}
+		component := []*TerraformModule{}
+		for {
+			top := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[top.Path] = false
+			component = append(component, top)
+			if top.Path == module.Path {
+				break
+			}
+		}
+		if len(component) > 1 || selfLoop {
+			sort.Slice(component, func(i, j int) bool { return component[i].Path < component[j].Path })
+			components = append(components, component)
+		}
+	}
+
+	for _, module := range modules {
+		if _, visited := indexes[module.Path]; !visited {
+			visit(module)
+		}
+	}
+
+	sort.Slice(components, func(i, j int) bool { return components[i][0].Path < components[j][0].Path })
+	return components
+}
+
+// hasCycle returns true if there is a cycle between the given modules without the dependency of the module at fromPath
+// on the module at toPath.
+func hasCycle(modules []*TerraformModule, fromPath string, toPath string) bool {
+	inModules := map[string]bool{}
+	for _, module := range modules {
+		inModules[module.Path] = true
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	states := map[string]int{}
+
+	var visit func(module *TerraformModule) bool
+	visit = func(module *TerraformModule) bool {
+		states[module.Path] = visiting
+		for _, dependency := range module.Dependencies {
+			if !inModules[dependency.Path] || (module.Path == fromPath && dependency.Path == toPath) {
+				continue
+			}
+			switch states[dependency.Path] {
+			case visiting:
+				return true
+			case unvisited:
+				if visit(dependency) {
+					return true
+				}
+			}
+		}
+		states[module.Path] = visited
+		return false
+	}
+
+	for _, module := range modules {
+		if states[module.Path] == unvisited && visit(module) {
+			return true
+		}
+	}
+	return false
+}
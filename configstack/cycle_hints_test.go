@@ -0,0 +1,97 @@
+package configstack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestBreakCycleHints(t *testing.T) {
+	t.Parallel()
+
+	// a -> b, not part of a cycle
+	b := &TerraformModule{Path: "b"}
+	a := &TerraformModule{Path: "a", Dependencies: []*TerraformModule{b}}
+
+	// l -> m -> n -> l, which any of the dependencies breaks
+	l := &TerraformModule{Path: "l"}
+	n := &TerraformModule{Path: "n", Dependencies: []*TerraformModule{l}}
+	m := &TerraformModule{Path: "m", Dependencies: []*TerraformModule{n}}
+	l.Dependencies = append(l.Dependencies, m)
+
+	// p <-> q <-> r, which no single dependency breaks
+	p := &TerraformModule{Path: "p"}
+	q := &TerraformModule{Path: "q"}
+	r := &TerraformModule{Path: "r"}
+	p.Dependencies = []*TerraformModule{q}
+	q.Dependencies = []*TerraformModule{p, r}
+	r.Dependencies = []*TerraformModule{q}
+
+	hints := breakCycleHints([]*TerraformModule{a, b, l, m, n, p, q, r})
+	assert.Equal(t, []string{
+		"Removing l -> m breaks all the cycles between l, m, n, but the outputs read through it must then be passed in another way, e.g. by moving them to a module both depend on",
+		"Removing m -> n breaks all the cycles between l, m, n, but the outputs read through it must then be passed in another way, e.g. by moving them to a module both depend on",
+		"Removing n -> l breaks all the cycles between l, m, n, but the outputs read through it must then be passed in another way, e.g. by moving them to a module both depend on",
+		"No single dependency breaks all the cycles between p, q, r, at least two of the dependencies between them must be removed",
+	}, hints)
+}
+
+func TestDependencyCycleDeclarations(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	writeCycleTestConfig(t, rootDir, "app", `
+terraform {
+  source = "."
+}
+
+dependency "vpc" {
+  config_path = "../vpc"
+}
+`)
+	writeCycleTestConfig(t, rootDir, "vpc", `
+terraform {
+  source = "."
+}
+
+dependencies {
+  paths = ["../app"]
+}
+`)
+
+	opts, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	opts.WorkingDir = rootDir
+	opts.BreakCycleHints = true
+
+	_, err = FindStackInSubfolders(opts, nil)
+	require.Error(t, err)
+
+	cycleErr, ok := errors.Unwrap(err).(config.DependencyCycleWithDeclarations)
+	require.True(t, ok, "unexpected error: %v", err)
+
+	appDir, vpcDir := filepath.Join(rootDir, "app"), filepath.Join(rootDir, "vpc")
+	assert.Equal(t, DependencyCycle([]string{appDir, vpcDir, appDir}), cycleErr.Cycle)
+	require.Len(t, cycleErr.Declarations, 2)
+	assert.Equal(t, fmt.Sprintf(`%s -> %s: dependency "vpc" at %s:6`, appDir, vpcDir, filepath.Join(appDir, config.DefaultTerragruntConfigPath)), cycleErr.Declarations[0].String())
+	assert.Equal(t, fmt.Sprintf(`%s -> %s: dependencies at %s:6`, vpcDir, appDir, filepath.Join(vpcDir, config.DefaultTerragruntConfigPath)), cycleErr.Declarations[1].String())
+
+	// The dependency that only orders the run comes first in the hints.
+	require.Len(t, cycleErr.Hints, 2)
+	assert.Contains(t, cycleErr.Hints[0], "dependencies at")
+	assert.Contains(t, cycleErr.Hints[1], `dependency "vpc" at`)
+}
+
+func writeCycleTestConfig(t *testing.T, rootDir string, name string, contents string) {
+	configPath := filepath.Join(rootDir, name, config.DefaultTerragruntConfigPath)
+	require.NoError(t, os.MkdirAll(filepath.Dir(configPath), os.ModePerm))
+	require.NoError(t, os.WriteFile(configPath, []byte(contents), 0644))
+}
@@ -1,6 +1,9 @@
 package configstack
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 // Custom error types
 
@@ -32,3 +35,27 @@ type InfiniteRecursion struct {
 func (err InfiniteRecursion) Error() string {
 	return fmt.Sprintf("Hit what seems to be an infinite recursion after going %d levels deep. Please check for a circular dependency! Modules involved: %v", err.RecursionLevel, err.Modules)
 }
+
+type ModuleNotInStack struct {
+	ModulePath string
+	StackPath  string
+}
+
+func (err ModuleNotInStack) Error() string {
+	return fmt.Sprintf("Module %s was not found in the stack at %s", err.ModulePath, err.StackPath)
+}
+
+type UnsupportedCIMatrixFormat string
+
+func (format UnsupportedCIMatrixFormat) Error() string {
+	return fmt.Sprintf("Unsupported CI matrix format %s. Supported formats are: %s", string(format), strings.Join(CIMatrixFormats, ", "))
+}
+
+type StateCollision struct {
+	Location    string
+	ModulePaths []string
+}
+
+func (err StateCollision) Error() string {
+	return fmt.Sprintf("Modules %s store their state at the same location %s, and would overwrite each other's state. Check the key of the remote_state config of these modules.", strings.Join(err.ModulePaths, " and "), err.Location)
+}
@@ -0,0 +1,98 @@
+package configstack
+
+import (
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// ExternalDependent is a module that is not part of the set of modules a run-all command processes, but depends on
+// some of them.
+type ExternalDependent struct {
+	Path         string
+	Dependencies []string
+}
+
+// FindExternalDependents returns the modules that are not processed by this stack, but depend on modules that are.
+// Besides the modules of the stack that were excluded, the modules found in every parent directory of the working
+// directory, up to the top level directory of its git repository, are checked.
+func (stack *Stack) FindExternalDependents(terragruntOptions *options.TerragruntOptions) []ExternalDependent {
+	// The excluded modules of the stack are not processed, so they are always candidates.
+	candidates := append([]*TerraformModule{}, stack.Modules...)
+
+	gitTopLevelDir, err := shell.GitTopLevelDir(terragruntOptions, terragruntOptions.WorkingDir)
+	if err != nil {
+		terragruntOptions.Logger.Debugf("Could not find the git repository of %s, only excluded modules will be checked for external dependents: %v", terragruntOptions.WorkingDir, err)
+		return findExternalDependents(stack.Modules, candidates)
+	}
+	pathsToCheck, err := buildDirList(terragruntOptions, gitTopLevelDir)
+	if err != nil {
+		terragruntOptions.Logger.Debugf("Failed to list the parent directories of %s, only excluded modules will be checked for external dependents: %v", terragruntOptions.WorkingDir, err)
+		return findExternalDependents(stack.Modules, candidates)
+	}
+
+	for _, dir := range pathsToCheck {
+		dirOptions := terragruntOptions.Clone(filepath.Join(dir, config.DefaultTerragruntConfigPath))
+		dirOptions.WorkingDir = dir
+		dirOptions.NonInteractive = true
+		// Every module in the directory has to be found, regardless of the filters used to build the stack.
+		dirOptions.ExcludeDirs = []string{}
+		dirOptions.IncludeDirs = []string{}
+		dirOptions.StrictInclude = false
+		dirOptions.ModulesThatInclude = []string{}
+		dirOptions.IgnoreExternalDependencies = true
+
+		dirStack, err := FindStackInSubfolders(dirOptions, nil)
+		if err != nil {
+			// Stacks in parent directories can fail to build, e.g. because of root configurations that are only
+			// meant to be included, so the directory is skipped like in FindWhereWorkingDirIsIncluded.
+			terragruntOptions.Logger.Debugf("Failed to build module stack in %s: %v", dir, err)
+			continue
+		}
+		candidates = append(candidates, dirStack.Modules...)
+	}
+
+	return findExternalDependents(stack.Modules, candidates)
+}
+
+// findExternalDependents returns the candidates that are not processed in the given stack modules, but depend on
+// modules that are, sorted by path.
+func findExternalDependents(stackModules []*TerraformModule, candidates []*TerraformModule) []ExternalDependent {
+	processed := map[string]bool{}
+	for _, module := range stackModules {
+		if !module.FlagExcluded && !module.AssumeAlreadyApplied {
+			processed[module.Path] = true
+		}
+	}
+
+	dependentsByPath := map[string]ExternalDependent{}
+	for _, candidate := range candidates {
+		if processed[candidate.Path] {
+			continue
+		}
+		if _, alreadyFound := dependentsByPath[candidate.Path]; alreadyFound {
+			continue
+		}
+
+		dependencies := []string{}
+		for _, dependency := range candidate.Dependencies {
+			if processed[dependency.Path] {
+				dependencies = append(dependencies, dependency.Path)
+			}
+		}
+		if len(dependencies) > 0 {
+			sort.Strings(dependencies)
+			dependentsByPath[candidate.Path] = ExternalDependent{Path: candidate.Path, Dependencies: dependencies}
+		}
+	}
+
+	dependents := []ExternalDependent{}
+	for _, dependent := range dependentsByPath {
+		dependents = append(dependents, dependent)
+	}
+	sort.Slice(dependents, func(i, j int) bool { return dependents[i].Path < dependents[j].Path })
+	return dependents
+}
@@ -0,0 +1,39 @@
+package configstack
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindExternalDependents(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+	modules := map[string]*TerraformModule{}
+	for _, module := range stack.Modules {
+		modules[module.Path] = module
+	}
+
+	// Only destroy vpc and mysql: redis, myapp and lambda depend on them but are not processed.
+	stackModules := []*TerraformModule{modules["/stage/mystack/vpc"], modules["/stage/mystack/mysql"]}
+
+	dependents := findExternalDependents(stackModules, stack.Modules)
+	assert.Equal(t, []ExternalDependent{
+		{Path: "/stage/mystack/lambda", Dependencies: []string{"/stage/mystack/vpc"}},
+		{Path: "/stage/mystack/myapp", Dependencies: []string{"/stage/mystack/mysql"}},
+		{Path: "/stage/mystack/redis", Dependencies: []string{"/stage/mystack/vpc"}},
+	}, dependents)
+}
+
+func TestFindExternalDependentsOfWholeStack(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+
+	// Neither the excluded account-baseline nor the already applied lambda are destroyed, and lambda depends on vpc.
+	dependents := findExternalDependents(stack.Modules, stack.Modules)
+	assert.Equal(t, []ExternalDependent{
+		{Path: "/stage/mystack/lambda", Dependencies: []string{"/stage/mystack/vpc"}},
+	}, dependents)
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
 	"sort"
 	"strings"
+	"sync"
 
 	"github.com/sirupsen/logrus"
 
@@ -16,6 +18,7 @@ import (
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/terraform"
 	"github.com/gruntwork-io/terragrunt/util"
 )
 
@@ -75,7 +78,12 @@ func ResolveTerraformModules(terragruntConfigPaths []string, terragruntOptions *
 
 	includedModulesWithExcluded := flagExcludedDirs(includedModules, terragruntOptions)
 
-	finalModules, err := flagModulesThatDontInclude(includedModulesWithExcluded, terragruntOptions)
+	includedModulesThatInclude, err := flagModulesThatDontInclude(includedModulesWithExcluded, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	finalModules, err := flagModulesNotChangedSince(includedModulesThatInclude, terragruntOptions)
 	if err != nil {
 		return nil, err
 	}
@@ -212,45 +220,219 @@ func flagModulesThatDontInclude(modules []*TerraformModule, terragruntOptions *o
 	return modules, nil
 }
 
+// flagModulesNotChangedSince iterates over a module slice and flags all modules that are not affected by the files
+// changed since the git ref specified on the TerragruntOptions ChangedSince attribute, nor depend on a module that is,
+// as excluded.
+func flagModulesNotChangedSince(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) ([]*TerraformModule, error) {
+
+	// If no ChangedSince is specified return the modules list instantly
+	if terragruntOptions.ChangedSince == "" {
+		return modules, nil
+	}
+
+	changedFiles, err := shell.GitChangedFilesSince(terragruntOptions, terragruntOptions.WorkingDir, terragruntOptions.ChangedSince)
+	if err != nil {
+		return nil, err
+	}
+	terragruntOptions.Logger.Debugf("Files changed since %s: %v", terragruntOptions.ChangedSince, changedFiles)
+
+	return flagModulesNotAffectedByFiles(modules, changedFiles)
+}
+
+// flagModulesNotAffectedByFiles flags all modules that are not affected by any of the given files, nor depend on a
+// module that is, as excluded. A module is affected by the files under its directory (unless they belong to a nested
+// module), the files it includes, and the files of its terraform source, if it is local.
+func flagModulesNotAffectedByFiles(modules []*TerraformModule, changedFiles []string) ([]*TerraformModule, error) {
+	modulesByPath := map[string]*TerraformModule{}
+	for _, module := range modules {
+		modulesByPath[module.Path] = module
+	}
+
+	affected := map[string]bool{}
+	for _, file := range changedFiles {
+		// Attribute the file to the closest module whose directory contains it.
+		for dir := filepath.Dir(file); ; dir = filepath.Dir(dir) {
+			if _, isModule := modulesByPath[dir]; isModule {
+				affected[dir] = true
+				break
+			}
+			if dir == filepath.Dir(dir) {
+				break
+			}
+		}
+	}
+
+	for _, module := range modules {
+		if affected[module.Path] {
+			continue
+		}
+
+		for _, includeConfig := range module.Config.ProcessedIncludes {
+			includePath, err := util.CanonicalPath(includeConfig.Path, module.Path)
+			if err != nil {
+				return nil, err
+			}
+			if util.ListContainsElement(changedFiles, includePath) {
+				affected[module.Path] = true
+			}
+		}
+
+		sourceDir, err := localSourceDir(module)
+		if err != nil {
+			return nil, err
+		}
+		if sourceDir == "" {
+			continue
+		}
+		for _, file := range changedFiles {
+			if util.HasPathPrefix(file, sourceDir) {
+				affected[module.Path] = true
+				break
+			}
+		}
+	}
+
+	changedModulePaths := []string{}
+	for path := range affected {
+		changedModulePaths = append(changedModulePaths, path)
+	}
+	for _, dependent := range findTransitiveDependents(modules, changedModulePaths) {
+		affected[dependent.Path] = true
+	}
+
+	for _, module := range modules {
+		if !affected[module.Path] {
+			module.FlagExcluded = true
+		}
+	}
+
+	return modules, nil
+}
+
+// localSourceDir returns the canonical path of the root directory of the terraform source of the given module if the
+// source is a local path, or an empty string otherwise. The whole root directory is copied when the source is
+// downloaded, so every file in it can affect the module.
+func localSourceDir(module *TerraformModule) (string, error) {
+	if module.TerragruntOptions == nil {
+		return "", nil
+	}
+
+	// The --terragrunt-source override takes precedence over the source in the configuration.
+	sourceURL := module.TerragruntOptions.Source
+	if sourceURL == "" && module.Config.Terraform != nil && module.Config.Terraform.Source != nil {
+		sourceURL = *module.Config.Terraform.Source
+	}
+	if sourceURL == "" {
+		return "", nil
+	}
+
+	source, err := terraform.NewSource(sourceURL, module.TerragruntOptions.DownloadDir, module.Path, module.TerragruntOptions.Logger)
+	if err != nil {
+		return "", err
+	}
+	if !terraform.IsLocalSource(source.CanonicalSourceURL) {
+		return "", nil
+	}
+	return source.CanonicalSourceURL.Path, nil
+}
+
 // Go through each of the given Terragrunt configuration files and resolve the module that configuration file represents
 // into a TerraformModule struct. Note that this method will NOT fill in the Dependencies field of the TerraformModule
 // struct (see the crosslinkDependencies method for that). Return a map from module path to TerraformModule struct.
+//
+// The configuration files are parsed concurrently, see resolveTerraformModulesConcurrently, and the modules are then
+// added to the map in the order of the given paths, so that the result and the reported error don't depend on which
+// parsing finishes first.
 func resolveModules(canonicalTerragruntConfigPaths []string, terragruntOptions *options.TerragruntOptions, childTerragruntConfig *config.TerragruntConfig, howTheseModulesWereFound string) (map[string]*TerraformModule, error) {
 	moduleMap := map[string]*TerraformModule{}
 
+	modules, errs := resolveTerraformModulesConcurrently(canonicalTerragruntConfigPaths, terragruntOptions, childTerragruntConfig, howTheseModulesWereFound)
+	for i, module := range modules {
+		if errs[i] != nil {
+			return moduleMap, errs[i]
+		}
+		if module == nil {
+			continue
+		}
+		// A folder with several configuration files is resolved once, from the first of them.
+		if _, ok := moduleMap[module.Path]; !ok {
+			moduleMap[module.Path] = module
+		}
+	}
+
 	for _, terragruntConfigPath := range canonicalTerragruntConfigPaths {
-		module, err := resolveTerraformModule(terragruntConfigPath, moduleMap, terragruntOptions, childTerragruntConfig, howTheseModulesWereFound)
+		modulePath, err := util.CanonicalPath(filepath.Dir(terragruntConfigPath), ".")
 		if err != nil {
 			return moduleMap, err
 		}
+		module, ok := moduleMap[modulePath]
+		if !ok {
+			continue
+		}
 
-		if module != nil {
-			moduleMap[module.Path] = module
-
-			dependencies, err := resolveDependenciesForModule(module, moduleMap, terragruntOptions, childTerragruntConfig, true)
-			if err != nil {
-				return moduleMap, err
-			}
-			moduleMap = collections.MergeMaps(moduleMap, dependencies)
+		dependencies, err := resolveDependenciesForModule(module, moduleMap, terragruntOptions, childTerragruntConfig, true)
+		if err != nil {
+			return moduleMap, err
 		}
+		moduleMap = collections.MergeMaps(moduleMap, dependencies)
 	}
 
 	return moduleMap, nil
 }
 
+// resolveTerraformModulesConcurrently resolves the module of each of the given Terragrunt configuration files with a
+// bounded pool of workers, as parsing thousands of configuration files one at a time makes run-all slow to start in
+// large repos. Return the module and the error of each configuration file, at the same index as its path.
+func resolveTerraformModulesConcurrently(terragruntConfigPaths []string, terragruntOptions *options.TerragruntOptions, childTerragruntConfig *config.TerragruntConfig, howTheseModulesWereFound string) ([]*TerraformModule, []error) {
+	modules := make([]*TerraformModule, len(terragruntConfigPaths))
+	errs := make([]error, len(terragruntConfigPaths))
+
+	indexes := make(chan int)
+	waitGroup := sync.WaitGroup{}
+	for worker := 0; worker < configParseWorkers(terragruntOptions, len(terragruntConfigPaths)); worker++ {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			for i := range indexes {
+				// Each worker writes to its own indexes, so the slices don't need a lock.
+				modules[i], errs[i] = resolveTerraformModule(terragruntConfigPaths[i], terragruntOptions, childTerragruntConfig, howTheseModulesWereFound)
+			}
+		}()
+	}
+	for i := range terragruntConfigPaths {
+		indexes <- i
+	}
+	close(indexes)
+	waitGroup.Wait()
+
+	return modules, errs
+}
+
+// configParseWorkers returns the number of Terragrunt configuration files to parse at the same time. Parsing is mostly
+// bound by the CPU, so this is the number of CPUs, unless --terragrunt-parallelism or the number of files is lower.
+func configParseWorkers(terragruntOptions *options.TerragruntOptions, numConfigs int) int {
+	workers := runtime.NumCPU()
+	if terragruntOptions.Parallelism < workers {
+		workers = terragruntOptions.Parallelism
+	}
+	if numConfigs < workers {
+		workers = numConfigs
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	return workers
+}
+
 // Create a TerraformModule struct for the Terraform module specified by the given Terragrunt configuration file path.
 // Note that this method will NOT fill in the Dependencies field of the TerraformModule struct (see the
 // crosslinkDependencies method for that).
-func resolveTerraformModule(terragruntConfigPath string, moduleMap map[string]*TerraformModule, terragruntOptions *options.TerragruntOptions, childTerragruntConfig *config.TerragruntConfig, howThisModuleWasFound string) (*TerraformModule, error) {
+func resolveTerraformModule(terragruntConfigPath string, terragruntOptions *options.TerragruntOptions, childTerragruntConfig *config.TerragruntConfig, howThisModuleWasFound string) (*TerraformModule, error) {
 	modulePath, err := util.CanonicalPath(filepath.Dir(terragruntConfigPath), ".")
 	if err != nil {
 		return nil, err
 	}
 
-	if _, ok := moduleMap[modulePath]; ok {
-		return nil, nil
-	}
-
 	// Clone the options struct so we don't modify the original one. This is especially important as run-all operations
 	// happen concurrently.
 	opts := terragruntOptions.Clone(terragruntConfigPath)
@@ -287,6 +469,12 @@ func resolveTerraformModule(terragruntConfigPath string, moduleMap map[string]*T
 			// Need for parsing out the dependencies
 			config.DependenciesBlock,
 			config.DependencyBlock,
+
+			// Need for scheduling the modules
+			config.TerragruntPriority,
+
+			// Need for filtering the modules on their tags
+			config.TerragruntTags,
 		},
 	)
 	if err != nil {
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"testing"
 
@@ -118,6 +120,8 @@ func TestResolveTerraformModulesReadConfigFromParentConfig(t *testing.T) {
 			"generate":                      map[string]interface{}{},
 			"iam_assume_role_duration":      interface{}(nil),
 			"iam_assume_role_session_name":  "",
+			"iam_mfa_serial":                "",
+			"iam_web_identity_token":        "",
 			"iam_role":                      "",
 			"inputs":                        interface{}(nil),
 			"locals":                        cfg.Locals,
@@ -844,6 +848,145 @@ func TestResolveTerraformModuleNoTerraformConfig(t *testing.T) {
 	assertModuleListsEqual(t, expected, actualModules)
 }
 
+func TestResolveTerraformModulesOneParseWorker(t *testing.T) {
+	t.Parallel()
+
+	configPaths := []string{"../test/fixture-modules/module-a/" + config.DefaultTerragruntConfigPath, "../test/fixture-modules/module-b/module-b-child/" + config.DefaultTerragruntConfigPath, "../test/fixture-modules/module-c/" + config.DefaultTerragruntConfigPath, "../test/fixture-modules/module-d/" + config.DefaultTerragruntConfigPath}
+
+	concurrentModules, err := ResolveTerraformModules(configPaths, mockOptions, nil, mockHowThesePathsWereFound)
+	require.NoError(t, err)
+
+	serialOptions := mockOptions.Clone(mockOptions.TerragruntConfigPath)
+	serialOptions.Parallelism = 1
+	serialModules, err := ResolveTerraformModules(configPaths, serialOptions, nil, mockHowThesePathsWereFound)
+	require.NoError(t, err)
+
+	moduleStrings := func(modules []*TerraformModule) []string {
+		strs := []string{}
+		for _, module := range modules {
+			strs = append(strs, module.String())
+		}
+		sort.Strings(strs)
+		return strs
+	}
+	assert.Equal(t, moduleStrings(serialModules), moduleStrings(concurrentModules))
+}
+
+func TestResolveTerraformModulesReportsFirstErrorInOrder(t *testing.T) {
+	t.Parallel()
+
+	configPaths := []string{
+		"../test/fixture-modules/module-a/" + config.DefaultTerragruntConfigPath,
+		"../test/fixture-modules/module-does-not-exist/" + config.DefaultTerragruntConfigPath,
+		"../test/fixture-modules/module-also-does-not-exist/" + config.DefaultTerragruntConfigPath,
+	}
+
+	_, actualErr := ResolveTerraformModules(configPaths, mockOptions, nil, mockHowThesePathsWereFound)
+	require.Error(t, actualErr)
+
+	underlying, ok := errors.Unwrap(actualErr).(ErrorProcessingModule)
+	require.True(t, ok)
+	assert.Equal(t, canonical(t, configPaths[1]), underlying.ModulePath)
+}
+
+func TestConfigParseWorkers(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		parallelism int
+		numConfigs  int
+		expected    int
+	}{
+		{"LimitedByCPUs", options.DefaultParallelism, 10 * runtime.NumCPU(), runtime.NumCPU()},
+		{"LimitedByParallelism", 1, 10 * runtime.NumCPU(), 1},
+		{"LimitedByConfigs", options.DefaultParallelism, 1, 1},
+		{"NoConfigs", options.DefaultParallelism, 0, 1},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			opts := mockOptions.Clone(mockOptions.TerragruntConfigPath)
+			opts.Parallelism = testCase.parallelism
+			assert.Equal(t, testCase.expected, configParseWorkers(opts, testCase.numConfigs))
+		})
+	}
+}
+
+func TestFlagModulesNotAffectedByFiles(t *testing.T) {
+	t.Parallel()
+
+	// - vpc; uses the local module at /infra/modules//vpc
+	// - mysql; depends on vpc
+	// - app; depends on mysql, includes /infra/live/root.hcl
+	// - app/nested; a module nested in the directory of app
+	// - other; no dependencies
+	newModules := func() []*TerraformModule {
+		vpc := &TerraformModule{
+			Path:              "/infra/live/vpc",
+			Config:            config.TerragruntConfig{Terraform: &config.TerraformConfig{Source: ptr("../../modules//vpc")}},
+			TerragruntOptions: mockOptions.Clone("/infra/live/vpc/" + config.DefaultTerragruntConfigPath),
+		}
+		mysql := &TerraformModule{
+			Path:              "/infra/live/mysql",
+			Dependencies:      []*TerraformModule{vpc},
+			TerragruntOptions: mockOptions.Clone("/infra/live/mysql/" + config.DefaultTerragruntConfigPath),
+		}
+		app := &TerraformModule{
+			Path:         "/infra/live/app",
+			Dependencies: []*TerraformModule{mysql},
+			Config: config.TerragruntConfig{
+				ProcessedIncludes: map[string]config.IncludeConfig{"root": {Path: "../root.hcl"}},
+			},
+			TerragruntOptions: mockOptions.Clone("/infra/live/app/" + config.DefaultTerragruntConfigPath),
+		}
+		nested := &TerraformModule{
+			Path:              "/infra/live/app/nested",
+			TerragruntOptions: mockOptions.Clone("/infra/live/app/nested/" + config.DefaultTerragruntConfigPath),
+		}
+		other := &TerraformModule{
+			Path:              "/infra/live/other",
+			TerragruntOptions: mockOptions.Clone("/infra/live/other/" + config.DefaultTerragruntConfigPath),
+		}
+		return []*TerraformModule{vpc, mysql, app, nested, other}
+	}
+
+	testCases := []struct {
+		name         string
+		changedFiles []string
+		expected     []string
+	}{
+		{"file in module", []string{"/infra/live/mysql/terragrunt.hcl"}, []string{"/infra/live/app", "/infra/live/mysql"}},
+		{"file in nested module", []string{"/infra/live/app/nested/main.tf"}, []string{"/infra/live/app/nested"}},
+		{"included file", []string{"/infra/live/root.hcl"}, []string{"/infra/live/app"}},
+		{"local source", []string{"/infra/modules/vpc/main.tf"}, []string{"/infra/live/app", "/infra/live/mysql", "/infra/live/vpc"}},
+		{"unrelated file", []string{"/infra/README.md"}, []string{}},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			modules, err := flagModulesNotAffectedByFiles(newModules(), testCase.changedFiles)
+			require.NoError(t, err)
+
+			notExcluded := []string{}
+			for _, module := range modules {
+				if !module.FlagExcluded {
+					notExcluded = append(notExcluded, module.Path)
+				}
+			}
+			sort.Strings(notExcluded)
+			assert.Equal(t, testCase.expected, notExcluded)
+		})
+	}
+}
+
 func ptr(str string) *string {
 	return &str
 }
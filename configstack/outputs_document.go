@@ -0,0 +1,54 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// OutputFormatJSON is the format of run-all output that prints the outputs of all the modules as a single JSON document.
+const OutputFormatJSON = "json"
+
+// writeOutputsDocument writes the outputs of every module that was run, as captured from `terraform output -json`, as a
+// single JSON document that maps the path of each module, relative to the working dir, to its outputs. The modules
+// whose output is not valid JSON, e.g. because the command failed, are left out.
+func (stack *Stack) writeOutputsDocument(terragruntOptions *options.TerragruntOptions, outStreams []bytes.Buffer) error {
+	document := map[string]json.RawMessage{}
+	for n, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+
+		outputs := bytes.TrimSpace(outStreams[n].Bytes())
+		if !json.Valid(outputs) {
+			terragruntOptions.Logger.Warnf("Leaving module %s out of the outputs document, as its outputs are not valid JSON", module.Path)
+			continue
+		}
+		document[outputsDocumentKey(terragruntOptions, module.Path)] = outputs
+	}
+
+	// The keys of the maps are sorted when marshalled, so the document doesn't depend on the order the modules ran in.
+	documentJSON, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := terragruntOptions.Writer.Write(append(documentJSON, '\n')); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// outputsDocumentKey returns the given module path relative to the working dir, so that the document doesn't depend on
+// where the working dir is checked out. The paths outside of the working dir are kept as is.
+func outputsDocumentKey(terragruntOptions *options.TerragruntOptions, path string) string {
+	rel, err := filepath.Rel(terragruntOptions.WorkingDir, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
@@ -0,0 +1,62 @@
+package configstack
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestWriteOutputsDocument(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("/stage/terragrunt.hcl")
+	require.NoError(t, err)
+	terragruntOptions.WorkingDir = "/stage"
+
+	var out bytes.Buffer
+	terragruntOptions.Writer = &out
+
+	stack := &Stack{
+		Path: "/stage",
+		Modules: []*TerraformModule{
+			{Path: "/stage/vpc"},
+			{Path: "/stage/mysql"},
+			{Path: "/stage/app"},
+			{Path: "/stage/excluded", FlagExcluded: true},
+			{Path: "/shared/dns"},
+		},
+	}
+
+	outStreams := make([]bytes.Buffer, len(stack.Modules))
+	outStreams[0].WriteString(`{"vpc_id": {"sensitive": false, "type": "string", "value": "vpc-123"}}` + "\n")
+	outStreams[1].WriteString("{}\n")
+	outStreams[2].WriteString("Error: Invalid reference\n")
+	outStreams[3].WriteString("{}\n")
+	outStreams[4].WriteString(`{"zone_id": {"sensitive": false, "type": "string", "value": "Z123"}}`)
+
+	require.NoError(t, stack.writeOutputsDocument(terragruntOptions, outStreams))
+
+	expected := `{
+  "/shared/dns": {
+    "zone_id": {
+      "sensitive": false,
+      "type": "string",
+      "value": "Z123"
+    }
+  },
+  "mysql": {},
+  "vpc": {
+    "vpc_id": {
+      "sensitive": false,
+      "type": "string",
+      "value": "vpc-123"
+    }
+  }
+}
+`
+	assert.Equal(t, expected, out.String())
+}
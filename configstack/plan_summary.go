@@ -0,0 +1,202 @@
+package configstack
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// PlanSummary is a roll-up of the changes planned in every module of a run-all plan.
+type PlanSummary struct {
+	Modules []ModulePlanChanges `json:"modules"`
+	Total   PlanChanges         `json:"total"`
+}
+
+// PlanChanges holds the number of resources a plan adds, changes and destroys.
+type PlanChanges struct {
+	Add     int `json:"add"`
+	Change  int `json:"change"`
+	Destroy int `json:"destroy"`
+}
+
+// ModulePlanChanges holds the changes planned in a single module. Found is false when no plan was recorded for the
+// module, e.g. because the plan failed.
+type ModulePlanChanges struct {
+	Path  string `json:"path"`
+	Found bool   `json:"found"`
+	PlanChanges
+}
+
+var (
+	ansiEscapeRegex = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+	// Newer versions of terraform also report the number of resources to import before the other counts.
+	planChangesRegex = regexp.MustCompile(`Plan: (?:\d+ to import, )?(\d+) to add, (\d+) to change, (\d+) to destroy`)
+)
+
+// planOutputLine represents the fields of a line of JSON output that are relevant to find the change summary. The line
+// is either a message of `terraform plan -json`, or a record of the json-stream log format wrapping a line of output.
+type planOutputLine struct {
+	Type    string `json:"type"`
+	Changes *struct {
+		Add    int `json:"add"`
+		Change int `json:"change"`
+		Remove int `json:"remove"`
+	} `json:"changes"`
+	Line string `json:"line"`
+}
+
+// modulePlanChanges maps the path of the config of a module to the changes of the plan of the module in this run.
+var modulePlanChanges = sync.Map{}
+
+// StorePlanChanges records the changes of the plan of the module of the given config, read from the plan in the format
+// of `terraform show -json`, for the plan summary of run-all.
+func StorePlanChanges(configPath string, planJSON []byte) error {
+	resourceChanges, err := config.ParsePlanResourceChanges(planJSON)
+	if err != nil {
+		return err
+	}
+	modulePlanChanges.Store(util.CleanPath(configPath), PlanChangesFromResourceChanges(resourceChanges))
+	return nil
+}
+
+// loadPlanChanges returns the changes recorded for the plan of the module of the given config, and forgets them, so
+// that they are not reported again by a later run in the same process.
+func loadPlanChanges(configPath string) (PlanChanges, bool) {
+	changes, found := modulePlanChanges.LoadAndDelete(util.CleanPath(configPath))
+	if !found {
+		return PlanChanges{}, false
+	}
+	return changes.(PlanChanges), true
+}
+
+// PlanChangesFromResourceChanges counts the resources the given resource changes of a plan add, change and destroy, the
+// way terraform does: a replacement counts as both an add and a destroy, and reads and no-ops are not counted.
+func PlanChangesFromResourceChanges(resourceChanges []config.PlanResourceChange) PlanChanges {
+	changes := PlanChanges{}
+	for _, resourceChange := range resourceChanges {
+		for _, action := range resourceChange.Actions {
+			switch action {
+			case "create":
+				changes.Add++
+			case "update":
+				changes.Change++
+			case "delete":
+				changes.Destroy++
+			}
+		}
+	}
+	return changes
+}
+
+// ParsePlanChanges finds the change summary in the output of a terraform plan. Both the human-readable output and the
+// machine-readable output of `terraform plan -json` are supported. The second return value is false if the output
+// doesn't contain a change summary.
+func ParsePlanChanges(output string) (PlanChanges, bool) {
+	changes, found := PlanChanges{}, false
+	for _, line := range strings.Split(output, "\n") {
+		if lineChanges, ok := parsePlanOutputLine(line); ok {
+			// The summary is printed at the end of the plan, so the last one wins.
+			changes, found = lineChanges, true
+		}
+	}
+	return changes, found
+}
+
+func parsePlanOutputLine(line string) (PlanChanges, bool) {
+	line = strings.TrimSpace(ansiEscapeRegex.ReplaceAllString(line, ""))
+
+	if strings.HasPrefix(line, "{") {
+		var jsonLine planOutputLine
+		if err := json.Unmarshal([]byte(line), &jsonLine); err == nil {
+			if jsonLine.Type == "change_summary" && jsonLine.Changes != nil {
+				return PlanChanges{Add: jsonLine.Changes.Add, Change: jsonLine.Changes.Change, Destroy: jsonLine.Changes.Remove}, true
+			}
+			if jsonLine.Line != "" {
+				return parsePlanOutputLine(jsonLine.Line)
+			}
+			return PlanChanges{}, false
+		}
+	}
+
+	if strings.HasPrefix(line, "No changes.") {
+		return PlanChanges{}, true
+	}
+
+	matches := planChangesRegex.FindStringSubmatch(line)
+	if matches == nil {
+		return PlanChanges{}, false
+	}
+	// The regex only matches digits, so the conversions can't fail.
+	add, _ := strconv.Atoi(matches[1])
+	change, _ := strconv.Atoi(matches[2])
+	destroy, _ := strconv.Atoi(matches[3])
+	return PlanChanges{Add: add, Change: change, Destroy: destroy}, true
+}
+
+// NewPlanSummary builds the roll-up of the plan changes of the given modules, keyed by module path. The modules without
+// a plan have nil changes.
+func NewPlanSummary(planChanges map[string]*PlanChanges) *PlanSummary {
+	summary := &PlanSummary{Modules: []ModulePlanChanges{}}
+
+	for path, moduleChanges := range planChanges {
+		changes := PlanChanges{}
+		if moduleChanges != nil {
+			changes = *moduleChanges
+		}
+		summary.Modules = append(summary.Modules, ModulePlanChanges{Path: path, Found: moduleChanges != nil, PlanChanges: changes})
+
+		summary.Total.Add += changes.Add
+		summary.Total.Change += changes.Change
+		summary.Total.Destroy += changes.Destroy
+	}
+
+	sort.Slice(summary.Modules, func(i, j int) bool { return summary.Modules[i].Path < summary.Modules[j].Path })
+	return summary
+}
+
+// WriteText writes the summary in a human-readable form to the given writer.
+func (summary *PlanSummary) WriteText(writer io.Writer) error {
+	var sb strings.Builder
+	sb.WriteString("Plan summary:\n")
+	for _, module := range summary.Modules {
+		if !module.Found {
+			sb.WriteString(fmt.Sprintf("  %s: no plan was recorded\n", module.Path))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("  %s: %s\n", module.Path, module.PlanChanges))
+	}
+	sb.WriteString(fmt.Sprintf("Total: %s\n", summary.Total))
+
+	_, err := io.WriteString(writer, sb.String())
+	return errors.WithStackTrace(err)
+}
+
+// WriteJSONFile writes the summary as JSON to the file at the given path, creating the parent directories if needed.
+func (summary *PlanSummary) WriteJSONFile(path string) error {
+	jsonBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if err := util.EnsureDirectory(filepath.Dir(path)); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(jsonBytes, '\n'), 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+func (changes PlanChanges) String() string {
+	return fmt.Sprintf("%d to add, %d to change, %d to destroy", changes.Add, changes.Change, changes.Destroy)
+}
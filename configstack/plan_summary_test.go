@@ -0,0 +1,138 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePlanChanges(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		output   string
+		expected PlanChanges
+		found    bool
+	}{
+		{
+			"human-readable",
+			"Terraform will perform the following actions:\n\nPlan: 2 to add, 1 to change, 3 to destroy.\n",
+			PlanChanges{Add: 2, Change: 1, Destroy: 3},
+			true,
+		},
+		{
+			"human-readable with colors",
+			"\x1b[0m\x1b[1mPlan:\x1b[0m 1 to add, 0 to change, 0 to destroy.\n",
+			PlanChanges{Add: 1},
+			true,
+		},
+		{
+			"human-readable with imports",
+			"Plan: 1 to import, 4 to add, 0 to change, 1 to destroy.\n",
+			PlanChanges{Add: 4, Destroy: 1},
+			true,
+		},
+		{
+			"no changes",
+			"No changes. Your infrastructure matches the configuration.\n",
+			PlanChanges{},
+			true,
+		},
+		{
+			"machine-readable",
+			`{"@level":"info","@message":"Plan: 1 to add, 2 to change, 0 to destroy.","type":"change_summary","changes":{"add":1,"change":2,"import":0,"remove":0,"operation":"plan"}}` + "\n",
+			PlanChanges{Add: 1, Change: 2},
+			true,
+		},
+		{
+			"json-stream log format",
+			`{"timestamp":"2023-01-01T00:00:00Z","module":"/stage/vpc","stream":"stdout","phase":"plan","line":"\u001b[1mPlan:\u001b[0m 5 to add, 0 to change, 2 to destroy."}` + "\n",
+			PlanChanges{Add: 5, Destroy: 2},
+			true,
+		},
+		{
+			"no summary",
+			"Error: Invalid reference\n",
+			PlanChanges{},
+			false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			changes, found := ParsePlanChanges(testCase.output)
+			assert.Equal(t, testCase.found, found)
+			assert.Equal(t, testCase.expected, changes)
+		})
+	}
+}
+
+func TestPlanSummary(t *testing.T) {
+	t.Parallel()
+
+	summary := NewPlanSummary(map[string]*PlanChanges{
+		"/stage/vpc":   {Add: 1},
+		"/stage/mysql": {Add: 2, Change: 1, Destroy: 1},
+		"/stage/app":   nil,
+	})
+
+	assert.Equal(t, PlanChanges{Add: 3, Change: 1, Destroy: 1}, summary.Total)
+
+	var out bytes.Buffer
+	require.NoError(t, summary.WriteText(&out))
+	expected := `Plan summary:
+  /stage/app: no plan was recorded
+  /stage/mysql: 2 to add, 1 to change, 1 to destroy
+  /stage/vpc: 1 to add, 0 to change, 0 to destroy
+Total: 3 to add, 1 to change, 1 to destroy
+`
+	assert.Equal(t, expected, out.String())
+
+	jsonPath := filepath.Join(t.TempDir(), "summary", "plan.json")
+	require.NoError(t, summary.WriteJSONFile(jsonPath))
+
+	jsonBytes, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal(jsonBytes, &parsed))
+	assert.Equal(t, map[string]interface{}{"add": 3.0, "change": 1.0, "destroy": 1.0}, parsed["total"])
+	assert.Equal(t, map[string]interface{}{"path": "/stage/mysql", "found": true, "add": 2.0, "change": 1.0, "destroy": 1.0}, parsed["modules"].([]interface{})[1])
+}
+
+func TestStorePlanChanges(t *testing.T) {
+	t.Parallel()
+
+	planJSON := `{
+  "format_version": "1.2",
+  "resource_changes": [
+    {"address": "aws_instance.new", "type": "aws_instance", "change": {"actions": ["create"]}},
+    {"address": "aws_instance.updated", "type": "aws_instance", "change": {"actions": ["update"]}},
+    {"address": "aws_instance.replaced", "type": "aws_instance", "change": {"actions": ["delete", "create"]}},
+    {"address": "aws_instance.removed", "type": "aws_instance", "change": {"actions": ["delete"]}},
+    {"address": "aws_instance.unchanged", "type": "aws_instance", "change": {"actions": ["no-op"]}},
+    {"address": "data.aws_ami.ubuntu", "type": "aws_ami", "change": {"actions": ["read"]}}
+  ]
+}`
+	configPath := filepath.Join(t.TempDir(), "vpc", "terragrunt.hcl")
+	require.NoError(t, StorePlanChanges(configPath, []byte(planJSON)))
+
+	changes, found := loadPlanChanges(configPath)
+	require.True(t, found)
+	assert.Equal(t, PlanChanges{Add: 2, Change: 1, Destroy: 2}, changes)
+
+	// The changes are only reported once.
+	_, found = loadPlanChanges(configPath)
+	assert.False(t, found)
+
+	require.Error(t, StorePlanChanges(configPath, []byte("Error: no plan")))
+}
@@ -0,0 +1,218 @@
+package configstack
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/terraform-config-inspect/tfconfig"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/terraform"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	// The terraform args of `run-all providers lock` that take the platforms to lock the providers for as a
+	// comma-separated list, which is expanded into the -platform args of terraform.
+	providersLockPlatformsArg = "-platforms"
+	providersLockPlatformArg  = "-platform"
+
+	envNamePluginCacheDir = "TF_PLUGIN_CACHE_DIR"
+)
+
+// isProvidersLockCommand returns true if the given terraform args are a `providers lock` command.
+func isProvidersLockCommand(args []string) bool {
+	return util.FirstArg(args) == "providers" && util.SecondArg(args) == "lock"
+}
+
+// runProvidersLock runs `providers lock` once for each distinct set of providers required by the modules of the stack,
+// in parallel and with a shared provider download cache, and then copies the lock file of each set to every other
+// module requiring the same providers, so that all the modules lock the same versions and checksums.
+func (stack *Stack) runProvidersLock(terragruntOptions *options.TerragruntOptions) error {
+	terragruntOptions.TerraformCliArgs = expandPlatformsArg(terragruntOptions.TerraformCliArgs)
+	stack.syncTerraformCliArgs(terragruntOptions)
+
+	if terragruntOptions.Env[envNamePluginCacheDir] == "" {
+		cacheDir, err := os.MkdirTemp("", "terragrunt-providers-lock")
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+		defer os.RemoveAll(cacheDir)
+
+		for _, module := range stack.Modules {
+			module.TerragruntOptions.Env[envNamePluginCacheDir] = cacheDir
+		}
+	}
+
+	groups := stack.providerSetGroups(terragruntOptions)
+
+	// Only the first module of each group runs, so their dependencies, which are irrelevant to locking the providers,
+	// are dropped, as the modules they point to are not run.
+	representatives := []*TerraformModule{}
+	for _, group := range groups {
+		representative := *group[0]
+		representative.Dependencies = nil
+		representatives = append(representatives, &representative)
+
+		if len(group) > 1 {
+			terragruntOptions.Logger.Infof("Locking the providers of %d modules by running providers lock in %s", len(group), representative.Path)
+		}
+	}
+
+	summary, runErr := RunModulesWithSummary(representatives, IgnoreOrder, terragruntOptions.Parallelism)
+	if summary == nil {
+		return runErr
+	}
+
+	for _, group := range groups {
+		if !util.ListContainsElement(summary.Succeeded, group[0].Path) {
+			continue
+		}
+		for _, module := range group[1:] {
+			if err := copyProvidersLockFile(terragruntOptions, group[0].Path, module.Path); err != nil {
+				return err
+			}
+		}
+	}
+	return runErr
+}
+
+// providerSetGroups groups the modules of the stack that are run by the set of providers they require, in the order of
+// the stack.
+func (stack *Stack) providerSetGroups(terragruntOptions *options.TerragruntOptions) [][]*TerraformModule {
+	keys := []string{}
+	groups := map[string][]*TerraformModule{}
+	for _, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+
+		key := providerSetKey(module)
+		terragruntOptions.Logger.Debugf("Module %s requires the providers %s", module.Path, key)
+		if _, found := groups[key]; !found {
+			keys = append(keys, key)
+		}
+		groups[key] = append(groups[key], module)
+	}
+
+	result := [][]*TerraformModule{}
+	for _, key := range keys {
+		result = append(result, groups[key])
+	}
+	return result
+}
+
+// providerSetKey returns a key identifying the providers the given module requires, which is the same for the modules
+// requiring the same providers:
+//
+//   - The modules with a terraform source require the providers of the code at that source. The local sources are
+//     made canonical, as the same relative path points to different code in different folders.
+//   - The modules with their terraform code in their own folder require the providers of that code, unless it calls
+//     other modules, whose providers can't be known before they are downloaded.
+//
+// As generate blocks can add providers to the code, the files they generate are part of the key.
+func providerSetKey(module *TerraformModule) string {
+	generated := []string{}
+	for name, generateConfig := range module.Config.GenerateConfigs {
+		if !generateConfig.Disable {
+			generated = append(generated, fmt.Sprintf("%s:%s:%s", name, generateConfig.Path, generateConfig.Contents))
+		}
+	}
+	sort.Strings(generated)
+	generatedKey := strings.Join(generated, "\n")
+
+	if module.Config.Terraform != nil && module.Config.Terraform.Source != nil && *module.Config.Terraform.Source != "" {
+		sourceKey, err := canonicalSourceKey(module, *module.Config.Terraform.Source)
+		if err != nil {
+			return fmt.Sprintf("module %s", module.Path)
+		}
+		return fmt.Sprintf("source %s\n%s", sourceKey, generatedKey)
+	}
+
+	tfModule, diags := tfconfig.LoadModule(module.Path)
+	if diags.HasErrors() || len(tfModule.ModuleCalls) > 0 {
+		return fmt.Sprintf("module %s", module.Path)
+	}
+
+	providers := []string{}
+	for name, requirement := range tfModule.RequiredProviders {
+		constraints := append([]string{}, requirement.VersionConstraints...)
+		sort.Strings(constraints)
+		providers = append(providers, fmt.Sprintf("%s=%s %s", name, requirement.Source, strings.Join(constraints, ",")))
+	}
+	sort.Strings(providers)
+	return fmt.Sprintf("providers %s\n%s", strings.Join(providers, ";"), generatedKey)
+}
+
+// canonicalSourceKey returns the given terraform source of the given module as is if it is remote, or, if it is a local
+// path, as the canonical path of its root folder followed by the path of the module within it.
+func canonicalSourceKey(module *TerraformModule, sourceURL string) (string, error) {
+	if module.TerragruntOptions == nil {
+		return "", errors.WithStackTrace(fmt.Errorf("module %s has no options to resolve its source with", module.Path))
+	}
+
+	source, err := terraform.NewSource(sourceURL, module.TerragruntOptions.DownloadDir, module.Path, module.TerragruntOptions.Logger)
+	if err != nil {
+		return "", err
+	}
+	if !terraform.IsLocalSource(source.CanonicalSourceURL) {
+		return sourceURL, nil
+	}
+
+	modulePath, err := filepath.Rel(source.DownloadDir, source.WorkingDir)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+	return fmt.Sprintf("%s//%s", source.CanonicalSourceURL.Path, filepath.ToSlash(modulePath)), nil
+}
+
+// expandPlatformsArg expands the comma-separated platforms of -platforms, given as -platforms=a,b or -platforms a,b,
+// into the -platform args of terraform.
+func expandPlatformsArg(args []string) []string {
+	result := []string{}
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+
+		var platforms string
+		switch {
+		case strings.HasPrefix(arg, providersLockPlatformsArg+"="):
+			platforms = strings.TrimPrefix(arg, providersLockPlatformsArg+"=")
+		case arg == providersLockPlatformsArg && i+1 < len(args):
+			i++
+			platforms = args[i]
+		default:
+			result = append(result, arg)
+			continue
+		}
+
+		for _, platform := range strings.Split(platforms, ",") {
+			if platform = strings.TrimSpace(platform); platform != "" {
+				result = append(result, providersLockPlatformArg+"="+platform)
+			}
+		}
+	}
+	return result
+}
+
+// copyProvidersLockFile copies the lock file of the module in the given source folder to the module in the given
+// destination folder.
+func copyProvidersLockFile(terragruntOptions *options.TerragruntOptions, sourceFolder string, destinationFolder string) error {
+	destinationLockFile := filepath.Join(destinationFolder, util.TerraformLockFile)
+	if util.FileExists(destinationLockFile) {
+		return util.CopyLockFile(sourceFolder, destinationFolder, terragruntOptions.Logger)
+	}
+
+	contents, err := os.ReadFile(filepath.Join(sourceFolder, util.TerraformLockFile))
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	terragruntOptions.Logger.Debugf("Copying lock file from %s to %s", sourceFolder, destinationFolder)
+	if err := os.WriteFile(destinationLockFile, contents, 0644); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package configstack
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const providersLockTestCode = `
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+`
+
+func TestExpandPlatformsArg(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"providers", "lock"}, []string{"providers", "lock"}},
+		{[]string{"providers", "lock", "-platforms=linux_amd64,darwin_arm64"}, []string{"providers", "lock", "-platform=linux_amd64", "-platform=darwin_arm64"}},
+		{[]string{"providers", "lock", "-platforms", "linux_amd64, darwin_arm64", "hashicorp/aws"}, []string{"providers", "lock", "-platform=linux_amd64", "-platform=darwin_arm64", "hashicorp/aws"}},
+		{[]string{"providers", "lock", "-platform=linux_amd64"}, []string{"providers", "lock", "-platform=linux_amd64"}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, expandPlatformsArg(testCase.args))
+	}
+}
+
+func TestRunProvidersLock(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	source := "git::https://example.com/modules.git//app?ref=v1.0.0"
+	modules := []*TerraformModule{}
+	for _, name := range []string{"vpc", "mysql", "app-a", "app-b", "dns"} {
+		modulePath := filepath.Join(rootDir, name)
+		require.NoError(t, os.MkdirAll(modulePath, 0755))
+		module := &TerraformModule{Path: modulePath}
+		switch name {
+		case "vpc", "mysql":
+			require.NoError(t, os.WriteFile(filepath.Join(modulePath, "main.tf"), []byte(providersLockTestCode), 0644))
+		case "app-a", "app-b":
+			module.Config.Terraform = &config.TerraformConfig{Source: &source}
+		case "dns":
+			require.NoError(t, os.WriteFile(filepath.Join(modulePath, "main.tf"), []byte(`provider "google" {}`), 0644))
+		}
+		modules = append(modules, module)
+	}
+	// The dependency is not run, as its lock file is copied from the module requiring the same providers.
+	modules[1].Dependencies = []*TerraformModule{modules[0]}
+	require.NoError(t, os.WriteFile(filepath.Join(modules[1].Path, util.TerraformLockFile), []byte("# outdated"), 0644))
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+	terragruntOptions.TerraformCliArgs = []string{"providers", "lock", "-platforms=linux_amd64,darwin_arm64"}
+
+	var mutex sync.Mutex
+	ran := []string{}
+	for _, module := range modules {
+		module := module
+		module.TerragruntOptions = terragruntOptions.Clone(filepath.Join(module.Path, config.DefaultTerragruntConfigPath))
+		module.TerragruntOptions.RunTerragrunt = func(opts *options.TerragruntOptions) error {
+			mutex.Lock()
+			ran = append(ran, module.Path)
+			mutex.Unlock()
+
+			assert.Equal(t, []string{"providers", "lock", "-platform=linux_amd64", "-platform=darwin_arm64"}, opts.TerraformCliArgs)
+			assert.NotEmpty(t, opts.Env[envNamePluginCacheDir])
+			return os.WriteFile(filepath.Join(module.Path, util.TerraformLockFile), []byte("# locked in "+filepath.Base(module.Path)), 0644)
+		}
+	}
+
+	stack := &Stack{Path: rootDir, Modules: modules}
+	require.NoError(t, stack.Run(terragruntOptions))
+
+	assert.ElementsMatch(t, []string{modules[0].Path, modules[2].Path, modules[4].Path}, ran)
+	expectedLockFiles := map[string]string{
+		"vpc":   "# locked in vpc",
+		"mysql": "# locked in vpc",
+		"app-a": "# locked in app-a",
+		"app-b": "# locked in app-a",
+		"dns":   "# locked in dns",
+	}
+	for name, expected := range expectedLockFiles {
+		contents, err := os.ReadFile(filepath.Join(rootDir, name, util.TerraformLockFile))
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(contents), name)
+	}
+}
+
+func TestProviderSetKeyLocalSource(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	terragruntOptions, err := options.NewTerragruntOptionsForTest(filepath.Join(rootDir, config.DefaultTerragruntConfigPath))
+	require.NoError(t, err)
+
+	newModule := func(path string, source string) *TerraformModule {
+		modulePath := filepath.Join(rootDir, path)
+		require.NoError(t, os.MkdirAll(modulePath, 0755))
+		module := &TerraformModule{Path: modulePath, TerragruntOptions: terragruntOptions.Clone(filepath.Join(modulePath, config.DefaultTerragruntConfigPath))}
+		module.Config.Terraform = &config.TerraformConfig{Source: &source}
+		return module
+	}
+
+	// The same relative path points to different code in different folders.
+	devApp := newModule("live/dev/app", "../../modules/app")
+	stageApp := newModule("other/stage/app", "../../modules/app")
+	assert.NotEqual(t, providerSetKey(devApp), providerSetKey(stageApp))
+
+	// Different relative paths pointing to the same code require the same providers.
+	devDB := newModule("live/dev/db", "../../modules/app")
+	prodApp := newModule("live/prod/nested/app", "../../../modules/app")
+	assert.Equal(t, providerSetKey(devApp), providerSetKey(devDB))
+	assert.Equal(t, providerSetKey(devApp), providerSetKey(prodApp))
+
+	// The remote sources are kept as is.
+	remoteSource := "git::https://example.com/modules.git//app?ref=v1.0.0"
+	assert.Equal(t, providerSetKey(newModule("live/dev/remote", remoteSource)), providerSetKey(newModule("other/remote", remoteSource)))
+}
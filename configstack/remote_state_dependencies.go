@@ -0,0 +1,250 @@
+package configstack
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/hcl/v2/hclparse"
+	"github.com/hashicorp/hcl/v2/hclsyntax"
+	"github.com/zclconf/go-cty/cty"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const remoteStateDataSourceType = "terraform_remote_state"
+
+// RemoteStateDependency is a terraform_remote_state data source of a module that reads the state of another module,
+// which the module therefore depends on.
+type RemoteStateDependency struct {
+	// The address of the data source, e.g. data.terraform_remote_state.vpc
+	DataSource string
+	// Where the state is stored, as returned by RemoteState.StateLocation
+	Location string
+	// The directory of the module whose remote_state stores its state at the location
+	ModulePath string
+}
+
+// FindUndeclaredRemoteStateDependencies returns the modules whose state is read by the terraform_remote_state data
+// sources of the terraform code in the given dir, but that are not in the dependencies or dependency blocks of the
+// given config of the module, sorted by data source.
+func FindUndeclaredRemoteStateDependencies(terragruntOptions *options.TerragruntOptions, terraformDir string, terragruntConfig *config.TerragruntConfig) ([]RemoteStateDependency, error) {
+	dependencies, err := FindRemoteStateDependencies(terragruntOptions, terraformDir)
+	if err != nil {
+		return nil, err
+	}
+
+	declaredPaths := []string{}
+	if terragruntConfig.Dependencies != nil {
+		declaredPaths = append(declaredPaths, terragruntConfig.Dependencies.Paths...)
+	}
+	for _, dependency := range terragruntConfig.TerragruntDependencies {
+		declaredPaths = append(declaredPaths, dependency.ConfigPath)
+	}
+
+	declared := map[string]bool{}
+	for _, path := range declaredPaths {
+		if !filepath.IsAbs(path) {
+			path = util.JoinPath(filepath.Dir(terragruntOptions.TerragruntConfigPath), path)
+		}
+		// The paths may point at the config file of the dependency rather than its dir.
+		if util.IsFile(path) {
+			path = filepath.Dir(path)
+		}
+		declared[util.CleanPath(path)] = true
+	}
+
+	undeclared := []RemoteStateDependency{}
+	for _, dependency := range dependencies {
+		if !declared[dependency.ModulePath] {
+			undeclared = append(undeclared, dependency)
+		}
+	}
+	return undeclared, nil
+}
+
+// FindRemoteStateDependencies returns the modules whose state is read by the terraform_remote_state data sources of
+// the terraform code in the given dir, sorted by data source. The modules are searched for in the git repository of
+// the working dir, or in the parent dir of the working dir if it is not in a git repository, and are matched by the
+// location of their state. The data sources whose config can't be evaluated statically, e.g. because it references
+// variables, are skipped.
+func FindRemoteStateDependencies(terragruntOptions *options.TerragruntOptions, terraformDir string) ([]RemoteStateDependency, error) {
+	dataSourceLocations, err := remoteStateDataSourceLocations(terragruntOptions, terraformDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(dataSourceLocations) == 0 {
+		return []RemoteStateDependency{}, nil
+	}
+
+	modulePathsByLocation, err := modulePathsByStateLocation(terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	dependencies := []RemoteStateDependency{}
+	for dataSource, location := range dataSourceLocations {
+		modulePath, found := modulePathsByLocation[location]
+		if !found {
+			terragruntOptions.Logger.Debugf("Found no module storing its state at %s, which is read by %s", location, dataSource)
+			continue
+		}
+		dependencies = append(dependencies, RemoteStateDependency{DataSource: dataSource, Location: location, ModulePath: modulePath})
+	}
+
+	sort.Slice(dependencies, func(i, j int) bool { return dependencies[i].DataSource < dependencies[j].DataSource })
+	return dependencies, nil
+}
+
+// remoteStateDataSourceLocations returns the location of the state read by each terraform_remote_state data source of
+// the terraform code in the given dir, keyed by the address of the data source.
+func remoteStateDataSourceLocations(terragruntOptions *options.TerragruntOptions, terraformDir string) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(terraformDir, "*.tf"))
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	locations := map[string]string{}
+	parser := hclparse.NewParser()
+
+	for _, filename := range files {
+		file, diags := parser.ParseHCLFile(filename)
+		if diags.HasErrors() {
+			return nil, errors.WithStackTrace(diags)
+		}
+
+		for _, block := range file.Body.(*hclsyntax.Body).Blocks {
+			if block.Type != "data" || len(block.Labels) != 2 || block.Labels[0] != remoteStateDataSourceType {
+				continue
+			}
+			dataSource := fmt.Sprintf("data.%s.%s", remoteStateDataSourceType, block.Labels[1])
+
+			remoteState, err := remoteStateDataSourceConfig(block.Body)
+			if err != nil {
+				terragruntOptions.Logger.Debugf("Skipping %s in %s, as its config can not be evaluated: %v", dataSource, filename, err)
+				continue
+			}
+
+			location := remoteState.StateLocation()
+			if location == "" {
+				terragruntOptions.Logger.Debugf("Skipping %s in %s, as the location of the state it reads can not be determined", dataSource, filename)
+				continue
+			}
+			locations[dataSource] = location
+		}
+	}
+
+	return locations, nil
+}
+
+// remoteStateDataSourceConfig returns the backend and config of the given body of a terraform_remote_state data source.
+// The config settings that can't be evaluated statically are left out, so that the location of the state can still be
+// determined if it doesn't depend on them.
+func remoteStateDataSourceConfig(body *hclsyntax.Body) (*remote.RemoteState, error) {
+	backendAttr, ok := body.Attributes["backend"]
+	if !ok {
+		return nil, fmt.Errorf("the backend is not set")
+	}
+	backend, diags := backendAttr.Expr.Value(nil)
+	if diags.HasErrors() {
+		return nil, diags
+	}
+	if backend.Type() != cty.String || backend.IsNull() {
+		return nil, fmt.Errorf("the backend is not a string")
+	}
+
+	remoteState := &remote.RemoteState{Backend: backend.AsString(), Config: map[string]interface{}{}}
+
+	configAttr, ok := body.Attributes["config"]
+	if !ok {
+		return remoteState, nil
+	}
+	configExpr, ok := configAttr.Expr.(*hclsyntax.ObjectConsExpr)
+	if !ok {
+		return nil, fmt.Errorf("the config is not an object")
+	}
+	for _, item := range configExpr.Items {
+		key, diags := item.KeyExpr.Value(nil)
+		if diags.HasErrors() || key.Type() != cty.String || key.IsNull() {
+			continue
+		}
+		value, diags := item.ValueExpr.Value(nil)
+		if diags.HasErrors() || !value.IsWhollyKnown() {
+			continue
+		}
+		remoteState.Config[key.AsString()] = ctyValueToInterface(value)
+	}
+
+	return remoteState, nil
+}
+
+// ctyValueToInterface converts the given value of a remote state setting to the go value the remote state config would
+// be decoded to.
+func ctyValueToInterface(value cty.Value) interface{} {
+	if value.IsNull() {
+		return nil
+	}
+	switch {
+	case value.Type() == cty.String:
+		return value.AsString()
+	case value.Type() == cty.Bool:
+		return value.True()
+	case value.Type() == cty.Number:
+		return value.AsBigFloat().String()
+	case value.Type().IsObjectType() || value.Type().IsMapType():
+		converted := map[string]interface{}{}
+		for key, nested := range value.AsValueMap() {
+			converted[key] = ctyValueToInterface(nested)
+		}
+		return converted
+	case value.CanIterateElements():
+		converted := []interface{}{}
+		for _, nested := range value.AsValueSlice() {
+			converted = append(converted, ctyValueToInterface(nested))
+		}
+		return converted
+	}
+	return nil
+}
+
+// modulePathsByStateLocation returns the dirs of the modules found in the git repository of the working dir, or in the
+// parent dir of the working dir, keyed by the location of their state. The modules whose remote_state can't be parsed
+// without running them, such as the ones that depend on the outputs of other modules, are left out.
+func modulePathsByStateLocation(terragruntOptions *options.TerragruntOptions) (map[string]string, error) {
+	rootDir, err := shell.GitTopLevelDir(terragruntOptions, terragruntOptions.WorkingDir)
+	if err != nil {
+		rootDir = filepath.Dir(terragruntOptions.WorkingDir)
+		terragruntOptions.Logger.Debugf("Could not find the git repository of %s, searching for modules in %s: %v", terragruntOptions.WorkingDir, rootDir, err)
+	}
+
+	configFiles, err := config.FindConfigFilesInPath(rootDir, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	modulePathsByLocation := map[string]string{}
+	for _, configFile := range configFiles {
+		configOptions := terragruntOptions.Clone(configFile)
+		configOptions.OriginalTerragruntConfigPath = configFile
+
+		terragruntConfig, err := config.PartialParseConfigFile(configFile, configOptions, nil, []config.PartialDecodeSectionType{config.RemoteStateBlock})
+		if err != nil {
+			terragruntOptions.Logger.Debugf("Not reading the state location of %s, as its remote_state can not be parsed: %v", configFile, err)
+			continue
+		}
+		if terragruntConfig.RemoteState == nil {
+			continue
+		}
+
+		if location := terragruntConfig.RemoteState.StateLocation(); location != "" {
+			modulePathsByLocation[location] = util.CleanPath(filepath.Dir(configFile))
+		}
+	}
+
+	return modulePathsByLocation, nil
+}
@@ -0,0 +1,101 @@
+package configstack
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestFindUndeclaredRemoteStateDependencies(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	files := map[string]string{
+		"vpc/terragrunt.hcl": `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "vpc/terraform.tfstate"
+    region = "us-east-1"
+  }
+}
+`,
+		"mysql/terragrunt.hcl": `
+remote_state {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "mysql/terraform.tfstate"
+    region = "us-east-1"
+  }
+}
+`,
+		"app/terragrunt.hcl": `
+dependencies {
+  paths = ["../mysql"]
+}
+`,
+		"app/main.tf": `
+data "terraform_remote_state" "vpc" {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "vpc/terraform.tfstate"
+    region = var.region
+  }
+}
+
+data "terraform_remote_state" "mysql" {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "mysql/terraform.tfstate"
+  }
+}
+
+data "terraform_remote_state" "dns" {
+  backend = "s3"
+  config = {
+    bucket = "my-state"
+    key    = "${var.env}/dns/terraform.tfstate"
+  }
+}
+`,
+	}
+	for path, contents := range files {
+		path = filepath.Join(rootDir, path)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), os.ModePerm))
+		require.NoError(t, os.WriteFile(path, []byte(contents), 0644))
+	}
+
+	appConfig := filepath.Join(rootDir, "app", config.DefaultTerragruntConfigPath)
+	opts, err := options.NewTerragruntOptionsForTest(appConfig)
+	require.NoError(t, err)
+	opts.WorkingDir = filepath.Join(rootDir, "app")
+
+	dependencies, err := FindRemoteStateDependencies(opts, filepath.Join(rootDir, "app"))
+	require.NoError(t, err)
+	require.Len(t, dependencies, 2)
+	assert.Equal(t, "data.terraform_remote_state.mysql", dependencies[0].DataSource)
+	assert.Equal(t, filepath.Join(rootDir, "mysql"), dependencies[0].ModulePath)
+	assert.Equal(t, RemoteStateDependency{
+		DataSource: "data.terraform_remote_state.vpc",
+		Location:   "s3://my-state/vpc/terraform.tfstate",
+		ModulePath: filepath.Join(rootDir, "vpc"),
+	}, dependencies[1])
+
+	terragruntConfig, err := config.PartialParseConfigFile(appConfig, opts, nil, []config.PartialDecodeSectionType{config.DependenciesBlock, config.DependencyBlock})
+	require.NoError(t, err)
+
+	undeclared, err := FindUndeclaredRemoteStateDependencies(opts, filepath.Join(rootDir, "app"), terragruntConfig)
+	require.NoError(t, err)
+	require.Len(t, undeclared, 1)
+	assert.Equal(t, filepath.Join(rootDir, "vpc"), undeclared[0].ModulePath)
+}
@@ -0,0 +1,130 @@
+package configstack
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// RunSummary is a machine-readable report of the outcome of every module in a run-all command.
+type RunSummary struct {
+	Succeeded []string           `json:"succeeded"`
+	Failed    []ModuleRunFailure `json:"failed"`
+	Skipped   []ModuleRunSkip    `json:"skipped"`
+}
+
+// ModuleRunFailure describes a module that ran and returned an error.
+type ModuleRunFailure struct {
+	Path  string `json:"path"`
+	Error string `json:"error"`
+}
+
+// ModuleRunSkip describes a module that was not run, either because one of its dependencies failed or because it was
+// assumed to be already applied.
+type ModuleRunSkip struct {
+	Path             string `json:"path"`
+	Reason           string `json:"reason"`
+	FailedDependency string `json:"failed_dependency,omitempty"`
+}
+
+const (
+	skipReasonDependencyFailed = "dependency_failed"
+	skipReasonAlreadyApplied   = "already_applied"
+)
+
+// RunModulesWithSummary runs the given modules in the given dependency order, like RunModules does, and additionally
+// returns a summary of the outcome of every module. Modules that were skipped because one of their dependencies failed
+// are reported in the summary, but only the errors of the modules that actually failed are returned.
+func RunModulesWithSummary(modules []*TerraformModule, dependencyOrder DependencyOrder, parallelism int) (*RunSummary, error) {
+	runningModules, err := toRunningModules(modules, dependencyOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	// The errors are collected from the summary instead, as they exclude the modules that were skipped.
+	_ = runModules(runningModules, parallelism)
+
+	summary := newRunSummary(runningModules)
+
+	var result *multierror.Error
+	for _, module := range sortedRunningModules(runningModules) {
+		if module.Err == nil {
+			continue
+		}
+		if _, isSkipped := module.Err.(DependencyFinishedWithError); !isSkipped {
+			result = multierror.Append(result, module.Err)
+		}
+	}
+
+	return summary, result.ErrorOrNil()
+}
+
+// Write the summary as JSON to the given writer.
+func (summary *RunSummary) Write(writer io.Writer) error {
+	jsonBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := writer.Write(append(jsonBytes, '\n')); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+// WriteJSONFile writes the summary as JSON to the file at the given path, creating the parent directories if needed.
+func (summary *RunSummary) WriteJSONFile(path string) error {
+	if err := util.EnsureDirectory(filepath.Dir(path)); err != nil {
+		return err
+	}
+	file, err := os.Create(path)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	defer file.Close()
+	return summary.Write(file)
+}
+
+func newRunSummary(modules map[string]*runningModule) *RunSummary {
+	summary := &RunSummary{
+		Succeeded: []string{},
+		Failed:    []ModuleRunFailure{},
+		Skipped:   []ModuleRunSkip{},
+	}
+
+	for _, module := range sortedRunningModules(modules) {
+		switch {
+		case module.Err != nil:
+			if dependencyErr, isSkipped := module.Err.(DependencyFinishedWithError); isSkipped {
+				summary.Skipped = append(summary.Skipped, ModuleRunSkip{
+					Path:             module.Module.Path,
+					Reason:           skipReasonDependencyFailed,
+					FailedDependency: dependencyErr.Dependency.Path,
+				})
+			} else {
+				summary.Failed = append(summary.Failed, ModuleRunFailure{Path: module.Module.Path, Error: module.Err.Error()})
+			}
+		case module.Module.AssumeAlreadyApplied:
+			summary.Skipped = append(summary.Skipped, ModuleRunSkip{Path: module.Module.Path, Reason: skipReasonAlreadyApplied})
+		default:
+			summary.Succeeded = append(summary.Succeeded, module.Module.Path)
+		}
+	}
+
+	return summary
+}
+
+func sortedRunningModules(modules map[string]*runningModule) []*runningModule {
+	sorted := make([]*runningModule, 0, len(modules))
+	for _, module := range modules {
+		sorted = append(sorted, module)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Module.Path < sorted[j].Module.Path })
+	return sorted
+}
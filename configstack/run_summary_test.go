@@ -0,0 +1,108 @@
+package configstack
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunModulesWithSummaryOneFailure(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "a", nil, &aRan),
+	}
+
+	bRan := false
+	expectedErrB := fmt.Errorf("Expected error for module b")
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{moduleA},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "b", expectedErrB, &bRan),
+	}
+
+	cRan := false
+	moduleC := &TerraformModule{
+		Path:              "c",
+		Dependencies:      []*TerraformModule{moduleB},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "c", nil, &cRan),
+	}
+
+	dRan := false
+	moduleD := &TerraformModule{
+		Path:              "d",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "d", nil, &dRan),
+	}
+
+	summary, err := RunModulesWithSummary([]*TerraformModule{moduleA, moduleB, moduleC, moduleD}, NormalOrder, options.DefaultParallelism)
+	assertMultiErrorContains(t, err, expectedErrB)
+
+	assert.True(t, aRan)
+	assert.True(t, bRan)
+	assert.False(t, cRan)
+	assert.True(t, dRan)
+
+	require.NotNil(t, summary)
+	assert.Equal(t, []string{"a", "d"}, summary.Succeeded)
+	assert.Equal(t, []ModuleRunFailure{{Path: "b", Error: expectedErrB.Error()}}, summary.Failed)
+	assert.Equal(t, []ModuleRunSkip{{Path: "c", Reason: skipReasonDependencyFailed, FailedDependency: "b"}}, summary.Skipped)
+}
+
+func TestRunModulesWithSummaryAllSuccess(t *testing.T) {
+	t.Parallel()
+
+	aRan := false
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, "a", nil, &aRan),
+	}
+
+	bRan := false
+	moduleB := &TerraformModule{
+		Path:                 "b",
+		Dependencies:         []*TerraformModule{moduleA},
+		Config:               config.TerragruntConfig{},
+		TerragruntOptions:    optionsWithMockTerragruntCommand(t, "b", nil, &bRan),
+		AssumeAlreadyApplied: true,
+	}
+
+	summary, err := RunModulesWithSummary([]*TerraformModule{moduleA, moduleB}, ReverseOrder, options.DefaultParallelism)
+	require.NoError(t, err)
+
+	assert.True(t, aRan)
+	assert.False(t, bRan)
+
+	var out bytes.Buffer
+	require.NoError(t, summary.Write(&out))
+
+	var parsed map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &parsed))
+	assert.Equal(t, []interface{}{"a"}, parsed["succeeded"])
+	assert.Equal(t, []interface{}{}, parsed["failed"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"path": "b", "reason": "already_applied"}}, parsed["skipped"])
+
+	jsonPath := filepath.Join(t.TempDir(), "summary", "run.json")
+	require.NoError(t, summary.WriteJSONFile(jsonPath))
+
+	jsonBytes, err := os.ReadFile(jsonPath)
+	require.NoError(t, err)
+	assert.Equal(t, out.String(), string(jsonBytes))
+}
@@ -162,16 +162,18 @@ func removeFlagExcluded(modules map[string]*runningModule) map[string]*runningMo
 
 // Run the given map of module path to runningModule. To "run" a module, execute the RunTerragrunt command in its
 // TerragruntOptions object. The modules will be executed in an order determined by their inter-dependencies, using
-// as much concurrency as possible.
+// as much concurrency as possible. When more modules are ready than the parallelism allows, the ones with the highest
+// priority are run first.
 func runModules(modules map[string]*runningModule, parallelism int) error {
 	var waitGroup sync.WaitGroup
-	var semaphore = make(chan struct{}, parallelism) // Make a semaphore from a buffered channel
+	scheduler := newModuleScheduler(modules, parallelism)
+	fanouts := moduleFanouts(modules)
 
 	for _, module := range modules {
 		waitGroup.Add(1)
 		go func(module *runningModule) {
 			defer waitGroup.Done()
-			module.runModuleWhenReady(semaphore)
+			module.runModuleWhenReady(scheduler, fanouts[module.Module.Path])
 		}(module)
 	}
 
@@ -194,14 +196,14 @@ func collectErrors(modules map[string]*runningModule) error {
 }
 
 // Run a module once all of its dependencies have finished executing.
-func (module *runningModule) runModuleWhenReady(semaphore chan struct{}) {
+func (module *runningModule) runModuleWhenReady(scheduler *moduleScheduler, fanout int) {
 	err := module.waitForDependencies()
-	semaphore <- struct{}{} // Add one to the buffered channel. Will block if parallelism limit is met
-	defer func() {
-		<-semaphore // Remove one from the buffered channel
-	}()
+	scheduler.acquire(module, fanout) // Will block if parallelism limit is met
+	defer scheduler.release()
 	if err == nil {
 		err = module.runNow()
+	} else {
+		module.runSkipHooks()
 	}
 	module.moduleFinished(err)
 }
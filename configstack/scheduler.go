@@ -0,0 +1,122 @@
+package configstack
+
+import (
+	"sync"
+)
+
+// moduleScheduler limits how many modules run at the same time. Unlike a plain semaphore, when modules are waiting for
+// a slot, the slot goes to the module with the highest scheduling priority: first the priority declared in its
+// configuration, then the number of modules that wait on it, so that long-running and high-fanout modules are started
+// first and the total run time of wide graphs is reduced.
+type moduleScheduler struct {
+	mutex     sync.Mutex
+	available int
+	waiting   []*scheduledModule
+	// The number of modules that need to ask for a slot before any slot is given, so that the modules that are ready
+	// from the start are scheduled by priority, rather than in the order their goroutines happen to start.
+	pendingStart int
+}
+
+type scheduledModule struct {
+	module   *runningModule
+	priority int
+	fanout   int
+	ready    chan struct{}
+}
+
+// newModuleScheduler creates a scheduler that runs at most parallelism of the given modules at the same time.
+func newModuleScheduler(modules map[string]*runningModule, parallelism int) *moduleScheduler {
+	pendingStart := 0
+	for _, module := range modules {
+		if len(module.Dependencies) == 0 {
+			pendingStart++
+		}
+	}
+	return &moduleScheduler{available: parallelism, pendingStart: pendingStart}
+}
+
+// acquire blocks until the given module may run.
+func (scheduler *moduleScheduler) acquire(module *runningModule, fanout int) {
+	waiter := &scheduledModule{module: module, priority: modulePriority(module.Module), fanout: fanout, ready: make(chan struct{})}
+
+	scheduler.mutex.Lock()
+	scheduler.waiting = append(scheduler.waiting, waiter)
+	if scheduler.pendingStart > 0 {
+		scheduler.pendingStart--
+	}
+	scheduler.dispatch()
+	scheduler.mutex.Unlock()
+
+	<-waiter.ready
+}
+
+// release frees the slot of a module that finished running.
+func (scheduler *moduleScheduler) release() {
+	scheduler.mutex.Lock()
+	scheduler.available++
+	scheduler.dispatch()
+	scheduler.mutex.Unlock()
+}
+
+// dispatch gives the free slots to the waiting modules with the highest priority. Must be called with the mutex held.
+func (scheduler *moduleScheduler) dispatch() {
+	if scheduler.pendingStart > 0 {
+		return
+	}
+	for scheduler.available > 0 && len(scheduler.waiting) > 0 {
+		next := 0
+		for i, waiter := range scheduler.waiting {
+			if waiter.runsBefore(scheduler.waiting[next]) {
+				next = i
+			}
+		}
+		waiter := scheduler.waiting[next]
+		scheduler.waiting = append(scheduler.waiting[:next], scheduler.waiting[next+1:]...)
+		scheduler.available--
+		close(waiter.ready)
+	}
+}
+
+// runsBefore returns true if the module should be given a slot before the other module. Ties are broken by path, so
+// that the order is deterministic.
+func (waiter *scheduledModule) runsBefore(other *scheduledModule) bool {
+	if waiter.priority != other.priority {
+		return waiter.priority > other.priority
+	}
+	if waiter.fanout != other.fanout {
+		return waiter.fanout > other.fanout
+	}
+	return waiter.module.Module.Path < other.module.Module.Path
+}
+
+// modulePriority returns the priority declared in the configuration of the module, or 0 if none was declared.
+func modulePriority(module *TerraformModule) int {
+	if module.Config.Priority == nil {
+		return 0
+	}
+	return *module.Config.Priority
+}
+
+// moduleFanouts returns, for each of the given modules, the number of modules that transitively wait on it.
+func moduleFanouts(modules map[string]*runningModule) map[string]int {
+	fanouts := map[string]int{}
+	for path, module := range modules {
+		visited := map[string]bool{}
+		toVisit := append([]*runningModule{}, module.NotifyWhenDone...)
+		for len(toVisit) > 0 {
+			current := toVisit[0]
+			toVisit = toVisit[1:]
+			if visited[current.Module.Path] {
+				continue
+			}
+			visited[current.Module.Path] = true
+			toVisit = append(toVisit, current.NotifyWhenDone...)
+		}
+		for visitedPath := range visited {
+			if _, isRun := modules[visitedPath]; isRun {
+				fanouts[path]++
+			}
+		}
+	}
+	return fanouts
+}
@@ -0,0 +1,81 @@
+package configstack
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Create a mock TerragruntOptions object whose RunTerragrunt command appends the given name to the given run order.
+func optionsRecordingRunOrder(t *testing.T, name string, mutex *sync.Mutex, runOrder *[]string) *options.TerragruntOptions {
+	opts, err := options.NewTerragruntOptionsForTest(name)
+	require.NoError(t, err)
+	opts.RunTerragrunt = func(_ *options.TerragruntOptions) error {
+		mutex.Lock()
+		defer mutex.Unlock()
+		*runOrder = append(*runOrder, name)
+		return nil
+	}
+	return opts
+}
+
+func TestRunModulesByPriority(t *testing.T) {
+	t.Parallel()
+
+	var mutex sync.Mutex
+	runOrder := []string{}
+	priority := 10
+
+	// a and b have no declared priority, but d depends on b. c declares a priority.
+	moduleA := &TerraformModule{
+		Path:              "a",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsRecordingRunOrder(t, "a", &mutex, &runOrder),
+	}
+	moduleB := &TerraformModule{
+		Path:              "b",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsRecordingRunOrder(t, "b", &mutex, &runOrder),
+	}
+	moduleC := &TerraformModule{
+		Path:              "c",
+		Dependencies:      []*TerraformModule{},
+		Config:            config.TerragruntConfig{Priority: &priority},
+		TerragruntOptions: optionsRecordingRunOrder(t, "c", &mutex, &runOrder),
+	}
+	moduleD := &TerraformModule{
+		Path:              "d",
+		Dependencies:      []*TerraformModule{moduleB},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsRecordingRunOrder(t, "d", &mutex, &runOrder),
+	}
+
+	err := RunModules([]*TerraformModule{moduleA, moduleB, moduleC, moduleD}, 1)
+	require.NoError(t, err)
+
+	// c goes first because of its priority, then b because d depends on it. a and d tie, so they run by path.
+	assert.Equal(t, []string{"c", "b", "a", "d"}, runOrder)
+}
+
+func TestModuleFanouts(t *testing.T) {
+	t.Parallel()
+
+	moduleA := &TerraformModule{Path: "a", TerragruntOptions: mockOptions}
+	moduleB := &TerraformModule{Path: "b", Dependencies: []*TerraformModule{moduleA}, TerragruntOptions: mockOptions}
+	moduleC := &TerraformModule{Path: "c", Dependencies: []*TerraformModule{moduleA, moduleB}, TerragruntOptions: mockOptions}
+	moduleD := &TerraformModule{Path: "d", TerragruntOptions: mockOptions}
+
+	runningModules, err := toRunningModules([]*TerraformModule{moduleA, moduleB, moduleC, moduleD}, NormalOrder)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"a": 2, "b": 1}, moduleFanouts(runningModules))
+
+	runningModules, err = toRunningModules([]*TerraformModule{moduleA, moduleB, moduleC, moduleD}, ReverseOrder)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"b": 1, "c": 2}, moduleFanouts(runningModules))
+}
@@ -0,0 +1,53 @@
+package configstack
+
+import (
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// runSkipHooks runs the on_skip hooks of the module, which was skipped because one of its dependencies failed. The
+// module never ran, so its terraform block is only partially parsed here. The errors of the hooks are logged, as the
+// module already finishes with the error of its dependency.
+func (module *runningModule) runSkipHooks() {
+	opts := module.Module.TerragruntOptions
+	if module.Module.AssumeAlreadyApplied || opts.TerragruntConfigPath == "" || !util.FileExists(opts.TerragruntConfigPath) {
+		return
+	}
+
+	terragruntConfig, err := config.PartialParseConfigFile(opts.TerragruntConfigPath, opts, nil, []config.PartialDecodeSectionType{config.TerraformBlock})
+	if err != nil {
+		opts.Logger.Warnf("Failed to read the on_skip hooks of module %s: %v", module.Module.Path, err)
+		return
+	}
+	if err := terragruntConfig.Terraform.ValidateHooks(); err != nil {
+		opts.Logger.Warnf("Failed to read the on_skip hooks of module %s: %v", module.Module.Path, err)
+		return
+	}
+
+	for _, hook := range terragruntConfig.Terraform.GetOnSkipHooks() {
+		if !util.ListContainsElement(hook.Commands, opts.TerraformCommand) {
+			continue
+		}
+
+		if hook.IsHTTP() {
+			opts.Logger.Infof("Executing http hook: %s", hook.Name)
+			event := config.HookEvent{Hook: hook.Name, Path: module.Module.Path, Command: opts.TerraformCommand, Status: config.HookStatusSkipped}
+			if err := hook.SendHTTPRequest(opts, event); err != nil {
+				opts.Logger.Errorf("Error running hook %s with message: %s", hook.Name, err.Error())
+			}
+			continue
+		}
+
+		opts.Logger.Infof("Executing hook: %s", hook.Name)
+		workingDir := ""
+		if hook.WorkingDir != nil {
+			workingDir = *hook.WorkingDir
+		}
+		suppressStdout := hook.SuppressStdout != nil && *hook.SuppressStdout
+
+		hookOptions := hook.CommandOptions(opts)
+		if err := config.RunHookCommand(hookOptions, hook.Name, hook.HookTimeout(), workingDir, suppressStdout, hook.ExecuteCommand(hookOptions, workingDir)); err != nil {
+			opts.Logger.Errorf("Error running hook %s with message: %s", hook.Name, err.Error())
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package configstack
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+func TestRunModulesRunsSkipHooksWhenDependencyFails(t *testing.T) {
+	t.Parallel()
+
+	rootDir := t.TempDir()
+	writeCycleTestConfig(t, rootDir, "app", fmt.Sprintf(`
+terraform {
+  on_skip_hook "notify" {
+    commands    = ["apply"]
+    execute     = ["touch", "skipped"]
+    working_dir = %q
+  }
+
+  on_skip_hook "plan_only" {
+    commands    = ["plan"]
+    execute     = ["touch", "planned"]
+    working_dir = %q
+  }
+}
+`, rootDir, rootDir))
+
+	vpcRan := false
+	vpcErr := fmt.Errorf("Expected error for module vpc")
+	vpc := &TerraformModule{
+		Path:              "vpc",
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, filepath.Join(rootDir, "vpc", config.DefaultTerragruntConfigPath), vpcErr, &vpcRan),
+	}
+
+	appRan := false
+	app := &TerraformModule{
+		Path:              "app",
+		Dependencies:      []*TerraformModule{vpc},
+		Config:            config.TerragruntConfig{},
+		TerragruntOptions: optionsWithMockTerragruntCommand(t, filepath.Join(rootDir, "app", config.DefaultTerragruntConfigPath), nil, &appRan),
+	}
+	app.TerragruntOptions.TerraformCommand = "apply"
+
+	err := RunModules([]*TerraformModule{vpc, app}, 1)
+	require.Error(t, err)
+	assert.False(t, appRan)
+	assert.True(t, util.FileExists(filepath.Join(rootDir, "skipped")))
+	assert.False(t, util.FileExists(filepath.Join(rootDir, "planned")))
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"path/filepath"
 	"sort"
 	"strings"
 
@@ -52,6 +53,56 @@ func (stack *Stack) LogModuleDeployOrder(logger *logrus.Entry, terraformCommand
 	return nil
 }
 
+// PreviewModuleRunOrder writes the groups in which the modules of the stack will be processed for the given command,
+// along with the modules that each module has to wait for, to the given writer. This doesn't run any command in the
+// modules.
+func (stack *Stack) PreviewModuleRunOrder(writer io.Writer, terraformCommand string) error {
+	runGraph, err := stack.getModuleRunGraph(terraformCommand)
+	if err != nil {
+		return err
+	}
+
+	groupIndexes := map[string]int{}
+	for i, group := range runGraph {
+		for _, module := range group {
+			groupIndexes[module.Path] = i + 1
+		}
+	}
+
+	// For destroy, modules wait for the modules that depend on them to be destroyed first.
+	waitsFor := map[string][]string{}
+	for _, module := range stack.Modules {
+		for _, dependency := range module.Dependencies {
+			if terraformCommand == "destroy" {
+				waitsFor[dependency.Path] = append(waitsFor[dependency.Path], module.Path)
+			} else {
+				waitsFor[module.Path] = append(waitsFor[module.Path], dependency.Path)
+			}
+		}
+	}
+
+	outStr := fmt.Sprintf("The stack at %s will be processed in the following order for command %s:\n", stack.Path, terraformCommand)
+	for i, group := range runGraph {
+		outStr += fmt.Sprintf("Group %d\n", i+1)
+		for _, module := range group {
+			outStr += fmt.Sprintf("- Module %s\n", module.Path)
+
+			blockers := waitsFor[module.Path]
+			sort.Strings(blockers)
+			for _, blocker := range blockers {
+				// Modules that are excluded or already applied are not part of the run, so they don't affect the order.
+				if groupIndex, isInRun := groupIndexes[blocker]; isInRun {
+					outStr += fmt.Sprintf("    waits for %s (group %d)\n", blocker, groupIndex)
+				}
+			}
+		}
+		outStr += "\n"
+	}
+
+	_, err = fmt.Fprint(writer, outStr)
+	return errors.WithStackTrace(err)
+}
+
 // JsonModuleDeployOrder will return the modules that will be deployed by a plan/apply operation, in the order
 // that the operations happen.
 func (stack *Stack) JsonModuleDeployOrder(terraformCommand string) (string, error) {
@@ -76,6 +127,55 @@ func (stack *Stack) JsonModuleDeployOrder(terraformCommand string) (string, erro
 	return string(j), nil
 }
 
+// FindDependents returns the modules of the stack that depend on the module at the given path, either directly or
+// through other modules, sorted by path. The path must be the canonical path of a module in the stack.
+func (stack *Stack) FindDependents(modulePath string) ([]*TerraformModule, error) {
+	found := false
+	for _, module := range stack.Modules {
+		if module.Path == modulePath {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return nil, errors.WithStackTrace(ModuleNotInStack{ModulePath: modulePath, StackPath: stack.Path})
+	}
+
+	return findTransitiveDependents(stack.Modules, []string{modulePath}), nil
+}
+
+// findTransitiveDependents returns the modules that depend on any of the modules at the given paths, either directly
+// or through other modules, sorted by path. The modules at the given paths are not included, unless they depend on
+// each other.
+func findTransitiveDependents(modules []*TerraformModule, modulePaths []string) []*TerraformModule {
+	// Map each module to the modules that depend on it directly, so the dependents can be walked from the roots.
+	directDependents := map[string][]*TerraformModule{}
+	for _, module := range modules {
+		for _, dependency := range module.Dependencies {
+			directDependents[dependency.Path] = append(directDependents[dependency.Path], module)
+		}
+	}
+
+	visited := map[string]bool{}
+	dependents := []*TerraformModule{}
+	queue := append([]string{}, modulePaths...)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		for _, dependent := range directDependents[current] {
+			if visited[dependent.Path] {
+				continue
+			}
+			visited[dependent.Path] = true
+			dependents = append(dependents, dependent)
+			queue = append(queue, dependent.Path)
+		}
+	}
+
+	sort.Slice(dependents, func(i, j int) bool { return dependents[i].Path < dependents[j].Path })
+	return dependents
+}
+
 // Graph creates a graphviz representation of the modules
 func (stack *Stack) Graph(terragruntOptions *options.TerragruntOptions) {
 	err := WriteDot(terragruntOptions.Writer, terragruntOptions, stack.Modules)
@@ -87,6 +187,10 @@ func (stack *Stack) Graph(terragruntOptions *options.TerragruntOptions) {
 func (stack *Stack) Run(terragruntOptions *options.TerragruntOptions) error {
 	stackCmd := terragruntOptions.TerraformCommand
 
+	if err := stack.CheckForStateCollisions(terragruntOptions); err != nil {
+		return err
+	}
+
 	// For any command that needs input, run in non-interactive mode to avoid cominglint stdin across multiple
 	// concurrent runs.
 	if util.ListContainsElement(config.TERRAFORM_COMMANDS_NEED_INPUT, stackCmd) {
@@ -109,6 +213,14 @@ func (stack *Stack) Run(terragruntOptions *options.TerragruntOptions) error {
 		stack.syncTerraformCliArgs(terragruntOptions)
 	}
 
+	if stackCmd == "cost" {
+		return stack.runCost(terragruntOptions)
+	}
+
+	if isProvidersLockCommand(terragruntOptions.TerraformCliArgs) {
+		return stack.runProvidersLock(terragruntOptions)
+	}
+
 	if stackCmd == "plan" {
 		// We capture the out stream for each module
 		errorStreams := make([]bytes.Buffer, len(stack.Modules))
@@ -120,18 +232,117 @@ func (stack *Stack) Run(terragruntOptions *options.TerragruntOptions) error {
 			}
 		}
 		defer stack.summarizePlanAllErrors(terragruntOptions, errorStreams)
+
+		if terragruntOptions.PlanSummary || terragruntOptions.PlanSummaryJSONFile != "" {
+			defer stack.writePlanSummary(terragruntOptions)
+		}
+	}
+
+	if stackCmd == "output" && terragruntOptions.RunAllOutputFormat == OutputFormatJSON {
+		if !util.ListContainsElement(terragruntOptions.TerraformCliArgs, "-json") {
+			terragruntOptions.TerraformCliArgs = util.StringListInsert(terragruntOptions.TerraformCliArgs, "-json", 1)
+			stack.syncTerraformCliArgs(terragruntOptions)
+		}
+
+		// The outputs of each module are captured, rather than interleaved on stdout, to be written as a single document.
+		outStreams := make([]bytes.Buffer, len(stack.Modules))
+		for n, module := range stack.Modules {
+			module.TerragruntOptions.Writer = &outStreams[n]
+		}
+
+		runErr := stack.runModules(terragruntOptions)
+		if err := stack.writeOutputsDocument(terragruntOptions, outStreams); err != nil {
+			return err
+		}
+		return runErr
+	}
+
+	return stack.runModules(terragruntOptions)
+}
+
+// runModules runs the command in the modules of the stack, in the order required by the command and the options.
+func (stack *Stack) runModules(terragruntOptions *options.TerragruntOptions) error {
+	if terragruntOptions.ContinueOnError {
+		return stack.runWithSummary(terragruntOptions)
 	}
 
 	switch {
 	case terragruntOptions.IgnoreDependencyOrder:
 		return RunModulesIgnoreOrder(stack.Modules, terragruntOptions.Parallelism)
-	case stackCmd == "destroy":
+	case terragruntOptions.TerraformCommand == "destroy":
 		return RunModulesReverseOrder(stack.Modules, terragruntOptions.Parallelism)
 	default:
 		return RunModules(stack.Modules, terragruntOptions.Parallelism)
 	}
 }
 
+// runWithSummary runs the modules of the stack until every module either finished or was skipped because one of its
+// dependencies failed, and then writes a summary of the outcome of every module to a file if requested, or to stderr,
+// so that it isn't mixed with the output of terraform on stdout.
+func (stack *Stack) runWithSummary(terragruntOptions *options.TerragruntOptions) error {
+	dependencyOrder := NormalOrder
+	switch {
+	case terragruntOptions.IgnoreDependencyOrder:
+		dependencyOrder = IgnoreOrder
+	case terragruntOptions.TerraformCommand == "destroy":
+		dependencyOrder = ReverseOrder
+	}
+
+	summary, runErr := RunModulesWithSummary(stack.Modules, dependencyOrder, terragruntOptions.Parallelism)
+	if summary == nil {
+		return runErr
+	}
+
+	terragruntOptions.Logger.Infof("Run summary: %d succeeded, %d failed, %d skipped", len(summary.Succeeded), len(summary.Failed), len(summary.Skipped))
+	if terragruntOptions.RunSummaryJSONFile == "" {
+		if err := summary.Write(terragruntOptions.ErrWriter); err != nil {
+			return err
+		}
+		return runErr
+	}
+
+	jsonPath := terragruntOptions.RunSummaryJSONFile
+	if !filepath.IsAbs(jsonPath) {
+		jsonPath = filepath.Join(terragruntOptions.WorkingDir, jsonPath)
+	}
+	if err := summary.WriteJSONFile(jsonPath); err != nil {
+		return err
+	}
+	return runErr
+}
+
+// writePlanSummary rolls up the changes recorded from the plan file of every module that was run, and writes the
+// roll-up as text to stdout and/or as JSON to a file, as requested.
+func (stack *Stack) writePlanSummary(terragruntOptions *options.TerragruntOptions) {
+	planChanges := map[string]*PlanChanges{}
+	for _, module := range stack.Modules {
+		if module.FlagExcluded || module.AssumeAlreadyApplied {
+			continue
+		}
+		planChanges[module.Path] = nil
+		if changes, found := loadPlanChanges(module.TerragruntOptions.TerragruntConfigPath); found {
+			planChanges[module.Path] = &changes
+		}
+	}
+	summary := NewPlanSummary(planChanges)
+
+	if terragruntOptions.PlanSummary {
+		if err := summary.WriteText(terragruntOptions.Writer); err != nil {
+			terragruntOptions.Logger.Errorf("Failed to write the plan summary: %v", err)
+		}
+	}
+
+	if terragruntOptions.PlanSummaryJSONFile != "" {
+		jsonPath := terragruntOptions.PlanSummaryJSONFile
+		if !filepath.IsAbs(jsonPath) {
+			jsonPath = filepath.Join(terragruntOptions.WorkingDir, jsonPath)
+		}
+		if err := summary.WriteJSONFile(jsonPath); err != nil {
+			terragruntOptions.Logger.Errorf("Failed to write the plan summary to %s: %v", jsonPath, err)
+		}
+	}
+}
+
 // We inspect the error streams to give an explicit message if the plan failed because there were references to
 // remote states. `terraform plan` will fail if it tries to access remote state from dependencies and the plan
 // has never been applied on the dependency.
@@ -275,6 +486,9 @@ func createStackForTerragruntConfigPaths(path string, terragruntConfigPaths []st
 
 	stack := &Stack{Path: path, Modules: modules}
 	if err := stack.CheckForCycles(); err != nil {
+		if cycle, isCycle := errors.Unwrap(err).(DependencyCycle); isCycle {
+			return nil, dependencyCycleWithDeclarations(cycle, modules, terragruntOptions)
+		}
 		return nil, err
 	}
 
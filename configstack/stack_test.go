@@ -1,11 +1,13 @@
 package configstack
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/gruntwork-io/terragrunt/util"
@@ -104,6 +106,74 @@ func TestGetModuleRunGraphDestroyOrder(t *testing.T) {
 
 }
 
+func TestPreviewModuleRunOrder(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+
+	var out bytes.Buffer
+	require.NoError(t, stack.PreviewModuleRunOrder(&out, "apply"))
+
+	expected := `The stack at /stage/mystack will be processed in the following order for command apply:
+Group 1
+- Module /stage/mystack/vpc
+
+Group 2
+- Module /stage/mystack/mysql
+    waits for /stage/mystack/vpc (group 1)
+- Module /stage/mystack/redis
+    waits for /stage/mystack/vpc (group 1)
+
+Group 3
+- Module /stage/mystack/myapp
+    waits for /stage/mystack/mysql (group 2)
+    waits for /stage/mystack/redis (group 2)
+
+`
+	assert.Equal(t, expected, out.String())
+}
+
+func TestPreviewModuleRunOrderDestroy(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+
+	var out bytes.Buffer
+	require.NoError(t, stack.PreviewModuleRunOrder(&out, "destroy"))
+
+	assert.Contains(t, out.String(), "Group 1\n- Module /stage/mystack/myapp\n\n")
+	assert.Contains(t, out.String(), "- Module /stage/mystack/vpc\n    waits for /stage/mystack/mysql (group 2)\n    waits for /stage/mystack/redis (group 2)\n")
+}
+
+func TestFindDependents(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+
+	dependents, err := stack.FindDependents("/stage/mystack/vpc")
+	require.NoError(t, err)
+
+	paths := []string{}
+	for _, module := range dependents {
+		paths = append(paths, module.Path)
+	}
+	assert.Equal(t, []string{"/stage/mystack/lambda", "/stage/mystack/myapp", "/stage/mystack/mysql", "/stage/mystack/redis"}, paths)
+
+	dependents, err = stack.FindDependents("/stage/mystack/myapp")
+	require.NoError(t, err)
+	assert.Empty(t, dependents)
+}
+
+func TestFindDependentsModuleNotInStack(t *testing.T) {
+	t.Parallel()
+
+	stack := createTestStack()
+
+	_, err := stack.FindDependents("/stage/mystack/unknown")
+	require.Error(t, err)
+	assert.IsType(t, ModuleNotInStack{}, errors.Unwrap(err))
+}
+
 func createTestStack() *Stack {
 	// Create the following module stack:
 	// - account-baseline (excluded)
@@ -0,0 +1,64 @@
+package configstack
+
+import (
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+)
+
+// CheckForStateCollisions returns an error if two modules of the stack store their state at the same location, e.g. in
+// the same S3 bucket under the same key because the key template was copy-pasted, as they would silently overwrite each
+// other's state. The modules whose remote_state can't be parsed without running them, such as the ones that depend on
+// the outputs of other modules, are not checked.
+func (stack *Stack) CheckForStateCollisions(terragruntOptions *options.TerragruntOptions) error {
+	return CheckForStateCollisions(stack.Modules, terragruntOptions)
+}
+
+// CheckForStateCollisions returns an error if two of the given modules store their state at the same location.
+func CheckForStateCollisions(modules []*TerraformModule, terragruntOptions *options.TerragruntOptions) error {
+	modulePathsByLocation := map[string]string{}
+
+	for _, module := range modules {
+		if module.FlagExcluded {
+			continue
+		}
+
+		remoteState, err := moduleRemoteState(module)
+		if err != nil {
+			terragruntOptions.Logger.Debugf("Not checking the state location of module %s, as its remote_state can not be parsed: %v", module.Path, err)
+			continue
+		}
+		if remoteState == nil {
+			continue
+		}
+
+		location := remoteState.StateLocation()
+		if location == "" {
+			continue
+		}
+
+		if otherModulePath, isUsed := modulePathsByLocation[location]; isUsed {
+			return errors.WithStackTrace(StateCollision{Location: location, ModulePaths: []string{otherModulePath, module.Path}})
+		}
+		modulePathsByLocation[location] = module.Path
+	}
+
+	return nil
+}
+
+// moduleRemoteState returns the remote state of the given module, which is only partially parsed when the stack is
+// resolved.
+func moduleRemoteState(module *TerraformModule) (*remote.RemoteState, error) {
+	if module.Config.RemoteState != nil {
+		return module.Config.RemoteState, nil
+	}
+
+	terragruntConfig, err := config.PartialParseConfigFile(module.TerragruntOptions.TerragruntConfigPath, module.TerragruntOptions, nil, []config.PartialDecodeSectionType{config.RemoteStateBlock})
+	if err != nil {
+		return nil, err
+	}
+
+	return terragruntConfig.RemoteState, nil
+}
@@ -0,0 +1,55 @@
+package configstack
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/config"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/remote"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func s3Module(path string, key string) *TerraformModule {
+	return &TerraformModule{
+		Path: path,
+		Config: config.TerragruntConfig{
+			RemoteState: &remote.RemoteState{Backend: "s3", Config: map[string]interface{}{"bucket": "my-bucket", "key": key}},
+		},
+	}
+}
+
+func TestCheckForStateCollisions(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("stack_test")
+	require.NoError(t, err)
+
+	modules := []*TerraformModule{
+		s3Module("/live/vpc", "vpc/terraform.tfstate"),
+		s3Module("/live/mysql", "mysql/terraform.tfstate"),
+	}
+	assert.NoError(t, CheckForStateCollisions(modules, terragruntOptions))
+
+	modules = append(modules, s3Module("/live/redis", "mysql/terraform.tfstate"))
+	err = CheckForStateCollisions(modules, terragruntOptions)
+
+	collision, ok := errors.Unwrap(err).(StateCollision)
+	require.True(t, ok)
+	assert.Equal(t, "s3://my-bucket/mysql/terraform.tfstate", collision.Location)
+	assert.Equal(t, []string{"/live/mysql", "/live/redis"}, collision.ModulePaths)
+}
+
+func TestCheckForStateCollisionsIgnoresExcludedModules(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("stack_test")
+	require.NoError(t, err)
+
+	excluded := s3Module("/live/redis", "mysql/terraform.tfstate")
+	excluded.FlagExcluded = true
+
+	modules := []*TerraformModule{s3Module("/live/mysql", "mysql/terraform.tfstate"), excluded}
+	assert.NoError(t, CheckForStateCollisions(modules, terragruntOptions))
+}
@@ -0,0 +1,163 @@
+// Package cost estimates the monthly cost of the resources of terraform plans with a cost engine, and reports the cost
+// of each unit of a stack and the total cost.
+package cost
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+)
+
+// The cost engines the cost command can estimate the cost of plans with.
+const (
+	// EngineInfracost runs the infracost CLI, which must be installed and authenticated, on the plans.
+	EngineInfracost = "infracost"
+	// EngineInfracostAPI posts the plans to an HTTP API that responds with the infracost breakdown of the plan.
+	EngineInfracostAPI = "infracost-api"
+
+	// EnvNameInfracostAPIKey is the environment variable holding the API key sent to the API of EngineInfracostAPI.
+	EnvNameInfracostAPIKey = "INFRACOST_API_KEY"
+
+	infracostCommand = "infracost"
+)
+
+// Estimate is the cost estimated for a plan: its total monthly cost, in the given currency.
+type Estimate struct {
+	MonthlyCost float64
+	Currency    string
+}
+
+// Engine estimates the cost of a plan, given as the path of the JSON representation of the plan, as output by
+// `terraform show -json`.
+type Engine interface {
+	Estimate(opts *options.TerragruntOptions, planJSONFile string) (*Estimate, error)
+}
+
+// NewEngine returns the cost engine of the given options, which defaults to the infracost CLI.
+func NewEngine(opts *options.TerragruntOptions) (Engine, error) {
+	switch opts.CostEngine {
+	case "", EngineInfracost:
+		return &InfracostCLI{}, nil
+	case EngineInfracostAPI:
+		if opts.CostAPIURL == "" {
+			return nil, errors.WithStackTrace(MissingCostAPIURL{})
+		}
+		return &InfracostAPI{URL: opts.CostAPIURL, Client: http.DefaultClient}, nil
+	default:
+		return nil, errors.WithStackTrace(UnsupportedCostEngine(opts.CostEngine))
+	}
+}
+
+// InfracostCLI estimates the cost of plans with `infracost breakdown`.
+type InfracostCLI struct{}
+
+func (engine *InfracostCLI) Estimate(opts *options.TerragruntOptions, planJSONFile string) (*Estimate, error) {
+	out, err := shell.RunShellCommandWithOutput(opts, "", true, false, infracostCommand, "breakdown", "--path", planJSONFile, "--format", "json", "--no-color")
+	if err != nil {
+		return nil, err
+	}
+	return parseBreakdown([]byte(out.Stdout))
+}
+
+// InfracostAPI estimates the cost of plans by posting them to an HTTP API, with the API key of INFRACOST_API_KEY, that
+// responds with the infracost breakdown of the plan, as `infracost breakdown --format json` outputs it.
+type InfracostAPI struct {
+	URL    string
+	Client *http.Client
+}
+
+func (engine *InfracostAPI) Estimate(opts *options.TerragruntOptions, planJSONFile string) (*Estimate, error) {
+	plan, err := os.ReadFile(planJSONFile)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost, engine.URL, bytes.NewReader(plan))
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if apiKey := opts.Env[EnvNameInfracostAPIKey]; apiKey != "" {
+		req.Header.Set("X-Api-Key", apiKey)
+	}
+
+	resp, err := engine.Client.Do(req)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.WithStackTrace(CostAPIError{URL: engine.URL, StatusCode: resp.StatusCode, Body: string(body)})
+	}
+	return parseBreakdown(body)
+}
+
+// infracostBreakdown holds the fields of the infracost breakdown JSON that the estimate is read from. The costs are
+// decimal strings, and null when infracost doesn't know the cost of any resource.
+type infracostBreakdown struct {
+	Currency         string  `json:"currency"`
+	TotalMonthlyCost *string `json:"totalMonthlyCost"`
+}
+
+func parseBreakdown(breakdownJSON []byte) (*Estimate, error) {
+	var breakdown infracostBreakdown
+	if err := json.Unmarshal(breakdownJSON, &breakdown); err != nil {
+		return nil, errors.WithStackTrace(InvalidBreakdown{Reason: err.Error()})
+	}
+
+	estimate := &Estimate{Currency: breakdown.Currency}
+	if breakdown.TotalMonthlyCost != nil {
+		monthlyCost, err := strconv.ParseFloat(*breakdown.TotalMonthlyCost, 64)
+		if err != nil {
+			return nil, errors.WithStackTrace(InvalidBreakdown{Reason: err.Error()})
+		}
+		estimate.MonthlyCost = monthlyCost
+	}
+	return estimate, nil
+}
+
+// Custom error types
+
+type UnsupportedCostEngine string
+
+func (err UnsupportedCostEngine) Error() string {
+	return fmt.Sprintf("Unsupported cost engine %s. The supported engines are %s and %s.", string(err), EngineInfracost, EngineInfracostAPI)
+}
+
+type MissingCostAPIURL struct{}
+
+func (err MissingCostAPIURL) Error() string {
+	return fmt.Sprintf("The %s cost engine requires the URL of the API to post the plans to.", EngineInfracostAPI)
+}
+
+type CostAPIError struct {
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+func (err CostAPIError) Error() string {
+	return fmt.Sprintf("The cost API %s responded with status %d: %s", err.URL, err.StatusCode, err.Body)
+}
+
+type InvalidBreakdown struct {
+	Reason string
+}
+
+func (err InvalidBreakdown) Error() string {
+	return fmt.Sprintf("Could not read the cost estimate of the infracost breakdown: %s", err.Reason)
+}
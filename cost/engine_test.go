@@ -0,0 +1,101 @@
+package cost
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+func TestParseBreakdown(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name          string
+		breakdown     string
+		expected      *Estimate
+		expectedError string
+	}{
+		{"cost", `{"version": "0.2", "currency": "USD", "projects": [], "totalHourlyCost": "0.0144", "totalMonthlyCost": "10.512"}`, &Estimate{MonthlyCost: 10.512, Currency: "USD"}, ""},
+		{"unknown cost", `{"currency": "EUR", "totalMonthlyCost": null}`, &Estimate{Currency: "EUR"}, ""},
+		{"invalid cost", `{"currency": "USD", "totalMonthlyCost": "a lot"}`, nil, "Could not read the cost estimate"},
+		{"invalid json", `Error: no valid Terraform files found`, nil, "Could not read the cost estimate"},
+	}
+
+	for _, testCase := range testCases {
+		testCase := testCase
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			estimate, err := parseBreakdown([]byte(testCase.breakdown))
+			if testCase.expectedError != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), testCase.expectedError)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.expected, estimate)
+		})
+	}
+}
+
+func TestInfracostAPIEstimate(t *testing.T) {
+	t.Parallel()
+
+	planJSONFile := filepath.Join(t.TempDir(), "plan.json")
+	require.NoError(t, os.WriteFile(planJSONFile, []byte(`{"format_version": "1.2"}`), 0644))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		if r.Header.Get("X-Api-Key") != "secret" || string(body) != `{"format_version": "1.2"}` {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{"currency": "USD", "totalMonthlyCost": "42"}`))
+	}))
+	defer server.Close()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+	opts.CostEngine = EngineInfracostAPI
+	opts.CostAPIURL = server.URL
+	opts.Env[EnvNameInfracostAPIKey] = "secret"
+
+	engine, err := NewEngine(opts)
+	require.NoError(t, err)
+	estimate, err := engine.Estimate(opts, planJSONFile)
+	require.NoError(t, err)
+	assert.Equal(t, &Estimate{MonthlyCost: 42, Currency: "USD"}, estimate)
+
+	opts.Env[EnvNameInfracostAPIKey] = "wrong"
+	_, err = engine.Estimate(opts, planJSONFile)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "responded with status 401")
+}
+
+func TestNewEngine(t *testing.T) {
+	t.Parallel()
+
+	opts, err := options.NewTerragruntOptionsForTest("terragrunt.hcl")
+	require.NoError(t, err)
+
+	engine, err := NewEngine(opts)
+	require.NoError(t, err)
+	assert.IsType(t, &InfracostCLI{}, engine)
+
+	opts.CostEngine = EngineInfracostAPI
+	_, err = NewEngine(opts)
+	assert.ErrorContains(t, err, "requires the URL of the API")
+
+	opts.CostEngine = "other"
+	_, err = NewEngine(opts)
+	assert.ErrorContains(t, err, "Unsupported cost engine other")
+}
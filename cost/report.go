@@ -0,0 +1,134 @@
+package cost
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// The formats the cost report can be written in.
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+	FormatHTML = "html"
+)
+
+// UnitCost is the estimated monthly cost of the plan of a unit.
+type UnitCost struct {
+	Path        string  `json:"path"`
+	MonthlyCost float64 `json:"monthly_cost"`
+	Currency    string  `json:"currency"`
+}
+
+// Report is the estimated monthly cost of each unit, sorted by path, and their total.
+type Report struct {
+	Units            []UnitCost `json:"units"`
+	TotalMonthlyCost float64    `json:"total_monthly_cost"`
+	Currency         string     `json:"currency"`
+}
+
+// NewReport builds the report of the given costs of units. The currency of the report is the one of the units, which
+// infracost reports all the costs in.
+func NewReport(units []UnitCost) *Report {
+	report := &Report{Units: append([]UnitCost{}, units...)}
+	for _, unit := range units {
+		report.TotalMonthlyCost += unit.MonthlyCost
+		if report.Currency == "" {
+			report.Currency = unit.Currency
+		}
+	}
+	sort.Slice(report.Units, func(i, j int) bool { return report.Units[i].Path < report.Units[j].Path })
+	return report
+}
+
+// ValidateFormat returns an error if the given format is not one of the formats of the report. An empty format is the
+// text format.
+func ValidateFormat(format string) error {
+	switch format {
+	case "", FormatText, FormatJSON, FormatHTML:
+		return nil
+	}
+	return errors.WithStackTrace(UnsupportedReportFormat(format))
+}
+
+// Write writes the report to the given writer in the given format, which defaults to text.
+func (report *Report) Write(writer io.Writer, format string) error {
+	switch format {
+	case "", FormatText:
+		return report.writeText(writer)
+	case FormatJSON:
+		return report.writeJSON(writer)
+	case FormatHTML:
+		return report.writeHTML(writer)
+	}
+	return errors.WithStackTrace(UnsupportedReportFormat(format))
+}
+
+func (report *Report) writeText(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "UNIT\tMONTHLY COST")
+	for _, unit := range report.Units {
+		fmt.Fprintf(table, "%s\t%s\n", unit.Path, formatCost(unit.MonthlyCost, unit.Currency))
+	}
+	fmt.Fprintf(table, "TOTAL\t%s\n", formatCost(report.TotalMonthlyCost, report.Currency))
+	return errors.WithStackTrace(table.Flush())
+}
+
+func (report *Report) writeJSON(writer io.Writer) error {
+	reportJSON, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+	if _, err := writer.Write(append(reportJSON, '\n')); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+var htmlReportTemplate = template.Must(template.New("cost").Funcs(template.FuncMap{"cost": formatCost}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Terragrunt cost estimate</title>
+</head>
+<body>
+<table>
+<thead>
+<tr><th>Unit</th><th>Monthly cost</th></tr>
+</thead>
+<tbody>
+{{- range .Units}}
+<tr><td>{{.Path}}</td><td>{{cost .MonthlyCost .Currency}}</td></tr>
+{{- end}}
+</tbody>
+<tfoot>
+<tr><th>Total</th><th>{{cost .TotalMonthlyCost .Currency}}</th></tr>
+</tfoot>
+</table>
+</body>
+</html>
+`))
+
+func (report *Report) writeHTML(writer io.Writer) error {
+	return errors.WithStackTrace(htmlReportTemplate.Execute(writer, report))
+}
+
+func formatCost(cost float64, currency string) string {
+	if currency == "" {
+		return fmt.Sprintf("%.2f", cost)
+	}
+	return fmt.Sprintf("%.2f %s", cost, currency)
+}
+
+// Custom error types
+
+type UnsupportedReportFormat string
+
+func (err UnsupportedReportFormat) Error() string {
+	return fmt.Sprintf("Unsupported cost report format %s. The supported formats are %s, %s and %s.", string(err), FormatText, FormatJSON, FormatHTML)
+}
@@ -0,0 +1,55 @@
+package cost
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReportWrite(t *testing.T) {
+	t.Parallel()
+
+	report := NewReport([]UnitCost{
+		{Path: "vpc", MonthlyCost: 10.5, Currency: "USD"},
+		{Path: "app", MonthlyCost: 2.25, Currency: "USD"},
+	})
+
+	testCases := []struct {
+		format   string
+		expected string
+	}{
+		{"", "UNIT   MONTHLY COST\napp    2.25 USD\nvpc    10.50 USD\nTOTAL  12.75 USD\n"},
+		{FormatJSON, `{
+  "units": [
+    {
+      "path": "app",
+      "monthly_cost": 2.25,
+      "currency": "USD"
+    },
+    {
+      "path": "vpc",
+      "monthly_cost": 10.5,
+      "currency": "USD"
+    }
+  ],
+  "total_monthly_cost": 12.75,
+  "currency": "USD"
+}
+`},
+	}
+
+	for _, testCase := range testCases {
+		var out bytes.Buffer
+		require.NoError(t, report.Write(&out, testCase.format))
+		assert.Equal(t, testCase.expected, out.String())
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, report.Write(&out, FormatHTML))
+	assert.Contains(t, out.String(), "<tr><td>app</td><td>2.25 USD</td></tr>\n<tr><td>vpc</td><td>10.50 USD</td></tr>")
+	assert.Contains(t, out.String(), "<tr><th>Total</th><th>12.75 USD</th></tr>")
+
+	assert.ErrorContains(t, report.Write(&out, "csv"), "Unsupported cost report format csv")
+}
@@ -22,6 +22,9 @@ var tableCreateDeleteSemaphore = NewCountingSemaphore(dynamoParallelOperations)
 // Terraform requires the DynamoDB table to have a primary key with this name
 const ATTR_LOCK_ID = "LockID"
 
+// Terraform stores the info of a lock in this attribute of the item of the lock
+const attrLockInfo = "Info"
+
 // Default is to retry for up to 5 minutes
 const MAX_RETRIES_WAITING_FOR_TABLE_TO_BE_ACTIVE = 30
 const SLEEP_BETWEEN_TABLE_STATUS_CHECKS = 10 * time.Second
@@ -155,6 +158,32 @@ func tagTableIfTagsGiven(tags map[string]string, tableArn *string, client *dynam
 	return err
 }
 
+// Return the IDs of the locks that are held in the given lock table. Terraform also stores the digests of the states in
+// the table, which are not locks, so only the items with the lock info are returned.
+func LockTableLockIDs(tableName string, client *dynamodb.DynamoDB) ([]string, error) {
+	input := &dynamodb.ScanInput{
+		TableName:                aws.String(tableName),
+		FilterExpression:         aws.String("attribute_exists(#info)"),
+		ExpressionAttributeNames: map[string]*string{"#info": aws.String(attrLockInfo)},
+		ProjectionExpression:     aws.String(ATTR_LOCK_ID),
+	}
+
+	lockIDs := []string{}
+	err := client.ScanPages(input, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		for _, item := range page.Items {
+			if lockID, ok := item[ATTR_LOCK_ID]; ok && lockID.S != nil {
+				lockIDs = append(lockIDs, *lockID.S)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	return lockIDs, nil
+}
+
 // Delete the given table in DynamoDB
 func DeleteTable(tableName string, client *dynamodb.DynamoDB) error {
 	tableCreateDeleteSemaphore.Acquire()
@@ -5,6 +5,7 @@ import (
 
 	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/cli"
+	"github.com/gruntwork-io/terragrunt/config"
 	"github.com/gruntwork-io/terragrunt/shell"
 	"github.com/gruntwork-io/terragrunt/util"
 )
@@ -25,7 +26,7 @@ func checkForErrorsAndExit(err error) {
 		os.Exit(0)
 	} else {
 		util.GlobalFallbackLogEntry.Debugf(errors.PrintErrorWithStackTrace(err))
-		util.GlobalFallbackLogEntry.Errorf(err.Error())
+		util.GlobalFallbackLogEntry.Error(config.FormatError(err))
 
 		// exit with the underlying error code
 		exitCode, exitCodeErr := shell.GetExitCode(err)
@@ -1,6 +1,12 @@
 package options
 
-import "time"
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/gruntwork-io/terragrunt/util"
+)
 
 const DEFAULT_RETRY_MAX_ATTEMPTS = 3
 const DEFAULT_RETRY_SLEEP_INTERVAL_SEC = 5 * time.Second
@@ -22,3 +28,96 @@ var DEFAULT_RETRYABLE_ERRORS = []string{
 	"(?s).*Client\\.Timeout exceeded while awaiting headers.*",
 	"(?s).*Could not download module.*The requested URL returned error: 429.*",
 }
+
+// RetryPolicy describes how terragrunt retries a terraform command whose output matches one of the RetryableErrors.
+// Policies are configured through `retry` blocks in the terragrunt configuration.
+type RetryPolicy struct {
+	Name string
+
+	// RetryableErrors is an array of regular expressions with RE2 syntax that qualify for retrying under this policy
+	RetryableErrors []string
+
+	// Commands restricts the policy to the given terraform commands. An empty list applies the policy to all commands.
+	Commands []string
+
+	// Maximum number of times the command is run when the output matches this policy
+	MaxAttempts int
+
+	// Sleep interval before the first retry
+	SleepInterval time.Duration
+
+	// Upper bound for the sleep interval after applying the backoff multiplier. Zero means no upper bound.
+	MaxSleepInterval time.Duration
+
+	// Multiplier applied to the sleep interval after every retry
+	BackoffMultiplier float64
+
+	// Whether to randomize the sleep interval to avoid retrying many modules at the same time
+	Jitter bool
+}
+
+// AppliesToCommand returns true if the policy should be considered for the given terraform command.
+func (policy *RetryPolicy) AppliesToCommand(command string) bool {
+	return len(policy.Commands) == 0 || util.ListContainsElement(policy.Commands, command)
+}
+
+// Matches returns true if any of the given outputs matches one of the retryable errors of the policy.
+func (policy *RetryPolicy) Matches(outputs ...string) bool {
+	for _, output := range outputs {
+		if util.MatchesAny(policy.RetryableErrors, output) {
+			return true
+		}
+	}
+	return false
+}
+
+// SleepDuration returns how long to sleep before the given retry attempt, starting at 1.
+func (policy *RetryPolicy) SleepDuration(attempt int) time.Duration {
+	sleep := float64(policy.SleepInterval)
+	if policy.BackoffMultiplier > 1 && attempt > 1 {
+		sleep *= math.Pow(policy.BackoffMultiplier, float64(attempt-1))
+	}
+	if policy.MaxSleepInterval > 0 && sleep > float64(policy.MaxSleepInterval) {
+		sleep = float64(policy.MaxSleepInterval)
+	}
+	if policy.Jitter && sleep > 0 {
+		// Keep at least half of the interval so that jitter never turns into a busy loop.
+		sleep = sleep/2 + rand.Float64()*sleep/2
+	}
+	return time.Duration(sleep)
+}
+
+// DefaultRetryPolicy returns the policy built from the retryable_errors, retry_max_attempts and
+// retry_sleep_interval_sec settings. It is used when none of the configured retry blocks match.
+func (opts *TerragruntOptions) DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		Name:              "default",
+		RetryableErrors:   opts.RetryableErrors,
+		MaxAttempts:       opts.RetryMaxAttempts,
+		SleepInterval:     opts.RetrySleepIntervalSec,
+		BackoffMultiplier: 1,
+	}
+}
+
+// FindRetryPolicy returns the policy to apply to the output of the given terraform command, or nil if the output is not
+// retryable. Policies restricted to the command take precedence over generic policies, and the default policy is only
+// checked when no configured policy matches.
+func (opts *TerragruntOptions) FindRetryPolicy(command string, stdout string, stderr string) *RetryPolicy {
+	for _, commandSpecific := range []bool{true, false} {
+		for i := range opts.RetryPolicies {
+			policy := &opts.RetryPolicies[i]
+			if (len(policy.Commands) > 0) != commandSpecific || !policy.AppliesToCommand(command) {
+				continue
+			}
+			// When -json is enabled, Terraform will send all output, errors included, to stdout.
+			if policy.Matches(stderr, stdout) {
+				return policy
+			}
+		}
+	}
+
+	if policy := opts.DefaultRetryPolicy(); policy.Matches(stderr, stdout) {
+		return policy
+	}
+	return nil
+}
@@ -27,6 +27,10 @@ const (
 	// no limits on parallelism by default (limited by GOPROCS)
 	DefaultParallelism = math.MaxInt32
 
+	// The default number of dependency outputs of a module that are fetched concurrently, each of which may run
+	// terraform init and output.
+	DefaultDependencyFetchParallelism = 10
+
 	// TofuDefaultPath command to run tofu
 	TofuDefaultPath = "tofu"
 
@@ -36,10 +40,20 @@ const (
 	// Default to naming it `terragrunt_rendered.json` in the terragrunt config directory.
 	DefaultJSONOutName = "terragrunt_rendered.json"
 
+	// Default to rendering the config as HCL in the render command.
+	DefaultRenderFormat = "hcl"
+
 	DefaultTFDataDir = ".terraform"
 
 	DefaultIAMAssumeRoleDuration = 3600
 
+	// LogFormatText is the default, human-readable log format.
+	LogFormatText = "text"
+
+	// LogFormatJSONStream wraps every line of terraform output in a JSON record (see util.JSONStreamRecord) and
+	// switches the terragrunt logs to JSON as well.
+	LogFormatJSONStream = "json-stream"
+
 	minCommandLength = 2
 )
 
@@ -100,6 +114,154 @@ type TerragruntOptions struct {
 	// Whether we should automatically run terraform with -auto-apply in run-all mode.
 	RunAllAutoApprove bool
 
+	// Addresses of the agents that run-all dispatches the modules to. The modules are run locally if empty.
+	Agents []string
+
+	// The address the agent command listens on
+	AgentListenAddress string
+
+	// The token shared between the run-all scheduler and the agents
+	AgentToken string
+
+	// The TLS certificate and key the agent serves with, or the scheduler presents to the agents
+	AgentTLSCert string
+	AgentTLSKey  string
+
+	// The CA the agent verifies the certificates of the schedulers with, or the scheduler verifies the agents with
+	AgentTLSCA string
+
+	// Whether run-all should only print the order in which the modules would be processed, without running them.
+	RunAllPreviewOrder bool
+
+	// Whether the run command should run the terraform command in each module of the stack, like run-all.
+	RunAll bool
+
+	// Whether the run command should run the terraform command in the module of the working directory and in the
+	// modules that depend on it, directly or transitively.
+	RunGraph bool
+
+	// The directory in which the run command looks for the modules that depend on the module of the working directory
+	// with --graph. Defaults to the root of the git repository of the working directory.
+	GraphRoot string
+
+	// The path of a module for which graph-dependencies should list the modules that depend on it, instead of
+	// printing the whole graph.
+	GraphDependentsOf string
+
+	// The CI system, github or gitlab, for which output-module-groups should output the groups as a matrix.
+	ModuleGroupsMatrixFormat string
+
+	// Whether run-all plan should print a roll-up of the changes planned in every module.
+	PlanSummary bool
+
+	// The path of the file run-all plan should write the roll-up of the planned changes to, as JSON.
+	PlanSummaryJSONFile string
+
+	// The format run-all output prints the outputs of the modules in. With json, the outputs of all the modules are
+	// printed as a single JSON document keyed by module path, rather than one after the other.
+	RunAllOutputFormat string
+
+	// The engine estimating the monthly cost of the plans of the cost command, one of infracost or infracost-api
+	CostEngine string
+
+	// The URL of the API the infracost-api cost engine posts the plans to
+	CostAPIURL string
+
+	// The format of the report of the cost command, one of text, json or html
+	CostFormat string
+
+	// The directory the docs command writes the documentation of the units to
+	DocsOutDir string
+
+	// The format the list command writes the units in, one of text or json
+	ListFormat string
+
+	// The tags the units listed by the list command must all have
+	ListTags []string
+
+	// The text the terraform source of the units listed by the list command must contain
+	ListSource string
+
+	// The path of the unit the units listed by the list command must depend on, directly or not
+	ListDependsOn string
+
+	// Whether the outputs of the dependencies of the modules of run-all plan are read from the values planned for the
+	// dependencies in the same run, rather than from their state, so that the plan reflects the changes to the
+	// dependencies that are not applied yet.
+	DependencyPlannedOutputs bool
+
+	// Whether run-all destroy should proceed even if modules outside of the stack depend on modules being destroyed.
+	IgnoreExternalDependents bool
+
+	// The paths of the files with the remote_state blocks of the backends `state migrate` should migrate the state of
+	// the modules from and to.
+	StateMigrateFrom string
+	StateMigrateTo   string
+
+	// Whether the `state` subcommands, such as `state migrate`, should only print the changes they would make, without
+	// making them.
+	StateDryRun bool
+
+	// The names of the DynamoDB lock tables `state remove-lock-table` should delete.
+	StateRemoveLockTables []string
+
+	// The format `state inventory` prints the inventory of the backends of the modules in, json or csv.
+	StateInventoryFormat string
+
+	// Whether `backend bootstrap` and `backend delete` should handle the backends of all the modules in the current
+	// directory tree, rather than the backend of the module in the working dir.
+	BackendAll bool
+
+	// Whether `dependency gen-mocks` writes the generated mock_outputs to the dependency block of the config, rather than
+	// printing them.
+	DependencyGenMocksWrite bool
+
+	// Whether `dependency discover` adds the discovered dependencies to the dependencies block of the config, rather
+	// than printing them.
+	DependencyDiscoverWrite bool
+
+	// Whether to warn about the modules whose state is read by the terraform_remote_state data sources of the module,
+	// but that are not declared as its dependencies.
+	CheckRemoteStateDependencies bool
+
+	// Whether to analyze all the dependency cycles of the graph when one is found, and suggest the dependencies to remove
+	// to break them.
+	BreakCycleHints bool
+
+	// The default maximum amount of time the commands run for a module may take, as a duration string such as "30m".
+	// The timeout attribute of the terraform block takes precedence.
+	ModuleTimeout string
+
+	// The time by which the commands run for the module must finish. Commands that are still running at that time are
+	// killed. Zero means no deadline.
+	Deadline time.Time
+
+	// Whether the commands are started in a process group of their own, which is killed as a whole when the deadline
+	// passes, so that the processes they started are killed along with them.
+	KillProcessGroup bool
+
+	// Whether plan and apply should be skipped for modules whose content did not change since their last successful
+	// apply.
+	SkipUnchanged bool
+
+	// The environment whose overlay config files, such as terragrunt.prod.hcl for the prod environment, are deep merged
+	// over the config files next to them. Empty means no overlay is used.
+	ConfigEnv string
+
+	// The values of the feature flags passed in on the command line, which override the defaults of the feature blocks
+	// with the same names.
+	FeatureFlags map[string]string
+
+	// The values of the variables declared with variable blocks passed in on the command line.
+	TerragruntVars map[string]string
+
+	// The files, in HCL or JSON, that set the values of the variables declared with variable blocks.
+	TerragruntVarFiles []string
+
+	// Whether unknown blocks and attributes, and deprecated ones, are errors in all the config files that are parsed,
+	// even partially.
+	StrictConfig bool
+
 	// CLI args that are intended for Terraform (i.e. all the CLI args except the --terragrunt ones)
 	TerraformCliArgs []string
 
@@ -118,9 +280,24 @@ type TerragruntOptions struct {
 	// Raw log level value
 	LogLevelStr string
 
+	// Format of the logs and terraform output, one of LogFormatText or LogFormatJSONStream
+	LogFormat string
+
 	// ValidateStrict mode for the validate-inputs command
 	ValidateStrict bool
 
+	// The path of a file to write the report of the validate-inputs command to as JSON
+	ValidateInputsJSONFile string
+
+	// Fix the inputs of terragrunt.hcl that the validate-inputs command finds misaligned
+	ValidateInputsFix bool
+
+	// The path of a CSV or JSON file listing the resources for the import-gen command to import
+	ImportManifest string
+
+	// Run terraform import for the resources of the import-gen command, instead of writing import blocks
+	ImportRun bool
+
 	// Environment variables at runtime
 	Env map[string]string
 
@@ -145,12 +322,23 @@ type TerragruntOptions struct {
 	// IAM Role options that should be used when authenticating to AWS.
 	IAMRoleOptions IAMRoleOptions
 
+	// The secret the sessions of the IAM roles assumed with MFA are encrypted with when they are cached on disk. The
+	// sessions are only cached in memory if it is empty.
+	MFASessionCacheKey string
+
 	// If set to true, continue running *-all commands even if a dependency has errors. This is mostly useful for 'output-all <some_variable>'. See https://github.com/gruntwork-io/terragrunt/issues/193
 	IgnoreDependencyErrors bool
 
 	// If set to true, ignore the dependency order when running *-all command.
 	IgnoreDependencyOrder bool
 
+	// Whether run-all should report a summary of succeeded, failed and skipped modules, and only report the errors of
+	// the modules that failed themselves
+	ContinueOnError bool
+
+	// The path of the file run-all should write the summary of ContinueOnError to, as JSON, instead of stderr.
+	RunSummaryJSONFile string
+
 	// If set to true, skip any external dependencies when running *-all commands
 	IgnoreExternalDependencies bool
 
@@ -179,6 +367,10 @@ type TerragruntOptions struct {
 	// RetryableErrors is an array of regular expressions with RE2 syntax (https://github.com/google/re2/wiki/Syntax) that qualify for retrying
 	RetryableErrors []string
 
+	// RetryPolicies configured through retry blocks. They are checked before the default policy built from
+	// RetryableErrors, RetryMaxAttempts and RetrySleepIntervalSec.
+	RetryPolicies []RetryPolicy
+
 	// Unix-style glob of directories to exclude when running *-all commands
 	ExcludeDirs []string
 
@@ -197,6 +389,9 @@ type TerragruntOptions struct {
 	// Show diff, by default it's disabled.
 	Diff bool
 
+	// The path of a patch file to write the diffs of hclfmt to, which can be applied with `patch -p1`.
+	DiffOut string
+
 	// The file which hclfmt should be specifically run on
 	HclFile string
 
@@ -207,6 +402,10 @@ type TerragruntOptions struct {
 	// in this list.
 	ModulesThatInclude []string
 
+	// When used with `run-all`, restrict the modules in the stack to those affected by the files changed since this git
+	// ref, and the modules that depend on them.
+	ChangedSince string
+
 	// A command that can be used to run Terragrunt with the given options. This is useful for running Terragrunt
 	// multiple times (e.g. when spinning up a stack of Terraform modules). The actual command is normally defined
 	// in the cli package, which depends on almost all other packages, so we declare it here so that other
@@ -228,12 +427,50 @@ type TerragruntOptions struct {
 	// This is an experimental feature, used to speed up dependency processing by getting the output from the state
 	FetchDependencyOutputFromState bool
 
+	// The maximum number of dependency outputs of a module to fetch concurrently
+	DependencyFetchParallelism int
+
+	// The directory to cache the outputs of the dependencies in, by the version of their state, across terragrunt
+	// processes. The outputs are not cached on disk if empty.
+	DependencyOutputCacheDir string
+
+	// The maximum age of the cached outputs of the dependencies, as a duration string such as "15m", after which they
+	// are fetched again. The cached outputs don't expire if empty.
+	DependencyOutputCacheTTL string
+
+	// Fail apply and destroy when the mock outputs of a dependency would be used, rather than running with placeholder
+	// data.
+	StrictMockOutputs bool
+
 	// Enables caching of includes during partial parsing operations.
 	UsePartialParseConfigCache bool
 
-	// Include fields metadata in render-json
+	// Enables caching the partial parses of the configs in their .terragrunt-cache folder, so that they are reused
+	// across runs until the configs or their includes change.
+	UsePersistentParseConfigCache bool
+
+	// Include fields metadata in render and render-json
 	RenderJsonWithMetadata bool
 
+	// Include the line and include level each field was defined at in the metadata of render and render-json
+	RenderJsonWithSourcePositions bool
+
+	// The format, hcl, json or yaml, in which the render command renders the config
+	RenderFormat string
+
+	// True if the render command should write the config next to the config of the unit, rather than printing it
+	RenderWrite bool
+
+	// The path, relative to the config of the unit, the render command writes the config to. Implies RenderWrite.
+	RenderOut string
+
+	// True if the render command should read the outputs of the dependencies, rather than using their mock outputs
+	RenderResolveDependencies bool
+
+	// True if the dependency outputs should not be retrieved when parsing the config, in which case the mock outputs
+	// are used if any, and the outputs are unknown otherwise. This is used by hclvalidate, which only checks the config.
+	SkipDependencyOutputs bool
+
 	// Prefix for shell commands' outputs
 	OutputPrefix string
 
@@ -260,6 +497,14 @@ type IAMRoleOptions struct {
 
 	// STS Session name when assuming the role.
 	AssumeRoleSessionName string
+
+	// The serial number, or ARN for a virtual device, of the MFA device required to assume the role. When set, the user
+	// is prompted for the MFA code, and the session is cached so that it is only prompted once.
+	MFASerial string
+
+	// The OIDC token, or the path of a file holding it, to assume the role with web identity federation, e.g. the token
+	// issued by the CI system. When set, the role is assumed with AssumeRoleWithWebIdentity, without AWS credentials.
+	WebIdentityToken string
 }
 
 func MergeIAMRoleOptions(target IAMRoleOptions, source IAMRoleOptions) IAMRoleOptions {
@@ -277,6 +522,14 @@ func MergeIAMRoleOptions(target IAMRoleOptions, source IAMRoleOptions) IAMRoleOp
 		out.AssumeRoleSessionName = source.AssumeRoleSessionName
 	}
 
+	if source.MFASerial != "" {
+		out.MFASerial = source.MFASerial
+	}
+
+	if source.WebIdentityToken != "" {
+		out.WebIdentityToken = source.WebIdentityToken
+	}
+
 	return out
 }
 
@@ -291,10 +544,13 @@ func NewTerragruntOptions() *TerragruntOptions {
 		NonInteractive:                 false,
 		TerraformCliArgs:               []string{},
 		LogLevelStr:                    util.GetDefaultLogLevel().String(),
+		LogFormat:                      LogFormatText,
 		Logger:                         util.GlobalFallbackLogEntry,
 		Env:                            map[string]string{},
 		Source:                         "",
 		SourceMap:                      map[string]string{},
+		FeatureFlags:                   map[string]string{},
+		TerragruntVars:                 map[string]string{},
 		SourceUpdate:                   false,
 		IgnoreDependencyErrors:         false,
 		IgnoreDependencyOrder:          false,
@@ -315,10 +571,12 @@ func NewTerragruntOptions() *TerragruntOptions {
 		Check:                          false,
 		Diff:                           false,
 		FetchDependencyOutputFromState: false,
+		DependencyFetchParallelism:     DefaultDependencyFetchParallelism,
 		UsePartialParseConfigCache:     false,
 		OutputPrefix:                   "",
 		IncludeModulePrefix:            false,
 		JSONOut:                        DefaultJSONOutName,
+		RenderFormat:                   DefaultRenderFormat,
 		TerraformImplementation:        UnknownImpl,
 		RunTerragrunt: func(opts *TerragruntOptions) error {
 			return errors.WithStackTrace(RunTerragruntCommandNotSet)
@@ -402,12 +660,61 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		TerragruntVersion:              opts.TerragruntVersion,
 		AutoInit:                       opts.AutoInit,
 		RunAllAutoApprove:              opts.RunAllAutoApprove,
+		RunAllPreviewOrder:             opts.RunAllPreviewOrder,
+		RunAll:                         opts.RunAll,
+		RunGraph:                       opts.RunGraph,
+		GraphRoot:                      opts.GraphRoot,
+		GraphDependentsOf:              opts.GraphDependentsOf,
+		ModuleGroupsMatrixFormat:       opts.ModuleGroupsMatrixFormat,
+		PlanSummary:                    opts.PlanSummary,
+		PlanSummaryJSONFile:            opts.PlanSummaryJSONFile,
+		RunAllOutputFormat:             opts.RunAllOutputFormat,
+		CostEngine:                     opts.CostEngine,
+		CostAPIURL:                     opts.CostAPIURL,
+		CostFormat:                     opts.CostFormat,
+		DocsOutDir:                     opts.DocsOutDir,
+		ListFormat:                     opts.ListFormat,
+		ListTags:                       util.CloneStringList(opts.ListTags),
+		ListSource:                     opts.ListSource,
+		ListDependsOn:                  opts.ListDependsOn,
+		DependencyPlannedOutputs:       opts.DependencyPlannedOutputs,
+		IgnoreExternalDependents:       opts.IgnoreExternalDependents,
+		StateMigrateFrom:               opts.StateMigrateFrom,
+		StateMigrateTo:                 opts.StateMigrateTo,
+		StateDryRun:                    opts.StateDryRun,
+		StateRemoveLockTables:          util.CloneStringList(opts.StateRemoveLockTables),
+		BackendAll:                     opts.BackendAll,
+		StateInventoryFormat:           opts.StateInventoryFormat,
+		DependencyGenMocksWrite:        opts.DependencyGenMocksWrite,
+		DependencyDiscoverWrite:        opts.DependencyDiscoverWrite,
+		CheckRemoteStateDependencies:   opts.CheckRemoteStateDependencies,
+		BreakCycleHints:                opts.BreakCycleHints,
+		ModuleTimeout:                  opts.ModuleTimeout,
+		Deadline:                       opts.Deadline,
+		KillProcessGroup:               opts.KillProcessGroup,
+		SkipUnchanged:                  opts.SkipUnchanged,
+		ConfigEnv:                      opts.ConfigEnv,
+		FeatureFlags:                   util.CloneStringMap(opts.FeatureFlags),
+		TerragruntVars:                 util.CloneStringMap(opts.TerragruntVars),
+		TerragruntVarFiles:             opts.TerragruntVarFiles,
+		StrictConfig:                   opts.StrictConfig,
+		Agents:                         opts.Agents,
+		AgentListenAddress:             opts.AgentListenAddress,
+		AgentToken:                     opts.AgentToken,
+		AgentTLSCert:                   opts.AgentTLSCert,
+		AgentTLSKey:                    opts.AgentTLSKey,
+		AgentTLSCA:                     opts.AgentTLSCA,
 		NonInteractive:                 opts.NonInteractive,
 		TerraformCliArgs:               util.CloneStringList(opts.TerraformCliArgs),
 		WorkingDir:                     workingDir,
 		Logger:                         util.CreateLogEntryWithWriter(opts.ErrWriter, workingDir, opts.LogLevel, opts.Logger.Logger.Hooks),
 		LogLevel:                       opts.LogLevel,
+		LogFormat:                      opts.LogFormat,
 		ValidateStrict:                 opts.ValidateStrict,
+		ValidateInputsJSONFile:         opts.ValidateInputsJSONFile,
+		ValidateInputsFix:              opts.ValidateInputsFix,
+		ImportManifest:                 opts.ImportManifest,
+		ImportRun:                      opts.ImportRun,
 		Env:                            util.CloneStringMap(opts.Env),
 		Source:                         opts.Source,
 		SourceMap:                      opts.SourceMap,
@@ -416,8 +723,11 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		Debug:                          opts.Debug,
 		OriginalIAMRoleOptions:         opts.OriginalIAMRoleOptions,
 		IAMRoleOptions:                 opts.IAMRoleOptions,
+		MFASessionCacheKey:             opts.MFASessionCacheKey,
 		IgnoreDependencyErrors:         opts.IgnoreDependencyErrors,
 		IgnoreDependencyOrder:          opts.IgnoreDependencyOrder,
+		ContinueOnError:                opts.ContinueOnError,
+		RunSummaryJSONFile:             opts.RunSummaryJSONFile,
 		IgnoreExternalDependencies:     opts.IgnoreExternalDependencies,
 		IncludeExternalDependencies:    opts.IncludeExternalDependencies,
 		Writer:                         opts.Writer,
@@ -427,9 +737,11 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		RetryMaxAttempts:               opts.RetryMaxAttempts,
 		RetrySleepIntervalSec:          opts.RetrySleepIntervalSec,
 		RetryableErrors:                util.CloneStringList(opts.RetryableErrors),
+		RetryPolicies:                  opts.RetryPolicies,
 		ExcludeDirs:                    opts.ExcludeDirs,
 		IncludeDirs:                    opts.IncludeDirs,
 		ModulesThatInclude:             opts.ModulesThatInclude,
+		ChangedSince:                   opts.ChangedSince,
 		Parallelism:                    opts.Parallelism,
 		StrictInclude:                  opts.StrictInclude,
 		RunTerragrunt:                  opts.RunTerragrunt,
@@ -439,7 +751,19 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 		Check:                          opts.Check,
 		CheckDependentModules:          opts.CheckDependentModules,
 		FetchDependencyOutputFromState: opts.FetchDependencyOutputFromState,
+		DependencyFetchParallelism:     opts.DependencyFetchParallelism,
+		DependencyOutputCacheDir:       opts.DependencyOutputCacheDir,
+		DependencyOutputCacheTTL:       opts.DependencyOutputCacheTTL,
+		StrictMockOutputs:              opts.StrictMockOutputs,
 		UsePartialParseConfigCache:     opts.UsePartialParseConfigCache,
+		UsePersistentParseConfigCache:  opts.UsePersistentParseConfigCache,
+		RenderJsonWithMetadata:         opts.RenderJsonWithMetadata,
+		RenderJsonWithSourcePositions:  opts.RenderJsonWithSourcePositions,
+		RenderFormat:                   opts.RenderFormat,
+		RenderWrite:                    opts.RenderWrite,
+		RenderOut:                      opts.RenderOut,
+		RenderResolveDependencies:      opts.RenderResolveDependencies,
+		SkipDependencyOutputs:          opts.SkipDependencyOutputs,
 		OutputPrefix:                   opts.OutputPrefix,
 		IncludeModulePrefix:            opts.IncludeModulePrefix,
 		FailIfBucketCreationRequired:   opts.FailIfBucketCreationRequired,
@@ -448,6 +772,21 @@ func (opts *TerragruntOptions) Clone(terragruntConfigPath string) *TerragruntOpt
 	}
 }
 
+// CloneWithTimeout returns a copy of the options for the commands that must finish within the given timeout, such as
+// a hook. The deadline of the module still applies if it comes first, and the returned boolean is true if the timeout
+// sets the deadline instead.
+func (opts *TerragruntOptions) CloneWithTimeout(timeout time.Duration) (*TerragruntOptions, bool) {
+	cloned := opts.Clone(opts.TerragruntConfigPath)
+	cloned.WorkingDir = opts.WorkingDir
+
+	deadline := time.Now().Add(timeout)
+	if timeout <= 0 || (!opts.Deadline.IsZero() && !deadline.Before(opts.Deadline)) {
+		return cloned, false
+	}
+	cloned.Deadline = deadline
+	return cloned, true
+}
+
 // Check if argument is planfile TODO check file format
 func checkIfPlanFile(arg string) bool {
 	return util.IsFile(arg) && filepath.Ext(arg) == ".tfplan"
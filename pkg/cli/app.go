@@ -108,6 +108,7 @@ func (app *App) Run(arguments []string) error {
 
 		args := Args(parentCtx.Args().Slice())
 		ctx := newContext(parentCtx.Context, app)
+		ctx.rawArgs = args
 
 		if app.Autocomplete {
 			if err := app.setupAutocomplete(args); err != nil {
@@ -115,8 +116,8 @@ func (app *App) Run(arguments []string) error {
 			}
 
 			if compLine := os.Getenv(envCompleteLine); compLine != "" {
-				args = strings.Fields(compLine)
-				if args[0] == app.Name {
+				args = completeLine(compLine, os.Getenv(envCompletePoint))
+				if len(args) > 0 && args[0] == app.Name {
 					args = args[1:]
 				}
 
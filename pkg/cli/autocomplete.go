@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
 	"github.com/gruntwork-io/go-commons/errors"
@@ -17,7 +19,8 @@ const (
 	defaultAutocompleteInstallFlag   = "install-autocomplete"
 	defaultAutocompleteUninstallFlag = "uninstall-autocomplete"
 
-	envCompleteLine = "COMP_LINE"
+	envCompleteLine  = "COMP_LINE"
+	envCompletePoint = "COMP_POINT"
 
 	maxDashesInFlag = 2
 )
@@ -63,6 +66,62 @@ func ShowCompletions(ctx *Context) error {
 	return DefaultComplete(ctx)
 }
 
+// completeFlagValue completes the last of the given args as the value of the flag before it, if that flag is one of the
+// given flags that take a value, by printing the values the flag completes. Returns false if it is not.
+func completeFlagValue(ctx *Context, flags Flags, args []string) (bool, error) {
+	if len(args) < 2 {
+		return false, nil
+	}
+
+	arg, prev := args[len(args)-1], args[len(args)-2]
+	if !strings.HasPrefix(prev, "-") || strings.Contains(prev, "=") || strings.HasPrefix(arg, "-") {
+		return false, nil
+	}
+
+	name := strings.TrimLeft(prev, "-")
+	for _, flag := range flags {
+		completable, ok := flag.(CompletableFlag)
+		if !ok || !flagHasName(flag, name) {
+			continue
+		}
+
+		for _, value := range completable.CompleteValue(ctx) {
+			if strings.HasPrefix(value, arg) {
+				fmt.Fprintln(ctx.App.Writer, value)
+			}
+		}
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func flagHasName(flag Flag, name string) bool {
+	for _, flagName := range flag.Names() {
+		if flagName == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// completeLine returns the words of the given command line, up to the given cursor position, if any. When the line
+// ends with a space, the last word is empty, as the word being completed hasn't been started yet.
+func completeLine(line, point string) []string {
+	if pos, err := strconv.Atoi(point); err == nil && pos >= 0 && pos < len(line) {
+		line = line[:pos]
+	}
+
+	words := strings.Fields(line)
+	if len(words) > 0 && strings.TrimRightFunc(line, unicode.IsSpace) != line {
+		words = append(words, "")
+	}
+
+	return words
+}
+
 func defaultComplete(ctx *Context) error {
 	arg := ctx.Args().Last()
 
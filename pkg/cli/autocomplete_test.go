@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompleteLine(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		line     string
+		point    string
+		expected []string
+	}{
+		{"terragrunt run-all pl", "", []string{"terragrunt", "run-all", "pl"}},
+		{"terragrunt list --tag ", "", []string{"terragrunt", "list", "--tag", ""}},
+		{"terragrunt list --tag net --format json", "25", []string{"terragrunt", "list", "--tag", "net"}},
+		{"terragrunt ", "11", []string{"terragrunt", ""}},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, completeLine(testCase.line, testCase.point), testCase.line)
+	}
+}
+
+func TestCompleteFlagValue(t *testing.T) {
+	t.Parallel()
+
+	var tags []string
+	var format string
+	cmd := &Command{
+		Name: "list",
+		Flags: Flags{
+			&SliceFlag[string]{
+				Name:        "tag",
+				Destination: &tags,
+				Complete:    func(ctx *Context) []string { return []string{"app", "network", "prod"} },
+			},
+			&GenericFlag[string]{
+				Name:        "format",
+				Destination: &format,
+			},
+		},
+		Subcommands: Commands{{Name: "sub"}},
+	}
+
+	testCases := []struct {
+		args     []string
+		expected string
+	}{
+		{[]string{"-tag", ""}, "app\nnetwork\nprod\n"},
+		{[]string{"-tag", "app", "-tag", "n"}, "network\n"},
+		// The value of the format flag is being typed, but the flag doesn't complete its values.
+		{[]string{"-format", ""}, ""},
+		{[]string{"-format", "json", ""}, "sub\n"},
+	}
+
+	for _, testCase := range testCases {
+		output := &bytes.Buffer{}
+		app := NewApp()
+		app.Writer = output
+		ctx := newContext(context.Background(), app)
+		ctx.shellComplete = true
+
+		require.NoError(t, cmd.Run(ctx, testCase.args))
+		assert.Equal(t, testCase.expected, output.String(), testCase.args)
+	}
+}
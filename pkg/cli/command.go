@@ -94,6 +94,12 @@ func (cmd Command) VisibleSubcommands() []*cli.Command {
 // Run parses the given args for the presence of flags as well as subcommands.
 // If this is the final command, starts its execution.
 func (cmd *Command) Run(ctx *Context, args Args) (err error) {
+	if ctx.shellComplete {
+		if ok, err := completeFlagValue(ctx, cmd.Flags, args.Slice()); ok {
+			return err
+		}
+	}
+
 	args, err = cmd.parseFlags(args.Slice())
 	if err != nil {
 		return err
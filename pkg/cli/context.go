@@ -10,6 +10,7 @@ type Context struct {
 	*App
 	Command       *Command
 	args          Args
+	rawArgs       Args
 	shellComplete bool
 }
 
@@ -27,6 +28,7 @@ func (ctx *Context) Clone(command *Command, args Args) *Context {
 		shellComplete: ctx.shellComplete,
 		Command:       command,
 		args:          args,
+		rawArgs:       ctx.rawArgs,
 	}
 }
 
@@ -43,3 +45,8 @@ func (ctx *Context) Value(key any) any {
 func (ctx *Context) Args() Args {
 	return ctx.args
 }
+
+// RawArgs returns the command line arguments of the app as they were given, before they were normalized and parsed.
+func (ctx *Context) RawArgs() Args {
+	return ctx.rawArgs
+}
@@ -19,6 +19,12 @@ type ActionableFlag interface {
 	RunAction(*Context) error
 }
 
+// CompletableFlag is an interface that wraps Flag interface and CompleteValue operation.
+type CompletableFlag interface {
+	Flag
+	CompleteValue(*Context) []string
+}
+
 type FlagType[T any] interface {
 	libflag.Getter
 	Clone(dest *T) FlagType[T]
@@ -3,6 +3,9 @@ package cli
 // CompleteFunc is an action to execute when the shell completion flag is set
 type CompleteFunc func(*Context) error
 
+// FlagCompleteFunc returns the values a flag can take, to complete the value of the flag in the shell.
+type FlagCompleteFunc func(*Context) []string
+
 // ActionFunc is the action to execute when no commands/subcommands are specified.
 type ActionFunc func(*Context) error
 
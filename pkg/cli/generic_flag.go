@@ -28,6 +28,8 @@ type GenericFlag[T GenericType] struct {
 	EnvVar string
 	// The action to execute when flag is specified
 	Action ActionFunc
+	// The func that returns the values of the flag to complete in the shell
+	Complete FlagCompleteFunc
 	// The pointer to which the value of the flag or env var is assigned.
 	// It also uses as the default value displayed in the help.
 	Destination *T
@@ -89,6 +91,15 @@ func (flag *GenericFlag[T]) RunAction(ctx *Context) error {
 	return nil
 }
 
+// CompleteValue implements CompletableFlag.CompleteValue
+func (flag *GenericFlag[T]) CompleteValue(ctx *Context) []string {
+	if flag.Complete != nil {
+		return flag.Complete(ctx)
+	}
+
+	return nil
+}
+
 // -- generic Value
 type genericValue[T comparable] struct {
 	value       FlagType[T]
@@ -31,6 +31,8 @@ type SliceFlag[T SliceFlagType] struct {
 	EnvVar string
 	// The action to execute when flag is specified
 	Action ActionFunc
+	// The func that returns the values of the flag to complete in the shell
+	Complete FlagCompleteFunc
 	// The pointer to which the value of the flag or env var is assigned.
 	// It also uses as the default value displayed in the help.
 	Destination *[]T
@@ -103,6 +105,15 @@ func (flag *SliceFlag[T]) RunAction(ctx *Context) error {
 	return nil
 }
 
+// CompleteValue implements CompletableFlag.CompleteValue
+func (flag *SliceFlag[T]) CompleteValue(ctx *Context) []string {
+	if flag.Complete != nil {
+		return flag.Complete(ctx)
+	}
+
+	return nil
+}
+
 // -- slice Value
 type sliceValue[T comparable] struct {
 	values      *[]T
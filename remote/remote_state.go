@@ -18,10 +18,14 @@ type RemoteState struct {
 	DisableDependencyOptimization bool
 	Generate                      *RemoteStateGenerate
 	Config                        map[string]interface{}
+	// The OpenTofu state encryption config, which is generated along with the backend
+	Encryption map[string]interface{}
+	// Where to copy the state to after every successful apply
+	Snapshot *RemoteStateSnapshot
 }
 
 func (remoteState *RemoteState) String() string {
-	return fmt.Sprintf("RemoteState{Backend = %v, DisableInit = %v, DisableDependencyOptimization = %v, Generate = %v, Config = %v}", remoteState.Backend, remoteState.DisableInit, remoteState.DisableDependencyOptimization, remoteState.Generate, remoteState.Config)
+	return fmt.Sprintf("RemoteState{Backend = %v, DisableInit = %v, DisableDependencyOptimization = %v, Generate = %v, Config = %v, Encryption = %v, Snapshot = %v}", remoteState.Backend, remoteState.DisableInit, remoteState.DisableDependencyOptimization, remoteState.Generate, remoteState.Config, remoteState.Encryption, remoteState.Snapshot)
 }
 
 // Code gen configuration for Terraform remote state
@@ -44,8 +48,28 @@ type RemoteStateInitializer interface {
 
 // TODO: initialization actions for other remote state backends can be added here
 var remoteStateInitializers = map[string]RemoteStateInitializer{
-	"s3":  S3Initializer{},
-	"gcs": GCSInitializer{},
+	"s3":      S3Initializer{},
+	"gcs":     GCSInitializer{},
+	"azurerm": AzureRMInitializer{},
+	"http":    HTTPInitializer{},
+	"remote":  TFCInitializer{},
+	"cloud":   TFCInitializer{},
+	"consul":  ConsulInitializer{},
+	"etcdv3":  EtcdInitializer{},
+}
+
+// RemoteStateDeleter deletes the resources of a remote state backend, such as the bucket the state is stored in, for
+// `terragrunt backend delete`.
+type RemoteStateDeleter interface {
+	// Delete the resources the state of the remote state is stored in, along with all the states they hold
+	Delete(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error
+}
+
+// The backends whose resources terragrunt can delete. They are the ones terragrunt creates the resources of.
+var remoteStateDeleters = map[string]RemoteStateDeleter{
+	"s3":      S3Initializer{},
+	"gcs":     GCSInitializer{},
+	"azurerm": AzureRMInitializer{},
 }
 
 // Fill in any default configuration for remote state
@@ -59,6 +83,29 @@ func (remoteState *RemoteState) Validate() error {
 		return errors.WithStackTrace(ErrRemoteBackendMissing)
 	}
 
+	// The cloud block is not a backend, so it can't be configured with -backend-config.
+	if remoteState.Backend == codegen.BackendCloud && remoteState.Generate == nil {
+		return errors.WithStackTrace(ErrCloudWithoutGenerate)
+	}
+
+	if len(remoteState.Encryption) > 0 {
+		// The encryption can only be configured in code, so without the generated backend the state would silently
+		// be stored unencrypted.
+		if remoteState.Generate == nil {
+			return errors.WithStackTrace(ErrEncryptionWithoutGenerate)
+		}
+
+		if err := codegen.ValidateEncryptionConfig(remoteState.Encryption); err != nil {
+			return err
+		}
+	}
+
+	if remoteState.Snapshot != nil {
+		if err := remoteState.Snapshot.Validate(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -74,6 +121,24 @@ func (remoteState *RemoteState) Initialize(terragruntOptions *options.Terragrunt
 	return nil
 }
 
+// Delete the resources the state of the remote state is stored in, such as the S3 bucket and the DynamoDB lock table,
+// along with all the states they hold.
+func (remoteState *RemoteState) Delete(terragruntOptions *options.TerragruntOptions) error {
+	deleter, hasDeleter := remoteStateDeleters[remoteState.Backend]
+	if !hasDeleter {
+		return errors.WithStackTrace(BackendDeletionNotSupported(remoteState.Backend))
+	}
+
+	terragruntOptions.Logger.Debugf("Deleting the remote state resources of the %s backend", remoteState.Backend)
+	return deleter.Delete(remoteState, terragruntOptions)
+}
+
+// CanDelete returns true if terragrunt can delete the resources of the backend of the remote state.
+func (remoteState *RemoteState) CanDelete() bool {
+	_, hasDeleter := remoteStateDeleters[remoteState.Backend]
+	return hasDeleter
+}
+
 // Returns true if remote state needs to be configured. This will be the case when:
 //
 // 1. Remote state has not already been configured
@@ -164,6 +229,10 @@ func (remoteState RemoteState) ToTerraformInitArgs() []string {
 	var backendConfigArgs []string = nil
 
 	for key, value := range config {
+		// Terraform parses the values of the non-string attributes, such as the headers of the http backend, as HCL.
+		if mapVal, ok := value.(map[string]interface{}); ok {
+			value = wrapMapToSingleLineHcl(mapVal)
+		}
 		arg := fmt.Sprintf("-backend-config=%s=%v", key, value)
 		backendConfigArgs = append(backendConfigArgs, arg)
 	}
@@ -190,7 +259,11 @@ func (remoteState *RemoteState) GenerateTerraformCode(terragruntOptions *options
 		return err
 	}
 
-	configBytes, err := codegen.RemoteStateConfigToTerraformCode(remoteState.Backend, config)
+	if len(remoteState.Encryption) > 0 && terragruntOptions.TerraformImplementation == options.TerraformImpl {
+		return errors.WithStackTrace(ErrEncryptionNotSupported)
+	}
+
+	configBytes, err := codegen.RemoteStateConfigToTerraformCode(remoteState.Backend, config, remoteState.Encryption)
 	if err != nil {
 		return err
 	}
@@ -208,8 +281,17 @@ func (remoteState *RemoteState) GenerateTerraformCode(terragruntOptions *options
 var (
 	ErrRemoteBackendMissing             = fmt.Errorf("the remote_state.backend field cannot be empty")
 	ErrGenerateCalledWithNoGenerateAttr = fmt.Errorf("generate code routine called when no generate attribute is configured")
+	ErrEncryptionWithoutGenerate        = fmt.Errorf("the remote_state.encryption field requires the remote_state.generate field to be set, so that the encryption can be generated along with the backend")
+	ErrCloudWithoutGenerate             = fmt.Errorf("the cloud backend requires the remote_state.generate field to be set, so that the cloud block can be generated")
+	ErrEncryptionNotSupported           = fmt.Errorf("the remote_state.encryption field configures the OpenTofu state encryption, which is not supported by Terraform")
 )
 
+type BackendDeletionNotSupported string
+
+func (backend BackendDeletionNotSupported) Error() string {
+	return fmt.Sprintf("Terragrunt can not delete the resources of the %s backend", string(backend))
+}
+
 type BucketCreationNotAllowed string
 
 func (bucketName BucketCreationNotAllowed) Error() string {
@@ -0,0 +1,68 @@
+package remote
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+)
+
+// The role to access the state backend with, which is distinct from the IAM role terragrunt runs terraform with, so
+// that the state can be stored in a central account while the resources are deployed in the accounts of the
+// environments. The role is assumed with the credentials of the IAM role of terragrunt, if any.
+type RemoteStateAssumeRole struct {
+	RoleArn     string `mapstructure:"role_arn"`
+	SessionName string `mapstructure:"session_name"`
+	ExternalID  string `mapstructure:"external_id"`
+	Duration    string `mapstructure:"duration"`
+}
+
+// ApplyToBackendConfig sets the role in the given backend config, as the assume_role block of the s3 backend, which is
+// used both by terraform and by terragrunt to create and check the bucket and the lock table. Unlike the role_arn of
+// the backend config, terragrunt assumes this role with the credentials of its IAM role, as terraform does.
+func (assumeRole *RemoteStateAssumeRole) ApplyToBackendConfig(backend string, config map[string]interface{}) error {
+	if backend != "s3" {
+		return errors.WithStackTrace(InvalidRemoteStateAssumeRole{Reason: fmt.Sprintf("the %s backend does not support assuming a role", backend)})
+	}
+
+	if assumeRole.RoleArn == "" {
+		return errors.WithStackTrace(InvalidRemoteStateAssumeRole{Reason: "role_arn is required"})
+	}
+
+	for _, key := range []string{"assume_role", "role_arn"} {
+		if _, isSet := config[key]; isSet {
+			return errors.WithStackTrace(InvalidRemoteStateAssumeRole{Reason: fmt.Sprintf("the role is already set with %s in config", key)})
+		}
+	}
+
+	if assumeRole.Duration != "" {
+		if _, err := time.ParseDuration(assumeRole.Duration); err != nil {
+			return errors.WithStackTrace(InvalidRemoteStateAssumeRole{Reason: fmt.Sprintf("duration must be a duration such as 1h, got %q", assumeRole.Duration)})
+		}
+	}
+
+	backendAssumeRole := map[string]interface{}{"role_arn": assumeRole.RoleArn}
+	if assumeRole.SessionName != "" {
+		backendAssumeRole["session_name"] = assumeRole.SessionName
+	}
+	if assumeRole.ExternalID != "" {
+		backendAssumeRole["external_id"] = assumeRole.ExternalID
+	}
+	if assumeRole.Duration != "" {
+		backendAssumeRole["duration"] = assumeRole.Duration
+	}
+	config["assume_role"] = backendAssumeRole
+	config["assume_role_with_iam_role"] = true
+
+	return nil
+}
+
+// Custom error types
+
+type InvalidRemoteStateAssumeRole struct {
+	Reason string
+}
+
+func (err InvalidRemoteStateAssumeRole) Error() string {
+	return fmt.Sprintf("Invalid remote_state assume_role: %s", err.Reason)
+}
@@ -0,0 +1,77 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoteStateAssumeRoleApplyToBackendConfig(t *testing.T) {
+	t.Parallel()
+
+	assumeRole := &RemoteStateAssumeRole{RoleArn: "arn:aws:iam::111111111111:role/state", SessionName: "terragrunt", Duration: "1h"}
+
+	config := map[string]interface{}{"bucket": "my-state"}
+	require.NoError(t, assumeRole.ApplyToBackendConfig("s3", config))
+	assert.Equal(t, map[string]interface{}{
+		"bucket":                    "my-state",
+		"assume_role":               map[string]interface{}{"role_arn": "arn:aws:iam::111111111111:role/state", "session_name": "terragrunt", "duration": "1h"},
+		"assume_role_with_iam_role": true,
+	}, config)
+
+	// The role is used by terragrunt itself to access the bucket and the lock table.
+	s3Config, err := ParseExtendedS3Config(config)
+	require.NoError(t, err)
+	assert.Equal(t, "arn:aws:iam::111111111111:role/state", s3Config.GetAwsSessionConfig().RoleArn)
+	assert.Equal(t, "terragrunt", s3Config.GetAwsSessionConfig().SessionName)
+	assert.True(t, s3Config.GetAwsSessionConfig().AssumeRoleWithIAMRole)
+
+	// The marker is only used by terragrunt and not passed to terraform.
+	initArgs := S3Initializer{}.GetTerraformInitArgs(config)
+	assert.NotContains(t, initArgs, "assume_role_with_iam_role")
+}
+
+func TestRemoteStateConfigRoleArnIsNotAssumedWithIAMRole(t *testing.T) {
+	t.Parallel()
+
+	// The role_arn and assume_role of the backend config keep replacing the IAM role of terragrunt.
+	for _, config := range []map[string]interface{}{
+		{"bucket": "my-state", "role_arn": "arn:aws:iam::111111111111:role/state"},
+		{"bucket": "my-state", "assume_role": map[string]interface{}{"role_arn": "arn:aws:iam::111111111111:role/state"}},
+	} {
+		s3Config, err := ParseExtendedS3Config(config)
+		require.NoError(t, err)
+		assert.Equal(t, "arn:aws:iam::111111111111:role/state", s3Config.GetAwsSessionConfig().RoleArn)
+		assert.False(t, s3Config.GetAwsSessionConfig().AssumeRoleWithIAMRole)
+	}
+}
+
+func TestRemoteStateAssumeRoleApplyToBackendConfigInvalid(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name       string
+		backend    string
+		assumeRole RemoteStateAssumeRole
+		config     map[string]interface{}
+	}{
+		{"unsupported-backend", "gcs", RemoteStateAssumeRole{RoleArn: "arn:aws:iam::111111111111:role/state"}, map[string]interface{}{}},
+		{"missing-role-arn", "s3", RemoteStateAssumeRole{SessionName: "terragrunt"}, map[string]interface{}{}},
+		{"role-in-config", "s3", RemoteStateAssumeRole{RoleArn: "arn:aws:iam::111111111111:role/state"}, map[string]interface{}{"role_arn": "arn:aws:iam::222222222222:role/state"}},
+		{"invalid-duration", "s3", RemoteStateAssumeRole{RoleArn: "arn:aws:iam::111111111111:role/state", Duration: "3600"}, map[string]interface{}{}},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := testCase.assumeRole.ApplyToBackendConfig(testCase.backend, testCase.config)
+			assert.IsType(t, InvalidRemoteStateAssumeRole{}, errors.Unwrap(err))
+		})
+	}
+}
@@ -0,0 +1,709 @@
+package remote
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2020-10-01/resources"
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+/*
+ * We use this construct to separate the config keys used to create the storage account and container from the
+ * others, as they are specific to the azurerm backend, but only used by terragrunt in case it has to create them.
+ */
+type ExtendedRemoteStateConfigAzureRM struct {
+	remoteStateConfigAzureRM RemoteStateConfigAzureRM
+
+	Location                       string            `mapstructure:"location"`
+	SkipStorageAccountCreation     bool              `mapstructure:"skip_storage_account_creation"`
+	SkipContainerCreation          bool              `mapstructure:"skip_container_creation"`
+	SkipBlobVersioning             bool              `mapstructure:"skip_blob_versioning"`
+	StorageAccountSku              string            `mapstructure:"storage_account_sku"`
+	StorageAccountTags             map[string]string `mapstructure:"storage_account_tags"`
+	StorageAccountAllowedIPRanges  []string          `mapstructure:"storage_account_allowed_ip_ranges"`
+	StorageAccountAllowedSubnetIDs []string          `mapstructure:"storage_account_allowed_subnet_ids"`
+}
+
+// These are settings that can appear in the remote_state config that are ONLY used by Terragrunt and NOT forwarded
+// to the underlying Terraform backend configuration.
+var terragruntAzureRMOnlyConfigs = []string{
+	"location",
+	"skip_storage_account_creation",
+	"skip_container_creation",
+	"skip_blob_versioning",
+	"storage_account_sku",
+	"storage_account_tags",
+	"storage_account_allowed_ip_ranges",
+	"storage_account_allowed_subnet_ids",
+}
+
+// A representation of the configuration options of the azurerm backend that terragrunt uses to create the storage
+// account and container
+type RemoteStateConfigAzureRM struct {
+	StorageAccountName string `mapstructure:"storage_account_name"`
+	ContainerName      string `mapstructure:"container_name"`
+	Key                string `mapstructure:"key"`
+	ResourceGroupName  string `mapstructure:"resource_group_name"`
+	SubscriptionID     string `mapstructure:"subscription_id"`
+	TenantID           string `mapstructure:"tenant_id"`
+	ClientID           string `mapstructure:"client_id"`
+	ClientSecret       string `mapstructure:"client_secret"`
+	UseMSI             bool   `mapstructure:"use_msi"`
+	AccessKey          string `mapstructure:"access_key"`
+	SASToken           string `mapstructure:"sas_token"`
+	UseAzureADAuth     bool   `mapstructure:"use_azuread_auth"`
+	UseOIDC            bool   `mapstructure:"use_oidc"`
+}
+
+// The SKU of the storage accounts created when storage_account_sku is not set
+const defaultAzureRMStorageAccountSku = storage.SkuNameStandardLRS
+
+type AzureRMInitializer struct{}
+
+// Returns true if:
+//
+// 1. Any of the existing backend settings are different than the current config
+// 2. The configured storage account or container does not exist
+func (azureRMInitializer AzureRMInitializer) NeedsInitialization(remoteState *RemoteState, existingBackend *TerraformBackend, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	if remoteState.DisableInit {
+		return false, nil
+	}
+
+	if !azureRMConfigValuesEqual(remoteState.Config, existingBackend, terragruntOptions) {
+		return true, nil
+	}
+
+	azureRMConfig, err := parseExtendedAzureRMConfig(remoteState.Config, terragruntOptions)
+	if err != nil {
+		return false, err
+	}
+
+	if azureRMConfig.skipResourceManagement() {
+		return false, nil
+	}
+
+	clients, err := createAzureRMClients(azureRMConfig)
+	if err != nil {
+		return false, err
+	}
+
+	missing, err := findMissingAzureRMResources(clients, azureRMConfig)
+	if err != nil {
+		return false, err
+	}
+
+	return missing.any(), nil
+}
+
+// Return true if the given config is in any way different than what is configured for the backend
+func azureRMConfigValuesEqual(config map[string]interface{}, existingBackend *TerraformBackend, terragruntOptions *options.TerragruntOptions) bool {
+	if existingBackend == nil {
+		return len(config) == 0
+	}
+
+	if existingBackend.Type != "azurerm" {
+		terragruntOptions.Logger.Debugf("Backend type has changed from azurerm to %s", existingBackend.Type)
+		return false
+	}
+
+	if len(config) == 0 && len(existingBackend.Config) == 0 {
+		return true
+	}
+
+	// If other keys in config are bools, DeepEqual also will consider the maps to be different.
+	for key, value := range existingBackend.Config {
+		if util.KindOf(existingBackend.Config[key]) == reflect.String && util.KindOf(config[key]) == reflect.Bool {
+			if convertedValue, err := strconv.ParseBool(value.(string)); err == nil {
+				existingBackend.Config[key] = convertedValue
+			}
+		}
+	}
+
+	// Construct a new map excluding the configs that are only used in Terragrunt config and not in Terraform's backend
+	comparisonConfig := make(map[string]interface{})
+	for key, value := range config {
+		comparisonConfig[key] = value
+	}
+
+	for _, key := range terragruntAzureRMOnlyConfigs {
+		delete(comparisonConfig, key)
+	}
+
+	if !terraformStateConfigEqual(existingBackend.Config, comparisonConfig) {
+		terragruntOptions.Logger.Debugf("Backend config changed from %s to %s", existingBackend.Config, config)
+		return false
+	}
+
+	return true
+}
+
+// Initialize the remote state storage account and container specified in the given config. This function will
+// validate the config parameters and, if the resource group, storage account or container don't already exist, create
+// them, with versioning enabled on the storage account.
+func (azureRMInitializer AzureRMInitializer) Initialize(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	azureRMConfig, err := parseExtendedAzureRMConfig(remoteState.Config, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := validateAzureRMConfig(azureRMConfig); err != nil {
+		return err
+	}
+
+	if azureRMConfig.skipResourceManagement() {
+		if !azureRMConfig.SkipStorageAccountCreation || !azureRMConfig.SkipContainerCreation {
+			terragruntOptions.Logger.Debugf("Not creating the Azure storage account and container of the remote state, as the backend authenticates to the storage account with an access key, a SAS token or OIDC, which can't manage them")
+		}
+		return nil
+	}
+
+	clients, err := createAzureRMClients(azureRMConfig)
+	if err != nil {
+		return err
+	}
+
+	return createAzureRMResourcesIfNecessary(clients, azureRMConfig, terragruntOptions)
+}
+
+// Delete the container specified in the given config, with all the blobs in it. The storage account and the resource
+// group are kept, as they usually hold other resources.
+func (azureRMInitializer AzureRMInitializer) Delete(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	azureRMConfig, err := parseExtendedAzureRMConfig(remoteState.Config, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := validateAzureRMConfig(azureRMConfig); err != nil {
+		return err
+	}
+
+	var config = azureRMConfig.remoteStateConfigAzureRM
+
+	clients, err := createAzureRMClients(azureRMConfig)
+	if err != nil {
+		return err
+	}
+
+	terragruntOptions.Logger.Infof("Deleting Azure storage container %s in storage account %s", config.ContainerName, config.StorageAccountName)
+	response, err := clients.containers.Delete(context.Background(), config.ResourceGroupName, config.StorageAccountName, config.ContainerName)
+	if err != nil {
+		if response.StatusCode == http.StatusNotFound {
+			terragruntOptions.Logger.Debugf("Remote state Azure storage container %s does not exist", config.ContainerName)
+			return nil
+		}
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
+func (azureRMInitializer AzureRMInitializer) GetTerraformInitArgs(config map[string]interface{}) map[string]interface{} {
+	var filteredConfig = make(map[string]interface{})
+
+	for key, val := range config {
+		if util.ListContainsElement(terragruntAzureRMOnlyConfigs, key) {
+			continue
+		}
+
+		filteredConfig[key] = val
+	}
+
+	return filteredConfig
+}
+
+// Parse the given map into an azurerm config. As with the azurerm backend, the credentials that are not set in the
+// config are read from the ARM_* env vars.
+func parseExtendedAzureRMConfig(config map[string]interface{}, terragruntOptions *options.TerragruntOptions) (*ExtendedRemoteStateConfigAzureRM, error) {
+	var azureRMConfig RemoteStateConfigAzureRM
+	var extendedConfig ExtendedRemoteStateConfigAzureRM
+
+	if err := mapstructure.Decode(config, &azureRMConfig); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	if err := mapstructure.Decode(config, &extendedConfig); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	envDefaults := map[*string]string{
+		&azureRMConfig.SubscriptionID: "ARM_SUBSCRIPTION_ID",
+		&azureRMConfig.TenantID:       "ARM_TENANT_ID",
+		&azureRMConfig.ClientID:       "ARM_CLIENT_ID",
+		&azureRMConfig.ClientSecret:   "ARM_CLIENT_SECRET",
+		&azureRMConfig.AccessKey:      "ARM_ACCESS_KEY",
+		&azureRMConfig.SASToken:       "ARM_SAS_TOKEN",
+	}
+	for value, envVar := range envDefaults {
+		if *value == "" {
+			*value = terragruntOptions.Env[envVar]
+		}
+	}
+	if !azureRMConfig.UseMSI {
+		azureRMConfig.UseMSI, _ = strconv.ParseBool(terragruntOptions.Env["ARM_USE_MSI"])
+	}
+	if !azureRMConfig.UseAzureADAuth {
+		azureRMConfig.UseAzureADAuth, _ = strconv.ParseBool(terragruntOptions.Env["ARM_USE_AZUREAD"])
+	}
+	if !azureRMConfig.UseOIDC {
+		azureRMConfig.UseOIDC, _ = strconv.ParseBool(terragruntOptions.Env["ARM_USE_OIDC"])
+	}
+
+	extendedConfig.remoteStateConfigAzureRM = azureRMConfig
+
+	return &extendedConfig, nil
+}
+
+// Validate all the parameters of the given azurerm remote state configuration
+func validateAzureRMConfig(extendedConfig *ExtendedRemoteStateConfigAzureRM) error {
+	var config = extendedConfig.remoteStateConfigAzureRM
+
+	if config.StorageAccountName == "" {
+		return errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("storage_account_name"))
+	}
+
+	if config.ContainerName == "" {
+		return errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("container_name"))
+	}
+
+	if config.Key == "" {
+		return errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("key"))
+	}
+
+	// The resources are looked up and created with the Azure Resource Manager API, which needs to know where they are.
+	if !extendedConfig.skipResourceManagement() {
+		if config.ResourceGroupName == "" {
+			return errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("resource_group_name"))
+		}
+
+		if config.SubscriptionID == "" {
+			return errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("subscription_id"))
+		}
+	}
+
+	return nil
+}
+
+// skipResourceManagement returns true if the storage account and container must not be looked up and created with the
+// Azure Resource Manager API: either the config skips their creation, or the backend authenticates with an access key,
+// a SAS token or OIDC, which only grant access to the storage account, so that the credentials terragrunt would use for
+// the Azure Resource Manager API are usually missing or not allowed to manage the resources.
+func (extendedConfig *ExtendedRemoteStateConfigAzureRM) skipResourceManagement() bool {
+	if extendedConfig.SkipStorageAccountCreation && extendedConfig.SkipContainerCreation {
+		return true
+	}
+	config := extendedConfig.remoteStateConfigAzureRM
+	return config.AccessKey != "" || config.SASToken != "" || config.UseOIDC
+}
+
+// azureRMClients are the clients of the Azure Resource Manager API used to look up and create the remote state
+// resources.
+type azureRMClients struct {
+	groups       resources.GroupsClient
+	accounts     storage.AccountsClient
+	blobServices storage.BlobServicesClient
+	containers   storage.BlobContainersClient
+}
+
+// Create the clients of the Azure Resource Manager API for the subscription of the given config. They authenticate
+// with the client secret or the managed identity of the config, if any, or else with the Azure CLI, as the azurerm
+// backend does.
+func createAzureRMClients(extendedConfig *ExtendedRemoteStateConfigAzureRM) (*azureRMClients, error) {
+	var config = extendedConfig.remoteStateConfigAzureRM
+
+	authorizer, err := newAzureRMAuthorizer(&config, "")
+	if err != nil {
+		return nil, err
+	}
+
+	clients := &azureRMClients{
+		groups:       resources.NewGroupsClient(config.SubscriptionID),
+		accounts:     storage.NewAccountsClient(config.SubscriptionID),
+		blobServices: storage.NewBlobServicesClient(config.SubscriptionID),
+		containers:   storage.NewBlobContainersClient(config.SubscriptionID),
+	}
+	clients.groups.Authorizer = authorizer
+	clients.accounts.Authorizer = authorizer
+	clients.blobServices.Authorizer = authorizer
+	clients.containers.Authorizer = authorizer
+
+	return clients, nil
+}
+
+// newAzureRMAuthorizer returns an authorizer for the given resource, such as the Azure Storage data plane, that
+// authenticates with the client secret or the managed identity of the config, if any, or else with the Azure CLI. The
+// authorizer is for the Azure Resource Manager API if the resource is empty.
+func newAzureRMAuthorizer(config *RemoteStateConfigAzureRM, resource string) (autorest.Authorizer, error) {
+	var authorizer autorest.Authorizer
+	var err error
+	switch {
+	case config.ClientID != "" && config.ClientSecret != "" && config.TenantID != "":
+		clientCredentialsConfig := auth.NewClientCredentialsConfig(config.ClientID, config.ClientSecret, config.TenantID)
+		if resource != "" {
+			clientCredentialsConfig.Resource = resource
+		}
+		authorizer, err = clientCredentialsConfig.Authorizer()
+	case config.UseMSI:
+		msiConfig := auth.NewMSIConfig()
+		msiConfig.ClientID = config.ClientID
+		if resource != "" {
+			msiConfig.Resource = resource
+		}
+		authorizer, err = msiConfig.Authorizer()
+	case resource != "":
+		authorizer, err = auth.NewAuthorizerFromCLIWithResource(resource)
+	default:
+		authorizer, err = auth.NewAuthorizerFromCLI()
+	}
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	return authorizer, nil
+}
+
+// missingAzureRMResources records which of the remote state resources don't exist yet.
+type missingAzureRMResources struct {
+	resourceGroup  bool
+	storageAccount bool
+	container      bool
+}
+
+func (missing missingAzureRMResources) any() bool {
+	return missing.resourceGroup || missing.storageAccount || missing.container
+}
+
+// Returns a description of the missing resources, such as "storage account foo and container bar", for the prompt.
+func (missing missingAzureRMResources) describe(config *RemoteStateConfigAzureRM) string {
+	descriptions := []string{}
+	if missing.resourceGroup {
+		descriptions = append(descriptions, fmt.Sprintf("resource group %s", config.ResourceGroupName))
+	}
+	if missing.storageAccount {
+		descriptions = append(descriptions, fmt.Sprintf("storage account %s", config.StorageAccountName))
+	}
+	if missing.container {
+		descriptions = append(descriptions, fmt.Sprintf("container %s", config.ContainerName))
+	}
+	return strings.Join(descriptions, " and ")
+}
+
+// Look up which of the resource group, storage account and container of the given config don't exist. The resources
+// whose creation is skipped are assumed to exist.
+func findMissingAzureRMResources(clients *azureRMClients, extendedConfig *ExtendedRemoteStateConfigAzureRM) (missingAzureRMResources, error) {
+	var config = extendedConfig.remoteStateConfigAzureRM
+	var missing missingAzureRMResources
+	ctx := context.Background()
+
+	if !extendedConfig.SkipStorageAccountCreation {
+		groupResponse, err := clients.groups.CheckExistence(ctx, config.ResourceGroupName)
+		if err != nil && groupResponse.StatusCode != http.StatusNotFound {
+			return missing, errors.WithStackTrace(err)
+		}
+		missing.resourceGroup = groupResponse.StatusCode == http.StatusNotFound
+
+		if missing.resourceGroup {
+			missing.storageAccount = true
+		} else {
+			account, err := clients.accounts.GetProperties(ctx, config.ResourceGroupName, config.StorageAccountName, "")
+			if err != nil && account.StatusCode != http.StatusNotFound {
+				return missing, errors.WithStackTrace(err)
+			}
+			missing.storageAccount = account.StatusCode == http.StatusNotFound
+		}
+	}
+
+	if !extendedConfig.SkipContainerCreation {
+		if missing.storageAccount {
+			missing.container = true
+		} else {
+			container, err := clients.containers.Get(ctx, config.ResourceGroupName, config.StorageAccountName, config.ContainerName)
+			if err != nil && container.StatusCode != http.StatusNotFound {
+				return missing, errors.WithStackTrace(err)
+			}
+			missing.container = container.StatusCode == http.StatusNotFound
+		}
+	}
+
+	return missing, nil
+}
+
+// If the resource group, storage account or container specified in the given config don't already exist, prompt the
+// user to create them, and if the user confirms, create them.
+func createAzureRMResourcesIfNecessary(clients *azureRMClients, extendedConfig *ExtendedRemoteStateConfigAzureRM, terragruntOptions *options.TerragruntOptions) error {
+	var config = extendedConfig.remoteStateConfigAzureRM
+
+	missing, err := findMissingAzureRMResources(clients, extendedConfig)
+	if err != nil {
+		return err
+	}
+
+	if !missing.any() {
+		return nil
+	}
+
+	terragruntOptions.Logger.Debugf("Remote state Azure %s does not exist. Attempting to create it", missing.describe(&config))
+
+	// A location must be specified in order for terragrunt to automatically create a storage account.
+	if (missing.resourceGroup || missing.storageAccount) && extendedConfig.Location == "" {
+		return errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("location"))
+	}
+
+	if terragruntOptions.FailIfBucketCreationRequired {
+		return BucketCreationNotAllowed(config.StorageAccountName)
+	}
+
+	prompt := fmt.Sprintf("Remote state Azure %s does not exist or you don't have permissions to access it. Would you like Terragrunt to create it?", missing.describe(&config))
+	shouldCreate, err := shell.PromptUserForYesNo(prompt, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if !shouldCreate {
+		return nil
+	}
+
+	ctx := context.Background()
+
+	if missing.resourceGroup {
+		terragruntOptions.Logger.Debugf("Creating Azure resource group %s in location %s", config.ResourceGroupName, extendedConfig.Location)
+		group := resources.Group{Location: &extendedConfig.Location}
+		if _, err := clients.groups.CreateOrUpdate(ctx, config.ResourceGroupName, group); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	if missing.storageAccount {
+		if err := CreateAzureRMStorageAccount(clients, extendedConfig, terragruntOptions); err != nil {
+			return err
+		}
+	}
+
+	if missing.container {
+		terragruntOptions.Logger.Debugf("Creating Azure storage container %s in storage account %s", config.ContainerName, config.StorageAccountName)
+		if _, err := clients.containers.Create(ctx, config.ResourceGroupName, config.StorageAccountName, config.ContainerName, storage.BlobContainer{}); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	return nil
+}
+
+// CreateAzureRMStorageAccount creates the storage account specified in the given config, waits until it is created, and
+// enables versioning for its blobs.
+func CreateAzureRMStorageAccount(clients *azureRMClients, extendedConfig *ExtendedRemoteStateConfigAzureRM, terragruntOptions *options.TerragruntOptions) error {
+	var config = extendedConfig.remoteStateConfigAzureRM
+	ctx := context.Background()
+
+	terragruntOptions.Logger.Debugf("Creating Azure storage account %s in resource group %s", config.StorageAccountName, config.ResourceGroupName)
+	future, err := clients.accounts.Create(ctx, config.ResourceGroupName, config.StorageAccountName, azureRMStorageAccountParameters(extendedConfig))
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	terragruntOptions.Logger.Debugf("Waiting for storage account %s to be created", config.StorageAccountName)
+	if err := future.WaitForCompletionRef(ctx, clients.accounts.Client); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if extendedConfig.SkipBlobVersioning {
+		terragruntOptions.Logger.Debugf("Versioning is disabled for the remote state storage account %s using 'skip_blob_versioning' config.", config.StorageAccountName)
+		return nil
+	}
+
+	terragruntOptions.Logger.Debugf("Enabling blob versioning on storage account %s", config.StorageAccountName)
+	versioningEnabled := true
+	properties := storage.BlobServiceProperties{
+		BlobServicePropertiesProperties: &storage.BlobServicePropertiesProperties{IsVersioningEnabled: &versioningEnabled},
+	}
+	if _, err := clients.blobServices.SetServiceProperties(ctx, config.ResourceGroupName, config.StorageAccountName, properties); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Returns the parameters of the storage account to create for the given config. The storage account only allows
+// HTTPS with TLS 1.2 and no public access to its blobs, as the state may contain secrets.
+func azureRMStorageAccountParameters(extendedConfig *ExtendedRemoteStateConfigAzureRM) storage.AccountCreateParameters {
+	sku := defaultAzureRMStorageAccountSku
+	if extendedConfig.StorageAccountSku != "" {
+		sku = storage.SkuName(extendedConfig.StorageAccountSku)
+	}
+
+	tags := map[string]*string{}
+	for key, value := range extendedConfig.StorageAccountTags {
+		value := value
+		tags[key] = &value
+	}
+
+	httpsOnly := true
+	allowBlobPublicAccess := false
+	location := extendedConfig.Location
+
+	return storage.AccountCreateParameters{
+		Sku:      &storage.Sku{Name: sku},
+		Kind:     storage.KindStorageV2,
+		Location: &location,
+		Tags:     tags,
+		AccountPropertiesCreateParameters: &storage.AccountPropertiesCreateParameters{
+			EnableHTTPSTrafficOnly: &httpsOnly,
+			MinimumTLSVersion:      storage.MinimumTLSVersionTLS12,
+			AllowBlobPublicAccess:  &allowBlobPublicAccess,
+			NetworkRuleSet:         azureRMNetworkRuleSet(extendedConfig),
+		},
+	}
+}
+
+// Returns the network rules of the storage account to create for the given config, which deny the access from
+// anywhere but the allowed IP ranges and subnets, and the trusted Azure services, if any is set.
+func azureRMNetworkRuleSet(extendedConfig *ExtendedRemoteStateConfigAzureRM) *storage.NetworkRuleSet {
+	if len(extendedConfig.StorageAccountAllowedIPRanges) == 0 && len(extendedConfig.StorageAccountAllowedSubnetIDs) == 0 {
+		return nil
+	}
+
+	ipRules := []storage.IPRule{}
+	for _, ipRange := range extendedConfig.StorageAccountAllowedIPRanges {
+		ipRange := ipRange
+		ipRules = append(ipRules, storage.IPRule{IPAddressOrRange: &ipRange, Action: storage.ActionAllow})
+	}
+
+	virtualNetworkRules := []storage.VirtualNetworkRule{}
+	for _, subnetID := range extendedConfig.StorageAccountAllowedSubnetIDs {
+		subnetID := subnetID
+		virtualNetworkRules = append(virtualNetworkRules, storage.VirtualNetworkRule{VirtualNetworkResourceID: &subnetID, Action: storage.ActionAllow})
+	}
+
+	return &storage.NetworkRuleSet{
+		Bypass:              storage.BypassAzureServices,
+		DefaultAction:       storage.DefaultActionDeny,
+		IPRules:             &ipRules,
+		VirtualNetworkRules: &virtualNetworkRules,
+	}
+}
+
+// The resource of the Azure Storage data plane, to authenticate with Azure AD to read the blobs.
+const azureStorageResource = "https://storage.azure.com/"
+
+// The version of the Azure Storage REST API used to read the state blob.
+const azureStorageAPIVersion = "2020-10-02"
+
+// ReadAzureRMState returns the contents of the state of the default workspace of the given azurerm remote state
+// config, which is the blob at key in the container. As with the azurerm backend, the blob is read with the access_key
+// or the sas_token of the config, with Azure AD if use_azuread_auth is set, or else with an access key of the storage
+// account looked up with the Azure Resource Manager API.
+func ReadAzureRMState(config map[string]interface{}, terragruntOptions *options.TerragruntOptions) ([]byte, error) {
+	extendedConfig, err := parseExtendedAzureRMConfig(config, terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+	azureRMConfig := extendedConfig.remoteStateConfigAzureRM
+
+	for name, value := range map[string]string{"storage_account_name": azureRMConfig.StorageAccountName, "container_name": azureRMConfig.ContainerName, "key": azureRMConfig.Key} {
+		if value == "" {
+			return nil, errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig(name))
+		}
+	}
+
+	authorizer, err := newAzureRMStorageAuthorizer(extendedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	blobURL := fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", azureRMConfig.StorageAccountName, azureRMConfig.ContainerName, azureRMConfig.Key)
+	request, err := autorest.Prepare(
+		&http.Request{},
+		autorest.AsGet(),
+		autorest.WithBaseURL(blobURL),
+		autorest.WithHeader("x-ms-version", azureStorageAPIVersion),
+		authorizer.WithAuthorization(),
+	)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	terragruntOptions.Logger.Debugf("Reading the state from %s", blobURL)
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer response.Body.Close()
+
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if response.StatusCode != http.StatusOK {
+		return nil, errors.WithStackTrace(fmt.Errorf("reading the state blob %s failed with status code %d: %s", blobURL, response.StatusCode, strings.TrimSpace(string(body))))
+	}
+
+	return body, nil
+}
+
+// newAzureRMStorageAuthorizer returns the authorizer to read the state blob of the given config with.
+func newAzureRMStorageAuthorizer(extendedConfig *ExtendedRemoteStateConfigAzureRM) (autorest.Authorizer, error) {
+	config := extendedConfig.remoteStateConfigAzureRM
+
+	switch {
+	case config.AccessKey != "":
+		return sharedKeyAuthorizer(config.StorageAccountName, config.AccessKey)
+	case config.SASToken != "":
+		authorizer, err := autorest.NewSASTokenAuthorizer(config.SASToken)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		return authorizer, nil
+	case config.UseAzureADAuth:
+		return newAzureRMAuthorizer(&config, azureStorageResource)
+	}
+
+	if config.ResourceGroupName == "" {
+		return nil, errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("resource_group_name"))
+	}
+	if config.SubscriptionID == "" {
+		return nil, errors.WithStackTrace(MissingRequiredAzureRMRemoteStateConfig("subscription_id"))
+	}
+
+	clients, err := createAzureRMClients(extendedConfig)
+	if err != nil {
+		return nil, err
+	}
+
+	keys, err := clients.accounts.ListKeys(context.Background(), config.ResourceGroupName, config.StorageAccountName, "")
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	if keys.Keys == nil || len(*keys.Keys) == 0 || (*keys.Keys)[0].Value == nil {
+		return nil, errors.WithStackTrace(fmt.Errorf("no access key found for storage account %s", config.StorageAccountName))
+	}
+
+	return sharedKeyAuthorizer(config.StorageAccountName, *(*keys.Keys)[0].Value)
+}
+
+func sharedKeyAuthorizer(accountName string, accountKey string) (autorest.Authorizer, error) {
+	authorizer, err := autorest.NewSharedKeyAuthorizer(accountName, accountKey, autorest.SharedKey)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	return authorizer, nil
+}
+
+// Custom error types
+
+type MissingRequiredAzureRMRemoteStateConfig string
+
+func (configName MissingRequiredAzureRMRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Missing required azurerm remote state configuration %s", string(configName))
+}
@@ -0,0 +1,218 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/services/storage/mgmt/2021-09-01/storage"
+	"github.com/Azure/go-autorest/autorest"
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureRMConfigValuesEqual(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.Nil(t, err, "Unexpected error creating NewTerragruntOptionsForTest: %v", err)
+
+	testCases := []struct {
+		name          string
+		config        map[string]interface{}
+		backend       *TerraformBackend
+		shouldBeEqual bool
+	}{
+		{
+			"equal-both-empty",
+			map[string]interface{}{},
+			&TerraformBackend{Type: "azurerm", Config: map[string]interface{}{}},
+			true,
+		},
+		{
+			"equal-empty-and-nil",
+			map[string]interface{}{},
+			nil,
+			true,
+		},
+		{
+			"equal-one-key",
+			map[string]interface{}{"storage_account_name": "tfstate"},
+			&TerraformBackend{Type: "azurerm", Config: map[string]interface{}{"storage_account_name": "tfstate"}},
+			true,
+		},
+		{
+			"equal-general-bool-handling",
+			map[string]interface{}{"use_msi": true},
+			&TerraformBackend{Type: "azurerm", Config: map[string]interface{}{"use_msi": "true"}},
+			true,
+		},
+		{
+			"terragrunt-only-configs-remain-intact",
+			map[string]interface{}{"storage_account_name": "tfstate", "location": "westeurope", "skip_container_creation": true},
+			&TerraformBackend{Type: "azurerm", Config: map[string]interface{}{"storage_account_name": "tfstate"}},
+			true,
+		},
+		{
+			"unequal-wrong-backend",
+			map[string]interface{}{"storage_account_name": "tfstate"},
+			&TerraformBackend{Type: "gcs", Config: map[string]interface{}{"storage_account_name": "tfstate"}},
+			false,
+		},
+		{
+			"unequal-values",
+			map[string]interface{}{"container_name": "tfstate"},
+			&TerraformBackend{Type: "azurerm", Config: map[string]interface{}{"container_name": "different"}},
+			false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create a copy of the new config
+			config := make(map[string]interface{})
+			for key, value := range testCase.config {
+				config[key] = value
+			}
+
+			actual := azureRMConfigValuesEqual(config, testCase.backend, terragruntOptions)
+			assert.Equal(t, testCase.shouldBeEqual, actual)
+
+			// Ensure the config remains unchanged by the comparison
+			assert.Equal(t, testCase.config, config)
+		})
+	}
+}
+
+func TestParseExtendedAzureRMConfig(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+	terragruntOptions.Env = map[string]string{"ARM_SUBSCRIPTION_ID": "env-subscription", "ARM_TENANT_ID": "env-tenant"}
+
+	config, err := parseExtendedAzureRMConfig(map[string]interface{}{
+		"storage_account_name": "tfstate",
+		"container_name":       "tfstate",
+		"key":                  "prod/terraform.tfstate",
+		"resource_group_name":  "tfstate",
+		"tenant_id":            "config-tenant",
+	}, terragruntOptions)
+	require.NoError(t, err)
+	assert.Equal(t, "env-subscription", config.remoteStateConfigAzureRM.SubscriptionID)
+	assert.Equal(t, "config-tenant", config.remoteStateConfigAzureRM.TenantID)
+	require.NoError(t, validateAzureRMConfig(config))
+
+	config, err = parseExtendedAzureRMConfig(map[string]interface{}{"storage_account_name": "tfstate", "container_name": "tfstate", "key": "terraform.tfstate"}, terragruntOptions)
+	require.NoError(t, err)
+	err = validateAzureRMConfig(config)
+	assert.Equal(t, MissingRequiredAzureRMRemoteStateConfig("resource_group_name"), errors.Unwrap(err))
+
+	// The resource group is only needed to create the storage account and container.
+	config, err = parseExtendedAzureRMConfig(map[string]interface{}{
+		"storage_account_name":          "tfstate",
+		"container_name":                "tfstate",
+		"key":                           "terraform.tfstate",
+		"skip_storage_account_creation": true,
+		"skip_container_creation":       true,
+	}, terragruntOptions)
+	require.NoError(t, err)
+	assert.NoError(t, validateAzureRMConfig(config))
+
+	// The resources are not managed when the backend only has credentials for the storage account.
+	for _, credentials := range []map[string]interface{}{{"access_key": "key"}, {"sas_token": "token"}, {"use_oidc": true}} {
+		remoteStateConfig := map[string]interface{}{"storage_account_name": "tfstate", "container_name": "tfstate", "key": "terraform.tfstate"}
+		for key, value := range credentials {
+			remoteStateConfig[key] = value
+		}
+		config, err = parseExtendedAzureRMConfig(remoteStateConfig, terragruntOptions)
+		require.NoError(t, err)
+		assert.True(t, config.skipResourceManagement(), credentials)
+		assert.NoError(t, validateAzureRMConfig(config), credentials)
+
+		needsInit, err := AzureRMInitializer{}.NeedsInitialization(&RemoteState{Backend: "azurerm", Config: remoteStateConfig}, &TerraformBackend{Type: "azurerm", Config: remoteStateConfig}, terragruntOptions)
+		require.NoError(t, err)
+		assert.False(t, needsInit, credentials)
+	}
+
+	initArgs := AzureRMInitializer{}.GetTerraformInitArgs(map[string]interface{}{"storage_account_name": "tfstate", "location": "westeurope", "storage_account_allowed_ip_ranges": []string{"1.2.3.4"}})
+	assert.Equal(t, map[string]interface{}{"storage_account_name": "tfstate"}, initArgs)
+}
+
+func TestAzureRMStorageAccountParameters(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+
+	config, err := parseExtendedAzureRMConfig(map[string]interface{}{
+		"storage_account_name":               "tfstate",
+		"location":                           "westeurope",
+		"storage_account_sku":                "Standard_GRS",
+		"storage_account_tags":               map[string]string{"team": "infra"},
+		"storage_account_allowed_ip_ranges":  []string{"203.0.113.0/24"},
+		"storage_account_allowed_subnet_ids": []string{"/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/default"},
+	}, terragruntOptions)
+	require.NoError(t, err)
+
+	params := azureRMStorageAccountParameters(config)
+	assert.Equal(t, storage.SkuNameStandardGRS, params.Sku.Name)
+	assert.Equal(t, storage.KindStorageV2, params.Kind)
+	assert.Equal(t, "westeurope", *params.Location)
+	assert.Equal(t, "infra", *params.Tags["team"])
+	assert.True(t, *params.EnableHTTPSTrafficOnly)
+	assert.False(t, *params.AllowBlobPublicAccess)
+	assert.Equal(t, storage.MinimumTLSVersionTLS12, params.MinimumTLSVersion)
+
+	rules := params.NetworkRuleSet
+	require.NotNil(t, rules)
+	assert.Equal(t, storage.DefaultActionDeny, rules.DefaultAction)
+	assert.Equal(t, storage.BypassAzureServices, rules.Bypass)
+	require.Len(t, *rules.IPRules, 1)
+	assert.Equal(t, "203.0.113.0/24", *(*rules.IPRules)[0].IPAddressOrRange)
+	require.Len(t, *rules.VirtualNetworkRules, 1)
+	assert.Equal(t, "/subscriptions/s/resourceGroups/rg/providers/Microsoft.Network/virtualNetworks/vnet/subnets/default", *(*rules.VirtualNetworkRules)[0].VirtualNetworkResourceID)
+
+	// Without network rules, the storage account is left with the default rules of Azure.
+	config, err = parseExtendedAzureRMConfig(map[string]interface{}{"storage_account_name": "tfstate", "location": "westeurope"}, terragruntOptions)
+	require.NoError(t, err)
+	params = azureRMStorageAccountParameters(config)
+	assert.Equal(t, storage.SkuNameStandardLRS, params.Sku.Name)
+	assert.Nil(t, params.NetworkRuleSet)
+}
+
+func TestNewAzureRMStorageAuthorizer(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+	terragruntOptions.Env = map[string]string{"ARM_SAS_TOKEN": "?sv=2020-10-02&sig=secret"}
+
+	config := map[string]interface{}{"storage_account_name": "account", "container_name": "tfstate", "key": "vpc.tfstate"}
+
+	extendedConfig, err := parseExtendedAzureRMConfig(config, terragruntOptions)
+	require.NoError(t, err)
+	authorizer, err := newAzureRMStorageAuthorizer(extendedConfig)
+	require.NoError(t, err)
+	assert.IsType(t, &autorest.SASTokenAuthorizer{}, authorizer)
+
+	config["access_key"] = "c2VjcmV0"
+	extendedConfig, err = parseExtendedAzureRMConfig(config, terragruntOptions)
+	require.NoError(t, err)
+	authorizer, err = newAzureRMStorageAuthorizer(extendedConfig)
+	require.NoError(t, err)
+	assert.IsType(t, &autorest.SharedKeyAuthorizer{}, authorizer)
+
+	// Without credentials, the access key of the storage account is looked up in its resource group.
+	terragruntOptions.Env = map[string]string{}
+	delete(config, "access_key")
+	extendedConfig, err = parseExtendedAzureRMConfig(config, terragruntOptions)
+	require.NoError(t, err)
+	_, err = newAzureRMStorageAuthorizer(extendedConfig)
+	assert.IsType(t, MissingRequiredAzureRMRemoteStateConfig(""), errors.Unwrap(err))
+}
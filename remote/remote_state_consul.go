@@ -0,0 +1,408 @@
+package remote
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const defaultConsulAddress = "127.0.0.1:8500"
+
+// These are settings that can appear in the remote_state config that are ONLY used by Terragrunt and NOT forwarded
+// to the underlying Terraform backend configuration.
+var terragruntConsulOnlyConfigs = []string{
+	"skip_prefix_creation",
+	"acl_policy_name",
+}
+
+// A representation of the configuration options of the consul backend that terragrunt uses to check the connectivity
+// to Consul and create the KV prefix of the state
+type RemoteStateConfigConsul struct {
+	Address     string `mapstructure:"address"`
+	Scheme      string `mapstructure:"scheme"`
+	Path        string `mapstructure:"path"`
+	AccessToken string `mapstructure:"access_token"`
+	Datacenter  string `mapstructure:"datacenter"`
+	HTTPAuth    string `mapstructure:"http_auth"`
+	CAFile      string `mapstructure:"ca_file"`
+	CertFile    string `mapstructure:"cert_file"`
+	KeyFile     string `mapstructure:"key_file"`
+
+	SkipPrefixCreation bool   `mapstructure:"skip_prefix_creation"`
+	ACLPolicyName      string `mapstructure:"acl_policy_name"`
+}
+
+type ConsulInitializer struct{}
+
+// Returns true if any of the backend settings are different than the current config. The prefix is only checked when
+// initializing, so that Consul is not called on every command.
+func (consulInitializer ConsulInitializer) NeedsInitialization(remoteState *RemoteState, existingBackend *TerraformBackend, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	return kvBackendNeedsInitialization(remoteState, consulInitializer.GetTerraformInitArgs(remoteState.Config), existingBackend, terragruntOptions)
+}
+
+// Check that Consul can be reached with the configured token, and create the KV prefix of the state and the ACL policy
+// to access it if they don't already exist.
+func (consulInitializer ConsulInitializer) Initialize(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	consulConfig, err := parseConsulConfig(remoteState.Config, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := validateConsulConfig(consulConfig); err != nil {
+		return err
+	}
+
+	client, err := newConsulClient(consulConfig)
+	if err != nil {
+		return err
+	}
+
+	terragruntOptions.Logger.Debugf("Checking the connectivity to Consul at %s", client.baseURL)
+	if _, err := client.do(http.MethodGet, "/v1/status/leader", nil); err != nil {
+		return errors.WithStackTrace(ConsulUnreachable{Address: client.baseURL, Err: err})
+	}
+
+	if err := createConsulACLPolicyIfNecessary(client, consulConfig, terragruntOptions); err != nil {
+		return err
+	}
+
+	if consulConfig.SkipPrefixCreation {
+		return nil
+	}
+
+	return createConsulPrefixIfNecessary(client, consulConfig, terragruntOptions)
+}
+
+func (consulInitializer ConsulInitializer) GetTerraformInitArgs(config map[string]interface{}) map[string]interface{} {
+	var filteredConfig = make(map[string]interface{})
+
+	for key, val := range config {
+		if util.ListContainsElement(terragruntConsulOnlyConfigs, key) {
+			continue
+		}
+
+		filteredConfig[key] = val
+	}
+
+	return filteredConfig
+}
+
+// Parse the given map into a consul backend config. As with the consul backend, the settings that are not set in the
+// config are read from the CONSUL_* env vars.
+func parseConsulConfig(config map[string]interface{}, terragruntOptions *options.TerragruntOptions) (*RemoteStateConfigConsul, error) {
+	var consulConfig RemoteStateConfigConsul
+
+	if err := mapstructure.Decode(config, &consulConfig); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	envDefaults := map[*string]string{
+		&consulConfig.Address:     "CONSUL_HTTP_ADDR",
+		&consulConfig.AccessToken: "CONSUL_HTTP_TOKEN",
+		&consulConfig.HTTPAuth:    "CONSUL_HTTP_AUTH",
+		&consulConfig.CAFile:      "CONSUL_CACERT",
+		&consulConfig.CertFile:    "CONSUL_CLIENT_CERT",
+		&consulConfig.KeyFile:     "CONSUL_CLIENT_KEY",
+	}
+	for setting, envName := range envDefaults {
+		if *setting == "" {
+			*setting = terragruntOptions.Env[envName]
+		}
+	}
+
+	if consulConfig.Address == "" {
+		consulConfig.Address = defaultConsulAddress
+	}
+
+	// The address may also contain the scheme, as with CONSUL_HTTP_ADDR.
+	if scheme, address, found := strings.Cut(consulConfig.Address, "://"); found {
+		consulConfig.Scheme = scheme
+		consulConfig.Address = address
+	}
+
+	if consulConfig.Scheme == "" {
+		consulConfig.Scheme = "http"
+		if terragruntOptions.Env["CONSUL_HTTP_SSL"] == "true" {
+			consulConfig.Scheme = "https"
+		}
+	}
+
+	return &consulConfig, nil
+}
+
+// Validate all the parameters of the given consul remote state configuration
+func validateConsulConfig(config *RemoteStateConfigConsul) error {
+	if config.Path == "" {
+		return errors.WithStackTrace(MissingRequiredConsulRemoteStateConfig("path"))
+	}
+
+	if config.Scheme != "http" && config.Scheme != "https" {
+		return errors.WithStackTrace(InvalidConsulRemoteStateConfig{Name: "scheme", Reason: fmt.Sprintf("must be http or https, got %q", config.Scheme)})
+	}
+
+	// The client certificate is only used for mutual TLS along with its private key.
+	if (config.CertFile == "") != (config.KeyFile == "") {
+		return errors.WithStackTrace(InvalidConsulRemoteStateConfig{Name: "cert_file", Reason: "cert_file and key_file must be set together"})
+	}
+
+	return nil
+}
+
+// consulPrefix returns the KV prefix the state is stored under, which is the folder of the path of the state, or an
+// empty string if the state is stored at the root of the KV store.
+func consulPrefix(config *RemoteStateConfigConsul) string {
+	prefix := path.Dir(strings.Trim(config.Path, "/"))
+	if prefix == "." {
+		return ""
+	}
+	return prefix + "/"
+}
+
+// createConsulPrefixIfNecessary creates the folder of the state in the KV store, which checks that the token can write
+// to it before the state is written.
+func createConsulPrefixIfNecessary(client *consulClient, config *RemoteStateConfigConsul, terragruntOptions *options.TerragruntOptions) error {
+	prefix := consulPrefix(config)
+	if prefix == "" {
+		return nil
+	}
+
+	statusCode, err := client.do(http.MethodGet, "/v1/kv/"+prefix+"?keys", nil)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusNotFound {
+		terragruntOptions.Logger.Debugf("Consul KV prefix %s already exists", prefix)
+		return nil
+	}
+
+	if terragruntOptions.FailIfBucketCreationRequired {
+		return BucketCreationNotAllowed(prefix)
+	}
+
+	prompt := fmt.Sprintf("Remote state Consul KV prefix %s does not exist or you don't have permissions to access it. Would you like Terragrunt to create it?", prefix)
+	shouldCreate, err := shell.PromptUserForYesNo(prompt, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if !shouldCreate {
+		return nil
+	}
+
+	terragruntOptions.Logger.Debugf("Creating Consul KV prefix %s", prefix)
+	if _, err := client.do(http.MethodPut, "/v1/kv/"+prefix, nil); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// createConsulACLPolicyIfNecessary creates the ACL policy of the state, which allows to read and write the state and
+// its lock, if acl_policy_name is set and the policy doesn't already exist. The policy can then be attached to the
+// tokens that run terraform.
+func createConsulACLPolicyIfNecessary(client *consulClient, config *RemoteStateConfigConsul, terragruntOptions *options.TerragruntOptions) error {
+	if config.ACLPolicyName == "" {
+		return nil
+	}
+
+	statusCode, err := client.do(http.MethodGet, "/v1/acl/policy/name/"+url.PathEscape(config.ACLPolicyName), nil)
+	if err != nil {
+		return err
+	}
+	if statusCode != http.StatusNotFound {
+		terragruntOptions.Logger.Debugf("Consul ACL policy %s already exists", config.ACLPolicyName)
+		return nil
+	}
+
+	policy := map[string]string{
+		"Name":        config.ACLPolicyName,
+		"Description": fmt.Sprintf("Access to the Terraform state at %s, created by Terragrunt", config.Path),
+		"Rules":       consulACLPolicyRules(config),
+	}
+
+	terragruntOptions.Logger.Debugf("Creating Consul ACL policy %s", config.ACLPolicyName)
+	if _, err := client.do(http.MethodPut, "/v1/acl/policy", policy); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// consulACLPolicyRules returns the rules of the ACL policy of the state, which covers the state of all the workspaces,
+// stored at <path>-env:<workspace>, and the sessions of the locks.
+func consulACLPolicyRules(config *RemoteStateConfigConsul) string {
+	return fmt.Sprintf("key_prefix %q {\n  policy = \"write\"\n}\n\nsession_prefix \"\" {\n  policy = \"write\"\n}\n", strings.Trim(config.Path, "/"))
+}
+
+// consulClient is a client of the HTTP API of Consul.
+type consulClient struct {
+	baseURL    string
+	config     *RemoteStateConfigConsul
+	httpClient *http.Client
+}
+
+func newConsulClient(config *RemoteStateConfigConsul) (*consulClient, error) {
+	httpClient, err := newTLSHTTPClient(config.CAFile, config.CertFile, config.KeyFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return &consulClient{baseURL: fmt.Sprintf("%s://%s", config.Scheme, config.Address), config: config, httpClient: httpClient}, nil
+}
+
+// do sends a request to the API with the token of the config. The status code is returned, as a 404 may be expected
+// by the caller, while the other errors are returned as errors.
+func (client *consulClient) do(method string, apiPath string, body interface{}) (int, error) {
+	var requestBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return 0, errors.WithStackTrace(err)
+		}
+		requestBody = bytes.NewReader(bodyBytes)
+	}
+
+	requestURL := client.baseURL + apiPath
+	if client.config.Datacenter != "" {
+		separator := "?"
+		if strings.Contains(apiPath, "?") {
+			separator = "&"
+		}
+		requestURL += separator + url.Values{"dc": {client.config.Datacenter}}.Encode()
+	}
+
+	request, err := http.NewRequest(method, requestURL, requestBody)
+	if err != nil {
+		return 0, errors.WithStackTrace(err)
+	}
+	if client.config.AccessToken != "" {
+		request.Header.Set("X-Consul-Token", client.config.AccessToken)
+	}
+	if username, password, found := strings.Cut(client.config.HTTPAuth, ":"); found {
+		request.SetBasicAuth(username, password)
+	}
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return 0, errors.WithStackTrace(err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode == http.StatusNotFound {
+		return response.StatusCode, nil
+	}
+	if response.StatusCode >= http.StatusBadRequest {
+		responseBody, _ := io.ReadAll(response.Body)
+		return response.StatusCode, errors.WithStackTrace(ConsulRequestFailed{Method: method, Path: apiPath, StatusCode: response.StatusCode, Body: strings.TrimSpace(string(responseBody))})
+	}
+
+	return response.StatusCode, nil
+}
+
+// kvBackendNeedsInitialization returns true if the backend type or any of the given backend settings are different
+// than the current config of the backend, for the key-value backends that terragrunt doesn't create any resource for
+// on every command.
+func kvBackendNeedsInitialization(remoteState *RemoteState, config map[string]interface{}, existingBackend *TerraformBackend, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	if remoteState.DisableInit {
+		return false, nil
+	}
+
+	if existingBackend == nil {
+		return true, nil
+	}
+
+	if existingBackend.Type != remoteState.Backend {
+		terragruntOptions.Logger.Debugf("Backend type has changed from %s to %s", existingBackend.Type, remoteState.Backend)
+		return true, nil
+	}
+
+	if !terraformStateConfigEqual(existingBackend.Config, config) {
+		terragruntOptions.Logger.Debugf("Backend config changed from %s to %s", existingBackend.Config, config)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// newTLSHTTPClient returns an HTTP client that trusts the given CA certificate and authenticates with the given client
+// certificate, if they are set.
+func newTLSHTTPClient(caFile string, certFile string, keyFile string) (*http.Client, error) {
+	if caFile == "" && certFile == "" {
+		return http.DefaultClient, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+
+	if caFile != "" {
+		caCert, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, errors.WithStackTrace(fmt.Errorf("no certificate found in %s", caFile))
+		}
+	}
+
+	if certFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return &http.Client{Transport: &http.Transport{TLSClientConfig: tlsConfig}}, nil
+}
+
+// Custom error types
+
+type MissingRequiredConsulRemoteStateConfig string
+
+func (configName MissingRequiredConsulRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Missing required consul remote state configuration %s", string(configName))
+}
+
+type InvalidConsulRemoteStateConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidConsulRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Invalid consul remote state configuration %s: %s", err.Name, err.Reason)
+}
+
+type ConsulUnreachable struct {
+	Address string
+	Err     error
+}
+
+func (err ConsulUnreachable) Error() string {
+	return fmt.Sprintf("Could not reach Consul at %s: %v", err.Address, err.Err)
+}
+
+type ConsulRequestFailed struct {
+	Method     string
+	Path       string
+	StatusCode int
+	Body       string
+}
+
+func (err ConsulRequestFailed) Error() string {
+	return fmt.Sprintf("Consul API request %s %s failed with status code %d: %s", err.Method, err.Path, err.StatusCode, err.Body)
+}
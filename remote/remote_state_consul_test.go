@@ -0,0 +1,140 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseConsulConfig(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+	terragruntOptions.Env = map[string]string{"CONSUL_HTTP_ADDR": "https://consul.example.com:8501", "CONSUL_HTTP_TOKEN": "secret"}
+
+	consulConfig, err := parseConsulConfig(map[string]interface{}{"path": "terraform/vpc/terraform.tfstate"}, terragruntOptions)
+	require.NoError(t, err)
+
+	assert.Equal(t, "consul.example.com:8501", consulConfig.Address)
+	assert.Equal(t, "https", consulConfig.Scheme)
+	assert.Equal(t, "secret", consulConfig.AccessToken)
+	assert.Equal(t, "terraform/vpc/", consulPrefix(consulConfig))
+
+	terragruntOptions.Env = map[string]string{}
+	consulConfig, err = parseConsulConfig(map[string]interface{}{"path": "terraform.tfstate"}, terragruntOptions)
+	require.NoError(t, err)
+
+	assert.Equal(t, defaultConsulAddress, consulConfig.Address)
+	assert.Equal(t, "http", consulConfig.Scheme)
+	assert.Equal(t, "", consulPrefix(consulConfig))
+}
+
+func TestValidateConsulConfig(t *testing.T) {
+	t.Parallel()
+
+	err := validateConsulConfig(&RemoteStateConfigConsul{Scheme: "http"})
+	assert.IsType(t, MissingRequiredConsulRemoteStateConfig(""), errors.Unwrap(err))
+
+	err = validateConsulConfig(&RemoteStateConfigConsul{Path: "terraform.tfstate", Scheme: "ftp"})
+	assert.IsType(t, InvalidConsulRemoteStateConfig{}, errors.Unwrap(err))
+
+	err = validateConsulConfig(&RemoteStateConfigConsul{Path: "terraform.tfstate", Scheme: "https", CertFile: "client.pem"})
+	assert.IsType(t, InvalidConsulRemoteStateConfig{}, errors.Unwrap(err))
+
+	assert.NoError(t, validateConsulConfig(&RemoteStateConfigConsul{Path: "terraform.tfstate", Scheme: "https"}))
+}
+
+func TestConsulGetTerraformInitArgs(t *testing.T) {
+	t.Parallel()
+
+	initializer := ConsulInitializer{}
+	args := initializer.GetTerraformInitArgs(map[string]interface{}{
+		"path":                 "terraform/vpc/terraform.tfstate",
+		"skip_prefix_creation": true,
+		"acl_policy_name":      "terraform-vpc",
+	})
+
+	assert.Equal(t, map[string]interface{}{"path": "terraform/vpc/terraform.tfstate"}, args)
+}
+
+func TestConsulInitialize(t *testing.T) {
+	t.Parallel()
+
+	var createdPrefix bool
+	var policy map[string]string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("X-Consul-Token"))
+		assert.Equal(t, "dc1", r.URL.Query().Get("dc"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/status/leader":
+			_, _ = w.Write([]byte(`"10.0.0.1:8300"`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/acl/policy/name/terraform-vpc":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/acl/policy":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&policy))
+			_, _ = w.Write([]byte(`{"ID": "policy-1"}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/kv/terraform/vpc/":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/kv/terraform/vpc/":
+			createdPrefix = true
+			_, _ = w.Write([]byte(`true`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+	terragruntOptions.NonInteractive = true
+
+	remoteState := &RemoteState{
+		Backend: "consul",
+		Config: map[string]interface{}{
+			"address":         server.URL,
+			"path":            "terraform/vpc/terraform.tfstate",
+			"access_token":    "secret",
+			"datacenter":      "dc1",
+			"acl_policy_name": "terraform-vpc",
+		},
+	}
+
+	require.NoError(t, ConsulInitializer{}.Initialize(remoteState, terragruntOptions))
+
+	assert.True(t, createdPrefix)
+	assert.Equal(t, "terraform-vpc", policy["Name"])
+	assert.True(t, strings.Contains(policy["Rules"], `key_prefix "terraform/vpc/terraform.tfstate"`))
+}
+
+func TestConsulInitializeFailIfPrefixCreationRequired(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/status/leader" {
+			_, _ = w.Write([]byte(`"10.0.0.1:8300"`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+	terragruntOptions.FailIfBucketCreationRequired = true
+
+	remoteState := &RemoteState{Backend: "consul", Config: map[string]interface{}{"address": server.URL, "path": "terraform/vpc/terraform.tfstate"}}
+
+	err = ConsulInitializer{}.Initialize(remoteState, terragruntOptions)
+	assert.IsType(t, BucketCreationNotAllowed(""), err)
+}
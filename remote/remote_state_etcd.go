@@ -0,0 +1,189 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+// A representation of the configuration options of the etcdv3 backend that terragrunt uses to check the connectivity
+// to the etcd cluster. Note that the etcdv3 backend was removed in Terraform 1.3 and is not available in OpenTofu.
+type RemoteStateConfigEtcd struct {
+	Endpoints  []string `mapstructure:"endpoints"`
+	Prefix     string   `mapstructure:"prefix"`
+	Username   string   `mapstructure:"username"`
+	Password   string   `mapstructure:"password"`
+	CACertPath string   `mapstructure:"cacert_path"`
+	CertPath   string   `mapstructure:"cert_path"`
+	KeyPath    string   `mapstructure:"key_path"`
+}
+
+type EtcdInitializer struct{}
+
+// Returns true if any of the backend settings are different than the current config.
+func (etcdInitializer EtcdInitializer) NeedsInitialization(remoteState *RemoteState, existingBackend *TerraformBackend, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	return kvBackendNeedsInitialization(remoteState, etcdInitializer.GetTerraformInitArgs(remoteState.Config), existingBackend, terragruntOptions)
+}
+
+// Check that the etcd cluster can be reached and that the credentials are valid. As etcd has no folders, there is no
+// prefix to create: the keys of the state are created by terraform under the prefix.
+func (etcdInitializer EtcdInitializer) Initialize(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	etcdConfig, err := parseEtcdConfig(remoteState.Config, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := validateEtcdConfig(etcdConfig); err != nil {
+		return err
+	}
+
+	httpClient, err := newTLSHTTPClient(etcdConfig.CACertPath, etcdConfig.CertPath, etcdConfig.KeyPath)
+	if err != nil {
+		return err
+	}
+
+	// The backend connects to any of the endpoints, so one healthy endpoint is enough.
+	var endpointErrs []string
+	for _, endpoint := range etcdConfig.Endpoints {
+		baseURL := etcdEndpointURL(endpoint, etcdConfig)
+
+		terragruntOptions.Logger.Debugf("Checking the connectivity to etcd at %s", baseURL)
+		if err := checkEtcdEndpoint(httpClient, baseURL, etcdConfig); err != nil {
+			endpointErrs = append(endpointErrs, err.Error())
+			continue
+		}
+
+		return nil
+	}
+
+	return errors.WithStackTrace(EtcdUnreachable{Endpoints: etcdConfig.Endpoints, Errs: endpointErrs})
+}
+
+func (etcdInitializer EtcdInitializer) GetTerraformInitArgs(config map[string]interface{}) map[string]interface{} {
+	return config
+}
+
+// Parse the given map into an etcdv3 backend config. As with the etcdv3 backend, the credentials that are not set in
+// the config are read from the ETCDV3_* env vars.
+func parseEtcdConfig(config map[string]interface{}, terragruntOptions *options.TerragruntOptions) (*RemoteStateConfigEtcd, error) {
+	var etcdConfig RemoteStateConfigEtcd
+
+	if err := mapstructure.Decode(config, &etcdConfig); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	if etcdConfig.Username == "" {
+		etcdConfig.Username = terragruntOptions.Env["ETCDV3_USERNAME"]
+	}
+	if etcdConfig.Password == "" {
+		etcdConfig.Password = terragruntOptions.Env["ETCDV3_PASSWORD"]
+	}
+
+	return &etcdConfig, nil
+}
+
+// Validate all the parameters of the given etcdv3 remote state configuration
+func validateEtcdConfig(config *RemoteStateConfigEtcd) error {
+	if len(config.Endpoints) == 0 {
+		return errors.WithStackTrace(MissingRequiredEtcdRemoteStateConfig("endpoints"))
+	}
+
+	if (config.CertPath == "") != (config.KeyPath == "") {
+		return errors.WithStackTrace(InvalidEtcdRemoteStateConfig{Name: "cert_path", Reason: "cert_path and key_path must be set together"})
+	}
+
+	if config.Password != "" && config.Username == "" {
+		return errors.WithStackTrace(InvalidEtcdRemoteStateConfig{Name: "username", Reason: "username is required when password is set"})
+	}
+
+	return nil
+}
+
+// etcdEndpointURL returns the URL of the given endpoint, which, as with the etcd client, may be set without a scheme.
+func etcdEndpointURL(endpoint string, config *RemoteStateConfigEtcd) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if strings.Contains(endpoint, "://") {
+		return endpoint
+	}
+	if config.CACertPath != "" || config.CertPath != "" {
+		return "https://" + endpoint
+	}
+	return "http://" + endpoint
+}
+
+// checkEtcdEndpoint checks the health of the etcd member at the given URL with the HTTP gateway of etcd, and that the
+// credentials of the config, if any, can be used to authenticate with it.
+func checkEtcdEndpoint(httpClient *http.Client, baseURL string, config *RemoteStateConfigEtcd) error {
+	response, err := httpClient.Get(baseURL + "/health")
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	var health struct {
+		Health string `json:"health"`
+		Reason string `json:"reason"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&health); err != nil {
+		return fmt.Errorf("%s: invalid health response with status code %d", baseURL, response.StatusCode)
+	}
+	if health.Health != "true" {
+		return fmt.Errorf("%s: unhealthy %s", baseURL, health.Reason)
+	}
+
+	if config.Username == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(map[string]string{"name": config.Username, "password": config.Password})
+	if err != nil {
+		return err
+	}
+
+	authResponse, err := httpClient.Post(baseURL+"/v3/auth/authenticate", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer authResponse.Body.Close()
+
+	if authResponse.StatusCode != http.StatusOK {
+		responseBody, _ := io.ReadAll(authResponse.Body)
+		return fmt.Errorf("%s: authentication of %s failed with status code %d: %s", baseURL, config.Username, authResponse.StatusCode, strings.TrimSpace(string(responseBody)))
+	}
+
+	return nil
+}
+
+// Custom error types
+
+type MissingRequiredEtcdRemoteStateConfig string
+
+func (configName MissingRequiredEtcdRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Missing required etcdv3 remote state configuration %s", string(configName))
+}
+
+type InvalidEtcdRemoteStateConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidEtcdRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Invalid etcdv3 remote state configuration %s: %s", err.Name, err.Reason)
+}
+
+type EtcdUnreachable struct {
+	Endpoints []string
+	Errs      []string
+}
+
+func (err EtcdUnreachable) Error() string {
+	return fmt.Sprintf("Could not reach any of the etcd endpoints %s: %s", strings.Join(err.Endpoints, ", "), strings.Join(err.Errs, "; "))
+}
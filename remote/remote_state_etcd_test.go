@@ -0,0 +1,61 @@
+package remote
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateEtcdConfig(t *testing.T) {
+	t.Parallel()
+
+	err := validateEtcdConfig(&RemoteStateConfigEtcd{})
+	assert.IsType(t, MissingRequiredEtcdRemoteStateConfig(""), errors.Unwrap(err))
+
+	err = validateEtcdConfig(&RemoteStateConfigEtcd{Endpoints: []string{"localhost:2379"}, KeyPath: "client-key.pem"})
+	assert.IsType(t, InvalidEtcdRemoteStateConfig{}, errors.Unwrap(err))
+
+	err = validateEtcdConfig(&RemoteStateConfigEtcd{Endpoints: []string{"localhost:2379"}, Password: "secret"})
+	assert.IsType(t, InvalidEtcdRemoteStateConfig{}, errors.Unwrap(err))
+
+	assert.NoError(t, validateEtcdConfig(&RemoteStateConfigEtcd{Endpoints: []string{"localhost:2379"}}))
+}
+
+func TestEtcdInitialize(t *testing.T) {
+	t.Parallel()
+
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"health": "false", "reason": "NOSPACE"}`))
+	}))
+	defer unhealthy.Close()
+
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/health":
+			_, _ = w.Write([]byte(`{"health": "true"}`))
+		case "/v3/auth/authenticate":
+			_, _ = w.Write([]byte(`{"token": "token"}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer healthy.Close()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+	terragruntOptions.Env = map[string]string{"ETCDV3_USERNAME": "terraform", "ETCDV3_PASSWORD": "secret"}
+
+	remoteState := &RemoteState{Backend: "etcdv3", Config: map[string]interface{}{"endpoints": []interface{}{unhealthy.URL, healthy.URL}, "prefix": "terraform/vpc/"}}
+	assert.NoError(t, EtcdInitializer{}.Initialize(remoteState, terragruntOptions))
+
+	remoteState = &RemoteState{Backend: "etcdv3", Config: map[string]interface{}{"endpoints": []interface{}{unhealthy.URL}}}
+	err = EtcdInitializer{}.Initialize(remoteState, terragruntOptions)
+	assert.IsType(t, EtcdUnreachable{}, errors.Unwrap(err))
+}
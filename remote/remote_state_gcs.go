@@ -2,14 +2,18 @@ package remote
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"google.golang.org/api/impersonate"
+	"google.golang.org/api/iterator"
 
 	"cloud.google.com/go/storage"
 	"github.com/gruntwork-io/go-commons/errors"
@@ -31,12 +35,16 @@ import (
 type ExtendedRemoteStateConfigGCS struct {
 	remoteStateConfigGCS RemoteStateConfigGCS
 
-	Project                string            `mapstructure:"project"`
-	Location               string            `mapstructure:"location"`
-	GCSBucketLabels        map[string]string `mapstructure:"gcs_bucket_labels"`
-	SkipBucketVersioning   bool              `mapstructure:"skip_bucket_versioning"`
-	SkipBucketCreation     bool              `mapstructure:"skip_bucket_creation"`
-	EnableBucketPolicyOnly bool              `mapstructure:"enable_bucket_policy_only"`
+	Project                                 string            `mapstructure:"project"`
+	Location                                string            `mapstructure:"location"`
+	GCSBucketLabels                         map[string]string `mapstructure:"gcs_bucket_labels"`
+	SkipBucketVersioning                    bool              `mapstructure:"skip_bucket_versioning"`
+	SkipBucketCreation                      bool              `mapstructure:"skip_bucket_creation"`
+	EnableBucketPolicyOnly                  bool              `mapstructure:"enable_bucket_policy_only"`
+	EnableUniformBucketLevelAccess          bool              `mapstructure:"enable_uniform_bucket_level_access"`
+	GCSBucketKMSKeyName                     string            `mapstructure:"gcs_bucket_kms_key_name"`
+	GCSBucketNoncurrentVersionRetentionDays int               `mapstructure:"gcs_bucket_noncurrent_version_retention_days"`
+	GCSBucketNumNewerVersions               int               `mapstructure:"gcs_bucket_num_newer_versions"`
 }
 
 // These are settings that can appear in the remote_state config that are ONLY used by Terragrunt and NOT forwarded
@@ -48,6 +56,10 @@ var terragruntGCSOnlyConfigs = []string{
 	"skip_bucket_versioning",
 	"skip_bucket_creation",
 	"enable_bucket_policy_only",
+	"enable_uniform_bucket_level_access",
+	"gcs_bucket_kms_key_name",
+	"gcs_bucket_noncurrent_version_retention_days",
+	"gcs_bucket_num_newer_versions",
 }
 
 // A representation of the configuration options available for GCS remote state
@@ -196,6 +208,61 @@ func (gcsInitializer GCSInitializer) Initialize(remoteState *RemoteState, terrag
 	return nil
 }
 
+// Delete the GCS bucket specified in the given config, with all the generations of its objects.
+func (gcsInitializer GCSInitializer) Delete(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	gcsConfigExtended, err := parseExtendedGCSConfig(remoteState.Config)
+	if err != nil {
+		return err
+	}
+
+	if err := validateGCSConfig(gcsConfigExtended); err != nil {
+		return err
+	}
+
+	var gcsConfig = gcsConfigExtended.remoteStateConfigGCS
+
+	gcsClient, err := CreateGCSClient(gcsConfig)
+	if err != nil {
+		return err
+	}
+	defer gcsClient.Close()
+
+	if !DoesGCSBucketExist(gcsClient, &gcsConfig) {
+		terragruntOptions.Logger.Debugf("Remote state GCS bucket %s does not exist", gcsConfig.Bucket)
+		return nil
+	}
+
+	return DeleteGCSBucket(gcsClient, gcsConfig.Bucket, terragruntOptions)
+}
+
+// DeleteGCSBucket deletes all the generations of the objects of the given GCS bucket, then the bucket itself.
+func DeleteGCSBucket(gcsClient *storage.Client, bucketName string, terragruntOptions *options.TerragruntOptions) error {
+	terragruntOptions.Logger.Infof("Deleting GCS bucket %s", bucketName)
+	ctx := context.Background()
+	bucket := gcsClient.Bucket(bucketName)
+
+	it := bucket.Objects(ctx, &storage.Query{Versions: true})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+
+		terragruntOptions.Logger.Debugf("Deleting generation %d of object %s of GCS bucket %s", attrs.Generation, attrs.Name, bucketName)
+		if err := bucket.Object(attrs.Name).Generation(attrs.Generation).Delete(ctx); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	if err := bucket.Delete(ctx); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
 func (gcsInitializer GCSInitializer) GetTerraformInitArgs(config map[string]interface{}) map[string]interface{} {
 	var filteredConfig = make(map[string]interface{})
 
@@ -246,6 +313,14 @@ func validateGCSConfig(extendedConfig *ExtendedRemoteStateConfigGCS) error {
 		return errors.WithStackTrace(MissingRequiredGCSRemoteStateConfig("bucket"))
 	}
 
+	if extendedConfig.GCSBucketNoncurrentVersionRetentionDays < 0 {
+		return errors.WithStackTrace(InvalidGCSRemoteStateConfig{Name: "gcs_bucket_noncurrent_version_retention_days", Reason: "must not be negative"})
+	}
+
+	if extendedConfig.GCSBucketNumNewerVersions < 0 {
+		return errors.WithStackTrace(InvalidGCSRemoteStateConfig{Name: "gcs_bucket_num_newer_versions", Reason: "must not be negative"})
+	}
+
 	return nil
 }
 
@@ -364,6 +439,12 @@ func CreateGCSBucket(gcsClient *storage.Client, config *ExtendedRemoteStateConfi
 	ctx := context.Background()
 	bucket := gcsClient.Bucket(config.remoteStateConfigGCS.Bucket)
 
+	err := bucket.Create(ctx, projectID, gcsBucketAttrs(config, terragruntOptions))
+	return errors.WithStackTrace(err)
+}
+
+// Returns the attributes of the GCS bucket to create for the given config
+func gcsBucketAttrs(config *ExtendedRemoteStateConfigGCS, terragruntOptions *options.TerragruntOptions) *storage.BucketAttrs {
 	bucketAttrs := &storage.BucketAttrs{}
 
 	if config.Location != "" {
@@ -378,13 +459,41 @@ func CreateGCSBucket(gcsClient *storage.Client, config *ExtendedRemoteStateConfi
 		bucketAttrs.VersioningEnabled = true
 	}
 
-	if config.EnableBucketPolicyOnly {
+	// enable_bucket_policy_only is the former name of uniform bucket-level access, so both enable it.
+	if config.EnableBucketPolicyOnly || config.EnableUniformBucketLevelAccess {
 		terragruntOptions.Logger.Debugf("Enabling uniform bucket-level access on GCS bucket %s", config.remoteStateConfigGCS.Bucket)
-		bucketAttrs.BucketPolicyOnly = storage.BucketPolicyOnly{Enabled: true}
+		bucketAttrs.UniformBucketLevelAccess = storage.UniformBucketLevelAccess{Enabled: true}
 	}
 
-	err := bucket.Create(ctx, projectID, bucketAttrs)
-	return errors.WithStackTrace(err)
+	if config.GCSBucketKMSKeyName != "" {
+		terragruntOptions.Logger.Debugf("Encrypting GCS bucket %s with KMS key %s", config.remoteStateConfigGCS.Bucket, config.GCSBucketKMSKeyName)
+		bucketAttrs.Encryption = &storage.BucketEncryption{DefaultKMSKeyName: config.GCSBucketKMSKeyName}
+	}
+
+	// The lifecycle rules only delete the noncurrent versions of the state, never the live one.
+	if config.GCSBucketNoncurrentVersionRetentionDays > 0 {
+		terragruntOptions.Logger.Debugf("Deleting noncurrent versions of GCS bucket %s after %d days", config.remoteStateConfigGCS.Bucket, config.GCSBucketNoncurrentVersionRetentionDays)
+		bucketAttrs.Lifecycle.Rules = append(bucketAttrs.Lifecycle.Rules, storage.LifecycleRule{
+			Action: storage.LifecycleAction{Type: storage.DeleteAction},
+			Condition: storage.LifecycleCondition{
+				Liveness:                storage.Archived,
+				DaysSinceNoncurrentTime: int64(config.GCSBucketNoncurrentVersionRetentionDays),
+			},
+		})
+	}
+
+	if config.GCSBucketNumNewerVersions > 0 {
+		terragruntOptions.Logger.Debugf("Deleting versions of GCS bucket %s with %d newer versions", config.remoteStateConfigGCS.Bucket, config.GCSBucketNumNewerVersions)
+		bucketAttrs.Lifecycle.Rules = append(bucketAttrs.Lifecycle.Rules, storage.LifecycleRule{
+			Action: storage.LifecycleAction{Type: storage.DeleteAction},
+			Condition: storage.LifecycleCondition{
+				Liveness:         storage.Archived,
+				NumNewerVersions: int64(config.GCSBucketNumNewerVersions),
+			},
+		})
+	}
+
+	return bucketAttrs
 }
 
 // GCP is eventually consistent, so after creating a GCS bucket, this method can be used to wait until the information
@@ -494,6 +603,64 @@ func CreateGCSClient(gcsConfigRemote RemoteStateConfigGCS) (*storage.Client, err
 	return client, nil
 }
 
+// ReadGCSState returns the contents of the state of the default workspace of the given gcs remote state config, which
+// is the object <prefix>/default.tfstate in the bucket. The object is decrypted with the encryption_key of the config,
+// if any.
+func ReadGCSState(config map[string]interface{}, terragruntOptions *options.TerragruntOptions) ([]byte, error) {
+	gcsConfig, err := parseGCSConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	if gcsConfig.Bucket == "" {
+		return nil, errors.WithStackTrace(MissingRequiredGCSRemoteStateConfig("bucket"))
+	}
+
+	gcsClient, err := CreateGCSClient(*gcsConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer gcsClient.Close()
+
+	objectName := gcsStateObjectName(gcsConfig.Prefix)
+	object := gcsClient.Bucket(gcsConfig.Bucket).Object(objectName)
+
+	if gcsConfig.EncryptionKey != "" {
+		encryptionKey, err := util.FileOrData(gcsConfig.EncryptionKey)
+		if err != nil {
+			return nil, errors.WithStackTrace(err)
+		}
+		decodedKey, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encryptionKey))
+		if err != nil {
+			return nil, errors.WithStackTrace(InvalidGCSRemoteStateConfig{Name: "encryption_key", Reason: "must be a base64 encoded key"})
+		}
+		object = object.Key(decodedKey)
+	}
+
+	terragruntOptions.Logger.Debugf("Reading the state from gs://%s/%s", gcsConfig.Bucket, objectName)
+	reader, err := object.NewReader(context.Background())
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+	defer reader.Close()
+
+	contents, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	return contents, nil
+}
+
+// gcsStateObjectName returns the name of the object of the state of the default workspace, which the gcs backend
+// stores under the prefix.
+func gcsStateObjectName(prefix string) string {
+	prefix = strings.TrimLeft(prefix, "/")
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return prefix + "default.tfstate"
+}
+
 // Custom error types
 
 type MissingRequiredGCSRemoteStateConfig string
@@ -501,3 +668,12 @@ type MissingRequiredGCSRemoteStateConfig string
 func (configName MissingRequiredGCSRemoteStateConfig) Error() string {
 	return fmt.Sprintf("Missing required GCS remote state configuration %s", string(configName))
 }
+
+type InvalidGCSRemoteStateConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidGCSRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Invalid GCS remote state configuration %s: %s", err.Name, err.Reason)
+}
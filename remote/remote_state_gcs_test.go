@@ -3,6 +3,7 @@ package remote
 import (
 	"testing"
 
+	"cloud.google.com/go/storage"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -127,3 +128,69 @@ func TestGCSConfigValuesEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestGCSBucketAttrs(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+
+	config, err := parseExtendedGCSConfig(map[string]interface{}{
+		"bucket":                             "my-state",
+		"location":                           "europe-west1",
+		"enable_uniform_bucket_level_access": true,
+		"gcs_bucket_kms_key_name":            "projects/p/locations/europe-west1/keyRings/r/cryptoKeys/k",
+		"gcs_bucket_noncurrent_version_retention_days": float64(30),
+		"gcs_bucket_num_newer_versions":                float64(5),
+	})
+	require.NoError(t, err)
+	require.NoError(t, validateGCSConfig(config))
+
+	attrs := gcsBucketAttrs(config, terragruntOptions)
+	assert.Equal(t, "europe-west1", attrs.Location)
+	assert.True(t, attrs.VersioningEnabled)
+	assert.True(t, attrs.UniformBucketLevelAccess.Enabled)
+	assert.Equal(t, &storage.BucketEncryption{DefaultKMSKeyName: "projects/p/locations/europe-west1/keyRings/r/cryptoKeys/k"}, attrs.Encryption)
+	assert.Equal(t, []storage.LifecycleRule{
+		{
+			Action:    storage.LifecycleAction{Type: storage.DeleteAction},
+			Condition: storage.LifecycleCondition{Liveness: storage.Archived, DaysSinceNoncurrentTime: 30},
+		},
+		{
+			Action:    storage.LifecycleAction{Type: storage.DeleteAction},
+			Condition: storage.LifecycleCondition{Liveness: storage.Archived, NumNewerVersions: 5},
+		},
+	}, attrs.Lifecycle.Rules)
+
+	// The options are only used by terragrunt to create the bucket.
+	initArgs := GCSInitializer{}.GetTerraformInitArgs(map[string]interface{}{"bucket": "my-state", "gcs_bucket_kms_key_name": "key", "gcs_bucket_num_newer_versions": 5})
+	assert.Equal(t, map[string]interface{}{"bucket": "my-state"}, initArgs)
+}
+
+func TestGCSBucketAttrsDefaults(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+
+	config, err := parseExtendedGCSConfig(map[string]interface{}{"bucket": "my-state", "skip_bucket_versioning": true, "enable_bucket_policy_only": true})
+	require.NoError(t, err)
+
+	attrs := gcsBucketAttrs(config, terragruntOptions)
+	assert.False(t, attrs.VersioningEnabled)
+	assert.True(t, attrs.UniformBucketLevelAccess.Enabled)
+	assert.Nil(t, attrs.Encryption)
+	assert.Empty(t, attrs.Lifecycle.Rules)
+
+	config, err = parseExtendedGCSConfig(map[string]interface{}{"bucket": "my-state", "gcs_bucket_num_newer_versions": -1})
+	require.NoError(t, err)
+	assert.Error(t, validateGCSConfig(config))
+}
+
+func TestGCSStateObjectName(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "default.tfstate", gcsStateObjectName(""))
+	assert.Equal(t, "vpc/default.tfstate", gcsStateObjectName("vpc"))
+	assert.Equal(t, "vpc/default.tfstate", gcsStateObjectName("/vpc/"))
+}
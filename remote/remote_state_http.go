@@ -0,0 +1,193 @@
+package remote
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+// The lock protocols of the state services that terragrunt knows how to generate the lock and unlock endpoints for.
+const (
+	// The protocol of the http backend, which locks and unlocks the state at its address with the LOCK and UNLOCK
+	// methods.
+	HTTPLockProtocolStandard = "standard"
+	// The protocol of the GitLab-managed Terraform state, which locks the state by POSTing to <address>/lock and unlocks
+	// it by DELETEing it.
+	HTTPLockProtocolGitLab = "gitlab"
+)
+
+// httpLockEndpoints are the endpoints and methods used to lock and unlock the state with a lock protocol.
+type httpLockEndpoints struct {
+	path         string
+	lockMethod   string
+	unlockMethod string
+}
+
+var httpLockProtocols = map[string]httpLockEndpoints{
+	HTTPLockProtocolStandard: {path: "", lockMethod: "LOCK", unlockMethod: "UNLOCK"},
+	HTTPLockProtocolGitLab:   {path: "/lock", lockMethod: "POST", unlockMethod: "DELETE"},
+}
+
+// These are settings that can appear in the remote_state config that are ONLY used by Terragrunt and NOT forwarded
+// to the underlying Terraform backend configuration.
+var terragruntHTTPOnlyConfigs = []string{
+	"lock_protocol",
+}
+
+// A representation of the configuration options of the http backend that terragrunt uses to generate the lock and
+// unlock endpoints and validate the config
+type RemoteStateConfigHTTP struct {
+	Address                string            `mapstructure:"address"`
+	LockAddress            string            `mapstructure:"lock_address"`
+	LockMethod             string            `mapstructure:"lock_method"`
+	UnlockAddress          string            `mapstructure:"unlock_address"`
+	UnlockMethod           string            `mapstructure:"unlock_method"`
+	Headers                map[string]string `mapstructure:"headers"`
+	ClientCertificatePEM   string            `mapstructure:"client_certificate_pem"`
+	ClientPrivateKeyPEM    string            `mapstructure:"client_private_key_pem"`
+	ClientCACertificatePEM string            `mapstructure:"client_ca_certificate_pem"`
+
+	LockProtocol string `mapstructure:"lock_protocol"`
+}
+
+type HTTPInitializer struct{}
+
+// Returns true if any of the backend settings, including the generated lock and unlock endpoints, are different than
+// the current config. As there is nothing to create for the http backend, this is the only reason to initialize it.
+func (httpInitializer HTTPInitializer) NeedsInitialization(remoteState *RemoteState, existingBackend *TerraformBackend, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	if remoteState.DisableInit {
+		return false, nil
+	}
+
+	if err := validateHTTPConfig(remoteState.Config); err != nil {
+		return false, err
+	}
+
+	config := httpInitializer.GetTerraformInitArgs(remoteState.Config)
+	if existingBackend == nil {
+		return len(config) != 0, nil
+	}
+
+	if existingBackend.Type != "http" {
+		terragruntOptions.Logger.Debugf("Backend type has changed from http to %s", existingBackend.Type)
+		return true, nil
+	}
+
+	if !terraformStateConfigEqual(existingBackend.Config, config) {
+		terragruntOptions.Logger.Debugf("Backend config changed from %s to %s", existingBackend.Config, config)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Validate the http backend config. The state is stored by the state service, so there is nothing to create.
+func (httpInitializer HTTPInitializer) Initialize(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	return validateHTTPConfig(remoteState.Config)
+}
+
+// Return the config to pass to the http backend, with the lock and unlock endpoints of the configured lock protocol.
+// The endpoints and methods that are set explicitly are kept as is.
+func (httpInitializer HTTPInitializer) GetTerraformInitArgs(config map[string]interface{}) map[string]interface{} {
+	var filteredConfig = make(map[string]interface{})
+
+	for key, val := range config {
+		if util.ListContainsElement(terragruntHTTPOnlyConfigs, key) {
+			continue
+		}
+
+		filteredConfig[key] = val
+	}
+
+	httpConfig, err := parseHTTPConfig(config)
+	if err != nil || httpConfig.LockProtocol == "" || httpConfig.Address == "" {
+		return filteredConfig
+	}
+
+	endpoints, ok := httpLockProtocols[httpConfig.LockProtocol]
+	if !ok {
+		return filteredConfig
+	}
+
+	lockAddress := httpConfig.Address
+	if endpoints.path != "" {
+		lockAddress = strings.TrimSuffix(httpConfig.Address, "/") + endpoints.path
+	}
+
+	defaults := map[string]string{
+		"lock_address":   lockAddress,
+		"lock_method":    endpoints.lockMethod,
+		"unlock_address": lockAddress,
+		"unlock_method":  endpoints.unlockMethod,
+	}
+	for key, value := range defaults {
+		if _, isSet := filteredConfig[key]; !isSet {
+			filteredConfig[key] = value
+		}
+	}
+
+	return filteredConfig
+}
+
+// Parse the given map into an http backend config
+func parseHTTPConfig(config map[string]interface{}) (*RemoteStateConfigHTTP, error) {
+	var httpConfig RemoteStateConfigHTTP
+
+	if err := mapstructure.Decode(config, &httpConfig); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	return &httpConfig, nil
+}
+
+// Validate all the parameters of the given http remote state configuration
+func validateHTTPConfig(config map[string]interface{}) error {
+	httpConfig, err := parseHTTPConfig(config)
+	if err != nil {
+		return err
+	}
+
+	if httpConfig.LockProtocol != "" {
+		if _, ok := httpLockProtocols[httpConfig.LockProtocol]; !ok {
+			return errors.WithStackTrace(InvalidHTTPRemoteStateConfig{Name: "lock_protocol", Reason: fmt.Sprintf("must be %s or %s, got %q", HTTPLockProtocolStandard, HTTPLockProtocolGitLab, httpConfig.LockProtocol)})
+		}
+
+		// The endpoints are generated from the address.
+		if httpConfig.Address == "" {
+			return errors.WithStackTrace(MissingRequiredHTTPRemoteStateConfig("address"))
+		}
+	}
+
+	// The client certificate is only used for mutual TLS along with its private key.
+	if httpConfig.ClientCertificatePEM != "" && httpConfig.ClientPrivateKeyPEM == "" {
+		return errors.WithStackTrace(MissingRequiredHTTPRemoteStateConfig("client_private_key_pem"))
+	}
+
+	if httpConfig.ClientPrivateKeyPEM != "" && httpConfig.ClientCertificatePEM == "" {
+		return errors.WithStackTrace(MissingRequiredHTTPRemoteStateConfig("client_certificate_pem"))
+	}
+
+	return nil
+}
+
+// Custom error types
+
+type MissingRequiredHTTPRemoteStateConfig string
+
+func (configName MissingRequiredHTTPRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Missing required http remote state configuration %s", string(configName))
+}
+
+type InvalidHTTPRemoteStateConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidHTTPRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Invalid http remote state configuration %s: %s", err.Name, err.Reason)
+}
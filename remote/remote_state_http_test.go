@@ -0,0 +1,166 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPGetTerraformInitArgs(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected map[string]interface{}
+	}{
+		{
+			"no-lock-protocol",
+			map[string]interface{}{"address": "https://state.example.com/prod"},
+			map[string]interface{}{"address": "https://state.example.com/prod"},
+		},
+		{
+			"standard-lock-protocol",
+			map[string]interface{}{"address": "https://state.example.com/prod", "lock_protocol": "standard"},
+			map[string]interface{}{
+				"address":        "https://state.example.com/prod",
+				"lock_address":   "https://state.example.com/prod",
+				"lock_method":    "LOCK",
+				"unlock_address": "https://state.example.com/prod",
+				"unlock_method":  "UNLOCK",
+			},
+		},
+		{
+			"gitlab-lock-protocol",
+			map[string]interface{}{"address": "https://gitlab.com/api/v4/projects/42/terraform/state/prod/", "lock_protocol": "gitlab", "retry_wait_min": 5},
+			map[string]interface{}{
+				"address":        "https://gitlab.com/api/v4/projects/42/terraform/state/prod/",
+				"lock_address":   "https://gitlab.com/api/v4/projects/42/terraform/state/prod/lock",
+				"lock_method":    "POST",
+				"unlock_address": "https://gitlab.com/api/v4/projects/42/terraform/state/prod/lock",
+				"unlock_method":  "DELETE",
+				"retry_wait_min": 5,
+			},
+		},
+		{
+			"explicit-endpoints-are-kept",
+			map[string]interface{}{"address": "https://state.example.com/prod", "lock_protocol": "standard", "unlock_method": "DELETE"},
+			map[string]interface{}{
+				"address":        "https://state.example.com/prod",
+				"lock_address":   "https://state.example.com/prod",
+				"lock_method":    "LOCK",
+				"unlock_address": "https://state.example.com/prod",
+				"unlock_method":  "DELETE",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual := HTTPInitializer{}.GetTerraformInitArgs(testCase.config)
+			assert.Equal(t, testCase.expected, actual)
+		})
+	}
+}
+
+func TestValidateHTTPConfig(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected error
+	}{
+		{
+			"valid",
+			map[string]interface{}{
+				"address":                "https://state.example.com/prod",
+				"lock_protocol":          "gitlab",
+				"headers":                map[string]interface{}{"X-Team": "infra"},
+				"client_certificate_pem": "cert",
+				"client_private_key_pem": "key",
+			},
+			nil,
+		},
+		{
+			"unknown-lock-protocol",
+			map[string]interface{}{"address": "https://state.example.com/prod", "lock_protocol": "consul"},
+			InvalidHTTPRemoteStateConfig{Name: "lock_protocol", Reason: `must be standard or gitlab, got "consul"`},
+		},
+		{
+			"lock-protocol-without-address",
+			map[string]interface{}{"lock_protocol": "standard"},
+			MissingRequiredHTTPRemoteStateConfig("address"),
+		},
+		{
+			"certificate-without-key",
+			map[string]interface{}{"address": "https://state.example.com/prod", "client_certificate_pem": "cert"},
+			MissingRequiredHTTPRemoteStateConfig("client_private_key_pem"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := validateHTTPConfig(testCase.config)
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, testCase.expected, errors.Unwrap(err))
+			}
+		})
+	}
+}
+
+func TestHTTPNeedsInitialization(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+
+	remoteState := &RemoteState{
+		Backend: "http",
+		Config:  map[string]interface{}{"address": "https://state.example.com/prod", "lock_protocol": "standard"},
+	}
+
+	// The generated endpoints are compared with the ones terraform was initialized with.
+	existingBackend := &TerraformBackend{Type: "http", Config: map[string]interface{}{
+		"address":        "https://state.example.com/prod",
+		"lock_address":   "https://state.example.com/prod",
+		"lock_method":    "LOCK",
+		"unlock_address": "https://state.example.com/prod",
+		"unlock_method":  "UNLOCK",
+		"username":       nil,
+	}}
+	needsInit, err := HTTPInitializer{}.NeedsInitialization(remoteState, existingBackend, terragruntOptions)
+	require.NoError(t, err)
+	assert.False(t, needsInit)
+
+	existingBackend.Config["lock_method"] = "POST"
+	needsInit, err = HTTPInitializer{}.NeedsInitialization(remoteState, existingBackend, terragruntOptions)
+	require.NoError(t, err)
+	assert.True(t, needsInit)
+}
+
+func TestHTTPToTerraformInitArgsWithHeaders(t *testing.T) {
+	t.Parallel()
+
+	remoteState := RemoteState{
+		Backend: "http",
+		Config:  map[string]interface{}{"address": "https://state.example.com/prod", "headers": map[string]interface{}{"X-Team": "infra"}},
+	}
+	args := remoteState.ToTerraformInitArgs()
+	assert.ElementsMatch(t, []string{"-backend-config=address=https://state.example.com/prod", `-backend-config=headers={X-Team="infra"}`}, args)
+}
@@ -0,0 +1,68 @@
+package remote
+
+import (
+	"fmt"
+	"strings"
+)
+
+// The settings of the config of each backend that identify where the state is stored. Two remote states with the same
+// values of these settings store their state at the same location, and overwrite each other.
+var stateLocationConfigs = map[string][]string{
+	"s3":      {"bucket", "key"},
+	"gcs":     {"bucket", "prefix"},
+	"azurerm": {"storage_account_name", "container_name", "key"},
+	"http":    {"address"},
+	"consul":  {"address", "path"},
+	"etcdv3":  {"endpoints", "prefix"},
+}
+
+// StateLocation returns where the state of this remote state is stored, such as s3://<bucket>/<key> for the s3 backend,
+// or an empty string if it can't be determined from the config, e.g. for the local backend, whose path is relative to
+// the working dir of each module, or if one of the settings identifying the location is not set.
+func (remoteState *RemoteState) StateLocation() string {
+	if remoteState.Backend == "remote" || remoteState.Backend == "cloud" {
+		return tfcStateLocation(remoteState.Backend, remoteState.Config)
+	}
+
+	configNames, ok := stateLocationConfigs[remoteState.Backend]
+	if !ok {
+		return ""
+	}
+
+	values := []string{}
+	for _, name := range configNames {
+		value, isSet := remoteState.Config[name]
+		if !isSet || value == nil {
+			// The gcs backend stores the state at the root of the bucket without a prefix.
+			if remoteState.Backend == "gcs" && name == "prefix" {
+				values = append(values, "")
+				continue
+			}
+			return ""
+		}
+		values = append(values, fmt.Sprintf("%v", value))
+	}
+
+	return fmt.Sprintf("%s://%s", remoteState.Backend, strings.Join(values, "/"))
+}
+
+// tfcStateLocation returns the workspace of the remote and cloud backends, which is only known when the backend selects
+// a single workspace by name.
+func tfcStateLocation(backend string, config map[string]interface{}) string {
+	workspaces, ok := config["workspaces"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+
+	name, ok := workspaces["name"].(string)
+	if !ok || name == "" {
+		return ""
+	}
+
+	hostname, ok := config["hostname"].(string)
+	if !ok || hostname == "" {
+		hostname = defaultTFCHostname
+	}
+
+	return fmt.Sprintf("%s://%s/%v/%s", backend, hostname, config["organization"], name)
+}
@@ -0,0 +1,36 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStateLocation(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name        string
+		remoteState RemoteState
+		expected    string
+	}{
+		{"s3", RemoteState{Backend: "s3", Config: map[string]interface{}{"bucket": "my-bucket", "key": "vpc/terraform.tfstate", "region": "us-east-1"}}, "s3://my-bucket/vpc/terraform.tfstate"},
+		{"s3-without-key", RemoteState{Backend: "s3", Config: map[string]interface{}{"bucket": "my-bucket"}}, ""},
+		{"gcs-without-prefix", RemoteState{Backend: "gcs", Config: map[string]interface{}{"bucket": "my-bucket"}}, "gcs://my-bucket/"},
+		{"azurerm", RemoteState{Backend: "azurerm", Config: map[string]interface{}{"storage_account_name": "account", "container_name": "tfstate", "key": "vpc.tfstate"}}, "azurerm://account/tfstate/vpc.tfstate"},
+		{"cloud-name", RemoteState{Backend: "cloud", Config: map[string]interface{}{"organization": "my-org", "workspaces": map[string]interface{}{"name": "vpc"}}}, "cloud://app.terraform.io/my-org/vpc"},
+		{"cloud-tags", RemoteState{Backend: "cloud", Config: map[string]interface{}{"organization": "my-org", "workspaces": map[string]interface{}{"tags": []interface{}{"vpc"}}}}, ""},
+		{"local", RemoteState{Backend: "local", Config: map[string]interface{}{"path": "terraform.tfstate"}}, ""},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, testCase.remoteState.StateLocation())
+		})
+	}
+}
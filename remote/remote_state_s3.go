@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
@@ -24,6 +25,7 @@ const (
 	DefaultS3BucketAccessLoggingTargetPrefix = "TFStateLogs/"
 	SidRootPolicy                            = "RootAccess"
 	SidEnforcedTLSPolicy                     = "EnforcedTLS"
+	SidDenyUnencryptedPutPolicy              = "DenyUnencryptedObjectUploads"
 
 	s3TimeBetweenRetries  = 5 * time.Second
 	s3MaxRetries          = 3
@@ -56,6 +58,12 @@ type ExtendedRemoteStateConfigS3 struct {
 	AccessLoggingTargetPrefix      string            `mapstructure:"accesslogging_target_prefix"`
 	BucketSSEAlgorithm             string            `mapstructure:"bucket_sse_algorithm"`
 	BucketSSEKMSKeyID              string            `mapstructure:"bucket_sse_kms_key_id"`
+	EnableBucketDenyUnencryptedPut bool              `mapstructure:"enable_bucket_deny_unencrypted_put"`
+	EnableBucketObjectLock         bool              `mapstructure:"enable_bucket_object_lock"`
+	BucketObjectLockRetentionMode  string            `mapstructure:"bucket_object_lock_retention_mode"`
+	BucketObjectLockRetentionDays  int               `mapstructure:"bucket_object_lock_retention_days"`
+	SkipBucketDriftCheck           bool              `mapstructure:"skip_bucket_drift_check"`
+	AssumeRoleWithIAMRole          bool              `mapstructure:"assume_role_with_iam_role"`
 }
 
 // These are settings that can appear in the remote_state config that are ONLY used by Terragrunt and NOT forwarded
@@ -77,6 +85,12 @@ var terragruntOnlyConfigs = []string{
 	"accesslogging_target_prefix",
 	"bucket_sse_algorithm",
 	"bucket_sse_kms_key_id",
+	"enable_bucket_deny_unencrypted_put",
+	"enable_bucket_object_lock",
+	"bucket_object_lock_retention_mode",
+	"bucket_object_lock_retention_days",
+	"skip_bucket_drift_check",
+	"assume_role_with_iam_role",
 }
 
 type RemoteStateConfigS3AssumeRole struct {
@@ -99,6 +113,7 @@ type RemoteStateConfigS3 struct {
 	SessionName      string                        `mapstructure:"session_name"` // Deprecated in Terraform version 1.6 or newer.
 	LockTable        string                        `mapstructure:"lock_table"`   // Deprecated in Terraform version 0.13 or newer.
 	DynamoDBTable    string                        `mapstructure:"dynamodb_table"`
+	UseLockfile      bool                          `mapstructure:"use_lockfile"` // Supported in Terraform version 1.10 or newer.
 	CredsFilename    string                        `mapstructure:"shared_credentials_file"`
 	S3ForcePathStyle bool                          `mapstructure:"force_path_style"`
 	AssumeRole       RemoteStateConfigS3AssumeRole `mapstructure:"assume_role"`
@@ -117,6 +132,7 @@ func (c *ExtendedRemoteStateConfigS3) GetAwsSessionConfig() *aws_helper.AwsSessi
 		CredsFilename:           c.remoteStateConfigS3.CredsFilename,
 		S3ForcePathStyle:        c.remoteStateConfigS3.S3ForcePathStyle,
 		DisableComputeChecksums: c.DisableAWSClientChecksums,
+		AssumeRoleWithIAMRole:   c.AssumeRoleWithIAMRole,
 	}
 }
 
@@ -316,6 +332,8 @@ func (s3Initializer S3Initializer) Initialize(remoteState *RemoteState, terragru
 		if err := updateS3BucketIfNecessary(s3Client, s3ConfigExtended, terragruntOptions); err != nil {
 			return err
 		}
+	} else if !s3ConfigExtended.SkipBucketDriftCheck {
+		warnIfS3BucketDrifted(s3Client, s3ConfigExtended, terragruntOptions)
 	}
 
 	if !s3ConfigExtended.SkipBucketVersioning {
@@ -332,6 +350,11 @@ func (s3Initializer S3Initializer) Initialize(remoteState *RemoteState, terragru
 		return err
 	}
 
+	// Both locks are acquired while the modules are switched over from the lock table to the lockfile.
+	if s3Config.UseLockfile && s3Config.GetLockTableName() != "" {
+		terragruntOptions.Logger.Debugf("The state in S3 bucket %s is locked with both a lockfile and the DynamoDB table %s. Once all the modules use the lockfile, the table can be deleted with `terragrunt state remove-lock-table`.", s3Config.Bucket, s3Config.GetLockTableName())
+	}
+
 	return nil
 }
 
@@ -412,9 +435,45 @@ func validateS3Config(extendedConfig *ExtendedRemoteStateConfigS3, terragruntOpt
 	}
 
 	if !config.Encrypt {
+		// Terraform only sends the encryption header on the uploads of the state when encrypt is enabled, so they would
+		// all be denied.
+		if extendedConfig.EnableBucketDenyUnencryptedPut {
+			return errors.WithStackTrace(InvalidS3RemoteStateConfig{Name: "enable_bucket_deny_unencrypted_put", Reason: "requires encrypt to be true"})
+		}
+
 		terragruntOptions.Logger.Warnf("Encryption is not enabled on the S3 remote state bucket %s. Terraform state files may contain secrets, so we STRONGLY recommend enabling encryption!", config.Bucket)
 	}
 
+	return validateS3ObjectLockConfig(extendedConfig)
+}
+
+// Validate the object lock settings of the given S3 remote state configuration
+func validateS3ObjectLockConfig(extendedConfig *ExtendedRemoteStateConfigS3) error {
+	if !extendedConfig.EnableBucketObjectLock {
+		if extendedConfig.BucketObjectLockRetentionMode != "" || extendedConfig.BucketObjectLockRetentionDays != 0 {
+			return errors.WithStackTrace(InvalidS3RemoteStateConfig{Name: "bucket_object_lock_retention_mode", Reason: "requires enable_bucket_object_lock to be true"})
+		}
+		return nil
+	}
+
+	// Object lock only protects the versions of the objects.
+	if extendedConfig.SkipBucketVersioning {
+		return errors.WithStackTrace(InvalidS3RemoteStateConfig{Name: "enable_bucket_object_lock", Reason: "requires the bucket to be versioned, but skip_bucket_versioning is true"})
+	}
+
+	switch extendedConfig.BucketObjectLockRetentionMode {
+	case "":
+		if extendedConfig.BucketObjectLockRetentionDays != 0 {
+			return errors.WithStackTrace(InvalidS3RemoteStateConfig{Name: "bucket_object_lock_retention_days", Reason: "requires bucket_object_lock_retention_mode to be set"})
+		}
+	case s3.ObjectLockRetentionModeGovernance, s3.ObjectLockRetentionModeCompliance:
+		if extendedConfig.BucketObjectLockRetentionDays <= 0 {
+			return errors.WithStackTrace(InvalidS3RemoteStateConfig{Name: "bucket_object_lock_retention_days", Reason: "must be a positive number of days"})
+		}
+	default:
+		return errors.WithStackTrace(InvalidS3RemoteStateConfig{Name: "bucket_object_lock_retention_mode", Reason: fmt.Sprintf("must be %s or %s, got %q", s3.ObjectLockRetentionModeGovernance, s3.ObjectLockRetentionModeCompliance, extendedConfig.BucketObjectLockRetentionMode)})
+	}
+
 	return nil
 }
 
@@ -541,9 +600,39 @@ func updateS3BucketIfNecessary(s3Client *s3.S3, config *ExtendedRemoteStateConfi
 		}
 	}
 
+	if bucketUpdatesRequired.DenyUnencryptedPut {
+		if err := EnableDenyUnencryptedPutForS3Bucket(s3Client, config.remoteStateConfigS3.Bucket, config, terragruntOptions); err != nil {
+			return err
+		}
+	}
+
+	if bucketUpdatesRequired.ObjectLock {
+		if err := EnableObjectLockForS3Bucket(s3Client, config, terragruntOptions); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
+// warnIfS3BucketDrifted warns the user if the live S3 bucket deviates from the declared baseline, when terragrunt is
+// not allowed to update it. The bucket is not modified and errors while checking it are not fatal.
+func warnIfS3BucketDrifted(s3Client *s3.S3, config *ExtendedRemoteStateConfigS3, terragruntOptions *options.TerragruntOptions) {
+	if !DoesS3BucketExist(s3Client, &config.remoteStateConfigS3.Bucket) {
+		return
+	}
+
+	needUpdate, _, err := checkIfS3BucketNeedsUpdate(s3Client, config, terragruntOptions)
+	if err != nil {
+		terragruntOptions.Logger.Warnf("Could not check if the remote state S3 bucket %s deviates from its configuration: %v", config.remoteStateConfigS3.Bucket, err)
+		return
+	}
+
+	if needUpdate {
+		terragruntOptions.Logger.Warnf("Terragrunt will not update the remote state S3 bucket %s, as bucket updates are disabled. Set 'skip_bucket_drift_check' to silence this warning.", config.remoteStateConfigS3.Bucket)
+	}
+}
+
 // configureAccessLogBucket - configure access log bucket.
 func configureAccessLogBucket(terragruntOptions *options.TerragruntOptions, s3Client *s3.S3, config *ExtendedRemoteStateConfigS3) error {
 	terragruntOptions.Logger.Debugf("Enabling bucket-wide Access Logging on AWS S3 bucket %s - using as TargetBucket %s", config.remoteStateConfigS3.Bucket, config.AccessLoggingBucketName)
@@ -574,12 +663,14 @@ func configureAccessLogBucket(terragruntOptions *options.TerragruntOptions, s3Cl
 }
 
 type S3BucketUpdatesRequired struct {
-	Versioning    bool
-	SSEEncryption bool
-	RootAccess    bool
-	EnforcedTLS   bool
-	AccessLogging bool
-	PublicAccess  bool
+	Versioning         bool
+	SSEEncryption      bool
+	RootAccess         bool
+	EnforcedTLS        bool
+	AccessLogging      bool
+	PublicAccess       bool
+	DenyUnencryptedPut bool
+	ObjectLock         bool
 }
 
 func checkIfS3BucketNeedsUpdate(s3Client *s3.S3, config *ExtendedRemoteStateConfigS3, terragruntOptions *options.TerragruntOptions) (bool, S3BucketUpdatesRequired, error) {
@@ -657,6 +748,30 @@ func checkIfS3BucketNeedsUpdate(s3Client *s3.S3, config *ExtendedRemoteStateConf
 		}
 	}
 
+	if config.EnableBucketDenyUnencryptedPut {
+		enabled, err := checkIfBucketPolicyHasStatement(s3Client, config.remoteStateConfigS3.Bucket, SidDenyUnencryptedPutPolicy, terragruntOptions)
+		if err != nil {
+			return false, configBucket, err
+		}
+
+		if !enabled {
+			configBucket.DenyUnencryptedPut = true
+			needUpdate = append(needUpdate, "Bucket Deny Unencrypted Uploads")
+		}
+	}
+
+	if config.EnableBucketObjectLock {
+		enabled, err := checkIfObjectLockForS3Enabled(s3Client, config, terragruntOptions)
+		if err != nil {
+			return false, configBucket, err
+		}
+
+		if !enabled {
+			configBucket.ObjectLock = true
+			needUpdate = append(needUpdate, "Bucket Object Lock")
+		}
+	}
+
 	// show update message if any of the above configs are not set
 	if len(needUpdate) > 0 {
 		terragruntOptions.Logger.Warnf("The remote state S3 bucket %s needs to be updated:", config.remoteStateConfigS3.Bucket)
@@ -693,7 +808,7 @@ func checkIfVersioningEnabled(s3Client *s3.S3, config *RemoteStateConfigS3, terr
 func CreateS3BucketWithVersioningSSEncryptionAndAccessLogging(s3Client *s3.S3, config *ExtendedRemoteStateConfigS3, terragruntOptions *options.TerragruntOptions) error {
 	terragruntOptions.Logger.Debugf("Create S3 bucket %s with versioning, SSE encryption, and access logging.", config.remoteStateConfigS3.Bucket)
 
-	err := CreateS3Bucket(s3Client, aws.String(config.remoteStateConfigS3.Bucket), terragruntOptions)
+	err := createS3Bucket(s3Client, aws.String(config.remoteStateConfigS3.Bucket), config.EnableBucketObjectLock, terragruntOptions)
 
 	if err != nil {
 		if accessError := checkBucketAccess(s3Client, aws.String(config.remoteStateConfigS3.Bucket), aws.String(config.remoteStateConfigS3.Key)); accessError != nil {
@@ -746,6 +861,18 @@ func CreateS3BucketWithVersioningSSEncryptionAndAccessLogging(s3Client *s3.S3, c
 		return err
 	}
 
+	if config.EnableBucketDenyUnencryptedPut {
+		if err := EnableDenyUnencryptedPutForS3Bucket(s3Client, config.remoteStateConfigS3.Bucket, config, terragruntOptions); err != nil {
+			return err
+		}
+	}
+
+	if config.EnableBucketObjectLock {
+		if err := EnableObjectLockForS3Bucket(s3Client, config, terragruntOptions); err != nil {
+			return err
+		}
+	}
+
 	if config.SkipBucketAccessLogging {
 		terragruntOptions.Logger.Warnf("Terragrunt configuration option 'skip_bucket_accesslogging' is now deprecated. Access logging for the state bucket %s is disabled by default. To enable access logging for bucket %s, please provide property `accesslogging_bucket_name` in the terragrunt config file. For more details, please refer to the Terragrunt documentation.", config.remoteStateConfigS3.Bucket, config.remoteStateConfigS3.Bucket)
 	}
@@ -873,9 +1000,19 @@ func WaitUntilS3BucketExists(s3Client *s3.S3, config *RemoteStateConfigS3, terra
 
 // Create the S3 bucket specified in the given config
 func CreateS3Bucket(s3Client *s3.S3, bucket *string, terragruntOptions *options.TerragruntOptions) error {
+	return createS3Bucket(s3Client, bucket, false, terragruntOptions)
+}
+
+// Create the given S3 bucket, with object lock enabled if objectLock is true. Object lock can also be enabled later on
+// versioned buckets, but enabling it at creation also enables versioning right away.
+func createS3Bucket(s3Client *s3.S3, bucket *string, objectLock bool, terragruntOptions *options.TerragruntOptions) error {
 	terragruntOptions.Logger.Debugf("Creating S3 bucket %s", aws.StringValue(bucket))
 	// https://github.com/aws/aws-sdk-go/blob/v1.44.245/service/s3/api.go#L41760
-	_, err := s3Client.CreateBucket(&s3.CreateBucketInput{Bucket: bucket, ObjectOwnership: aws.String("ObjectWriter")})
+	input := &s3.CreateBucketInput{Bucket: bucket, ObjectOwnership: aws.String("ObjectWriter")}
+	if objectLock {
+		input.ObjectLockEnabledForBucket = aws.Bool(true)
+	}
+	_, err := s3Client.CreateBucket(input)
 	if err != nil {
 		return errors.WithStackTrace(err)
 	}
@@ -1124,6 +1261,176 @@ func checkIfBucketEnforcedTLS(s3Client *s3.S3, config *RemoteStateConfigS3, terr
 	return false, nil
 }
 
+// Add a policy to deny the uploads of objects that are not encrypted with server-side encryption to the bucket
+func EnableDenyUnencryptedPutForS3Bucket(s3Client *s3.S3, bucket string, config *ExtendedRemoteStateConfigS3, terragruntOptions *options.TerragruntOptions) error {
+	terragruntOptions.Logger.Debugf("Denying unencrypted uploads to S3 bucket %s", bucket)
+
+	partition, err := aws_helper.GetAWSPartition(config.GetAwsSessionConfig(), terragruntOptions)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	var policyInBucket aws_helper.Policy
+	policyOutput, err := s3Client.GetBucketPolicy(&s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	// If there's no policy, we need to create one
+	if err != nil {
+		terragruntOptions.Logger.Debugf("Policy not exists for bucket %s", bucket)
+	}
+
+	if policyOutput.Policy != nil {
+		policyInBucket, err = aws_helper.UnmarshalPolicy(*policyOutput.Policy)
+		if err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	for _, statement := range policyInBucket.Statement {
+		if statement.Sid == SidDenyUnencryptedPutPolicy {
+			terragruntOptions.Logger.Debugf("Policy for DenyUnencryptedObjectUploads already exists for bucket %s", bucket)
+			return nil
+		}
+	}
+
+	denyS3Policy := aws_helper.Policy{
+		Version:   "2012-10-17",
+		Statement: append([]aws_helper.Statement{denyUnencryptedPutStatement(partition, bucket)}, policyInBucket.Statement...),
+	}
+	policy, err := aws_helper.MarshalPolicy(denyS3Policy)
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	_, err = s3Client.PutBucketPolicy(&s3.PutBucketPolicyInput{
+		Bucket: aws.String(bucket),
+		Policy: aws.String(string(policy)),
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	terragruntOptions.Logger.Debugf("Denied unencrypted uploads to bucket %s", bucket)
+	return nil
+}
+
+// Returns the policy statement that denies the uploads of objects without the server-side encryption header to the
+// given bucket. Terraform sends the header when encrypt is enabled in the backend config.
+func denyUnencryptedPutStatement(partition string, bucket string) aws_helper.Statement {
+	return aws_helper.Statement{
+		Sid:       SidDenyUnencryptedPutPolicy,
+		Effect:    "Deny",
+		Action:    "s3:PutObject",
+		Principal: "*",
+		Resource:  []string{"arn:" + partition + ":s3:::" + bucket + "/*"},
+		Condition: &map[string]interface{}{
+			"Null": map[string]interface{}{
+				"s3:x-amz-server-side-encryption": "true",
+			},
+		},
+	}
+}
+
+// Helper function to check if the policy of the bucket has a statement with the given sid
+func checkIfBucketPolicyHasStatement(s3Client *s3.S3, bucket string, sid string, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	terragruntOptions.Logger.Debugf("Checking if the policy of bucket %s has the statement %s", bucket, sid)
+
+	policyOutput, err := s3Client.GetBucketPolicy(&s3.GetBucketPolicyInput{
+		Bucket: aws.String(bucket),
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "NoSuchBucketPolicy" {
+			return false, nil
+		}
+
+		return false, errors.WithStackTrace(err)
+	}
+
+	if policyOutput.Policy == nil {
+		return false, nil
+	}
+
+	policyInBucket, err := aws_helper.UnmarshalPolicy(*policyOutput.Policy)
+	if err != nil {
+		return false, errors.WithStackTrace(err)
+	}
+
+	for _, statement := range policyInBucket.Statement {
+		if statement.Sid == sid {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// Enable object lock for the S3 bucket specified in the given config, with the configured default retention, if any.
+// The bucket must be versioned.
+func EnableObjectLockForS3Bucket(s3Client *s3.S3, config *ExtendedRemoteStateConfigS3, terragruntOptions *options.TerragruntOptions) error {
+	bucket := config.remoteStateConfigS3.Bucket
+	terragruntOptions.Logger.Debugf("Enabling object lock on S3 bucket %s", bucket)
+
+	_, err := s3Client.PutObjectLockConfiguration(&s3.PutObjectLockConfigurationInput{
+		Bucket:                  aws.String(bucket),
+		ObjectLockConfiguration: objectLockConfiguration(config),
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	terragruntOptions.Logger.Debugf("Enabled object lock on S3 bucket %s", bucket)
+	return nil
+}
+
+// Returns the object lock configuration declared in the given config
+func objectLockConfiguration(config *ExtendedRemoteStateConfigS3) *s3.ObjectLockConfiguration {
+	lockConfig := &s3.ObjectLockConfiguration{ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled)}
+	if config.BucketObjectLockRetentionMode != "" {
+		lockConfig.Rule = &s3.ObjectLockRule{
+			DefaultRetention: &s3.DefaultRetention{
+				Mode: aws.String(config.BucketObjectLockRetentionMode),
+				Days: aws.Int64(int64(config.BucketObjectLockRetentionDays)),
+			},
+		}
+	}
+	return lockConfig
+}
+
+// Helper function to check if object lock is enabled for the bucket with the default retention declared in the config
+func checkIfObjectLockForS3Enabled(s3Client *s3.S3, config *ExtendedRemoteStateConfigS3, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	terragruntOptions.Logger.Debugf("Checking if object lock is enabled for AWS S3 bucket %s", config.remoteStateConfigS3.Bucket)
+
+	output, err := s3Client.GetObjectLockConfiguration(&s3.GetObjectLockConfigurationInput{Bucket: aws.String(config.remoteStateConfigS3.Bucket)})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == "ObjectLockConfigurationNotFoundError" {
+			return false, nil
+		}
+
+		return false, errors.WithStackTrace(err)
+	}
+
+	return objectLockConfigurationMatches(output.ObjectLockConfiguration, objectLockConfiguration(config)), nil
+}
+
+// Returns true if the live object lock configuration of a bucket is the declared one
+func objectLockConfigurationMatches(live *s3.ObjectLockConfiguration, declared *s3.ObjectLockConfiguration) bool {
+	if live == nil || aws.StringValue(live.ObjectLockEnabled) != aws.StringValue(declared.ObjectLockEnabled) {
+		return false
+	}
+
+	// Only the declared default retention is enforced.
+	if declared.Rule == nil {
+		return true
+	}
+
+	if live.Rule == nil || live.Rule.DefaultRetention == nil {
+		return false
+	}
+
+	return aws.StringValue(live.Rule.DefaultRetention.Mode) == aws.StringValue(declared.Rule.DefaultRetention.Mode) &&
+		aws.Int64Value(live.Rule.DefaultRetention.Days) == aws.Int64Value(declared.Rule.DefaultRetention.Days)
+}
+
 // Enable versioning for the S3 bucket specified in the given config
 func EnableVersioningForS3Bucket(s3Client *s3.S3, config *RemoteStateConfigS3, terragruntOptions *options.TerragruntOptions) error {
 	terragruntOptions.Logger.Debugf("Enabling versioning on S3 bucket %s", config.Bucket)
@@ -1441,6 +1748,161 @@ func UpdateLockTableSetSSEncryptionOnIfNecessary(s3Config *RemoteStateConfigS3,
 	return dynamodb.UpdateLockTableSetSSEncryptionOnIfNecessary(s3Config.GetLockTableName(), dynamodbClient, terragruntOptions)
 }
 
+// S3LockTable is the DynamoDB table the state of an S3 remote state config is locked with.
+type S3LockTable struct {
+	Name     string
+	Region   string
+	Endpoint string
+
+	sessionConfig *aws_helper.AwsSessionConfig
+}
+
+func (lockTable *S3LockTable) String() string {
+	if lockTable.Endpoint != "" {
+		return fmt.Sprintf("%s (%s, %s)", lockTable.Name, lockTable.Region, lockTable.Endpoint)
+	}
+	return fmt.Sprintf("%s (%s)", lockTable.Name, lockTable.Region)
+}
+
+// GetS3Locking returns the DynamoDB lock table of the given S3 remote state config, which is nil when the state is not
+// locked with DynamoDB, and whether the state is locked with a lockfile in the S3 bucket.
+func GetS3Locking(config map[string]interface{}) (*S3LockTable, bool, error) {
+	s3ConfigExtended, err := ParseExtendedS3Config(config)
+	if err != nil {
+		return nil, false, err
+	}
+	s3Config := s3ConfigExtended.remoteStateConfigS3
+
+	if s3Config.GetLockTableName() == "" {
+		return nil, s3Config.UseLockfile, nil
+	}
+
+	lockTable := &S3LockTable{
+		Name:          s3Config.GetLockTableName(),
+		Region:        s3Config.Region,
+		Endpoint:      s3Config.DynamoDBEndpoint,
+		sessionConfig: s3ConfigExtended.GetAwsSessionConfig(),
+	}
+
+	return lockTable, s3Config.UseLockfile, nil
+}
+
+// DeleteS3LockTable deletes the given DynamoDB lock table, unless the state of a module is still locked in it.
+func DeleteS3LockTable(lockTable *S3LockTable, terragruntOptions *options.TerragruntOptions) error {
+	dynamodbClient, err := dynamodb.CreateDynamoDbClient(lockTable.sessionConfig, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	lockIDs, err := dynamodb.LockTableLockIDs(lockTable.Name, dynamodbClient)
+	if err != nil {
+		return err
+	}
+
+	if len(lockIDs) > 0 {
+		return errors.WithStackTrace(LockTableInUse{TableName: lockTable.Name, LockIDs: lockIDs})
+	}
+
+	terragruntOptions.Logger.Infof("Deleting DynamoDB lock table %s", lockTable)
+	if err := dynamodb.DeleteTable(lockTable.Name, dynamodbClient); err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	return nil
+}
+
+// Delete the S3 bucket specified in the given config, with all the versions of its objects, and its DynamoDB lock table,
+// unless the state of a module is still locked in it. The access logs bucket is kept, as it may hold the logs of other
+// buckets.
+func (s3Initializer S3Initializer) Delete(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	s3ConfigExtended, err := ParseExtendedS3Config(remoteState.Config)
+	if err != nil {
+		return err
+	}
+	var s3Config = s3ConfigExtended.remoteStateConfigS3
+
+	// The lock table is deleted first, so that nothing is deleted if a state is locked.
+	lockTable, _, err := GetS3Locking(remoteState.Config)
+	if err != nil {
+		return err
+	}
+	if lockTable != nil {
+		dynamodbClient, err := dynamodb.CreateDynamoDbClient(lockTable.sessionConfig, terragruntOptions)
+		if err != nil {
+			return err
+		}
+		tableExists, err := dynamodb.LockTableExistsAndIsActive(lockTable.Name, dynamodbClient)
+		if err != nil {
+			return err
+		}
+		if tableExists {
+			if err := DeleteS3LockTable(lockTable, terragruntOptions); err != nil {
+				return err
+			}
+		} else {
+			terragruntOptions.Logger.Debugf("DynamoDB lock table %s does not exist", lockTable)
+		}
+	}
+
+	s3Client, err := CreateS3Client(s3ConfigExtended.GetAwsSessionConfig(), terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if !DoesS3BucketExist(s3Client, aws.String(s3Config.Bucket)) {
+		terragruntOptions.Logger.Debugf("Remote state S3 bucket %s does not exist", s3Config.Bucket)
+		return nil
+	}
+
+	return DeleteS3Bucket(s3Client, aws.String(s3Config.Bucket), terragruntOptions)
+}
+
+// DeleteS3Bucket deletes all the versions of the objects of the given S3 bucket, along with their delete markers, then
+// the bucket itself.
+func DeleteS3Bucket(s3Client *s3.S3, bucket *string, terragruntOptions *options.TerragruntOptions) error {
+	terragruntOptions.Logger.Infof("Deleting S3 bucket %s", aws.StringValue(bucket))
+
+	var deleteErr error
+	err := s3Client.ListObjectVersionsPages(&s3.ListObjectVersionsInput{Bucket: bucket}, func(page *s3.ListObjectVersionsOutput, lastPage bool) bool {
+		objects := []*s3.ObjectIdentifier{}
+		for _, version := range page.Versions {
+			objects = append(objects, &s3.ObjectIdentifier{Key: version.Key, VersionId: version.VersionId})
+		}
+		for _, marker := range page.DeleteMarkers {
+			objects = append(objects, &s3.ObjectIdentifier{Key: marker.Key, VersionId: marker.VersionId})
+		}
+		if len(objects) == 0 {
+			return true
+		}
+
+		terragruntOptions.Logger.Debugf("Deleting %d object versions of S3 bucket %s", len(objects), aws.StringValue(bucket))
+		output, err := s3Client.DeleteObjects(&s3.DeleteObjectsInput{
+			Bucket: bucket,
+			Delete: &s3.Delete{Objects: objects, Quiet: aws.Bool(true)},
+		})
+		if err != nil {
+			deleteErr = err
+			return false
+		}
+		if len(output.Errors) > 0 {
+			deleteErr = S3ObjectDeletionFailed{Bucket: aws.StringValue(bucket), Key: aws.StringValue(output.Errors[0].Key), Message: aws.StringValue(output.Errors[0].Message)}
+			return false
+		}
+		return true
+	})
+	if deleteErr != nil {
+		return errors.WithStackTrace(deleteErr)
+	}
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	if _, err := s3Client.DeleteBucket(&s3.DeleteBucketInput{Bucket: bucket}); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	return nil
+}
+
 // Create an authenticated client for DynamoDB
 func CreateS3Client(config *aws_helper.AwsSessionConfig, terragruntOptions *options.TerragruntOptions) (*s3.S3, error) {
 	session, err := aws_helper.CreateAwsSession(config, terragruntOptions)
@@ -1459,6 +1921,15 @@ func (configName MissingRequiredS3RemoteStateConfig) Error() string {
 	return fmt.Sprintf("Missing required S3 remote state configuration %s", string(configName))
 }
 
+type InvalidS3RemoteStateConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidS3RemoteStateConfig) Error() string {
+	return fmt.Sprintf("Invalid S3 remote state configuration %s: %s", err.Name, err.Reason)
+}
+
 type MultipleTagsDeclarations string
 
 func (target MultipleTagsDeclarations) Error() string {
@@ -1484,3 +1955,22 @@ type InvalidAccessLoggingBucketEncryption struct {
 func (err InvalidAccessLoggingBucketEncryption) Error() string {
 	return fmt.Sprintf("Encryption algorithm %s is not supported for access logging bucket. Please use AES256", err.BucketSSEAlgorithm)
 }
+
+type LockTableInUse struct {
+	TableName string
+	LockIDs   []string
+}
+
+func (err LockTableInUse) Error() string {
+	return fmt.Sprintf("The DynamoDB lock table %s can not be deleted, as it still holds the locks of the states %s", err.TableName, strings.Join(err.LockIDs, ", "))
+}
+
+type S3ObjectDeletionFailed struct {
+	Bucket  string
+	Key     string
+	Message string
+}
+
+func (err S3ObjectDeletionFailed) Error() string {
+	return fmt.Sprintf("Could not delete object %s of S3 bucket %s: %s", err.Key, err.Bucket, err.Message)
+}
@@ -0,0 +1,112 @@
+package remote
+
+import (
+	"fmt"
+
+	"github.com/gruntwork-io/terragrunt/dynamodb"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const (
+	BackendResourceS3Bucket      = "s3_bucket"
+	BackendResourceDynamoDBTable = "dynamodb_table"
+)
+
+// BackendDrift is a setting of a resource of the backend, such as the state bucket or the lock table, whose live value
+// differs from the one terragrunt configures the resource with.
+type BackendDrift struct {
+	Resource string `json:"resource"`
+	Name     string `json:"name"`
+	Setting  string `json:"setting"`
+	Expected string `json:"expected"`
+}
+
+// CheckS3BackendDrift compares the S3 bucket and DynamoDB lock table of the given S3 remote state config with the
+// configuration terragrunt would create them with, and returns the settings that differ. Nothing is modified.
+func CheckS3BackendDrift(config map[string]interface{}, terragruntOptions *options.TerragruntOptions) ([]BackendDrift, error) {
+	s3ConfigExtended, err := ParseExtendedS3Config(config)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateS3Config(s3ConfigExtended, terragruntOptions); err != nil {
+		return nil, err
+	}
+
+	s3Config := s3ConfigExtended.remoteStateConfigS3
+	drifts := []BackendDrift{}
+
+	s3Client, err := CreateS3Client(s3ConfigExtended.GetAwsSessionConfig(), terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	if !DoesS3BucketExist(s3Client, &s3Config.Bucket) {
+		drifts = append(drifts, BackendDrift{Resource: BackendResourceS3Bucket, Name: s3Config.Bucket, Setting: "exists", Expected: "true"})
+	} else {
+		_, bucketUpdatesRequired, err := checkIfS3BucketNeedsUpdate(s3Client, s3ConfigExtended, terragruntOptions)
+		if err != nil {
+			return nil, err
+		}
+		drifts = append(drifts, s3BucketDrifts(s3ConfigExtended, bucketUpdatesRequired)...)
+	}
+
+	tableName := s3Config.GetLockTableName()
+	if tableName == "" {
+		return drifts, nil
+	}
+
+	dynamodbClient, err := dynamodb.CreateDynamoDbClient(s3ConfigExtended.GetAwsSessionConfig(), terragruntOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	exists, err := dynamodb.LockTableExistsAndIsActive(tableName, dynamodbClient)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return append(drifts, BackendDrift{Resource: BackendResourceDynamoDBTable, Name: tableName, Setting: "exists", Expected: "true"}), nil
+	}
+
+	if s3ConfigExtended.EnableLockTableSSEncryption {
+		enabled, err := dynamodb.LockTableCheckSSEncryptionIsOn(tableName, dynamodbClient)
+		if err != nil {
+			return nil, err
+		}
+		if !enabled {
+			drifts = append(drifts, BackendDrift{Resource: BackendResourceDynamoDBTable, Name: tableName, Setting: "encryption", Expected: "enabled"})
+		}
+	}
+
+	return drifts, nil
+}
+
+// s3BucketDrifts returns the settings of the S3 bucket that need an update, along with the values terragrunt would
+// update them to.
+func s3BucketDrifts(config *ExtendedRemoteStateConfigS3, bucketUpdatesRequired S3BucketUpdatesRequired) []BackendDrift {
+	bucket := config.remoteStateConfigS3.Bucket
+	drifts := []BackendDrift{}
+
+	addDrift := func(required bool, setting string, expected string) {
+		if required {
+			drifts = append(drifts, BackendDrift{Resource: BackendResourceS3Bucket, Name: bucket, Setting: setting, Expected: expected})
+		}
+	}
+
+	addDrift(bucketUpdatesRequired.Versioning, "versioning", "enabled")
+	addDrift(bucketUpdatesRequired.SSEEncryption, "encryption", fetchEncryptionAlgorithm(config))
+	addDrift(bucketUpdatesRequired.RootAccess, "policy", fmt.Sprintf("statement %s", SidRootPolicy))
+	addDrift(bucketUpdatesRequired.EnforcedTLS, "policy", fmt.Sprintf("statement %s", SidEnforcedTLSPolicy))
+	addDrift(bucketUpdatesRequired.DenyUnencryptedPut, "policy", fmt.Sprintf("statement %s", SidDenyUnencryptedPutPolicy))
+	addDrift(bucketUpdatesRequired.AccessLogging, "access_logging", fmt.Sprintf("s3://%s/%s", config.AccessLoggingBucketName, config.AccessLoggingTargetPrefix))
+	addDrift(bucketUpdatesRequired.PublicAccess, "public_access_block", "all public access blocked")
+
+	objectLock := "enabled"
+	if config.BucketObjectLockRetentionMode != "" {
+		objectLock = fmt.Sprintf("enabled, %s retention of %d days", config.BucketObjectLockRetentionMode, config.BucketObjectLockRetentionDays)
+	}
+	addDrift(bucketUpdatesRequired.ObjectLock, "object_lock", objectLock)
+
+	return drifts
+}
@@ -0,0 +1,30 @@
+package remote
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestS3BucketDrifts(t *testing.T) {
+	t.Parallel()
+
+	config, err := ParseExtendedS3Config(map[string]interface{}{
+		"bucket":                            "my-state",
+		"bucket_sse_algorithm":              "AES256",
+		"enable_bucket_object_lock":         true,
+		"bucket_object_lock_retention_mode": "GOVERNANCE",
+		"bucket_object_lock_retention_days": 30,
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, s3BucketDrifts(config, S3BucketUpdatesRequired{}))
+
+	drifts := s3BucketDrifts(config, S3BucketUpdatesRequired{SSEEncryption: true, EnforcedTLS: true, ObjectLock: true})
+	assert.Equal(t, []BackendDrift{
+		{Resource: BackendResourceS3Bucket, Name: "my-state", Setting: "encryption", Expected: "AES256"},
+		{Resource: BackendResourceS3Bucket, Name: "my-state", Setting: "policy", Expected: "statement EnforcedTLS"},
+		{Resource: BackendResourceS3Bucket, Name: "my-state", Setting: "object_lock", Expected: "enabled, GOVERNANCE retention of 30 days"},
+	}, drifts)
+}
@@ -6,6 +6,7 @@ import (
 	"github.com/aws/aws-sdk-go/service/s3"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/aws_helper"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
@@ -453,3 +454,130 @@ func TestNegativePublicAccessResponse(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateS3ConfigSecurityBaseline(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+
+	baseConfig := map[string]interface{}{"bucket": "my-state", "key": "terraform.tfstate", "region": "us-east-1", "encrypt": true}
+
+	testCases := []struct {
+		name     string
+		config   map[string]interface{}
+		expected error
+	}{
+		{
+			"valid-baseline",
+			map[string]interface{}{"enable_bucket_deny_unencrypted_put": true, "enable_bucket_object_lock": true, "bucket_object_lock_retention_mode": "GOVERNANCE", "bucket_object_lock_retention_days": 30},
+			nil,
+		},
+		{
+			"deny-unencrypted-put-without-encrypt",
+			map[string]interface{}{"enable_bucket_deny_unencrypted_put": true, "encrypt": false},
+			InvalidS3RemoteStateConfig{Name: "enable_bucket_deny_unencrypted_put", Reason: "requires encrypt to be true"},
+		},
+		{
+			"object-lock-without-versioning",
+			map[string]interface{}{"enable_bucket_object_lock": true, "skip_bucket_versioning": true},
+			InvalidS3RemoteStateConfig{Name: "enable_bucket_object_lock", Reason: "requires the bucket to be versioned, but skip_bucket_versioning is true"},
+		},
+		{
+			"retention-without-object-lock",
+			map[string]interface{}{"bucket_object_lock_retention_mode": "GOVERNANCE", "bucket_object_lock_retention_days": 30},
+			InvalidS3RemoteStateConfig{Name: "bucket_object_lock_retention_mode", Reason: "requires enable_bucket_object_lock to be true"},
+		},
+		{
+			"unknown-retention-mode",
+			map[string]interface{}{"enable_bucket_object_lock": true, "bucket_object_lock_retention_mode": "FOREVER", "bucket_object_lock_retention_days": 30},
+			InvalidS3RemoteStateConfig{Name: "bucket_object_lock_retention_mode", Reason: `must be GOVERNANCE or COMPLIANCE, got "FOREVER"`},
+		},
+		{
+			"retention-mode-without-days",
+			map[string]interface{}{"enable_bucket_object_lock": true, "bucket_object_lock_retention_mode": "COMPLIANCE"},
+			InvalidS3RemoteStateConfig{Name: "bucket_object_lock_retention_days", Reason: "must be a positive number of days"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			config := map[string]interface{}{}
+			for key, value := range baseConfig {
+				config[key] = value
+			}
+			for key, value := range testCase.config {
+				config[key] = value
+			}
+
+			s3ConfigExtended, err := ParseExtendedS3Config(config)
+			require.NoError(t, err)
+
+			err = validateS3Config(s3ConfigExtended, terragruntOptions)
+			if testCase.expected == nil {
+				assert.NoError(t, err)
+			} else {
+				assert.Equal(t, testCase.expected, errors.Unwrap(err))
+			}
+		})
+	}
+}
+
+func TestDenyUnencryptedPutStatement(t *testing.T) {
+	t.Parallel()
+
+	statement := denyUnencryptedPutStatement("aws", "my-state")
+	assert.Equal(t, SidDenyUnencryptedPutPolicy, statement.Sid)
+	assert.Equal(t, "Deny", statement.Effect)
+	assert.Equal(t, "s3:PutObject", statement.Action)
+	assert.Equal(t, []string{"arn:aws:s3:::my-state/*"}, statement.Resource)
+	assert.Equal(t, &map[string]interface{}{"Null": map[string]interface{}{"s3:x-amz-server-side-encryption": "true"}}, statement.Condition)
+}
+
+func TestObjectLockConfigurationMatches(t *testing.T) {
+	t.Parallel()
+
+	withRetention, err := ParseExtendedS3Config(map[string]interface{}{"bucket": "my-state", "enable_bucket_object_lock": true, "bucket_object_lock_retention_mode": "GOVERNANCE", "bucket_object_lock_retention_days": 30})
+	require.NoError(t, err)
+	withoutRetention, err := ParseExtendedS3Config(map[string]interface{}{"bucket": "my-state", "enable_bucket_object_lock": true})
+	require.NoError(t, err)
+
+	live := &s3.ObjectLockConfiguration{
+		ObjectLockEnabled: aws.String(s3.ObjectLockEnabledEnabled),
+		Rule:              &s3.ObjectLockRule{DefaultRetention: &s3.DefaultRetention{Mode: aws.String("GOVERNANCE"), Days: aws.Int64(30)}},
+	}
+	assert.True(t, objectLockConfigurationMatches(live, objectLockConfiguration(withRetention)))
+	assert.True(t, objectLockConfigurationMatches(live, objectLockConfiguration(withoutRetention)))
+
+	live.Rule.DefaultRetention.Days = aws.Int64(7)
+	assert.False(t, objectLockConfigurationMatches(live, objectLockConfiguration(withRetention)))
+
+	assert.False(t, objectLockConfigurationMatches(&s3.ObjectLockConfiguration{}, objectLockConfiguration(withoutRetention)))
+	assert.False(t, objectLockConfigurationMatches(nil, objectLockConfiguration(withoutRetention)))
+}
+
+func TestGetS3Locking(t *testing.T) {
+	t.Parallel()
+
+	lockTable, useLockfile, err := GetS3Locking(map[string]interface{}{"bucket": "my-state", "region": "us-east-1", "use_lockfile": true})
+	require.NoError(t, err)
+	assert.Nil(t, lockTable)
+	assert.True(t, useLockfile)
+
+	// The deprecated lock_table attribute is still taken into account.
+	lockTable, useLockfile, err = GetS3Locking(map[string]interface{}{"bucket": "my-state", "region": "us-east-1", "lock_table": "locks", "dynamodb_endpoint": "http://localhost:8000"})
+	require.NoError(t, err)
+	require.NotNil(t, lockTable)
+	assert.Equal(t, "locks", lockTable.Name)
+	assert.Equal(t, "locks (us-east-1, http://localhost:8000)", lockTable.String())
+	assert.False(t, useLockfile)
+
+	// The lockfile is a setting of the backend, so it is passed to terraform.
+	args := S3Initializer{}.GetTerraformInitArgs(map[string]interface{}{"bucket": "my-state", "use_lockfile": true})
+	assert.Equal(t, true, args["use_lockfile"])
+}
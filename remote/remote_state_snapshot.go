@@ -0,0 +1,196 @@
+package remote
+
+import (
+	"bytes"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/gruntwork-io/go-commons/errors"
+
+	"github.com/gruntwork-io/terragrunt/aws_helper"
+	"github.com/gruntwork-io/terragrunt/options"
+)
+
+const (
+	// The format of the timestamps in the names of the snapshots, which sort in the order the snapshots were taken.
+	snapshotTimestampFormat = "20060102T150405Z"
+	snapshotSuffix          = ".tfstate"
+)
+
+// Configuration for copying the state to a secondary S3 bucket after every successful apply, which keeps timestamped
+// snapshots of the state independently of the backend.
+type RemoteStateSnapshot struct {
+	Bucket         string `cty:"bucket" mapstructure:"bucket"`
+	Prefix         string `cty:"prefix" mapstructure:"prefix"`
+	Region         string `cty:"region" mapstructure:"region"`
+	Profile        string `cty:"profile" mapstructure:"profile"`
+	RoleArn        string `cty:"role_arn" mapstructure:"role_arn"`
+	KMSKeyID       string `cty:"kms_key_id" mapstructure:"kms_key_id"`
+	RetentionCount int    `cty:"retention_count" mapstructure:"retention_count"`
+	RetentionDays  int    `cty:"retention_days" mapstructure:"retention_days"`
+}
+
+func (snapshot *RemoteStateSnapshot) String() string {
+	return fmt.Sprintf("s3://%s/%s", snapshot.Bucket, snapshot.prefix())
+}
+
+// Validate that the snapshot is configured correctly
+func (snapshot *RemoteStateSnapshot) Validate() error {
+	if snapshot.Bucket == "" {
+		return errors.WithStackTrace(MissingRequiredSnapshotConfig("bucket"))
+	}
+
+	// The snapshots of the modules sharing a bucket are told apart by their prefix, so that the retention of a module
+	// never deletes the snapshots of another one.
+	if snapshot.prefix() == "" || snapshot.prefix() == "." {
+		return errors.WithStackTrace(MissingRequiredSnapshotConfig("prefix"))
+	}
+
+	if snapshot.Region == "" {
+		return errors.WithStackTrace(MissingRequiredSnapshotConfig("region"))
+	}
+
+	if snapshot.RetentionCount < 0 {
+		return errors.WithStackTrace(InvalidSnapshotConfig{Name: "retention_count", Reason: "must not be negative"})
+	}
+
+	if snapshot.RetentionDays < 0 {
+		return errors.WithStackTrace(InvalidSnapshotConfig{Name: "retention_days", Reason: "must not be negative"})
+	}
+
+	return nil
+}
+
+// SaveStateSnapshot uploads the given state to a new timestamped snapshot in the snapshot bucket, then deletes the
+// snapshots that are past the retention.
+func (snapshot *RemoteStateSnapshot) SaveStateSnapshot(state []byte, terragruntOptions *options.TerragruntOptions) error {
+	s3Client, err := CreateS3Client(&aws_helper.AwsSessionConfig{Region: snapshot.Region, Profile: snapshot.Profile, RoleArn: snapshot.RoleArn}, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	key := snapshot.key(now)
+
+	input := &s3.PutObjectInput{
+		Bucket:               aws.String(snapshot.Bucket),
+		Key:                  aws.String(key),
+		Body:                 bytes.NewReader(state),
+		ContentType:          aws.String("application/json"),
+		ServerSideEncryption: aws.String(s3.ServerSideEncryptionAes256),
+	}
+	// The state may contain secrets, so the snapshots are always encrypted.
+	if snapshot.KMSKeyID != "" {
+		input.ServerSideEncryption = aws.String(s3.ServerSideEncryptionAwsKms)
+		input.SSEKMSKeyId = aws.String(snapshot.KMSKeyID)
+	}
+
+	if _, err := s3Client.PutObject(input); err != nil {
+		return errors.WithStackTrace(err)
+	}
+	terragruntOptions.Logger.Infof("Saved a snapshot of the state to s3://%s/%s", snapshot.Bucket, key)
+
+	if snapshot.RetentionCount == 0 && snapshot.RetentionDays == 0 {
+		return nil
+	}
+
+	snapshots := []stateSnapshotObject{}
+	listInput := &s3.ListObjectsV2Input{Bucket: aws.String(snapshot.Bucket), Prefix: aws.String(snapshot.listPrefix())}
+	err = s3Client.ListObjectsV2Pages(listInput, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			snapshots = append(snapshots, stateSnapshotObject{Key: aws.StringValue(object.Key), LastModified: aws.TimeValue(object.LastModified)})
+		}
+		return true
+	})
+	if err != nil {
+		return errors.WithStackTrace(err)
+	}
+
+	for _, expiredKey := range snapshot.expiredSnapshots(snapshots, now) {
+		terragruntOptions.Logger.Debugf("Deleting expired state snapshot s3://%s/%s", snapshot.Bucket, expiredKey)
+		if _, err := s3Client.DeleteObject(&s3.DeleteObjectInput{Bucket: aws.String(snapshot.Bucket), Key: aws.String(expiredKey)}); err != nil {
+			return errors.WithStackTrace(err)
+		}
+	}
+
+	return nil
+}
+
+// stateSnapshotObject is an object in the snapshot bucket.
+type stateSnapshotObject struct {
+	Key          string
+	LastModified time.Time
+}
+
+// expiredSnapshots returns the keys of the given objects that are snapshots past the retention, that is more than
+// retention_count newer snapshots exist, or they were taken more than retention_days ago. The latest snapshot is
+// always kept.
+func (snapshot *RemoteStateSnapshot) expiredSnapshots(objects []stateSnapshotObject, now time.Time) []string {
+	snapshots := []stateSnapshotObject{}
+	for _, object := range objects {
+		name := strings.TrimPrefix(object.Key, snapshot.listPrefix())
+		// Only the snapshots taken by terragrunt are deleted, and not the objects in nested prefixes.
+		if strings.Contains(name, "/") || !strings.HasSuffix(name, snapshotSuffix) {
+			continue
+		}
+		if _, err := time.Parse(snapshotTimestampFormat, strings.TrimSuffix(name, snapshotSuffix)); err != nil {
+			continue
+		}
+		snapshots = append(snapshots, object)
+	}
+
+	// Newest first
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Key > snapshots[j].Key })
+
+	expired := []string{}
+	for i, object := range snapshots {
+		if i == 0 {
+			continue
+		}
+
+		tooMany := snapshot.RetentionCount > 0 && i >= snapshot.RetentionCount
+		tooOld := snapshot.RetentionDays > 0 && now.Sub(object.LastModified) > time.Duration(snapshot.RetentionDays)*24*time.Hour
+		if tooMany || tooOld {
+			expired = append(expired, object.Key)
+		}
+	}
+
+	return expired
+}
+
+func (snapshot *RemoteStateSnapshot) prefix() string {
+	return strings.Trim(snapshot.Prefix, "/")
+}
+
+func (snapshot *RemoteStateSnapshot) listPrefix() string {
+	if snapshot.prefix() == "" {
+		return ""
+	}
+	return snapshot.prefix() + "/"
+}
+
+func (snapshot *RemoteStateSnapshot) key(now time.Time) string {
+	return path.Join(snapshot.prefix(), now.Format(snapshotTimestampFormat)+snapshotSuffix)
+}
+
+// Custom error types
+
+type MissingRequiredSnapshotConfig string
+
+func (configName MissingRequiredSnapshotConfig) Error() string {
+	return fmt.Sprintf("Missing required remote_state snapshot configuration %s", string(configName))
+}
+
+type InvalidSnapshotConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidSnapshotConfig) Error() string {
+	return fmt.Sprintf("Invalid remote_state snapshot configuration %s: %s", err.Name, err.Reason)
+}
@@ -0,0 +1,93 @@
+package remote
+
+import (
+	"testing"
+	"time"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoteStateSnapshotKey(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 1, 12, 30, 5, 0, time.UTC)
+
+	snapshot := &RemoteStateSnapshot{Bucket: "snapshots", Prefix: "/prod/vpc/"}
+	assert.Equal(t, "prod/vpc/20240301T123005Z.tfstate", snapshot.key(now))
+	assert.Equal(t, "s3://snapshots/prod/vpc", snapshot.String())
+
+	snapshot = &RemoteStateSnapshot{Bucket: "snapshots"}
+	assert.Equal(t, "20240301T123005Z.tfstate", snapshot.key(now))
+}
+
+func TestRemoteStateSnapshotExpiredSnapshots(t *testing.T) {
+	t.Parallel()
+
+	now := time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)
+	objects := []stateSnapshotObject{
+		{Key: "vpc/20240301T000000Z.tfstate", LastModified: time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)},
+		{Key: "vpc/20240309T000000Z.tfstate", LastModified: time.Date(2024, 3, 9, 0, 0, 0, 0, time.UTC)},
+		{Key: "vpc/20240305T000000Z.tfstate", LastModified: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		{Key: "vpc/20240310T000000Z.tfstate", LastModified: time.Date(2024, 3, 10, 0, 0, 0, 0, time.UTC)},
+		// These are not snapshots of this module, and are never deleted.
+		{Key: "vpc/notes.txt", LastModified: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{Key: "vpc/subnets/20200101T000000Z.tfstate", LastModified: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	testCases := []struct {
+		name     string
+		snapshot RemoteStateSnapshot
+		expected []string
+	}{
+		{
+			"no-retention",
+			RemoteStateSnapshot{Prefix: "vpc"},
+			[]string{},
+		},
+		{
+			"retention-count",
+			RemoteStateSnapshot{Prefix: "vpc", RetentionCount: 2},
+			[]string{"vpc/20240305T000000Z.tfstate", "vpc/20240301T000000Z.tfstate"},
+		},
+		{
+			"retention-days",
+			RemoteStateSnapshot{Prefix: "vpc", RetentionDays: 7},
+			[]string{"vpc/20240301T000000Z.tfstate"},
+		},
+		{
+			"latest-is-always-kept",
+			RemoteStateSnapshot{Prefix: "vpc", RetentionCount: 1, RetentionDays: 1},
+			[]string{"vpc/20240309T000000Z.tfstate", "vpc/20240305T000000Z.tfstate", "vpc/20240301T000000Z.tfstate"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.expected, testCase.snapshot.expiredSnapshots(objects, now))
+		})
+	}
+}
+
+func TestRemoteStateSnapshotValidate(t *testing.T) {
+	t.Parallel()
+
+	snapshot := &RemoteStateSnapshot{Bucket: "snapshots", Prefix: "prod/vpc", Region: "us-east-1", RetentionCount: 10}
+	assert.NoError(t, snapshot.Validate())
+
+	snapshot = &RemoteStateSnapshot{Prefix: "prod/vpc", Region: "us-east-1"}
+	assert.Equal(t, MissingRequiredSnapshotConfig("bucket"), errors.Unwrap(snapshot.Validate()))
+
+	for _, prefix := range []string{"", "/", "."} {
+		snapshot = &RemoteStateSnapshot{Bucket: "snapshots", Prefix: prefix, Region: "us-east-1", RetentionCount: 10}
+		assert.Equal(t, MissingRequiredSnapshotConfig("prefix"), errors.Unwrap(snapshot.Validate()), prefix)
+	}
+
+	snapshot = &RemoteStateSnapshot{Bucket: "snapshots", Prefix: "prod/vpc", Region: "us-east-1", RetentionDays: -1}
+	assert.Equal(t, InvalidSnapshotConfig{Name: "retention_days", Reason: "must not be negative"}, errors.Unwrap(snapshot.Validate()))
+}
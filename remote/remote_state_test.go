@@ -4,8 +4,10 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/gruntwork-io/go-commons/errors"
 	"github.com/gruntwork-io/terragrunt/options"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 /**
@@ -94,6 +96,18 @@ func TestToTerraformInitArgsUnknownBackend(t *testing.T) {
 	assertTerraformInitArgsEqual(t, args, "-backend-config=encrypt=true -backend-config=bucket=my-bucket -backend-config=key=terraform.tfstate -backend-config=region=us-east-1")
 }
 
+func TestDeleteUnsupportedBackend(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+
+	remoteState := RemoteState{Backend: "local", Config: map[string]interface{}{"path": "terraform.tfstate"}}
+	assert.False(t, remoteState.CanDelete())
+	assert.Equal(t, BackendDeletionNotSupported("local"), errors.Unwrap(remoteState.Delete(terragruntOptions)))
+	assert.True(t, (&RemoteState{Backend: "s3"}).CanDelete())
+}
+
 func TestToTerraformInitArgsInitDisabled(t *testing.T) {
 	t.Parallel()
 
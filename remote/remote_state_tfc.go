@@ -0,0 +1,507 @@
+package remote
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/mitchellh/mapstructure"
+
+	"github.com/gruntwork-io/terragrunt/codegen"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/gruntwork-io/terragrunt/shell"
+	"github.com/gruntwork-io/terragrunt/util"
+)
+
+const (
+	defaultTFCHostname = "app.terraform.io"
+	tfcContentType     = "application/vnd.api+json"
+	// The prefix of the IDs of the projects, which is used to tell the IDs from the names
+	tfcProjectIDPrefix = "prj-"
+)
+
+// The execution modes of the workspaces created by terragrunt.
+var tfcExecutionModes = []string{"remote", "local", "agent"}
+
+// These are settings that can appear in the remote_state config that are ONLY used by Terragrunt and NOT forwarded
+// to the underlying Terraform backend configuration.
+var terragruntTFCOnlyConfigs = []string{
+	"skip_workspace_creation",
+	"workspace_project",
+	"workspace_tags",
+	"workspace_execution_mode",
+	"workspace_agent_pool_id",
+}
+
+// A representation of the configuration options of the remote and cloud backends, along with the settings terragrunt
+// uses to create the workspace when it doesn't exist
+type RemoteStateConfigTFC struct {
+	Hostname     string                  `mapstructure:"hostname"`
+	Organization string                  `mapstructure:"organization"`
+	Token        string                  `mapstructure:"token"`
+	Workspaces   RemoteStateTFCWorkspace `mapstructure:"workspaces"`
+
+	SkipWorkspaceCreation  bool     `mapstructure:"skip_workspace_creation"`
+	WorkspaceProject       string   `mapstructure:"workspace_project"`
+	WorkspaceTags          []string `mapstructure:"workspace_tags"`
+	WorkspaceExecutionMode string   `mapstructure:"workspace_execution_mode"`
+	WorkspaceAgentPoolID   string   `mapstructure:"workspace_agent_pool_id"`
+}
+
+// The workspaces block of the remote and cloud backends
+type RemoteStateTFCWorkspace struct {
+	Name    string `mapstructure:"name"`
+	Prefix  string `mapstructure:"prefix"`
+	Project string `mapstructure:"project"`
+	// The tags of the cloud backend, which are either a list of tags or a map of key-value tags
+	Tags interface{} `mapstructure:"tags"`
+}
+
+type TFCInitializer struct{}
+
+// Returns true if the backend type or any of the backend settings are different than the current config. The
+// workspace is only looked up in Terraform Cloud when initializing, so that the API is not called on every command.
+func (tfcInitializer TFCInitializer) NeedsInitialization(remoteState *RemoteState, existingBackend *TerraformBackend, terragruntOptions *options.TerragruntOptions) (bool, error) {
+	if remoteState.DisableInit {
+		return false, nil
+	}
+
+	if existingBackend == nil {
+		return true, nil
+	}
+
+	if existingBackend.Type != remoteState.Backend {
+		terragruntOptions.Logger.Debugf("Backend type has changed from %s to %s", existingBackend.Type, remoteState.Backend)
+		return true, nil
+	}
+
+	config := tfcInitializer.GetTerraformInitArgs(remoteState.Config)
+	if !terraformStateConfigEqual(stripNilValues(existingBackend.Config), config) {
+		terragruntOptions.Logger.Debugf("Backend config changed from %s to %s", existingBackend.Config, config)
+		return true, nil
+	}
+
+	return false, nil
+}
+
+// Create the workspace of the remote or cloud backend in Terraform Cloud or Enterprise if it doesn't already exist, with
+// the project, tags and execution mode of the config.
+func (tfcInitializer TFCInitializer) Initialize(remoteState *RemoteState, terragruntOptions *options.TerragruntOptions) error {
+	tfcConfig, err := parseTFCConfig(remoteState.Config, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if err := validateTFCConfig(tfcConfig); err != nil {
+		return err
+	}
+
+	if tfcConfig.SkipWorkspaceCreation {
+		return nil
+	}
+
+	workspaceName := tfcWorkspaceName(remoteState.Backend, tfcConfig, terragruntOptions)
+	if workspaceName == "" {
+		terragruntOptions.Logger.Debugf("The %s backend does not select a single workspace, so Terragrunt will not create it", remoteState.Backend)
+		return nil
+	}
+
+	token, err := tfcToken(tfcConfig, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	client := &tfcClient{baseURL: "https://" + tfcConfig.Hostname, token: token, httpClient: http.DefaultClient}
+
+	return createTFCWorkspaceIfNecessary(client, tfcConfig, workspaceName, tfcWorkspaceTags(tfcConfig), terragruntOptions)
+}
+
+func (tfcInitializer TFCInitializer) GetTerraformInitArgs(config map[string]interface{}) map[string]interface{} {
+	var filteredConfig = make(map[string]interface{})
+
+	for key, val := range config {
+		if util.ListContainsElement(terragruntTFCOnlyConfigs, key) {
+			continue
+		}
+
+		filteredConfig[key] = val
+	}
+
+	return filteredConfig
+}
+
+// Parse the given map into a remote or cloud backend config. As with the backends, the hostname and organization that
+// are not set in the config are read from the TF_CLOUD_* env vars.
+func parseTFCConfig(config map[string]interface{}, terragruntOptions *options.TerragruntOptions) (*RemoteStateConfigTFC, error) {
+	var tfcConfig RemoteStateConfigTFC
+
+	if err := mapstructure.Decode(config, &tfcConfig); err != nil {
+		return nil, errors.WithStackTrace(err)
+	}
+
+	if tfcConfig.Hostname == "" {
+		tfcConfig.Hostname = terragruntOptions.Env["TF_CLOUD_HOSTNAME"]
+	}
+	if tfcConfig.Hostname == "" {
+		tfcConfig.Hostname = defaultTFCHostname
+	}
+
+	if tfcConfig.Organization == "" {
+		tfcConfig.Organization = terragruntOptions.Env["TF_CLOUD_ORGANIZATION"]
+	}
+
+	return &tfcConfig, nil
+}
+
+// Validate all the parameters of the given remote or cloud backend configuration
+func validateTFCConfig(config *RemoteStateConfigTFC) error {
+	if config.Organization == "" {
+		return errors.WithStackTrace(MissingRequiredTFCRemoteStateConfig("organization"))
+	}
+
+	if config.WorkspaceExecutionMode != "" && !util.ListContainsElement(tfcExecutionModes, config.WorkspaceExecutionMode) {
+		return errors.WithStackTrace(InvalidTFCRemoteStateConfig{Name: "workspace_execution_mode", Reason: fmt.Sprintf("must be one of %s, got %q", strings.Join(tfcExecutionModes, ", "), config.WorkspaceExecutionMode)})
+	}
+
+	// The workspaces in agent mode run in an agent pool.
+	if config.WorkspaceExecutionMode == "agent" && config.WorkspaceAgentPoolID == "" {
+		return errors.WithStackTrace(MissingRequiredTFCRemoteStateConfig("workspace_agent_pool_id"))
+	}
+
+	return nil
+}
+
+// tfcWorkspaceName returns the name of the workspace the backend uses, which is empty when the backend selects the
+// workspace at run time among several ones, by prefix or tags, and TF_WORKSPACE is not set.
+func tfcWorkspaceName(backend string, config *RemoteStateConfigTFC, terragruntOptions *options.TerragruntOptions) string {
+	if config.Workspaces.Name != "" {
+		return config.Workspaces.Name
+	}
+
+	selectedWorkspace := terragruntOptions.Env["TF_WORKSPACE"]
+	if selectedWorkspace == "" {
+		return ""
+	}
+
+	if config.Workspaces.Prefix != "" {
+		return config.Workspaces.Prefix + selectedWorkspace
+	}
+
+	if backend == codegen.BackendCloud {
+		return selectedWorkspace
+	}
+
+	return ""
+}
+
+// tfcWorkspaceTags returns the tags of the workspace to create, which include the tags the cloud backend selects the
+// workspaces by, so that it can find the new workspace.
+func tfcWorkspaceTags(config *RemoteStateConfigTFC) []string {
+	tags := append([]string{}, config.WorkspaceTags...)
+
+	if backendTags, isList := config.Workspaces.Tags.([]interface{}); isList {
+		for _, tag := range backendTags {
+			if tagName, isString := tag.(string); isString && !util.ListContainsElement(tags, tagName) {
+				tags = append(tags, tagName)
+			}
+		}
+	}
+
+	return tags
+}
+
+// tfcToken returns the API token of the Terraform Cloud or Enterprise host, which is read from the token setting, the
+// TF_TOKEN_<hostname> env var, or the credentials file of `terraform login`, the same way Terraform does.
+func tfcToken(config *RemoteStateConfigTFC, terragruntOptions *options.TerragruntOptions) (string, error) {
+	if config.Token != "" {
+		return config.Token, nil
+	}
+
+	envName := "TF_TOKEN_" + strings.NewReplacer("-", "__", ".", "_").Replace(config.Hostname)
+	if token := terragruntOptions.Env[envName]; token != "" {
+		return token, nil
+	}
+
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", errors.WithStackTrace(MissingTFCToken(config.Hostname))
+	}
+
+	credentialsPath := filepath.Join(homeDir, ".terraform.d", "credentials.tfrc.json")
+	if !util.FileExists(credentialsPath) {
+		return "", errors.WithStackTrace(MissingTFCToken(config.Hostname))
+	}
+
+	credentialsFile, err := os.ReadFile(credentialsPath)
+	if err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	var credentials struct {
+		Credentials map[string]struct {
+			Token string `json:"token"`
+		} `json:"credentials"`
+	}
+	if err := json.Unmarshal(credentialsFile, &credentials); err != nil {
+		return "", errors.WithStackTrace(err)
+	}
+
+	if token := credentials.Credentials[config.Hostname].Token; token != "" {
+		return token, nil
+	}
+
+	return "", errors.WithStackTrace(MissingTFCToken(config.Hostname))
+}
+
+// createTFCWorkspaceIfNecessary creates the given workspace if it doesn't exist in the organization of the config.
+func createTFCWorkspaceIfNecessary(client *tfcClient, config *RemoteStateConfigTFC, workspaceName string, tags []string, terragruntOptions *options.TerragruntOptions) error {
+	exists, err := client.workspaceExists(config.Organization, workspaceName)
+	if err != nil {
+		return err
+	}
+
+	if exists {
+		terragruntOptions.Logger.Debugf("Workspace %s already exists in organization %s", workspaceName, config.Organization)
+		return nil
+	}
+
+	if terragruntOptions.FailIfBucketCreationRequired {
+		return BucketCreationNotAllowed(workspaceName)
+	}
+
+	prompt := fmt.Sprintf("Workspace %s does not exist in organization %s on %s or you don't have permissions to access it. Would you like Terragrunt to create it?", workspaceName, config.Organization, config.Hostname)
+	shouldCreate, err := shell.PromptUserForYesNo(prompt, terragruntOptions)
+	if err != nil {
+		return err
+	}
+
+	if !shouldCreate {
+		return nil
+	}
+
+	attributes := tfcWorkspaceAttributes{
+		Name:          workspaceName,
+		ExecutionMode: config.WorkspaceExecutionMode,
+		AgentPoolID:   config.WorkspaceAgentPoolID,
+		TagNames:      tags,
+	}
+
+	// The project of the workspaces block of the cloud backend is the one the workspace is looked up in.
+	project := config.WorkspaceProject
+	if project == "" {
+		project = config.Workspaces.Project
+	}
+
+	projectID := project
+	if project != "" && !strings.HasPrefix(project, tfcProjectIDPrefix) {
+		if projectID, err = client.projectID(config.Organization, project); err != nil {
+			return err
+		}
+	}
+
+	terragruntOptions.Logger.Infof("Creating workspace %s in organization %s on %s", workspaceName, config.Organization, config.Hostname)
+	return client.createWorkspace(config.Organization, attributes, projectID)
+}
+
+// tfcClient is a client of the API of Terraform Cloud or Enterprise.
+type tfcClient struct {
+	baseURL    string
+	token      string
+	httpClient *http.Client
+}
+
+type tfcWorkspaceAttributes struct {
+	Name          string   `json:"name"`
+	ExecutionMode string   `json:"execution-mode,omitempty"`
+	AgentPoolID   string   `json:"agent-pool-id,omitempty"`
+	TagNames      []string `json:"tag-names,omitempty"`
+}
+
+type tfcRelationship struct {
+	Data tfcResource `json:"data"`
+}
+
+type tfcResource struct {
+	Type string `json:"type"`
+	ID   string `json:"id,omitempty"`
+}
+
+// workspaceExists returns true if the given workspace exists in the given organization.
+func (client *tfcClient) workspaceExists(organization string, workspaceName string) (bool, error) {
+	statusCode, err := client.do(http.MethodGet, fmt.Sprintf("/api/v2/organizations/%s/workspaces/%s", url.PathEscape(organization), url.PathEscape(workspaceName)), nil, nil)
+	if err != nil {
+		return false, err
+	}
+
+	return statusCode != http.StatusNotFound, nil
+}
+
+// projectID returns the ID of the project with the given name in the given organization.
+func (client *tfcClient) projectID(organization string, projectName string) (string, error) {
+	var response struct {
+		Data []struct {
+			ID         string `json:"id"`
+			Attributes struct {
+				Name string `json:"name"`
+			} `json:"attributes"`
+		} `json:"data"`
+	}
+
+	path := fmt.Sprintf("/api/v2/organizations/%s/projects?%s", url.PathEscape(organization), url.Values{"filter[names]": {projectName}}.Encode())
+	statusCode, err := client.do(http.MethodGet, path, nil, &response)
+	if err != nil {
+		return "", err
+	}
+
+	if statusCode != http.StatusNotFound {
+		for _, project := range response.Data {
+			if project.Attributes.Name == projectName {
+				return project.ID, nil
+			}
+		}
+	}
+
+	return "", errors.WithStackTrace(TFCProjectNotFound{Organization: organization, Project: projectName})
+}
+
+// createWorkspace creates a workspace with the given attributes in the given organization and project. A workspace
+// that was created in the meantime, e.g. by another module of run-all, is not an error.
+func (client *tfcClient) createWorkspace(organization string, attributes tfcWorkspaceAttributes, projectID string) error {
+	data := map[string]interface{}{
+		"type":       "workspaces",
+		"attributes": attributes,
+	}
+	if projectID != "" {
+		data["relationships"] = map[string]interface{}{
+			"project": tfcRelationship{Data: tfcResource{Type: "projects", ID: projectID}},
+		}
+	}
+
+	statusCode, err := client.do(http.MethodPost, fmt.Sprintf("/api/v2/organizations/%s/workspaces", url.PathEscape(organization)), map[string]interface{}{"data": data}, nil)
+	if err != nil {
+		return err
+	}
+
+	if statusCode == http.StatusUnprocessableEntity {
+		exists, err := client.workspaceExists(organization, attributes.Name)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return errors.WithStackTrace(TFCRequestFailed{Method: http.MethodPost, Path: fmt.Sprintf("/api/v2/organizations/%s/workspaces", organization), StatusCode: statusCode})
+		}
+	}
+
+	return nil
+}
+
+// do sends a request to the API and decodes the response into out, if it is set. The status code is returned along
+// with the response, as a 404 or 422 may be expected by the caller, while the other errors are returned as errors.
+func (client *tfcClient) do(method string, path string, body interface{}, out interface{}) (int, error) {
+	var requestBody io.Reader
+	if body != nil {
+		bodyBytes, err := json.Marshal(body)
+		if err != nil {
+			return 0, errors.WithStackTrace(err)
+		}
+		requestBody = bytes.NewReader(bodyBytes)
+	}
+
+	request, err := http.NewRequest(method, client.baseURL+path, requestBody)
+	if err != nil {
+		return 0, errors.WithStackTrace(err)
+	}
+	request.Header.Set("Authorization", "Bearer "+client.token)
+	request.Header.Set("Content-Type", tfcContentType)
+
+	response, err := client.httpClient.Do(request)
+	if err != nil {
+		return 0, errors.WithStackTrace(err)
+	}
+	defer response.Body.Close()
+
+	switch {
+	case response.StatusCode == http.StatusNotFound || response.StatusCode == http.StatusUnprocessableEntity:
+		return response.StatusCode, nil
+	case response.StatusCode >= http.StatusBadRequest:
+		return response.StatusCode, errors.WithStackTrace(TFCRequestFailed{Method: method, Path: path, StatusCode: response.StatusCode})
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(response.Body).Decode(out); err != nil {
+			return response.StatusCode, errors.WithStackTrace(err)
+		}
+	}
+
+	return response.StatusCode, nil
+}
+
+// stripNilValues returns the given backend config without the nil values, including the ones of the nested blocks,
+// as Terraform stores all the possible settings of the workspaces block in the .tfstate file.
+func stripNilValues(config map[string]interface{}) map[string]interface{} {
+	if config == nil {
+		return nil
+	}
+
+	stripped := map[string]interface{}{}
+	for key, value := range config {
+		if value == nil {
+			continue
+		}
+		if nested, isMap := value.(map[string]interface{}); isMap {
+			value = stripNilValues(nested)
+		}
+		stripped[key] = value
+	}
+
+	return stripped
+}
+
+// Custom error types
+
+type MissingRequiredTFCRemoteStateConfig string
+
+func (configName MissingRequiredTFCRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Missing required Terraform Cloud remote state configuration %s", string(configName))
+}
+
+type InvalidTFCRemoteStateConfig struct {
+	Name   string
+	Reason string
+}
+
+func (err InvalidTFCRemoteStateConfig) Error() string {
+	return fmt.Sprintf("Invalid Terraform Cloud remote state configuration %s: %s", err.Name, err.Reason)
+}
+
+type MissingTFCToken string
+
+func (hostname MissingTFCToken) Error() string {
+	return fmt.Sprintf("No API token found for %s. Set the token in the remote_state config, set the TF_TOKEN_* env var of the host, or run `terraform login %s`.", string(hostname), string(hostname))
+}
+
+type TFCProjectNotFound struct {
+	Organization string
+	Project      string
+}
+
+func (err TFCProjectNotFound) Error() string {
+	return fmt.Sprintf("Project %s does not exist in organization %s", err.Project, err.Organization)
+}
+
+type TFCRequestFailed struct {
+	Method     string
+	Path       string
+	StatusCode int
+}
+
+func (err TFCRequestFailed) Error() string {
+	return fmt.Sprintf("Terraform Cloud API request %s %s failed with status code %d", err.Method, err.Path, err.StatusCode)
+}
@@ -0,0 +1,143 @@
+package remote
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gruntwork-io/go-commons/errors"
+	"github.com/gruntwork-io/terragrunt/options"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTFCWorkspaceName(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		backend  string
+		config   RemoteStateConfigTFC
+		env      map[string]string
+		expected string
+	}{
+		{"name", "remote", RemoteStateConfigTFC{Workspaces: RemoteStateTFCWorkspace{Name: "vpc"}}, map[string]string{"TF_WORKSPACE": "prod"}, "vpc"},
+		{"prefix", "remote", RemoteStateConfigTFC{Workspaces: RemoteStateTFCWorkspace{Prefix: "vpc-"}}, map[string]string{"TF_WORKSPACE": "prod"}, "vpc-prod"},
+		{"prefix-without-selected-workspace", "remote", RemoteStateConfigTFC{Workspaces: RemoteStateTFCWorkspace{Prefix: "vpc-"}}, map[string]string{}, ""},
+		{"cloud-tags", "cloud", RemoteStateConfigTFC{Workspaces: RemoteStateTFCWorkspace{Tags: []interface{}{"vpc"}}}, map[string]string{"TF_WORKSPACE": "vpc-prod"}, "vpc-prod"},
+		{"remote-without-workspaces", "remote", RemoteStateConfigTFC{}, map[string]string{"TF_WORKSPACE": "prod"}, ""},
+	}
+
+	for _, testCase := range testCases {
+		// Save the testCase in local scope so all the t.Run calls don't end up with the last item in the list
+		testCase := testCase
+
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+			require.NoError(t, err)
+			terragruntOptions.Env = testCase.env
+
+			assert.Equal(t, testCase.expected, tfcWorkspaceName(testCase.backend, &testCase.config, terragruntOptions))
+		})
+	}
+}
+
+func TestValidateTFCConfig(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, validateTFCConfig(&RemoteStateConfigTFC{Organization: "my-org", WorkspaceExecutionMode: "local"}))
+	assert.Equal(t, MissingRequiredTFCRemoteStateConfig("organization"), errors.Unwrap(validateTFCConfig(&RemoteStateConfigTFC{})))
+	assert.Equal(t, MissingRequiredTFCRemoteStateConfig("workspace_agent_pool_id"), errors.Unwrap(validateTFCConfig(&RemoteStateConfigTFC{Organization: "my-org", WorkspaceExecutionMode: "agent"})))
+
+	err := validateTFCConfig(&RemoteStateConfigTFC{Organization: "my-org", WorkspaceExecutionMode: "cloud"})
+	assert.IsType(t, InvalidTFCRemoteStateConfig{}, errors.Unwrap(err))
+}
+
+func TestTFCNeedsInitialization(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+
+	remoteState := &RemoteState{
+		Backend: "remote",
+		Config: map[string]interface{}{
+			"organization":             "my-org",
+			"workspaces":               map[string]interface{}{"name": "vpc"},
+			"workspace_execution_mode": "local",
+		},
+	}
+
+	needsInit, err := TFCInitializer{}.NeedsInitialization(remoteState, nil, terragruntOptions)
+	require.NoError(t, err)
+	assert.True(t, needsInit)
+
+	// Terraform stores the settings that are not set as null.
+	existingBackend := &TerraformBackend{
+		Type:   "remote",
+		Config: map[string]interface{}{"organization": "my-org", "hostname": nil, "workspaces": map[string]interface{}{"name": "vpc", "prefix": nil}},
+	}
+	needsInit, err = TFCInitializer{}.NeedsInitialization(remoteState, existingBackend, terragruntOptions)
+	require.NoError(t, err)
+	assert.False(t, needsInit)
+
+	existingBackend.Config["workspaces"] = map[string]interface{}{"name": "other", "prefix": nil}
+	needsInit, err = TFCInitializer{}.NeedsInitialization(remoteState, existingBackend, terragruntOptions)
+	require.NoError(t, err)
+	assert.True(t, needsInit)
+}
+
+func TestCreateTFCWorkspaceIfNecessary(t *testing.T) {
+	t.Parallel()
+
+	var createRequest map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/organizations/my-org/workspaces/existing":
+			_, _ = w.Write([]byte(`{"data": {"id": "ws-1"}}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/organizations/my-org/workspaces/vpc":
+			w.WriteHeader(http.StatusNotFound)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v2/organizations/my-org/projects":
+			assert.Equal(t, "networking", r.URL.Query().Get("filter[names]"))
+			_, _ = w.Write([]byte(`{"data": [{"id": "prj-123", "attributes": {"name": "networking"}}]}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v2/organizations/my-org/workspaces":
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&createRequest))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"data": {"id": "ws-2"}}`))
+		default:
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("remote_state_test")
+	require.NoError(t, err)
+	terragruntOptions.NonInteractive = true
+
+	client := &tfcClient{baseURL: server.URL, token: "secret", httpClient: server.Client()}
+	config := &RemoteStateConfigTFC{Organization: "my-org", WorkspaceProject: "networking", WorkspaceExecutionMode: "local"}
+
+	require.NoError(t, createTFCWorkspaceIfNecessary(client, config, "existing", nil, terragruntOptions))
+	assert.Nil(t, createRequest)
+
+	require.NoError(t, createTFCWorkspaceIfNecessary(client, config, "vpc", []string{"networking"}, terragruntOptions))
+	assert.Equal(t, map[string]interface{}{
+		"data": map[string]interface{}{
+			"type": "workspaces",
+			"attributes": map[string]interface{}{
+				"name":           "vpc",
+				"execution-mode": "local",
+				"tag-names":      []interface{}{"networking"},
+			},
+			"relationships": map[string]interface{}{
+				"project": map[string]interface{}{"data": map[string]interface{}{"type": "projects", "id": "prj-123"}},
+			},
+		},
+	}, createRequest)
+}
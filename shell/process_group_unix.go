@@ -0,0 +1,19 @@
+//go:build !windows
+// +build !windows
+
+package shell
+
+import (
+	"os/exec"
+	"syscall"
+)
+
+// startInProcessGroup makes the command start in a process group of its own, whose ID is the PID of the command.
+func startInProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+// killProcessGroup kills the process group of the given command, which was started with startInProcessGroup.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+}
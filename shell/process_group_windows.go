@@ -0,0 +1,16 @@
+//go:build windows
+// +build windows
+
+package shell
+
+import (
+	"os/exec"
+)
+
+// startInProcessGroup does nothing on Windows, where the processes started by the command are not killed with it.
+func startInProcessGroup(cmd *exec.Cmd) {}
+
+// killProcessGroup kills the given command.
+func killProcessGroup(cmd *exec.Cmd) error {
+	return cmd.Process.Kill()
+}
@@ -7,8 +7,10 @@ import (
 	"os"
 	"os/exec"
 	"os/signal"
+	"path/filepath"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -86,7 +88,7 @@ func RunShellCommandWithOutput(
 		terraformInitMutex.Lock()
 	}
 
-	terragruntOptions.Logger.Debugf("Running command: %s %s", command, strings.Join(args, " "))
+	terragruntOptions.Logger.Debugf("Running command: %s %s", command, util.RedactSecrets(strings.Join(args, " ")))
 	if suppressStdout {
 		terragruntOptions.Logger.Debugf("Command output will be suppressed.")
 	}
@@ -112,6 +114,25 @@ func RunShellCommandWithOutput(
 		cmd.Dir = workingDir
 	}
 
+	if terragruntOptions.LogFormat == options.LogFormatJSONStream {
+		modulePath := filepath.Dir(terragruntOptions.TerragruntConfigPath)
+		phase := util.FirstArg(args)
+
+		jsonErrWriter := util.JSONStreamWriter(errWriter, modulePath, "stderr", phase)
+		jsonOutWriter := util.JSONStreamWriter(outWriter, modulePath, "stdout", phase)
+		defer func() {
+			if err := jsonErrWriter.Flush(); err != nil {
+				terragruntOptions.Logger.Warnf("Error flushing stderr stream: %v", err)
+			}
+			if err := jsonOutWriter.Flush(); err != nil {
+				terragruntOptions.Logger.Warnf("Error flushing stdout stream: %v", err)
+			}
+		}()
+
+		// The records already carry the module path, so there is no need for the prefix.
+		errWriter, outWriter, prefix = jsonErrWriter, jsonOutWriter, ""
+	}
+
 	// Inspired by https://blog.kowalczyk.info/article/wOYk/advanced-command-execution-in-go-with-osexec.html
 	cmdStderr := io.MultiWriter(withPrefix(errWriter, prefix), &stderrBuf)
 	var cmdStdout io.Writer
@@ -121,6 +142,13 @@ func RunShellCommandWithOutput(
 		cmdStdout = io.MultiWriter(&stdoutBuf)
 	}
 
+	if !terragruntOptions.Deadline.IsZero() && !time.Now().Before(terragruntOptions.Deadline) {
+		return nil, errors.WithStackTrace(TimeoutExceeded{Command: command, Args: args, WorkingDir: cmd.Dir})
+	}
+
+	// Set when the command is killed because the deadline of the module passed while it was running.
+	var timedOut atomic.Bool
+
 	// If we need to allocate a ptty for the command, route through the ptty routine. Otherwise, directly call the
 	// command.
 	if allocatePseudoTty {
@@ -131,10 +159,29 @@ func RunShellCommandWithOutput(
 		cmd.Stdin = os.Stdin
 		cmd.Stdout = cmdStdout
 		cmd.Stderr = cmdStderr
+		if terragruntOptions.KillProcessGroup {
+			startInProcessGroup(cmd)
+		}
 		if err := cmd.Start(); err != nil {
 			// bad path, binary not executable, &c
 			return nil, errors.WithStackTrace(err)
 		}
+
+		// Commands that run with a ptty are interactive, so the deadline is only enforced for the others.
+		if !terragruntOptions.Deadline.IsZero() {
+			timer := time.AfterFunc(time.Until(terragruntOptions.Deadline), func() {
+				timedOut.Store(true)
+				terragruntOptions.Logger.Errorf("Timed out, killing command: %s %s", command, strings.Join(args, " "))
+				kill := cmd.Process.Kill
+				if terragruntOptions.KillProcessGroup {
+					kill = func() error { return killProcessGroup(cmd) }
+				}
+				if err := kill(); err != nil {
+					terragruntOptions.Logger.Errorf("Error killing command: %v", err)
+				}
+			})
+			defer timer.Stop()
+		}
 	}
 
 	// Make sure to forward signals to the subcommand.
@@ -155,6 +202,10 @@ func RunShellCommandWithOutput(
 		Stderr: stderrBuf.String(),
 	}
 
+	if timedOut.Load() {
+		return &cmdOutput, errors.WithStackTrace(TimeoutExceeded{Command: command, Args: args, WorkingDir: cmd.Dir})
+	}
+
 	if err != nil {
 		err = ProcessExecutionError{
 			Err:        err,
@@ -295,6 +346,58 @@ func GitTopLevelDir(terragruntOptions *options.TerragruntOptions, path string) (
 	return strings.TrimSpace(cmd.Stdout), nil
 }
 
+// GitChangedFilesSince - fetch the absolute paths of the files of the git repository of the passed directory that
+// changed since the given ref. The changes are computed from the merge base of the ref and HEAD, so that changes made
+// on the ref after the current branch was created are not included. Uncommitted and untracked files are included.
+func GitChangedFilesSince(terragruntOptions *options.TerragruntOptions, path string, ref string) ([]string, error) {
+	topLevelDir, err := GitTopLevelDir(terragruntOptions, path)
+	if err != nil {
+		return nil, err
+	}
+
+	mergeBase, err := runGitCommand(terragruntOptions, topLevelDir, "merge-base", ref, "HEAD")
+	if err != nil {
+		return nil, err
+	}
+	changed, err := runGitCommand(terragruntOptions, topLevelDir, "diff", "--name-only", strings.TrimSpace(mergeBase))
+	if err != nil {
+		return nil, err
+	}
+	untracked, err := runGitCommand(terragruntOptions, topLevelDir, "ls-files", "--others", "--exclude-standard", "--full-name")
+	if err != nil {
+		return nil, err
+	}
+
+	files := []string{}
+	for _, file := range strings.Split(changed+"\n"+untracked, "\n") {
+		file = strings.TrimSpace(file)
+		if file == "" {
+			continue
+		}
+		files = append(files, filepath.Join(topLevelDir, filepath.FromSlash(file)))
+	}
+	return files, nil
+}
+
+// runGitCommand - run git with the given args in the passed directory, without writing its output to the terminal
+func runGitCommand(terragruntOptions *options.TerragruntOptions, path string, args ...string) (string, error) {
+	stdout := bytes.Buffer{}
+	stderr := bytes.Buffer{}
+	opts, err := options.NewTerragruntOptionsWithConfigPath(path)
+	if err != nil {
+		return "", err
+	}
+	opts.Env = terragruntOptions.Env
+	opts.Writer = &stdout
+	opts.ErrWriter = &stderr
+	cmd, err := RunShellCommandWithOutput(opts, path, true, false, "git", args...)
+	if err != nil {
+		terragruntOptions.Logger.Debugf("git %s result: \n%v\n", strings.Join(args, " "), stderr.String())
+		return "", err
+	}
+	return cmd.Stdout, nil
+}
+
 // ProcessExecutionError - error returned when a command fails, contains StdOut and StdErr
 type ProcessExecutionError struct {
 	Err        error
@@ -311,3 +414,14 @@ func (err ProcessExecutionError) Error() string {
 func (err ProcessExecutionError) ExitStatus() (int, error) {
 	return GetExitCode(err.Err)
 }
+
+// TimeoutExceeded - error returned when a command is not run, or is killed, because the timeout of the module passed
+type TimeoutExceeded struct {
+	Command    string
+	Args       []string
+	WorkingDir string
+}
+
+func (err TimeoutExceeded) Error() string {
+	return fmt.Sprintf("[%s] %s %s exceeded the timeout of the module", err.WorkingDir, err.Command, strings.Join(err.Args, " "))
+}
@@ -143,3 +143,48 @@ func TestRunShellCommandWithOutputInterrupt(t *testing.T) {
 	expectedErr := fmt.Sprintf("[.] exit status %d", expectedWait)
 	assert.EqualError(t, <-errCh, expectedErr)
 }
+
+func TestRunShellCommandKilledAfterDeadline(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	assert.Nil(t, err, "Unexpected error creating NewTerragruntOptionsForTest: %v", err)
+	terragruntOptions.Deadline = time.Now().Add(500 * time.Millisecond)
+
+	start := time.Now()
+	err = RunShellCommand(terragruntOptions, "sleep", "30")
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	var timeoutErr TimeoutExceeded
+	assert.True(t, goerrors.As(err, &timeoutErr), "Expected TimeoutExceeded but got %v", err)
+}
+
+func TestRunShellCommandNotStartedAfterDeadline(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	assert.Nil(t, err, "Unexpected error creating NewTerragruntOptionsForTest: %v", err)
+	terragruntOptions.Deadline = time.Now().Add(-time.Second)
+
+	err = RunShellCommand(terragruntOptions, "true")
+
+	var timeoutErr TimeoutExceeded
+	assert.True(t, goerrors.As(err, &timeoutErr), "Expected TimeoutExceeded but got %v", err)
+}
+
+func TestRunShellCommandKillsProcessGroupAfterDeadline(t *testing.T) {
+	t.Parallel()
+
+	terragruntOptions, err := options.NewTerragruntOptionsForTest("")
+	assert.Nil(t, err, "Unexpected error creating NewTerragruntOptionsForTest: %v", err)
+	terragruntOptions.Deadline = time.Now().Add(500 * time.Millisecond)
+	terragruntOptions.KillProcessGroup = true
+
+	// The background sleep keeps the output of the command open, so the command only finishes once it is killed too.
+	start := time.Now()
+	err = RunShellCommand(terragruntOptions, "sh", "-c", "sleep 30 & sleep 30")
+	assert.Less(t, time.Since(start), 10*time.Second)
+
+	var timeoutErr TimeoutExceeded
+	assert.True(t, goerrors.As(err, &timeoutErr), "Expected TimeoutExceeded but got %v", err)
+}
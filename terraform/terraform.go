@@ -27,3 +27,21 @@ func ModuleVariables(modulePath string) ([]string, []string, error) {
 	}
 	return required, optional, nil
 }
+
+// ModuleVariableTypes returns the type constraints of the variables defined in the downloaded terraform modules, as
+// written in their type argument, e.g. "list(string)", by variable name. The variables without a type argument are left
+// out.
+func ModuleVariableTypes(modulePath string) (map[string]string, error) {
+	module, diags := tfconfig.LoadModule(modulePath)
+	if diags.HasErrors() {
+		return nil, errors.WithStackTrace(diags)
+	}
+
+	types := map[string]string{}
+	for _, variable := range module.Variables {
+		if variable.Type != "" {
+			types[variable.Name] = variable.Type
+		}
+	}
+	return types, nil
+}
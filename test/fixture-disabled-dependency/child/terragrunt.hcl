@@ -0,0 +1,18 @@
+include "root" {
+  path           = find_in_parent_folders("root.hcl")
+  merge_strategy = "deep"
+}
+
+locals {
+  env = "dev"
+}
+
+# The vpc of the root config is not deployed in dev.
+dependency "vpc" {
+  config_path = "../vpc"
+  enabled     = local.env != "dev"
+}
+
+inputs = {
+  vpc_id = dependency.vpc.outputs.vpc_id
+}
@@ -0,0 +1,12 @@
+dependency "vpc" {
+  config_path = "../vpc"
+
+  mock_outputs = {
+    vpc_id = "mock-vpc-id"
+  }
+}
+
+dependency "db" {
+  config_path = "../db"
+  enabled     = false
+}
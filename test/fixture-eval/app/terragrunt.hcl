@@ -0,0 +1,17 @@
+include "root" {
+  path   = find_in_parent_folders("root.hcl")
+  expose = true
+}
+
+locals {
+  region = "us-east-1"
+  name   = "app-${include.root.locals.env}"
+}
+
+dependency "vpc" {
+  config_path  = "../vpc"
+  skip_outputs = true
+  mock_outputs = {
+    vpc_id = "vpc-1234"
+  }
+}
@@ -0,0 +1,7 @@
+include "root" {
+  path = find_in_parent_folders("root.hcl")
+}
+
+inputs = {
+  regions = ["eu-west-1", "us-west-2"]
+}
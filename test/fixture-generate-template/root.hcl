@@ -0,0 +1,10 @@
+locals {
+  default_region = "us-east-1"
+}
+
+generate "provider" {
+  path      = "provider.tf"
+  if_exists = "overwrite_terragrunt"
+  template  = true
+  contents  = file("${get_parent_terragrunt_dir()}/provider.tftpl")
+}
@@ -0,0 +1,4 @@
+indent_width               = 4
+align_attributes           = false
+blank_lines_between_blocks = 1
+block_order                = ["include", "terraform", "dependency", "inputs"]
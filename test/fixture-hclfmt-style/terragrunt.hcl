@@ -0,0 +1,22 @@
+inputs = {
+  name = "app"
+  instance_count = 2
+  tags = {
+    Team = "platform"
+  }
+}
+# The module to deploy.
+terraform {
+  source = "git::git@github.com:acme/modules.git//app?ref=v0.1.0"
+}
+
+
+dependency "vpc" {
+  config_path = "../vpc"
+  mock_outputs = {
+    vpc_id = "mock"
+  }
+}
+include "root" {
+  path = find_in_parent_folders()
+}
@@ -0,0 +1,10 @@
+dependency "valid" {
+  config_path = "../valid"
+}
+
+inputs = {
+  vpc_id = dependency.valid.outputs.vpc_id
+  tags = {
+    Name = "${dependency.valid.outputs.name}-dependency"
+  }
+}
@@ -0,0 +1,8 @@
+locals {
+  name = "reference-error"
+}
+
+inputs = {
+  name   = local.nme
+  region = get_env()
+}
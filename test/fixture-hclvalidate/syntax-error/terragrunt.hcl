@@ -0,0 +1,4 @@
+inputs = {
+  name = "syntax-error"
+  count = 
+}
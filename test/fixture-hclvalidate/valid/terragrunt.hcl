@@ -0,0 +1,7 @@
+locals {
+  name = "valid"
+}
+
+inputs = {
+  name = local.name
+}
@@ -3508,6 +3508,8 @@ func TestReadTerragruntConfigFull(t *testing.T) {
 		terraformOut,
 		map[string]interface{}{
 			"source":          "./delorean",
+			"source_mirrors":  nil,
+			"timeout":         nil,
 			"include_in_copy": []interface{}{"time_machine.*"},
 			"extra_arguments": map[string]interface{}{
 				"var-files": map[string]interface{}{
@@ -3523,25 +3525,70 @@ func TestReadTerragruntConfigFull(t *testing.T) {
 			},
 			"before_hook": map[string]interface{}{
 				"before_hook_1": map[string]interface{}{
-					"name":            "before_hook_1",
-					"commands":        []interface{}{"apply", "plan"},
-					"execute":         []interface{}{"touch", "before.out"},
-					"working_dir":     nil,
-					"run_on_error":    true,
-					"suppress_stdout": nil,
+					"name":               "before_hook_1",
+					"commands":           []interface{}{"apply", "plan"},
+					"execute":            []interface{}{"touch", "before.out"},
+					"working_dir":        nil,
+					"run_on_error":       true,
+					"suppress_stdout":    nil,
+					"merge":              nil,
+					"env":                nil,
+					"run_in_background":  nil,
+					"background_timeout": nil,
+					"type":               nil,
+					"url":                nil,
+					"headers":            nil,
+					"payload":            nil,
+					"message":            nil,
+					"channels":           nil,
+					"url_from_env":       nil,
+					"url_from_command":   nil,
+					"export":             nil,
+					"export_json":        nil,
+					"image":              nil,
+					"container_runtime":  nil,
+					"propagate":          nil,
+					"timeout":            nil,
+					"on_timeout":         nil,
+					"kill_process_group": nil,
+					"if_plan":            nil,
 				},
 			},
 			"after_hook": map[string]interface{}{
 				"after_hook_1": map[string]interface{}{
-					"name":            "after_hook_1",
-					"commands":        []interface{}{"apply", "plan"},
-					"execute":         []interface{}{"touch", "after.out"},
-					"working_dir":     nil,
-					"run_on_error":    true,
-					"suppress_stdout": nil,
+					"name":               "after_hook_1",
+					"commands":           []interface{}{"apply", "plan"},
+					"execute":            []interface{}{"touch", "after.out"},
+					"working_dir":        nil,
+					"run_on_error":       true,
+					"suppress_stdout":    nil,
+					"merge":              nil,
+					"env":                nil,
+					"run_in_background":  nil,
+					"background_timeout": nil,
+					"type":               nil,
+					"url":                nil,
+					"headers":            nil,
+					"payload":            nil,
+					"message":            nil,
+					"channels":           nil,
+					"url_from_env":       nil,
+					"url_from_command":   nil,
+					"export":             nil,
+					"export_json":        nil,
+					"image":              nil,
+					"container_runtime":  nil,
+					"propagate":          nil,
+					"timeout":            nil,
+					"on_timeout":         nil,
+					"kill_process_group": nil,
+					"if_plan":            nil,
 				},
 			},
-			"error_hook": map[string]interface{}{},
+			"error_hook":      map[string]interface{}{},
+			"on_success_hook": map[string]interface{}{},
+			"on_failure_hook": map[string]interface{}{},
+			"on_skip_hook":    map[string]interface{}{},
 		},
 	)
 }
@@ -5474,6 +5521,8 @@ func TestRenderJsonMetadataTerraform(t *testing.T) {
 			"extra_arguments": map[string]interface{}{},
 			"include_in_copy": nil,
 			"source":          "../terraform",
+			"source_mirrors":  nil,
+			"timeout":         nil,
 		},
 	}
 
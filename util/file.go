@@ -22,6 +22,10 @@ const TerraformLockFile = ".terraform.lock.hcl"
 
 const TerragruntCacheDir = ".terragrunt-cache"
 
+// The file terragrunt writes into the working dir to declare the variable of terraform console holding the outputs of
+// the dependencies, which is never copied into the cache dir.
+const TerraformConsoleVariablesFile = "terragrunt-console-variables.tf"
+
 // FileOrData will read the contents of the data of the given arg if it is a file, and otherwise return the contents by
 // itself. This will return an error if the given path is a directory.
 func FileOrData(maybePath string) (string, error) {
@@ -203,6 +207,17 @@ func GetPathRelativeTo(path string, basePath string) (string, error) {
 	return filepath.ToSlash(relPath), nil
 }
 
+// GetPathRelativeToIfInside returns the given path relative to the given base path, with forward slashes, if it is
+// inside of it, or the given path as is, with forward slashes, otherwise, e.g. to show the paths of the units relative
+// to the working dir.
+func GetPathRelativeToIfInside(path string, basePath string) string {
+	rel, err := filepath.Rel(basePath, path)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.ToSlash(path)
+	}
+	return filepath.ToSlash(rel)
+}
+
 // Return the contents of the file at the given path as a string
 func ReadFileAsString(path string) (string, error) {
 	bytes, err := os.ReadFile(path)
@@ -361,6 +376,10 @@ func TerragruntExcludes(path string) bool {
 	if filepath.Base(path) == TerraformLockFile {
 		return false
 	}
+	// A console variables file left behind by an interrupted terraform console is not part of the module.
+	if filepath.Base(path) == TerraformConsoleVariablesFile {
+		return true
+	}
 	pathParts := strings.Split(path, string(filepath.Separator))
 	for _, pathPart := range pathParts {
 		if strings.HasPrefix(pathPart, ".") && pathPart != "." && pathPart != ".." {
@@ -128,6 +128,8 @@ func TestPathContainsHiddenFileOrFolder(t *testing.T) {
 		{"/foo/.././bar", false},
 		{"/foo/.././.bar", true},
 		{"/foo/.././.bar/", true},
+		{"terragrunt-console-variables.tf", true},
+		{"foo/terragrunt-console-variables.tf", true},
 	}
 
 	for _, testCase := range testCases {
@@ -0,0 +1,86 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// JSONStreamRecord is a single line of command output wrapped with the context it was produced in.
+type JSONStreamRecord struct {
+	Timestamp string `json:"timestamp"`
+	Module    string `json:"module"`
+	Stream    string `json:"stream"`
+	Phase     string `json:"phase"`
+	Line      string `json:"line"`
+}
+
+// JSONStreamWriter returns a writer that wraps every line written to it in a JSONStreamRecord and writes the record to
+// the underlying writer as a single line of JSON. Incomplete lines are buffered until a newline is received or Flush is
+// called.
+func JSONStreamWriter(writer io.Writer, module, stream, phase string) *jsonStreamWriter {
+	return &jsonStreamWriter{writer: writer, module: module, stream: stream, phase: phase}
+}
+
+func (jw *jsonStreamWriter) Write(p []byte) (int, error) {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+
+	jw.buf.Write(p)
+
+	for {
+		idx := bytes.IndexByte(jw.buf.Bytes(), '\n')
+		if idx < 0 {
+			break
+		}
+		line := string(jw.buf.Next(idx + 1))
+		if err := jw.writeRecord(line[:len(line)-1]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(p), nil
+}
+
+// Flush writes out any buffered data that was not terminated by a newline.
+func (jw *jsonStreamWriter) Flush() error {
+	jw.mu.Lock()
+	defer jw.mu.Unlock()
+
+	if jw.buf.Len() == 0 {
+		return nil
+	}
+	line := jw.buf.String()
+	jw.buf.Reset()
+	return jw.writeRecord(line)
+}
+
+func (jw *jsonStreamWriter) writeRecord(line string) error {
+	record := JSONStreamRecord{
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+		Module:    jw.module,
+		Stream:    jw.stream,
+		Phase:     jw.phase,
+		Line:      string(bytes.TrimSuffix([]byte(line), []byte("\r"))),
+	}
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	// Write the whole record with a single call so that records from modules running concurrently never interleave.
+	_, err = jw.writer.Write(append(data, '\n'))
+	return err
+}
+
+type jsonStreamWriter struct {
+	writer io.Writer
+	module string
+	stream string
+	phase  string
+
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
@@ -0,0 +1,52 @@
+package util
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONStreamWriter(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		values   []string
+		expected []string
+	}{
+		{[]string{"a", "b"}, []string{"ab"}},
+		{[]string{"a\n", "b\n"}, []string{"a", "b"}},
+		{[]string{"ab\ncd\n\nef"}, []string{"ab", "cd", "", "ef"}},
+		{[]string{"ab\r\n"}, []string{"ab"}},
+		{[]string{""}, nil},
+	}
+
+	for _, testCase := range testCases {
+		var b bytes.Buffer
+		jw := JSONStreamWriter(&b, "/live/vpc", "stdout", "plan")
+		for _, input := range testCase.values {
+			written, err := jw.Write([]byte(input))
+			require.NoError(t, err)
+			assert.Equal(t, len(input), written)
+		}
+		require.NoError(t, jw.Flush())
+
+		var lines []string
+		for _, raw := range strings.Split(strings.TrimSuffix(b.String(), "\n"), "\n") {
+			if raw == "" {
+				continue
+			}
+			var record JSONStreamRecord
+			require.NoError(t, json.Unmarshal([]byte(raw), &record))
+			assert.Equal(t, "/live/vpc", record.Module)
+			assert.Equal(t, "stdout", record.Stream)
+			assert.Equal(t, "plan", record.Phase)
+			assert.NotEmpty(t, record.Timestamp)
+			lines = append(lines, record.Line)
+		}
+		assert.Equal(t, testCase.expected, lines)
+	}
+}
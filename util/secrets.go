@@ -0,0 +1,54 @@
+package util
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// RedactedValue is written to the logs in place of secret values.
+const RedactedValue = "[REDACTED]"
+
+// Secrets shorter than this are not redacted, as replacing very short strings, such as booleans or single characters,
+// would make the logs unreadable without hiding anything meaningful.
+const minSecretLength = 4
+
+// secrets holds the values, such as the contents of files decrypted with sops, that must not appear in the logs.
+var secrets = struct {
+	mutex  sync.RWMutex
+	values map[string]bool
+}{values: map[string]bool{}}
+
+// RegisterSecret marks the given value as secret, so that it is redacted by RedactSecrets for the rest of the run.
+func RegisterSecret(value string) {
+	value = strings.TrimSpace(value)
+	if len(value) < minSecretLength {
+		return
+	}
+
+	secrets.mutex.Lock()
+	defer secrets.mutex.Unlock()
+	secrets.values[value] = true
+}
+
+// RedactSecrets returns the given text with all the registered secret values replaced with RedactedValue.
+func RedactSecrets(text string) string {
+	secrets.mutex.RLock()
+	defer secrets.mutex.RUnlock()
+
+	if len(secrets.values) == 0 {
+		return text
+	}
+
+	// Replace the longest values first, so that a secret that contains another one is redacted as a whole.
+	values := make([]string, 0, len(secrets.values))
+	for value := range secrets.values {
+		values = append(values, value)
+	}
+	sort.Slice(values, func(i, j int) bool { return len(values[i]) > len(values[j]) })
+
+	for _, value := range values {
+		text = strings.ReplaceAll(text, value, RedactedValue)
+	}
+	return text
+}
@@ -0,0 +1,18 @@
+package util
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactSecrets(t *testing.T) {
+	t.Parallel()
+
+	RegisterSecret("s3cr3t-password")
+	RegisterSecret("s3cr3t")
+	RegisterSecret("yes")
+
+	assert.Equal(t, "password=[REDACTED] token=[REDACTED] enabled=yes", RedactSecrets("password=s3cr3t-password token=s3cr3t enabled=yes"))
+	assert.Equal(t, "nothing to hide", RedactSecrets("nothing to hide"))
+}